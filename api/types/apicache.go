@@ -97,7 +97,11 @@ var _ BlockSummarySaver = (*APICache)(nil)
 func (apic *APICache) Capacity() uint32 { return apic.capacity }
 
 // UtilizationBlocks returns the number of blocks stored in the cache
-func (apic *APICache) UtilizationBlocks() int64 { return int64(len(apic.blockCache)) }
+func (apic *APICache) UtilizationBlocks() int64 {
+	apic.mtx.RLock()
+	defer apic.mtx.RUnlock()
+	return int64(len(apic.blockCache))
+}
 
 // Utilization returns the percent utilization of the cache
 func (apic *APICache) Utilization() float64 {
@@ -107,10 +111,18 @@ func (apic *APICache) Utilization() float64 {
 }
 
 // Hits returns the hit count of the APICache
-func (apic *APICache) Hits() uint64 { return apic.hits }
+func (apic *APICache) Hits() uint64 {
+	apic.mtx.RLock()
+	defer apic.mtx.RUnlock()
+	return apic.hits
+}
 
 // Misses returns the miss count of the APICache
-func (apic *APICache) Misses() uint64 { return apic.misses }
+func (apic *APICache) Misses() uint64 {
+	apic.mtx.RLock()
+	defer apic.mtx.RUnlock()
+	return apic.misses
+}
 
 // StoreBlockSummary caches the input BlockDataBasic, if the priority queue
 // indicates that the block should be added.
@@ -86,8 +86,19 @@ type BlockTransactions struct {
 // Tx models TxShort with the number of confirmations and block info Block
 type Tx struct {
 	TxShort
-	Confirmations int64    `json:"confirmations"`
-	Block         *BlockID `json:"block,omitempty"`
+	Confirmations int64      `json:"confirmations"`
+	Block         *BlockID   `json:"block,omitempty"`
+	FiatValue     *FiatValue `json:"fiat_value,omitempty"`
+}
+
+// FiatValue is the value of a transaction in a fiat currency, at the daily
+// price of the coin on the day of its block. The fee is omitted when it is not
+// known.
+type FiatValue struct {
+	Currency string   `json:"currency"`
+	Price    float64  `json:"price"`
+	Value    float64  `json:"value"`
+	Fee      *float64 `json:"fee,omitempty"`
 }
 
 // Vin is an alias for dcrd's rpc/jsonrpc/types/v4.Vin type.
@@ -384,10 +395,14 @@ type OutPoint struct {
 	Tree  int8   `json:"tree"`
 }
 
-// Address models the address string with the transactions as AddressTxShort
+// Address models the address string with the transactions as AddressTxShort.
+// RetainedFromHeight is set for the chains whose address rows of the blocks
+// below that height are pruned, and the transactions of those blocks missing.
 type Address struct {
-	Address      string            `json:"address"`
-	Transactions []*AddressTxShort `json:"address_transactions"`
+	Address            string            `json:"address"`
+	Transactions       []*AddressTxShort `json:"address_transactions"`
+	NextCursor         string            `json:"next_cursor,omitempty"`
+	RetainedFromHeight int64             `json:"retained_from_height,omitempty"`
 }
 
 // ScriptSig models the signature script used to redeem a transaction output.
@@ -493,11 +508,12 @@ type AddressTxRaw struct {
 // AddressTxShort is a subset of AddressTxRaw with just the basic tx details
 // pertaining the particular address
 type AddressTxShort struct {
-	TxID          string  `json:"txid"`
-	Size          int32   `json:"size"`
-	Time          TimeAPI `json:"time"`
-	Value         float64 `json:"value"`
-	Confirmations int64   `json:"confirmations"`
+	TxID          string     `json:"txid"`
+	Size          int32      `json:"size"`
+	Time          TimeAPI    `json:"time"`
+	Value         float64    `json:"value"`
+	Confirmations int64      `json:"confirmations"`
+	FiatValue     *FiatValue `json:"fiat_value,omitempty"`
 }
 
 // AddressTotals represents the number and value of spent and unspent outputs
@@ -619,6 +635,47 @@ func (s *Status) Happy() Happy {
 	return h
 }
 
+// Health states of ChainHealth and Readiness.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthFailing  = "failing"
+)
+
+// ChainHealth is the health of a chain, for the JSON-formatted response at
+// /status/{chaintype}. Failures make the chain failing, and warnings degraded.
+// Ages are in seconds, and -1 if unknown.
+type ChainHealth struct {
+	Chain           string   `json:"chain"`
+	Status          string   `json:"status"`
+	NodeConnected   bool     `json:"node_connected"`
+	NodeConnections int64    `json:"node_connections"`
+	NodeHeight      int64    `json:"node_height"`
+	DBHeight        int64    `json:"db_height"`
+	HeightLag       int64    `json:"height_lag"`
+	BlockAge        int64    `json:"block_age"`
+	TargetSpacing   int64    `json:"target_spacing"`
+	MempoolAge      int64    `json:"mempool_age"`
+	ExternalAPIUsed bool     `json:"external_api_used"`
+	ExchangeAge     int64    `json:"exchange_age"`
+	Failures        []string `json:"failures,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+	CheckTime       int64    `json:"check_time"`
+}
+
+// Healthy indicates whether the chain is ok or degraded.
+func (h *ChainHealth) Healthy() bool {
+	return h.Status != HealthFailing
+}
+
+// Readiness is the health of all the enabled chains, for the JSON-formatted
+// response at /status/ready. Its status is the worst of the chains.
+type Readiness struct {
+	Ready  bool           `json:"ready"`
+	Status string         `json:"status"`
+	Chains []*ChainHealth `json:"chains"`
+}
+
 // Height is the last known node height.
 func (s *Status) Height() uint32 {
 	s.RLock()
@@ -0,0 +1,114 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package types
+
+// The types in this file are used by the chain-scoped API of the BTC, LTC and
+// XMR chains at /api/chain/{chaintype}. Amounts are in the smallest unit of
+// the chain, i.e. satoshis for BTC and LTC, and piconero for XMR.
+
+// ChainBlockSummary is the summary of a block of a BTC, LTC or XMR chain.
+type ChainBlockSummary struct {
+	Height        int64   `json:"height"`
+	Hash          string  `json:"hash"`
+	Version       int32   `json:"version"`
+	Size          int64   `json:"size"`
+	Time          int64   `json:"time"`
+	NumTx         int     `json:"num_tx"`
+	Difficulty    float64 `json:"difficulty"`
+	Nonce         uint64  `json:"nonce"`
+	Confirmations int64   `json:"confirmations"`
+	PreviousHash  string  `json:"previousblockhash"`
+	NextHash      string  `json:"nextblockhash,omitempty"`
+}
+
+// ChainBlockSubsidy is the reward of the coinbase (or miner) transaction of a
+// block, split into the newly generated coins and the fees of the block's
+// transactions.
+type ChainBlockSubsidy struct {
+	Height  int64  `json:"height"`
+	Hash    string `json:"hash"`
+	Subsidy int64  `json:"subsidy"`
+	Fees    int64  `json:"fees"`
+	Reward  int64  `json:"reward"`
+}
+
+// ChainBlockTransactions is the list of the transaction hashes of a block, in
+// block order, starting with the coinbase (or miner) transaction.
+type ChainBlockTransactions struct {
+	Height int64    `json:"height"`
+	Hash   string   `json:"hash"`
+	Tx     []string `json:"tx"`
+}
+
+// ChainTxIn is a transaction input. The previous outpoint is not set for
+// coinbase inputs, and XMR inputs spend one of the ring members identified by
+// the key image instead.
+type ChainTxIn struct {
+	Index    uint32 `json:"n"`
+	Coinbase bool   `json:"coinbase,omitempty"`
+	PrevTxID string `json:"prev_txid,omitempty"`
+	PrevVout uint32 `json:"prev_vout"`
+	Amount   int64  `json:"amount"`
+	Address  string `json:"address,omitempty"`
+	Sequence uint32 `json:"sequence,omitempty"`
+	KeyImage string `json:"key_image,omitempty"`
+	RingSize int    `json:"ring_size,omitempty"`
+}
+
+// ChainTxOut is a transaction output. The amounts of XMR RingCT outputs are
+// hidden and reported as zero, and the output is identified by its one-time
+// public key instead of a pkScript.
+type ChainTxOut struct {
+	Index     uint32   `json:"n"`
+	Amount    int64    `json:"amount"`
+	Type      string   `json:"type,omitempty"`
+	PkScript  string   `json:"pkscript,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	Key       string   `json:"key,omitempty"`
+}
+
+// ChainTx is a transaction of a BTC, LTC or XMR chain. The block fields are
+// omitted for mempool transactions. The fee is omitted when it is not known,
+// e.g. when a previous output of a BTC or LTC transaction is not stored.
+type ChainTx struct {
+	TxID          string       `json:"txid"`
+	Version       int32        `json:"version"`
+	Size          int64        `json:"size"`
+	LockTime      uint64       `json:"locktime"`
+	Coinbase      bool         `json:"coinbase"`
+	Fee           *int64       `json:"fee,omitempty"`
+	BlockHeight   int64        `json:"block_height,omitempty"`
+	BlockHash     string       `json:"block_hash,omitempty"`
+	Time          int64        `json:"time,omitempty"`
+	Confirmations int64        `json:"confirmations"`
+	Vin           []ChainTxIn  `json:"vin"`
+	Vout          []ChainTxOut `json:"vout"`
+	FiatValue     *FiatValue   `json:"fiat_value,omitempty"`
+}
+
+// ChainAddressTotals represents the number and value of spent and unspent
+// outputs for a BTC or LTC address.
+type ChainAddressTotals struct {
+	Address       string `json:"address"`
+	NumSpent      int64  `json:"num_stxos"`
+	NumUnspent    int64  `json:"num_utxos"`
+	TotalReceived int64  `json:"amount_received"`
+	TotalSpent    int64  `json:"amount_spent"`
+	Balance       int64  `json:"balance"`
+}
+
+// ChainMempool is the content of the mempool of a chain's node.
+type ChainMempool struct {
+	Count int      `json:"count"`
+	Txids []string `json:"txids"`
+}
+
+// ChainCoinSupply models the coin supply of a chain at its best block. The
+// ultimate supply is omitted for chains with a perpetual tail emission.
+type ChainCoinSupply struct {
+	Height   int64  `json:"block_height"`
+	Hash     string `json:"block_hash"`
+	Mined    uint64 `json:"supply_mined"`
+	Ultimate uint64 `json:"supply_ultimate,omitempty"`
+}
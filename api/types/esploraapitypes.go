@@ -0,0 +1,110 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package types
+
+// EsploraTxStatus is the confirmation status of a transaction in the Esplora
+// API. The block fields are omitted for unconfirmed transactions.
+type EsploraTxStatus struct {
+	Confirmed   bool   `json:"confirmed"`
+	BlockHeight int64  `json:"block_height,omitempty"`
+	BlockHash   string `json:"block_hash,omitempty"`
+	BlockTime   int64  `json:"block_time,omitempty"`
+}
+
+// EsploraVout is a transaction output, or the previous output of an input, in
+// the Esplora API. Values are in satoshis.
+type EsploraVout struct {
+	ScriptPubKey        string `json:"scriptpubkey"`
+	ScriptPubKeyAsm     string `json:"scriptpubkey_asm"`
+	ScriptPubKeyType    string `json:"scriptpubkey_type"`
+	ScriptPubKeyAddress string `json:"scriptpubkey_address,omitempty"`
+	Value               int64  `json:"value"`
+}
+
+// EsploraVin is a transaction input in the Esplora API. Prevout is nil for
+// coinbase inputs.
+type EsploraVin struct {
+	Txid         string       `json:"txid"`
+	Vout         uint32       `json:"vout"`
+	Prevout      *EsploraVout `json:"prevout"`
+	ScriptSig    string       `json:"scriptsig"`
+	ScriptSigAsm string       `json:"scriptsig_asm"`
+	Witness      []string     `json:"witness,omitempty"`
+	IsCoinbase   bool         `json:"is_coinbase"`
+	Sequence     uint32       `json:"sequence"`
+}
+
+// EsploraTx is a transaction in the Esplora API.
+type EsploraTx struct {
+	Txid     string          `json:"txid"`
+	Version  int32           `json:"version"`
+	Locktime uint32          `json:"locktime"`
+	Vin      []EsploraVin    `json:"vin"`
+	Vout     []EsploraVout   `json:"vout"`
+	Size     int32           `json:"size"`
+	Weight   int32           `json:"weight"`
+	Fee      int64           `json:"fee"`
+	Status   EsploraTxStatus `json:"status"`
+}
+
+// EsploraOutspend is the spending status of a transaction output in the
+// Esplora API. The spending transaction fields are omitted for unspent
+// outputs.
+type EsploraOutspend struct {
+	Spent  bool             `json:"spent"`
+	Txid   string           `json:"txid,omitempty"`
+	Vin    *uint32          `json:"vin,omitempty"`
+	Status *EsploraTxStatus `json:"status,omitempty"`
+}
+
+// EsploraAddressStats are the funded and spent output statistics of an
+// address, either confirmed or in mempool.
+type EsploraAddressStats struct {
+	FundedTxoCount int64 `json:"funded_txo_count"`
+	FundedTxoSum   int64 `json:"funded_txo_sum"`
+	SpentTxoCount  int64 `json:"spent_txo_count"`
+	SpentTxoSum    int64 `json:"spent_txo_sum"`
+	TxCount        int64 `json:"tx_count"`
+}
+
+// EsploraAddress is the summary of an address or scripthash in the Esplora
+// API. Address is omitted for scripthash requests and ScriptHash for address
+// requests.
+type EsploraAddress struct {
+	Address      string              `json:"address,omitempty"`
+	ScriptHash   string              `json:"scripthash,omitempty"`
+	ChainStats   EsploraAddressStats `json:"chain_stats"`
+	MempoolStats EsploraAddressStats `json:"mempool_stats"`
+}
+
+// EsploraUTXO is an unspent output of an address in the Esplora API.
+type EsploraUTXO struct {
+	Txid   string          `json:"txid"`
+	Vout   uint32          `json:"vout"`
+	Value  int64           `json:"value"`
+	Status EsploraTxStatus `json:"status"`
+}
+
+// EsploraBlock is a block in the Esplora API.
+type EsploraBlock struct {
+	ID                string  `json:"id"`
+	Height            int64   `json:"height"`
+	Version           int32   `json:"version"`
+	Timestamp         int64   `json:"timestamp"`
+	TxCount           int     `json:"tx_count"`
+	Size              int32   `json:"size"`
+	Weight            int32   `json:"weight"`
+	MerkleRoot        string  `json:"merkle_root"`
+	PreviousBlockHash string  `json:"previousblockhash,omitempty"`
+	Nonce             uint32  `json:"nonce"`
+	Bits              uint32  `json:"bits"`
+	Difficulty        float64 `json:"difficulty"`
+}
+
+// EsploraBlockStatus is the main chain status of a block in the Esplora API.
+type EsploraBlockStatus struct {
+	InBestChain bool   `json:"in_best_chain"`
+	Height      int64  `json:"height,omitempty"`
+	NextBest    string `json:"next_best,omitempty"`
+}
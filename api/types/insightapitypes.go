@@ -214,3 +214,18 @@ type InsightBlockAddrTxSummary struct {
 	PagesTotal int64       `json:"pagesTotal"`
 	Txs        []InsightTx `json:"txs"`
 }
+
+// InsightStatusInfo models the node information returned by the status
+// endpoint of the Insight API when no query is specified.
+type InsightStatusInfo struct {
+	Version         int32   `json:"version"`
+	Protocolversion int32   `json:"protocolversion"`
+	Blocks          int64   `json:"blocks"`
+	NodeTimeoffset  int64   `json:"timeoffset"`
+	NodeConnections int32   `json:"connections"`
+	Proxy           string  `json:"proxy"`
+	Difficulty      float64 `json:"difficulty"`
+	Testnet         bool    `json:"testnet"`
+	Relayfee        float64 `json:"relayfee"`
+	Errors          string  `json:"errors"`
+}
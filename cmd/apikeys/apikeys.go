@@ -0,0 +1,199 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// apikeys issues, revokes and lists the API keys of a dcrdata database, and
+// shows their usage. Requests made with a key are limited by the rate, burst
+// and daily quota of the key instead of the IP based limits of the APIs.
+// Changes take effect in a running dcrdata within a minute.
+//
+// Usage:
+//
+//	apikeys [db flags] issue -name <name> [-rate <req/s>] [-burst <n>] [-quota <n>]
+//	apikeys [db flags] limits -id <id> [-rate <req/s>] [-burst <n>] [-quota <n>]
+//	apikeys [db flags] revoke -id <id>
+//	apikeys [db flags] list
+//	apikeys [db flags] usage -id <id> [-days <n>]
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+var (
+	dbHostPort = flag.String("dbhost", "127.0.0.1:5432", "DB host:port, or an absolute path to a UNIX socket")
+	dbUser     = flag.String("dbuser", "dcrdata", "DB user")
+	dbPass     = flag.String("dbpass", "", "DB pass")
+	dbName     = flag.String("dbname", "dcrdata", "DB name")
+)
+
+// command is a subcommand with its own flags.
+type command struct {
+	usage string
+	run   func(ctx context.Context, db *sql.DB, args []string) error
+}
+
+var commands = map[string]command{
+	"issue":  {"Issue a new API key", issueKey},
+	"limits": {"Change the limits of an API key", setKeyLimits},
+	"revoke": {"Revoke an API key", revokeKey},
+	"list":   {"List the API keys", listKeys},
+	"usage":  {"Show the daily usage of an API key by endpoint", showUsage},
+}
+
+// limitFlags adds the flags of the limits of a key to a FlagSet.
+func limitFlags(fs *flag.FlagSet) (rate *float64, burst *int, quota *int64) {
+	rate = fs.Float64("rate", 10, "Sustained requests per second, 0 for no rate limit")
+	burst = fs.Int("burst", 20, "Requests allowed at once above the rate")
+	quota = fs.Int64("quota", 0, "Requests per UTC day, 0 for no quota")
+	return
+}
+
+func issueKey(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("issue", flag.ExitOnError)
+	name := fs.String("name", "", "Name of the owner of the key")
+	rate, burst, quota := limitFlags(fs)
+	_ = fs.Parse(args)
+	if *name == "" {
+		return errors.New("a name is required")
+	}
+
+	key, apiKey, err := dcrpg.InsertAPIKey(ctx, db, *name, *rate, *burst, *quota)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Issued API key %d for %q. The key cannot be shown again:\n%s\n",
+		apiKey.ID, apiKey.Name, key)
+	return nil
+}
+
+func setKeyLimits(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("limits", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the key")
+	rate, burst, quota := limitFlags(fs)
+	_ = fs.Parse(args)
+
+	err := dcrpg.UpdateAPIKeyLimits(ctx, db, *id, *rate, *burst, *quota)
+	if errors.Is(err, dbtypes.ErrNoResult) {
+		return fmt.Errorf("no API key with id %d", *id)
+	}
+	return err
+}
+
+func revokeKey(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the key")
+	_ = fs.Parse(args)
+
+	err := dcrpg.RevokeAPIKey(ctx, db, *id)
+	if errors.Is(err, dbtypes.ErrNoResult) {
+		return fmt.Errorf("no API key with id %d", *id)
+	}
+	return err
+}
+
+func listKeys(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	keys, err := dcrpg.RetrieveAPIKeys(ctx, db)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tKEY\tNAME\tRATE\tBURST\tQUOTA\tCREATED\tREVOKED")
+	for _, k := range keys {
+		revoked := "-"
+		if k.Revoked() {
+			revoked = k.RevokedAt.UTC().Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%d\t%s...\t%s\t%g\t%d\t%d\t%s\t%s\n", k.ID, k.Display, k.Name,
+			k.RateLimit, k.Burst, k.DailyQuota, k.CreatedAt.UTC().Format(time.DateTime), revoked)
+	}
+	return tw.Flush()
+}
+
+func showUsage(ctx context.Context, db *sql.DB, args []string) error {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the key")
+	days := fs.Int("days", 7, "Number of UTC days to show, including today")
+	_ = fs.Parse(args)
+	if *days < 1 {
+		return errors.New("days must be at least 1")
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, 1-*days)
+	usage, err := dcrpg.RetrieveAPIKeyUsage(ctx, db, *id, since)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tENDPOINT\tREQUESTS")
+	for _, u := range usage {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", u.Day.Format(time.DateOnly), u.Endpoint, u.Count)
+	}
+	return tw.Flush()
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [db flags] <command> [command flags]\n\nCommands:\n", os.Args[0])
+	for _, name := range []string{"issue", "limits", "revoke", "list", "usage"} {
+		fmt.Fprintf(os.Stderr, "  %-8s%s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nDB flags:")
+	flag.PrintDefaults()
+}
+
+func mainCore(ctx context.Context) error {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command %q", flag.Arg(0))
+	}
+
+	dbHost, dbPort := *dbHostPort, ""
+	if !strings.HasPrefix(dbHost, "/") {
+		var err error
+		dbHost, dbPort, err = net.SplitHostPort(*dbHostPort)
+		if err != nil {
+			return fmt.Errorf("SplitHostPort failed: %w", err)
+		}
+	}
+	db, err := dcrpg.Connect(dbHost, dbPort, *dbUser, *dbPass, *dbName)
+	if err != nil {
+		return fmt.Errorf("unable to connect to PostgreSQL: %w", err)
+	}
+	defer db.Close()
+
+	if err = dcrpg.CheckCreateAPIKeyTables(db); err != nil {
+		return err
+	}
+	return cmd.run(ctx, db, flag.Args()[1:])
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := mainCore(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
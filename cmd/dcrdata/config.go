@@ -85,9 +85,32 @@ var (
 
 	defaultCacheControlMaxAge  = 86400
 	defaultInsightReqRateLimit = 20.0
+	defaultEsploraReqRateLimit = 20.0
 	defaultMaxCSVAddrs         = 25
+	defaultMaxExportRows       = 100000
+	defaultConcurrentExports   = 4
 	defaultServerHeader        = "dcrdata"
 
+	defaultHealthMaxLag         = 3
+	defaultHealthBlockAge       = 12.0
+	defaultHealthMempoolAge     = 20 * time.Minute
+	defaultHealthExchangeAge    = 30 * time.Minute
+	defaultHealthFallbackWindow = time.Hour
+
+	defaultMarketDepthInterval = 30 * time.Minute
+
+	defaultExchangeMaxQuoteAge  = 30 * time.Minute
+	defaultExchangeMADThreshold = 3.0
+	defaultExchangeMinExchanges = 1
+
+	defaultFiatHistoryCurrencies = "USD"
+
+	defaultMultichainBulkBatch       = 50
+	defaultRetainBlocks        int64 = 25
+	defaultRetentionPruneBatch       = 5000
+
+	defaultUtxoSnapshotInterval int64 = 144
+
 	defaultMempoolMinInterval = 2
 	defaultMempoolMaxInterval = 120
 	defaultMPTriggerTickets   = 1
@@ -112,6 +135,8 @@ var (
 	defaultExchangeIndex     = "USD"
 	defaultDisabledExchanges = "bittrex,dragonex,poloniex"
 	defaultRateCertFile      = filepath.Join(defaultHomeDir, "rpc.cert")
+	defaultElectrumCertFile  = filepath.Join(defaultHomeDir, "electrum.cert")
+	defaultElectrumKeyFile   = filepath.Join(defaultHomeDir, "electrum.key")
 
 	defaultMainnetLink  = "https://bisonexplorer.com/"
 	defaultTestnetLink  = "https://testnet.bisonexplorer.com/"
@@ -150,9 +175,30 @@ type config struct {
 	AllowedHosts        []string `long:"allowedhost" description:"Permitted Host values in the request header. Unrecognized hosts are cleared."`
 	CacheControlMaxAge  int      `long:"cachecontrol-maxage" description:"Set CacheControl in the HTTP response header to a value in seconds for clients to cache the response. This applies only to FileServer routes." env:"DCRDATA_MAX_CACHE_AGE"`
 	InsightReqRateLimit float64  `long:"insight-limit-rps" description:"Requests/second per client IP for the Insight API's rate limiter." env:"DCRDATA_INSIGHT_RATE_LIMIT"`
+	EsploraReqRateLimit float64  `long:"esplora-limit-rps" description:"Requests/second per client IP for the BTC and LTC Esplora APIs' rate limiter." env:"DCRDATA_ESPLORA_RATE_LIMIT"`
 	MaxCSVAddrs         int      `long:"max-api-addrs" description:"Maximum allowed comma-separated addresses for endpoints that accept multiple addresses." env:"DCRDATA_MAX_CSV_ADDRS"`
+	MaxExportRows       int      `long:"max-export-rows" description:"Maximum number of rows of the CSV and NDJSON data exports at /download/export." env:"DCRDATA_MAX_EXPORT_ROWS"`
+	ConcurrentExports   int      `long:"max-concurrent-exports" description:"Maximum number of data exports at /download/export served at once. Further exports are rejected with 429 Too Many Requests." env:"DCRDATA_MAX_CONCURRENT_EXPORTS"`
 	CompressAPI         bool     `long:"compress-api" description:"Use compression for a number of endpoints with commonly large responses." env:"DCRDATA_COMPRESS_API"`
 	ServerHeader        string   `long:"server-http-header" description:"Set the HTTP response header Server key value. Valid values are \"off\", \"version\", or a custom string." env:"DCRDATA_SERVER_HEADER"`
+	EnableWebhooks      bool     `long:"webhooks" description:"Enable the webhook subscriptions of API keys, and the delivery of their events." env:"DCRDATA_ENABLE_WEBHOOKS"`
+	WebhookAllowedNets  []string `long:"webhook-allownet" description:"Network in CIDR notation of loopback, private or link-local webhook callback addresses that are permitted, e.g. 127.0.0.1/32 for a local receiver. May be repeated."`
+	EnableMetrics       bool     `long:"metrics" description:"Serve the Prometheus metrics of the node and DB heights, caches, pubsub, exchanges, and query and request latencies at /metrics." env:"DCRDATA_ENABLE_METRICS"`
+
+	// Electrum protocol servers
+	BTCElectrumListen    string `long:"btc-electrum-listen" description:"Listen address for the BTC Electrum protocol TCP server (e.g. :50001). The server is disabled if no listen address is set." env:"DCRDATA_BTC_ELECTRUM_LISTEN"`
+	BTCElectrumTLSListen string `long:"btc-electrum-tlslisten" description:"Listen address for the BTC Electrum protocol TLS server (e.g. :50002)." env:"DCRDATA_BTC_ELECTRUM_TLS_LISTEN"`
+	LTCElectrumListen    string `long:"ltc-electrum-listen" description:"Listen address for the LTC Electrum protocol TCP server (e.g. :50011). The server is disabled if no listen address is set." env:"DCRDATA_LTC_ELECTRUM_LISTEN"`
+	LTCElectrumTLSListen string `long:"ltc-electrum-tlslisten" description:"Listen address for the LTC Electrum protocol TLS server (e.g. :50012)." env:"DCRDATA_LTC_ELECTRUM_TLS_LISTEN"`
+	ElectrumTLSCert      string `long:"electrum-tlscert" description:"File containing the TLS certificate of the Electrum protocol servers." env:"DCRDATA_ELECTRUM_TLS_CERT"`
+	ElectrumTLSKey       string `long:"electrum-tlskey" description:"File containing the TLS key of the Electrum protocol servers." env:"DCRDATA_ELECTRUM_TLS_KEY"`
+
+	// Health checks at /api/status/{chain} and /api/status/ready
+	HealthMaxLag         int           `long:"health-max-lag" description:"Number of blocks the DB may be behind the node of a chain before the chain is failing." env:"DCRDATA_HEALTH_MAX_LAG"`
+	HealthBlockAge       float64       `long:"health-block-age" description:"Maximum age of the best block of a chain before the chain is failing, in target block spacings of the chain." env:"DCRDATA_HEALTH_BLOCK_AGE"`
+	HealthMempoolAge     time.Duration `long:"health-mempool-age" description:"Maximum time since the last update of the mempool monitor of a chain before the chain is failing." env:"DCRDATA_HEALTH_MEMPOOL_AGE"`
+	HealthExchangeAge    time.Duration `long:"health-exchange-age" description:"Maximum age of the exchange rates of a chain before the chain is degraded." env:"DCRDATA_HEALTH_EXCHANGE_AGE"`
+	HealthFallbackWindow time.Duration `long:"health-fallback-window" description:"How long a chain is degraded after its data was served from external APIs in place of the DB." env:"DCRDATA_HEALTH_FALLBACK_WINDOW"`
 
 	// Mempool
 	MempoolMinInterval int `long:"mp-min-interval" description:"The minimum time in seconds between mempool reports, regardless of number of new tickets seen." env:"DCRDATA_MEMPOOL_MIN_INTERVAL"`
@@ -216,6 +262,16 @@ type config struct {
 	RateMaster        string `long:"ratemaster" description:"The address of a DCRRates instance. Exchange monitoring will get all data from a DCRRates subscription." env:"DCRDATA_RATE_MASTER"`
 	RateCertificate   string `long:"ratecert" description:"File containing DCRRates TLS certificate file." env:"DCRDATA_RATE_MASTER"`
 	BinanceAPI        string `long:"binance-api" description:"Link to Binance data. Default is Binance API URL" env:"DCRRATES_BINANCEAPI_INDEX"`
+	// Market history of the exchange monitor
+	NoMarketStore       bool          `long:"no-market-store" description:"Do not store the exchange candlesticks and order book snapshots in the DB. The candlestick charts are then limited to what the exchanges return." env:"DCRDATA_NO_MARKET_STORE"`
+	MarketDepthInterval time.Duration `long:"market-depth-interval" description:"Minimum time between the stored order book snapshots of a market. A negative interval disables the snapshots." env:"DCRDATA_MARKET_DEPTH_INTERVAL"`
+	// Price aggregation of the exchange monitor
+	ExchangeMaxQuoteAge  time.Duration `long:"exchange-max-quote-age" description:"Exclude the prices of the exchanges not updated for this long from the market prices. 0 disables the exclusion." env:"DCRDATA_EXCHANGE_MAX_QUOTE_AGE"`
+	ExchangeMADThreshold float64       `long:"exchange-mad-threshold" description:"Exclude the prices of the exchanges deviating from the median price by more than this many scaled median absolute deviations. 0 disables the outlier rejection." env:"DCRDATA_EXCHANGE_MAD_THRESHOLD"`
+	ExchangeMinExchanges int           `long:"exchange-min-exchanges" description:"Minimum number of exchanges in a market price. The price of a market without this quorum is not updated." env:"DCRDATA_EXCHANGE_MIN_EXCHANGES"`
+	// Historical fiat valuation
+	NoFiatHistory         bool   `long:"no-fiat-history" description:"Do not backfill the daily fiat prices of the coins. Transactions and addresses are then not valued at block time." env:"DCRDATA_NO_FIAT_HISTORY"`
+	FiatHistoryCurrencies string `long:"fiat-history-currencies" description:"The fiat currencies of the backfilled daily prices. Use a comma to separate multiple 3-letter currency codes" env:"DCRDATA_FIAT_HISTORY_CURRENCIES"`
 	// Links
 	MainnetLink    string `long:"mainnet-link" description:"When dcrdata is on testnet, this address will be used to direct a user to a dcrdata on mainnet when appropriate." env:"DCRDATA_MAINNET_LINK"`
 	TestnetLink    string `long:"testnet-link" description:"When dcrdata is on mainnet, this address will be used to direct a user to a dcrdata on testnet when appropriate." env:"DCRDATA_TESTNET_LINK"`
@@ -224,6 +280,19 @@ type config struct {
 	SyncChainDB    bool   `long:"syncchaindb" description:"Flag for syncing mutilchain to DB" env:"SYNC_CHAIN_DB"`
 	XmrSyncDB      bool   `long:"xmrsyncdb" description:"Flag for syncing Monero to DB" env:"XMR_SYNC_DB"`
 	OkLinkKey      string `long:"oklinkkey" description:"Setting up oklink api key" env:"OKLINK_KEY"`
+	// Multichain bulk sync and retention
+	MultichainBulkBatch    int           `long:"multichain-bulk-batch" description:"Number of blocks of the batches stored at once by the whole BTC and LTC chain sync." env:"DCRDATA_MULTICHAIN_BULK_BATCH"`
+	BTCRetainBlocks        int64         `long:"btc-retain-blocks" description:"Number of blocks before the tip whose BTC transaction inputs and outputs are kept when the whole chain is not synced. With btc-retain-age 0, 0 keeps all of them." env:"DCRDATA_BTC_RETAIN_BLOCKS"`
+	BTCRetainAge           time.Duration `long:"btc-retain-age" description:"Age of the blocks whose BTC transaction inputs and outputs are kept when the whole chain is not synced, in addition to btc-retain-blocks." env:"DCRDATA_BTC_RETAIN_AGE"`
+	BTCRetainAddressBlocks int64         `long:"btc-retain-address-blocks" description:"Number of blocks before the tip whose BTC address rows are kept when the whole chain is not synced. With btc-retain-address-age 0, 0 keeps all the address rows." env:"DCRDATA_BTC_RETAIN_ADDRESS_BLOCKS"`
+	BTCRetainAddressAge    time.Duration `long:"btc-retain-address-age" description:"Age of the blocks whose BTC address rows are kept when the whole chain is not synced, in addition to btc-retain-address-blocks." env:"DCRDATA_BTC_RETAIN_ADDRESS_AGE"`
+	LTCRetainBlocks        int64         `long:"ltc-retain-blocks" description:"Number of blocks before the tip whose LTC transaction inputs and outputs are kept when the whole chain is not synced. With ltc-retain-age 0, 0 keeps all of them." env:"DCRDATA_LTC_RETAIN_BLOCKS"`
+	LTCRetainAge           time.Duration `long:"ltc-retain-age" description:"Age of the blocks whose LTC transaction inputs and outputs are kept when the whole chain is not synced, in addition to ltc-retain-blocks." env:"DCRDATA_LTC_RETAIN_AGE"`
+	LTCRetainAddressBlocks int64         `long:"ltc-retain-address-blocks" description:"Number of blocks before the tip whose LTC address rows are kept when the whole chain is not synced. With ltc-retain-address-age 0, 0 keeps all the address rows." env:"DCRDATA_LTC_RETAIN_ADDRESS_BLOCKS"`
+	LTCRetainAddressAge    time.Duration `long:"ltc-retain-address-age" description:"Age of the blocks whose LTC address rows are kept when the whole chain is not synced, in addition to ltc-retain-address-blocks." env:"DCRDATA_LTC_RETAIN_ADDRESS_AGE"`
+	RetentionPruneBatch    int           `long:"retention-prune-batch" description:"Maximum number of rows deleted at once when pruning the BTC and LTC tables to their retention windows." env:"DCRDATA_RETENTION_PRUNE_BATCH"`
+	// UTXO set statistics
+	UtxoSnapshotInterval int64 `long:"utxo-snapshot-interval" description:"Number of blocks between the UTXO set snapshots of the DCR, BTC and LTC charts." env:"DCRDATA_UTXO_SNAPSHOT_INTERVAL"`
 }
 
 var (
@@ -246,7 +315,10 @@ var (
 		IndentJSON:          defaultIndentJSON,
 		CacheControlMaxAge:  defaultCacheControlMaxAge,
 		InsightReqRateLimit: defaultInsightReqRateLimit,
+		EsploraReqRateLimit: defaultEsploraReqRateLimit,
 		MaxCSVAddrs:         defaultMaxCSVAddrs,
+		MaxExportRows:       defaultMaxExportRows,
+		ConcurrentExports:   defaultConcurrentExports,
 		ServerHeader:        defaultServerHeader,
 		DcrdCert:            defaultDaemonRPCCertFile,
 		LtcdCert:            defaultLTCDaemonRPCCertFile,
@@ -266,11 +338,32 @@ var (
 		ExchangeCurrency:    defaultExchangeIndex,
 		DisabledExchanges:   defaultDisabledExchanges,
 		RateCertificate:     defaultRateCertFile,
+		ElectrumTLSCert:     defaultElectrumCertFile,
+		ElectrumTLSKey:      defaultElectrumKeyFile,
 		MainnetLink:         defaultMainnetLink,
 		TestnetLink:         defaultTestnetLink,
 		OnionAddress:        defaultOnionAddress,
 		BinanceAPI:          defaultBinanceAPI,
 		CoincapActive:       defaultCoinCaps,
+
+		HealthMaxLag:         defaultHealthMaxLag,
+		HealthBlockAge:       defaultHealthBlockAge,
+		HealthMempoolAge:     defaultHealthMempoolAge,
+		HealthExchangeAge:    defaultHealthExchangeAge,
+		HealthFallbackWindow: defaultHealthFallbackWindow,
+		MarketDepthInterval:  defaultMarketDepthInterval,
+		ExchangeMaxQuoteAge:  defaultExchangeMaxQuoteAge,
+		ExchangeMADThreshold: defaultExchangeMADThreshold,
+		ExchangeMinExchanges: defaultExchangeMinExchanges,
+
+		FiatHistoryCurrencies: defaultFiatHistoryCurrencies,
+
+		MultichainBulkBatch: defaultMultichainBulkBatch,
+		BTCRetainBlocks:     defaultRetainBlocks,
+		LTCRetainBlocks:     defaultRetainBlocks,
+		RetentionPruneBatch: defaultRetentionPruneBatch,
+
+		UtxoSnapshotInterval: defaultUtxoSnapshotInterval,
 	}
 )
 
@@ -670,6 +763,37 @@ func loadConfig() (*config, error) {
 		return nil, fmt.Errorf("purge-n-blocks must be non-negative")
 	}
 
+	if cfg.MaxExportRows <= 0 {
+		return nil, fmt.Errorf("max-export-rows must be positive")
+	}
+	if cfg.ConcurrentExports <= 0 {
+		return nil, fmt.Errorf("max-concurrent-exports must be positive")
+	}
+
+	if cfg.MultichainBulkBatch <= 0 {
+		return nil, fmt.Errorf("multichain-bulk-batch must be positive")
+	}
+	if cfg.RetentionPruneBatch <= 0 {
+		return nil, fmt.Errorf("retention-prune-batch must be positive")
+	}
+	if cfg.UtxoSnapshotInterval <= 0 {
+		return nil, fmt.Errorf("utxo-snapshot-interval must be positive")
+	}
+	if cfg.BTCRetainBlocks < 0 || cfg.BTCRetainAddressBlocks < 0 ||
+		cfg.LTCRetainBlocks < 0 || cfg.LTCRetainAddressBlocks < 0 {
+		return nil, fmt.Errorf("the retain-blocks options must be non-negative")
+	}
+	if cfg.BTCRetainAge < 0 || cfg.BTCRetainAddressAge < 0 ||
+		cfg.LTCRetainAge < 0 || cfg.LTCRetainAddressAge < 0 {
+		return nil, fmt.Errorf("the retain-age options must be non-negative")
+	}
+	// The Electrum servers look up the scripthashes in the outputs of the
+	// whole chain.
+	if !cfg.SyncChainDB && (cfg.BTCElectrumListen != "" || cfg.BTCElectrumTLSListen != "" ||
+		cfg.LTCElectrumListen != "" || cfg.LTCElectrumTLSListen != "") {
+		return nil, fmt.Errorf("the electrum-listen options require syncchaindb")
+	}
+
 	// Set the host names and ports to the default if the user does not specify
 	// them.
 	cfg.DcrdServ, err = normalizeNetworkAddress(cfg.DcrdServ, defaultHost, activeNet.JSONRPCClientPort)
@@ -764,6 +888,8 @@ func loadConfig() (*config, error) {
 	cfg.AgendasDBFileName = cleanAndExpandPath(cfg.AgendasDBFileName)
 	cfg.ProposalsFileName = cleanAndExpandPath(cfg.ProposalsFileName)
 	cfg.RateCertificate = cleanAndExpandPath(cfg.RateCertificate)
+	cfg.ElectrumTLSCert = cleanAndExpandPath(cfg.ElectrumTLSCert)
+	cfg.ElectrumTLSKey = cleanAndExpandPath(cfg.ElectrumTLSKey)
 	cfg.ChartsCacheDump = cleanAndExpandPath(cfg.ChartsCacheDump)
 	cfg.LTCChartsCacheDump = cleanAndExpandPath(cfg.LTCChartsCacheDump)
 	cfg.BTCChartsCacheDump = cleanAndExpandPath(cfg.BTCChartsCacheDump)
@@ -304,3 +304,15 @@ func TestNormalizeNetworkAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestElectrumRequiresSyncChainDB(t *testing.T) {
+	t.Setenv("DCRDATA_BTC_ELECTRUM_LISTEN", ":50001")
+	if _, err := loadConfig(); err == nil {
+		t.Error("expected an error for an Electrum server without syncchaindb")
+	}
+
+	t.Setenv("SYNC_CHAIN_DB", "1")
+	if _, err := loadConfig(); err != nil {
+		t.Errorf("Failed to load dcrdata config: %v", err)
+	}
+}
@@ -39,12 +39,16 @@ require (
 	github.com/google/gops v0.3.27
 	github.com/googollee/go-socket.io v1.4.4
 	github.com/gorilla/websocket v1.5.0
+	github.com/graph-gophers/graphql-go v1.7.0
 	github.com/jessevdk/go-flags v1.5.0
 	github.com/jrick/logrotate v1.0.0
 	github.com/ltcsuite/ltcd v0.23.5
 	github.com/ltcsuite/ltcd/chaincfg/chainhash v1.0.2
 	github.com/ltcsuite/ltcd/ltcutil v1.1.3
+	github.com/monperrus/crawler-user-agents v0.0.0-20240519135500-708b496e7e7b
+	github.com/prometheus/client_golang v1.14.0
 	github.com/rs/cors v1.8.2
+	github.com/x-way/crawlerdetect v0.2.21
 	golang.org/x/net v0.21.0
 	golang.org/x/text v0.14.0
 )
@@ -180,10 +184,8 @@ require (
 	github.com/matttproud/golang_protobuf_extensions v1.0.4 // indirect
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
 	github.com/mitchellh/pointerstructure v1.2.0 // indirect
-	github.com/monperrus/crawler-user-agents v0.0.0-20240519135500-708b496e7e7b // indirect
 	github.com/olekukonko/tablewriter v0.0.5 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	github.com/prometheus/client_golang v1.14.0 // indirect
 	github.com/prometheus/client_model v0.3.0 // indirect
 	github.com/prometheus/common v0.39.0 // indirect
 	github.com/prometheus/procfs v0.9.0 // indirect
@@ -198,7 +200,6 @@ require (
 	github.com/tklauser/numcpus v0.6.0 // indirect
 	github.com/tyler-smith/go-bip39 v1.1.0 // indirect
 	github.com/urfave/cli/v2 v2.17.2-0.20221006022127-8f469abc00aa // indirect
-	github.com/x-way/crawlerdetect v0.2.21 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
 	github.com/zquestz/grab v0.0.0-20190224022517-abcee96e61b1 // indirect
 	go.etcd.io/bbolt v1.3.7-0.20220130032806-d5db64bdbfde // indirect
@@ -16,6 +16,7 @@ import (
 
 type apiMux struct {
 	*chi.Mux
+	spec *openAPISpec
 }
 
 type fileMux struct {
@@ -38,8 +39,11 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 
 	mux.Get("/status", app.status)
 	mux.Get("/status/happy", app.statusHappy)
+	mux.Get("/status/ready", app.statusReady)
+	mux.Get("/status/{chain}", app.chainStatus)
 	mux.Get("/supply", app.coinSupply)
 	mux.Get("/supply/circulating", app.coinSupplyCirculating)
+	mux.Get("/utxoset", app.utxoSet)
 
 	compMiddleware := m.Next
 	if compressLarge {
@@ -142,6 +146,10 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		r.Get("/powerless", app.getPowerlessTickets)
 	})
 
+	// Requests for the other chains get the same error whether the chain is
+	// unknown or disabled.
+	chainTypeCtx := m.ChainTypePathCtx(app.ChainDisabledMap)
+
 	mux.Route("/tx", func(r chi.Router) {
 		r.Route("/", func(rt chi.Router) {
 			rt.Route("/{txid}", func(rd chi.Router) {
@@ -162,10 +170,10 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		})
 		r.With(m.TransactionHashCtx).Get("/hex/{txid}", app.getTransactionHex)
 		r.With(m.TransactionHashCtx).Get("/decoded/{txid}", app.getDecodedTx)
-		r.With(m.MultichainTxHashCtx).Get("/hex/{chaintype}/{txid}", app.getMultichainTransactionHex)
-		r.With(m.MultichainTxHashCtx).Get("/decoded/{chaintype}/{txid}", app.getMultichainDecodedTx)
+		r.With(chainTypeCtx, m.MultichainTxHashCtx).Get("/hex/{chaintype}/{txid}", app.getMultichainTransactionHex)
+		r.With(chainTypeCtx, m.MultichainTxHashCtx).Get("/decoded/{chaintype}/{txid}", app.getMultichainDecodedTx)
 		r.With(m.TransactionHashCtx).Get("/swaps/{txid}", app.getTxSwapsInfo)
-		r.With(m.MultichainTxHashCtx).Get("/swaps/{chaintype}/{txid}", app.getMultichainTxSwapsInfo)
+		r.With(chainTypeCtx, m.MultichainTxHashCtx).Get("/swaps/{chaintype}/{txid}", app.getMultichainTxSwapsInfo)
 	})
 
 	mux.Route("/txs", func(r chi.Router) {
@@ -188,6 +196,7 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 				re.Get("/", app.getAddressTransactions)
 				re.With(m.ChartGroupingCtx).Get("/types/{chartgrouping}", app.getAddressTxTypesData)
 				re.With(m.ChartGroupingCtx).Get("/amountflow/{chartgrouping}", app.getAddressTxAmountFlowData)
+				re.With(m.ChartGroupingCtx).Get("/fiatflow/{chartgrouping}", app.getAddressFiatFlowData)
 				re.With(compMiddleware).Get("/raw", app.getAddressTransactionsRaw)
 				re.Route("/count/{N}", func(ri chi.Router) {
 					ri.Use(m.NPathCtx)
@@ -212,11 +221,97 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 
 	mux.Route("/chainaddress", func(r chi.Router) {
 		r.Route("/{chaintype}/{address}", func(rd chi.Router) {
-			rd.Use(m.AddressPathCtxN(1))
+			rd.Use(chainTypeCtx, m.AddressPathCtxN(1))
 			rd.Get("/", app.getMutilchainAddressTransactions)
 		})
 	})
 
+	// Block, transaction, address, mempool and supply endpoints of the other
+	// chains, mirroring those of the DCR API above.
+	mux.Route("/chain/{chaintype}", func(r chi.Router) {
+		r.Use(chainTypeCtx)
+		r.Route("/block", func(rb chi.Router) {
+			rb.Route("/best", func(rd chi.Router) {
+				rd.Use(app.ChainBlockHashCtx)
+				rd.Get("/", app.getChainBlockSummary)
+				rd.Get("/height", app.getChainBlockHeight)
+				rd.Get("/hash", app.getChainBlockHash)
+				rd.Get("/raw", app.getChainBlockRaw)
+				rd.Get("/size", app.getChainBlockSize)
+				rd.Get("/subsidy", app.getChainBlockSubsidy)
+				rd.Route("/tx", func(rt chi.Router) {
+					rt.Get("/", app.getChainBlockTransactions)
+					rt.Get("/count", app.getChainBlockTransactionsCount)
+				})
+			})
+
+			rb.Route("/hash/{blockhash}", func(rd chi.Router) {
+				rd.Use(app.ChainBlockHashCtx)
+				rd.Get("/", app.getChainBlockSummary)
+				rd.Get("/height", app.getChainBlockHeight)
+				rd.Get("/raw", app.getChainBlockRaw)
+				rd.Get("/size", app.getChainBlockSize)
+				rd.Get("/subsidy", app.getChainBlockSubsidy)
+				rd.Route("/tx", func(rt chi.Router) {
+					rt.Get("/", app.getChainBlockTransactions)
+					rt.Get("/count", app.getChainBlockTransactionsCount)
+				})
+			})
+
+			rb.Route("/{idx}", func(rd chi.Router) {
+				rd.Use(app.ChainBlockHashCtx)
+				rd.Get("/", app.getChainBlockSummary)
+				rd.Get("/hash", app.getChainBlockHash)
+				rd.Get("/raw", app.getChainBlockRaw)
+				rd.Get("/size", app.getChainBlockSize)
+				rd.Get("/subsidy", app.getChainBlockSubsidy)
+				rd.Route("/tx", func(rt chi.Router) {
+					rt.Get("/", app.getChainBlockTransactions)
+					rt.Get("/count", app.getChainBlockTransactionsCount)
+				})
+			})
+
+			rb.Route("/range/{idx0}/{idx}", func(rd chi.Router) {
+				rd.Use(m.BlockIndex0PathCtx, m.BlockIndexPathCtx)
+				rd.Use(compMiddleware)
+				rd.Get("/", app.getChainBlockRangeSummary)
+				rd.Get("/size", app.getChainBlockRangeSize)
+			})
+		})
+
+		r.Route("/tx/{txid}", func(rd chi.Router) {
+			rd.Use(m.TransactionHashCtx)
+			rd.Get("/", app.getChainTransaction)
+			rd.Get("/hex", app.getChainTransactionHex)
+			rd.Get("/decoded", app.getChainDecodedTx)
+			rd.Route("/out", func(ro chi.Router) {
+				ro.Get("/", app.getChainTransactionOutputs)
+				ro.With(m.TransactionIOIndexCtx).Get("/{txinoutindex}", app.getChainTransactionOutput)
+			})
+			rd.Route("/in", func(ri chi.Router) {
+				ri.Get("/", app.getChainTransactionInputs)
+				ri.With(m.TransactionIOIndexCtx).Get("/{txinoutindex}", app.getChainTransactionInput)
+			})
+		})
+
+		r.Route("/address/{address}", func(rd chi.Router) {
+			rd.Get("/", app.getChainAddressTransactions)
+			rd.Get("/totals", app.getChainAddressTotals)
+			rd.With(m.ChartGroupingCtx).Get("/fiatflow/{chartgrouping}", app.getChainAddressFiatFlowData)
+			rd.Route("/count/{N}", func(ri chi.Router) {
+				ri.Use(m.NPathCtx)
+				ri.Get("/", app.getChainAddressTransactions)
+				ri.With(m.MPathCtx).Get("/skip/{M}", app.getChainAddressTransactions)
+			})
+		})
+
+		r.Get("/mempool", app.getChainMempool)
+		r.Get("/supply", app.getChainCoinSupply)
+		r.Get("/supply/circulating", app.getChainCoinSupplyCirculating)
+		r.Get("/retention", app.getChainRetention)
+		r.Get("/utxoset", app.getChainUtxoSet)
+	})
+
 	// Treasury
 	mux.Route("/treasury", func(r chi.Router) {
 		r.Get("/balance", app.getTreasuryBalance)
@@ -226,6 +321,22 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		})
 	})
 
+	// CoinShuffle++ mix statistics
+	mux.Route("/mixes", func(r chi.Router) {
+		r.Get("/denoms", app.getMixDenomSummary)
+		r.Get("/daily", app.getDailyMixStats)
+		r.With(m.BlockIndexPathCtx).Get("/block/{idx}", app.getBlockMixStats)
+		r.With(m.BlockIndex0PathCtx, m.BlockIndexPathCtx).Get("/range/{idx0}/{idx}", app.getBlockRangeMixStats)
+		r.Route("/txs", func(rd chi.Router) {
+			rd.Get("/", app.getMixTxns)
+			rd.Route("/count/{N}", func(ri chi.Router) {
+				ri.Use(m.NPathCtx)
+				ri.Get("/", app.getMixTxns)
+				ri.With(m.MPathCtx).Get("/skip/{M}", app.getMixTxns)
+			})
+		})
+	})
+
 	// Returns agenda data like; description, name, lockedin activated and other
 	// high level agenda details for all agendas.
 	mux.Route("/agendas", func(r chi.Router) {
@@ -255,6 +366,7 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Use(m.ExchangeTokenContext)
 			rd.With(m.StickWidthContext).Get("/candlestick/{bin}", app.getCandlestickChart)
 			rd.Get("/depth", app.getDepthChart)
+			rd.Get("/liquidity", app.getLiquidityChart)
 		})
 		r.Route("/submarket/{token}", func(rd chi.Router) {
 			rd.Use(m.ExchangeTokenContext)
@@ -268,6 +380,7 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 			rd.Use(m.ExchangeTokenContext)
 			rd.With(m.StickWidthContext).Get("/candlestick/{bin}", app.getMutilchainCandlestickChart)
 			rd.Get("/depth", app.getMutilchainDepthChart)
+			rd.Get("/liquidity", app.getLiquidityChart)
 		})
 		r.Route("/{chaintype}/submarket/{token}", func(rd chi.Router) {
 			rd.Use(m.ExchangeTokenContext)
@@ -345,12 +458,33 @@ func NewAPIRouter(app *appContext, JSONIndent string, useRealIP, compressLarge b
 		return patterns
 	}
 
+	// Server-Sent Events streams of the pubsub events.
+	mux.Get("/stream", app.streamEvents)
+
+	// GraphQL queries, and subscriptions as Server-Sent Events streams.
+	mux.Get("/graphql", app.graphQL)
+	mux.Post("/graphql", app.graphQL)
+
+	// Webhook subscriptions of the API key of the request.
+	mux.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", app.getWebhookSubscriptions)
+		r.Post("/", app.postWebhookSubscription)
+		r.Delete("/{id}", app.deleteWebhookSubscription)
+		r.Get("/deadletters", app.getWebhookDeadLetters)
+	})
+
 	mux.HandleFunc("/list", func(w http.ResponseWriter, _ *http.Request) {
 		routeList := listRoutePatterns(mux.Routes())
 		writeJSON(w, routeList, JSONIndent)
 	})
 
-	return apiMux{mux}
+	// OpenAPI 3 document describing this router and any other routers added
+	// with DocumentRouter.
+	spec := new(openAPISpec)
+	spec.document(documentedRouter{prefix: "/api", routes: mux})
+	mux.Get("/openapi.json", spec.serve)
+
+	return apiMux{mux, spec}
 }
 
 // NewFileRouter creates a new HTTP request path router/mux for file downloads.
@@ -366,6 +500,24 @@ func NewFileRouter(app *appContext, useRealIP bool) fileMux {
 		rd.With(m.AddressPathCtxN(1)).Get("/io/{address}/win", app.addressIoCsvCR)
 	})
 
+	// Data exports, as CSV or NDJSON.
+	mux.Route("/export", func(rd chi.Router) {
+		rd.Use(app.limitExports)
+		rd.With(m.AddressPathCtxN(1)).Get("/address/{address}", app.exportAddress)
+		rd.Get("/blocks", app.exportBlocks)
+		rd.Get("/treasury", app.exportTreasury)
+		rd.Get("/swaps", app.exportAtomicSwaps)
+		rd.With(m.ChartTypeCtx).Get("/chart/{charttype}", app.exportChart)
+		rd.Get("/report", app.exportCostBasisReport)
+
+		rd.Route("/{chaintype}", func(rc chi.Router) {
+			rc.Use(m.ChainTypePathCtx(app.ChainDisabledMap))
+			rc.Get("/address/{address}", app.exportMutilchainAddress)
+			rc.Get("/blocks", app.exportMutilchainBlocks)
+			rc.With(m.ChartTypeCtx).Get("/chart/{charttype}", app.exportMutilchainChart)
+		})
+	})
+
 	return fileMux{mux}
 }
 
@@ -69,6 +69,8 @@ type DataSource interface {
 	FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
 	AddressTransactionDetails(addr string, count, skip int64,
 		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
+	AddressTransactionDetailsByCursor(addr string, count int64, cursor *dbtypes.PageCursor,
+		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
 	MutilchainAddressTransactionDetails(addr, chainType string, count, skip int64,
 		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
 	AddressTotals(address string) (*apitypes.AddressTotals, error)
@@ -79,6 +81,18 @@ type DataSource interface {
 		chartGroupings dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error)
 	TreasuryBalance() (*dbtypes.TreasuryBalance, error)
 	BinnedTreasuryIO(chartGroupings dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error)
+	MixStatsByBlockRange(from, to int64) ([]*dbtypes.MixStats, error)
+	MixStatsByDay(from, to time.Time) ([]*dbtypes.MixStats, error)
+	MixDenomSummary() ([]*dbtypes.MixDenomStats, error)
+	MixTxns(n, offset, denom int64) ([]*dbtypes.MixTx, int64, error)
+	MixTxnsByCursor(n int64, cursor *dbtypes.PageCursor, denom int64) ([]*dbtypes.MixTx, int64, string, error)
+	ExportAddressRows(ctx context.Context, ew dbtypes.ExportWriter, address string, fiat bool, maxRows int64) error
+	ExportBlocks(ctx context.Context, ew dbtypes.ExportWriter, from, to int64, fiat bool, maxRows int64) error
+	ExportTreasuryTxns(ctx context.Context, ew dbtypes.ExportWriter, fiat bool, maxRows int64) error
+	ExportAtomicSwaps(ctx context.Context, ew dbtypes.ExportWriter, fiat bool, maxRows int64) error
+	ExportMutilchainAddressRows(ctx context.Context, ew dbtypes.ExportWriter, chainType, address string, maxRows int64) error
+	ExportMutilchainBlocks(ctx context.Context, ew dbtypes.ExportWriter, chainType string, from, to, maxRows int64) error
+	AddressLedger(ctx context.Context, chainType string, addresses []string, to, maxRows int64) ([]*dbtypes.LedgerTx, error)
 	TicketPoolVisualization(interval dbtypes.TimeBasedGrouping) (
 		*dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, *dbtypes.PoolTicketsData, int64, error)
 	AgendaVotes(agendaID string, chartType int) (*dbtypes.AgendaVoteChoices, error)
@@ -153,8 +167,24 @@ type DataSource interface {
 	GetMultichainTransactionHex(txid, chainType string) string
 	GetMultichainTransactionVerbose(txid, chainType string) (any, error)
 	GetMultichainSwapInfoData(txid, chainType string) (swapsInfo *txhelpers.TxAtomicSwaps, err error)
+	MutilchainBestBlock(chainType string) (int64, string, error)
+	MutilchainBlockHashAtHeight(height int64, chainType string) (string, error)
+	MutilchainBlockSummary(hash, chainType string) (*apitypes.ChainBlockSummary, error)
+	MutilchainBlockSubsidy(hash, chainType string) (*apitypes.ChainBlockSubsidy, error)
+	MutilchainBlockTxids(hash, chainType string) ([]string, error)
+	MutilchainRawBlock(hash, chainType string) (string, error)
+	MutilchainTx(txid, chainType string) (*apitypes.ChainTx, error)
+	MutilchainAddressTotals(address, chainType string) (*apitypes.ChainAddressTotals, error)
+	MutilchainMempoolTxids(chainType string) ([]string, error)
+	MutilchainCoinSupply(chainType string) (*apitypes.ChainCoinSupply, error)
+	MultichainRetentionWindow(chainType string) *dbtypes.RetentionWindow
+	UtxoSetSnapshot(chainType string) (*dbtypes.UtxoSetSnapshot, error)
+	IsMutilchainValidAddress(chainType string, address string) bool
 	InsertToBlackList(agent, ip, note string) error
 	CheckOnBlackList(agent, ip string) (bool, error)
+	FiatPrices(chainType, currency string, from, to int64) (*dbtypes.FiatPrices, error)
+	FiatCurrencies(chainType string) ([]string, error)
+	AddressFiatFlow(chainType, address, currency string, grouping dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error)
 }
 
 // dcrdata application context used by all route handlers
@@ -169,6 +199,8 @@ type appContext struct {
 	AgendaDB         *agendas.AgendaDB
 	ProposalsDB      *politeia.ProposalsDB
 	maxCSVAddrs      int
+	maxExportRows    int64
+	exportSlots      chan struct{}
 	charts           *cache.ChartData
 	LtcCharts        *cache.MutilchainChartData
 	BtcCharts        *cache.MutilchainChartData
@@ -176,6 +208,10 @@ type appContext struct {
 	ChainDisabledMap map[string]bool
 	CoinCaps         []string
 	CoinCapDataList  []*dbtypes.MarketCapData
+	webhooks         WebhookManager
+	eventStream      http.HandlerFunc
+	graphQLServer    http.Handler
+	health           HealthMonitor
 }
 
 // AppContextConfig is the configuration for the appContext and the only
@@ -190,12 +226,32 @@ type AppContextConfig struct {
 	AgendasDBInstance *agendas.AgendaDB
 	ProposalsDB       *politeia.ProposalsDB
 	MaxAddrs          int
+	MaxExportRows     int64
+	MaxExports        int // concurrent data exports
 	Charts            *cache.ChartData
 	LtcCharts         *cache.MutilchainChartData
 	BtcCharts         *cache.MutilchainChartData
 	AppVer            string
 	ChainDisabledMap  map[string]bool
 	CoinCaps          []string
+	// Webhooks manages the webhook subscriptions. Webhooks are disabled if
+	// nil.
+	Webhooks WebhookManager
+	// EventStream serves the Server-Sent Events streams at /api/stream. The
+	// streams are disabled if nil.
+	EventStream http.HandlerFunc
+	// GraphQL serves the GraphQL queries and subscriptions at /api/graphql.
+	// The endpoint is disabled if nil.
+	GraphQL http.Handler
+	// Health reports the health of the chains at /api/status/{chain} and
+	// /api/status/ready. The endpoints are disabled if nil.
+	Health HealthMonitor
+}
+
+// HealthMonitor reports the health of the enabled chains.
+type HealthMonitor interface {
+	ChainHealth(chainType string) (*apitypes.ChainHealth, bool)
+	Readiness() *apitypes.Readiness
 }
 
 type simulationRow struct {
@@ -221,6 +277,11 @@ func NewContext(cfg *AppContextConfig) *appContext {
 		return nil
 	}
 
+	webhooks := cfg.Webhooks
+	if webhooks != nil && reflect.ValueOf(webhooks).IsNil() {
+		webhooks = nil
+	}
+
 	return &appContext{
 		nodeClient:       cfg.Client,
 		btcNodeClient:    cfg.BtcClient,
@@ -232,9 +293,15 @@ func NewContext(cfg *AppContextConfig) *appContext {
 		ProposalsDB:      cfg.ProposalsDB,
 		Status:           apitypes.NewStatus(uint32(nodeHeight), conns, APIVersion, cfg.AppVer, cfg.Params.Name),
 		maxCSVAddrs:      cfg.MaxAddrs,
+		maxExportRows:    cfg.MaxExportRows,
+		exportSlots:      make(chan struct{}, cfg.MaxExports),
 		charts:           cfg.Charts,
 		ChainDisabledMap: cfg.ChainDisabledMap,
 		CoinCaps:         cfg.CoinCaps,
+		webhooks:         webhooks,
+		eventStream:      cfg.EventStream,
+		graphQLServer:    cfg.GraphQL,
+		health:           cfg.Health,
 	}
 }
 
@@ -393,6 +460,24 @@ func (c *appContext) status(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, c.Status.API(), m.GetIndentCtx(r))
 }
 
+// streamEvents serves a Server-Sent Events stream of the pubsub events.
+func (c *appContext) streamEvents(w http.ResponseWriter, r *http.Request) {
+	if c.eventStream == nil {
+		http.Error(w, "event streams are disabled", http.StatusNotFound)
+		return
+	}
+	c.eventStream(w, r)
+}
+
+// graphQL serves the GraphQL queries and subscriptions.
+func (c *appContext) graphQL(w http.ResponseWriter, r *http.Request) {
+	if c.graphQLServer == nil {
+		http.Error(w, "GraphQL is disabled", http.StatusNotFound)
+		return
+	}
+	c.graphQLServer.ServeHTTP(w, r)
+}
+
 func (c *appContext) statusHappy(w http.ResponseWriter, r *http.Request) {
 	happy := c.Status.Happy()
 	statusCode := http.StatusOK
@@ -403,6 +488,41 @@ func (c *appContext) statusHappy(w http.ResponseWriter, r *http.Request) {
 	writeJSONWithStatus(w, happy, statusCode, m.GetIndentCtx(r))
 }
 
+// chainStatus reports the health of a chain. The status code is 503 if the
+// chain is failing, so that it may be used as a health probe.
+func (c *appContext) chainStatus(w http.ResponseWriter, r *http.Request) {
+	if c.health == nil {
+		http.Error(w, "health checks are disabled", http.StatusNotFound)
+		return
+	}
+	chainType := chi.URLParam(r, "chain")
+	health, ok := c.health.ChainHealth(chainType)
+	if !ok {
+		http.Error(w, fmt.Sprintf("chain %q is not enabled", chainType), http.StatusNotFound)
+		return
+	}
+	statusCode := http.StatusOK
+	if !health.Healthy() {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeJSONWithStatus(w, health, statusCode, m.GetIndentCtx(r))
+}
+
+// statusReady reports the health of all the enabled chains. The status code is
+// 503 if any chain is failing, so that it may be used as a readiness probe.
+func (c *appContext) statusReady(w http.ResponseWriter, r *http.Request) {
+	if c.health == nil {
+		http.Error(w, "health checks are disabled", http.StatusNotFound)
+		return
+	}
+	readiness := c.health.Readiness()
+	statusCode := http.StatusOK
+	if !readiness.Ready {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeJSONWithStatus(w, readiness, statusCode, m.GetIndentCtx(r))
+}
+
 func (c *appContext) coinSupply(w http.ResponseWriter, r *http.Request) {
 	supply := c.DataSource.CurrentCoinSupply()
 	if supply == nil {
@@ -441,6 +561,28 @@ func (c *appContext) coinSupplyCirculating(w http.ResponseWriter, r *http.Reques
 	writeJSONBytes(w, []byte(strconv.FormatInt(supply.Mined, 10)))
 }
 
+// utxoSet serves the latest snapshot of the DCR UTXO set.
+func (c *appContext) utxoSet(w http.ResponseWriter, r *http.Request) {
+	c.writeUtxoSetSnapshot(w, r, mutilchain.TYPEDCR)
+}
+
+// writeUtxoSetSnapshot writes the latest UTXO set snapshot of the chain, or
+// 404 if no snapshot was taken yet.
+func (c *appContext) writeUtxoSetSnapshot(w http.ResponseWriter, r *http.Request, chainType string) {
+	snapshot, err := c.DataSource.UtxoSetSnapshot(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s UTXO set snapshot: %v", chainType, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if snapshot == nil {
+		http.Error(w, "no UTXO set snapshot yet", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, snapshot, m.GetIndentCtx(r))
+}
+
 func (c *appContext) currentHeight(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	if _, err := io.WriteString(w, strconv.Itoa(int(c.Status.Height()))); err != nil {
@@ -737,6 +879,14 @@ func (c *appContext) getTransaction(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if tx.Block != nil {
+		prices, err := c.fiatPrices(w, r, mutilchain.TYPEDCR, tx.Block.Time, tx.Block.Time)
+		if err != nil {
+			return
+		}
+		setTxFiatValue(tx, prices)
+	}
+
 	writeJSON(w, tx, m.GetIndentCtx(r))
 }
 
@@ -3469,6 +3619,176 @@ func (c *appContext) getTreasuryIO(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, data, m.GetIndentCtx(r))
 }
 
+// maxMixTxnsCount is the maximum number of mix transactions that can be
+// requested at once.
+const maxMixTxnsCount = 1000
+
+func (c *appContext) getBlockMixStats(w http.ResponseWriter, r *http.Request) {
+	idx := int64(m.GetBlockIndexCtx(r))
+	if idx < 0 || idx > int64(c.Status.Height()) {
+		http.Error(w, "invalid block index", http.StatusBadRequest)
+		return
+	}
+	stats, err := c.DataSource.MixStatsByBlockRange(idx, idx)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MixStatsByBlockRange: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get mix stats for block %d: %v", idx, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	blockStats := &dbtypes.MixStats{Height: idx, Denoms: []*dbtypes.MixDenomStats{}}
+	if len(stats) > 0 {
+		blockStats = stats[0]
+	}
+	writeJSON(w, blockStats, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getBlockRangeMixStats(w http.ResponseWriter, r *http.Request) {
+	low, high := int64(m.GetBlockIndex0Ctx(r)), int64(m.GetBlockIndexCtx(r))
+	if low > high {
+		low, high = high, low
+	}
+	if low < 0 || high > int64(c.Status.Height()) {
+		http.Error(w, "invalid block range", http.StatusBadRequest)
+		return
+	}
+	if high-low+1 > maxBlockRangeCount {
+		http.Error(w, fmt.Sprintf("requested more than %d-block maximum", maxBlockRangeCount), http.StatusBadRequest)
+		return
+	}
+	stats, err := c.DataSource.MixStatsByBlockRange(low, high)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MixStatsByBlockRange: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get mix stats for blocks %d-%d: %v", low, high, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	if stats == nil {
+		stats = []*dbtypes.MixStats{}
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+// getDailyMixStats serves the daily (UTC) mix statistics. The optional "from"
+// and "to" URL query parameters are UNIX timestamps that limit the range of
+// days, which defaults to all days.
+func (c *appContext) getDailyMixStats(w http.ResponseWriter, r *http.Request) {
+	from, to := time.Unix(0, 0), time.Now().Add(24*time.Hour)
+	for param, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		if str := r.URL.Query().Get(param); str != "" {
+			unix, err := strconv.ParseInt(str, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s parameter", param), http.StatusBadRequest)
+				return
+			}
+			*t = time.Unix(unix, 0)
+		}
+	}
+	stats, err := c.DataSource.MixStatsByDay(from, to)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MixStatsByDay: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get daily mix stats: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	if stats == nil {
+		stats = []*dbtypes.MixStats{}
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getMixDenomSummary(w http.ResponseWriter, r *http.Request) {
+	denoms, err := c.DataSource.MixDenomSummary()
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MixDenomSummary: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get mix denomination summary: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	if denoms == nil {
+		denoms = []*dbtypes.MixDenomStats{}
+	}
+	writeJSON(w, denoms, m.GetIndentCtx(r))
+}
+
+// getMixTxns serves a page of mix transactions, most recent first. The page
+// size and offset are set by the count and skip path parameters, and the
+// optional "denom" URL query parameter limits the results to a single mix
+// denomination in atoms. The "cursor" URL query parameter selects cursor
+// pagination instead of the skip offset, and the response then includes the
+// cursor of the next page.
+func (c *appContext) getMixTxns(w http.ResponseWriter, r *http.Request) {
+	count := int64(m.GetNCtx(r))
+	if count < 0 {
+		count = 100
+	}
+	if count > maxMixTxnsCount {
+		count = maxMixTxnsCount
+	}
+	skip := int64(m.GetMCtx(r))
+	if skip < 0 {
+		skip = 0
+	}
+	var denom int64
+	if denomStr := r.URL.Query().Get("denom"); denomStr != "" {
+		var err error
+		denom, err = strconv.ParseInt(denomStr, 10, 64)
+		if err != nil || denom < 0 {
+			http.Error(w, "invalid denom parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	var txns []*dbtypes.MixTx
+	var total int64
+	var nextCursor string
+	var err error
+	if query := r.URL.Query(); query.Has("cursor") {
+		var cursor *dbtypes.PageCursor
+		cursor, err = dbtypes.ParsePageCursor(query.Get("cursor"))
+		if err != nil {
+			http.Error(w, "invalid cursor parameter", http.StatusBadRequest)
+			return
+		}
+		txns, total, nextCursor, err = c.DataSource.MixTxnsByCursor(count, cursor, denom)
+	} else {
+		txns, total, err = c.DataSource.MixTxns(count, skip, denom)
+	}
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MixTxns: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get mix transactions: %v", err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	if txns == nil {
+		txns = []*dbtypes.MixTx{}
+	}
+	writeJSON(w, struct {
+		Total      int64            `json:"total"`
+		Txns       []*dbtypes.MixTx `json:"txs"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}{total, txns, nextCursor}, m.GetIndentCtx(r))
+}
+
 func (c *appContext) ChartTypeData(w http.ResponseWriter, r *http.Request) {
 	chartType := m.GetChartTypeCtx(r)
 	bin := r.URL.Query().Get("bin")
@@ -3707,6 +4027,31 @@ func (c *appContext) getDepthChart(w http.ResponseWriter, r *http.Request) {
 	writeJSONBytes(w, chart)
 }
 
+// route: /market/{token}/liquidity and chainchart/{chaintype}/market/{token}/liquidity
+func (c *appContext) getLiquidityChart(w http.ResponseWriter, r *http.Request) {
+	if c.xcBot == nil {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	chainType := chi.URLParam(r, "chaintype")
+	if chainType == "" {
+		chainType = mutilchain.TYPEDCR
+	}
+	token := m.RetrieveExchangeTokenCtx(r)
+	if token == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	chart, err := c.xcBot.QuickLiquidity(token, chainType)
+	if err != nil {
+		apiLog.Infof("QuickLiquidity error: %v", err)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	writeJSONBytes(w, chart)
+}
+
 func (c *appContext) getAddressTransactions(w http.ResponseWriter, r *http.Request) {
 	addresses, err := m.GetAddressCtx(r, c.Params)
 	if err != nil || len(addresses) > 1 {
@@ -3726,7 +4071,20 @@ func (c *appContext) getAddressTransactions(w http.ResponseWriter, r *http.Reque
 		skip = 0
 	}
 
-	txs, err := c.DataSource.AddressTransactionDetails(address, count, skip, dbtypes.AddrTxnAll)
+	// The cursor query parameter selects cursor pagination, which replaces
+	// the skip path parameter.
+	var txs *apitypes.Address
+	if query := r.URL.Query(); query.Has("cursor") {
+		var cursor *dbtypes.PageCursor
+		cursor, err = dbtypes.ParsePageCursor(query.Get("cursor"))
+		if err != nil {
+			http.Error(w, "invalid cursor parameter", http.StatusBadRequest)
+			return
+		}
+		txs, err = c.DataSource.AddressTransactionDetailsByCursor(address, count, cursor, dbtypes.AddrTxnAll)
+	} else {
+		txs, err = c.DataSource.AddressTransactionDetails(address, count, skip, dbtypes.AddrTxnAll)
+	}
 	if dbtypes.IsTimeoutErr(err) {
 		apiLog.Errorf("AddressTransactionDetails: %v", err)
 		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
@@ -3737,6 +4095,11 @@ func (c *appContext) getAddressTransactions(w http.ResponseWriter, r *http.Reque
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
+	prices, err := c.addressFiatPrices(w, r, mutilchain.TYPEDCR, txs.Transactions)
+	if err != nil {
+		return
+	}
+	setAddressFiatValues(txs.Transactions, prices)
 	writeJSON(w, txs, m.GetIndentCtx(r))
 }
 
@@ -3776,6 +4139,12 @@ func (c *appContext) getMutilchainAddressTransactions(w http.ResponseWriter, r *
 		http.Error(w, http.StatusText(422), 422)
 		return
 	}
+	prices, err := c.addressFiatPrices(w, r, chainType, txs.Transactions)
+	if err != nil {
+		return
+	}
+	setAddressFiatValues(txs.Transactions, prices)
+	txs.RetainedFromHeight = c.DataSource.MultichainRetentionWindow(chainType).AddressFromHeight
 	writeJSON(w, txs, m.GetIndentCtx(r))
 }
 
@@ -0,0 +1,563 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/go-chi/chi/v5"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// The handlers in this file serve the chain-scoped API of the BTC, LTC and XMR
+// chains at /api/chain/{chaintype}. The chain type is validated, and disabled
+// chains rejected, by m.ChainTypePathCtx.
+
+// ChainBlockHashCtx embeds the hash of the block at {blockhash} or {idx}, or of
+// the best block, of the chain at {chaintype} into the request context.
+func (c *appContext) ChainBlockHashCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := m.ChainBlockHashCtx(r, c.DataSource)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (c *appContext) getChainBlockSummary(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, block, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainBlockHeight(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := io.WriteString(w, strconv.FormatInt(block.Height, 10)); err != nil {
+		apiLog.Infof("failed to write height response: %v", err)
+	}
+}
+
+func (c *appContext) getChainBlockHash(w http.ResponseWriter, r *http.Request) {
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := io.WriteString(w, hash); err != nil {
+		apiLog.Infof("failed to write hash response: %v", err)
+	}
+}
+
+func (c *appContext) getChainBlockRaw(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	blockHex, err := c.DataSource.MutilchainRawBlock(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get raw %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	blockRaw := &apitypes.BlockRaw{
+		Height: uint32(block.Height),
+		Hash:   hash,
+		Hex:    blockHex,
+	}
+
+	writeJSON(w, blockRaw, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainBlockSize(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, block.Size, "")
+}
+
+func (c *appContext) getChainBlockSubsidy(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	subsidy, err := c.DataSource.MutilchainBlockSubsidy(hash, chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get the subsidy of %s block %s: %v", chainType, hash, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, subsidy, m.GetIndentCtx(r))
+}
+
+// chainBlockTransactions retrieves the height, hash and transactions of the
+// block set on the request context.
+func (c *appContext) chainBlockTransactions(r *http.Request) (*apitypes.ChainBlockTransactions, error) {
+	chainType := m.GetChainTypeCtx(r)
+	hash, err := m.GetBlockHashCtx(r)
+	if err != nil {
+		return nil, err
+	}
+	block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	txids, err := c.DataSource.MutilchainBlockTxids(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return &apitypes.ChainBlockTransactions{
+		Height: block.Height,
+		Hash:   hash,
+		Tx:     txids,
+	}, nil
+}
+
+func (c *appContext) getChainBlockTransactions(w http.ResponseWriter, r *http.Request) {
+	blockTransactions, err := c.chainBlockTransactions(r)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block transactions: %v", m.GetChainTypeCtx(r), err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, blockTransactions, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainBlockTransactionsCount(w http.ResponseWriter, r *http.Request) {
+	blockTransactions, err := c.chainBlockTransactions(r)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block transactions: %v", m.GetChainTypeCtx(r), err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, len(blockTransactions.Tx), "")
+}
+
+// chainBlockRange retrieves the summaries of the blocks in the range set on the
+// request path, in the order of the path. The response has been written if an
+// error is returned.
+func (c *appContext) chainBlockRange(w http.ResponseWriter, r *http.Request) ([]*apitypes.ChainBlockSummary, error) {
+	chainType := m.GetChainTypeCtx(r)
+	idx0 := int64(m.GetBlockIndex0Ctx(r))
+	idx1 := int64(m.GetBlockIndexCtx(r))
+
+	low, high := idx0, idx1
+	if idx0 > idx1 {
+		low, high = idx1, idx0
+	}
+	bestHeight, _, err := c.DataSource.MutilchainBestBlock(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get the best %s block: %v", chainType, err)
+		http.Error(w, http.StatusText(422), 422)
+		return nil, err
+	}
+	if low < 0 || high > bestHeight {
+		http.Error(w, "invalid block range", http.StatusBadRequest)
+		return nil, fmt.Errorf("invalid block range")
+	}
+
+	if high-low+1 > maxBlockRangeCount {
+		http.Error(w, fmt.Sprintf("requested more than %d-block maximum", maxBlockRangeCount), http.StatusBadRequest)
+		return nil, fmt.Errorf("block range too large")
+	}
+
+	step := int64(1)
+	if idx0 > idx1 {
+		step = -1
+	}
+	blocks := make([]*apitypes.ChainBlockSummary, 0, high-low+1)
+	for idx := idx0; ; idx += step {
+		hash, err := c.DataSource.MutilchainBlockHashAtHeight(idx, chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to get the hash of %s block %d: %v", chainType, idx, err)
+			http.Error(w, http.StatusText(422), 422)
+			return nil, err
+		}
+		block, err := c.DataSource.MutilchainBlockSummary(hash, chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to get %s block %s: %v", chainType, hash, err)
+			http.Error(w, http.StatusText(422), 422)
+			return nil, err
+		}
+		blocks = append(blocks, block)
+		if idx == idx1 {
+			break
+		}
+	}
+	return blocks, nil
+}
+
+func (c *appContext) getChainBlockRangeSummary(w http.ResponseWriter, r *http.Request) {
+	blocks, err := c.chainBlockRange(w, r)
+	if err != nil {
+		return
+	}
+
+	writeJSON(w, blocks, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainBlockRangeSize(w http.ResponseWriter, r *http.Request) {
+	blocks, err := c.chainBlockRange(w, r)
+	if err != nil {
+		return
+	}
+
+	blockSizes := make([]int64, 0, len(blocks))
+	for _, block := range blocks {
+		blockSizes = append(blockSizes, block.Size)
+	}
+
+	writeJSON(w, blockSizes, "")
+}
+
+// chainTx retrieves the transaction set on the request context. The response
+// has been written if an error is returned.
+func (c *appContext) chainTx(w http.ResponseWriter, r *http.Request) (*apitypes.ChainTx, error) {
+	chainType := m.GetChainTypeCtx(r)
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return nil, err
+	}
+
+	tx, err := c.DataSource.MutilchainTx(txid.String(), chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s transaction %s: %v", chainType, txid, err)
+		http.NotFound(w, r)
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (c *appContext) getChainTransaction(w http.ResponseWriter, r *http.Request) {
+	tx, err := c.chainTx(w, r)
+	if err != nil {
+		return
+	}
+
+	if tx.Time > 0 {
+		chainType := m.GetChainTypeCtx(r)
+		prices, err := c.fiatPrices(w, r, chainType, tx.Time, tx.Time)
+		if err != nil {
+			return
+		}
+		setChainTxFiatValue(tx, chainType, prices)
+	}
+
+	writeJSON(w, tx, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainTransactionHex(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	hex := c.DataSource.GetMultichainTransactionHex(txid.String(), m.GetChainTypeCtx(r))
+
+	fmt.Fprint(w, hex)
+}
+
+func (c *appContext) getChainDecodedTx(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	tx, err := c.DataSource.GetMultichainTransactionVerbose(txid.String(), chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s transaction %s: %v", chainType, txid, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, tx, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainTransactionInputs(w http.ResponseWriter, r *http.Request) {
+	tx, err := c.chainTx(w, r)
+	if err != nil {
+		return
+	}
+
+	writeJSON(w, tx.Vin, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainTransactionInput(w http.ResponseWriter, r *http.Request) {
+	index := m.GetTxIOIndexCtx(r)
+	if index < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	tx, err := c.chainTx(w, r)
+	if err != nil {
+		return
+	}
+
+	if len(tx.Vin) <= index {
+		apiLog.Debugf("Index %d larger than []ChainTxIn length %d", index, len(tx.Vin))
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, tx.Vin[index], m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainTransactionOutputs(w http.ResponseWriter, r *http.Request) {
+	tx, err := c.chainTx(w, r)
+	if err != nil {
+		return
+	}
+
+	writeJSON(w, tx.Vout, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainTransactionOutput(w http.ResponseWriter, r *http.Request) {
+	index := m.GetTxIOIndexCtx(r)
+	if index < 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	tx, err := c.chainTx(w, r)
+	if err != nil {
+		return
+	}
+
+	if len(tx.Vout) <= index {
+		apiLog.Debugf("Index %d larger than []ChainTxOut length %d", index, len(tx.Vout))
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, tx.Vout[index], m.GetIndentCtx(r))
+}
+
+// chainAddress retrieves the address at the url part {address}. XMR addresses
+// are not indexed, and are rejected like invalid addresses: the outputs of an
+// XMR address, and so its transactions, amounts and fiat flow, can only be
+// found with the view key of the address, which dcrdata does not have. The
+// response has been written if an error is returned.
+func (c *appContext) chainAddress(w http.ResponseWriter, r *http.Request) (string, error) {
+	chainType := m.GetChainTypeCtx(r)
+	address := chi.URLParam(r, "address")
+	if chainType == mutilchain.TYPEXMR {
+		http.Error(w, fmt.Sprintf("%s addresses are not supported: their outputs are only known with the view key", chainType), 422)
+		return "", fmt.Errorf("unsupported address")
+	}
+	if !c.DataSource.IsMutilchainValidAddress(chainType, address) {
+		http.Error(w, http.StatusText(422), 422)
+		return "", fmt.Errorf("invalid address")
+	}
+	return address, nil
+}
+
+func (c *appContext) getChainAddressTransactions(w http.ResponseWriter, r *http.Request) {
+	address, err := c.chainAddress(w, r)
+	if err != nil {
+		return
+	}
+	chainType := m.GetChainTypeCtx(r)
+	count := int64(m.GetNCtx(r))
+	skip := int64(m.GetMCtx(r))
+	if count <= 0 {
+		count = 10
+	} else if count > 8000 {
+		count = 8000
+	}
+	if skip <= 0 {
+		skip = 0
+	}
+
+	txs, err := c.DataSource.MutilchainAddressTransactionDetails(address, chainType, count, skip, dbtypes.AddrTxnAll)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MutilchainAddressTransactionDetails: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if txs == nil || err != nil {
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	prices, err := c.addressFiatPrices(w, r, chainType, txs.Transactions)
+	if err != nil {
+		return
+	}
+	setAddressFiatValues(txs.Transactions, prices)
+	txs.RetainedFromHeight = c.DataSource.MultichainRetentionWindow(chainType).AddressFromHeight
+
+	writeJSON(w, txs, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainAddressTotals(w http.ResponseWriter, r *http.Request) {
+	address, err := c.chainAddress(w, r)
+	if err != nil {
+		return
+	}
+	chainType := m.GetChainTypeCtx(r)
+
+	totals, err := c.DataSource.MutilchainAddressTotals(address, chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MutilchainAddressTotals: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get totals of %s address %s: %v", chainType, address, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, totals, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainMempool(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	txids, err := c.DataSource.MutilchainMempoolTxids(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get the %s mempool: %v", chainType, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+	if txids == nil {
+		txids = []string{}
+	}
+
+	mempool := &apitypes.ChainMempool{
+		Count: len(txids),
+		Txids: txids,
+	}
+
+	writeJSON(w, mempool, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getChainCoinSupply(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	supply, err := c.DataSource.MutilchainCoinSupply(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s coin supply: %v", chainType, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	writeJSON(w, supply, m.GetIndentCtx(r))
+}
+
+// getChainCoinSupplyCirculating serves the mined supply in atoms, or in coins
+// with ?coins=true.
+func (c *appContext) getChainCoinSupplyCirculating(w http.ResponseWriter, r *http.Request) {
+	var coins bool
+	if coinsParam := r.URL.Query().Get("coins"); coinsParam != "" {
+		var err error
+		coins, err = strconv.ParseBool(coinsParam)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+	}
+
+	chainType := m.GetChainTypeCtx(r)
+	supply, err := c.DataSource.MutilchainCoinSupply(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s coin supply: %v", chainType, err)
+		http.Error(w, http.StatusText(422), 422)
+		return
+	}
+
+	if coins {
+		atomsPerCoin := float64(btcutil.SatoshiPerBitcoin)
+		precision := 8
+		if chainType == mutilchain.TYPEXMR {
+			atomsPerCoin, precision = 1e12, 12
+		}
+		coinSupply := float64(supply.Mined) / atomsPerCoin
+		writeJSONBytes(w, []byte(strconv.FormatFloat(coinSupply, 'f', precision, 64)))
+		return
+	}
+
+	writeJSONBytes(w, []byte(strconv.FormatUint(supply.Mined, 10)))
+}
+
+// getChainRetention serves the range of blocks whose inputs, outputs,
+// transactions and address rows are kept in the DB. The rows of the older
+// blocks are pruned when the whole chain is not synced.
+func (c *appContext) getChainRetention(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	writeJSON(w, c.DataSource.MultichainRetentionWindow(chainType), m.GetIndentCtx(r))
+}
+
+// getChainUtxoSet serves the latest UTXO set snapshot of the BTC and LTC
+// chains. The Monero outputs are not tracked.
+func (c *appContext) getChainUtxoSet(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	if chainType == mutilchain.TYPEXMR {
+		http.Error(w, "no UTXO set statistics for xmr", 422)
+		return
+	}
+	c.writeUtxoSetSnapshot(w, r, chainType)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// merkleBranch returns the merkle branch of the transaction at index pos of a
+// block with the given transaction hashes. The branch lists the hashes paired
+// with the transaction's path to the merkle root, from the leaves up, encoded
+// like transaction hashes.
+func merkleBranch(txids []string, pos int) ([]string, error) {
+	if pos < 0 || pos >= len(txids) {
+		return nil, fmt.Errorf("position %d out of range [0, %d)", pos, len(txids))
+	}
+	level := make([][]byte, len(txids))
+	for i, txid := range txids {
+		hash, err := hex.DecodeString(txid)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("invalid transaction hash %q", txid)
+		}
+		reverseBytes(hash)
+		level[i] = hash
+	}
+
+	var branch []string
+	for len(level) > 1 {
+		// The last hash of a level with an odd number of hashes is paired
+		// with itself.
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+		branch = append(branch, hashString(level[pos^1]))
+		next := make([][]byte, len(level)/2)
+		for i := range next {
+			next[i] = hashMerkleNode(level[2*i], level[2*i+1])
+		}
+		level = next
+		pos /= 2
+	}
+	return branch, nil
+}
+
+// hashMerkleNode returns the double SHA256 hash of the concatenated child
+// hashes of a merkle tree node.
+func hashMerkleNode(left, right []byte) []byte {
+	buf := make([]byte, 0, 2*sha256.Size)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	hash := sha256.Sum256(buf)
+	hash = sha256.Sum256(hash[:])
+	return hash[:]
+}
+
+// hashString returns the hex encoding of a hash in reversed byte order, like a
+// transaction or block hash.
+func hashString(hash []byte) string {
+	reversed := make([]byte, len(hash))
+	copy(reversed, hash)
+	reverseBytes(reversed)
+	return hex.EncodeToString(reversed)
+}
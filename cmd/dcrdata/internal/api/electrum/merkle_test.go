@@ -0,0 +1,98 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// merkleRootFromBranch computes the merkle root of a transaction from its
+// merkle branch, as an Electrum client does.
+func merkleRootFromBranch(t *testing.T, txid string, branch []string, pos int) string {
+	t.Helper()
+	hash, err := hex.DecodeString(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reverseBytes(hash)
+	for _, h := range branch {
+		sibling, err := hex.DecodeString(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reverseBytes(sibling)
+		if pos&1 == 0 {
+			hash = hashMerkleNode(hash, sibling)
+		} else {
+			hash = hashMerkleNode(sibling, hash)
+		}
+		pos >>= 1
+	}
+	return hashString(hash)
+}
+
+func Test_merkleBranch(t *testing.T) {
+	tests := []struct {
+		name       string
+		txids      []string
+		merkleRoot string
+	}{
+		{
+			name: "block 170",
+			txids: []string{
+				"b1fea52486ce0c62bb442b530a3f0132b826c74e473d1f2c220bfa78111c5082",
+				"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+			},
+			merkleRoot: "7dac2c5666815c17a3b36427de37bb9d2e2c5ccec3f8633eb91a4205cb4c10ff",
+		},
+		{
+			name: "block 100000",
+			txids: []string{
+				"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+				"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+				"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+				"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+			},
+			merkleRoot: "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+		},
+		{
+			name: "odd number of transactions",
+			txids: []string{
+				"8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
+				"fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
+				"6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+				"e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+				"b1fea52486ce0c62bb442b530a3f0132b826c74e473d1f2c220bfa78111c5082",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root string
+			for pos, txid := range tt.txids {
+				branch, err := merkleBranch(tt.txids, pos)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := merkleRootFromBranch(t, txid, branch, pos)
+				if root == "" {
+					root = got
+				} else if got != root {
+					t.Errorf("branch of tx %d gives root %s, tx 0 gives %s", pos, got, root)
+				}
+			}
+			if tt.merkleRoot != "" && root != tt.merkleRoot {
+				t.Errorf("got merkle root %s, want %s", root, tt.merkleRoot)
+			}
+		})
+	}
+
+	if _, err := merkleBranch([]string{"00"}, 0); err == nil {
+		t.Error("expected an error for an invalid transaction hash")
+	}
+	if _, err := merkleBranch(nil, 0); err == nil {
+		t.Error("expected an error for an out of range position")
+	}
+}
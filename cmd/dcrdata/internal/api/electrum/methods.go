@@ -0,0 +1,555 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// method is an Electrum protocol method with the names of its parameters, of
+// which the first required are mandatory.
+type method struct {
+	params   []string
+	required int
+	handler  func(sess *session, args []json.RawMessage) (interface{}, error)
+}
+
+// methods are the supported Electrum protocol methods by name. It is set in
+// init to avoid an initialization cycle through session.call.
+var methods map[string]*method
+
+func init() {
+	methods = map[string]*method{
+		"server.version":            {[]string{"client_name", "protocol_version"}, 0, handleServerVersion},
+		"server.banner":             {nil, 0, handleServerBanner},
+		"server.donation_address":   {nil, 0, handleServerDonationAddress},
+		"server.features":           {nil, 0, handleServerFeatures},
+		"server.peers.subscribe":    {nil, 0, handleServerPeersSubscribe},
+		"server.ping":               {nil, 0, handleServerPing},
+		"mempool.get_fee_histogram": {nil, 0, handleMempoolFeeHistogram},
+
+		"blockchain.headers.subscribe": {nil, 0, handleHeadersSubscribe},
+		"blockchain.block.header":      {[]string{"height", "cp_height"}, 1, handleBlockHeader},
+		"blockchain.block.headers":     {[]string{"start_height", "count", "cp_height"}, 2, handleBlockHeaders},
+		"blockchain.estimatefee":       {[]string{"number"}, 1, handleEstimateFee},
+		"blockchain.relayfee":          {nil, 0, handleRelayFee},
+
+		"blockchain.scripthash.get_balance": {[]string{"scripthash"}, 1, handleScripthashGetBalance},
+		"blockchain.scripthash.get_history": {[]string{"scripthash"}, 1, handleScripthashGetHistory},
+		"blockchain.scripthash.get_mempool": {[]string{"scripthash"}, 1, handleScripthashGetMempool},
+		"blockchain.scripthash.listunspent": {[]string{"scripthash"}, 1, handleScripthashListUnspent},
+		"blockchain.scripthash.subscribe":   {[]string{"scripthash"}, 1, handleScripthashSubscribe},
+		"blockchain.scripthash.unsubscribe": {[]string{"scripthash"}, 1, handleScripthashUnsubscribe},
+
+		"blockchain.transaction.broadcast":   {[]string{"raw_tx"}, 1, handleTransactionBroadcast},
+		"blockchain.transaction.get":         {[]string{"tx_hash", "verbose"}, 1, handleTransactionGet},
+		"blockchain.transaction.get_merkle":  {[]string{"tx_hash", "height"}, 2, handleTransactionGetMerkle},
+		"blockchain.transaction.id_from_pos": {[]string{"height", "tx_pos", "merkle"}, 2, handleTransactionIDFromPos},
+	}
+}
+
+// args returns the positional arguments of a request, which may be given as
+// an array or as an object keyed by the parameter names.
+func (m *method) args(raw json.RawMessage) ([]json.RawMessage, *rpcError) {
+	var args []json.RawMessage
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+	case raw[0] == '[':
+		if err := json.Unmarshal(raw, &args); err != nil {
+			return nil, newRPCError(errInvalidParams, "invalid params")
+		}
+	case raw[0] == '{':
+		var named map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &named); err != nil {
+			return nil, newRPCError(errInvalidParams, "invalid params")
+		}
+		for _, name := range m.params {
+			arg, ok := named[name]
+			if !ok {
+				break
+			}
+			args = append(args, arg)
+			delete(named, name)
+		}
+		if len(named) > 0 {
+			return nil, newRPCError(errInvalidParams, "unexpected or out of order params")
+		}
+	default:
+		return nil, newRPCError(errInvalidParams, "params must be an array or an object")
+	}
+	if len(args) < m.required || len(args) > len(m.params) {
+		return nil, newRPCError(errInvalidParams, "expected %d to %d params, got %d",
+			m.required, len(m.params), len(args))
+	}
+	return args, nil
+}
+
+// stringArg decodes the string argument at index i, or returns def if absent.
+func stringArg(args []json.RawMessage, i int, def string) (string, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	var s string
+	if err := json.Unmarshal(args[i], &s); err != nil {
+		return "", newRPCError(errInvalidParams, "param %d must be a string", i)
+	}
+	return s, nil
+}
+
+// intArg decodes the non-negative integer argument at index i, or returns def
+// if absent.
+func intArg(args []json.RawMessage, i int, def int64) (int64, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	var n int64
+	if err := json.Unmarshal(args[i], &n); err != nil || n < 0 {
+		return 0, newRPCError(errInvalidParams, "param %d must be a non-negative integer", i)
+	}
+	return n, nil
+}
+
+// boolArg decodes the boolean argument at index i, or returns def if absent.
+func boolArg(args []json.RawMessage, i int, def bool) (bool, error) {
+	if i >= len(args) {
+		return def, nil
+	}
+	var b bool
+	if err := json.Unmarshal(args[i], &b); err != nil {
+		return false, newRPCError(errInvalidParams, "param %d must be a boolean", i)
+	}
+	return b, nil
+}
+
+// txidArg decodes the transaction hash argument at index i.
+func txidArg(args []json.RawMessage, i int) (string, error) {
+	txid, err := stringArg(args, i, "")
+	if err != nil {
+		return "", err
+	}
+	if b, err := hex.DecodeString(txid); err != nil || len(b) != 32 {
+		return "", newRPCError(errBadRequest, "%q is not a valid transaction hash", txid)
+	}
+	return strings.ToLower(txid), nil
+}
+
+// compareVersions compares two dotted version strings numerically.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func handleServerVersion(sess *session, args []json.RawMessage) (interface{}, error) {
+	// The protocol version is either a single version or a [min, max] range.
+	protoMin, protoMax := ProtocolVersion, ProtocolVersion
+	if len(args) > 1 {
+		var versions []string
+		if err := json.Unmarshal(args[1], &versions); err == nil && len(versions) == 2 {
+			protoMin, protoMax = versions[0], versions[1]
+		} else if v, err := stringArg(args, 1, ""); err == nil {
+			protoMin, protoMax = v, v
+		} else {
+			return nil, err
+		}
+	}
+	if compareVersions(protoMin, ProtocolVersion) > 0 || compareVersions(ProtocolVersion, protoMax) > 0 {
+		return nil, newRPCError(errBadRequest, "unsupported protocol version: %s to %s",
+			protoMin, protoMax)
+	}
+	return []string{sess.srv.version, ProtocolVersion}, nil
+}
+
+func handleServerBanner(sess *session, _ []json.RawMessage) (interface{}, error) {
+	return "Welcome to the dcrdata " + strings.ToUpper(sess.srv.chainType) + " Electrum server", nil
+}
+
+func handleServerDonationAddress(*session, []json.RawMessage) (interface{}, error) {
+	return "", nil
+}
+
+// serverFeatures is the result of server.features.
+type serverFeatures struct {
+	GenesisHash   string                 `json:"genesis_hash"`
+	Hosts         map[string]interface{} `json:"hosts"`
+	ProtocolMax   string                 `json:"protocol_max"`
+	ProtocolMin   string                 `json:"protocol_min"`
+	Pruning       *int64                 `json:"pruning"`
+	ServerVersion string                 `json:"server_version"`
+	HashFunction  string                 `json:"hash_function"`
+}
+
+func handleServerFeatures(sess *session, _ []json.RawMessage) (interface{}, error) {
+	genesisHash, err := sess.srv.src.MutilchainBlockHashAtHeight(0, sess.srv.chainType)
+	if err != nil {
+		return nil, err
+	}
+	return &serverFeatures{
+		GenesisHash:   genesisHash,
+		Hosts:         map[string]interface{}{},
+		ProtocolMax:   ProtocolVersion,
+		ProtocolMin:   ProtocolVersion,
+		ServerVersion: sess.srv.version,
+		HashFunction:  "sha256",
+	}, nil
+}
+
+// handleServerPeersSubscribe returns no peers, as the server does not take
+// part in peer discovery.
+func handleServerPeersSubscribe(*session, []json.RawMessage) (interface{}, error) {
+	return []interface{}{}, nil
+}
+
+func handleServerPing(*session, []json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+// handleMempoolFeeHistogram returns an empty fee histogram, as the mempool
+// monitors do not track the fee rates of the mempool transactions.
+func handleMempoolFeeHistogram(*session, []json.RawMessage) (interface{}, error) {
+	return [][2]float64{}, nil
+}
+
+// headerResult is a block header with its height.
+type headerResult struct {
+	Height int64  `json:"height"`
+	Hex    string `json:"hex"`
+}
+
+func handleHeadersSubscribe(sess *session, _ []json.RawMessage) (interface{}, error) {
+	height, hash, err := sess.srv.src.MutilchainBestBlock(sess.srv.chainType)
+	if err != nil {
+		return nil, err
+	}
+	headerHex, err := sess.srv.src.MutilchainBlockHeaderHex(hash, sess.srv.chainType)
+	if err != nil {
+		return nil, err
+	}
+	sess.setHeadersSubscribed()
+	return &headerResult{Height: height, Hex: headerHex}, nil
+}
+
+// headerAtHeight returns the hex encoded header of the main chain block at the
+// specified height.
+func (s *Server) headerAtHeight(height int64) (string, error) {
+	hash, err := s.src.MutilchainBlockHashAtHeight(height, s.chainType)
+	if err != nil {
+		return "", newRPCError(errBadRequest, "height %d out of range", height)
+	}
+	return s.src.MutilchainBlockHeaderHex(hash, s.chainType)
+}
+
+func handleBlockHeader(sess *session, args []json.RawMessage) (interface{}, error) {
+	height, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	cpHeight, err := intArg(args, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if cpHeight != 0 {
+		return nil, newRPCError(errBadRequest, "checkpoint proofs are not supported")
+	}
+	return sess.srv.headerAtHeight(height)
+}
+
+// headersResult is the result of blockchain.block.headers.
+type headersResult struct {
+	Count int64  `json:"count"`
+	Hex   string `json:"hex"`
+	Max   int64  `json:"max"`
+}
+
+func handleBlockHeaders(sess *session, args []json.RawMessage) (interface{}, error) {
+	start, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	count, err := intArg(args, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	cpHeight, err := intArg(args, 2, 0)
+	if err != nil {
+		return nil, err
+	}
+	if cpHeight != 0 {
+		return nil, newRPCError(errBadRequest, "checkpoint proofs are not supported")
+	}
+	tipHeight, _, err := sess.srv.src.MutilchainBestBlock(sess.srv.chainType)
+	if err != nil {
+		return nil, err
+	}
+	if count > maxHeaders {
+		count = maxHeaders
+	}
+	if start+count > tipHeight+1 {
+		count = tipHeight + 1 - start
+	}
+
+	var headers strings.Builder
+	res := &headersResult{Max: maxHeaders}
+	for height := start; height < start+count; height++ {
+		headerHex, err := sess.srv.headerAtHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		headers.WriteString(headerHex)
+		res.Count++
+	}
+	res.Hex = headers.String()
+	return res, nil
+}
+
+// handleEstimateFee returns the estimated fee rate in coins per kilobyte, or
+// -1 if the node cannot estimate it.
+func handleEstimateFee(sess *session, args []json.RawMessage) (interface{}, error) {
+	target, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if target < 1 {
+		target = 1
+	}
+	feeRate, err := sess.srv.src.MutilchainEstimateFee(target, sess.srv.chainType)
+	if err != nil || feeRate <= 0 {
+		return -1, nil
+	}
+	return feeRate, nil
+}
+
+func handleRelayFee(sess *session, _ []json.RawMessage) (interface{}, error) {
+	info, _, err := sess.srv.src.MutilchainInsightStatus(sess.srv.chainType)
+	if err != nil {
+		return nil, err
+	}
+	return info.Relayfee, nil
+}
+
+func handleScripthashGetBalance(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := sess.scripthashAddress(scripthash)
+	if err != nil {
+		return nil, err
+	}
+	return sess.srv.balance(address)
+}
+
+func handleScripthashGetHistory(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := sess.scripthashAddress(scripthash)
+	if err != nil {
+		return nil, err
+	}
+	return sess.srv.history(address)
+}
+
+func handleScripthashGetMempool(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := sess.scripthashAddress(scripthash)
+	if err != nil {
+		return nil, err
+	}
+	return sess.srv.mempoolHistory(address, nil)
+}
+
+func handleScripthashListUnspent(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := sess.scripthashAddress(scripthash)
+	if err != nil {
+		return nil, err
+	}
+	return sess.srv.listUnspent(address)
+}
+
+// handleScripthashSubscribe subscribes the session to the status changes of a
+// scripthash, returning its current status.
+func handleScripthashSubscribe(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	address, err := sess.scripthashAddress(scripthash)
+	if err != nil {
+		return nil, err
+	}
+	status, err := sess.srv.status(address)
+	if err != nil {
+		return nil, err
+	}
+	if err = sess.subscribe(scripthash, address, status); err != nil {
+		return nil, err
+	}
+	return statusResult(status), nil
+}
+
+func handleScripthashUnsubscribe(sess *session, args []json.RawMessage) (interface{}, error) {
+	scripthash, err := scripthashArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return sess.unsubscribe(scripthash), nil
+}
+
+func handleTransactionBroadcast(sess *session, args []json.RawMessage) (interface{}, error) {
+	rawTx, err := stringArg(args, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = hex.DecodeString(rawTx); err != nil || rawTx == "" {
+		return nil, newRPCError(errBadRequest, "raw_tx must be a hex encoded transaction")
+	}
+	txid, err := sess.srv.src.MutilchainSendRawTransaction(rawTx, sess.srv.chainType)
+	if err != nil {
+		return nil, newRPCError(errBadRequest,
+			"the transaction was rejected by network rules.\n\n%v", err)
+	}
+	return txid, nil
+}
+
+func handleTransactionGet(sess *session, args []json.RawMessage) (interface{}, error) {
+	txid, err := txidArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	verbose, err := boolArg(args, 1, false)
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		tx, err := sess.srv.src.MutilchainRawTransactionVerbose(txid, sess.srv.chainType)
+		if err != nil {
+			return nil, newRPCError(errDaemon, "no such mempool or blockchain transaction %s", txid)
+		}
+		return tx, nil
+	}
+	txHex := sess.srv.src.GetMultichainTransactionHex(txid, sess.srv.chainType)
+	if txHex == "" {
+		return nil, newRPCError(errDaemon, "no such mempool or blockchain transaction %s", txid)
+	}
+	return txHex, nil
+}
+
+// blockTxids returns the hashes of the transactions of the main chain block
+// at the specified height.
+func (s *Server) blockTxids(height int64) ([]string, error) {
+	hash, err := s.src.MutilchainBlockHashAtHeight(height, s.chainType)
+	if err != nil {
+		return nil, newRPCError(errBadRequest, "height %d out of range", height)
+	}
+	return s.src.MutilchainBlockTxids(hash, s.chainType)
+}
+
+// merkleResult is the result of blockchain.transaction.get_merkle.
+type merkleResult struct {
+	BlockHeight int64    `json:"block_height"`
+	Merkle      []string `json:"merkle"`
+	Pos         int      `json:"pos"`
+}
+
+func handleTransactionGetMerkle(sess *session, args []json.RawMessage) (interface{}, error) {
+	txid, err := txidArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	height, err := intArg(args, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	txids, err := sess.srv.blockTxids(height)
+	if err != nil {
+		return nil, err
+	}
+	pos := -1
+	for i := range txids {
+		if txids[i] == txid {
+			pos = i
+			break
+		}
+	}
+	if pos < 0 {
+		return nil, newRPCError(errBadRequest, "tx %s not in block at height %d", txid, height)
+	}
+	branch, err := merkleBranch(txids, pos)
+	if err != nil {
+		return nil, err
+	}
+	return &merkleResult{
+		BlockHeight: height,
+		Merkle:      branch,
+		Pos:         pos,
+	}, nil
+}
+
+// idFromPosResult is the result of blockchain.transaction.id_from_pos when
+// the merkle branch is requested.
+type idFromPosResult struct {
+	TxHash string   `json:"tx_hash"`
+	Merkle []string `json:"merkle"`
+}
+
+func handleTransactionIDFromPos(sess *session, args []json.RawMessage) (interface{}, error) {
+	height, err := intArg(args, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := intArg(args, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	withMerkle, err := boolArg(args, 2, false)
+	if err != nil {
+		return nil, err
+	}
+	txids, err := sess.srv.blockTxids(height)
+	if err != nil {
+		return nil, err
+	}
+	if pos >= int64(len(txids)) {
+		return nil, newRPCError(errBadRequest, "no tx at position %d in block at height %d", pos, height)
+	}
+	if !withMerkle {
+		return txids[pos], nil
+	}
+	branch, err := merkleBranch(txids, int(pos))
+	if err != nil {
+		return nil, err
+	}
+	return &idFromPosResult{
+		TxHash: txids[pos],
+		Merkle: branch,
+	}, nil
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcjson"
+	ltcjson "github.com/ltcsuite/ltcd/btcjson"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// touchedScripts are the scripthashes paid by the outputs of new transactions,
+// with the addresses of the outputs, and the addresses of the previous outputs
+// they spend.
+type touchedScripts struct {
+	scripthashes map[string]string
+	addresses    map[string]bool
+}
+
+// txPrevOut is a previous outpoint spent by a transaction input.
+type txPrevOut struct {
+	txid string
+	vout uint32
+}
+
+// txOutput is the hex encoded pkScript of a transaction output with the
+// addresses it pays.
+type txOutput struct {
+	pkScript  string
+	addresses []string
+}
+
+// BTCBlockHandler notifies the sessions of a new BTC block. This method
+// satisfies notification.BtcBlockHandler.
+func (s *Server) BTCBlockHandler(block *mutilchain.BtcBlockHeader) error {
+	return s.blockConnected(int64(block.Height), block.Hash.String())
+}
+
+// LTCBlockHandler notifies the sessions of a new LTC block. This method
+// satisfies notification.LtcBlockHandler.
+func (s *Server) LTCBlockHandler(block *mutilchain.LtcBlockHeader) error {
+	return s.blockConnected(int64(block.Height), block.Hash.String())
+}
+
+// blockConnected sends the new header to the sessions subscribed to headers,
+// and queues the block for the update of the statuses of the scripthashes
+// funded or spent by its transactions.
+func (s *Server) blockConnected(height int64, hash string) error {
+	sessions := s.sessionList()
+	if len(sessions) == 0 {
+		return nil
+	}
+	go func() {
+		headerHex, err := s.src.MutilchainBlockHeaderHex(hash, s.chainType)
+		if err != nil {
+			log.Errorf("Unable to get the header of %s block %s: %v", s.chainType, hash, err)
+			return
+		}
+		header := &headerResult{Height: height, Hex: headerHex}
+		for _, sess := range sessions {
+			if sess.isHeadersSubscribed() {
+				sess.notify("blockchain.headers.subscribe", header)
+			}
+		}
+	}()
+
+	s.queueMtx.Lock()
+	s.queuedBlocks = append(s.queuedBlocks, hash)
+	s.queueMtx.Unlock()
+	s.wakeRefresh()
+	return nil
+}
+
+// BTCTxHandler updates the statuses of the subscribed scripthashes touched by
+// a new BTC mempool transaction. This method satisfies
+// notification.BtcTxHandler.
+func (s *Server) BTCTxHandler(rawTx *btcjson.TxRawResult) error {
+	prevOuts, outputs := btcTxScripts(rawTx)
+	return s.txAccepted(prevOuts, outputs)
+}
+
+// LTCTxHandler updates the statuses of the subscribed scripthashes touched by
+// a new LTC mempool transaction. This method satisfies
+// notification.LtcTxHandler.
+func (s *Server) LTCTxHandler(rawTx *ltcjson.TxRawResult) error {
+	prevOuts := make([]txPrevOut, 0, len(rawTx.Vin))
+	for i := range rawTx.Vin {
+		if !rawTx.Vin[i].IsCoinBase() {
+			prevOuts = append(prevOuts, txPrevOut{rawTx.Vin[i].Txid, rawTx.Vin[i].Vout})
+		}
+	}
+	outputs := make([]txOutput, 0, len(rawTx.Vout))
+	for i := range rawTx.Vout {
+		spk := &rawTx.Vout[i].ScriptPubKey
+		addrs := spk.Addresses
+		if len(addrs) == 0 && spk.Address != "" {
+			addrs = []string{spk.Address}
+		}
+		outputs = append(outputs, txOutput{spk.Hex, addrs})
+	}
+	return s.txAccepted(prevOuts, outputs)
+}
+
+// btcTxScripts returns the previous outpoints spent by a BTC transaction, or
+// an LTC transaction converted to the btcjson type, and its outputs.
+func btcTxScripts(rawTx *btcjson.TxRawResult) ([]txPrevOut, []txOutput) {
+	prevOuts := make([]txPrevOut, 0, len(rawTx.Vin))
+	for i := range rawTx.Vin {
+		if !rawTx.Vin[i].IsCoinBase() {
+			prevOuts = append(prevOuts, txPrevOut{rawTx.Vin[i].Txid, rawTx.Vin[i].Vout})
+		}
+	}
+	outputs := make([]txOutput, 0, len(rawTx.Vout))
+	for i := range rawTx.Vout {
+		spk := &rawTx.Vout[i].ScriptPubKey
+		addrs := spk.Addresses
+		if len(addrs) == 0 && spk.Address != "" {
+			addrs = []string{spk.Address}
+		}
+		outputs = append(outputs, txOutput{spk.Hex, addrs})
+	}
+	return prevOuts, outputs
+}
+
+// txAccepted queues a mempool transaction for the update of the statuses of
+// the subscriptions it touches. The transactions are dropped while there are
+// no sessions or maxQueuedTxs are already waiting, in which case the statuses
+// are updated when the transactions are mined.
+func (s *Server) txAccepted(prevOuts []txPrevOut, outputs []txOutput) error {
+	if len(s.sessionList()) == 0 {
+		return nil
+	}
+	s.queueMtx.Lock()
+	if len(s.queuedTxs) >= maxQueuedTxs {
+		s.queueMtx.Unlock()
+		log.Debugf("%s Electrum refresh queue full, dropping a mempool transaction", s.chainType)
+		return nil
+	}
+	s.queuedTxs = append(s.queuedTxs, queuedTx{prevOuts, outputs})
+	s.queueMtx.Unlock()
+	s.wakeRefresh()
+	return nil
+}
+
+// queuedTx is a mempool transaction waiting for the refresh worker.
+type queuedTx struct {
+	prevOuts []txPrevOut
+	outputs  []txOutput
+}
+
+// wakeRefresh signals the refresh worker without blocking. A pending signal
+// already covers the newly queued blocks and transactions.
+func (s *Server) wakeRefresh() {
+	select {
+	case s.refreshQueued <- struct{}{}:
+	default:
+	}
+}
+
+// refreshWorker updates the subscriptions touched by the queued blocks and
+// transactions until the context is canceled. All that was queued since the
+// last update is handled at once, so a burst of transactions costs one pass
+// over the sessions.
+func (s *Server) refreshWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.refreshQueued:
+		}
+
+		s.queueMtx.Lock()
+		blocks, txs := s.queuedBlocks, s.queuedTxs
+		s.queuedBlocks, s.queuedTxs = nil, nil
+		s.queueMtx.Unlock()
+
+		touched := s.touchedBy(blocks, txs)
+		if len(touched.scripthashes) > 0 || len(touched.addresses) > 0 {
+			s.refreshSubscriptions(touched)
+		}
+	}
+}
+
+// touchedBy returns the scripthashes paid and the addresses spent by the
+// transactions of the blocks and the mempool transactions. The addresses of
+// the previous outputs are looked up in one query, and only those of the
+// mempool transactions that are not stored, such as unconfirmed parents, are
+// looked up one at a time.
+func (s *Server) touchedBy(blocks []string, txs []queuedTx) *touchedScripts {
+	touched := &touchedScripts{
+		scripthashes: make(map[string]string),
+		addresses:    make(map[string]bool),
+	}
+	var blockPrevOuts, mempoolPrevOuts []txPrevOut
+	addOutputs := func(outputs []txOutput) {
+		for _, out := range outputs {
+			scripthash, err := pkScriptScripthash(out.pkScript)
+			if err != nil {
+				continue
+			}
+			var address string
+			if len(out.addresses) > 0 {
+				address = out.addresses[0]
+			}
+			touched.scripthashes[scripthash] = address
+		}
+	}
+	for _, hash := range blocks {
+		rawTxs, err := s.src.MutilchainBlockRawTxs(hash, s.chainType)
+		if err != nil {
+			log.Errorf("Unable to get the transactions of %s block %s: %v", s.chainType, hash, err)
+			continue
+		}
+		for i := range rawTxs {
+			prevOuts, outputs := btcTxScripts(&rawTxs[i])
+			blockPrevOuts = append(blockPrevOuts, prevOuts...)
+			addOutputs(outputs)
+		}
+	}
+	for _, tx := range txs {
+		mempoolPrevOuts = append(mempoolPrevOuts, tx.prevOuts...)
+		addOutputs(tx.outputs)
+	}
+
+	prevOuts := append(blockPrevOuts, mempoolPrevOuts...)
+	if len(prevOuts) == 0 {
+		return touched
+	}
+	txids := make([]string, len(prevOuts))
+	vouts := make([]uint32, len(prevOuts))
+	for i, prevOut := range prevOuts {
+		txids[i], vouts[i] = prevOut.txid, prevOut.vout
+	}
+	prevAddrs, err := s.src.MutilchainOutPointsAddresses(txids, vouts, s.chainType)
+	if err != nil {
+		log.Errorf("Unable to get the addresses of %d %s outpoints: %v", len(prevOuts), s.chainType, err)
+		prevAddrs = make(map[string][]string)
+	}
+	for i, prevOut := range prevOuts {
+		addrs, found := prevAddrs[prevOut.txid+":"+strconv.FormatUint(uint64(prevOut.vout), 10)]
+		// The outputs spent within a block are among its outputs.
+		if !found && i >= len(blockPrevOuts) {
+			addrs, _, err = s.src.MutilchainOutPointAddresses(prevOut.txid, prevOut.vout, s.chainType)
+			if err != nil {
+				log.Warnf("Unable to get the addresses of %s outpoint %s:%d: %v",
+					s.chainType, prevOut.txid, prevOut.vout, err)
+				continue
+			}
+		}
+		for _, addr := range addrs {
+			touched.addresses[addr] = true
+		}
+	}
+	return touched
+}
+
+// refreshSubscriptions recomputes the statuses of the subscribed scripthashes
+// touched by new transactions, and notifies the sessions of the changed ones. The status of a scripthash is computed once
+// for all sessions.
+func (s *Server) refreshSubscriptions(touched *touchedScripts) {
+	s.refreshMtx.Lock()
+	defer s.refreshMtx.Unlock()
+
+	type subscribed struct {
+		scripthash, address string
+	}
+	statuses := make(map[string]string)
+	addresses := make(map[string]string)
+	for _, sess := range s.sessionList() {
+		sess.mtx.Lock()
+		subs := make([]subscribed, 0, len(sess.subs))
+		for scripthash, sub := range sess.subs {
+			address := sub.address
+			outAddress, paid := touched.scripthashes[scripthash]
+			if address == "" {
+				address = outAddress
+			}
+			if !paid && (address == "" || !touched.addresses[address]) {
+				continue
+			}
+			subs = append(subs, subscribed{scripthash, address})
+		}
+		sess.mtx.Unlock()
+
+		for _, sub := range subs {
+			// Scripthashes without a known address may have been paid by
+			// newly stored outputs.
+			address := sub.address
+			if address == "" {
+				var ok bool
+				if address, ok = addresses[sub.scripthash]; !ok {
+					var err error
+					address, err = s.scripthashAddress(sub.scripthash)
+					if err != nil {
+						log.Errorf("Unable to look up %s scripthash %s: %v", s.chainType, sub.scripthash, err)
+						continue
+					}
+					addresses[sub.scripthash] = address
+				}
+			}
+			status, ok := statuses[sub.scripthash]
+			if !ok {
+				var err error
+				status, err = s.status(address)
+				if err != nil {
+					log.Errorf("Unable to get the status of %s scripthash %s: %v", s.chainType, sub.scripthash, err)
+					continue
+				}
+				statuses[sub.scripthash] = status
+			}
+			if sess.updateSubscription(sub.scripthash, address, status) {
+				sess.notify("blockchain.scripthash.subscribe", sub.scripthash, statusResult(status))
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+)
+
+// stubSource serves the block transactions and stored outpoints of the
+// touched scripts lookups, counting the lookups.
+type stubSource struct {
+	DataSource
+	blockTxs      map[string][]btcjson.TxRawResult
+	stored        map[string][]string
+	node          map[string][]string
+	batchLookups  int
+	singleLookups int
+}
+
+func (src *stubSource) MutilchainBlockRawTxs(hash, _ string) ([]btcjson.TxRawResult, error) {
+	return src.blockTxs[hash], nil
+}
+
+func (src *stubSource) MutilchainOutPointsAddresses(txids []string, vouts []uint32, _ string) (map[string][]string, error) {
+	src.batchLookups++
+	addrs := make(map[string][]string)
+	for i := range txids {
+		outpoint := fmt.Sprintf("%s:%d", txids[i], vouts[i])
+		if a, ok := src.stored[outpoint]; ok {
+			addrs[outpoint] = a
+		}
+	}
+	return addrs, nil
+}
+
+func (src *stubSource) MutilchainOutPointAddresses(txid string, vout uint32, _ string) ([]string, int64, error) {
+	src.singleLookups++
+	return src.node[fmt.Sprintf("%s:%d", txid, vout)], 0, nil
+}
+
+func TestTouchedBy(t *testing.T) {
+	src := &stubSource{
+		blockTxs: map[string][]btcjson.TxRawResult{
+			"block": {{
+				Vin: []btcjson.Vin{{Txid: "funding", Vout: 0}, {Txid: "sameblock", Vout: 1}},
+				Vout: []btcjson.Vout{{ScriptPubKey: btcjson.ScriptPubKeyResult{
+					Hex: "0014aa", Address: "paid"}}},
+			}},
+		},
+		stored: map[string][]string{"funding:0": {"spent"}},
+		node:   map[string][]string{"parent:2": {"unconfirmed"}},
+	}
+	s := NewServer("btc", src, "test")
+
+	touched := s.touchedBy([]string{"block"}, []queuedTx{{
+		prevOuts: []txPrevOut{{"funding", 0}, {"parent", 2}},
+		outputs:  []txOutput{{pkScript: "0014bb", addresses: []string{"mempool"}}},
+	}})
+
+	for pkScript, address := range map[string]string{"0014aa": "paid", "0014bb": "mempool"} {
+		scripthash, _ := pkScriptScripthash(pkScript)
+		if got, ok := touched.scripthashes[scripthash]; !ok || got != address {
+			t.Errorf("scripthash of %s: got %q, %v, expected %q", pkScript, got, ok, address)
+		}
+	}
+	for _, address := range []string{"spent", "unconfirmed"} {
+		if !touched.addresses[address] {
+			t.Errorf("address %s not touched", address)
+		}
+	}
+	if len(touched.addresses) != 2 {
+		t.Errorf("expected 2 touched addresses, got %v", touched.addresses)
+	}
+	// One query for all the previous outputs, and a node lookup only for the
+	// unstored previous output of the mempool transaction.
+	if src.batchLookups != 1 || src.singleLookups != 1 {
+		t.Errorf("expected 1 batch and 1 single lookup, got %d and %d",
+			src.batchLookups, src.singleLookups)
+	}
+}
+
+func TestTxAcceptedQueueBound(t *testing.T) {
+	s := NewServer("btc", &stubSource{}, "test")
+	s.addSession(&session{})
+	for i := 0; i < maxQueuedTxs+10; i++ {
+		if err := s.txAccepted(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.queuedTxs) != maxQueuedTxs {
+		t.Errorf("expected %d queued transactions, got %d", maxQueuedTxs, len(s.queuedTxs))
+	}
+
+	// The worker takes the whole queue at once.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.refreshWorker(ctx)
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+		s.queueMtx.Lock()
+		n := len(s.queuedTxs)
+		s.queueMtx.Unlock()
+		if n == 0 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Error("the queued transactions were not taken by the worker")
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// An Electrum scripthash is the SHA256 hash of a pkScript, hex encoded in
+// reversed byte order. The vouts_all table of the whole chain is indexed on
+// the hash in natural byte order.
+
+// scripthashArg decodes the scripthash argument at index i, returning it as
+// lowercase hex.
+func scripthashArg(args []json.RawMessage, i int) (string, error) {
+	scripthash, err := stringArg(args, i, "")
+	if err != nil {
+		return "", err
+	}
+	if b, err := hex.DecodeString(scripthash); err != nil || len(b) != sha256.Size {
+		return "", newRPCError(errBadRequest, "%q is not a valid script hash", scripthash)
+	}
+	return strings.ToLower(scripthash), nil
+}
+
+// pkScriptScripthash returns the Electrum scripthash of a hex encoded pkScript.
+func pkScriptScripthash(pkScriptHex string) (string, error) {
+	pkScript, err := hex.DecodeString(pkScriptHex)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(pkScript)
+	reverseBytes(hash[:])
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func reverseBytes(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// scripthashAddress returns the address paid by the pkScript of a scripthash,
+// or an empty string if no stored output pays to the pkScript.
+func (s *Server) scripthashAddress(scripthash string) (string, error) {
+	hash, err := hex.DecodeString(scripthash)
+	if err != nil {
+		return "", err
+	}
+	reverseBytes(hash)
+	return s.src.MutilchainScripthashAddress(hash, s.chainType)
+}
+
+// scripthashAddress returns the address of a scripthash, preferring the one of
+// the session's subscription, which may have been learned from a mempool
+// transaction before any output paying to the pkScript was stored.
+func (sess *session) scripthashAddress(scripthash string) (string, error) {
+	if address := sess.subscribedAddress(scripthash); address != "" {
+		return address, nil
+	}
+	return sess.srv.scripthashAddress(scripthash)
+}
+
+// historyItem is a transaction in the history of a scripthash. The height of
+// mempool transactions is 0, or -1 if they spend unconfirmed outputs, and
+// only those have a fee.
+type historyItem struct {
+	TxHash string `json:"tx_hash"`
+	Height int64  `json:"height"`
+	Fee    *int64 `json:"fee,omitempty"`
+}
+
+// history returns the confirmed transactions of an address in block order,
+// followed by its mempool transactions.
+func (s *Server) history(address string) ([]historyItem, error) {
+	items := []historyItem{}
+	if address == "" {
+		return items, nil
+	}
+	txs, err := s.src.MutilchainAddressTxHeights(address, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	confirmed := make(map[string]bool, len(txs))
+	for _, tx := range txs {
+		confirmed[tx.TxHash] = true
+		items = append(items, historyItem{
+			TxHash: tx.TxHash,
+			Height: tx.BlockHeight,
+		})
+	}
+	mempoolItems, err := s.mempoolHistory(address, confirmed)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, mempoolItems...), nil
+}
+
+// mempoolHistory returns the mempool transactions of an address that are not
+// in exclude, sorted by height and hash.
+func (s *Server) mempoolHistory(address string, exclude map[string]bool) ([]historyItem, error) {
+	items := []historyItem{}
+	if address == "" {
+		return items, nil
+	}
+	mp, err := s.src.MutilchainUnconfirmedTxnsForAddress(address, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	txids := make([]string, 0, len(mp.Txids))
+	for _, txid := range mp.Txids {
+		if !exclude[txid] {
+			txids = append(txids, txid)
+		}
+	}
+	if len(txids) == 0 {
+		return items, nil
+	}
+
+	mempoolTxids, err := s.src.MutilchainMempoolTxids(s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	inMempool := make(map[string]bool, len(mempoolTxids))
+	for _, txid := range mempoolTxids {
+		inMempool[txid] = true
+	}
+	txs, err := s.src.MutilchainEsploraTxs(txids, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		item := historyItem{
+			TxHash: tx.Txid,
+			Fee:    new(int64),
+		}
+		*item.Fee = tx.Fee
+		for i := range tx.Vin {
+			if inMempool[tx.Vin[i].Txid] {
+				item.Height = -1
+				break
+			}
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Height != items[j].Height {
+			return items[i].Height > items[j].Height
+		}
+		return items[i].TxHash < items[j].TxHash
+	})
+	return items, nil
+}
+
+// historyStatus returns the Electrum status of a history, which is the hex
+// encoded SHA256 hash of the concatenated "tx_hash:height:" strings of its
+// transactions, or an empty string if there are none.
+func historyStatus(items []historyItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for i := range items {
+		fmt.Fprintf(h, "%s:%d:", items[i].TxHash, items[i].Height)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// status returns the Electrum status of an address.
+func (s *Server) status(address string) (string, error) {
+	items, err := s.history(address)
+	if err != nil {
+		return "", err
+	}
+	return historyStatus(items), nil
+}
+
+// statusResult returns the JSON value of a status, which is null for
+// scripthashes without history.
+func statusResult(status string) interface{} {
+	if status == "" {
+		return nil
+	}
+	return status
+}
+
+// balanceResult is the result of blockchain.scripthash.get_balance, in
+// satoshis. The unconfirmed balance is the net change by mempool transactions
+// and may be negative.
+type balanceResult struct {
+	Confirmed   int64 `json:"confirmed"`
+	Unconfirmed int64 `json:"unconfirmed"`
+}
+
+func (s *Server) balance(address string) (*balanceResult, error) {
+	bal := new(balanceResult)
+	if address == "" {
+		return bal, nil
+	}
+	utxos, err := s.src.MutilchainAddressUTXO(address, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	for _, utxo := range utxos {
+		bal.Confirmed += utxo.Atoms
+	}
+	mp, err := s.src.MutilchainUnconfirmedTxnsForAddress(address, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	bal.Unconfirmed = mp.Received - mp.Sent
+	return bal, nil
+}
+
+// unspentResult is an unspent output in the result of
+// blockchain.scripthash.listunspent. The height of mempool outputs is 0.
+type unspentResult struct {
+	TxHash string `json:"tx_hash"`
+	TxPos  uint32 `json:"tx_pos"`
+	Height int64  `json:"height"`
+	Value  int64  `json:"value"`
+}
+
+// listUnspent returns the unspent outputs of an address, excluding those spent
+// by mempool transactions, with the confirmed ones first in block order.
+func (s *Server) listUnspent(address string) ([]unspentResult, error) {
+	unspent := []unspentResult{}
+	if address == "" {
+		return unspent, nil
+	}
+	utxos, err := s.src.MutilchainEsploraUTXOs(address, s.chainType)
+	if err != nil {
+		return nil, err
+	}
+	for i := range utxos {
+		var height int64
+		if utxos[i].Status.Confirmed {
+			height = utxos[i].Status.BlockHeight
+		}
+		unspent = append(unspent, unspentResult{
+			TxHash: utxos[i].Txid,
+			TxPos:  utxos[i].Vout,
+			Height: height,
+			Value:  utxos[i].Value,
+		})
+	}
+	sortHeight := func(height int64) int64 {
+		if height == 0 {
+			return math.MaxInt64
+		}
+		return height
+	}
+	sort.Slice(unspent, func(i, j int) bool {
+		hi, hj := sortHeight(unspent[i].Height), sortHeight(unspent[j].Height)
+		if hi != hj {
+			return hi < hj
+		}
+		if unspent[i].TxHash != unspent[j].TxHash {
+			return unspent[i].TxHash < unspent[j].TxHash
+		}
+		return unspent[i].TxPos < unspent[j].TxPos
+	})
+	return unspent, nil
+}
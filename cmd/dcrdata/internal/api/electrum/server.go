@@ -0,0 +1,221 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package electrum implements an Electrum protocol (ElectrumX 1.4) server for
+// the BTC and LTC chains, so that Electrum and Sparrow wallets can use the
+// explorer's address index.
+package electrum
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+const (
+	// ProtocolVersion is the version of the Electrum protocol implemented by
+	// the server.
+	ProtocolVersion = "1.4"
+	// maxRequestSize is the maximum size in bytes of a request line. It must
+	// fit a hex encoded transaction for broadcast.
+	maxRequestSize = 1 << 21
+	// maxSubscriptions is the maximum number of scripthash subscriptions of a
+	// session.
+	maxSubscriptions = 20000
+	// maxHeaders is the maximum number of block headers returned by a
+	// blockchain.block.headers request.
+	maxHeaders = 2016
+	// sessionTimeout is how long an idle session is kept open.
+	sessionTimeout = 10 * time.Minute
+	// writeTimeout is how long a write to a session may block.
+	writeTimeout = 30 * time.Second
+	// syncPollInterval is how often WaitWholeChainSynced checks the sync of
+	// the whole chain.
+	syncPollInterval = 10 * time.Second
+	// maxQueuedTxs is the maximum number of mempool transactions waiting for
+	// the update of the subscription statuses.
+	maxQueuedTxs = 10000
+)
+
+// DataSource is the interface for the multichain DB, mempool monitors and node
+// RPC clients that back the Electrum server of a BTC or LTC chain.
+type DataSource interface {
+	MutilchainScripthashAddress(scripthash []byte, chainType string) (string, error)
+	MutilchainWholeChainSynced(chainType string) bool
+	MutilchainAddressTxHeights(address, chainType string) ([]*dbtypes.MutilchainAddressTxHeight, error)
+	MutilchainAddressUTXO(address, chainType string) ([]*dbtypes.MutilchainAddressTxnOutput, error)
+	MutilchainUnconfirmedTxnsForAddress(address, chainType string) (*dbtypes.MutilchainMempoolAddress, error)
+	MutilchainEsploraUTXOs(address, chainType string) ([]apitypes.EsploraUTXO, error)
+	MutilchainEsploraTxs(txids []string, chainType string) ([]*apitypes.EsploraTx, error)
+	MutilchainMempoolTxids(chainType string) ([]string, error)
+	MutilchainOutPointAddresses(txid string, vout uint32, chainType string) ([]string, int64, error)
+	MutilchainOutPointsAddresses(txids []string, vouts []uint32, chainType string) (map[string][]string, error)
+	GetMultichainTransactionHex(txid, chainType string) string
+	MutilchainRawTransactionVerbose(txid, chainType string) (*btcjson.TxRawResult, error)
+	MutilchainSendRawTransaction(txhex, chainType string) (string, error)
+	MutilchainEstimateFee(nbBlocks int64, chainType string) (float64, error)
+	MutilchainInsightStatus(chainType string) (*apitypes.InsightStatusInfo, string, error)
+	MutilchainBestBlock(chainType string) (int64, string, error)
+	MutilchainBlockHashAtHeight(height int64, chainType string) (string, error)
+	MutilchainBlockHeaderHex(hash, chainType string) (string, error)
+	MutilchainBlockTxids(hash, chainType string) ([]string, error)
+	MutilchainBlockRawTxs(hash, chainType string) ([]btcjson.TxRawResult, error)
+}
+
+// Server is an Electrum protocol server of a BTC or LTC chain. Sessions are
+// served on the listeners passed to Serve, and are notified of new blocks and
+// of the transactions of their subscribed scripthashes by the chain's block
+// and tx handlers.
+type Server struct {
+	chainType string
+	src       DataSource
+	version   string
+
+	mtx      sync.RWMutex
+	sessions map[*session]struct{}
+
+	// refreshMtx serializes the updates of the subscription statuses.
+	refreshMtx sync.Mutex
+
+	// The blocks and mempool transactions waiting for the refresh worker,
+	// which is signaled on refreshQueued.
+	queueMtx      sync.Mutex
+	queuedBlocks  []string
+	queuedTxs     []queuedTx
+	refreshQueued chan struct{}
+	workerOnce    sync.Once
+}
+
+// NewServer is the constructor for Server. version is the server software
+// version reported to clients.
+func NewServer(chainType string, src DataSource, version string) *Server {
+	return &Server{
+		chainType: chainType,
+		src:       src,
+		version:   version,
+		sessions:  make(map[*session]struct{}),
+
+		refreshQueued: make(chan struct{}, 1),
+	}
+}
+
+// WaitWholeChainSynced blocks until the whole-chain tables of the chain are
+// synced to the best block, since the scripthashes are looked up in the
+// outputs of the whole chain, or until the context is canceled.
+func (s *Server) WaitWholeChainSynced(ctx context.Context) error {
+	if s.src.MutilchainWholeChainSynced(s.chainType) {
+		return nil
+	}
+	log.Infof("The %s Electrum server waits for the whole chain to be synced", s.chainType)
+	ticker := time.NewTicker(syncPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if s.src.MutilchainWholeChainSynced(s.chainType) {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Serve accepts connections on the listener until the context is canceled,
+// serving each in a new session. Serve closes the listener and the sessions
+// on return.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	s.workerOnce.Do(func() { go s.refreshWorker(ctx) })
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	log.Infof("Serving the %s Electrum protocol on %v", s.chainType, ln.Addr())
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn reads and handles the newline delimited JSON-RPC requests of a
+// session until the connection is closed or idle for too long.
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	sess := newSession(s, conn)
+	s.addSession(sess)
+	defer s.removeSession(sess)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	log.Debugf("New %s Electrum session from %s", s.chainType, conn.RemoteAddr())
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	for {
+		conn.SetReadDeadline(time.Now().Add(sessionTimeout))
+		if !scanner.Scan() {
+			break
+		}
+		resp := sess.handleMessage(scanner.Bytes())
+		if resp == nil {
+			continue
+		}
+		if err := sess.send(resp); err != nil {
+			log.Debugf("Failed to write to %s Electrum session %s: %v",
+				s.chainType, conn.RemoteAddr(), err)
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		log.Debugf("%s Electrum session %s closed: %v", s.chainType, conn.RemoteAddr(), err)
+	}
+}
+
+func (s *Server) addSession(sess *session) {
+	s.mtx.Lock()
+	s.sessions[sess] = struct{}{}
+	s.mtx.Unlock()
+}
+
+func (s *Server) removeSession(sess *session) {
+	s.mtx.Lock()
+	delete(s.sessions, sess)
+	s.mtx.Unlock()
+}
+
+// sessionList returns the current sessions.
+func (s *Server) sessionList() []*session {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	return sessions
+}
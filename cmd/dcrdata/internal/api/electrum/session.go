@@ -0,0 +1,275 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package electrum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// JSON-RPC and Electrum protocol error codes.
+const (
+	errParse          = -32700
+	errInvalidRequest = -32600
+	errMethodNotFound = -32601
+	errInvalidParams  = -32602
+	errBadRequest     = 1
+	errDaemon         = 2
+)
+
+// rpcError is the error object of a JSON-RPC response.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func newRPCError(code int, format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// request is a JSON-RPC request. Requests without an id are notifications,
+// which are not answered.
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// resultResponse is a successful JSON-RPC response. The result is always
+// present, even if null.
+type resultResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result"`
+}
+
+// errorResponse is a failed JSON-RPC response.
+type errorResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Error   *rpcError       `json:"error"`
+}
+
+// notification is a JSON-RPC notification sent to subscribed sessions.
+type notification struct {
+	JSONRPC string        `json:"jsonrpc"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// subscription is a scripthash subscription of a session, with the address
+// paid by the scripthash's pkScript, if known, and the last status sent.
+type subscription struct {
+	address string
+	status  string
+}
+
+// session is the connection of an Electrum client.
+type session struct {
+	srv  *Server
+	conn net.Conn
+
+	writeMtx sync.Mutex
+
+	mtx               sync.Mutex
+	headersSubscribed bool
+	subs              map[string]*subscription
+}
+
+func newSession(srv *Server, conn net.Conn) *session {
+	return &session{
+		srv:  srv,
+		conn: conn,
+		subs: make(map[string]*subscription),
+	}
+}
+
+// send writes a JSON-RPC message as a single line.
+func (sess *session) send(msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	sess.writeMtx.Lock()
+	defer sess.writeMtx.Unlock()
+	sess.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = sess.conn.Write(b)
+	return err
+}
+
+// notify sends a notification, closing the session if it cannot be written.
+func (sess *session) notify(method string, params ...interface{}) {
+	err := sess.send(&notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Debugf("Failed to notify %s Electrum session %s: %v",
+			sess.srv.chainType, sess.conn.RemoteAddr(), err)
+		sess.conn.Close()
+	}
+}
+
+// handleMessage handles a request or a batch of requests, returning the
+// response(s) to send, if any.
+func (sess *session) handleMessage(msg []byte) interface{} {
+	msg = bytes.TrimSpace(msg)
+	if len(msg) == 0 {
+		return nil
+	}
+	if msg[0] != '[' {
+		return sess.handleRequest(msg)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(msg, &batch); err != nil {
+		return newErrorResponse(nil, newRPCError(errParse, "invalid JSON"))
+	}
+	if len(batch) == 0 {
+		return newErrorResponse(nil, newRPCError(errInvalidRequest, "empty batch"))
+	}
+	resps := make([]interface{}, 0, len(batch))
+	for _, raw := range batch {
+		if resp := sess.handleRequest(raw); resp != nil {
+			resps = append(resps, resp)
+		}
+	}
+	if len(resps) == 0 {
+		return nil
+	}
+	return resps
+}
+
+// handleRequest handles a single JSON-RPC request.
+func (sess *session) handleRequest(raw []byte) interface{} {
+	var req request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return newErrorResponse(nil, newRPCError(errParse, "invalid JSON"))
+	}
+	if req.Method == "" {
+		return newErrorResponse(req.ID, newRPCError(errInvalidRequest, "missing method"))
+	}
+
+	result, err := sess.call(req.Method, req.Params)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if err != nil {
+		return newErrorResponse(req.ID, err)
+	}
+	return &resultResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// call runs the handler of a method. Errors that are not an *rpcError are
+// logged and reported to the client as daemon errors.
+func (sess *session) call(methodName string, rawParams json.RawMessage) (interface{}, *rpcError) {
+	m, ok := methods[methodName]
+	if !ok {
+		return nil, newRPCError(errMethodNotFound, "unknown method %q", methodName)
+	}
+	args, rpcErr := m.args(rawParams)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	result, err := m.handler(sess, args)
+	if err != nil {
+		if rpcErr, ok := err.(*rpcError); ok {
+			return nil, rpcErr
+		}
+		log.Errorf("%s Electrum %s failed: %v", sess.srv.chainType, methodName, err)
+		return nil, newRPCError(errDaemon, "internal error")
+	}
+	return result, nil
+}
+
+func newErrorResponse(id json.RawMessage, err *rpcError) *errorResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &errorResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   err,
+	}
+}
+
+// subscribe adds or updates a scripthash subscription.
+func (sess *session) subscribe(scripthash, address, status string) error {
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	if _, ok := sess.subs[scripthash]; !ok && len(sess.subs) >= maxSubscriptions {
+		return newRPCError(errBadRequest, "too many subscriptions")
+	}
+	sess.subs[scripthash] = &subscription{
+		address: address,
+		status:  status,
+	}
+	return nil
+}
+
+// unsubscribe removes a scripthash subscription, returning false if there was
+// no such subscription.
+func (sess *session) unsubscribe(scripthash string) bool {
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	_, ok := sess.subs[scripthash]
+	delete(sess.subs, scripthash)
+	return ok
+}
+
+// subscribedAddress returns the address of a subscribed scripthash, if known.
+func (sess *session) subscribedAddress(scripthash string) string {
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	if sub, ok := sess.subs[scripthash]; ok {
+		return sub.address
+	}
+	return ""
+}
+
+// updateSubscription sets the address and status of a subscribed scripthash,
+// returning true if the status changed.
+func (sess *session) updateSubscription(scripthash, address, status string) bool {
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	sub, ok := sess.subs[scripthash]
+	if !ok {
+		return false
+	}
+	if address != "" {
+		sub.address = address
+	}
+	if sub.status == status {
+		return false
+	}
+	sub.status = status
+	return true
+}
+
+func (sess *session) setHeadersSubscribed() {
+	sess.mtx.Lock()
+	sess.headersSubscribed = true
+	sess.mtx.Unlock()
+}
+
+func (sess *session) isHeadersSubscribed() bool {
+	sess.mtx.Lock()
+	defer sess.mtx.Unlock()
+	return sess.headersSubscribed
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package esplora
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type contextKey int
+
+const (
+	ctxTxid contextKey = iota
+	ctxBlockHash
+	ctxHeight
+	ctxAddress
+	ctxScripthash
+	ctxVout
+	ctxStartIndex
+	ctxStartHeight
+	ctxLastSeenTxid
+	ctxTxIndex
+)
+
+// hashPathCtx returns a middleware that embeds the hex encoded hash at the url
+// part {param} into the request context with the given key.
+func hashPathCtx(param string, key contextKey, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash := chi.URLParam(r, param)
+		if !isHash(hash) {
+			http.Error(w, "Invalid hex string", http.StatusBadRequest)
+			return
+		}
+		ctx := context.WithValue(r.Context(), key, hash)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// getHashCtx retrieves the hash with the given key from the request context.
+// If not set, the return value is an empty string.
+func getHashCtx(r *http.Request, key contextKey) string {
+	hash, _ := r.Context().Value(key).(string)
+	return hash
+}
+
+// TxidPathCtx is a middleware that embeds the transaction hash at the url part
+// {txid} into the request context.
+func TxidPathCtx(next http.Handler) http.Handler {
+	return hashPathCtx("txid", ctxTxid, next)
+}
+
+// BlockHashPathCtx is a middleware that embeds the block hash at the url part
+// {hash} into the request context.
+func BlockHashPathCtx(next http.Handler) http.Handler {
+	return hashPathCtx("hash", ctxBlockHash, next)
+}
+
+// LastSeenTxidPathCtx is a middleware that embeds the transaction hash at the
+// url part {last_seen_txid} into the request context.
+func LastSeenTxidPathCtx(next http.Handler) http.Handler {
+	return hashPathCtx("last_seen_txid", ctxLastSeenTxid, next)
+}
+
+// AddressPathCtx is a middleware that embeds the address at the url part
+// {address} into the request context, after checking that it is a valid
+// address of the chain.
+func (eapi *EsploraApi) AddressPathCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		address := chi.URLParam(r, "address")
+		if !eapi.BlockData.IsMutilchainValidAddress(eapi.chainType, address) {
+			http.Error(w, "Invalid address", http.StatusBadRequest)
+			return
+		}
+		ctx := context.WithValue(r.Context(), ctxAddress, address)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ScripthashPathCtx is a middleware that embeds the hex encoded SHA256 hash of
+// a pkScript at the url part {hash}, and the address paid by that pkScript,
+// into the request context. The pkScripts are looked up in the outputs of the
+// whole chain, so the response is 503 until the whole-chain tables are synced,
+// and 404 if no output pays to the pkScript.
+func (eapi *EsploraApi) ScripthashPathCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hashStr := chi.URLParam(r, "hash")
+		scripthash, err := hex.DecodeString(hashStr)
+		if err != nil || len(scripthash) != 32 {
+			http.Error(w, "Invalid scripthash", http.StatusBadRequest)
+			return
+		}
+		if !eapi.BlockData.MutilchainWholeChainSynced(eapi.chainType) {
+			http.Error(w, "Scripthash lookups require the whole chain to be synced (syncchaindb)",
+				http.StatusServiceUnavailable)
+			return
+		}
+		address, err := eapi.BlockData.MutilchainScripthashAddress(scripthash, eapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to look up %s scripthash %s: %v", eapi.chainType, hashStr, err)
+			writeDBError(w, err)
+			return
+		}
+		if address == "" {
+			http.Error(w, "Scripthash not found", http.StatusNotFound)
+			return
+		}
+		ctx := context.WithValue(r.Context(), ctxScripthash, hashStr)
+		ctx = context.WithValue(ctx, ctxAddress, address)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// getAddressCtx retrieves the ctxAddress data from the request context. If not
+// set, the return value is an empty string.
+func getAddressCtx(r *http.Request) string {
+	address, _ := r.Context().Value(ctxAddress).(string)
+	return address
+}
+
+// getScripthashCtx retrieves the ctxScripthash data from the request context.
+// If not set, the return value is an empty string.
+func getScripthashCtx(r *http.Request) string {
+	hash, _ := r.Context().Value(ctxScripthash).(string)
+	return hash
+}
+
+// uintPathCtx returns a middleware that embeds the unsigned integer at the url
+// part {param} into the request context with the given key.
+func uintPathCtx(param string, key contextKey, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		val, err := strconv.ParseUint(chi.URLParam(r, param), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
+		ctx := context.WithValue(r.Context(), key, uint32(val))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// getUintCtx retrieves the unsigned integer with the given key from the
+// request context.
+func getUintCtx(r *http.Request, key contextKey) (uint32, bool) {
+	val, ok := r.Context().Value(key).(uint32)
+	return val, ok
+}
+
+// VoutPathCtx is a middleware that embeds the output index at the url part
+// {vout} into the request context.
+func VoutPathCtx(next http.Handler) http.Handler {
+	return uintPathCtx("vout", ctxVout, next)
+}
+
+// StartIndexPathCtx is a middleware that embeds the transaction index at the
+// url part {start_index} into the request context.
+func StartIndexPathCtx(next http.Handler) http.Handler {
+	return uintPathCtx("start_index", ctxStartIndex, next)
+}
+
+// HeightPathCtx is a middleware that embeds the block height at the url part
+// {height} into the request context.
+func HeightPathCtx(next http.Handler) http.Handler {
+	return uintPathCtx("height", ctxHeight, next)
+}
+
+// StartHeightPathCtx is a middleware that embeds the block height at the url
+// part {start_height} into the request context.
+func StartHeightPathCtx(next http.Handler) http.Handler {
+	return uintPathCtx("start_height", ctxStartHeight, next)
+}
+
+// TxIndexPathCtx is a middleware that embeds the transaction index at the url
+// part {index} into the request context.
+func TxIndexPathCtx(next http.Handler) http.Handler {
+	return uintPathCtx("index", ctxTxIndex, next)
+}
+
+// isHash checks that s is a hex encoded 32-byte hash.
+func isHash(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package esplora implements the Esplora HTTP API for the BTC and LTC chains.
+package esplora
+
+import (
+	"fmt"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// defaultReqPerSecLimit is the default requests/second/IP of the rate limiter.
+const defaultReqPerSecLimit = 20.0
+
+// ApiMux contains the struct mux
+type ApiMux struct {
+	*chi.Mux
+}
+
+// NewEsploraAPIRouter returns a new HTTP path router, ApiMux, for the Esplora
+// API of a BTC or LTC chain, app.
+func NewEsploraAPIRouter(app *EsploraApi, useRealIP, compression bool) ApiMux {
+	// chi router
+	mux := chi.NewRouter()
+
+	// Create a rate limiter struct.
+	limiter := m.NewLimiter(app.ReqPerSecLimit)
+	limiter.SetMessage(fmt.Sprintf(
+		"You have reached the maximum request limit (%g req/s)", app.ReqPerSecLimit))
+
+	if useRealIP {
+		mux.Use(middleware.RealIP)
+		// RealIP sets RemoteAddr
+		limiter.SetIPLookups([]string{"RemoteAddr"})
+	} else {
+		limiter.SetIPLookups([]string{"X-Forwarded-For", "X-Real-IP", "RemoteAddr"})
+	}
+
+	// Put the limiter after RealIP
+	mux.Use(m.Tollbooth(limiter))
+
+	mux.Use(m.Indent(app.JSONIndent))
+	mux.Use(middleware.Logger)
+	mux.Use(middleware.Recoverer)
+	mux.Use(middleware.StripSlashes)
+	if compression {
+		mux.Use(middleware.Compress(3))
+	}
+
+	// Transaction endpoints
+	mux.Post("/tx", app.broadcastTransaction)
+	mux.Route("/tx/{txid}", func(r chi.Router) {
+		r.Use(TxidPathCtx)
+		r.Get("/", app.getTransaction)
+		r.Get("/status", app.getTransactionStatus)
+		r.Get("/hex", app.getTransactionHex)
+		r.Get("/raw", app.getTransactionRaw)
+		r.Get("/outspends", app.getTransactionOutspends)
+		r.With(VoutPathCtx).Get("/outspend/{vout}", app.getTransactionOutspend)
+	})
+
+	// Address and scripthash endpoints
+	addressRoutes := func(r chi.Router) {
+		r.Get("/", app.getAddressStats)
+		r.Get("/txs", app.getAddressTxns)
+		r.Get("/txs/chain", app.getAddressChainTxns)
+		r.With(LastSeenTxidPathCtx).Get("/txs/chain/{last_seen_txid}", app.getAddressChainTxns)
+		r.Get("/txs/mempool", app.getAddressMempoolTxns)
+		r.Get("/utxo", app.getAddressUTXOs)
+	}
+	mux.Route("/address/{address}", func(r chi.Router) {
+		r.Use(app.AddressPathCtx)
+		addressRoutes(r)
+	})
+	mux.Route("/scripthash/{hash}", func(r chi.Router) {
+		r.Use(app.ScripthashPathCtx)
+		addressRoutes(r)
+	})
+
+	// Block endpoints
+	mux.Route("/block/{hash}", func(r chi.Router) {
+		r.Use(BlockHashPathCtx)
+		r.Get("/", app.getBlock)
+		r.Get("/status", app.getBlockStatus)
+		r.Get("/txids", app.getBlockTxids)
+		r.With(TxIndexPathCtx).Get("/txid/{index}", app.getBlockTxid)
+		r.Get("/txs", app.getBlockTxns)
+		r.With(StartIndexPathCtx).Get("/txs/{start_index}", app.getBlockTxns)
+		r.Get("/header", app.getBlockHeader)
+		r.Get("/raw", app.getBlockRaw)
+	})
+	mux.With(HeightPathCtx).Get("/block-height/{height}", app.getBlockHashAtHeight)
+	mux.Get("/blocks", app.getBlocks)
+	mux.With(StartHeightPathCtx).Get("/blocks/{start_height}", app.getBlocks)
+	mux.Get("/blocks/tip/height", app.getTipHeight)
+	mux.Get("/blocks/tip/hash", app.getTipHash)
+
+	// Mempool and fee endpoints
+	mux.Get("/mempool/txids", app.getMempoolTxids)
+	mux.Get("/fee-estimates", app.getFeeEstimates)
+
+	return ApiMux{mux}
+}
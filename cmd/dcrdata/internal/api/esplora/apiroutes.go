@@ -0,0 +1,522 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package esplora
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+const (
+	// maxTxSize is the maximum size in bytes of a transaction accepted by the
+	// broadcast endpoint.
+	maxTxSize = 400000
+	// chainTxsPerPage is the number of confirmed transactions of an address
+	// returned per request.
+	chainTxsPerPage = 25
+	// maxMempoolTxs is the maximum number of mempool transactions of an address
+	// returned per request.
+	maxMempoolTxs = 50
+	// blockTxsPerPage is the number of transactions of a block returned per
+	// request.
+	blockTxsPerPage = 25
+	// blocksPerPage is the number of blocks returned by the blocks endpoint.
+	blocksPerPage = 10
+	// feeEstimatesTTL is how long the fee estimates of the node are cached.
+	feeEstimatesTTL = 30 * time.Second
+)
+
+// DataSource is the interface for the multichain DB, mempool monitors and node
+// RPC clients that back the Esplora API of a BTC or LTC chain.
+type DataSource interface {
+	IsMutilchainValidAddress(chainType string, address string) bool
+	MutilchainEsploraTxs(txids []string, chainType string) ([]*apitypes.EsploraTx, error)
+	MutilchainEsploraTxStatus(txid, chainType string) (*apitypes.EsploraTxStatus, error)
+	GetMultichainTransactionHex(txid, chainType string) string
+	MutilchainEsploraOutspends(txid, chainType string) ([]apitypes.EsploraOutspend, error)
+	MutilchainSendRawTransaction(txhex, chainType string) (string, error)
+	MutilchainEsploraAddress(address, chainType string) (*apitypes.EsploraAddress, error)
+	MutilchainInsightAddressTransactions(addrs []string, chainType string) ([]string, error)
+	MutilchainUnconfirmedTxnsForAddress(address, chainType string) (*dbtypes.MutilchainMempoolAddress, error)
+	MutilchainEsploraUTXOs(address, chainType string) ([]apitypes.EsploraUTXO, error)
+	MutilchainScripthashAddress(scripthash []byte, chainType string) (string, error)
+	MutilchainWholeChainSynced(chainType string) bool
+	MutilchainEsploraBlock(hash, chainType string) (*apitypes.EsploraBlock, error)
+	MutilchainEsploraBlockStatus(hash, chainType string) (*apitypes.EsploraBlockStatus, error)
+	MutilchainBlockTxids(hash, chainType string) ([]string, error)
+	MutilchainEsploraBlockTxs(hash string, start, count int, chainType string) ([]*apitypes.EsploraTx, error)
+	MutilchainBlockHeaderHex(hash, chainType string) (string, error)
+	MutilchainRawBlock(hash, chainType string) (string, error)
+	MutilchainBlockHashAtHeight(height int64, chainType string) (string, error)
+	MutilchainBestBlock(chainType string) (int64, string, error)
+	MutilchainMempoolTxids(chainType string) ([]string, error)
+	MutilchainEsploraFeeEstimates(chainType string) (map[string]float64, error)
+}
+
+// EsploraApi implements the Esplora HTTP API of a BTC or LTC chain.
+type EsploraApi struct {
+	chainType      string
+	BlockData      DataSource
+	JSONIndent     string
+	ReqPerSecLimit float64
+
+	feeMtx       sync.Mutex
+	feeEstimates map[string]float64
+	feeUpdated   time.Time
+}
+
+// NewEsploraAPI is the constructor for EsploraApi.
+func NewEsploraAPI(chainType string, blockData DataSource, JSONIndent string) *EsploraApi {
+	return &EsploraApi{
+		chainType:      chainType,
+		BlockData:      blockData,
+		JSONIndent:     JSONIndent,
+		ReqPerSecLimit: defaultReqPerSecLimit,
+	}
+}
+
+// SetReqRateLimit is used to set the requests/second/IP for the Esplora API's
+// rate limiter.
+func (eapi *EsploraApi) SetReqRateLimit(reqPerSecLimit float64) {
+	eapi.ReqPerSecLimit = reqPerSecLimit
+}
+
+func writeJSON(w http.ResponseWriter, thing interface{}, indent string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(thing); err != nil {
+		apiLog.Infof("JSON encode error: %v", err)
+	}
+}
+
+func writeText(w http.ResponseWriter, text string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := io.WriteString(w, text); err != nil {
+		apiLog.Infof("Write error: %v", err)
+	}
+}
+
+func writeBinary(w http.ResponseWriter, hexStr string) {
+	b, err := hex.DecodeString(hexStr)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	if _, err = w.Write(b); err != nil {
+		apiLog.Infof("Write error: %v", err)
+	}
+}
+
+// writeDBError writes a 503 response for DB timeouts, and a 500 response for
+// other errors.
+func writeDBError(w http.ResponseWriter, err error) {
+	if dbtypes.IsTimeoutErr(err) {
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
+func (eapi *EsploraApi) getTransaction(w http.ResponseWriter, r *http.Request) {
+	txid := getHashCtx(r, ctxTxid)
+	txs, err := eapi.BlockData.MutilchainEsploraTxs([]string{txid}, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s transaction %s: %v", eapi.chainType, txid, err)
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, txs[0], m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getTransactionStatus(w http.ResponseWriter, r *http.Request) {
+	txid := getHashCtx(r, ctxTxid)
+	status, err := eapi.BlockData.MutilchainEsploraTxStatus(txid, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s transaction %s: %v", eapi.chainType, txid, err)
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, status, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getTransactionHex(w http.ResponseWriter, r *http.Request) {
+	txHex := eapi.BlockData.GetMultichainTransactionHex(getHashCtx(r, ctxTxid), eapi.chainType)
+	if txHex == "" {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	writeText(w, txHex)
+}
+
+func (eapi *EsploraApi) getTransactionRaw(w http.ResponseWriter, r *http.Request) {
+	txHex := eapi.BlockData.GetMultichainTransactionHex(getHashCtx(r, ctxTxid), eapi.chainType)
+	if txHex == "" {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	writeBinary(w, txHex)
+}
+
+func (eapi *EsploraApi) getTransactionOutspends(w http.ResponseWriter, r *http.Request) {
+	txid := getHashCtx(r, ctxTxid)
+	outspends, err := eapi.BlockData.MutilchainEsploraOutspends(txid, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s outspends of %s: %v", eapi.chainType, txid, err)
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, outspends, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getTransactionOutspend(w http.ResponseWriter, r *http.Request) {
+	txid := getHashCtx(r, ctxTxid)
+	vout, _ := getUintCtx(r, ctxVout)
+	outspends, err := eapi.BlockData.MutilchainEsploraOutspends(txid, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s outspends of %s: %v", eapi.chainType, txid, err)
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	if int(vout) >= len(outspends) {
+		http.Error(w, "Output not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, outspends[vout], m.GetIndentCtx(r))
+}
+
+// broadcastTransaction broadcasts the hex encoded transaction in the request
+// body and responds with its hash.
+func (eapi *EsploraApi) broadcastTransaction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 2*maxTxSize+2))
+	if err != nil {
+		http.Error(w, "Transaction too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	txHex := strings.TrimSpace(string(body))
+	if _, err = hex.DecodeString(txHex); err != nil || txHex == "" {
+		http.Error(w, "Invalid hex string", http.StatusBadRequest)
+		return
+	}
+	txid, err := eapi.BlockData.MutilchainSendRawTransaction(txHex, eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to send %s transaction: %v", eapi.chainType, err)
+		http.Error(w, "sendrawtransaction RPC error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeText(w, txid)
+}
+
+// getAddressStats responds with the output statistics of an address, or of
+// the address of a scripthash.
+func (eapi *EsploraApi) getAddressStats(w http.ResponseWriter, r *http.Request) {
+	address, scripthash := getAddressCtx(r), getScripthashCtx(r)
+	stats, err := eapi.BlockData.MutilchainEsploraAddress(address, eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s address stats for %s: %v", eapi.chainType, address, err)
+		writeDBError(w, err)
+		return
+	}
+	if scripthash != "" {
+		stats.Address = ""
+		stats.ScriptHash = scripthash
+	}
+	writeJSON(w, stats, m.GetIndentCtx(r))
+}
+
+// mempoolTxids returns the hashes of the mempool transactions of an address,
+// newest first.
+func (eapi *EsploraApi) mempoolTxids(address string) ([]string, error) {
+	mp, err := eapi.BlockData.MutilchainUnconfirmedTxnsForAddress(address, eapi.chainType)
+	if err != nil {
+		return nil, err
+	}
+	idx := make([]int, len(mp.Txids))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return mp.TxTimes[idx[i]] > mp.TxTimes[idx[j]]
+	})
+	txids := make([]string, 0, len(idx))
+	for _, i := range idx {
+		txids = append(txids, mp.Txids[i])
+	}
+	return txids, nil
+}
+
+// chainTxids returns the hashes of up to chainTxsPerPage confirmed
+// transactions of an address, most recent first, after lastSeen if set.
+func (eapi *EsploraApi) chainTxids(address, lastSeen string) ([]string, error) {
+	txids, err := eapi.BlockData.MutilchainInsightAddressTransactions([]string{address}, eapi.chainType)
+	if err != nil {
+		return nil, err
+	}
+	if lastSeen != "" {
+		for i := range txids {
+			if txids[i] == lastSeen {
+				txids = txids[i+1:]
+				break
+			}
+		}
+	}
+	if len(txids) > chainTxsPerPage {
+		txids = txids[:chainTxsPerPage]
+	}
+	return txids, nil
+}
+
+// writeAddressTxns responds with the transactions of the address in the
+// request context, which are up to maxMempoolTxs mempool transactions if
+// mempool is set, followed by a page of confirmed transactions if chain is
+// set.
+func (eapi *EsploraApi) writeAddressTxns(w http.ResponseWriter, r *http.Request, mempool, chain bool) {
+	address := getAddressCtx(r)
+	txids := []string{}
+	if mempool {
+		mempoolTxids, err := eapi.mempoolTxids(address)
+		if err != nil {
+			apiLog.Errorf("Unable to get %s mempool transactions for %s: %v", eapi.chainType, address, err)
+			writeDBError(w, err)
+			return
+		}
+		if len(mempoolTxids) > maxMempoolTxs {
+			mempoolTxids = mempoolTxids[:maxMempoolTxs]
+		}
+		txids = append(txids, mempoolTxids...)
+	}
+	if chain {
+		chainTxids, err := eapi.chainTxids(address, getHashCtx(r, ctxLastSeenTxid))
+		if err != nil {
+			apiLog.Errorf("Unable to get %s transactions for %s: %v", eapi.chainType, address, err)
+			writeDBError(w, err)
+			return
+		}
+		txids = append(txids, chainTxids...)
+	}
+	txs, err := eapi.BlockData.MutilchainEsploraTxs(txids, eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s transactions for %s: %v", eapi.chainType, address, err)
+		writeDBError(w, err)
+		return
+	}
+	writeJSON(w, txs, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getAddressTxns(w http.ResponseWriter, r *http.Request) {
+	eapi.writeAddressTxns(w, r, true, true)
+}
+
+func (eapi *EsploraApi) getAddressChainTxns(w http.ResponseWriter, r *http.Request) {
+	eapi.writeAddressTxns(w, r, false, true)
+}
+
+func (eapi *EsploraApi) getAddressMempoolTxns(w http.ResponseWriter, r *http.Request) {
+	eapi.writeAddressTxns(w, r, true, false)
+}
+
+func (eapi *EsploraApi) getAddressUTXOs(w http.ResponseWriter, r *http.Request) {
+	address := getAddressCtx(r)
+	utxos, err := eapi.BlockData.MutilchainEsploraUTXOs(address, eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s UTXOs for %s: %v", eapi.chainType, address, err)
+		writeDBError(w, err)
+		return
+	}
+	writeJSON(w, utxos, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getBlock(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	block, err := eapi.BlockData.MutilchainEsploraBlock(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, block, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getBlockStatus(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	status, err := eapi.BlockData.MutilchainEsploraBlockStatus(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, status, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getBlockTxids(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	txids, err := eapi.BlockData.MutilchainBlockTxids(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, txids, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getBlockTxid(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	index, _ := getUintCtx(r, ctxTxIndex)
+	txids, err := eapi.BlockData.MutilchainBlockTxids(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	if int(index) >= len(txids) {
+		http.Error(w, "Transaction index out of range", http.StatusNotFound)
+		return
+	}
+	writeText(w, txids[index])
+}
+
+func (eapi *EsploraApi) getBlockTxns(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	start, _ := getUintCtx(r, ctxStartIndex)
+	if start%blockTxsPerPage != 0 {
+		http.Error(w, "start index must be a multiple of "+
+			strconv.Itoa(blockTxsPerPage), http.StatusBadRequest)
+		return
+	}
+	txs, err := eapi.BlockData.MutilchainEsploraBlockTxs(hash, int(start), blockTxsPerPage, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s transactions: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, txs, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getBlockHeader(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	headerHex, err := eapi.BlockData.MutilchainBlockHeaderHex(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s header: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeText(w, headerHex)
+}
+
+func (eapi *EsploraApi) getBlockRaw(w http.ResponseWriter, r *http.Request) {
+	hash := getHashCtx(r, ctxBlockHash)
+	blockHex, err := eapi.BlockData.MutilchainRawBlock(hash, eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeBinary(w, blockHex)
+}
+
+func (eapi *EsploraApi) getBlockHashAtHeight(w http.ResponseWriter, r *http.Request) {
+	height, _ := getUintCtx(r, ctxHeight)
+	hash, err := eapi.BlockData.MutilchainBlockHashAtHeight(int64(height), eapi.chainType)
+	if err != nil {
+		apiLog.Debugf("Unable to get %s block hash at height %d: %v", eapi.chainType, height, err)
+		http.Error(w, "Block not found", http.StatusNotFound)
+		return
+	}
+	writeText(w, hash)
+}
+
+// getBlocks responds with the blocksPerPage blocks at and below the start
+// height, which defaults to the chain tip.
+func (eapi *EsploraApi) getBlocks(w http.ResponseWriter, r *http.Request) {
+	height, _, err := eapi.BlockData.MutilchainBestBlock(eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s best block: %v", eapi.chainType, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if start, ok := getUintCtx(r, ctxStartHeight); ok && int64(start) < height {
+		height = int64(start)
+	}
+
+	blocks := make([]*apitypes.EsploraBlock, 0, blocksPerPage)
+	for h := height; h >= 0 && h > height-blocksPerPage; h-- {
+		hash, err := eapi.BlockData.MutilchainBlockHashAtHeight(h, eapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to get %s block hash at height %d: %v", eapi.chainType, h, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		block, err := eapi.BlockData.MutilchainEsploraBlock(hash, eapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to get %s block %s: %v", eapi.chainType, hash, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		blocks = append(blocks, block)
+	}
+	writeJSON(w, blocks, m.GetIndentCtx(r))
+}
+
+func (eapi *EsploraApi) getTipHeight(w http.ResponseWriter, _ *http.Request) {
+	height, _, err := eapi.BlockData.MutilchainBestBlock(eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s best block: %v", eapi.chainType, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeText(w, strconv.FormatInt(height, 10))
+}
+
+func (eapi *EsploraApi) getTipHash(w http.ResponseWriter, _ *http.Request) {
+	_, hash, err := eapi.BlockData.MutilchainBestBlock(eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s best block: %v", eapi.chainType, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	writeText(w, hash)
+}
+
+func (eapi *EsploraApi) getMempoolTxids(w http.ResponseWriter, r *http.Request) {
+	txids, err := eapi.BlockData.MutilchainMempoolTxids(eapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s mempool: %v", eapi.chainType, err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if txids == nil {
+		txids = []string{}
+	}
+	writeJSON(w, txids, m.GetIndentCtx(r))
+}
+
+// getFeeEstimates responds with the fee rate estimates of the node in sat/vB
+// by confirmation target. The estimates are cached for feeEstimatesTTL.
+func (eapi *EsploraApi) getFeeEstimates(w http.ResponseWriter, r *http.Request) {
+	eapi.feeMtx.Lock()
+	defer eapi.feeMtx.Unlock()
+	if eapi.feeEstimates == nil || time.Since(eapi.feeUpdated) > feeEstimatesTTL {
+		estimates, err := eapi.BlockData.MutilchainEsploraFeeEstimates(eapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to estimate %s fees: %v", eapi.chainType, err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		eapi.feeEstimates = estimates
+		eapi.feeUpdated = time.Now()
+	}
+	writeJSON(w, eapi.feeEstimates, m.GetIndentCtx(r))
+}
@@ -0,0 +1,574 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/go-chi/chi/v5"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// The handlers in this file serve the data exports of the file router at
+// /download/export. The "format" URL query parameter selects CSV (the default)
+// or NDJSON, and the "fiat" URL query parameter adds the historical USD price
+// and value of each row to the Decred exports. The rows are streamed from the
+// database as they are read, and limited to maxExportRows. At most
+// max-concurrent-exports exports are served at once, see limitExports.
+
+// exportRetryAfter is the Retry-After of the responses to the exports that
+// are rejected because the maximum number of concurrent exports is reached.
+const exportRetryAfter = 30 * time.Second
+
+// limitExports is a middleware that rejects an export with 429 Too Many
+// Requests if the maximum number of exports are already being served, since
+// each export holds a database connection and transaction for the whole
+// response.
+func (c *appContext) limitExports(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case c.exportSlots <- struct{}{}:
+		default:
+			w.Header().Set("Retry-After", strconv.Itoa(int(exportRetryAfter.Seconds())))
+			http.Error(w, "Too many concurrent exports, try again later.", http.StatusTooManyRequests)
+			return
+		}
+		defer func() { <-c.exportSlots }()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// exportParams parses the format and fiat URL query parameters.
+func exportParams(r *http.Request) (format dbtypes.ExportFormat, fiat bool, err error) {
+	format, err = dbtypes.ExportFormatFromStr(r.URL.Query().Get("format"))
+	if err != nil {
+		return
+	}
+	if fiatStr := r.URL.Query().Get("fiat"); fiatStr != "" {
+		fiat, err = strconv.ParseBool(fiatStr)
+	}
+	return
+}
+
+// exportRange parses the "from" and "to" block height URL query parameters.
+// The range defaults to the last maxExportRows blocks.
+func (c *appContext) exportRange(r *http.Request, bestHeight int64) (from, to int64, err error) {
+	to = bestHeight
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = strconv.ParseInt(toStr, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid to height")
+		}
+	}
+	from = to - c.maxExportRows + 1
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = strconv.ParseInt(fromStr, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid from height")
+		}
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to < from {
+		return 0, 0, fmt.Errorf("invalid height range")
+	}
+	return from, to, nil
+}
+
+// writeExport sets the headers of an export response named name and streams
+// the rows written by export. Once the first rows are sent, errors can only be
+// logged.
+func (c *appContext) writeExport(w http.ResponseWriter, r *http.Request, name string, format dbtypes.ExportFormat,
+	export func(context.Context, dbtypes.ExportWriter) error) {
+	wf, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "unable to flush streamed data", http.StatusBadRequest)
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%d-%d.%s", name, c.Status.Height(), time.Now().Unix(), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", filename))
+	w.Header().Set("Content-Type", format.ContentType())
+	ew := &flushingExportWriter{ExportWriter: dbtypes.NewExportWriter(w, format, false), w: w, wf: wf}
+	if err := export(r.Context(), ew); err != nil {
+		apiLog.Errorf("Export %s failed: %v", name, err)
+		if !ew.started {
+			if dbtypes.IsTimeoutErr(err) {
+				http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}
+	}
+}
+
+// flushingExportWriter flushes the HTTP response with the export writer, and
+// records if the response was started.
+type flushingExportWriter struct {
+	dbtypes.ExportWriter
+	w       http.ResponseWriter
+	wf      http.Flusher
+	started bool
+}
+
+func (fw *flushingExportWriter) WriteHeader(columns []string) error {
+	fw.w.WriteHeader(http.StatusOK)
+	fw.started = true
+	return fw.ExportWriter.WriteHeader(columns)
+}
+
+func (fw *flushingExportWriter) Flush() error {
+	if err := fw.ExportWriter.Flush(); err != nil {
+		return err
+	}
+	fw.wf.Flush()
+	return nil
+}
+
+// exportAddress handles /download/export/address/{address}.
+func (c *appContext) exportAddress(w http.ResponseWriter, r *http.Request) {
+	format, fiat, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	address := addresses[0]
+	if _, err = stdaddr.DecodeAddress(address, c.Params); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	c.writeExport(w, r, "address-"+address, format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportAddressRows(ctx, ew, address, fiat, c.maxExportRows)
+	})
+}
+
+// exportBlocks handles /download/export/blocks.
+func (c *appContext) exportBlocks(w http.ResponseWriter, r *http.Request) {
+	format, fiat, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	from, to, err := c.exportRange(r, int64(c.Status.Height()))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	name := fmt.Sprintf("blocks-%d-%d", from, to)
+	c.writeExport(w, r, name, format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportBlocks(ctx, ew, from, to, fiat, c.maxExportRows)
+	})
+}
+
+// exportTreasury handles /download/export/treasury.
+func (c *appContext) exportTreasury(w http.ResponseWriter, r *http.Request) {
+	format, fiat, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.writeExport(w, r, "treasury", format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportTreasuryTxns(ctx, ew, fiat, c.maxExportRows)
+	})
+}
+
+// exportAtomicSwaps handles /download/export/swaps.
+func (c *appContext) exportAtomicSwaps(w http.ResponseWriter, r *http.Request) {
+	format, fiat, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.writeExport(w, r, "atomic-swaps", format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportAtomicSwaps(ctx, ew, fiat, c.maxExportRows)
+	})
+}
+
+// exportChart handles /download/export/chart/{charttype}, with the same bin
+// and axis URL query parameters as /api/chart/{charttype}.
+func (c *appContext) exportChart(w http.ResponseWriter, r *http.Request) {
+	format, _, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chartType := m.GetChartTypeCtx(r)
+	query := r.URL.Query()
+	chartData, err := c.charts.Chart(chartType, query.Get("bin"), query.Get("axis"), query.Get("range"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	c.writeExport(w, r, "chart-"+chartType, format, func(_ context.Context, ew dbtypes.ExportWriter) error {
+		return writeChartExport(ew, chartData, c.maxExportRows)
+	})
+}
+
+// isExportChain is true for the chains with exports of their own data.
+func isExportChain(chainType string) bool {
+	return chainType == mutilchain.TYPEBTC || chainType == mutilchain.TYPELTC
+}
+
+// exportMutilchainAddress handles /download/export/{chaintype}/address/{address}.
+func (c *appContext) exportMutilchainAddress(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	if !isExportChain(chainType) {
+		http.NotFound(w, r)
+		return
+	}
+	format, fiat, err := exportParams(r)
+	if err != nil || fiat {
+		http.Error(w, "invalid format, or fiat values requested for a chain without price data", http.StatusBadRequest)
+		return
+	}
+	address := chi.URLParam(r, "address")
+	if !c.DataSource.IsMutilchainValidAddress(chainType, address) {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	name := fmt.Sprintf("%s-address-%s", chainType, address)
+	c.writeExport(w, r, name, format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportMutilchainAddressRows(ctx, ew, chainType, address, c.maxExportRows)
+	})
+}
+
+// exportMutilchainBlocks handles /download/export/{chaintype}/blocks.
+func (c *appContext) exportMutilchainBlocks(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	if !isExportChain(chainType) {
+		http.NotFound(w, r)
+		return
+	}
+	format, fiat, err := exportParams(r)
+	if err != nil || fiat {
+		http.Error(w, "invalid format, or fiat values requested for a chain without price data", http.StatusBadRequest)
+		return
+	}
+	bestHeight, _, err := c.DataSource.MutilchainBestBlock(chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get the %s best block: %v", chainType, err)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	from, to, err := c.exportRange(r, bestHeight)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	name := fmt.Sprintf("%s-blocks-%d-%d", chainType, from, to)
+	c.writeExport(w, r, name, format, func(ctx context.Context, ew dbtypes.ExportWriter) error {
+		return c.DataSource.ExportMutilchainBlocks(ctx, ew, chainType, from, to, c.maxExportRows)
+	})
+}
+
+// exportMutilchainChart handles /download/export/{chaintype}/chart/{charttype}.
+func (c *appContext) exportMutilchainChart(w http.ResponseWriter, r *http.Request) {
+	chainType := m.GetChainTypeCtx(r)
+	charts := c.GetMutilchainChartData(chainType)
+	if charts == nil {
+		http.NotFound(w, r)
+		return
+	}
+	format, _, err := exportParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chartType := m.GetChartTypeCtx(r)
+	chartData, err := charts.Chart(chartType, r.URL.Query().Get("bin"), r.URL.Query().Get("axis"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	name := fmt.Sprintf("%s-chart-%s", chainType, chartType)
+	c.writeExport(w, r, name, format, func(_ context.Context, ew dbtypes.ExportWriter) error {
+		return writeChartExport(ew, chartData, c.maxExportRows)
+	})
+}
+
+// writeChartExport writes the series of an encoded chart as the columns of an
+// export, up to maxRows rows. The time and height axes are the first columns,
+// followed by the other series in name order. Scalar chart fields, e.g. the
+// bin and axis, are omitted.
+func writeChartExport(ew dbtypes.ExportWriter, chartData []byte, maxRows int64) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(chartData, &fields); err != nil {
+		return err
+	}
+	series := make(map[string][]any, len(fields))
+	columns := make([]string, 0, len(fields))
+	var numRows int
+	for name, raw := range fields {
+		if len(raw) == 0 || raw[0] != '[' {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		var values []any
+		if err := dec.Decode(&values); err != nil {
+			return fmt.Errorf("chart series %q: %w", name, err)
+		}
+		series[name] = values
+		columns = append(columns, name)
+		numRows = max(numRows, len(values))
+	}
+	axisOrder := map[string]int{"t": 0, "h": 1}
+	sort.Slice(columns, func(i, j int) bool {
+		oi, iAxis := axisOrder[columns[i]]
+		oj, jAxis := axisOrder[columns[j]]
+		if iAxis || jAxis {
+			return iAxis && (!jAxis || oi < oj)
+		}
+		return columns[i] < columns[j]
+	})
+
+	if err := ew.WriteHeader(columns); err != nil {
+		return err
+	}
+	if int64(numRows) > maxRows {
+		numRows = int(maxRows)
+	}
+	row := make([]any, len(columns))
+	for i := 0; i < numRows; i++ {
+		for j, col := range columns {
+			row[j] = nil
+			if values := series[col]; i < len(values) {
+				row[j] = values[i]
+			}
+		}
+		if err := ew.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return ew.Flush()
+}
+
+// maxReportAddresses is the maximum number of addresses of a cost basis
+// report.
+const maxReportAddresses = 100
+
+// reportAddresses parses the "addresses" URL query parameter of a cost basis
+// report, a comma-separated list of addresses prefixed with their chain, e.g.
+// btc:bc1q..., or DCR addresses without a prefix. The addresses are grouped by
+// chain.
+func (c *appContext) reportAddresses(r *http.Request) (map[string][]string, error) {
+	list := strings.Split(r.URL.Query().Get("addresses"), ",")
+	if len(list) > maxReportAddresses {
+		return nil, fmt.Errorf("more than %d addresses", maxReportAddresses)
+	}
+	chainAddrs := make(map[string][]string)
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		chainType, address, found := strings.Cut(item, ":")
+		if !found {
+			chainType, address = mutilchain.TYPEDCR, item
+		}
+		chainType = strings.ToLower(chainType)
+		if c.ChainDisabledMap[chainType] {
+			return nil, fmt.Errorf("chain %s is disabled", chainType)
+		}
+		switch chainType {
+		case mutilchain.TYPEDCR:
+			if _, err := stdaddr.DecodeAddress(address, c.Params); err != nil {
+				return nil, fmt.Errorf("invalid dcr address %s", address)
+			}
+		case mutilchain.TYPEBTC, mutilchain.TYPELTC:
+			if !c.DataSource.IsMutilchainValidAddress(chainType, address) {
+				return nil, fmt.Errorf("invalid %s address %s", chainType, address)
+			}
+		default:
+			return nil, fmt.Errorf("no address ledger for chain %q", chainType)
+		}
+		if !slices.Contains(chainAddrs[chainType], address) {
+			chainAddrs[chainType] = append(chainAddrs[chainType], address)
+		}
+	}
+	if len(chainAddrs) == 0 {
+		return nil, fmt.Errorf("no addresses")
+	}
+	return chainAddrs, nil
+}
+
+// reportDateRange parses the "from" and "to" UTC dates (YYYY-MM-DD) of a cost
+// basis report into a Unix time range, with the last day included. The range
+// defaults to the start of the year of the to date, or of today, to today.
+func reportDateRange(r *http.Request) (from, to int64, err error) {
+	const layout = "2006-01-02"
+	toDate := time.Now().UTC().Truncate(24 * time.Hour)
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if toDate, err = time.Parse(layout, toStr); err != nil {
+			return 0, 0, fmt.Errorf("invalid to date")
+		}
+	}
+	fromDate := time.Date(toDate.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if fromDate, err = time.Parse(layout, fromStr); err != nil {
+			return 0, 0, fmt.Errorf("invalid from date")
+		}
+	}
+	if toDate.Before(fromDate) {
+		return 0, 0, fmt.Errorf("invalid date range")
+	}
+	return fromDate.Unix(), toDate.AddDate(0, 0, 1).Unix() - 1, nil
+}
+
+// roundTo rounds a value to a number of decimal places.
+func roundTo(v float64, places int) float64 {
+	p := math.Pow10(places)
+	return math.Round(v*p) / p
+}
+
+// exportCostBasisReport handles /download/export/report, the cost basis report
+// of a set of addresses of one or more chains. The URL query parameters are
+// the addresses (see reportAddresses), the from and to dates, the fiat
+// currency (USD by default), the cost basis method (fifo or lifo), and the
+// view: the per-transaction ledger (the default), or the summary of each
+// chain. The cost bases include the lots acquired before the from date.
+func (c *appContext) exportCostBasisReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format, err := dbtypes.ExportFormatFromStr(query.Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	method, err := dbtypes.CostBasisMethodFromStr(query.Get("method"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	view := query.Get("view")
+	if view != "" && view != "ledger" && view != "summary" {
+		http.Error(w, fmt.Sprintf("unknown view %q", view), http.StatusBadRequest)
+		return
+	}
+	from, to, err := reportDateRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	chainAddrs, err := c.reportAddresses(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	var txs []*dbtypes.LedgerTx
+	var currency string
+	prices := make(map[string]*dbtypes.FiatPrices, len(chainAddrs))
+	for chainType, addresses := range chainAddrs {
+		if currency, err = c.fiatCurrency(w, r, chainType, defaultFiatCurrency); err != nil {
+			return
+		}
+		// The cost basis of the disposals depends on all the earlier
+		// acquisitions, which are missing beyond the retention window.
+		if chainType != mutilchain.TYPEDCR {
+			window := c.DataSource.MultichainRetentionWindow(chainType)
+			if window.AddressFromHeight > 0 {
+				http.Error(w, fmt.Sprintf("the %s address rows before block %d are pruned from the DB",
+					chainType, window.AddressFromHeight), http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		chainTxs, err := c.DataSource.AddressLedger(r.Context(), chainType, addresses, to, c.maxExportRows)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("AddressLedger: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("Unable to get the %s address ledger: %v", chainType, err)
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if len(chainTxs) == 0 {
+			continue
+		}
+		if prices[chainType], err = c.currencyPrices(w, chainType, currency, chainTxs[0].Time, to); err != nil {
+			return
+		}
+		txs = append(txs, chainTxs...)
+	}
+	entries, summaries := dbtypes.CostBasisReport(txs, prices, method, from, to)
+
+	name := fmt.Sprintf("report-%s-%s", method, strings.ToLower(currency))
+	if view == "summary" {
+		c.writeExport(w, r, name+"-summary", format, func(_ context.Context, ew dbtypes.ExportWriter) error {
+			return writeCostBasisSummaries(ew, summaries)
+		})
+		return
+	}
+	c.writeExport(w, r, name, format, func(_ context.Context, ew dbtypes.ExportWriter) error {
+		return writeLedgerEntries(ew, entries)
+	})
+}
+
+// writeLedgerEntries writes the entries of a cost basis report, with the coin
+// amounts rounded to atoms and the fiat values to cents.
+func writeLedgerEntries(ew dbtypes.ExportWriter, entries []*dbtypes.LedgerEntry) error {
+	err := ew.WriteHeader([]string{"time_stamp", "chain", "tx_hash", "direction", "amount", "fee_share",
+		"counterparty_tx_hashes", "price", "fiat_value", "cost_basis", "realized_gain"})
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		row := []any{e.Time, e.Chain, e.TxHash, e.Direction, roundTo(e.Amount, 8), roundTo(e.FeeShare, 8),
+			e.Counterparty, e.Price, roundTo(e.FiatValue, 2), roundTo(e.CostBasis, 2), roundTo(e.Gain, 2)}
+		if err = ew.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return ew.Flush()
+}
+
+// writeCostBasisSummaries writes the summaries of a cost basis report, one row
+// per chain.
+func writeCostBasisSummaries(ew dbtypes.ExportWriter, summaries []*dbtypes.CostBasisSummary) error {
+	err := ew.WriteHeader([]string{"chain", "currency", "method", "from", "to",
+		"opening_amount", "opening_cost_basis", "acquired_amount", "acquired_cost",
+		"disposed_amount", "proceeds", "disposed_cost_basis", "realized_gain", "fees",
+		"closing_amount", "closing_cost_basis", "unmatched_amount", "unpriced_entries"})
+	if err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		row := []any{s.Chain, s.Currency, string(s.Method), s.From, s.To,
+			roundTo(s.OpeningAmount, 8), roundTo(s.OpeningCost, 2),
+			roundTo(s.AcquiredAmount, 8), roundTo(s.AcquiredCost, 2),
+			roundTo(s.DisposedAmount, 8), roundTo(s.Proceeds, 2), roundTo(s.DisposedCost, 2),
+			roundTo(s.RealizedGain, 2), roundTo(s.Fees, 8),
+			roundTo(s.ClosingAmount, 8), roundTo(s.ClosingCost, 2),
+			roundTo(s.Unmatched, 8), int64(s.Unpriced)}
+		if err = ew.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return ew.Flush()
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+func TestWriteChartExport(t *testing.T) {
+	chart := []byte(`{"axis":"time","bin":"day","price":[1.5,2,3.25],"h":[10,20,30],"t":[100,200,300],"count":[7,8]}`)
+	tests := []struct {
+		maxRows int64
+		want    string
+	}{
+		{10, "t,h,count,price\n100,10,7,1.5\n200,20,8,2\n300,30,,3.25\n"},
+		{2, "t,h,count,price\n100,10,7,1.5\n200,20,8,2\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		ew := dbtypes.NewExportWriter(&buf, dbtypes.ExportCSV, false)
+		if err := writeChartExport(ew, chart, tt.maxRows); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("maxRows %d: got %q, want %q", tt.maxRows, got, tt.want)
+		}
+	}
+
+	if err := writeChartExport(dbtypes.NewExportWriter(&bytes.Buffer{}, dbtypes.ExportCSV, false),
+		[]byte(`{"t":[1,`), 10); err == nil {
+		t.Error("expected an error for a malformed chart")
+	}
+}
+
+func TestReportDateRange(t *testing.T) {
+	tests := []struct {
+		query    string
+		from, to int64
+		wantErr  bool
+	}{
+		{"from=2024-01-01&to=2024-01-31", 1704067200, 1706745599, false},
+		{"to=2024-03-05", 1704067200, 1709683199, false},
+		{"from=2024-02-01&to=2024-01-31", 0, 0, true},
+		{"from=01/02/2024", 0, 0, true},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/export/report?"+tt.query, nil)
+		from, to, err := reportDateRange(r)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error %v", tt.query, err)
+			continue
+		}
+		if from != tt.from || to != tt.to {
+			t.Errorf("%s: range %d-%d, expecting %d-%d", tt.query, from, to, tt.from, tt.to)
+		}
+	}
+}
+
+func TestLimitExports(t *testing.T) {
+	c := &appContext{exportSlots: make(chan struct{}, 1)}
+	started, release := make(chan struct{}), make(chan struct{})
+	h := c.limitExports(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/export/blocks", nil))
+		close(done)
+	}()
+	<-started
+
+	// The only slot is taken by the running export.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/treasury", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, expecting 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After header")
+	}
+
+	// The slot is released when the export is done.
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("export not done")
+	}
+	next := c.limitExports(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	w = httptest.NewRecorder()
+	next.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/treasury", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status %d after the export, expecting 200", w.Code)
+	}
+}
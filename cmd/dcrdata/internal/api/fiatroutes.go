@@ -0,0 +1,227 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// The handlers and helpers in this file value the transactions and addresses
+// in a fiat currency at the daily price of the day of their block. The
+// currency is set with the ?currency query parameter, and must be one of the
+// fiat-history-currencies of the daily price backfill.
+
+// defaultFiatCurrency is the currency of the address fiat flow charts without
+// a ?currency query parameter.
+const defaultFiatCurrency = "USD"
+
+// fiatCurrency retrieves the currency of the ?currency query parameter, or
+// fallback without the parameter, and checks that there are daily prices of
+// the coin of the chain in this currency. The response has been written if an
+// error is returned.
+func (c *appContext) fiatCurrency(w http.ResponseWriter, r *http.Request, chainType, fallback string) (string, error) {
+	currency := dbtypes.NormalizeCurrency(r.URL.Query().Get("currency"))
+	if currency == "" {
+		currency = fallback
+	}
+	if currency == "" {
+		return "", nil
+	}
+	currencies, err := c.DataSource.FiatCurrencies(chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("FiatCurrencies: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return "", err
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get the %s fiat currencies: %v", chainType, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return "", err
+	}
+	if !slices.Contains(currencies, currency) {
+		http.Error(w, fmt.Sprintf("no %s price history in %s", chainType, currency), 422)
+		return "", fmt.Errorf("unsupported currency")
+	}
+	return currency, nil
+}
+
+// fiatPrices retrieves the daily prices of the coin of the chain in the
+// currency of the ?currency query parameter, for the Unix times from to to.
+// Without the parameter, the prices are nil. The response has been written if
+// an error is returned.
+func (c *appContext) fiatPrices(w http.ResponseWriter, r *http.Request, chainType string, from, to int64) (*dbtypes.FiatPrices, error) {
+	currency, err := c.fiatCurrency(w, r, chainType, "")
+	if err != nil || currency == "" {
+		return nil, err
+	}
+	return c.currencyPrices(w, chainType, currency, from, to)
+}
+
+// currencyPrices retrieves the daily prices of the coin of the chain in the
+// currency for the Unix times from to to. The response has been written if an
+// error is returned.
+func (c *appContext) currencyPrices(w http.ResponseWriter, chainType, currency string, from, to int64) (*dbtypes.FiatPrices, error) {
+	prices, err := c.DataSource.FiatPrices(chainType, currency, from, to)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("FiatPrices: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return nil, err
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get the %s prices in %s: %v", chainType, currency, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, err
+	}
+	return prices, nil
+}
+
+// fiatValue values an amount of coins, and optionally a fee, at the price of
+// the day of the Unix time t. It is nil if there is no price.
+func fiatValue(prices *dbtypes.FiatPrices, t int64, value float64, fee *float64) *apitypes.FiatValue {
+	price := prices.PriceAt(t)
+	if price == 0 {
+		return nil
+	}
+	fv := &apitypes.FiatValue{
+		Currency: prices.Currency,
+		Price:    price,
+		Value:    value * price,
+	}
+	if fee != nil {
+		feeValue := *fee * price
+		fv.Fee = &feeValue
+	}
+	return fv
+}
+
+// setTxFiatValue values the outputs and fee of a DCR transaction at the price
+// of the day of its block. Mempool transactions are not valued. The fee is
+// only set for transactions spending previous outputs alone.
+func setTxFiatValue(tx *apitypes.Tx, prices *dbtypes.FiatPrices) {
+	if prices == nil || tx.Block == nil {
+		return
+	}
+	var value, amountIn float64
+	for i := range tx.Vout {
+		value += tx.Vout[i].Value
+	}
+	feeKnown := true
+	for i := range tx.Vin {
+		vin := &tx.Vin[i]
+		if vin.IsCoinBase() || vin.IsStakeBase() || vin.Treasurybase || vin.TreasurySpend != "" {
+			feeKnown = false
+		}
+		amountIn += vin.AmountIn
+	}
+	var fee *float64
+	if feeKnown && amountIn >= value {
+		fee = new(float64)
+		*fee = amountIn - value
+	}
+	tx.FiatValue = fiatValue(prices, tx.Block.Time, value, fee)
+}
+
+// setChainTxFiatValue values the outputs and fee of a BTC, LTC or XMR
+// transaction at the price of the day of its block. Mempool transactions are
+// not valued. The XMR amounts hidden by RingCT count as zero.
+func setChainTxFiatValue(tx *apitypes.ChainTx, chainType string, prices *dbtypes.FiatPrices) {
+	if prices == nil || tx.Time == 0 {
+		return
+	}
+	atomsPerCoin := 1e8
+	if chainType == mutilchain.TYPEXMR {
+		atomsPerCoin = 1e12
+	}
+	var amount int64
+	for i := range tx.Vout {
+		amount += tx.Vout[i].Amount
+	}
+	var fee *float64
+	if tx.Fee != nil {
+		fee = new(float64)
+		*fee = float64(*tx.Fee) / atomsPerCoin
+	}
+	tx.FiatValue = fiatValue(prices, tx.Time, float64(amount)/atomsPerCoin, fee)
+}
+
+// addressFiatPrices retrieves the prices of the ?currency query parameter for
+// the times of the transactions of an address. The response has been written
+// if an error is returned.
+func (c *appContext) addressFiatPrices(w http.ResponseWriter, r *http.Request, chainType string, txs []*apitypes.AddressTxShort) (*dbtypes.FiatPrices, error) {
+	if len(txs) == 0 || !r.URL.Query().Has("currency") {
+		return nil, nil
+	}
+	from, to := txs[0].Time.S.UNIX(), txs[0].Time.S.UNIX()
+	for _, tx := range txs {
+		t := tx.Time.S.UNIX()
+		from, to = min(from, t), max(to, t)
+	}
+	return c.fiatPrices(w, r, chainType, from, to)
+}
+
+// setAddressFiatValues values the transactions of an address at the price of
+// the day of their block. Unconfirmed transactions are not valued.
+func setAddressFiatValues(txs []*apitypes.AddressTxShort, prices *dbtypes.FiatPrices) {
+	if prices == nil {
+		return
+	}
+	for _, tx := range txs {
+		if tx.Confirmations > 0 {
+			tx.FiatValue = fiatValue(prices, tx.Time.S.UNIX(), tx.Value, nil)
+		}
+	}
+}
+
+// writeAddressFiatFlow writes the chart of the amounts received and sent by an
+// address, valued in the currency of the ?currency query parameter, or USD.
+func (c *appContext) writeAddressFiatFlow(w http.ResponseWriter, r *http.Request, chainType, address string) {
+	interval := dbtypes.TimeGroupingFromStr(m.GetChartGroupingCtx(r))
+	if interval == dbtypes.UnknownGrouping {
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	currency, err := c.fiatCurrency(w, r, chainType, defaultFiatCurrency)
+	if err != nil {
+		return
+	}
+	data, err := c.DataSource.AddressFiatFlow(chainType, address, currency, interval)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("AddressFiatFlow: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Unable to get the %s fiat flow of %s address %s: %v", currency, chainType, address, err)
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, data, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getAddressFiatFlowData(w http.ResponseWriter, r *http.Request) {
+	addresses, err := m.GetAddressCtx(r, c.Params)
+	if err != nil || len(addresses) > 1 {
+		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		return
+	}
+	c.writeAddressFiatFlow(w, r, mutilchain.TYPEDCR, addresses[0])
+}
+
+// getChainAddressFiatFlowData serves the fiat flow of a BTC or LTC address. XMR
+// is rejected by chainAddress, as the received and sent amounts of an XMR
+// address are only known with its view key.
+func (c *appContext) getChainAddressFiatFlowData(w http.ResponseWriter, r *http.Request) {
+	address, err := c.chainAddress(w, r)
+	if err != nil {
+		return
+	}
+	c.writeAddressFiatFlow(w, r, m.GetChainTypeCtx(r), address)
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// chainType returns the chain type of a Chain enum value, if the chain is
+// enabled.
+func (s *Server) chainType(chain string) (string, error) {
+	chainType := strings.ToLower(chain)
+	switch chainType {
+	case mutilchain.TYPEBTC, mutilchain.TYPELTC, mutilchain.TYPEXMR:
+	default:
+		return "", fmt.Errorf("unknown chain %s", chain)
+	}
+	if s.chainDisabled[chainType] {
+		return "", fmt.Errorf("%s is disabled", chain)
+	}
+	return chainType, nil
+}
+
+// chainEnum returns the Chain enum value of a chain type.
+func chainEnum(chainType string) string {
+	return strings.ToUpper(chainType)
+}
+
+// ChainBlock resolves the block of a chain with the height or the hash, or the
+// best block.
+func (r *resolver) ChainBlock(ctx context.Context, args struct {
+	Chain  string
+	Height *Int64
+	Hash   *string
+}) (*chainBlockResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	req := requestFromContext(ctx)
+	var hash string
+	switch {
+	case args.Hash != nil:
+		hash = *args.Hash
+	case args.Height != nil:
+		hash, err = r.s.ds.MutilchainBlockHashAtHeight(int64(*args.Height), chainType)
+		if err != nil {
+			log.Debugf("Unable to get %s block %d: %v", chainType, *args.Height, err)
+			return nil, nil
+		}
+	default:
+		_, hash, err = r.s.ds.MutilchainBestBlock(chainType)
+		if err != nil {
+			log.Errorf("Unable to get the best %s block: %v", chainType, err)
+			return nil, fmt.Errorf("unable to get the best %s block", args.Chain)
+		}
+	}
+	return req.loadChainBlock(ctx, chainType, hash)
+}
+
+// ChainBlocks resolves the blocks of a chain in a range of heights.
+func (r *resolver) ChainBlocks(ctx context.Context, args struct {
+	Chain string
+	From  Int64
+	To    Int64
+}) ([]*chainBlockResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	if err = heightRange(int64(args.From), int64(args.To)); err != nil {
+		return nil, err
+	}
+	req := requestFromContext(ctx)
+	if err = req.charge(int(args.To - args.From + 1)); err != nil {
+		return nil, err
+	}
+	keys := make([]chainKey, 0, args.To-args.From+1)
+	for h := args.From; h <= args.To; h++ {
+		hash, err := r.s.ds.MutilchainBlockHashAtHeight(int64(h), chainType)
+		if err != nil {
+			// Past the best block.
+			break
+		}
+		keys = append(keys, chainKey{chainType, hash})
+	}
+	blocks, err := req.chainBlock.loadMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*chainBlockResolver, len(blocks))
+	for i, b := range blocks {
+		resolvers[i] = newChainBlockResolver(req, chainType, b)
+	}
+	return resolvers, nil
+}
+
+// ChainTransaction resolves the transaction of a chain with the txid.
+func (r *resolver) ChainTransaction(ctx context.Context, args struct {
+	Chain string
+	Txid  string
+}) (*chainTxResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	return requestFromContext(ctx).loadChainTx(ctx, chainType, args.Txid)
+}
+
+// ChainAddress resolves the totals and the transactions of a BTC or LTC
+// address.
+func (r *resolver) ChainAddress(ctx context.Context, args struct {
+	Chain   string
+	Address string
+}) (*chainAddressResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	if chainType == mutilchain.TYPEXMR {
+		return nil, errors.New("XMR addresses are not indexed")
+	}
+	if !r.s.ds.IsMutilchainValidAddress(chainType, args.Address) {
+		return nil, errors.New("invalid address")
+	}
+	req := requestFromContext(ctx)
+	if err = req.charge(1); err != nil {
+		return nil, err
+	}
+	totals, err := r.s.ds.MutilchainAddressTotals(args.Address, chainType)
+	if err != nil {
+		log.Errorf("Unable to get the totals of %s address %s: %v", chainType, args.Address, err)
+		return nil, errors.New("unable to get the address")
+	}
+	if totals == nil {
+		return nil, nil
+	}
+	return &chainAddressResolver{req, chainType, totals}, nil
+}
+
+// loadChainBlock loads a block of a chain, which costs one.
+func (req *request) loadChainBlock(ctx context.Context, chainType, hash string) (*chainBlockResolver, error) {
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	b, err := req.chainBlock.load(ctx, chainKey{chainType, hash})
+	return newChainBlockResolver(req, chainType, b), err
+}
+
+// loadChainTx loads a transaction of a chain, which costs one.
+func (req *request) loadChainTx(ctx context.Context, chainType, txid string) (*chainTxResolver, error) {
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	tx, err := req.chainTx.load(ctx, chainKey{chainType, txid})
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return &chainTxResolver{req, chainType, tx}, nil
+}
+
+type chainBlockResolver struct {
+	req   *request
+	chain string
+	b     *apitypes.ChainBlockSummary
+}
+
+// newChainBlockResolver returns the resolver of a block of a chain, or nil if
+// the block is nil.
+func newChainBlockResolver(req *request, chainType string, b *apitypes.ChainBlockSummary) *chainBlockResolver {
+	if b == nil {
+		return nil
+	}
+	return &chainBlockResolver{req, chainType, b}
+}
+
+func (b *chainBlockResolver) Chain() string        { return chainEnum(b.chain) }
+func (b *chainBlockResolver) Height() Int64        { return Int64(b.b.Height) }
+func (b *chainBlockResolver) Hash() string         { return b.b.Hash }
+func (b *chainBlockResolver) Version() int32       { return b.b.Version }
+func (b *chainBlockResolver) Size() Int64          { return Int64(b.b.Size) }
+func (b *chainBlockResolver) Time() Int64          { return Int64(b.b.Time) }
+func (b *chainBlockResolver) NumTx() int32         { return int32(b.b.NumTx) }
+func (b *chainBlockResolver) Difficulty() float64  { return b.b.Difficulty }
+func (b *chainBlockResolver) Nonce() Int64         { return Int64(b.b.Nonce) }
+func (b *chainBlockResolver) Confirmations() Int64 { return Int64(b.b.Confirmations) }
+func (b *chainBlockResolver) PreviousHash() string { return b.b.PreviousHash }
+
+func (b *chainBlockResolver) NextHash() *string {
+	if b.b.NextHash == "" {
+		return nil
+	}
+	return &b.b.NextHash
+}
+
+func (b *chainBlockResolver) Transactions(ctx context.Context, args pageArgs) ([]*chainTxResolver, error) {
+	first, skip, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	txids, err := b.req.chainTxids.load(ctx, chainKey{b.chain, b.b.Hash})
+	if err != nil {
+		return nil, err
+	}
+	txids = pageOf(txids, first, skip)
+	if err = b.req.charge(len(txids)); err != nil {
+		return nil, err
+	}
+	keys := make([]chainKey, len(txids))
+	for i, txid := range txids {
+		keys[i] = chainKey{b.chain, txid}
+	}
+	txns, err := b.req.chainTx.loadMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*chainTxResolver, len(txns))
+	for i, tx := range txns {
+		if tx != nil {
+			resolvers[i] = &chainTxResolver{b.req, b.chain, tx}
+		}
+	}
+	return resolvers, nil
+}
+
+type chainTxResolver struct {
+	req   *request
+	chain string
+	tx    *apitypes.ChainTx
+}
+
+func (t *chainTxResolver) Chain() string        { return chainEnum(t.chain) }
+func (t *chainTxResolver) Txid() string         { return t.tx.TxID }
+func (t *chainTxResolver) Version() int32       { return t.tx.Version }
+func (t *chainTxResolver) Size() Int64          { return Int64(t.tx.Size) }
+func (t *chainTxResolver) LockTime() Int64      { return Int64(t.tx.LockTime) }
+func (t *chainTxResolver) Coinbase() bool       { return t.tx.Coinbase }
+func (t *chainTxResolver) Fee() *Int64          { return int64Ptr(t.tx.Fee) }
+func (t *chainTxResolver) Confirmations() Int64 { return Int64(t.tx.Confirmations) }
+
+func (t *chainTxResolver) Time() *Int64 {
+	if t.tx.Time == 0 {
+		return nil
+	}
+	tm := Int64(t.tx.Time)
+	return &tm
+}
+
+func (t *chainTxResolver) Block(ctx context.Context) (*chainBlockResolver, error) {
+	if t.tx.BlockHash == "" {
+		return nil, nil
+	}
+	return t.req.loadChainBlock(ctx, t.chain, t.tx.BlockHash)
+}
+
+func (t *chainTxResolver) Inputs() []*chainTxInputResolver {
+	inputs := make([]*chainTxInputResolver, len(t.tx.Vin))
+	for i := range t.tx.Vin {
+		inputs[i] = &chainTxInputResolver{t.req, t.chain, &t.tx.Vin[i]}
+	}
+	return inputs
+}
+
+func (t *chainTxResolver) Outputs() []*chainTxOutputResolver {
+	outputs := make([]*chainTxOutputResolver, len(t.tx.Vout))
+	for i := range t.tx.Vout {
+		outputs[i] = &chainTxOutputResolver{&t.tx.Vout[i]}
+	}
+	return outputs
+}
+
+type chainTxInputResolver struct {
+	req   *request
+	chain string
+	in    *apitypes.ChainTxIn
+}
+
+func (in *chainTxInputResolver) Index() Int64    { return Int64(in.in.Index) }
+func (in *chainTxInputResolver) Coinbase() bool  { return in.in.Coinbase }
+func (in *chainTxInputResolver) PrevVout() Int64 { return Int64(in.in.PrevVout) }
+func (in *chainTxInputResolver) Amount() Int64   { return Int64(in.in.Amount) }
+func (in *chainTxInputResolver) Sequence() Int64 { return Int64(in.in.Sequence) }
+func (in *chainTxInputResolver) RingSize() int32 { return int32(in.in.RingSize) }
+
+func (in *chainTxInputResolver) PrevTxid() *string { return optString(in.in.PrevTxID) }
+func (in *chainTxInputResolver) Address() *string  { return optString(in.in.Address) }
+func (in *chainTxInputResolver) KeyImage() *string { return optString(in.in.KeyImage) }
+
+func (in *chainTxInputResolver) PreviousTransaction(ctx context.Context) (*chainTxResolver, error) {
+	if in.in.Coinbase || in.in.PrevTxID == "" {
+		return nil, nil
+	}
+	return in.req.loadChainTx(ctx, in.chain, in.in.PrevTxID)
+}
+
+type chainTxOutputResolver struct {
+	out *apitypes.ChainTxOut
+}
+
+func (out *chainTxOutputResolver) Index() Int64    { return Int64(out.out.Index) }
+func (out *chainTxOutputResolver) Amount() Int64   { return Int64(out.out.Amount) }
+func (out *chainTxOutputResolver) Type() *string   { return optString(out.out.Type) }
+func (out *chainTxOutputResolver) Script() *string { return optString(out.out.PkScript) }
+func (out *chainTxOutputResolver) Key() *string    { return optString(out.out.Key) }
+
+func (out *chainTxOutputResolver) Addresses() []string {
+	if out.out.Addresses == nil {
+		return []string{}
+	}
+	return out.out.Addresses
+}
+
+// optString returns nil for an empty string.
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+type chainAddressResolver struct {
+	req    *request
+	chain  string
+	totals *apitypes.ChainAddressTotals
+}
+
+func (a *chainAddressResolver) Chain() string     { return chainEnum(a.chain) }
+func (a *chainAddressResolver) Address() string   { return a.totals.Address }
+func (a *chainAddressResolver) NumSpent() Int64   { return Int64(a.totals.NumSpent) }
+func (a *chainAddressResolver) NumUnspent() Int64 { return Int64(a.totals.NumUnspent) }
+func (a *chainAddressResolver) Received() Int64   { return Int64(a.totals.TotalReceived) }
+func (a *chainAddressResolver) Spent() Int64      { return Int64(a.totals.TotalSpent) }
+func (a *chainAddressResolver) Balance() Int64    { return Int64(a.totals.Balance) }
+
+func (a *chainAddressResolver) Transactions(ctx context.Context, args pageArgs) ([]*chainAddressTxResolver, error) {
+	first, skip, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	if err = a.req.charge(first); err != nil {
+		return nil, err
+	}
+	addr, err := a.req.s.ds.MutilchainAddressTransactionDetails(a.totals.Address, a.chain,
+		int64(first), int64(skip), dbtypes.AddrTxnAll)
+	if err != nil {
+		log.Errorf("Unable to get the transactions of %s address %s: %v", a.chain, a.totals.Address, err)
+		return nil, errors.New("unable to get the address transactions")
+	}
+	resolvers := make([]*chainAddressTxResolver, len(addr.Transactions))
+	for i, tx := range addr.Transactions {
+		resolvers[i] = &chainAddressTxResolver{a.req, a.chain, tx}
+	}
+	return resolvers, nil
+}
+
+type chainAddressTxResolver struct {
+	req   *request
+	chain string
+	tx    *apitypes.AddressTxShort
+}
+
+func (t *chainAddressTxResolver) Txid() string         { return t.tx.TxID }
+func (t *chainAddressTxResolver) Size() int32          { return t.tx.Size }
+func (t *chainAddressTxResolver) Time() Int64          { return Int64(t.tx.Time.UNIX()) }
+func (t *chainAddressTxResolver) Value() float64       { return t.tx.Value }
+func (t *chainAddressTxResolver) Confirmations() Int64 { return Int64(t.tx.Confirmations) }
+
+func (t *chainAddressTxResolver) Transaction(ctx context.Context) (*chainTxResolver, error) {
+	return t.req.loadChainTx(ctx, t.chain, t.tx.TxID)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// loaderWait is how long a loader collects the keys of the resolvers that
+	// run in parallel before it fetches them in one batch.
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch is the maximum number of keys of a batch.
+	loaderMaxBatch = 100
+	// fetchConcurrency is the maximum number of concurrent fetches of a batch
+	// whose keys are fetched one at a time, e.g. from a node RPC.
+	fetchConcurrency = 8
+)
+
+// batchFunc fetches the values of the keys. The values are in the order of
+// the keys, with the zero value for the keys that are not found. An error
+// fails every key of the batch.
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) ([]V, error)
+
+// loaderResult is the value of a key, which is ready when done is closed.
+type loaderResult[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// loader batches the loads of the resolvers of a GraphQL request, and
+// memoizes the values for the lifetime of the request. The keys requested
+// within loaderWait of the first key of a batch, or until the batch has
+// loaderMaxBatch keys, are fetched with a single call of the batch function.
+type loader[K comparable, V any] struct {
+	fetch    batchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mtx     sync.Mutex
+	results map[K]*loaderResult[V]
+	batch   []K
+	timer   *time.Timer
+}
+
+func newLoader[K comparable, V any](fetch batchFunc[K, V]) *loader[K, V] {
+	return &loader[K, V]{
+		fetch:    fetch,
+		wait:     loaderWait,
+		maxBatch: loaderMaxBatch,
+		results:  make(map[K]*loaderResult[V]),
+	}
+}
+
+// load returns the value of the key, waiting for the batch of the key to be
+// fetched.
+func (l *loader[K, V]) load(ctx context.Context, key K) (V, error) {
+	l.mtx.Lock()
+	res, ok := l.results[key]
+	if !ok {
+		res = &loaderResult[V]{done: make(chan struct{})}
+		l.results[key] = res
+		l.batch = append(l.batch, key)
+		switch {
+		case len(l.batch) >= l.maxBatch:
+			l.dispatchLocked(ctx)
+		case l.timer == nil:
+			l.timer = time.AfterFunc(l.wait, func() {
+				l.mtx.Lock()
+				defer l.mtx.Unlock()
+				l.dispatchLocked(ctx)
+			})
+		}
+	}
+	l.mtx.Unlock()
+
+	select {
+	case <-res.done:
+		return res.val, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatchLocked fetches the pending batch in a new goroutine. The loader
+// mutex must be held.
+func (l *loader[K, V]) dispatchLocked(ctx context.Context) {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.batch) == 0 {
+		return
+	}
+	keys := l.batch
+	l.batch = nil
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.results[key]
+	}
+
+	go func() {
+		vals, err := l.fetch(ctx, keys)
+		for i, res := range results {
+			if err != nil {
+				res.err = err
+			} else if i < len(vals) {
+				res.val = vals[i]
+			}
+			close(res.done)
+		}
+	}()
+}
+
+// fetchEach returns a batchFunc that fetches the keys one at a time with at
+// most fetchConcurrency concurrent calls of fetch. The first error fails the
+// batch.
+func fetchEach[K comparable, V any](fetch func(ctx context.Context, key K) (V, error)) batchFunc[K, V] {
+	return func(ctx context.Context, keys []K) ([]V, error) {
+		vals := make([]V, len(keys))
+		errs := make([]error, len(keys))
+		sem := make(chan struct{}, fetchConcurrency)
+		var wg sync.WaitGroup
+		for i, key := range keys {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					return
+				}
+				vals[i], errs[i] = fetch(ctx, key)
+			}()
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+		return vals, nil
+	}
+}
+
+// loadMany loads the values of the keys, which are fetched in the same batches.
+func (l *loader[K, V]) loadMany(ctx context.Context, keys []K) ([]V, error) {
+	vals := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i], errs[i] = l.load(ctx, key)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+)
+
+// maxQueryCost is the default cost budget of a request. Each object that is
+// loaded costs one, so the budget bounds the work of a query regardless of
+// how its list fields are nested.
+const maxQueryCost = 2000
+
+// errBudgetExceeded is the error of the resolvers that exceed the cost budget
+// of the request.
+type errBudgetExceeded int64
+
+func (e errBudgetExceeded) Error() string {
+	return fmt.Sprintf("query exceeds the complexity limit of %d objects", int64(e))
+}
+
+// chainKey identifies a block or transaction of a BTC, LTC or XMR chain.
+type chainKey struct {
+	chain string
+	id    string
+}
+
+// request is the state of a GraphQL request: the cost budget and the loaders,
+// whose values are memoized for the lifetime of the request. Each event of a
+// subscription is a new request.
+type request struct {
+	s       *Server
+	cost    atomic.Int64
+	maxCost int64
+
+	blockByHeight *loader[int64, *apitypes.BlockDataBasic]
+	blockByHash   *loader[string, *apitypes.BlockDataBasic]
+	blockTxns     *loader[string, *apitypes.BlockTransactions]
+	tx            *loader[string, *apitypes.Tx]
+	chainBlock    *loader[chainKey, *apitypes.ChainBlockSummary]
+	chainTxids    *loader[chainKey, []string]
+	chainTx       *loader[chainKey, *apitypes.ChainTx]
+}
+
+func (s *Server) newRequest() *request {
+	ds := s.ds
+	return &request{
+		s:       s,
+		maxCost: s.maxCost,
+		blockByHeight: newLoader(func(_ context.Context, heights []int64) ([]*apitypes.BlockDataBasic, error) {
+			return s.blocksByHeight(heights), nil
+		}),
+		blockByHash: newLoader(fetchEach(func(_ context.Context, hash string) (*apitypes.BlockDataBasic, error) {
+			return ds.GetSummaryByHash(hash, false), nil
+		})),
+		blockTxns: newLoader(fetchEach(func(_ context.Context, hash string) (*apitypes.BlockTransactions, error) {
+			return ds.GetTransactionsForBlockByHash(hash), nil
+		})),
+		tx: newLoader(fetchEach(func(_ context.Context, txid string) (*apitypes.Tx, error) {
+			hash, err := chainhash.NewHashFromStr(txid)
+			if err != nil {
+				return nil, nil
+			}
+			return ds.GetAPITransaction(hash), nil
+		})),
+		// As with the DCR blocks and transactions, the blocks and transactions
+		// of the other chains that cannot be retrieved are null.
+		chainBlock: newLoader(fetchEach(func(_ context.Context, k chainKey) (*apitypes.ChainBlockSummary, error) {
+			block, err := ds.MutilchainBlockSummary(k.id, k.chain)
+			if err != nil {
+				log.Debugf("Unable to get %s block %s: %v", k.chain, k.id, err)
+			}
+			return block, nil
+		})),
+		chainTxids: newLoader(fetchEach(func(_ context.Context, k chainKey) ([]string, error) {
+			txids, err := ds.MutilchainBlockTxids(k.id, k.chain)
+			if err != nil {
+				log.Debugf("Unable to get the transactions of %s block %s: %v", k.chain, k.id, err)
+			}
+			return txids, nil
+		})),
+		chainTx: newLoader(fetchEach(func(_ context.Context, k chainKey) (*apitypes.ChainTx, error) {
+			tx, err := ds.MutilchainTx(k.id, k.chain)
+			if err != nil {
+				log.Debugf("Unable to get %s transaction %s: %v", k.chain, k.id, err)
+			}
+			return tx, nil
+		})),
+	}
+}
+
+// charge adds n objects to the cost of the request, and fails once the cost
+// exceeds the budget.
+func (req *request) charge(n int) error {
+	if req.cost.Add(int64(n)) > req.maxCost {
+		return errBudgetExceeded(req.maxCost)
+	}
+	return nil
+}
+
+type ctxKey struct{}
+
+// requestFromContext returns the request of a query, which is set by the HTTP
+// handler.
+func requestFromContext(ctx context.Context) *request {
+	req, _ := ctx.Value(ctxKey{}).(*request)
+	return req
+}
+
+// blocksByHeight returns the summaries of the blocks at the heights. The
+// contiguous runs of heights are fetched with one query each, and the single
+// heights from the block summary cache.
+func (s *Server) blocksByHeight(heights []int64) []*apitypes.BlockDataBasic {
+	byHeight := make(map[int64]*apitypes.BlockDataBasic, len(heights))
+	sorted := sortedUnique(heights)
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if j == i {
+			if bd := s.ds.GetSummary(int(sorted[i])); bd != nil {
+				byHeight[sorted[i]] = bd
+			}
+		} else {
+			for _, bd := range s.ds.GetSummaryRange(int(sorted[i]), int(sorted[j])) {
+				byHeight[int64(bd.Height)] = bd
+			}
+		}
+		i = j + 1
+	}
+	blocks := make([]*apitypes.BlockDataBasic, len(heights))
+	for i, height := range heights {
+		blocks[i] = byHeight[height]
+	}
+	return blocks
+}
+
+func sortedUnique(heights []int64) []int64 {
+	sorted := slices.Clone(heights)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
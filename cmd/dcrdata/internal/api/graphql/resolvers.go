@@ -0,0 +1,649 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	recordsv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	ticketvotev1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+
+	pitypes "github.com/decred/dcrdata/gov/v6/politeia/types"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// resolver is the root resolver of the queries and the subscriptions.
+type resolver struct {
+	s *Server
+}
+
+// pageArgs are the arguments of the paginated list fields, which have
+// defaults in the schema.
+type pageArgs struct {
+	First int32
+	Skip  int32
+}
+
+// page returns the validated first and skip arguments.
+func (a pageArgs) page() (first, skip int, err error) {
+	first, skip = int(a.First), int(a.Skip)
+	if first < 0 || first > maxListSize {
+		return 0, 0, errors.New("first must be from 0 to 100")
+	}
+	if skip < 0 {
+		return 0, 0, errors.New("skip must not be negative")
+	}
+	return first, skip, nil
+}
+
+// pageOf returns the page of the items.
+func pageOf[T any](items []T, first, skip int) []T {
+	if skip >= len(items) {
+		return nil
+	}
+	items = items[skip:]
+	if first < len(items) {
+		items = items[:first]
+	}
+	return items
+}
+
+// heightRange checks that a range of heights has at most maxListSize heights.
+func heightRange(from, to int64) error {
+	if from < 0 || to < from {
+		return errors.New("invalid height range")
+	}
+	if to-from >= maxListSize {
+		return errors.New("the height range exceeds 100 blocks")
+	}
+	return nil
+}
+
+func int64Ptr(v *int64) *Int64 {
+	if v == nil {
+		return nil
+	}
+	i := Int64(*v)
+	return &i
+}
+
+// Block resolves the block with the height or the hash, or the best block.
+func (r *resolver) Block(ctx context.Context, args struct {
+	Height *int32
+	Hash   *string
+}) (*blockResolver, error) {
+	req := requestFromContext(ctx)
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	var bd *apitypes.BlockDataBasic
+	var err error
+	switch {
+	case args.Hash != nil:
+		bd, err = req.blockByHash.load(ctx, *args.Hash)
+	case args.Height != nil:
+		bd, err = req.blockByHeight.load(ctx, int64(*args.Height))
+	default:
+		bd = r.s.ds.GetBestBlockSummary()
+	}
+	return newBlockResolver(req, bd), err
+}
+
+// Blocks resolves the blocks of a range of heights.
+func (r *resolver) Blocks(ctx context.Context, args struct {
+	From int32
+	To   int32
+}) ([]*blockResolver, error) {
+	if err := heightRange(int64(args.From), int64(args.To)); err != nil {
+		return nil, err
+	}
+	req := requestFromContext(ctx)
+	heights := make([]int64, 0, args.To-args.From+1)
+	for h := args.From; h <= args.To; h++ {
+		heights = append(heights, int64(h))
+	}
+	if err := req.charge(len(heights)); err != nil {
+		return nil, err
+	}
+	blocks, err := req.blockByHeight.loadMany(ctx, heights)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*blockResolver, len(blocks))
+	for i, bd := range blocks {
+		resolvers[i] = newBlockResolver(req, bd)
+	}
+	return resolvers, nil
+}
+
+// Transaction resolves the transaction with the txid.
+func (r *resolver) Transaction(ctx context.Context, args struct{ Txid string }) (*txResolver, error) {
+	return requestFromContext(ctx).loadTx(ctx, args.Txid)
+}
+
+// Address resolves the totals and the transactions of an address.
+func (r *resolver) Address(ctx context.Context, args struct{ Address string }) (*addressResolver, error) {
+	if _, err := stdaddr.DecodeAddress(args.Address, r.s.params); err != nil {
+		return nil, errors.New("invalid address")
+	}
+	req := requestFromContext(ctx)
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	totals, err := r.s.ds.AddressTotals(args.Address)
+	if err != nil {
+		log.Errorf("Unable to get the totals of address %s: %v", args.Address, err)
+		return nil, errors.New("unable to get the address")
+	}
+	if totals == nil {
+		return nil, nil
+	}
+	return &addressResolver{req, totals}, nil
+}
+
+// Ticket resolves the status of a ticket.
+func (r *resolver) Ticket(ctx context.Context, args struct{ Txid string }) (*ticketResolver, error) {
+	req := requestFromContext(ctx)
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	ti, err := r.s.ds.GetTicketInfo(args.Txid)
+	if err != nil {
+		log.Debugf("Unable to get ticket %s: %v", args.Txid, err)
+		return nil, nil
+	}
+	return &ticketResolver{req, args.Txid, ti}, nil
+}
+
+// Vote resolves the block validation and the choices of a vote.
+func (r *resolver) Vote(ctx context.Context, args struct{ Txid string }) (*voteResolver, error) {
+	hash, err := chainhash.NewHashFromStr(args.Txid)
+	if err != nil {
+		return nil, errors.New("invalid txid")
+	}
+	req := requestFromContext(ctx)
+	if err = req.charge(1); err != nil {
+		return nil, err
+	}
+	vi, err := r.s.ds.GetVoteInfo(hash)
+	if err != nil || vi == nil {
+		log.Debugf("Unable to get vote %s: %v", args.Txid, err)
+		return nil, nil
+	}
+	return &voteResolver{req, args.Txid, vi}, nil
+}
+
+// Treasury resolves the treasury balance.
+func (r *resolver) Treasury(ctx context.Context) (*treasuryResolver, error) {
+	if err := requestFromContext(ctx).charge(1); err != nil {
+		return nil, err
+	}
+	tb, err := r.s.ds.TreasuryBalance()
+	if err != nil {
+		log.Errorf("Unable to get the treasury balance: %v", err)
+		return nil, errors.New("unable to get the treasury balance")
+	}
+	return &treasuryResolver{tb}, nil
+}
+
+// Proposal resolves the proposal with the token.
+func (r *resolver) Proposal(ctx context.Context, args struct{ Token string }) (*proposalResolver, error) {
+	if r.s.proposals == nil {
+		return nil, errors.New("proposals are disabled")
+	}
+	req := requestFromContext(ctx)
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	p, err := r.s.proposals.ProposalByToken(args.Token)
+	if err != nil {
+		log.Debugf("Unable to get proposal %s: %v", args.Token, err)
+		return nil, nil
+	}
+	return &proposalResolver{req, p}, nil
+}
+
+// Proposals resolves a page of the proposals.
+func (r *resolver) Proposals(ctx context.Context, args pageArgs) ([]*proposalResolver, error) {
+	if r.s.proposals == nil {
+		return nil, errors.New("proposals are disabled")
+	}
+	first, skip, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	req := requestFromContext(ctx)
+	if err = req.charge(first); err != nil {
+		return nil, err
+	}
+	proposals, _, err := r.s.proposals.ProposalsAll(skip, first)
+	if err != nil {
+		log.Errorf("Unable to get the proposals: %v", err)
+		return nil, errors.New("unable to get the proposals")
+	}
+	resolvers := make([]*proposalResolver, len(proposals))
+	for i, p := range proposals {
+		resolvers[i] = &proposalResolver{req, p}
+	}
+	return resolvers, nil
+}
+
+// loadTx loads a transaction, which costs one.
+func (req *request) loadTx(ctx context.Context, txid string) (*txResolver, error) {
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	tx, err := req.tx.load(ctx, txid)
+	if err != nil || tx == nil {
+		return nil, err
+	}
+	return &txResolver{req, tx}, nil
+}
+
+// loadTxs loads the transactions, which cost one each.
+func (req *request) loadTxs(ctx context.Context, txids []string) ([]*txResolver, error) {
+	if err := req.charge(len(txids)); err != nil {
+		return nil, err
+	}
+	txns, err := req.tx.loadMany(ctx, txids)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*txResolver, len(txns))
+	for i, tx := range txns {
+		if tx != nil {
+			resolvers[i] = &txResolver{req, tx}
+		}
+	}
+	return resolvers, nil
+}
+
+// loadBlockByHash loads a block, which costs one.
+func (req *request) loadBlockByHash(ctx context.Context, hash string) (*blockResolver, error) {
+	if err := req.charge(1); err != nil {
+		return nil, err
+	}
+	bd, err := req.blockByHash.load(ctx, hash)
+	return newBlockResolver(req, bd), err
+}
+
+type blockResolver struct {
+	req *request
+	b   *apitypes.BlockDataBasic
+}
+
+// newBlockResolver returns the resolver of a block, or nil if the block is
+// nil.
+func newBlockResolver(req *request, b *apitypes.BlockDataBasic) *blockResolver {
+	if b == nil {
+		return nil
+	}
+	return &blockResolver{req, b}
+}
+
+func (b *blockResolver) Height() int32            { return int32(b.b.Height) }
+func (b *blockResolver) Hash() string             { return b.b.Hash }
+func (b *blockResolver) Size() int32              { return int32(b.b.Size) }
+func (b *blockResolver) Time() Int64              { return Int64(b.b.Time.UNIX()) }
+func (b *blockResolver) Difficulty() float64      { return b.b.Difficulty }
+func (b *blockResolver) StakeDifficulty() float64 { return b.b.StakeDiff }
+func (b *blockResolver) NumTx() int32             { return int32(b.b.NumTx) }
+func (b *blockResolver) Fees() *Int64             { return int64Ptr(b.b.MiningFee) }
+func (b *blockResolver) TotalSent() *Int64        { return int64Ptr(b.b.TotalSent) }
+
+func (b *blockResolver) TicketPoolSize() *int32 {
+	if b.b.PoolInfo == nil {
+		return nil
+	}
+	size := int32(b.b.PoolInfo.Size)
+	return &size
+}
+
+func (b *blockResolver) TicketPoolValue() *float64 {
+	if b.b.PoolInfo == nil {
+		return nil
+	}
+	return &b.b.PoolInfo.Value
+}
+
+func (b *blockResolver) Transactions(ctx context.Context, args pageArgs) ([]*txResolver, error) {
+	return b.transactions(ctx, args, false)
+}
+
+func (b *blockResolver) StakeTransactions(ctx context.Context, args pageArgs) ([]*txResolver, error) {
+	return b.transactions(ctx, args, true)
+}
+
+func (b *blockResolver) transactions(ctx context.Context, args pageArgs, stake bool) ([]*txResolver, error) {
+	first, skip, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	txns, err := b.req.blockTxns.load(ctx, b.b.Hash)
+	if err != nil || txns == nil {
+		return nil, err
+	}
+	txids := txns.Tx
+	if stake {
+		txids = txns.STx
+	}
+	return b.req.loadTxs(ctx, pageOf(txids, first, skip))
+}
+
+type txResolver struct {
+	req *request
+	tx  *apitypes.Tx
+}
+
+func (t *txResolver) Txid() string         { return t.tx.TxID }
+func (t *txResolver) Type() string         { return t.tx.Type }
+func (t *txResolver) Tree() int32          { return int32(t.tx.Tree) }
+func (t *txResolver) Size() int32          { return t.tx.Size }
+func (t *txResolver) Version() int32       { return t.tx.Version }
+func (t *txResolver) LockTime() Int64      { return Int64(t.tx.Locktime) }
+func (t *txResolver) Expiry() Int64        { return Int64(t.tx.Expiry) }
+func (t *txResolver) Confirmations() Int64 { return Int64(t.tx.Confirmations) }
+
+func (t *txResolver) Block(ctx context.Context) (*blockResolver, error) {
+	if t.tx.Block == nil || t.tx.Block.BlockHash == "" {
+		return nil, nil
+	}
+	return t.req.loadBlockByHash(ctx, t.tx.Block.BlockHash)
+}
+
+func (t *txResolver) BlockIndex() *int32 {
+	if t.tx.Block == nil || t.tx.Block.BlockHash == "" {
+		return nil
+	}
+	idx := int32(t.tx.Block.BlockIndex)
+	return &idx
+}
+
+func (t *txResolver) Inputs() []*txInputResolver {
+	inputs := make([]*txInputResolver, len(t.tx.Vin))
+	for i := range t.tx.Vin {
+		inputs[i] = &txInputResolver{t.req, &t.tx.Vin[i]}
+	}
+	return inputs
+}
+
+func (t *txResolver) Outputs() []*txOutputResolver {
+	outputs := make([]*txOutputResolver, len(t.tx.Vout))
+	for i := range t.tx.Vout {
+		outputs[i] = &txOutputResolver{&t.tx.Vout[i]}
+	}
+	return outputs
+}
+
+type txInputResolver struct {
+	req *request
+	in  *apitypes.Vin
+}
+
+func (in *txInputResolver) Coinbase() bool      { return in.in.Coinbase != "" }
+func (in *txInputResolver) Stakebase() bool     { return in.in.Stakebase != "" }
+func (in *txInputResolver) Treasurybase() bool  { return in.in.Treasurybase }
+func (in *txInputResolver) TreasurySpend() bool { return in.in.TreasurySpend != "" }
+func (in *txInputResolver) AmountIn() float64   { return in.in.AmountIn }
+func (in *txInputResolver) BlockHeight() Int64  { return Int64(in.in.BlockHeight) }
+func (in *txInputResolver) Sequence() Int64     { return Int64(in.in.Sequence) }
+
+// hasPrevOut checks if the input spends a previous output.
+func (in *txInputResolver) hasPrevOut() bool {
+	return in.in.Txid != ""
+}
+
+func (in *txInputResolver) Txid() *string {
+	if !in.hasPrevOut() {
+		return nil
+	}
+	return &in.in.Txid
+}
+
+func (in *txInputResolver) Vout() *Int64 {
+	if !in.hasPrevOut() {
+		return nil
+	}
+	vout := Int64(in.in.Vout)
+	return &vout
+}
+
+func (in *txInputResolver) Tree() *int32 {
+	if !in.hasPrevOut() {
+		return nil
+	}
+	tree := int32(in.in.Tree)
+	return &tree
+}
+
+func (in *txInputResolver) PreviousTransaction(ctx context.Context) (*txResolver, error) {
+	if !in.hasPrevOut() {
+		return nil, nil
+	}
+	return in.req.loadTx(ctx, in.in.Txid)
+}
+
+type txOutputResolver struct {
+	out *apitypes.Vout
+}
+
+func (out *txOutputResolver) Index() Int64   { return Int64(out.out.N) }
+func (out *txOutputResolver) Value() float64 { return out.out.Value }
+func (out *txOutputResolver) Version() int32 { return int32(out.out.Version) }
+func (out *txOutputResolver) Type() string   { return out.out.ScriptPubKeyDecoded.Type }
+func (out *txOutputResolver) Script() string { return out.out.ScriptPubKeyDecoded.Hex }
+
+func (out *txOutputResolver) Addresses() []string {
+	if out.out.ScriptPubKeyDecoded.Addresses == nil {
+		return []string{}
+	}
+	return out.out.ScriptPubKeyDecoded.Addresses
+}
+
+type addressResolver struct {
+	req    *request
+	totals *apitypes.AddressTotals
+}
+
+func (a *addressResolver) Address() string   { return a.totals.Address }
+func (a *addressResolver) NumSpent() Int64   { return Int64(a.totals.NumSpent) }
+func (a *addressResolver) NumUnspent() Int64 { return Int64(a.totals.NumUnspent) }
+func (a *addressResolver) Spent() float64    { return a.totals.CoinsSpent }
+func (a *addressResolver) Unspent() float64  { return a.totals.CoinsUnspent }
+
+func (a *addressResolver) Transactions(ctx context.Context, args pageArgs) ([]*addressTxResolver, error) {
+	first, skip, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+	if err = a.req.charge(first); err != nil {
+		return nil, err
+	}
+	addr, err := a.req.s.ds.AddressTransactionDetails(a.totals.Address, int64(first),
+		int64(skip), dbtypes.AddrTxnAll)
+	if err != nil {
+		log.Errorf("Unable to get the transactions of address %s: %v", a.totals.Address, err)
+		return nil, errors.New("unable to get the address transactions")
+	}
+	resolvers := make([]*addressTxResolver, len(addr.Transactions))
+	for i, tx := range addr.Transactions {
+		resolvers[i] = &addressTxResolver{a.req, tx}
+	}
+	return resolvers, nil
+}
+
+type addressTxResolver struct {
+	req *request
+	tx  *apitypes.AddressTxShort
+}
+
+func (t *addressTxResolver) Txid() string         { return t.tx.TxID }
+func (t *addressTxResolver) Size() int32          { return t.tx.Size }
+func (t *addressTxResolver) Time() Int64          { return Int64(t.tx.Time.UNIX()) }
+func (t *addressTxResolver) Value() float64       { return t.tx.Value }
+func (t *addressTxResolver) Confirmations() Int64 { return Int64(t.tx.Confirmations) }
+
+func (t *addressTxResolver) Transaction(ctx context.Context) (*txResolver, error) {
+	return t.req.loadTx(ctx, t.tx.TxID)
+}
+
+type ticketResolver struct {
+	req  *request
+	txid string
+	ti   *apitypes.TicketInfo
+}
+
+func (t *ticketResolver) Txid() string            { return t.txid }
+func (t *ticketResolver) Status() string          { return t.ti.Status }
+func (t *ticketResolver) MaturityHeight() Int64   { return Int64(t.ti.MaturityHeight) }
+func (t *ticketResolver) ExpirationHeight() Int64 { return Int64(t.ti.ExpirationHeight) }
+
+func (t *ticketResolver) PurchaseBlock(ctx context.Context) (*blockResolver, error) {
+	if t.ti.PurchaseBlock == nil {
+		return nil, nil
+	}
+	return t.req.loadBlockByHash(ctx, t.ti.PurchaseBlock.Hash)
+}
+
+func (t *ticketResolver) LotteryBlock(ctx context.Context) (*blockResolver, error) {
+	if t.ti.LotteryBlock == nil {
+		return nil, nil
+	}
+	return t.req.loadBlockByHash(ctx, t.ti.LotteryBlock.Hash)
+}
+
+func (t *ticketResolver) Vote(ctx context.Context) (*txResolver, error) {
+	if t.ti.Vote == nil {
+		return nil, nil
+	}
+	return t.req.loadTx(ctx, *t.ti.Vote)
+}
+
+func (t *ticketResolver) Revocation(ctx context.Context) (*txResolver, error) {
+	if t.ti.Revocation == nil {
+		return nil, nil
+	}
+	return t.req.loadTx(ctx, *t.ti.Revocation)
+}
+
+type voteResolver struct {
+	req  *request
+	txid string
+	vi   *apitypes.VoteInfo
+}
+
+func (v *voteResolver) Txid() string     { return v.txid }
+func (v *voteResolver) BlockValid() bool { return v.vi.Validation.Validity }
+func (v *voteResolver) Version() Int64   { return Int64(v.vi.Version) }
+func (v *voteResolver) Bits() int32      { return int32(v.vi.Bits) }
+
+func (v *voteResolver) Transaction(ctx context.Context) (*txResolver, error) {
+	return v.req.loadTx(ctx, v.txid)
+}
+
+func (v *voteResolver) ValidatedBlock(ctx context.Context) (*blockResolver, error) {
+	if v.vi.Validation.Hash == "" {
+		return nil, nil
+	}
+	return v.req.loadBlockByHash(ctx, v.vi.Validation.Hash)
+}
+
+func (v *voteResolver) Choices() []*voteChoiceResolver {
+	choices := make([]*voteChoiceResolver, 0, len(v.vi.Choices))
+	for _, c := range v.vi.Choices {
+		if c == nil || c.Choice == nil {
+			continue
+		}
+		choices = append(choices, &voteChoiceResolver{c.ID, c.Description, c.Choice.Id})
+	}
+	return choices
+}
+
+func (v *voteResolver) TspendVotes() []*tspendVoteResolver {
+	votes := make([]*tspendVoteResolver, len(v.vi.TSpends))
+	for i, tv := range v.vi.TSpends {
+		votes[i] = &tspendVoteResolver{tv}
+	}
+	return votes
+}
+
+type voteChoiceResolver struct {
+	agenda, description, choice string
+}
+
+func (c *voteChoiceResolver) Agenda() string      { return c.agenda }
+func (c *voteChoiceResolver) Description() string { return c.description }
+func (c *voteChoiceResolver) Choice() string      { return c.choice }
+
+type tspendVoteResolver struct {
+	v *apitypes.TSpendVote
+}
+
+func (v *tspendVoteResolver) Tspend() string { return v.v.TSpend }
+func (v *tspendVoteResolver) Choice() int32  { return int32(v.v.Choice) }
+
+type treasuryResolver struct {
+	tb *dbtypes.TreasuryBalance
+}
+
+func (t *treasuryResolver) Height() Int64         { return Int64(t.tb.Height) }
+func (t *treasuryResolver) MaturityHeight() Int64 { return Int64(t.tb.MaturityHeight) }
+func (t *treasuryResolver) Balance() Int64        { return Int64(t.tb.Balance) }
+func (t *treasuryResolver) OutputCount() Int64    { return Int64(t.tb.TxCount) }
+func (t *treasuryResolver) AddCount() Int64       { return Int64(t.tb.AddCount) }
+func (t *treasuryResolver) Added() Int64          { return Int64(t.tb.Added) }
+func (t *treasuryResolver) SpendCount() Int64     { return Int64(t.tb.SpendCount) }
+func (t *treasuryResolver) Spent() Int64          { return Int64(t.tb.Spent) }
+func (t *treasuryResolver) TbaseCount() Int64     { return Int64(t.tb.TBaseCount) }
+func (t *treasuryResolver) Tbase() Int64          { return Int64(t.tb.TBase) }
+func (t *treasuryResolver) ImmatureCount() Int64  { return Int64(t.tb.ImmatureCount) }
+func (t *treasuryResolver) Immature() Int64       { return Int64(t.tb.Immature) }
+
+type proposalResolver struct {
+	req *request
+	p   *pitypes.ProposalRecord
+}
+
+func (p *proposalResolver) Token() string           { return p.p.Token }
+func (p *proposalResolver) Name() string            { return p.p.Name }
+func (p *proposalResolver) Username() string        { return p.p.Username }
+func (p *proposalResolver) Status() string          { return recordsv1.RecordStatuses[p.p.Status] }
+func (p *proposalResolver) VoteStatus() string      { return ticketvotev1.VoteStatuses[p.p.VoteStatus] }
+func (p *proposalResolver) TotalVotes() Int64       { return Int64(p.p.TotalVotes) }
+func (p *proposalResolver) EligibleTickets() Int64  { return Int64(p.p.EligibleTickets) }
+func (p *proposalResolver) QuorumPercentage() int32 { return int32(p.p.QuorumPercentage) }
+func (p *proposalResolver) PassPercentage() int32   { return int32(p.p.PassPercentage) }
+func (p *proposalResolver) EndBlockHeight() Int64   { return Int64(p.p.EndBlockHeight) }
+func (p *proposalResolver) PublishedAt() Int64      { return Int64(p.p.PublishedAt) }
+
+func (p *proposalResolver) StartBlock(ctx context.Context) (*blockResolver, error) {
+	if p.p.StartBlockHeight == 0 {
+		return nil, nil
+	}
+	if err := p.req.charge(1); err != nil {
+		return nil, err
+	}
+	bd, err := p.req.blockByHeight.load(ctx, int64(p.p.StartBlockHeight))
+	return newBlockResolver(p.req, bd), err
+}
+
+func (p *proposalResolver) VoteResults() []*voteResultResolver {
+	results := make([]*voteResultResolver, len(p.p.VoteResults))
+	for i := range p.p.VoteResults {
+		results[i] = &voteResultResolver{&p.p.VoteResults[i]}
+	}
+	return results
+}
+
+type voteResultResolver struct {
+	r *ticketvotev1.VoteResult
+}
+
+func (r *voteResultResolver) ID() string          { return r.r.ID }
+func (r *voteResultResolver) Description() string { return r.r.Description }
+func (r *voteResultResolver) Votes() Int64        { return Int64(r.r.Votes) }
@@ -0,0 +1,48 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Int64 is the Int64 scalar of the schema. The Int scalar of GraphQL is a
+// 32-bit integer, which cannot hold the amounts in atoms or the block heights
+// of every chain.
+type Int64 int64
+
+// ImplementsGraphQLType maps Int64 to the Int64 scalar of the schema.
+func (Int64) ImplementsGraphQLType(name string) bool {
+	return name == "Int64"
+}
+
+// UnmarshalGraphQL decodes an Int64 argument, which may be an integer or a
+// decimal string.
+func (i *Int64) UnmarshalGraphQL(input any) error {
+	switch v := input.(type) {
+	case int32:
+		*i = Int64(v)
+	case int64:
+		*i = Int64(v)
+	case int:
+		*i = Int64(v)
+	case float64:
+		// Variables are decoded from JSON as float64.
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return fmt.Errorf("%v is not an Int64", v)
+		}
+		*i = Int64(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an Int64", v)
+		}
+		*i = Int64(n)
+	default:
+		return fmt.Errorf("%T is not an Int64", input)
+	}
+	return nil
+}
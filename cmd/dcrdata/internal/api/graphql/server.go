@@ -0,0 +1,276 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package graphql implements the GraphQL API over the blocks, transactions and
+// addresses of DCR and the other chains. Queries are served over HTTP GET and
+// POST, and subscriptions, which are backed by the pubsub hub, as
+// Server-Sent Events.
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	pitypes "github.com/decred/dcrdata/gov/v6/politeia/types"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/pubsub"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+const (
+	// maxQueryLength is the maximum length of a query document in bytes.
+	maxQueryLength = 8 << 10
+	// maxQueryDepth is the maximum nesting depth of the fields of a query.
+	maxQueryDepth = 10
+	// maxParallelism is the maximum number of resolvers of a request that run
+	// concurrently.
+	maxParallelism = 16
+	// maxListSize is the maximum number of items of a list argument, e.g.
+	// first, or of a height range.
+	maxListSize = 100
+	// eventTimeout is the time allowed to resolve each event of a
+	// subscription.
+	eventTimeout = 10 * time.Second
+	// writeTimeout is the write deadline of each event of a subscription
+	// stream, which replaces the write timeout of the server.
+	writeTimeout = 10 * time.Second
+)
+
+// DataSource is the interface for the DB, caches and node RPC clients that
+// back the resolvers.
+type DataSource interface {
+	GetSummary(idx int) *apitypes.BlockDataBasic
+	GetSummaryRange(idx0, idx1 int) []*apitypes.BlockDataBasic
+	GetSummaryByHash(hash string, withTxTotals bool) *apitypes.BlockDataBasic
+	GetBestBlockSummary() *apitypes.BlockDataBasic
+	GetTransactionsForBlockByHash(hash string) *apitypes.BlockTransactions
+	GetAPITransaction(txid *chainhash.Hash) *apitypes.Tx
+	GetTicketInfo(txid string) (*apitypes.TicketInfo, error)
+	GetVoteInfo(txid *chainhash.Hash) (*apitypes.VoteInfo, error)
+	TreasuryBalance() (*dbtypes.TreasuryBalance, error)
+	AddressTotals(address string) (*apitypes.AddressTotals, error)
+	AddressTransactionDetails(addr string, count, skip int64,
+		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
+	MutilchainBestBlock(chainType string) (int64, string, error)
+	MutilchainBlockHashAtHeight(height int64, chainType string) (string, error)
+	MutilchainBlockSummary(hash, chainType string) (*apitypes.ChainBlockSummary, error)
+	MutilchainBlockTxids(hash, chainType string) ([]string, error)
+	MutilchainTx(txid, chainType string) (*apitypes.ChainTx, error)
+	MutilchainAddressTotals(address, chainType string) (*apitypes.ChainAddressTotals, error)
+	MutilchainAddressTransactionDetails(addr, chainType string, count, skip int64,
+		txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error)
+	IsMutilchainValidAddress(chainType string, address string) bool
+}
+
+// ProposalsSource is the interface for the Politeia proposals DB.
+type ProposalsSource interface {
+	ProposalByToken(token string) (*pitypes.ProposalRecord, error)
+	ProposalsAll(offset, rowsCount int, filterByVoteStatus ...int) ([]*pitypes.ProposalRecord, int, error)
+}
+
+// Hub is the interface for the pubsub hub that backs the subscriptions.
+type Hub interface {
+	Subscribe(subs ...pstypes.HubMessage) (*pubsub.HubSubscription, error)
+}
+
+// Config is the configuration of the Server.
+type Config struct {
+	DataSource DataSource
+	// Proposals are disabled if ProposalsDB is nil.
+	ProposalsDB ProposalsSource
+	// Subscriptions are disabled if Hub is nil.
+	Hub    Hub
+	Params *chaincfg.Params
+	// ChainDisabled lists the disabled chains, by chain type.
+	ChainDisabled map[string]bool
+	// MaxCost is the cost budget of a request, the number of objects that it
+	// may load. The default is used if zero.
+	MaxCost int64
+}
+
+// Server is the http.Handler of the GraphQL API.
+type Server struct {
+	ds            DataSource
+	proposals     ProposalsSource
+	hub           Hub
+	params        *chaincfg.Params
+	chainDisabled map[string]bool
+	maxCost       int64
+	schema        *graphql.Schema
+}
+
+// isNil checks if an interface has a nil pointer value.
+func isNil(v any) bool {
+	return v == nil || reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()
+}
+
+// NewServer is the constructor for Server.
+func NewServer(cfg *Config) (*Server, error) {
+	if isNil(cfg.DataSource) {
+		return nil, errors.New("a DataSource is required")
+	}
+	s := &Server{
+		ds:            cfg.DataSource,
+		params:        cfg.Params,
+		chainDisabled: cfg.ChainDisabled,
+		maxCost:       cfg.MaxCost,
+	}
+	if !isNil(cfg.ProposalsDB) {
+		s.proposals = cfg.ProposalsDB
+	}
+	if !isNil(cfg.Hub) {
+		s.hub = cfg.Hub
+	}
+	if s.maxCost <= 0 {
+		s.maxCost = maxQueryCost
+	}
+	var err error
+	s.schema, err = graphql.ParseSchema(schemaSDL, &resolver{s},
+		graphql.MaxQueryLength(maxQueryLength),
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxParallelism(maxParallelism),
+		graphql.SubscribeResolverTimeout(eventTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("invalid GraphQL schema: %w", err)
+	}
+	return s, nil
+}
+
+// params are the parameters of a GraphQL request.
+type params struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// readParams reads the parameters of a GET request from the URL query, and of
+// a POST request from the JSON body.
+func readParams(r *http.Request) (*params, error) {
+	p := new(params)
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		p.Query = q.Get("query")
+		p.OperationName = q.Get("operationName")
+		if vars := q.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &p.Variables); err != nil {
+				return nil, fmt.Errorf("invalid variables: %w", err)
+			}
+		}
+	} else {
+		dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 2*maxQueryLength))
+		if err := dec.Decode(p); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+	if p.Query == "" {
+		return nil, errors.New("no query")
+	}
+	return p, nil
+}
+
+// ServeHTTP serves a GraphQL request. A request that accepts
+// text/event-stream is served as a stream of Server-Sent Events, which is
+// required for subscriptions.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p, err := readParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.serveEventStream(w, r, p)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), ctxKey{}, s.newRequest())
+	resp := s.schema.Exec(ctx, p.Query, p.OperationName, p.Variables)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err = json.NewEncoder(w).Encode(resp); err != nil {
+		log.Warnf("JSON encode error: %v", err)
+	}
+}
+
+// serveEventStream streams the responses of a subscription, or the response of
+// a query, as "next" events, followed by a "complete" event.
+func (s *Server) serveEventStream(w http.ResponseWriter, r *http.Request, p *params) {
+	// Subscribe does not check the length of the query.
+	if len(p.Query) > maxQueryLength {
+		http.Error(w, fmt.Sprintf("the query exceeds %d bytes", maxQueryLength),
+			http.StatusBadRequest)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.WithValue(r.Context(), ctxKey{}, s.newRequest()))
+	defer cancel()
+	responses, err := s.schema.Subscribe(ctx, p.Query, p.OperationName, p.Variables)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The stream outlives the write timeout of the server, so each write has
+	// its own deadline.
+	rc := http.NewResponseController(w)
+	write := func(format string, args ...any) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil &&
+			!errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // no proxy buffering
+	w.WriteHeader(http.StatusOK)
+	if err = rc.Flush(); err != nil {
+		log.Tracef("GraphQL stream closed: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(pubsub.SSEHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case resp, ok := <-responses:
+			if !ok {
+				_ = write("event: complete\ndata:\n\n")
+				return
+			}
+			data, err := json.Marshal(resp)
+			if err != nil {
+				log.Warnf("JSON encode error: %v", err)
+				return
+			}
+			if err = write("event: next\ndata: %s\n\n", data); err != nil {
+				log.Tracef("GraphQL stream closed: %v", err)
+				return
+			}
+		case <-heartbeat.C:
+			if err = write(": heartbeat\n\n"); err != nil {
+				log.Tracef("GraphQL stream closed: %v", err)
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
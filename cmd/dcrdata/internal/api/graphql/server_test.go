@@ -0,0 +1,351 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/pubsub"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// stubSource is a DataSource with a chain of blocks that each have one
+// transaction, which spends the transaction of the previous block.
+type stubSource struct {
+	DataSource
+
+	mtx         sync.Mutex
+	rangeCalls  [][2]int
+	singleCalls []int
+	txCalls     map[string]int
+}
+
+const stubBestHeight = 50
+
+func stubBlockHash(height int) string {
+	return fmt.Sprintf("%064x", 0xb000+height)
+}
+
+func stubTxid(height int) string {
+	return fmt.Sprintf("%064x", 0x7000+height)
+}
+
+func stubBlock(height int) *apitypes.BlockDataBasic {
+	if height < 0 || height > stubBestHeight {
+		return nil
+	}
+	return &apitypes.BlockDataBasic{Height: uint32(height), Hash: stubBlockHash(height), NumTx: 1}
+}
+
+func (s *stubSource) GetSummary(idx int) *apitypes.BlockDataBasic {
+	s.mtx.Lock()
+	s.singleCalls = append(s.singleCalls, idx)
+	s.mtx.Unlock()
+	return stubBlock(idx)
+}
+
+func (s *stubSource) GetSummaryRange(idx0, idx1 int) []*apitypes.BlockDataBasic {
+	s.mtx.Lock()
+	s.rangeCalls = append(s.rangeCalls, [2]int{idx0, idx1})
+	s.mtx.Unlock()
+	var blocks []*apitypes.BlockDataBasic
+	for h := idx0; h <= idx1; h++ {
+		if b := stubBlock(h); b != nil {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+func (s *stubSource) GetBestBlockSummary() *apitypes.BlockDataBasic {
+	return stubBlock(stubBestHeight)
+}
+
+func (s *stubSource) GetSummaryByHash(hash string, _ bool) *apitypes.BlockDataBasic {
+	for h := 0; h <= stubBestHeight; h++ {
+		if stubBlockHash(h) == hash {
+			return stubBlock(h)
+		}
+	}
+	return nil
+}
+
+func (s *stubSource) GetTransactionsForBlockByHash(hash string) *apitypes.BlockTransactions {
+	b := s.GetSummaryByHash(hash, false)
+	if b == nil {
+		return nil
+	}
+	return &apitypes.BlockTransactions{Tx: []string{stubTxid(int(b.Height))}, STx: []string{}}
+}
+
+func (s *stubSource) GetAPITransaction(txid *chainhash.Hash) *apitypes.Tx {
+	s.mtx.Lock()
+	s.txCalls[txid.String()]++
+	s.mtx.Unlock()
+	for h := 0; h <= stubBestHeight; h++ {
+		if stubTxid(h) != txid.String() {
+			continue
+		}
+		tx := &apitypes.Tx{
+			TxShort: apitypes.TxShort{TxID: stubTxid(h), Type: "Regular"},
+			Block:   &apitypes.BlockID{BlockHash: stubBlockHash(h), BlockHeight: int64(h)},
+		}
+		if h > 0 {
+			tx.Vin = []apitypes.Vin{{Txid: stubTxid(h - 1)}}
+		} else {
+			tx.Vin = []apitypes.Vin{{Coinbase: "00"}}
+		}
+		return tx
+	}
+	return nil
+}
+
+func newStubServer(t *testing.T, hub Hub) (*Server, *stubSource) {
+	t.Helper()
+	ds := &stubSource{txCalls: make(map[string]int)}
+	s, err := NewServer(&Config{DataSource: ds, Hub: hub, Params: chaincfg.MainNetParams()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, ds
+}
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, s *Server, q string) *gqlResponse {
+	t.Helper()
+	body, _ := json.Marshal(map[string]any{"query": q})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body))))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	resp := new(gqlResponse)
+	if err := json.Unmarshal(rec.Body.Bytes(), resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestQueryBatching(t *testing.T) {
+	s, ds := newStubServer(t, nil)
+	resp := query(t, s, `{
+		blocks(from: 10, to: 14) {
+			height
+			transactions { txid inputs { previousTransaction { txid block { height } } } }
+		}
+	}`)
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %v", resp.Errors)
+	}
+	var data struct {
+		Blocks []struct {
+			Height       int
+			Transactions []struct {
+				Txid   string
+				Inputs []struct {
+					PreviousTransaction struct {
+						Txid  string
+						Block struct{ Height int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Blocks) != 5 {
+		t.Fatalf("got %d blocks, want 5", len(data.Blocks))
+	}
+	for i, b := range data.Blocks {
+		h := 10 + i
+		prev := b.Transactions[0].Inputs[0].PreviousTransaction
+		if b.Height != h || b.Transactions[0].Txid != stubTxid(h) ||
+			prev.Txid != stubTxid(h-1) || prev.Block.Height != h-1 {
+			t.Errorf("block %d: %+v", h, b)
+		}
+	}
+
+	// The range of blocks is one query, and each transaction is fetched once,
+	// although the previous transactions of blocks 11-14 are also the
+	// transactions of blocks 10-13.
+	if len(ds.rangeCalls) != 1 || ds.rangeCalls[0] != [2]int{10, 14} {
+		t.Errorf("block range queries %v, want [[10 14]]", ds.rangeCalls)
+	}
+	for txid, n := range ds.txCalls {
+		if n != 1 {
+			t.Errorf("transaction %s fetched %d times", txid, n)
+		}
+	}
+	if len(ds.txCalls) != 6 {
+		t.Errorf("fetched %d transactions, want 6", len(ds.txCalls))
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	s, _ := newStubServer(t, nil)
+	s.maxCost = 10
+	resp := query(t, s, `{ blocks(from: 0, to: 9) { transactions { txid } } }`)
+	if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "complexity limit") {
+		t.Errorf("expected a complexity error, got %v", resp.Errors)
+	}
+
+	resp = query(t, s, `{ blocks(from: 0, to: 100) { height } }`)
+	if len(resp.Errors) == 0 {
+		t.Error("expected an error for 101 blocks")
+	}
+
+	deep := "{ block { transactions { inputs { previousTransaction { inputs { previousTransaction" +
+		" { inputs { previousTransaction { inputs { previousTransaction { inputs { previousTransaction" +
+		" { txid } } } } } } } } } } } } } }"
+	if resp = query(t, s, deep); len(resp.Errors) == 0 {
+		t.Error("expected a depth error")
+	}
+
+	if resp = query(t, s, "{ block { height } }"+strings.Repeat(" ", maxQueryLength)); len(resp.Errors) == 0 {
+		t.Error("expected a length error")
+	}
+}
+
+func TestGetQuery(t *testing.T) {
+	s, _ := newStubServer(t, nil)
+	q := url.Values{
+		"query":     {`query ($h: Int) { block(height: $h) { hash } }`},
+		"variables": {`{"h": 7}`},
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graphql?"+q.Encode(), nil))
+	want := `{"data":{"block":{"hash":"` + stubBlockHash(7) + `"}}}`
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graphql", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status %d for no query, want 400", rec.Code)
+	}
+}
+
+func TestSubscription(t *testing.T) {
+	wsh := pubsub.NewWebsocketHub()
+	go wsh.Run()
+	defer wsh.Stop()
+	s, _ := newStubServer(t, wsh)
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	body := `{"query": "subscription { newBlock { height transactions { txid } } }"}`
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader(body))
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// Signal new blocks until the subscription is registered with the hub.
+	var received atomic.Bool
+	go func() {
+		for !received.Load() {
+			select {
+			case wsh.HubRelay <- pstypes.HubMessage{Signal: pstypes.SigNewBlock}:
+			case <-ctx.Done():
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	scanner := bufio.NewScanner(resp.Body)
+	var event string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if ev, ok := strings.CutPrefix(line, "event: "); ok {
+			event = ev
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		received.Store(true)
+		if event != "next" {
+			t.Fatalf("got event %q", event)
+		}
+		want := `{"data":{"newBlock":{"height":50,"transactions":[{"txid":"` + stubTxid(50) + `"}]}}}`
+		if data != want {
+			t.Errorf("got %s, want %s", data, want)
+		}
+		return
+	}
+	t.Fatal(errors.Join(errors.New("no event"), scanner.Err()))
+}
+
+func TestLoader(t *testing.T) {
+	var calls atomic.Int32
+	fail := errors.New("fail")
+	l := newLoader(func(_ context.Context, keys []int) ([]string, error) {
+		calls.Add(1)
+		vals := make([]string, len(keys))
+		for i, k := range keys {
+			if k < 0 {
+				return nil, fail
+			}
+			vals[i] = strings.Repeat("x", k)
+		}
+		return vals, nil
+	})
+	ctx := context.Background()
+	vals, err := l.loadMany(ctx, []int{1, 2, 3, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(vals, ",") != "x,xx,xxx,xx" {
+		t.Errorf("got %v", vals)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d batches, want 1", calls.Load())
+	}
+
+	// Memoized.
+	if v, _ := l.load(ctx, 3); v != "xxx" || calls.Load() != 1 {
+		t.Errorf("got %q after %d batches", v, calls.Load())
+	}
+
+	// The error of a batch fails each key.
+	if _, err = l.loadMany(ctx, []int{4, -1}); !errors.Is(err, fail) {
+		t.Errorf("got error %v, want %v", err, fail)
+	}
+
+	// Full batches are dispatched without waiting.
+	l.wait = time.Hour
+	l.maxBatch = 2
+	if _, err = l.loadMany(ctx, []int{5, 6}); err != nil {
+		t.Fatal(err)
+	}
+}
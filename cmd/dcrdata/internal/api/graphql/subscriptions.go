@@ -0,0 +1,166 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// subscribe subscribes to the hub signal of the message, and sends the events
+// made by newEvent from each message on the returned channel until the
+// subscription context is done or the hub is stopped. Each event is resolved
+// with a new request. The messages for which newEvent returns false are
+// skipped.
+func subscribe[T any](ctx context.Context, s *Server, sub pstypes.HubMessage,
+	newEvent func(req *request, msg pstypes.HubMessage) (T, bool)) (<-chan T, error) {
+	if s.hub == nil {
+		return nil, errors.New("subscriptions are disabled")
+	}
+	hs, err := s.hub.Subscribe(sub)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan T)
+	go func() {
+		defer close(events)
+		defer hs.Close()
+		for {
+			select {
+			case msg, ok := <-hs.C:
+				if !ok {
+					return
+				}
+				ev, ok := newEvent(s.newRequest(), msg)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// NewBlock subscribes to the new DCR blocks.
+func (r *resolver) NewBlock(ctx context.Context) (<-chan *blockResolver, error) {
+	return subscribe(ctx, r.s, pstypes.HubMessage{Signal: pstypes.SigNewBlock},
+		func(req *request, _ pstypes.HubMessage) (*blockResolver, bool) {
+			b := newBlockResolver(req, r.s.ds.GetBestBlockSummary())
+			return b, b != nil
+		})
+}
+
+// ChainNewBlock subscribes to the new blocks of BTC or LTC.
+func (r *resolver) ChainNewBlock(ctx context.Context, args struct{ Chain string }) (<-chan *chainBlockResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	var sig pstypes.HubSignal
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		sig = pstypes.SigNewBTCBlock
+	case mutilchain.TYPELTC:
+		sig = pstypes.SigNewLTCBlock
+	default:
+		return nil, fmt.Errorf("no new block events for %s", args.Chain)
+	}
+	return subscribe(ctx, r.s, pstypes.HubMessage{Signal: sig},
+		func(req *request, _ pstypes.HubMessage) (*chainBlockResolver, bool) {
+			_, hash, err := r.s.ds.MutilchainBestBlock(chainType)
+			if err != nil {
+				log.Errorf("Unable to get the best %s block: %v", chainType, err)
+				return nil, false
+			}
+			b, err := req.chainBlock.load(ctx, chainKey{chainType, hash})
+			if err != nil || b == nil {
+				return nil, false
+			}
+			return newChainBlockResolver(req, chainType, b), true
+		})
+}
+
+// AddressTx subscribes to the transactions of a DCR address.
+func (r *resolver) AddressTx(ctx context.Context, args struct{ Address string }) (<-chan *addressTxEventResolver, error) {
+	if _, err := stdaddr.DecodeAddress(args.Address, r.s.params); err != nil {
+		return nil, errors.New("invalid address")
+	}
+	sub := pstypes.HubMessage{
+		Signal: pstypes.SigAddressTx,
+		Msg:    &pstypes.AddressMessage{Address: args.Address},
+	}
+	return subscribe(ctx, r.s, sub, func(req *request, msg pstypes.HubMessage) (*addressTxEventResolver, bool) {
+		am, ok := msg.Msg.(*pstypes.AddressMessage)
+		return &addressTxEventResolver{req, am}, ok
+	})
+}
+
+// ChainAddressTx subscribes to the transactions of a BTC or LTC address.
+func (r *resolver) ChainAddressTx(ctx context.Context, args struct {
+	Chain   string
+	Address string
+}) (<-chan *chainAddressTxEventResolver, error) {
+	chainType, err := r.s.chainType(args.Chain)
+	if err != nil {
+		return nil, err
+	}
+	var sig pstypes.HubSignal
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		sig = pstypes.SigBTCAddressTx
+	case mutilchain.TYPELTC:
+		sig = pstypes.SigLTCAddressTx
+	default:
+		return nil, fmt.Errorf("no address events for %s", args.Chain)
+	}
+	if !r.s.ds.IsMutilchainValidAddress(chainType, args.Address) {
+		return nil, errors.New("invalid address")
+	}
+	sub := pstypes.HubMessage{
+		Signal: sig,
+		Msg:    &pstypes.AddressMessage{Address: args.Address},
+	}
+	return subscribe(ctx, r.s, sub, func(req *request, msg pstypes.HubMessage) (*chainAddressTxEventResolver, bool) {
+		am, ok := msg.Msg.(*pstypes.AddressMessage)
+		return &chainAddressTxEventResolver{req, chainType, am}, ok
+	})
+}
+
+type addressTxEventResolver struct {
+	req *request
+	am  *pstypes.AddressMessage
+}
+
+func (e *addressTxEventResolver) Address() string { return e.am.Address }
+func (e *addressTxEventResolver) Txid() string    { return e.am.TxHash }
+
+func (e *addressTxEventResolver) Transaction(ctx context.Context) (*txResolver, error) {
+	return e.req.loadTx(ctx, e.am.TxHash)
+}
+
+type chainAddressTxEventResolver struct {
+	req   *request
+	chain string
+	am    *pstypes.AddressMessage
+}
+
+func (e *chainAddressTxEventResolver) Chain() string   { return chainEnum(e.chain) }
+func (e *chainAddressTxEventResolver) Address() string { return e.am.Address }
+func (e *chainAddressTxEventResolver) Txid() string    { return e.am.TxHash }
+
+func (e *chainAddressTxEventResolver) Transaction(ctx context.Context) (*chainTxResolver, error) {
+	return e.req.loadChainTx(ctx, e.chain, e.am.TxHash)
+}
@@ -21,6 +21,19 @@ import (
 
 type contextKey int
 
+const (
+	// minAddressLength and maxAddressLength bound the length of a Decred
+	// address, for p2pkh/p2sh and p2pk respectively.
+	minAddressLength = 35
+	maxAddressLength = 53
+
+	// minChainAddressLength and maxChainAddressLength bound the length of a
+	// BTC or LTC address, from a short base58 p2pkh address to a bech32
+	// address of the maximum length.
+	minChainAddressLength = 26
+	maxChainAddressLength = 90
+)
+
 const (
 	ctxFrom contextKey = iota
 	ctxTo
@@ -156,6 +169,16 @@ func (iapi *InsightApi) ValidatePostCtx(next http.Handler) http.Handler {
 // list, "addrs", must be in the POST body JSON, the other parameters may be
 // specified as URL queries. POST body values take priority.
 func PostAddrsTxsCtxN(n int) func(next http.Handler) http.Handler {
+	return postAddrsTxsCtxN(n, minAddressLength, maxAddressLength)
+}
+
+// ChainPostAddrsTxsCtxN is like PostAddrsTxsCtxN, but for the BTC and LTC
+// addresses of the chain Insight APIs.
+func ChainPostAddrsTxsCtxN(n int) func(next http.Handler) http.Handler {
+	return postAddrsTxsCtxN(n, minChainAddressLength, maxChainAddressLength)
+}
+
+func postAddrsTxsCtxN(n, minAddressLength, maxAddressLength int) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			body, err := io.ReadAll(r.Body)
@@ -180,12 +203,10 @@ func PostAddrsTxsCtxN(n int) func(next http.Handler) http.Handler {
 
 			// Initial sanity check without splitting string: It can't be longer
 			// than n addresses, plus n - 1 commas.
-			const minAddressLength = 35 // p2pk and p2sh
 			if len(addressStr) < minAddressLength {
 				http.Error(w, "invalid address", http.StatusBadRequest)
 				return
 			}
-			const maxAddressLength = 53 // p2pk
 			if len(addressStr) > n*(maxAddressLength+1)-1 {
 				apiLog.Warnf("PostAddrsTxsCtxN rejecting address parameter of length %d", len(addressStr))
 				http.Error(w, "too many address", http.StatusBadRequest)
@@ -262,6 +283,16 @@ func PostAddrsTxsCtxN(n int) func(next http.Handler) http.Handler {
 // PostAddrsUtxoCtxN middleware processes parameters given in the POST request
 // body for an addrs utxo endpoint, limiting to N addresses.
 func PostAddrsUtxoCtxN(n int) func(next http.Handler) http.Handler {
+	return postAddrsUtxoCtxN(n, minAddressLength, maxAddressLength)
+}
+
+// ChainPostAddrsUtxoCtxN is like PostAddrsUtxoCtxN, but for the BTC and LTC
+// addresses of the chain Insight APIs.
+func ChainPostAddrsUtxoCtxN(n int) func(next http.Handler) http.Handler {
+	return postAddrsUtxoCtxN(n, minChainAddressLength, maxChainAddressLength)
+}
+
+func postAddrsUtxoCtxN(n, minAddressLength, maxAddressLength int) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			req := apitypes.InsightAddr{}
@@ -285,12 +316,10 @@ func PostAddrsUtxoCtxN(n int) func(next http.Handler) http.Handler {
 
 			// Initial sanity check without splitting string: It can't be longer
 			// than n addresses, plus n - 1 commas.
-			const minAddressLength = 35 // p2pk and p2sh
 			if len(addressStr) < minAddressLength {
 				http.Error(w, "invalid address", http.StatusBadRequest)
 				return
 			}
-			const maxAddressLength = 53 // p2pk
 			if len(addressStr) > n*(maxAddressLength+1)-1 {
 				apiLog.Warnf("PostAddrsTxsCtxN rejecting address parameter of length %d", len(addressStr))
 				http.Error(w, "too many address", http.StatusBadRequest)
@@ -312,6 +341,37 @@ func PostAddrsUtxoCtxN(n int) func(next http.Handler) http.Handler {
 	}
 }
 
+// ChainAddressPathCtxN returns a http.HandlerFunc that embeds the BTC or LTC
+// addresses at the url part {address}, limiting to N addresses, into the
+// request context. The addresses are validated by the handlers.
+func ChainAddressPathCtxN(n int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addressStr := chi.URLParam(r, "address")
+			if len(addressStr) < minChainAddressLength ||
+				(n == 1 && len(addressStr) > maxChainAddressLength) {
+				apiLog.Warnf("ChainAddressPathCtxN rejecting address parameter of length %d", len(addressStr))
+				http.Error(w, "invalid address", http.StatusUnprocessableEntity)
+				return
+			}
+			// string can't be longer than n addresses, plus n - 1 commas.
+			if len(addressStr) > n*(maxChainAddressLength+1)-1 {
+				apiLog.Warnf("ChainAddressPathCtxN rejecting address parameter of length %d", len(addressStr))
+				http.Error(w, "too many address", http.StatusUnprocessableEntity)
+				return
+			}
+			addrs := strings.Split(addressStr, ",")
+			if len(addrs) > n {
+				apiLog.Warnf("ChainAddressPathCtxN parsed %d > %d strings", len(addrs), n)
+				http.Error(w, "address parse error", http.StatusUnprocessableEntity)
+				return
+			}
+			ctx := context.WithValue(r.Context(), m.CtxAddress, addrs)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // AddressCommandCtx returns a http.HandlerFunc that embeds the value at the url
 // part {command} into the request context.
 func AddressCommandCtx(next http.Handler) http.Handler {
@@ -25,45 +25,63 @@ const APIVersion = 0
 // NewInsightAPIRouter returns a new HTTP path router, ApiMux, for the Insight
 // API, app.
 func NewInsightAPIRouter(app *InsightApi, useRealIP, compression bool, maxAddrs int) ApiMux {
-	// chi router
-	mux := chi.NewRouter()
+	mux := newInsightMux(app.ReqPerSecLimit, app.JSONIndent, useRealIP, compression)
 
-	// Create a rate limiter struct.
-	limiter := m.NewLimiter(app.ReqPerSecLimit)
-	limiter.SetMessage(fmt.Sprintf(
-		"You have reached the maximum request limit (%g req/s)", app.ReqPerSecLimit))
-
-	if useRealIP {
-		mux.Use(middleware.RealIP)
-		// RealIP sets RemoteAddr
-		limiter.SetIPLookups([]string{"RemoteAddr"})
-	} else {
-		limiter.SetIPLookups([]string{"X-Forwarded-For", "X-Real-IP", "RemoteAddr"})
-	}
+	// Block endpoints
+	mux.With(BlockDateLimitQueryCtx).Get("/blocks", app.getBlockSummaryByTime)
+	mux.With(m.BlockIndexOrHashPathCtx).Get("/block/{idxorhash}", app.getBlockSummary)
+	mux.With(m.BlockIndexOrHashPathCtx).Get("/block-index/{idxorhash}", app.getBlockHash)
+	mux.With(m.BlockIndexOrHashPathCtx).Get("/rawblock/{idxorhash}", app.getRawBlock)
 
-	// Put the limiter after RealIP
-	mux.Use(m.Tollbooth(limiter))
+	// Transaction endpoints
+	mux.With(middleware.AllowContentType("application/json"),
+		app.ValidatePostCtx, m.PostBroadcastTxCtx).Post("/tx/send", app.broadcastTransactionRaw)
+	mux.With(m.TransactionHashCtx).Get("/tx/{txid}", app.getTransaction)
+	mux.With(m.TransactionHashCtx).Get("/rawtx/{txid}", app.getTransactionHex)
+	mux.With(m.TransactionsCtx, m.PageNumCtx).Get("/txs", app.getTransactions)
 
-	// Check for and validate the "indent" URL query. Each API request handler
-	// may now access the configured indentation string if indent was specified
-	// and parsed as a boolean, otherwise the empty string, from
-	// m.GetIndentCtx(*http.Request).
+	// Status and Utility
+	mux.With(app.StatusInfoCtx).Get("/status", app.getStatusInfo)
+	mux.Get("/sync", app.getSyncInfo)
+	mux.With(NbBlocksCtx).Get("/utils/estimatefee", app.getEstimateFee)
+	mux.Get("/peer", app.GetPeerStatus)
 
-	mux.Use(m.Indent(app.JSONIndent))
+	addrs1Ctx := m.AddressPathCtxN(1)
+	addrsMaxCtx := m.AddressPathCtxN(maxAddrs)
 
-	mux.Use(middleware.Logger)
-	mux.Use(middleware.Recoverer)
-	mux.Use(middleware.StripSlashes)
-	if compression {
-		mux.Use(middleware.Compress(3))
-	}
+	// Addresses endpoints
+	mux.Route("/addrs", func(rd chi.Router) {
+		rd.Route("/{address}", func(ra chi.Router) {
+			ra.Use(addrsMaxCtx, FromToPaginationCtx)
+			ra.Get("/txs", app.getAddressesTxn)
+			ra.Get("/utxo", app.getAddressesTxnOutput)
+		})
+		// POST methods
+		rd.With(middleware.AllowContentType("application/json"),
+			app.ValidatePostCtx, PostAddrsTxsCtxN(maxAddrs)).Post("/txs", app.getAddressesTxn)
+		rd.With(middleware.AllowContentType("application/json"),
+			app.ValidatePostCtx, PostAddrsUtxoCtxN(maxAddrs)).Post("/utxo", app.getAddressesTxnOutput)
+	})
 
-	mux.With(m.OriginalRequestURI).Get("/", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, r.URL.Path+"/status", http.StatusSeeOther)
+	// Address endpoints
+	mux.Route("/addr/{address}", func(rd chi.Router) {
+		rd.With(addrs1Ctx, FromToPaginationCtx, NoTxListCtx).Get("/", app.getAddressInfo)
+		rd.With(addrsMaxCtx).Get("/utxo", app.getAddressesTxnOutput)
+		rd.Route("/{command}", func(ra chi.Router) {
+			ra.With(addrs1Ctx, AddressCommandCtx).Get("/", app.getAddressInfo)
+		})
 	})
 
+	return ApiMux{mux}
+}
+
+// NewChainInsightAPIRouter returns a new HTTP path router, ApiMux, for the
+// Insight API of a BTC or LTC chain, app. The routes are those of the Decred
+// Insight API, except for the blocks by date endpoint.
+func NewChainInsightAPIRouter(app *ChainInsightApi, useRealIP, compression bool, maxAddrs int) ApiMux {
+	mux := newInsightMux(app.ReqPerSecLimit, app.JSONIndent, useRealIP, compression)
+
 	// Block endpoints
-	mux.With(BlockDateLimitQueryCtx).Get("/blocks", app.getBlockSummaryByTime)
 	mux.With(m.BlockIndexOrHashPathCtx).Get("/block/{idxorhash}", app.getBlockSummary)
 	mux.With(m.BlockIndexOrHashPathCtx).Get("/block-index/{idxorhash}", app.getBlockHash)
 	mux.With(m.BlockIndexOrHashPathCtx).Get("/rawblock/{idxorhash}", app.getRawBlock)
@@ -76,13 +94,13 @@ func NewInsightAPIRouter(app *InsightApi, useRealIP, compression bool, maxAddrs
 	mux.With(m.TransactionsCtx, m.PageNumCtx).Get("/txs", app.getTransactions)
 
 	// Status and Utility
-	mux.With(app.StatusInfoCtx).Get("/status", app.getStatusInfo)
+	mux.Get("/status", app.getStatusInfo)
 	mux.Get("/sync", app.getSyncInfo)
 	mux.With(NbBlocksCtx).Get("/utils/estimatefee", app.getEstimateFee)
 	mux.Get("/peer", app.GetPeerStatus)
 
-	addrs1Ctx := m.AddressPathCtxN(1)
-	addrsMaxCtx := m.AddressPathCtxN(maxAddrs)
+	addrs1Ctx := ChainAddressPathCtxN(1)
+	addrsMaxCtx := ChainAddressPathCtxN(maxAddrs)
 
 	// Addresses endpoints
 	mux.Route("/addrs", func(rd chi.Router) {
@@ -93,9 +111,9 @@ func NewInsightAPIRouter(app *InsightApi, useRealIP, compression bool, maxAddrs
 		})
 		// POST methods
 		rd.With(middleware.AllowContentType("application/json"),
-			app.ValidatePostCtx, PostAddrsTxsCtxN(maxAddrs)).Post("/txs", app.getAddressesTxn)
+			app.ValidatePostCtx, ChainPostAddrsTxsCtxN(maxAddrs)).Post("/txs", app.getAddressesTxn)
 		rd.With(middleware.AllowContentType("application/json"),
-			app.ValidatePostCtx, PostAddrsUtxoCtxN(maxAddrs)).Post("/utxo", app.getAddressesTxnOutput)
+			app.ValidatePostCtx, ChainPostAddrsUtxoCtxN(maxAddrs)).Post("/utxo", app.getAddressesTxnOutput)
 	})
 
 	// Address endpoints
@@ -109,3 +127,46 @@ func NewInsightAPIRouter(app *InsightApi, useRealIP, compression bool, maxAddrs
 
 	return ApiMux{mux}
 }
+
+// newInsightMux creates a chi router with the rate limiting, indentation,
+// logging, and compression middleware common to the Insight APIs.
+func newInsightMux(reqPerSecLimit float64, jsonIndent string, useRealIP, compression bool) *chi.Mux {
+	// chi router
+	mux := chi.NewRouter()
+
+	// Create a rate limiter struct.
+	limiter := m.NewLimiter(reqPerSecLimit)
+	limiter.SetMessage(fmt.Sprintf(
+		"You have reached the maximum request limit (%g req/s)", reqPerSecLimit))
+
+	if useRealIP {
+		mux.Use(middleware.RealIP)
+		// RealIP sets RemoteAddr
+		limiter.SetIPLookups([]string{"RemoteAddr"})
+	} else {
+		limiter.SetIPLookups([]string{"X-Forwarded-For", "X-Real-IP", "RemoteAddr"})
+	}
+
+	// Put the limiter after RealIP
+	mux.Use(m.Tollbooth(limiter))
+
+	// Check for and validate the "indent" URL query. Each API request handler
+	// may now access the configured indentation string if indent was specified
+	// and parsed as a boolean, otherwise the empty string, from
+	// m.GetIndentCtx(*http.Request).
+
+	mux.Use(m.Indent(jsonIndent))
+
+	mux.Use(middleware.Logger)
+	mux.Use(middleware.Recoverer)
+	mux.Use(middleware.StripSlashes)
+	if compression {
+		mux.Use(middleware.Compress(3))
+	}
+
+	mux.With(m.OriginalRequestURI).Get("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, r.URL.Path+"/status", http.StatusSeeOther)
+	})
+
+	return mux
+}
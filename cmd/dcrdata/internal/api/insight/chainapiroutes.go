@@ -0,0 +1,810 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package insight
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcutil"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// maxChainTxSize is the largest BTC or LTC transaction, in bytes, accepted by
+// the tx/send endpoint. This is the standardness limit of 400000 weight units
+// of the nodes, which bounds the serialized size of a relayed transaction.
+const maxChainTxSize = 400000
+
+// MutilchainDataSource is the data source of the Insight API of the BTC and
+// LTC chains, which is the multichain tables of the ChainDB and the chain's
+// node and mempool.
+type MutilchainDataSource interface {
+	GetMultichainTransactionHex(txid, chainType string) string
+	GetMutilchainBlockHash(idx int64, chainType string) (string, error)
+	IsMutilchainValidAddress(chainType string, address string) bool
+	MutilchainAddressBalance(address string, chainType string) (*dbtypes.AddressBalance, bool, error)
+	MutilchainAddressUTXO(address, chainType string) ([]*dbtypes.MutilchainAddressTxnOutput, error)
+	MutilchainEstimateFee(nbBlocks int64, chainType string) (float64, error)
+	MutilchainHeight(chainType string) int64
+	MutilchainInsightAddressTransactions(addrs []string, chainType string) ([]string, error)
+	MutilchainInsightBlock(hash, chainType string) (*apitypes.InsightBlockResult, error)
+	MutilchainInsightStatus(chainType string) (*apitypes.InsightStatusInfo, string, error)
+	MutilchainInsightTransactions(txids []string, chainType string, noAsm, noScriptSig, noSpent bool) ([]apitypes.InsightTx, error)
+	MutilchainOutPointAddresses(txid string, vout uint32, chainType string) ([]string, int64, error)
+	MutilchainRawBlock(hash, chainType string) (string, error)
+	MutilchainSendRawTransaction(txhex, chainType string) (string, error)
+	MutilchainUnconfirmedTxnsForAddress(address, chainType string) (*dbtypes.MutilchainMempoolAddress, error)
+}
+
+// ChainInsightApi contains the resources for the Insight HTTP API of a BTC or
+// LTC chain. ChainInsightApi's methods include the http.Handlers for the URL
+// path routes.
+type ChainInsightApi struct {
+	chainType      string
+	BlockData      MutilchainDataSource
+	JSONIndent     string
+	ReqPerSecLimit float64
+}
+
+// NewChainInsightAPI is the constructor for ChainInsightApi.
+func NewChainInsightAPI(chainType string, blockData MutilchainDataSource, JSONIndent string) *ChainInsightApi {
+	return &ChainInsightApi{
+		chainType:      chainType,
+		BlockData:      blockData,
+		JSONIndent:     JSONIndent,
+		ReqPerSecLimit: defaultReqPerSecLimit,
+	}
+}
+
+// SetReqRateLimit is used to set the requests/second/IP for the chain Insight
+// API's rate limiter.
+func (iapi *ChainInsightApi) SetReqRateLimit(reqPerSecLimit float64) {
+	iapi.ReqPerSecLimit = reqPerSecLimit
+}
+
+// ValidatePostCtx will confirm Post content length is valid.
+func (iapi *ChainInsightApi) ValidatePostCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentLength, err := strconv.Atoi(r.Header.Get("Content-Length"))
+		if err != nil {
+			writeInsightError(w, "Content-Length Header must be set")
+			return
+		}
+		// Broadcast Tx has the largest possible body.
+		maxPayload := (maxChainTxSize * 2) + 50
+		if contentLength > maxPayload {
+			writeInsightError(w, fmt.Sprintf("Maximum Content-Length is %d", maxPayload))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// addressesCtx retrieves the addresses from the request context, removing
+// duplicates and validating them for the chain.
+func (iapi *ChainInsightApi) addressesCtx(r *http.Request) ([]string, error) {
+	addressStrs, ok := r.Context().Value(m.CtxAddress).([]string)
+	if !ok {
+		return nil, fmt.Errorf("type assertion failed")
+	}
+	seen := make(map[string]bool, len(addressStrs))
+	addresses := make([]string, 0, len(addressStrs))
+	for _, addrStr := range addressStrs {
+		if seen[addrStr] {
+			continue
+		}
+		if !iapi.BlockData.IsMutilchainValidAddress(iapi.chainType, addrStr) {
+			return nil, fmt.Errorf("invalid address %q for this network", addrStr)
+		}
+		seen[addrStr] = true
+		addresses = append(addresses, addrStr)
+	}
+	return addresses, nil
+}
+
+// blockHashCtx retrieves the block hash from the request context, looking it
+// up by the block index if a hash was not given.
+func (iapi *ChainInsightApi) blockHashCtx(w http.ResponseWriter, r *http.Request) (string, bool) {
+	hash, err := m.GetBlockHashCtx(r)
+	if err == nil {
+		return hash, true
+	}
+	idx := m.GetBlockIndexCtx(r)
+	if idx < 0 {
+		writeInsightError(w, "Must provide a block index or hash.")
+		return "", false
+	}
+	hash, err = iapi.BlockData.GetMutilchainBlockHash(int64(idx), iapi.chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("GetMutilchainBlockHash: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return "", false
+	}
+	if err != nil || hash == "" {
+		writeInsightError(w, "Unable to get block hash from index")
+		return "", false
+	}
+	return hash, true
+}
+
+// unconfirmedTxns gathers the mempool transactions of the addresses, skipping
+// any that are in the exclude set, which is updated with the new hashes.
+func (iapi *ChainInsightApi) unconfirmedTxns(addresses []string, exclude map[string]bool) (txids []string, txTimes []int64, err error) {
+	for _, addr := range addresses {
+		addrMempool, err := iapi.BlockData.MutilchainUnconfirmedTxnsForAddress(addr, iapi.chainType)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, txid := range addrMempool.Txids {
+			if exclude[txid] {
+				continue
+			}
+			exclude[txid] = true
+			txids = append(txids, txid)
+			txTimes = append(txTimes, addrMempool.TxTimes[i])
+		}
+	}
+	return txids, txTimes, nil
+}
+
+// addressTxns returns the hashes of the unconfirmed and confirmed transactions
+// of the addresses, unconfirmed first, and the mempool times of the
+// unconfirmed transactions.
+func (iapi *ChainInsightApi) addressTxns(addresses []string) (txids []string, unconfirmedTimes []int64, err error) {
+	confirmed, err := iapi.BlockData.MutilchainInsightAddressTransactions(addresses, iapi.chainType)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A transaction that was just mined may still be in the mempool data.
+	exclude := make(map[string]bool, len(confirmed))
+	for _, txid := range confirmed {
+		exclude[txid] = true
+	}
+	unconfirmed, unconfirmedTimes, err := iapi.unconfirmedTxns(addresses, exclude)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(unconfirmed, confirmed...), unconfirmedTimes, nil
+}
+
+// insightTxns converts the transactions to the Insight format, setting the
+// time of the first len(unconfirmedTimes) transactions to their mempool time.
+func (iapi *ChainInsightApi) insightTxns(txids []string, unconfirmedTimes []int64, noAsm, noScriptSig, noSpent bool) ([]apitypes.InsightTx, error) {
+	txs, err := iapi.BlockData.MutilchainInsightTransactions(txids, iapi.chainType,
+		noAsm, noScriptSig, noSpent)
+	if err != nil {
+		return nil, err
+	}
+	for i := range txs {
+		if i < len(unconfirmedTimes) && txs[i].Confirmations == 0 {
+			txs[i].Time = unconfirmedTimes[i]
+		}
+	}
+	return txs, nil
+}
+
+func (iapi *ChainInsightApi) getTransaction(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+
+	txs, err := iapi.BlockData.MutilchainInsightTransactions([]string{txid.String()},
+		iapi.chainType, false, false, false)
+	if err != nil || len(txs) == 0 {
+		apiLog.Errorf("Unable to get %s transaction %s: %v", iapi.chainType, txid, err)
+		writeInsightNotFound(w, fmt.Sprintf("Unable to get transaction (%s)", txid))
+		return
+	}
+
+	writeJSON(w, txs[0], m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getTransactionHex(w http.ResponseWriter, r *http.Request) {
+	txid, err := m.GetTxIDCtx(r)
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+
+	txHex := iapi.BlockData.GetMultichainTransactionHex(txid.String(), iapi.chainType)
+	if txHex == "" {
+		writeInsightNotFound(w, fmt.Sprintf("Unable to get transaction (%s)", txid))
+		return
+	}
+
+	writeJSON(w, &apitypes.InsightRawTx{Rawtx: txHex}, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getBlockSummary(w http.ResponseWriter, r *http.Request) {
+	hash, ok := iapi.blockHashCtx(w, r)
+	if !ok {
+		return
+	}
+
+	block, err := iapi.BlockData.MutilchainInsightBlock(hash, iapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to get %s block %s: %v", iapi.chainType, hash, err)
+		writeInsightNotFound(w, "Unable to get block")
+		return
+	}
+
+	writeJSON(w, block, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getBlockHash(w http.ResponseWriter, r *http.Request) {
+	idx := m.GetBlockIndexCtx(r)
+	if idx < 0 {
+		writeInsightError(w, "No index found in query")
+		return
+	}
+	if idx > int(iapi.BlockData.MutilchainHeight(iapi.chainType)) {
+		writeInsightError(w, "Block height out of range")
+		return
+	}
+	hash, err := iapi.BlockData.GetMutilchainBlockHash(int64(idx), iapi.chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("GetMutilchainBlockHash: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil || hash == "" {
+		writeInsightNotFound(w, "Not found")
+		return
+	}
+
+	blockOutput := struct {
+		BlockHash string `json:"blockHash"`
+	}{
+		hash,
+	}
+	writeJSON(w, blockOutput, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getRawBlock(w http.ResponseWriter, r *http.Request) {
+	hash, ok := iapi.blockHashCtx(w, r)
+	if !ok {
+		return
+	}
+
+	blockHex, err := iapi.BlockData.MutilchainRawBlock(hash, iapi.chainType)
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightNotFound(w, fmt.Sprintf("Failed to retrieve block %s: %q", hash, errStr))
+		return
+	}
+
+	blockJSON := struct {
+		BlockHash string `json:"rawblock"`
+	}{
+		blockHex,
+	}
+	writeJSON(w, blockJSON, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) broadcastTransactionRaw(w http.ResponseWriter, r *http.Request) {
+	rawHexTx, err := m.GetMultichainRawHexTx(r)
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+
+	// Check maximum transaction size.
+	if len(rawHexTx)/2 > maxChainTxSize {
+		writeInsightError(w, fmt.Sprintf("Rawtx length exceeds maximum allowable characters"+
+			"(%d bytes received)", len(rawHexTx)/2))
+		return
+	}
+
+	txid, err := iapi.BlockData.MutilchainSendRawTransaction(rawHexTx, iapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Unable to send %s transaction %s", iapi.chainType, rawHexTx)
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, fmt.Sprintf("SendRawTransaction failed: %q", errStr))
+		return
+	}
+
+	txidJSON := struct {
+		TxidHash string `json:"txid"`
+	}{
+		txid,
+	}
+	writeJSON(w, txidJSON, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getTransactions(w http.ResponseWriter, r *http.Request) {
+	pageNum := m.GetPageNumCtx(r)
+	hash, blockerr := m.GetBlockHashCtx(r)
+	addresses, addrerr := iapi.addressesCtx(r)
+
+	if blockerr != nil && addrerr != nil {
+		msg := fmt.Sprintf(`Required query parameters (address or block) not present. `+
+			`address error: "%v" / block error: "%v"`, addrerr, blockerr)
+		writeInsightError(w, html.EscapeString(msg))
+		return
+	}
+	if addrerr == nil && len(addresses) > 1 {
+		writeInsightError(w, "Only one address is allowed.")
+		return
+	}
+
+	var txids []string
+	var unconfirmedTimes []int64
+	if blockerr == nil {
+		block, err := iapi.BlockData.MutilchainInsightBlock(hash, iapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Unable to get %s block %s transactions: %v", iapi.chainType, hash, err)
+			writeInsightError(w, fmt.Sprintf("Unable to get block %q transactions", hash))
+			return
+		}
+		txids = block.Tx
+	} else {
+		var err error
+		txids, unconfirmedTimes, err = iapi.addressTxns(addresses)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("MutilchainInsightAddressTransactions: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			errStr := html.EscapeString(err.Error())
+			writeInsightError(w,
+				fmt.Sprintf("Error retrieving transactions for address %s (%q)",
+					addresses[0], errStr))
+			return
+		}
+	}
+
+	txCount := len(txids)
+	if txCount == 0 {
+		writeJSON(w, apitypes.InsightBlockAddrTxSummary{Txs: []apitypes.InsightTx{}},
+			m.GetIndentCtx(r))
+		return
+	}
+
+	const txPageSize = 10
+	pagesTotal := txCount / txPageSize
+	if txCount%txPageSize != 0 {
+		pagesTotal++
+	}
+
+	// Go to the last page if pageNum is greater than the number of pages.
+	if pageNum > pagesTotal {
+		pageNum = pagesTotal
+	}
+
+	// Grab the transactions for the given page (1-based index).
+	start := (pageNum - 1) * txPageSize // middleware guarantees pageNum>0
+	end := start + txPageSize
+	if end > txCount {
+		end = txCount
+	}
+	var pageTimes []int64
+	if start < len(unconfirmedTimes) {
+		pageTimes = unconfirmedTimes[start:]
+	}
+	txs, err := iapi.insightTxns(txids[start:end], pageTimes, false, false, false)
+	if err != nil {
+		apiLog.Errorf("getTransactions: Error processing %s transactions: %v", iapi.chainType, err)
+		writeInsightError(w, "Error Processing Transactions")
+		return
+	}
+
+	writeJSON(w, apitypes.InsightBlockAddrTxSummary{
+		PagesTotal: int64(pagesTotal),
+		Txs:        txs,
+	}, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getAddressesTxn(w http.ResponseWriter, r *http.Request) {
+	addresses, err := iapi.addressesCtx(r) // Required, also validates the addresses
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+
+	noAsm := GetNoAsmCtx(r)             // Optional
+	noScriptSig := GetNoScriptSigCtx(r) // Optional
+	noSpent := GetNoSpentCtx(r)         // Optional
+	from := GetFromCtx(r)               // Optional
+	if from < 0 {
+		from = 0
+	}
+	to, ok := GetToCtx(r) // Optional
+	if !ok {
+		to = from + 10
+	}
+	if to < 0 {
+		to = 0
+	}
+	if from > to {
+		to = from
+	}
+	if to-from > maxInsightAddrsTxns {
+		writeInsightError(w, fmt.Sprintf(
+			`"from" (%d) and "to" (%d) range should be less than or equal to %d`,
+			from, to, maxInsightAddrsTxns))
+		return
+	}
+
+	txids, unconfirmedTimes, err := iapi.addressTxns(addresses)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MutilchainInsightAddressTransactions: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w,
+			fmt.Sprintf("Error retrieving transactions for addresses %s (%q)",
+				addresses, errStr))
+		return
+	}
+
+	addressOutput := &apitypes.InsightMultiAddrsTxOutput{
+		TotalItems: int64(len(txids)),
+		Items:      make([]apitypes.InsightTx, 0),
+	}
+	txCount := int64(len(txids))
+	if from > txCount {
+		from = txCount
+	}
+	if to > txCount {
+		to = txCount
+	}
+	addressOutput.From = int(from)
+	addressOutput.To = int(to)
+
+	if to > from {
+		var pageTimes []int64
+		if int(from) < len(unconfirmedTimes) {
+			pageTimes = unconfirmedTimes[from:]
+		}
+		txs, err := iapi.insightTxns(txids[from:to], pageTimes, noAsm, noScriptSig, noSpent)
+		if err != nil {
+			apiLog.Errorf("Unable to process %s transactions: %v", iapi.chainType, err)
+			errStr := html.EscapeString(err.Error())
+			writeInsightError(w, fmt.Sprintf("Unable to convert transactions (%q)", errStr))
+			return
+		}
+		addressOutput.Items = append(addressOutput.Items, txs...)
+	}
+
+	writeJSON(w, addressOutput, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getAddressesTxnOutput(w http.ResponseWriter, r *http.Request) {
+	addresses, err := iapi.addressesCtx(r) // Required, also validates the addresses
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+
+	bestHeight := iapi.BlockData.MutilchainHeight(iapi.chainType)
+	txnOutputs := make([]*apitypes.AddressTxnOutput, 0)
+	for _, address := range addresses {
+		confirmed, err := iapi.BlockData.MutilchainAddressUTXO(address, iapi.chainType)
+		if dbtypes.IsTimeoutErr(err) {
+			apiLog.Errorf("MutilchainAddressUTXO: %v", err)
+			http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+			return
+		}
+		if err != nil {
+			apiLog.Errorf("Error getting %s UTXOs: %v", iapi.chainType, err)
+			continue
+		}
+
+		addrMempool, err := iapi.BlockData.MutilchainUnconfirmedTxnsForAddress(address, iapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Error getting %s unconfirmed transactions: %v", iapi.chainType, err)
+			continue
+		}
+
+		// Outpoints spent in mempool are no longer unspent.
+		spent := make(map[string]bool, len(addrMempool.SpentOutpoints))
+		for _, op := range addrMempool.SpentOutpoints {
+			spent[op] = true
+		}
+		isSpent := func(out *dbtypes.MutilchainAddressTxnOutput) bool {
+			return spent[fmt.Sprintf("%s:%d", out.TxHash, out.Vout)]
+		}
+
+		// Mempool UTXOs, unless the transaction was just mined.
+		confirmedOuts := make(map[string]bool, len(confirmed))
+		for _, out := range confirmed {
+			confirmedOuts[fmt.Sprintf("%s:%d", out.TxHash, out.Vout)] = true
+		}
+		for _, out := range addrMempool.UTXOs {
+			if confirmedOuts[fmt.Sprintf("%s:%d", out.TxHash, out.Vout)] {
+				continue
+			}
+			txnOutputs = append(txnOutputs, &apitypes.AddressTxnOutput{
+				Address:      address,
+				TxnID:        out.TxHash,
+				Vout:         out.Vout,
+				BlockTime:    out.BlockTime,
+				ScriptPubKey: out.PkScript,
+				Amount:       btcutil.Amount(out.Atoms).ToBTC(),
+				Satoshis:     out.Atoms,
+			})
+		}
+
+		for _, out := range confirmed {
+			if isSpent(out) {
+				continue
+			}
+			txnOutputs = append(txnOutputs, &apitypes.AddressTxnOutput{
+				Address:       address,
+				TxnID:         out.TxHash,
+				Vout:          out.Vout,
+				BlockTime:     out.BlockTime,
+				ScriptPubKey:  out.PkScript,
+				Height:        int64(out.Height),
+				Amount:        btcutil.Amount(out.Atoms).ToBTC(),
+				Satoshis:      out.Atoms,
+				Confirmations: bestHeight - int64(out.Height) + 1,
+			})
+		}
+
+		if len(addresses) > 1 && len(txnOutputs) > maxInsightAddrsUTXOs {
+			writeInsightError(w, "Too many UTXOs in that result. "+
+				"Please request the UTXOs for each address individually.")
+			return
+		}
+	}
+
+	// Sort the UTXOs by timestamp (descending) if unconfirmed and by
+	// confirmations (ascending) if confirmed.
+	sort.Slice(txnOutputs, func(i, j int) bool {
+		if txnOutputs[i].Confirmations == 0 && txnOutputs[j].Confirmations == 0 {
+			return txnOutputs[i].BlockTime > txnOutputs[j].BlockTime
+		}
+		return txnOutputs[i].Confirmations < txnOutputs[j].Confirmations
+	})
+
+	writeJSON(w, txnOutputs, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getAddressInfo(w http.ResponseWriter, r *http.Request) {
+	addresses, err := iapi.addressesCtx(r)
+	if err != nil {
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, errStr)
+		return
+	}
+	if len(addresses) != 1 {
+		writeInsightError(w, fmt.Sprintln("only one address allowed"))
+		return
+	}
+	address := addresses[0]
+
+	// Get confirmed balance.
+	balance, _, err := iapi.BlockData.MutilchainAddressBalance(address, iapi.chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MutilchainAddressBalance: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil || balance == nil {
+		apiLog.Errorf("MutilchainAddressBalance: %v", err)
+		http.Error(w, "Unexpected error retrieving address info.", http.StatusInternalServerError)
+		return
+	}
+
+	command, isCmd := GetAddressCommandCtx(r)
+	if isCmd {
+		switch command {
+		case "balance":
+			writeJSON(w, balance.TotalUnspent, m.GetIndentCtx(r))
+			return
+		case "totalReceived":
+			writeJSON(w, balance.TotalSpent+balance.TotalUnspent, m.GetIndentCtx(r))
+			return
+		case "totalSent":
+			writeJSON(w, balance.TotalSpent, m.GetIndentCtx(r))
+			return
+		}
+	}
+
+	confirmed, err := iapi.BlockData.MutilchainInsightAddressTransactions(addresses, iapi.chainType)
+	if dbtypes.IsTimeoutErr(err) {
+		apiLog.Errorf("MutilchainInsightAddressTransactions: %v", err)
+		http.Error(w, "Database timeout.", http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		apiLog.Errorf("Error retrieving %s transactions for address %s: %v",
+			iapi.chainType, address, err)
+		http.Error(w, "Error retrieving transactions for that address.",
+			http.StatusInternalServerError)
+		return
+	}
+
+	addrMempool, err := iapi.BlockData.MutilchainUnconfirmedTxnsForAddress(address, iapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Error getting %s unconfirmed transactions: %v", iapi.chainType, err)
+		addrMempool = &dbtypes.MutilchainMempoolAddress{Address: address}
+	}
+	unconfirmedBalanceSat := addrMempool.Received - addrMempool.Sent
+
+	if isCmd && command == "unconfirmedBalance" {
+		writeJSON(w, unconfirmedBalanceSat, m.GetIndentCtx(r))
+		return
+	}
+
+	exclude := make(map[string]bool, len(confirmed))
+	for _, txid := range confirmed {
+		exclude[txid] = true
+	}
+	var unconfirmed []string
+	for _, txid := range addrMempool.Txids {
+		if !exclude[txid] {
+			unconfirmed = append(unconfirmed, txid)
+		}
+	}
+	txids := append(unconfirmed, confirmed...)
+
+	// Final tx slice extraction
+	if txCount := int64(len(txids)); txCount > 0 {
+		txLimit := int64(1000)
+		// "from" and "to" are zero-based indexes for inclusive range bounds.
+		from := GetFromCtx(r)
+		to, ok := GetToCtx(r)
+		if !ok || to < from {
+			to = from + txLimit - 1 // to is inclusive
+		}
+
+		// [from, to] --(limits)--> [start,end)
+		start, end, err := fromToForSlice(from, to, txCount, txLimit)
+		if err != nil {
+			errStr := html.EscapeString(err.Error())
+			writeInsightError(w, errStr)
+			return
+		}
+		txids = txids[start:end]
+	}
+
+	addressInfo := apitypes.InsightAddressInfo{
+		Address:                  address,
+		TotalReceivedSat:         balance.TotalSpent + balance.TotalUnspent,
+		TotalSentSat:             balance.TotalSpent,
+		BalanceSat:               balance.TotalUnspent,
+		TotalReceived:            btcutil.Amount(balance.TotalSpent + balance.TotalUnspent).ToBTC(),
+		TotalSent:                btcutil.Amount(balance.TotalSpent).ToBTC(),
+		Balance:                  btcutil.Amount(balance.TotalUnspent).ToBTC(),
+		TxAppearances:            int64(len(confirmed)),
+		UnconfirmedBalance:       btcutil.Amount(unconfirmedBalanceSat).ToBTC(),
+		UnconfirmedBalanceSat:    unconfirmedBalanceSat,
+		UnconfirmedTxAppearances: int64(len(unconfirmed)),
+	}
+
+	if GetNoTxListCtx(r) == 0 && len(txids) > 0 {
+		addressInfo.TransactionsID = txids
+	}
+
+	writeJSON(w, addressInfo, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getSyncInfo(w http.ResponseWriter, r *http.Request) {
+	info, _, err := iapi.BlockData.MutilchainInsightStatus(iapi.chainType)
+	if err != nil {
+		s := err.Error()
+		writeJSON(w, apitypes.SyncResponse{
+			Status: "error",
+			Error:  &s,
+		}, m.GetIndentCtx(r))
+		return
+	}
+
+	height := iapi.BlockData.MutilchainHeight(iapi.chainType)
+	var syncPercentage int64
+	if info.Blocks > 0 {
+		syncPercentage = int64((float64(height) / float64(info.Blocks)) * 100)
+	}
+
+	st := "syncing"
+	if syncPercentage >= 100 {
+		st = "finished"
+	}
+
+	writeJSON(w, apitypes.SyncResponse{
+		Status:           st,
+		BlockChainHeight: info.Blocks,
+		SyncPercentage:   syncPercentage,
+		Height:           height,
+		Type:             "from RPC calls",
+	}, m.GetIndentCtx(r))
+}
+
+func (iapi *ChainInsightApi) getStatusInfo(w http.ResponseWriter, r *http.Request) {
+	info, bestHash, err := iapi.BlockData.MutilchainInsightStatus(iapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Error getting %s status: %v", iapi.chainType, err)
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, fmt.Sprintf("Error getting status (%q)", errStr))
+		return
+	}
+
+	switch r.FormValue("q") {
+	case "getDifficulty":
+		writeJSON(w, struct {
+			Difficulty float64 `json:"difficulty"`
+		}{
+			info.Difficulty,
+		}, m.GetIndentCtx(r))
+	case "getBestBlockHash":
+		writeJSON(w, struct {
+			BestBlockHash string `json:"bestblockhash"`
+		}{
+			bestHash,
+		}, m.GetIndentCtx(r))
+	case "getLastBlockHash":
+		height := iapi.BlockData.MutilchainHeight(iapi.chainType)
+		lastBlockHash, err := iapi.BlockData.GetMutilchainBlockHash(height, iapi.chainType)
+		if err != nil {
+			apiLog.Errorf("Error getting %s block hash %d (%s)", iapi.chainType, height, err)
+			errStr := html.EscapeString(err.Error())
+			writeInsightError(w, fmt.Sprintf("Error getting block hash %d (%q)", height, errStr))
+			return
+		}
+		writeJSON(w, struct {
+			SyncTipHash   string `json:"syncTipHash"`
+			LastBlockHash string `json:"lastblockhash"`
+		}{
+			bestHash,
+			lastBlockHash,
+		}, m.GetIndentCtx(r))
+	default:
+		writeJSON(w, info, m.GetIndentCtx(r))
+	}
+}
+
+func (iapi *ChainInsightApi) getEstimateFee(w http.ResponseWriter, r *http.Request) {
+	nbBlocks := GetNbBlocksCtx(r)
+	if nbBlocks == 0 {
+		nbBlocks = 2
+	}
+
+	feeRate, err := iapi.BlockData.MutilchainEstimateFee(int64(nbBlocks), iapi.chainType)
+	if err != nil {
+		apiLog.Errorf("Error estimating %s fee: %v", iapi.chainType, err)
+		errStr := html.EscapeString(err.Error())
+		writeInsightError(w, fmt.Sprintf("Error estimating fee (%s)", errStr))
+		return
+	}
+
+	writeJSON(w, map[string]float64{
+		strconv.Itoa(nbBlocks): feeRate,
+	}, m.GetIndentCtx(r))
+}
+
+// GetPeerStatus handles requests for the connection status of the chain's
+// node.
+func (iapi *ChainInsightApi) GetPeerStatus(w http.ResponseWriter, r *http.Request) {
+	_, _, err := iapi.BlockData.MutilchainInsightStatus(iapi.chainType)
+
+	var port *string
+	peerInfo := struct {
+		Connected bool    `json:"connected"`
+		Host      string  `json:"host"`
+		Port      *string `json:"port"`
+	}{
+		err == nil, "127.0.0.1", port,
+	}
+
+	writeJSON(w, peerInfo, m.GetIndentCtx(r))
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package insight
+
+import (
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	ltcjson "github.com/ltcsuite/ltcd/btcjson"
+
+	socketio "github.com/googollee/go-socket.io"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// MutilchainOutPointSource looks up the addresses and value of a previous
+// outpoint of a BTC or LTC transaction.
+type MutilchainOutPointSource interface {
+	IsMutilchainValidAddress(chainType string, address string) bool
+	MutilchainOutPointAddresses(txid string, vout uint32, chainType string) ([]string, int64, error)
+}
+
+// ChainSocketServer wraps the socket.io server of the Insight API of a BTC or
+// LTC chain with the watched address list.
+type ChainSocketServer struct {
+	*socketio.Server
+	chainType        string
+	watchedAddresses *roomSubscriptionCounter
+	outPoints        MutilchainOutPointSource
+}
+
+// chainSocketVin is an input of a BTC or LTC transaction.
+type chainSocketVin struct {
+	coinbase bool
+	txid     string
+	vout     uint32
+}
+
+// chainSocketVout is an output of a BTC or LTC transaction.
+type chainSocketVout struct {
+	addresses []string
+	value     float64
+}
+
+// NewChainSocketServer constructs a new ChainSocketServer for the chain,
+// registering handlers for the "connection", "disconnection", and "subscribe"
+// events. The address rooms are the valid addresses of the chain.
+func NewChainSocketServer(chainType string, outPoints MutilchainOutPointSource) (*ChainSocketServer, error) {
+	isValidAddress := func(addr string) bool {
+		return outPoints.IsMutilchainValidAddress(chainType, addr)
+	}
+	socketIOServer, addrs, err := newSocketIOServer("Insight "+chainType, isValidAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainSocketServer{
+		Server:           socketIOServer,
+		chainType:        chainType,
+		watchedAddresses: addrs,
+		outPoints:        outPoints,
+	}, nil
+}
+
+// BTCBlockHandler broadcasts the new BTC block hash to the inv room. This
+// method satisfies notification.BtcBlockHandler.
+func (soc *ChainSocketServer) BTCBlockHandler(block *mutilchain.BtcBlockHeader) error {
+	return soc.sendBlock(block.Hash.String())
+}
+
+// LTCBlockHandler broadcasts the new LTC block hash to the inv room. This
+// method satisfies notification.LtcBlockHandler.
+func (soc *ChainSocketServer) LTCBlockHandler(block *mutilchain.LtcBlockHeader) error {
+	return soc.sendBlock(block.Hash.String())
+}
+
+func (soc *ChainSocketServer) sendBlock(hash string) error {
+	apiLog.Debugf("Sending new %s websocket block %s", soc.chainType, hash)
+	soc.BroadcastToRoom("", "inv", "block", hash)
+	return nil
+}
+
+// SendBTCTx prepares a BTC mempool tx for broadcast. This method satisfies
+// notification.BtcTxHandler.
+func (soc *ChainSocketServer) SendBTCTx(rawTx *btcjson.TxRawResult) error {
+	vins := make([]chainSocketVin, 0, len(rawTx.Vin))
+	for i := range rawTx.Vin {
+		vins = append(vins, chainSocketVin{
+			coinbase: rawTx.Vin[i].IsCoinBase(),
+			txid:     rawTx.Vin[i].Txid,
+			vout:     rawTx.Vin[i].Vout,
+		})
+	}
+	vouts := make([]chainSocketVout, 0, len(rawTx.Vout))
+	for i := range rawTx.Vout {
+		spk := &rawTx.Vout[i].ScriptPubKey
+		addrs := spk.Addresses
+		if len(addrs) == 0 && spk.Address != "" {
+			addrs = []string{spk.Address}
+		}
+		vouts = append(vouts, chainSocketVout{
+			addresses: addrs,
+			value:     rawTx.Vout[i].Value,
+		})
+	}
+	return soc.sendNewTx(rawTx.Txid, len(rawTx.Hex)/2, vins, vouts)
+}
+
+// SendLTCTx prepares a LTC mempool tx for broadcast. This method satisfies
+// notification.LtcTxHandler.
+func (soc *ChainSocketServer) SendLTCTx(rawTx *ltcjson.TxRawResult) error {
+	vins := make([]chainSocketVin, 0, len(rawTx.Vin))
+	for i := range rawTx.Vin {
+		vins = append(vins, chainSocketVin{
+			coinbase: rawTx.Vin[i].IsCoinBase(),
+			txid:     rawTx.Vin[i].Txid,
+			vout:     rawTx.Vin[i].Vout,
+		})
+	}
+	vouts := make([]chainSocketVout, 0, len(rawTx.Vout))
+	for i := range rawTx.Vout {
+		spk := &rawTx.Vout[i].ScriptPubKey
+		addrs := spk.Addresses
+		if len(addrs) == 0 && spk.Address != "" {
+			addrs = []string{spk.Address}
+		}
+		vouts = append(vouts, chainSocketVout{
+			addresses: addrs,
+			value:     rawTx.Vout[i].Value,
+		})
+	}
+	return soc.sendNewTx(rawTx.Txid, len(rawTx.Hex)/2, vins, vouts)
+}
+
+// sendNewTx broadcasts a tx to the inv room, and its hash to the rooms of the
+// subscribed addresses paid by its outputs or its inputs' previous outputs.
+func (soc *ChainSocketServer) sendNewTx(hash string, size int, vins []chainSocketVin, vouts []chainSocketVout) error {
+	// Gather vins and their prevouts.
+	vinsInsight := make([]InsightSocketVin, 0, len(vins))
+	for _, v := range vins {
+		if v.coinbase {
+			// Coinbase inputs need to be "{}".
+			vinsInsight = append(vinsInsight, InsightSocketVin{})
+			continue
+		}
+		addrs, amt, err := soc.outPoints.MutilchainOutPointAddresses(v.txid, v.vout, soc.chainType)
+		if err != nil {
+			apiLog.Warnf("failed to get %s outpoint address from txid: %v", soc.chainType, err)
+			// Still must append this vin to maintain valid implicit indexing
+			// of vins array.
+		}
+		vinsInsight = append(vinsInsight, InsightSocketVin{
+			TxID:      v.txid,
+			Vout:      newUint32Ptr(v.vout),
+			Addresses: addrs,
+			Value:     newInt64Ptr(amt),
+		})
+	}
+
+	// All addresses that have client subscriptions, and are paid to by vouts
+	// and the vins' prevouts.
+	addrTxs := make(map[string]struct{})
+
+	var voutsInsight []InsightSocketVout
+	var total int64
+	soc.watchedAddresses.RLock()
+	for _, v := range vouts {
+		amt, err := btcutil.NewAmount(v.value)
+		if err != nil {
+			apiLog.Warnf("invalid %s output value %v in tx %s", soc.chainType, v.value, hash)
+		}
+		total += int64(amt)
+		for _, address := range v.addresses {
+			if _, ok := soc.watchedAddresses.c[address]; ok {
+				addrTxs[address] = struct{}{}
+			}
+			voutsInsight = append(voutsInsight, InsightSocketVout{
+				Address: address,
+				Value:   int64(amt),
+			})
+		}
+	}
+	for i := range vinsInsight {
+		for _, address := range vinsInsight[i].Addresses {
+			if _, ok := soc.watchedAddresses.c[address]; ok {
+				addrTxs[address] = struct{}{}
+			}
+		}
+	}
+	soc.watchedAddresses.RUnlock()
+
+	// Broadcast this tx hash to each relevant address room.
+	for address := range addrTxs {
+		soc.BroadcastToRoom("", address, address, hash)
+	}
+
+	// Broadcast the WebSocketTx data to add "inv" room subscribers.
+	tx := WebSocketTx{
+		Hash:     hash,
+		Size:     size,
+		TotalOut: total,
+		Vins:     vinsInsight,
+		Vouts:    voutsInsight,
+	}
+	apiLog.Tracef("Sending new %s websocket tx %s", soc.chainType, hash)
+	soc.BroadcastToRoom("", "inv", "tx", tx)
+	return nil
+}
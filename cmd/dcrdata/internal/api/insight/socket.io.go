@@ -92,6 +92,28 @@ type WebSocketTx struct {
 // NewSocketServer constructs a new SocketServer, registering handlers for the
 // "connection", "disconnection", and "subscribe" events.
 func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionGetter) (*SocketServer, error) {
+	isValidAddress := func(addr string) bool {
+		_, err := stdaddr.DecodeAddress(addr, params)
+		return err == nil
+	}
+	socketIOServer, addrs, err := newSocketIOServer("Insight", isValidAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SocketServer{
+		Server:           socketIOServer,
+		params:           params,
+		watchedAddresses: addrs,
+		txGetter:         txGetter,
+	}, nil
+}
+
+// newSocketIOServer creates and starts a socket.io server with handlers for the
+// "connection", "disconnection", and "subscribe" events. Subscriptions to an
+// address room are allowed for the addresses accepted by isValidAddress. The
+// subscriber counts of the address rooms are returned with the server.
+func newSocketIOServer(name string, isValidAddress func(string) bool) (*socketio.Server, *roomSubscriptionCounter, error) {
 	wsTrans := &websocket.Transport{
 		// Without this affirmative CheckOrigin, gorilla's "sensible default" is
 		// to ensure same origin.
@@ -104,10 +126,10 @@ func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionG
 		PingTimeout:  5 * time.Second,
 		Transports:   []transport.Transport{wsTrans},
 	}
-	socketIOServer, err := socketio.NewServer(opts)
+	server, err := socketio.NewServer(opts)
 	if err != nil {
 		apiLog.Errorf("Could not create socket.io server: %v", err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Each address subscription uses its own room, which has the same name as
@@ -116,13 +138,6 @@ func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionG
 		c: make(map[string]int),
 	}
 
-	server := &SocketServer{
-		Server:           socketIOServer,
-		params:           params,
-		watchedAddresses: addrs,
-		txGetter:         txGetter,
-	}
-
 	// OnConnect sets the address room subscription counter to 0. There are no
 	// default subscriptions. The client must subscribe to "inv" if they want
 	// notification of all new transactions. Note that OnConnect previously
@@ -139,7 +154,7 @@ func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionG
 	})
 
 	// Subscription to a room checks the room name is a valid subscription
-	// (currently just "inv" or a valid Decred address), joins the room, and
+	// (currently just "inv" or a valid address), joins the room, and
 	// increments the room's subscriber count.
 	server.OnEvent("", "subscribe", func(so socketio.Conn, room string) string {
 		switch room {
@@ -152,8 +167,8 @@ func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionG
 			return "error: " + msg
 		}
 
-		// See if the room is a Decred address.
-		if _, err = stdaddr.DecodeAddress(room, params); err != nil {
+		// See if the room is an address.
+		if !isValidAddress(room) {
 			apiLog.Debugf("socket.io connection %s requested invalid subscription: %s",
 				so.ID(), room)
 			msg := fmt.Sprintf(`invalid subscription "%s"`, room)
@@ -202,13 +217,13 @@ func NewSocketServer(params *chaincfg.Params, txGetter txhelpers.RawTransactionG
 	})
 
 	server.OnError("", func(_ socketio.Conn, err error) {
-		apiLog.Errorf("Insight socket.io server error: %v", err)
+		apiLog.Errorf("%s socket.io server error: %v", name, err)
 	})
 
-	apiLog.Infof("Started Insight socket.io server.")
+	apiLog.Infof("Started %s socket.io server.", name)
 
 	go server.Serve()
-	return server, nil
+	return server, addrs, nil
 }
 
 // Store broadcasts the lastest block hash to the the inv room. The coinbase
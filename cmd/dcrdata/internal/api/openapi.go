@@ -0,0 +1,597 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/go-chi/chi/v5"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+)
+
+// openAPIVersion is the version of the OpenAPI specification that the
+// generated document conforms to.
+const openAPIVersion = "3.0.3"
+
+// openAPIDoc is the root of an OpenAPI 3 document. Only the parts of the
+// specification used to describe the dcrdata APIs are modeled.
+type openAPIDoc struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       openAPIInfo                `json:"info"`
+	Servers    []openAPIServer            `json:"servers"`
+	Tags       []openAPITag               `json:"tags,omitempty"`
+	Paths      map[string]openAPIPathItem `json:"paths"`
+	Components openAPIComponents          `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPITag struct {
+	Name string `json:"name"`
+}
+
+// openAPIPathItem maps lower case HTTP methods to operations on a path.
+type openAPIPathItem map[string]*openAPIOperation
+
+type openAPIOperation struct {
+	Summary     string                      `json:"summary,omitempty"`
+	OperationID string                      `json:"operationId"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+// routeDoc documents a single method and path of an API router. The route's
+// path and query parameters bound by middleware are described by the
+// middlewareDocs table, so only handler specific details are given here.
+type routeDoc struct {
+	summary string
+	// response is the type of the response body, nil if there is no body.
+	response reflect.Type
+	// contentType is the response media type. It defaults to application/json.
+	contentType string
+	// query lists URL query parameters read directly by the handler.
+	query      []*openAPIParameter
+	deprecated bool
+}
+
+// middlewareDoc describes the parameters that a middleware extracts from the
+// request and stores in the request context.
+type middlewareDoc struct {
+	params []*openAPIParameter
+	// body is the type of the JSON request body decoded by the middleware.
+	body reflect.Type
+}
+
+// typeOf returns the reflect.Type of T, which may be an interface type.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func pathParam(name, typ, desc string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "path", Required: true,
+		Description: desc, Schema: &openAPISchema{Type: typ}}
+}
+
+func queryParam(name, typ, desc string) *openAPIParameter {
+	return &openAPIParameter{Name: name, In: "query", Description: desc,
+		Schema: &openAPISchema{Type: typ}}
+}
+
+func enumParam(p *openAPIParameter, values ...string) *openAPIParameter {
+	p.Schema.Enum = values
+	return p
+}
+
+// apiRoute is a GET or POST route found by walking a router.
+type apiRoute struct {
+	method      string
+	path        string
+	middlewares []string
+}
+
+// key is the routeDocs key of the route.
+func (rt *apiRoute) key() string {
+	return rt.method + " " + rt.path
+}
+
+// documentedRouter is a router mounted at prefix that is included in the
+// OpenAPI document. If tag is set, all of the router's operations use it,
+// otherwise the first path element after the prefix is the tag.
+type documentedRouter struct {
+	prefix string
+	tag    string
+	routes chi.Routes
+}
+
+// walkAPIRoutes lists the GET and POST routes of the routers with normalized
+// full paths, sorted by path and method. Routes registered for every method
+// with HandleFunc are treated as GET routes.
+func walkAPIRoutes(routers []documentedRouter) ([]*apiRoute, error) {
+	var routes []*apiRoute
+	for _, dr := range routers {
+		methods := make(map[string]map[string][]string)
+		walkFn := func(method, pattern string, _ http.Handler, mws ...func(http.Handler) http.Handler) error {
+			path := dr.prefix + normalizeRoutePattern(pattern)
+			if methods[path] == nil {
+				methods[path] = make(map[string][]string)
+			}
+			names := make([]string, 0, len(mws))
+			for _, mw := range mws {
+				names = append(names, middlewareName(mw))
+			}
+			methods[path][method] = names
+			return nil
+		}
+		if err := chi.Walk(dr.routes, walkFn); err != nil {
+			return nil, err
+		}
+		for path, byMethod := range methods {
+			if len(byMethod) == len(allHTTPMethods) {
+				routes = append(routes, &apiRoute{http.MethodGet, path, byMethod[http.MethodGet]})
+				continue
+			}
+			for _, method := range []string{http.MethodGet, http.MethodPost} {
+				if mws, ok := byMethod[method]; ok {
+					routes = append(routes, &apiRoute{method, path, mws})
+				}
+			}
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path == routes[j].path {
+			return routes[i].method < routes[j].method
+		}
+		return routes[i].path < routes[j].path
+	})
+	return routes, nil
+}
+
+var allHTTPMethods = []string{http.MethodConnect, http.MethodDelete, http.MethodGet,
+	http.MethodHead, http.MethodOptions, http.MethodPatch, http.MethodPost,
+	http.MethodPut, http.MethodTrace}
+
+// normalizeRoutePattern converts a chi route pattern, which may include the
+// wildcards of sub-routers and trailing slashes, into an OpenAPI path.
+func normalizeRoutePattern(pattern string) string {
+	pattern = strings.ReplaceAll(pattern, "/*/", "/")
+	pattern = strings.TrimSuffix(pattern, "/*")
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	if pattern == "" {
+		pattern = "/"
+	}
+	return pattern
+}
+
+var funcSuffixRE = regexp.MustCompile(`^(func)?[0-9]+$`)
+
+// middlewareName returns a short name like "middleware.NPathCtx" for a
+// middleware function. Method values and closures returned by middleware
+// constructors are named after the method or constructor.
+func middlewareName(mw func(http.Handler) http.Handler) string {
+	name := runtime.FuncForPC(reflect.ValueOf(mw).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	var parts []string
+	for _, p := range strings.Split(name, ".") {
+		p = strings.TrimSuffix(p, "-fm")
+		if strings.HasPrefix(p, "(") || funcSuffixRE.MatchString(p) {
+			continue
+		}
+		parts = append(parts, p)
+	}
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
+
+var pathParamRE = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// buildOpenAPIDoc creates the OpenAPI document for the given routes.
+// Undocumented routes are included with a generic operation so that the
+// document always describes every route.
+func buildOpenAPIDoc(routers []documentedRouter, routes []*apiRoute) *openAPIDoc {
+	gen := newSchemaGenerator()
+	doc := &openAPIDoc{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:       "dcrdata API",
+			Description: "Block explorer REST and Insight APIs for Decred and the other supported chains.",
+			Version:     strconv.Itoa(APIVersion),
+		},
+		Servers: []openAPIServer{{URL: "/"}},
+		Paths:   make(map[string]openAPIPathItem),
+	}
+
+	tags := make(map[string]bool)
+	errResp := &openAPIResponse{
+		Description: "Error. The body is a plain text message.",
+		Content:     map[string]openAPIMediaType{"text/plain": {Schema: &openAPISchema{Type: "string"}}},
+	}
+
+	for _, rt := range routes {
+		rd, found := routeDocs[rt.key()]
+		op := &openAPIOperation{
+			Summary:     rd.summary,
+			OperationID: operationID(rt.method, rt.path),
+			Deprecated:  rd.deprecated,
+			Responses:   map[string]*openAPIResponse{"default": errResp},
+		}
+		if !found {
+			op.Summary = "Undocumented route."
+		}
+
+		for _, dr := range routers {
+			if !strings.HasPrefix(rt.path, dr.prefix+"/") {
+				continue
+			}
+			tag := dr.tag
+			if tag == "" {
+				tag = strings.SplitN(strings.TrimPrefix(rt.path, dr.prefix+"/"), "/", 2)[0]
+			}
+			if tag != "" {
+				op.Tags = []string{tag}
+				tags[tag] = true
+			}
+			break
+		}
+
+		// Path and query parameters from the middlewares, and the handler's
+		// own query parameters.
+		inPath := make(map[string]bool)
+		for _, match := range pathParamRE.FindAllStringSubmatch(rt.path, -1) {
+			inPath[match[1]] = true
+		}
+		seen := make(map[string]bool)
+		addParam := func(p *openAPIParameter) {
+			if seen[p.In+p.Name] || (p.In == "path" && !inPath[p.Name]) {
+				return
+			}
+			seen[p.In+p.Name] = true
+			op.Parameters = append(op.Parameters, p)
+		}
+		var body reflect.Type
+		for _, name := range rt.middlewares {
+			md := middlewareDocs[name]
+			for _, p := range md.params {
+				addParam(p)
+			}
+			if md.body != nil {
+				body = md.body
+			}
+		}
+		for _, match := range pathParamRE.FindAllStringSubmatch(rt.path, -1) {
+			if p, ok := pathParamDocs[match[1]]; ok {
+				addParam(p)
+			} else {
+				addParam(pathParam(match[1], "string", ""))
+			}
+		}
+		for _, p := range rd.query {
+			addParam(p)
+		}
+		// Present the parameters in path order, then query parameters.
+		sort.SliceStable(op.Parameters, func(i, j int) bool {
+			pi, pj := op.Parameters[i], op.Parameters[j]
+			if pi.In != pj.In {
+				return pi.In == "path"
+			}
+			if pi.In == "path" {
+				return strings.Index(rt.path, "{"+pi.Name) < strings.Index(rt.path, "{"+pj.Name)
+			}
+			return false
+		})
+
+		if body != nil {
+			op.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: gen.schemaOf(body)}},
+			}
+		}
+
+		okResp := &openAPIResponse{Description: "OK"}
+		if rd.response != nil {
+			contentType := rd.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			okResp.Content = map[string]openAPIMediaType{contentType: {Schema: gen.schemaOf(rd.response)}}
+		}
+		op.Responses["200"] = okResp
+
+		// OpenAPI path templates may not include chi's regexp patterns.
+		path := pathParamRE.ReplaceAllString(rt.path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(openAPIPathItem)
+		}
+		doc.Paths[path][strings.ToLower(rt.method)] = op
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, openAPITag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	doc.Components.Schemas = gen.schemas
+	return doc
+}
+
+var nonAlphaNumRE = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// operationID creates a unique operation ID from the method and path, e.g.
+// get_api_block_idx_verbose for GET /api/block/{idx}/verbose.
+func operationID(method, path string) string {
+	return strings.ToLower(method) + "_" + strings.Trim(nonAlphaNumRE.ReplaceAllString(path, "_"), "_")
+}
+
+// schemaGenerator creates JSON schemas for Go types, following the rules of
+// encoding/json. Named struct types are added to the components of the
+// document and referenced.
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+	names   map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		schemas: make(map[string]*openAPISchema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+var (
+	jsonMarshalerType = typeOf[json.Marshaler]()
+
+	// knownSchemas are the schemas of types with custom JSON marshalling.
+	knownSchemas = map[reflect.Type]*openAPISchema{
+		typeOf[time.Time]():                {Type: "string", Format: "date-time"},
+		typeOf[dbtypes.TimeDef]():          {Type: "string", Format: "date-time"},
+		typeOf[apitypes.TimeAPI]():         {Type: "integer", Format: "int64", Description: "UNIX time"},
+		typeOf[dbtypes.ScriptClass]():      {Type: "string"},
+		typeOf[dbtypes.AgendaStatusType](): {Type: "string"},
+		typeOf[json.RawMessage]():          {},
+	}
+)
+
+func (g *schemaGenerator) schemaOf(t reflect.Type) *openAPISchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s, ok := knownSchemas[t]; ok {
+		cp := *s
+		return &cp
+	}
+	// Any other type with custom marshalling is described as any value.
+	if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+		return &openAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Array:
+		return &openAPISchema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name, ok := g.names[t]
+		if !ok {
+			name = g.componentName(t)
+			g.names[t] = name
+			// Reserve the name before generating the properties, which may
+			// refer back to this type.
+			g.schemas[name] = &openAPISchema{}
+			*g.schemas[name] = *g.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	}
+	// Interfaces, and anything else that cannot be described.
+	return &openAPISchema{}
+}
+
+var (
+	componentNameRE = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	majorVersionRE  = regexp.MustCompile(`^v[0-9]+$`)
+)
+
+// componentName names the schema of a named type after its package and type
+// name, e.g. apitypes.BlockDataBasic, adding a suffix if the name is taken.
+func (g *schemaGenerator) componentName(t reflect.Type) string {
+	pkg := strings.Split(t.PkgPath(), "/")
+	// Skip major version suffixes of module paths.
+	last := pkg[len(pkg)-1]
+	if len(pkg) > 1 && majorVersionRE.MatchString(last) {
+		pkg = pkg[:len(pkg)-1]
+		last = pkg[len(pkg)-1]
+	}
+	// Qualify the many packages named types, e.g. apitypes.
+	if last == "types" && len(pkg) > 1 {
+		last = pkg[len(pkg)-2] + last
+	}
+	// Unexported response types of this package are exported in the schema.
+	name := t.Name()
+	name = strings.ToUpper(name[:1]) + name[1:]
+	base := componentNameRE.ReplaceAllString(last+"."+name, "_")
+	name = base
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields adds the JSON encoded fields of the struct type t, including the
+// fields of embedded structs, to the properties of s.
+func (g *schemaGenerator) addFields(s *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.addFields(s, ft)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.Contains(opts, "string") {
+			s.Properties[name] = &openAPISchema{Type: "string"}
+			continue
+		}
+		s.Properties[name] = g.schemaOf(f.Type)
+	}
+}
+
+// openAPISpec builds the OpenAPI document of the API router and any other
+// documented routers on first request.
+type openAPISpec struct {
+	mtx     sync.Mutex
+	routers []documentedRouter
+	doc     []byte
+}
+
+// document adds a router to the spec, discarding any previously built
+// document.
+func (s *openAPISpec) document(dr documentedRouter) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.routers = append(s.routers, dr)
+	s.doc = nil
+}
+
+func (s *openAPISpec) json() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.doc != nil {
+		return s.doc, nil
+	}
+	routes, err := walkAPIRoutes(s.routers)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(buildOpenAPIDoc(s.routers, routes))
+	if err != nil {
+		return nil, err
+	}
+	s.doc = b
+	return b, nil
+}
+
+func (s *openAPISpec) serve(w http.ResponseWriter, r *http.Request) {
+	b, err := s.json()
+	if err != nil {
+		apiLog.Errorf("Failed to build OpenAPI document: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if indent := m.GetIndentCtx(r); indent != "" {
+		var buf bytes.Buffer
+		if err = json.Indent(&buf, b, "", indent); err == nil {
+			b = buf.Bytes()
+		}
+	}
+	writeJSONBytes(w, b)
+}
+
+// DocumentRouter includes the routes of a router mounted at prefix, such as
+// the Insight API router, in the OpenAPI document served at /openapi.json.
+// All of the router's operations are tagged with tag.
+func (mux apiMux) DocumentRouter(prefix, tag string, r chi.Routes) {
+	mux.spec.document(documentedRouter{prefix: prefix, tag: tag, routes: r})
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/esplora"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/insight"
+)
+
+func testDocumentedRouters() []documentedRouter {
+	apiMux := NewAPIRouter(&appContext{}, "", false, false)
+	insightMux := insight.NewInsightAPIRouter(&insight.InsightApi{}, false, false, 10)
+	routers := []documentedRouter{
+		{prefix: "/api", routes: apiMux.Mux},
+		{prefix: "/insight/api", tag: "insight", routes: insightMux.Mux},
+	}
+	for _, chainType := range chainInsightTypes {
+		chainMux := insight.NewChainInsightAPIRouter(&insight.ChainInsightApi{}, false, false, 10)
+		routers = append(routers, documentedRouter{prefix: "/insight/" + chainType + "/api",
+			tag: "insight-" + chainType, routes: chainMux.Mux})
+		esploraMux := esplora.NewEsploraAPIRouter(&esplora.EsploraApi{}, false, false)
+		routers = append(routers, documentedRouter{prefix: "/esplora/" + chainType + "/api",
+			tag: "esplora-" + chainType, routes: esploraMux.Mux})
+	}
+	return routers
+}
+
+// TestOpenAPIRoutesDocumented ensures that every GET and POST route of the API
+// and Insight API routers is documented, and that routeDocs has no entries
+// for routes that no longer exist.
+func TestOpenAPIRoutesDocumented(t *testing.T) {
+	routes, err := walkAPIRoutes(testDocumentedRouters())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	routed := make(map[string]bool, len(routes))
+	for _, rt := range routes {
+		routed[rt.key()] = true
+		if _, ok := routeDocs[rt.key()]; !ok {
+			t.Errorf("route %q is not documented in routeDocs", rt.key())
+		}
+		for _, match := range pathParamRE.FindAllStringSubmatch(rt.path, -1) {
+			found := pathParamDocs[match[1]] != nil
+			for _, mw := range rt.middlewares {
+				for _, p := range middlewareDocs[mw].params {
+					found = found || (p.In == "path" && p.Name == match[1])
+				}
+			}
+			if !found {
+				t.Errorf("path parameter %q of route %q is not documented", match[1], rt.key())
+			}
+		}
+	}
+	for key := range routeDocs {
+		if !routed[key] {
+			t.Errorf("routeDocs entry %q does not match a route", key)
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	routers := testDocumentedRouters()
+	routes, err := walkAPIRoutes(routers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := buildOpenAPIDoc(routers, routes)
+
+	op := doc.Paths["/api/block/range/{idx0}/{idx}/{step}"]["get"]
+	if op == nil {
+		t.Fatal("missing operation for GET /api/block/range/{idx0}/{idx}/{step}")
+	}
+	var names []string
+	for _, p := range op.Parameters {
+		names = append(names, p.In+":"+p.Name)
+	}
+	if got, want := strings.Join(names, ","), "path:idx0,path:idx,path:step,query:indent"; got != want {
+		t.Errorf("wrong parameters, got %s, want %s", got, want)
+	}
+
+	op = doc.Paths["/insight/api/addrs/txs"]["post"]
+	if op == nil || op.RequestBody == nil {
+		t.Fatal("missing request body for POST /insight/api/addrs/txs")
+	}
+	if op.Tags[0] != "insight" {
+		t.Errorf("wrong tag %q", op.Tags[0])
+	}
+
+	// Every schema reference must resolve.
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const refPrefix = `"$ref":"#/components/schemas/`
+	for _, s := range strings.Split(string(b), refPrefix)[1:] {
+		name := s[:strings.IndexByte(s, '"')]
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("unresolved schema reference %q", name)
+		}
+	}
+}
+
+func TestOpenAPIServe(t *testing.T) {
+	apiMux := NewAPIRouter(&appContext{}, "", false, false)
+	insightMux := insight.NewInsightAPIRouter(&insight.InsightApi{}, false, false, 10)
+	apiMux.DocumentRouter("/insight/api", "insight", insightMux.Mux)
+
+	srv := httptest.NewServer(http.StripPrefix("/api", apiMux))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status code %d", resp.StatusCode)
+	}
+	var doc openAPIDoc
+	if err = json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != openAPIVersion {
+		t.Errorf("wrong version %q", doc.OpenAPI)
+	}
+	if doc.Paths["/insight/api/status"] == nil || doc.Paths["/api/openapi.json"] == nil {
+		t.Errorf("missing paths")
+	}
+}
@@ -0,0 +1,621 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"strings"
+
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrdata/exchanges/v3"
+	pitypes "github.com/decred/dcrdata/gov/v6/politeia/types"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/txhelpers"
+)
+
+// middlewareDocs describes the parameters extracted by the request context
+// middlewares, keyed by the short middleware names given by middlewareName.
+// Path parameters are only applied to routes that include them.
+var middlewareDocs = map[string]middlewareDoc{
+	"middleware.Indent": {params: []*openAPIParameter{
+		queryParam("indent", "boolean", "Indent the JSON response."),
+	}},
+	"middleware.BlockIndexPathCtx": {params: []*openAPIParameter{
+		pathParam("idx", "integer", "Block height."),
+	}},
+	"middleware.BlockIndex0PathCtx": {params: []*openAPIParameter{
+		pathParam("idx0", "integer", "First block height of the range."),
+	}},
+	"middleware.BlockIndexOrHashPathCtx": {params: []*openAPIParameter{
+		pathParam("idxorhash", "string", "Block height or block hash."),
+	}},
+	"middleware.BlockStepPathCtx": {params: []*openAPIParameter{
+		pathParam("step", "integer", "Block height step."),
+	}},
+	"api.BlockHashPathAndIndexCtx": {params: []*openAPIParameter{
+		pathParam("blockhash", "string", "Block hash."),
+	}},
+	"middleware.TransactionHashCtx": {params: []*openAPIParameter{
+		pathParam("txid", "string", "Transaction hash."),
+	}},
+	"middleware.MultichainTxHashCtx": {params: []*openAPIParameter{
+		enumParam(pathParam("chaintype", "string", "Chain type."), chainTypes...),
+		pathParam("txid", "string", "Transaction hash."),
+	}},
+	"middleware.ChainTypePathCtx": {params: []*openAPIParameter{
+		enumParam(pathParam("chaintype", "string", "Chain type."), chainTypes...),
+	}},
+	"api.ChainBlockHashCtx": {params: []*openAPIParameter{
+		pathParam("blockhash", "string", "Block hash."),
+		pathParam("idx", "integer", "Block height."),
+	}},
+	"middleware.TransactionIOIndexCtx": {params: []*openAPIParameter{
+		pathParam("txinoutindex", "integer", "Transaction input or output index."),
+	}},
+	"middleware.AddressPathCtxN": {params: []*openAPIParameter{
+		pathParam("address", "string", "Address, or a comma-separated list of addresses where accepted."),
+	}},
+	"middleware.ChartGroupingCtx": {params: []*openAPIParameter{
+		enumParam(pathParam("chartgrouping", "string", "Time grouping of the chart data."),
+			"all", "year", "month", "week", "day"),
+	}},
+	"middleware.ChartTypeCtx": {params: []*openAPIParameter{
+		pathParam("charttype", "string", "Chart name, e.g. ticket-price."),
+	}},
+	"middleware.NPathCtx": {params: []*openAPIParameter{
+		pathParam("N", "integer", "Number of items to return."),
+	}},
+	"middleware.MPathCtx": {params: []*openAPIParameter{
+		pathParam("M", "integer", "Number of items to skip."),
+	}},
+	"middleware.ExchangeTokenContext": {params: []*openAPIParameter{
+		pathParam("token", "string", "Exchange token, e.g. binance."),
+	}},
+	"middleware.StickWidthContext": {params: []*openAPIParameter{
+		pathParam("bin", "string", "Candlestick width, e.g. 1h or 1d."),
+	}},
+	"middleware.TicketPoolCtx": {params: []*openAPIParameter{
+		enumParam(pathParam("tp", "string", "Time grouping of the ticket pool."),
+			"all", "year", "month", "week", "day"),
+	}},
+	"middleware.ProposalTokenCtx": {params: []*openAPIParameter{
+		pathParam("token", "string", "Politeia proposal token."),
+	}},
+	"middleware.AgendaIdCtx": {params: []*openAPIParameter{
+		pathParam("agendaId", "string", "Consensus agenda ID."),
+	}},
+	"middleware.TSpendVotesIdCtx": {params: []*openAPIParameter{
+		pathParam("txhash", "string", "Treasury spend transaction hash."),
+	}},
+	"middleware.PostTxnsCtx": {body: typeOf[apitypes.Txns]()},
+	"middleware.TransactionsCtx": {params: []*openAPIParameter{
+		queryParam("block", "string", "Block hash. Either block or address is required."),
+		queryParam("address", "string", "Address. Either block or address is required."),
+	}},
+	"middleware.PageNumCtx": {params: []*openAPIParameter{
+		queryParam("pageNum", "integer", "Page number, starting at 1."),
+	}},
+	"middleware.PostBroadcastTxCtx": {body: typeOf[apitypes.InsightRawTx]()},
+
+	// Insight API middlewares.
+	"insight.BlockDateLimitQueryCtx": {params: []*openAPIParameter{
+		queryParam("blockDate", "string", "Date of the blocks in YYYY-MM-DD format."),
+		queryParam("limit", "integer", "Maximum number of blocks."),
+	}},
+	"insight.FromToPaginationCtx": {params: []*openAPIParameter{
+		queryParam("from", "integer", "Index of the first item."),
+		queryParam("to", "integer", "Index after the last item."),
+	}},
+	"insight.NoTxListCtx": {params: []*openAPIParameter{
+		queryParam("noTxList", "integer", "Omit the transaction list if 1."),
+	}},
+	"insight.AddressCommandCtx": {params: []*openAPIParameter{
+		enumParam(pathParam("command", "string", "Address property."),
+			"balance", "totalReceived", "totalSent", "unconfirmedBalance"),
+	}},
+	"insight.NbBlocksCtx": {params: []*openAPIParameter{
+		queryParam("nbBlocks", "integer", "Number of blocks for the estimate."),
+	}},
+	"insight.StatusInfoCtx": {params: []*openAPIParameter{
+		enumParam(queryParam("q", "string", "Status query."),
+			"getInfo", "getDifficulty", "getBestBlockHash", "getLastBlockHash"),
+	}},
+	"insight.postAddrsTxsCtxN":  {body: typeOf[apitypes.InsightMultiAddrsTx]()},
+	"insight.postAddrsUtxoCtxN": {body: typeOf[apitypes.InsightAddr]()},
+
+	// Esplora API middlewares.
+	"esplora.TxidPathCtx": {params: []*openAPIParameter{
+		pathParam("txid", "string", "Transaction hash."),
+	}},
+	"esplora.VoutPathCtx": {params: []*openAPIParameter{
+		pathParam("vout", "integer", "Transaction output index."),
+	}},
+	"esplora.AddressPathCtx": {params: []*openAPIParameter{
+		pathParam("address", "string", "Address."),
+	}},
+	"esplora.ScripthashPathCtx": {params: []*openAPIParameter{
+		pathParam("hash", "string", "SHA256 hash of the output script, in hex."),
+	}},
+	"esplora.LastSeenTxidPathCtx": {params: []*openAPIParameter{
+		pathParam("last_seen_txid", "string", "Hash of the last transaction of the previous page."),
+	}},
+	"esplora.BlockHashPathCtx": {params: []*openAPIParameter{
+		pathParam("hash", "string", "Block hash."),
+	}},
+	"esplora.TxIndexPathCtx": {params: []*openAPIParameter{
+		pathParam("index", "integer", "Transaction index in the block."),
+	}},
+	"esplora.StartIndexPathCtx": {params: []*openAPIParameter{
+		pathParam("start_index", "integer", "Index of the first transaction, a multiple of 25."),
+	}},
+	"esplora.HeightPathCtx": {params: []*openAPIParameter{
+		pathParam("height", "integer", "Block height."),
+	}},
+	"esplora.StartHeightPathCtx": {params: []*openAPIParameter{
+		pathParam("start_height", "integer", "Height of the first block."),
+	}},
+}
+
+// chainTypes are the values of the chaintype path parameter.
+var chainTypes = []string{"btc", "ltc", "xmr"}
+
+// pathParamDocs describes path parameters that are not bound by a middleware
+// reported by chi.Walk, either because the handler reads them directly or
+// because the middleware is applied to a route group.
+var pathParamDocs = map[string]*openAPIParameter{
+	"address":   pathParam("address", "string", "Address."),
+	"chaintype": enumParam(pathParam("chaintype", "string", "Chain type."), chainTypes...),
+	"chain":     enumParam(pathParam("chain", "string", "Chain type, including Decred."), append([]string{"dcr"}, chainTypes...)...),
+	"addresses": pathParam("addresses", "string", "Comma-separated list of addresses."),
+}
+
+var (
+	txSpendsQuery = queryParam("spends", "boolean", "Include the spending transaction of each output.")
+	codeQuery     = queryParam("code", "string", "Fiat currency code for converted values.")
+	denomQuery    = queryParam("denom", "integer", "Mix denomination in atoms.")
+	cursorQuery   = queryParam("cursor", "string", "Opaque page cursor from the next_cursor of the previous page, or empty for the first page. Replaces the skip offset.")
+	currencyQuery = queryParam("currency", "string", "Fiat currency code of the daily price history. Sets the fiat_value at block time.")
+)
+
+// Response bodies of handlers that create them ad hoc.
+type (
+	mixTxnsResponse struct {
+		Total      int64            `json:"total"`
+		Txns       []*dbtypes.MixTx `json:"txs"`
+		NextCursor string           `json:"next_cursor,omitempty"`
+	}
+	ticketPoolByDateResponse struct {
+		Height    int64                    `json:"height"`
+		TimeChart *dbtypes.PoolTicketsData `json:"time_chart"`
+	}
+	chainExchangesResponse []struct {
+		ChainType string                       `json:"chain_type"`
+		Exchanges []*exchanges.TokenedExchange `json:"exchanges,omitempty"`
+	}
+	blocksRewardResponse struct {
+		RewardMap map[int64]float64 `json:"rewardMap"`
+	}
+	insightBlockHashResponse struct {
+		BlockHash string `json:"blockHash"`
+	}
+	insightRawBlockResponse struct {
+		BlockHash string `json:"rawblock"`
+	}
+	insightTxIDResponse struct {
+		TxidHash string `json:"txid"`
+	}
+	insightPeerResponse struct {
+		Connected bool    `json:"connected"`
+		Host      string  `json:"host"`
+		Port      *string `json:"port"`
+	}
+)
+
+var (
+	textResponse = typeOf[string]()
+	// anyResponse is used for handlers that write pre-encoded JSON or JSON
+	// that depends on the request.
+	anyResponse = typeOf[interface{}]()
+
+	blockSummaryDoc = routeDoc{summary: "Block summary.", response: typeOf[*apitypes.BlockDataBasic](),
+		query: []*openAPIParameter{queryParam("txtotals", "boolean", "Include transaction totals.")}}
+	blockHashDoc      = routeDoc{summary: "Block hash.", response: textResponse, contentType: "text/plain"}
+	blockHeightDoc    = routeDoc{summary: "Block height.", response: textResponse, contentType: "text/plain"}
+	blockHeaderDoc    = routeDoc{summary: "Block header.", response: typeOf[*chainjson.GetBlockHeaderVerboseResult]()}
+	blockHeaderRawDoc = routeDoc{summary: "Serialized block header.", response: typeOf[*apitypes.BlockRaw]()}
+	blockRawDoc       = routeDoc{summary: "Serialized block.", response: typeOf[*apitypes.BlockRaw]()}
+	blockSizeDoc      = routeDoc{summary: "Block size in bytes.", response: typeOf[int32]()}
+	blockSubsidyDoc   = routeDoc{summary: "Block subsidies.", response: typeOf[apitypes.BlockSubsidies]()}
+	blockVerboseDoc   = routeDoc{summary: "Verbose block data.", response: typeOf[*chainjson.GetBlockVerboseResult]()}
+	blockPoSDoc       = routeDoc{summary: "Block stake info.", response: typeOf[*apitypes.StakeInfoExtended]()}
+	blockTxnsDoc      = routeDoc{summary: "Transactions of the block.", response: typeOf[*apitypes.BlockTransactions]()}
+	blockTxCountDoc   = routeDoc{summary: "Number of transactions of the block.", response: typeOf[*apitypes.BlockTransactionCounts]()}
+
+	chainBlockSummaryDoc = routeDoc{summary: "Block summary.", response: typeOf[*apitypes.ChainBlockSummary]()}
+	chainBlockSizeDoc    = routeDoc{summary: "Block size in bytes.", response: typeOf[int64]()}
+	chainBlockSubsidyDoc = routeDoc{summary: "Block reward, subsidy and fees.", response: typeOf[*apitypes.ChainBlockSubsidy]()}
+	chainBlockTxnsDoc    = routeDoc{summary: "Transactions of the block.", response: typeOf[*apitypes.ChainBlockTransactions]()}
+	chainBlockTxCountDoc = routeDoc{summary: "Number of transactions of the block.", response: typeOf[int]()}
+	chainAddressTxnsDoc  = routeDoc{summary: "Address transactions.", response: typeOf[*apitypes.Address](),
+		query: []*openAPIParameter{currencyQuery}}
+
+	addressTxnsDoc = routeDoc{summary: "Address transactions.", response: typeOf[*apitypes.Address](),
+		query: []*openAPIParameter{cursorQuery, currencyQuery}}
+	addressRawDoc = routeDoc{summary: "Raw address transactions.", response: typeOf[[]*apitypes.AddressTxRaw]()}
+	chartDataDoc  = routeDoc{summary: "Chart data.", response: typeOf[*dbtypes.ChartsData]()}
+	rawChartDoc   = routeDoc{summary: "Chart data.", response: anyResponse}
+	mixTxnsDoc    = routeDoc{summary: "Mix transactions, most recent first.", response: typeOf[mixTxnsResponse](),
+		query: []*openAPIParameter{denomQuery, cursorQuery}}
+	mixStatsDoc = routeDoc{summary: "Mix statistics of a block range.", response: typeOf[[]*dbtypes.MixStats]()}
+
+	fiatFlowDoc = routeDoc{summary: "Amounts received and sent by the address, valued at the daily price of their block time.",
+		response: typeOf[*dbtypes.ChartsData](), query: []*openAPIParameter{queryParam("currency", "string", "Fiat currency code. Defaults to USD.")}}
+	chainFiatFlowDoc = routeDoc{summary: "Amounts received and sent by the address, valued at the daily price of their block time. " +
+		"XMR is not supported (422), since the amounts of an XMR address are only known with its view key.",
+		response: fiatFlowDoc.response, query: fiatFlowDoc.query}
+
+	liquidityChartDoc = routeDoc{summary: "Mid-gap, spread, and depth within 2% of the mid-gap of the stored order book snapshots of an exchange.",
+		response: anyResponse}
+
+	insightBlockDoc = routeDoc{summary: "Insight block.", response: typeOf[[]*apitypes.InsightBlockResult]()}
+	insightUTXODoc  = routeDoc{summary: "Unspent outputs of the addresses.", response: typeOf[[]*apitypes.AddressTxnOutput]()}
+	insightTxnsDoc  = routeDoc{summary: "Transactions of the addresses.", response: typeOf[*apitypes.InsightMultiAddrsTxOutput]()}
+)
+
+// routeDocs documents every GET and POST route of the API and Insight API
+// routers, keyed by method and full path. TestOpenAPIRoutesDocumented checks
+// that the table and the routers agree.
+var routeDocs = map[string]routeDoc{
+	"GET /api/":                     {summary: "API liveness message.", response: textResponse, contentType: "text/plain"},
+	"GET /api/list":                 {summary: "List of API route patterns.", response: typeOf[[]string]()},
+	"GET /api/openapi.json":         {summary: "This OpenAPI document.", response: anyResponse},
+	"GET /api/status":               {summary: "Status of the node and the databases.", response: typeOf[*apitypes.APIStatus]()},
+	"GET /api/status/happy":         {summary: "Health summary. The status code is 503 if unhappy.", response: typeOf[apitypes.Happy]()},
+	"GET /api/status/ready":         {summary: "Health of all the enabled chains. The status code is 503 if any chain is failing.", response: typeOf[*apitypes.Readiness]()},
+	"GET /api/status/{chain}":       {summary: "Health of the node, DB, mempool monitor and exchange rates of a chain. The status code is 503 if the chain is failing.", response: typeOf[*apitypes.ChainHealth]()},
+	"GET /api/supply":               {summary: "Current coin supply.", response: typeOf[*apitypes.CoinSupply]()},
+	"GET /api/supply/circulating":   {summary: "Circulating supply in atoms.", response: typeOf[float64](), query: []*openAPIParameter{queryParam("dcr", "boolean", "Return the supply in DCR.")}},
+	"GET /api/utxoset":              {summary: "Latest snapshot of the UTXO set, with its dust and its value and script type distributions. The status code is 404 before the first snapshot.", response: typeOf[*dbtypes.UtxoSetSnapshot]()},
+	"GET /api/block/avg-block-time": {summary: "Average block time in seconds.", response: typeOf[uint64]()},
+
+	"GET /api/block/best":                           blockSummaryDoc,
+	"GET /api/block/best/hash":                      blockHashDoc,
+	"GET /api/block/best/height":                    blockHeightDoc,
+	"GET /api/block/best/header":                    blockHeaderDoc,
+	"GET /api/block/best/header/raw":                blockHeaderRawDoc,
+	"GET /api/block/best/raw":                       blockRawDoc,
+	"GET /api/block/best/size":                      blockSizeDoc,
+	"GET /api/block/best/subsidy":                   blockSubsidyDoc,
+	"GET /api/block/best/verbose":                   blockVerboseDoc,
+	"GET /api/block/best/pos":                       blockPoSDoc,
+	"GET /api/block/best/tx":                        blockTxnsDoc,
+	"GET /api/block/best/tx/count":                  blockTxCountDoc,
+	"GET /api/block/hash/{blockhash}":               blockSummaryDoc,
+	"GET /api/block/hash/{blockhash}/height":        blockHeightDoc,
+	"GET /api/block/hash/{blockhash}/header":        blockHeaderDoc,
+	"GET /api/block/hash/{blockhash}/header/raw":    blockHeaderRawDoc,
+	"GET /api/block/hash/{blockhash}/raw":           blockRawDoc,
+	"GET /api/block/hash/{blockhash}/size":          blockSizeDoc,
+	"GET /api/block/hash/{blockhash}/subsidy":       blockSubsidyDoc,
+	"GET /api/block/hash/{blockhash}/verbose":       blockVerboseDoc,
+	"GET /api/block/hash/{blockhash}/pos":           blockPoSDoc,
+	"GET /api/block/hash/{blockhash}/tx":            blockTxnsDoc,
+	"GET /api/block/hash/{blockhash}/tx/count":      blockTxCountDoc,
+	"GET /api/block/{idx}":                          blockSummaryDoc,
+	"GET /api/block/{idx}/hash":                     blockHashDoc,
+	"GET /api/block/{idx}/header":                   blockHeaderDoc,
+	"GET /api/block/{idx}/header/raw":               blockHeaderRawDoc,
+	"GET /api/block/{idx}/raw":                      blockRawDoc,
+	"GET /api/block/{idx}/size":                     blockSizeDoc,
+	"GET /api/block/{idx}/subsidy":                  blockSubsidyDoc,
+	"GET /api/block/{idx}/verbose":                  blockVerboseDoc,
+	"GET /api/block/{idx}/pos":                      blockPoSDoc,
+	"GET /api/block/{idx}/tx":                       blockTxnsDoc,
+	"GET /api/block/{idx}/tx/count":                 blockTxCountDoc,
+	"GET /api/block/range/{idx0}/{idx}":             {summary: "Block summaries of a height range.", response: typeOf[[]*apitypes.BlockDataBasic]()},
+	"GET /api/block/range/{idx0}/{idx}/size":        {summary: "Block sizes of a height range.", response: typeOf[[]int32]()},
+	"GET /api/block/range/{idx0}/{idx}/{step}":      {summary: "Block summaries of a stepped height range.", response: typeOf[[]*apitypes.BlockDataBasic]()},
+	"GET /api/block/range/{idx0}/{idx}/{step}/size": {summary: "Block sizes of a stepped height range.", response: typeOf[[]int32]()},
+
+	"GET /api/stake/vote/info": {summary: "Vote version info.", response: typeOf[*chainjson.GetVoteInfoResult](),
+		query: []*openAPIParameter{queryParam("version", "integer", "Vote version. Defaults to the latest.")}},
+	"GET /api/stake/pool": {summary: "Ticket pool info at the best block.", response: typeOf[*apitypes.TicketPoolInfo]()},
+	"GET /api/stake/pool/full": {summary: "Ticket hashes in the live pool at the best block.", response: typeOf[[]string](),
+		query: []*openAPIParameter{queryParam("sort", "boolean", "Sort the ticket hashes.")}},
+	"GET /api/stake/pool/b/{idx}": {summary: "Ticket pool info at a block.", response: typeOf[*apitypes.TicketPoolInfo]()},
+	"GET /api/stake/pool/b/{idxorhash}/full": {summary: "Ticket hashes in the live pool at a block.", response: typeOf[[]string](),
+		query: []*openAPIParameter{queryParam("sort", "boolean", "Sort the ticket hashes.")}},
+	"GET /api/stake/pool/r/{idx0}/{idx}": {summary: "Ticket pool info of a height range.", response: typeOf[[]apitypes.TicketPoolInfo](),
+		query: []*openAPIParameter{queryParam("arrays", "boolean", "Return an object of arrays.")}},
+	"GET /api/stake/diff":                {summary: "Stake difficulty summary.", response: typeOf[*apitypes.StakeDiff]()},
+	"GET /api/stake/diff/current":        {summary: "Current and next stake difficulty.", response: typeOf[chainjson.GetStakeDifficultyResult]()},
+	"GET /api/stake/diff/estimates":      {summary: "Stake difficulty estimates.", response: typeOf[chainjson.EstimateStakeDiffResult]()},
+	"GET /api/stake/diff/b/{idx}":        {summary: "Stake difficulty of a block.", response: typeOf[[]float64]()},
+	"GET /api/stake/diff/r/{idx0}/{idx}": {summary: "Stake difficulty of a height range.", response: typeOf[[]float64]()},
+	"GET /api/stake/powerless":           {summary: "Missed and expired tickets.", response: typeOf[*apitypes.PowerlessTickets]()},
+
+	"GET /api/tx/{txid}": {summary: "Transaction.", response: typeOf[*apitypes.Tx](),
+		query: []*openAPIParameter{txSpendsQuery, currencyQuery}},
+	"GET /api/tx/{txid}/trimmed": {summary: "Trimmed transaction.", response: typeOf[*apitypes.TrimmedTx](),
+		query: []*openAPIParameter{txSpendsQuery}},
+	"GET /api/tx/{txid}/out":                {summary: "Transaction outputs.", response: typeOf[[]*apitypes.TxOut]()},
+	"GET /api/tx/{txid}/out/{txinoutindex}": {summary: "Transaction output.", response: typeOf[apitypes.TxOut]()},
+	"GET /api/tx/{txid}/in":                 {summary: "Transaction inputs.", response: typeOf[[]*apitypes.TxIn]()},
+	"GET /api/tx/{txid}/in/{txinoutindex}":  {summary: "Transaction input.", response: typeOf[apitypes.TxIn]()},
+	"GET /api/tx/{txid}/vinfo":              {summary: "Vote info of a vote transaction.", response: typeOf[*apitypes.VoteInfo]()},
+	"GET /api/tx/{txid}/tinfo":              {summary: "Ticket info of a ticket transaction.", response: typeOf[*apitypes.TicketInfo]()},
+	"GET /api/tx/hex/{txid}":                {summary: "Serialized transaction.", response: textResponse, contentType: "text/plain"},
+	"GET /api/tx/decoded/{txid}": {summary: "Trimmed transaction.", response: typeOf[*apitypes.TrimmedTx](),
+		query: []*openAPIParameter{txSpendsQuery}},
+	"GET /api/tx/hex/{chaintype}/{txid}":     {summary: "Serialized transaction of another chain.", response: textResponse, contentType: "text/plain"},
+	"GET /api/tx/decoded/{chaintype}/{txid}": {summary: "Verbose transaction of another chain.", response: anyResponse},
+	"GET /api/tx/swaps/{txid}":               {summary: "Atomic swap contracts in the transaction.", response: typeOf[*txhelpers.TxAtomicSwaps]()},
+	"GET /api/tx/swaps/{chaintype}/{txid}":   {summary: "Atomic swap contracts in a transaction of another chain.", response: typeOf[*txhelpers.TxAtomicSwaps]()},
+	"POST /api/txs": {summary: "Transactions.", response: typeOf[[]*apitypes.Tx](),
+		query: []*openAPIParameter{txSpendsQuery}},
+	"POST /api/txs/trimmed": {summary: "Trimmed transactions.", response: typeOf[[]*apitypes.TrimmedTx]()},
+
+	"GET /api/address/{address}":                            addressTxnsDoc,
+	"GET /api/address/{address}/count/{N}":                  addressTxnsDoc,
+	"GET /api/address/{address}/count/{N}/skip/{M}":         addressTxnsDoc,
+	"GET /api/address/{address}/raw":                        addressRawDoc,
+	"GET /api/address/{address}/count/{N}/raw":              addressRawDoc,
+	"GET /api/address/{address}/count/{N}/skip/{M}/raw":     addressRawDoc,
+	"GET /api/address/{address}/exists":                     {summary: "Whether each address has been used.", response: typeOf[[]bool]()},
+	"GET /api/address/{address}/totals":                     {summary: "Address totals.", response: typeOf[*apitypes.AddressTotals]()},
+	"GET /api/address/{address}/types/{chartgrouping}":      chartDataDoc,
+	"GET /api/address/{address}/amountflow/{chartgrouping}": chartDataDoc,
+	"GET /api/address/{address}/fiatflow/{chartgrouping}":   fiatFlowDoc,
+	"GET /api/address/addressesTxs/{addresses}":             {summary: "Raw transactions of each address.", response: typeOf[map[string][]*apitypes.AddressTxRaw]()},
+	"GET /api/chainaddress/{chaintype}/{address}":           {summary: "Address transactions on another chain.", response: typeOf[*apitypes.Address](), query: []*openAPIParameter{currencyQuery}},
+
+	"GET /api/chain/{chaintype}/block/best":                           chainBlockSummaryDoc,
+	"GET /api/chain/{chaintype}/block/best/hash":                      blockHashDoc,
+	"GET /api/chain/{chaintype}/block/best/height":                    blockHeightDoc,
+	"GET /api/chain/{chaintype}/block/best/raw":                       blockRawDoc,
+	"GET /api/chain/{chaintype}/block/best/size":                      chainBlockSizeDoc,
+	"GET /api/chain/{chaintype}/block/best/subsidy":                   chainBlockSubsidyDoc,
+	"GET /api/chain/{chaintype}/block/best/tx":                        chainBlockTxnsDoc,
+	"GET /api/chain/{chaintype}/block/best/tx/count":                  chainBlockTxCountDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}":               chainBlockSummaryDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/height":        blockHeightDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/raw":           blockRawDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/size":          chainBlockSizeDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/subsidy":       chainBlockSubsidyDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/tx":            chainBlockTxnsDoc,
+	"GET /api/chain/{chaintype}/block/hash/{blockhash}/tx/count":      chainBlockTxCountDoc,
+	"GET /api/chain/{chaintype}/block/{idx}":                          chainBlockSummaryDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/hash":                     blockHashDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/raw":                      blockRawDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/size":                     chainBlockSizeDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/subsidy":                  chainBlockSubsidyDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/tx":                       chainBlockTxnsDoc,
+	"GET /api/chain/{chaintype}/block/{idx}/tx/count":                 chainBlockTxCountDoc,
+	"GET /api/chain/{chaintype}/block/range/{idx0}/{idx}":             {summary: "Block summaries of a height range.", response: typeOf[[]*apitypes.ChainBlockSummary]()},
+	"GET /api/chain/{chaintype}/block/range/{idx0}/{idx}/size":        {summary: "Block sizes of a height range.", response: typeOf[[]int64]()},
+	"GET /api/chain/{chaintype}/tx/{txid}":                            {summary: "Transaction.", response: typeOf[*apitypes.ChainTx](), query: []*openAPIParameter{currencyQuery}},
+	"GET /api/chain/{chaintype}/tx/{txid}/hex":                        {summary: "Serialized transaction.", response: textResponse, contentType: "text/plain"},
+	"GET /api/chain/{chaintype}/tx/{txid}/decoded":                    {summary: "Verbose transaction as returned by the node.", response: anyResponse},
+	"GET /api/chain/{chaintype}/tx/{txid}/in":                         {summary: "Transaction inputs.", response: typeOf[[]apitypes.ChainTxIn]()},
+	"GET /api/chain/{chaintype}/tx/{txid}/in/{txinoutindex}":          {summary: "Transaction input.", response: typeOf[apitypes.ChainTxIn]()},
+	"GET /api/chain/{chaintype}/tx/{txid}/out":                        {summary: "Transaction outputs.", response: typeOf[[]apitypes.ChainTxOut]()},
+	"GET /api/chain/{chaintype}/tx/{txid}/out/{txinoutindex}":         {summary: "Transaction output.", response: typeOf[apitypes.ChainTxOut]()},
+	"GET /api/chain/{chaintype}/address/{address}":                    chainAddressTxnsDoc,
+	"GET /api/chain/{chaintype}/address/{address}/count/{N}":          chainAddressTxnsDoc,
+	"GET /api/chain/{chaintype}/address/{address}/count/{N}/skip/{M}": chainAddressTxnsDoc,
+	"GET /api/chain/{chaintype}/address/{address}/totals":             {summary: "Address totals.", response: typeOf[*apitypes.ChainAddressTotals]()},
+	"GET /api/chain/{chaintype}/mempool":                              {summary: "Transactions in the mempool.", response: typeOf[*apitypes.ChainMempool]()},
+	"GET /api/chain/{chaintype}/supply":                               {summary: "Current coin supply.", response: typeOf[*apitypes.ChainCoinSupply]()},
+	"GET /api/chain/{chaintype}/supply/circulating": {summary: "Circulating supply in atoms.", response: typeOf[float64](),
+		query: []*openAPIParameter{queryParam("coins", "boolean", "Return the supply in coins.")}},
+	"GET /api/chain/{chaintype}/address/{address}/fiatflow/{chartgrouping}": chainFiatFlowDoc,
+	"GET /api/chain/{chaintype}/retention":                                  {summary: "Range of blocks whose rows are kept in the DB.", response: typeOf[*dbtypes.RetentionWindow]()},
+	"GET /api/chain/{chaintype}/utxoset":                                    {summary: "Latest snapshot of the UTXO set, with its dust and its value and script type distributions. Not available for xmr. The status code is 404 before the first snapshot.", response: typeOf[*dbtypes.UtxoSetSnapshot]()},
+
+	"GET /api/atomic-swaps/amount/{chartgrouping}":  chartDataDoc,
+	"GET /api/atomic-swaps/txcount/{chartgrouping}": chartDataDoc,
+
+	"GET /api/treasury/balance":            {summary: "Treasury balance.", response: typeOf[*dbtypes.TreasuryBalance]()},
+	"GET /api/treasury/io/{chartgrouping}": chartDataDoc,
+	"GET /api/treasury/votechart/{txhash}": {summary: "Votes on a treasury spend.", response: typeOf[*apitypes.AgendaAPIResponse]()},
+
+	"GET /api/mixes/denoms": {summary: "All-time mix statistics by denomination.", response: typeOf[[]*dbtypes.MixDenomStats]()},
+	"GET /api/mixes/daily": {summary: "Daily mix statistics.", response: typeOf[[]*dbtypes.MixStats](),
+		query: []*openAPIParameter{
+			queryParam("from", "integer", "Start UNIX time."),
+			queryParam("to", "integer", "End UNIX time."),
+		}},
+	"GET /api/mixes/block/{idx}":            {summary: "Mix statistics of a block.", response: typeOf[*dbtypes.MixStats]()},
+	"GET /api/mixes/range/{idx0}/{idx}":     mixStatsDoc,
+	"GET /api/mixes/txs":                    mixTxnsDoc,
+	"GET /api/mixes/txs/count/{N}":          mixTxnsDoc,
+	"GET /api/mixes/txs/count/{N}/skip/{M}": mixTxnsDoc,
+
+	"GET /api/agendas":           {summary: "All consensus agendas.", response: typeOf[[]apitypes.AgendasInfo]()},
+	"GET /api/agenda/{agendaId}": {summary: "Votes on a consensus agenda.", response: typeOf[*apitypes.AgendaAPIResponse]()},
+
+	"GET /api/mempool":                  {summary: "Not implemented.", deprecated: true},
+	"GET /api/mempool/sstx":             {summary: "Mempool ticket fee summary.", response: typeOf[*apitypes.MempoolTicketFeeInfo]()},
+	"GET /api/mempool/sstx/fees":        {summary: "Mempool ticket fee rates.", response: typeOf[*apitypes.MempoolTicketFees]()},
+	"GET /api/mempool/sstx/fees/{N}":    {summary: "Highest N mempool ticket fee rates.", response: typeOf[*apitypes.MempoolTicketFees]()},
+	"GET /api/mempool/sstx/details":     {summary: "Mempool ticket details.", response: typeOf[*apitypes.MempoolTicketDetails]()},
+	"GET /api/mempool/sstx/details/{N}": {summary: "Details of the N mempool tickets with the highest fees.", response: typeOf[*apitypes.MempoolTicketDetails]()},
+
+	"GET /api/chart/market/{token}/candlestick/{bin}": rawChartDoc,
+	"GET /api/chart/market/{token}/depth":             rawChartDoc,
+	"GET /api/chart/market/{token}/liquidity":         liquidityChartDoc,
+	"GET /api/chart/submarket/{token}/depth":          rawChartDoc,
+	"GET /api/chart/{charttype}": {summary: "Chart data.", response: anyResponse,
+		query: []*openAPIParameter{
+			queryParam("axis", "string", "X axis, time or height."),
+			queryParam("bin", "string", "Bin size, e.g. day or block."),
+			queryParam("zoom", "string", "Zoom range."),
+			queryParam("range", "string", "Range."),
+		}},
+	"GET /api/chainchart/{chaintype}/market/{token}/candlestick/{bin}": rawChartDoc,
+	"GET /api/chainchart/{chaintype}/market/{token}/depth":             rawChartDoc,
+	"GET /api/chainchart/{chaintype}/market/{token}/liquidity":         liquidityChartDoc,
+	"GET /api/chainchart/{chaintype}/submarket/{token}/depth":          rawChartDoc,
+	"GET /api/chainchart/exchanges":                                    {summary: "Exchanges of each chain.", response: typeOf[chainExchangesResponse]()},
+	"GET /api/chainchart/{chaintype}/{charttype}": {summary: "Chart data of another chain.", response: anyResponse,
+		query: []*openAPIParameter{
+			queryParam("axis", "string", "X axis, time or height."),
+			queryParam("bin", "string", "Bin size, e.g. day or block."),
+			queryParam("zoom", "string", "Zoom range."),
+		}},
+
+	"GET /api/stakingcalc/getBlocksReward": {summary: "Block rewards by height.", response: typeOf[blocksRewardResponse](),
+		query: []*openAPIParameter{queryParam("list", "string", "Comma-separated list of block heights.")}},
+
+	"GET /api/finance-report/proposal": {summary: "Proposal spending report.", response: anyResponse,
+		query: []*openAPIParameter{queryParam("search", "string", "Search text.")}},
+	"GET /api/finance-report/treasury": {summary: "Treasury spending report.", response: anyResponse},
+	"GET /api/finance-report/detail": {summary: "Detailed spending report.", response: anyResponse,
+		query: []*openAPIParameter{
+			queryParam("type", "string", "Report type."),
+			queryParam("time", "string", "Report period."),
+			queryParam("token", "string", "Proposal token."),
+			queryParam("name", "string", "Author or domain name."),
+		}},
+	"GET /api/finance-report/time-range": {summary: "Time range of the spending reports.", response: anyResponse},
+
+	"GET /api/ticketpool":             {summary: "Ticket pool by purchase date.", response: typeOf[ticketPoolByDateResponse]()},
+	"GET /api/ticketpool/bydate/{tp}": {summary: "Ticket pool by purchase date.", response: typeOf[ticketPoolByDateResponse]()},
+	"GET /api/ticketpool/charts":      {summary: "Ticket pool charts.", response: typeOf[*apitypes.TicketPoolChartsData]()},
+	"GET /api/proposal/{token}":       {summary: "Vote chart data of a proposal.", response: typeOf[*pitypes.ProposalChartData]()},
+	"GET /api/exchangerate":           {summary: "Exchange rates.", response: typeOf[*exchanges.ExchangeRates](), query: []*openAPIParameter{codeQuery}},
+	"GET /api/exchanges":              {summary: "State of the exchange data.", response: typeOf[*exchanges.ExchangeBotState](), query: []*openAPIParameter{codeQuery}},
+	"GET /api/exchanges/codes":        {summary: "Available fiat currency codes.", response: typeOf[[]string]()},
+	"GET /api/broadcast": {summary: "Broadcast a transaction. Returns the transaction hash.", response: textResponse,
+		query: []*openAPIParameter{queryParam("hex", "string", "Serialized transaction.")}},
+
+	"GET /api/stream": {summary: "Server-Sent Events stream of the pubsub events. Each event has the name and the data of the websocket message. Send the Last-Event-ID header to resume a stream.",
+		response: textResponse, contentType: "text/event-stream",
+		query: []*openAPIParameter{queryParam("events", "string", "Comma-separated subscriptions, e.g. newblock,mempool,address:Dsxyz,btcnewtxs.")}},
+
+	"GET /api/graphql": {summary: "GraphQL query of the DCR, BTC, LTC and XMR blocks, transactions and addresses, and the DCR tickets, votes, treasury and proposals. Send Accept: text/event-stream to stream a subscription.",
+		response: anyResponse,
+		query: []*openAPIParameter{
+			queryParam("query", "string", "GraphQL query document."),
+			queryParam("operationName", "string", "Operation of the document to run."),
+			queryParam("variables", "string", "JSON object of the variables."),
+		}},
+	"POST /api/graphql": {summary: "GraphQL query. The JSON body has the query, operationName and variables. Send Accept: text/event-stream to stream a subscription.",
+		response: anyResponse},
+
+	"GET /api/webhooks": {summary: "Webhook subscriptions of the API key of the request.",
+		response: typeOf[[]*dbtypes.WebhookSubscription]()},
+	"POST /api/webhooks": {summary: "Subscribe a callback URL to an event. The JSON body has the url, event, chain, target and confirmations. The response includes the secret of the delivery signatures.",
+		response: typeOf[*dbtypes.WebhookSubscription]()},
+	"GET /api/webhooks/deadletters": {summary: "Failed webhook deliveries of the API key of the request.",
+		response: typeOf[[]*dbtypes.WebhookDeadLetter](),
+		query:    []*openAPIParameter{queryParam("limit", "integer", "Maximum number of dead letters.")}},
+
+	// Insight API
+	"GET /insight/api/":                         {summary: "Redirects to the status endpoint."},
+	"GET /insight/api/blocks":                   {summary: "Block summaries of a day.", response: typeOf[apitypes.InsightBlocksSummaryResult]()},
+	"GET /insight/api/block/{idxorhash}":        insightBlockDoc,
+	"GET /insight/api/block-index/{idxorhash}":  {summary: "Block hash.", response: typeOf[insightBlockHashResponse]()},
+	"GET /insight/api/rawblock/{idxorhash}":     {summary: "Serialized block.", response: typeOf[insightRawBlockResponse]()},
+	"POST /insight/api/tx/send":                 {summary: "Broadcast a transaction.", response: typeOf[insightTxIDResponse]()},
+	"GET /insight/api/tx/{txid}":                {summary: "Transaction.", response: typeOf[apitypes.InsightTx]()},
+	"GET /insight/api/rawtx/{txid}":             {summary: "Serialized transaction.", response: typeOf[*apitypes.InsightRawTx]()},
+	"GET /insight/api/txs":                      {summary: "Transactions of a block or an address.", response: typeOf[apitypes.InsightBlockAddrTxSummary]()},
+	"GET /insight/api/status":                   {summary: "Node status. The response depends on q.", response: anyResponse},
+	"GET /insight/api/sync":                     {summary: "Sync status.", response: typeOf[apitypes.SyncResponse]()},
+	"GET /insight/api/utils/estimatefee":        {summary: "Fee estimate by number of blocks.", response: typeOf[map[string]float64]()},
+	"GET /insight/api/peer":                     {summary: "Node peer status.", response: typeOf[insightPeerResponse]()},
+	"GET /insight/api/addrs/{address}/txs":      insightTxnsDoc,
+	"GET /insight/api/addrs/{address}/utxo":     insightUTXODoc,
+	"POST /insight/api/addrs/txs":               insightTxnsDoc,
+	"POST /insight/api/addrs/utxo":              insightUTXODoc,
+	"GET /insight/api/addr/{address}":           {summary: "Address info.", response: typeOf[apitypes.InsightAddressInfo]()},
+	"GET /insight/api/addr/{address}/utxo":      insightUTXODoc,
+	"GET /insight/api/addr/{address}/{command}": {summary: "Address balance or total in atoms.", response: typeOf[int64]()},
+}
+
+// chainInsightTypes are the chains with an Insight API under
+// /insight/{chaintype}/api and an Esplora API under /esplora/{chaintype}/api.
+var chainInsightTypes = []string{"btc", "ltc"}
+
+var (
+	esploraTxDoc      = routeDoc{summary: "Transaction.", response: typeOf[*apitypes.EsploraTx]()}
+	esploraTxsDoc     = routeDoc{summary: "Transactions, newest first.", response: typeOf[[]*apitypes.EsploraTx]()}
+	esploraHexDoc     = routeDoc{summary: "Hex encoded data.", response: textResponse, contentType: "text/plain"}
+	esploraBinaryDoc  = routeDoc{summary: "Binary data.", response: textResponse, contentType: "application/octet-stream"}
+	esploraHashDoc    = routeDoc{summary: "Hash.", response: textResponse, contentType: "text/plain"}
+	esploraAddressDoc = routeDoc{summary: "Confirmed and mempool output statistics.", response: typeOf[*apitypes.EsploraAddress]()}
+	esploraUTXODoc    = routeDoc{summary: "Unspent outputs, including unconfirmed ones.", response: typeOf[[]apitypes.EsploraUTXO]()}
+	esploraBlocksDoc  = routeDoc{summary: "The 10 blocks at and below the start height, which defaults to the tip.",
+		response: typeOf[[]*apitypes.EsploraBlock]()}
+	esploraBlockTxsDoc = routeDoc{summary: "A page of 25 transactions of the block.", response: typeOf[[]*apitypes.EsploraTx]()}
+)
+
+// esploraRouteDocs documents the routes of the Esplora API of each chain in
+// chainInsightTypes, keyed by method and path relative to the API root.
+var esploraRouteDocs = map[string]routeDoc{
+	"POST /tx":                            {summary: "Broadcast a hex encoded transaction sent as the request body.", response: textResponse, contentType: "text/plain"},
+	"GET /tx/{txid}":                      esploraTxDoc,
+	"GET /tx/{txid}/status":               {summary: "Transaction confirmation status.", response: typeOf[*apitypes.EsploraTxStatus]()},
+	"GET /tx/{txid}/hex":                  esploraHexDoc,
+	"GET /tx/{txid}/raw":                  esploraBinaryDoc,
+	"GET /tx/{txid}/outspends":            {summary: "Spending status of all outputs.", response: typeOf[[]apitypes.EsploraOutspend]()},
+	"GET /tx/{txid}/outspend/{vout}":      {summary: "Spending status of an output.", response: typeOf[apitypes.EsploraOutspend]()},
+	"GET /block/{hash}":                   {summary: "Block.", response: typeOf[*apitypes.EsploraBlock]()},
+	"GET /block/{hash}/status":            {summary: "Block main chain status.", response: typeOf[*apitypes.EsploraBlockStatus]()},
+	"GET /block/{hash}/txids":             {summary: "Transaction hashes of the block.", response: typeOf[[]string]()},
+	"GET /block/{hash}/txid/{index}":      esploraHashDoc,
+	"GET /block/{hash}/txs":               esploraBlockTxsDoc,
+	"GET /block/{hash}/txs/{start_index}": esploraBlockTxsDoc,
+	"GET /block/{hash}/header":            esploraHexDoc,
+	"GET /block/{hash}/raw":               esploraBinaryDoc,
+	"GET /block-height/{height}":          esploraHashDoc,
+	"GET /blocks":                         esploraBlocksDoc,
+	"GET /blocks/{start_height}":          esploraBlocksDoc,
+	"GET /blocks/tip/height":              {summary: "Height of the best block.", response: textResponse, contentType: "text/plain"},
+	"GET /blocks/tip/hash":                esploraHashDoc,
+	"GET /mempool/txids":                  {summary: "Hashes of the mempool transactions.", response: typeOf[[]string]()},
+	"GET /fee-estimates":                  {summary: "Fee rates in sat/vB by confirmation target in blocks.", response: typeOf[map[string]float64]()},
+}
+
+func init() {
+	// The address and scripthash routes are the same.
+	addressDocs := map[string]routeDoc{
+		"":                            esploraAddressDoc,
+		"/txs":                        {summary: "Up to 50 mempool transactions and 25 confirmed transactions.", response: typeOf[[]*apitypes.EsploraTx]()},
+		"/txs/chain":                  esploraTxsDoc,
+		"/txs/chain/{last_seen_txid}": esploraTxsDoc,
+		"/txs/mempool":                {summary: "Up to 50 mempool transactions.", response: typeOf[[]*apitypes.EsploraTx]()},
+		"/utxo":                       esploraUTXODoc,
+	}
+	for path, doc := range addressDocs {
+		esploraRouteDocs["GET /address/{address}"+path] = doc
+		esploraRouteDocs["GET /scripthash/{hash}"+path] = doc
+	}
+	for key, doc := range esploraRouteDocs {
+		method, path, _ := strings.Cut(key, " ")
+		for _, chainType := range chainInsightTypes {
+			routeDocs[method+" /esplora/"+chainType+"/api"+path] = doc
+		}
+	}
+}
+
+func init() {
+	// The BTC and LTC Insight APIs have the routes of the Decred Insight API,
+	// except for the blocks by date endpoint.
+	chainDocs := make(map[string]routeDoc)
+	for key, doc := range routeDocs {
+		method, path, _ := strings.Cut(key, " ")
+		if !strings.HasPrefix(path, "/insight/api/") || path == "/insight/api/blocks" {
+			continue
+		}
+		for _, chainType := range chainInsightTypes {
+			chainPath := "/insight/" + chainType + path[len("/insight"):]
+			chainDocs[method+" "+chainPath] = doc
+		}
+	}
+	for key, doc := range chainDocs {
+		routeDocs[key] = doc
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	m "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/webhook"
+)
+
+// The handlers in this file manage the webhook subscriptions of the API key of
+// the request at /api/webhooks. Requests without an API key are rejected with
+// 401, and all requests with 404 if webhooks are disabled.
+
+const (
+	defaultDeadLetters = 100
+	maxDeadLetters     = 1000
+)
+
+// WebhookManager manages the webhook subscriptions of API keys.
+type WebhookManager interface {
+	Subscribe(sub *dbtypes.WebhookSubscription) error
+	Subscriptions(keyID int64) ([]*dbtypes.WebhookSubscription, error)
+	Unsubscribe(keyID, id int64) error
+	DeadLetters(keyID int64, limit int) ([]*dbtypes.WebhookDeadLetter, error)
+}
+
+// webhookKey returns the API key of a webhook request, or writes the error
+// response and returns nil.
+func (c *appContext) webhookKey(w http.ResponseWriter, r *http.Request) *dbtypes.APIKey {
+	if c.webhooks == nil {
+		http.Error(w, "webhooks are disabled", http.StatusNotFound)
+		return nil
+	}
+	key := m.RequestAPIKey(r)
+	if key == nil {
+		http.Error(w, "an API key is required", http.StatusUnauthorized)
+		return nil
+	}
+	return key
+}
+
+// postWebhookSubscription creates a webhook subscription from the JSON request
+// body. The response includes the secret of the signatures of the deliveries,
+// which is not shown again.
+func (c *appContext) postWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	key := c.webhookKey(w, r)
+	if key == nil {
+		return
+	}
+	var req dbtypes.WebhookSubscription
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON request body", http.StatusBadRequest)
+		return
+	}
+	sub := &dbtypes.WebhookSubscription{
+		KeyID:         key.ID,
+		URL:           req.URL,
+		Event:         req.Event,
+		Chain:         req.Chain,
+		Target:        req.Target,
+		Confirmations: req.Confirmations,
+	}
+	if err := c.webhooks.Subscribe(sub); err != nil {
+		if errors.Is(err, webhook.ErrInvalidSubscription) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		apiLog.Errorf("Unable to store the webhook subscription of API key %d: %v", key.ID, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeJSONWithStatus(w, sub, http.StatusCreated, m.GetIndentCtx(r))
+}
+
+func (c *appContext) getWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	key := c.webhookKey(w, r)
+	if key == nil {
+		return
+	}
+	subs, err := c.webhooks.Subscriptions(key.ID)
+	if err != nil {
+		apiLog.Errorf("Unable to retrieve the webhook subscriptions of API key %d: %v", key.ID, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if subs == nil {
+		subs = []*dbtypes.WebhookSubscription{}
+	}
+	writeJSON(w, subs, m.GetIndentCtx(r))
+}
+
+func (c *appContext) deleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	key := c.webhookKey(w, r)
+	if key == nil {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid subscription id", http.StatusBadRequest)
+		return
+	}
+	if err = c.webhooks.Unsubscribe(key.ID, id); err != nil {
+		if errors.Is(err, dbtypes.ErrNoResult) {
+			http.Error(w, "no such subscription", http.StatusNotFound)
+			return
+		}
+		apiLog.Errorf("Unable to delete webhook subscription %d: %v", id, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getWebhookDeadLetters lists the most recent failed deliveries, up to the
+// "limit" URL query parameter.
+func (c *appContext) getWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	key := c.webhookKey(w, r)
+	if key == nil {
+		return
+	}
+	limit := defaultDeadLetters
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxDeadLetters {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+	deadLetters, err := c.webhooks.DeadLetters(key.ID, limit)
+	if err != nil {
+		apiLog.Errorf("Unable to retrieve the webhook dead letters of API key %d: %v", key.ID, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if deadLetters == nil {
+		deadLetters = []*dbtypes.WebhookDeadLetter{}
+	}
+	writeJSON(w, deadLetters, m.GetIndentCtx(r))
+}
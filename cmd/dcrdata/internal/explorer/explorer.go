@@ -76,6 +76,10 @@ const (
 
 	MaxTreasuryRows int64 = 200
 
+	// MaxMixRows is an upper limit on the number of rows that may be shown on
+	// the mixes page table.
+	MaxMixRows int64 = 200
+
 	testnetNetName = "Testnet"
 )
 
@@ -99,10 +103,16 @@ type explorerDataSource interface {
 	TreasuryTxns(n, offset int64, txType stake.TxType) ([]*dbtypes.TreasuryTx, error)
 	TreasuryTxnsWithPeriod(n, offset int64, txType stake.TxType, year int64, month int64) ([]*dbtypes.TreasuryTx, error)
 	GetAtomicSwapList(n, offset int64, pair, status, searchKey string) ([]*dbtypes.AtomicSwapFullData, int64, error)
+	TreasuryTxnsByCursor(n int64, cursor *dbtypes.PageCursor, txType stake.TxType, year int64, month int64) ([]*dbtypes.TreasuryTx, string, error)
+	GetAtomicSwapListByCursor(n int64, cursor *dbtypes.PageCursor, pair, status, searchKey string) ([]*dbtypes.AtomicSwapFullData, int64, string, error)
 	CountRefundContract() (int64, error)
+	MixStatsByBlockRange(from, to int64) ([]*dbtypes.MixStats, error)
+	MixDenomSummary() ([]*dbtypes.MixDenomStats, error)
+	MixTxns(n, offset, denom int64) ([]*dbtypes.MixTx, int64, error)
 	AddressHistory(address string, N, offset int64, txnType dbtypes.AddrTxnViewType, year int64, month int64) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, error)
 	MutilchainAddressHistory(address string, N, offset int64, txnType dbtypes.AddrTxnViewType, chainType string) ([]*dbtypes.MutilchainAddressRow, *dbtypes.AddressBalance, error)
 	AddressData(address string, N, offset int64, txnType dbtypes.AddrTxnViewType, year int64, month int64) (*dbtypes.AddressInfo, error)
+	AddressDataByCursor(address string, N int64, cursor *dbtypes.PageCursor, txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, error)
 	MutilchainAddressData(address string, N, offset int64, txnType dbtypes.AddrTxnViewType, chainType string) (*dbtypes.AddressInfo, error)
 	DevBalance() (*dbtypes.AddressBalance, error)
 	FillAddressTransactions(addrInfo *dbtypes.AddressInfo) error
@@ -172,8 +182,9 @@ type explorerDataSource interface {
 	MutilchainBestBlockTime(chainType string) int64
 	GetDecredBlockchainSize() int64
 	GetDecredTotalTransactions() int64
-	SyncLast20LTCBlocks(nodeHeight int32) error
-	SyncLast20BTCBlocks(nodeHeight int32) error
+	SyncRecentLTCBlocks(nodeHeight int32) error
+	SyncRecentBTCBlocks(nodeHeight int32) error
+	MultichainRetentionWindow(chainType string) *dbtypes.RetentionWindow
 	GetDBBlockDetailInfo(chainType string, height int64) *dbtypes.MutilchainDBBlockInfo
 	SyncAndGet24hMetricsInfo(bestBlockHeight int64, chainType string) (*dbtypes.Block24hInfo, error)
 	SyncAddressSummary() error
@@ -206,6 +217,7 @@ type explorerDataSource interface {
 	GetXMRTotalOutputs() int64
 	GetXMRBasicBlock(height int64) *types.BlockBasic
 	GetXMRExplorerBlocks(from, to int64) []*types.BlockBasic
+	FiatPrices(chainType, currency string, from, to int64) (*dbtypes.FiatPrices, error)
 }
 
 type PoliteiaBackend interface {
@@ -335,6 +347,7 @@ type XmrPageData struct {
 	BlockchainInfo *xmrutil.BlockchainInfo
 	HomeInfo       *types.HomeInfo
 	MempoolData    *xmrutil.Mempool
+	MempoolTime    time.Time // time of the last MempoolData update
 	sync24hMtx     sync.Mutex
 }
 
@@ -560,7 +573,7 @@ func New(cfg *ExplorerConfig) *ExplorerUI {
 		"chain_address", "chain_mempool", "chain_charts", "chain_market",
 		"chain_addresstable", "supply", "marketlist", "chain_parameters",
 		"whatsnew", "chain_visualblocks", "bwdash", "atomicswaps", "atomicswaps_table",
-		"about"}
+		"about", "mixes"}
 
 	for _, name := range tmpls {
 		if err := exp.templates.addTemplate(name); err != nil {
@@ -1074,7 +1087,7 @@ func (exp *ExplorerUI) BTCStore(blockData *blockdatabtc.BlockData, msgBlock *btc
 	//TODO: Open later
 	//totalVoutsCount := exp.dataSource.MutilchainGetTotalVoutsCount(mutilchain.TYPEBTC)
 	//totalAddressesCount := exp.dataSource.MutilchainGetTotalAddressesCount(mutilchain.TYPEBTC)
-	// err = exp.dataSource.SyncLast20BTCBlocks(blockData.Header.Height)
+	// err = exp.dataSource.SyncRecentBTCBlocks(blockData.Header.Height)
 	// if err != nil {
 	// 	log.Error(err)
 	// } else {
@@ -1306,7 +1319,7 @@ func (exp *ExplorerUI) LTCStore(blockData *blockdataltc.BlockData, msgBlock *ltc
 	//TODO: Open later
 	//totalVoutsCount := exp.dataSource.MutilchainGetTotalVoutsCount(mutilchain.TYPELTC)
 	//totalAddressesCount := exp.dataSource.MutilchainGetTotalAddressesCount(mutilchain.TYPELTC)
-	err = exp.dataSource.SyncLast20LTCBlocks(blockData.Header.Height)
+	err = exp.dataSource.SyncRecentLTCBlocks(blockData.Header.Height)
 	if err != nil {
 		log.Error(err)
 	}
@@ -1394,6 +1407,14 @@ func (exp *ExplorerUI) LTCStore(blockData *blockdataltc.BlockData, msgBlock *ltc
 	return nil
 }
 
+// XMRMempoolUpdateTime returns the time of the last update of the XMR mempool
+// data by UpdateXMRMempoolData, or the zero time if there was none.
+func (exp *ExplorerUI) XMRMempoolUpdateTime() time.Time {
+	exp.XmrPageData.RLock()
+	defer exp.XmrPageData.RUnlock()
+	return exp.XmrPageData.MempoolTime
+}
+
 // Generate xmr updates for mempool data
 func (exp *ExplorerUI) UpdateXMRMempoolData(xmrClient *xmrclient.XMRClient, stop <-chan struct{}) error {
 	xmrMempoolUpdateInterval := 15 * time.Second
@@ -1462,6 +1483,7 @@ func (exp *ExplorerUI) UpdateXMRMempoolData(xmrClient *xmrclient.XMRClient, stop
 			// set to explorer
 			exp.XmrPageData.Lock()
 			exp.XmrPageData.MempoolData = &mp
+			exp.XmrPageData.MempoolTime = time.Now()
 			exp.XmrPageData.Unlock()
 
 			// send to websocket
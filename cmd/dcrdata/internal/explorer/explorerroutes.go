@@ -1619,8 +1619,10 @@ func (exp *ExplorerUI) MutilchainTxPage(w http.ResponseWriter, r *http.Request)
 		TargetToken     string
 		IsRefund        bool
 		Conversions     struct {
-			Total *exchanges.Conversion
-			Fees  *exchanges.Conversion
+			Total      *exchanges.Conversion
+			Fees       *exchanges.Conversion
+			BlockTotal *exchanges.Conversion
+			BlockFees  *exchanges.Conversion
 		}
 	}{
 		CommonPageData:  exp.commonData(r),
@@ -1635,6 +1637,13 @@ func (exp *ExplorerUI) MutilchainTxPage(w http.ResponseWriter, r *http.Request)
 		pageData.Conversions.Total = exp.xcBot.MutilchainConversion(tx.Total, chainType)
 		pageData.Conversions.Fees = exp.xcBot.MutilchainConversion(tx.FeeCoin, chainType)
 	}
+	// And at the price of the day of its block.
+	if tx.Confirmations > 0 {
+		blockTime := tx.Time.UNIX()
+		prices := exp.fiatPrices(r, chainType, blockTime, blockTime)
+		pageData.Conversions.BlockTotal = blockTimeConversion(prices, blockTime, tx.Total)
+		pageData.Conversions.BlockFees = blockTimeConversion(prices, blockTime, tx.FeeCoin)
+	}
 
 	str, err := exp.templates.exec("chain_tx", pageData)
 	if err != nil {
@@ -1740,10 +1749,12 @@ func (exp *ExplorerUI) MutilchainBlockDetail(w http.ResponseWriter, r *http.Requ
 		LastStart int64
 		Txs       []*types.TrimmedTxInfo
 		XmrTxs    []*types.XmrTxFull
+		Retention *dbtypes.RetentionWindow
 	}{
 		CommonPageData: exp.commonData(r),
 		Data:           data,
 		ChainType:      chainType,
+		Retention:      exp.dataSource.MultichainRetentionWindow(chainType),
 		Txs:            txRows,
 		XmrTxs:         xmrRows,
 		Rows:           int(limitN),
@@ -2431,6 +2442,22 @@ func (exp *ExplorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		tx.Time = exp.mempoolTime(tx.TxID)
 	}
 
+	// For a mix, get the mix statistics of its denomination in the block.
+	var mixBlockStats *dbtypes.MixDenomStats
+	if tx.MixCount > 0 && isConfirmedMainchain {
+		stats, err := exp.dataSource.MixStatsByBlockRange(tx.BlockHeight, tx.BlockHeight)
+		if err != nil {
+			log.Warnf("Unable to get mix stats for block %d: %v", tx.BlockHeight, err)
+		} else if len(stats) > 0 {
+			for _, ds := range stats[0].Denoms {
+				if ds.Denom == tx.MixDenom {
+					mixBlockStats = ds
+					break
+				}
+			}
+		}
+	}
+
 	pageData := struct {
 		*CommonPageData
 		Data                 *types.TxInfo
@@ -2443,9 +2470,13 @@ func (exp *ExplorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		SwapFirstSource      *dbtypes.AtomicSwapForTokenData
 		TargetToken          string
 		IsRefund             bool
+		MixVolume            int64
+		MixBlockStats        *dbtypes.MixDenomStats
 		Conversions          struct {
-			Total *exchanges.Conversion
-			Fees  *exchanges.Conversion
+			Total      *exchanges.Conversion
+			Fees       *exchanges.Conversion
+			BlockTotal *exchanges.Conversion
+			BlockFees  *exchanges.Conversion
 		}
 	}{
 		CommonPageData:       exp.commonData(r),
@@ -2459,6 +2490,8 @@ func (exp *ExplorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		SwapsFound:           swapsInfo.Found,
 		TargetToken:          targetToken,
 		IsRefund:             isRefund,
+		MixVolume:            int64(tx.MixCount) * tx.MixDenom,
+		MixBlockStats:        mixBlockStats,
 	}
 
 	// Get a fiat-converted value for the total and the fees.
@@ -2466,6 +2499,13 @@ func (exp *ExplorerUI) TxPage(w http.ResponseWriter, r *http.Request) {
 		pageData.Conversions.Total = exp.xcBot.Conversion(tx.Total)
 		pageData.Conversions.Fees = exp.xcBot.Conversion(tx.Fee.ToCoin())
 	}
+	// And at the price of the day of its block.
+	if tx.Confirmations > 0 {
+		blockTime := tx.Time.UNIX()
+		prices := exp.fiatPrices(r, mutilchain.TYPEDCR, blockTime, blockTime)
+		pageData.Conversions.BlockTotal = blockTimeConversion(prices, blockTime, tx.Total)
+		pageData.Conversions.BlockFees = blockTimeConversion(prices, blockTime, tx.Fee.ToCoin())
+	}
 
 	str, err := exp.templates.exec("tx", pageData)
 	if err != nil {
@@ -2576,6 +2616,100 @@ func (exp *ExplorerUI) AtomicSwapsPage(w http.ResponseWriter, r *http.Request) {
 	io.WriteString(w, str)
 }
 
+// MixesPage is the page handler for the "/decred/mixes" path. It lists the
+// CoinShuffle++ mix transactions, optionally of a single denomination, along
+// with the all-time statistics of each mix denomination.
+func (exp *ExplorerUI) MixesPage(w http.ResponseWriter, r *http.Request) {
+	limitN := defaultAddressRows
+	if nParam := r.URL.Query().Get("n"); nParam != "" {
+		val, err := strconv.ParseUint(nParam, 10, 64)
+		if err != nil {
+			exp.StatusPage(w, defaultErrorCode, "invalid n value", "", ExpStatusError)
+			return
+		}
+		if int64(val) > MaxMixRows {
+			log.Warnf("MixesPage: requested up to %d mix rows, "+
+				"limiting to %d", val, MaxMixRows)
+			limitN = MaxMixRows
+		} else if val > 0 {
+			limitN = int64(val)
+		}
+	}
+
+	var offset int64
+	if startParam := r.URL.Query().Get("start"); startParam != "" {
+		val, err := strconv.ParseUint(startParam, 10, 64)
+		if err != nil {
+			exp.StatusPage(w, defaultErrorCode, "invalid start value", "", ExpStatusError)
+			return
+		}
+		offset = int64(val)
+	}
+
+	var denom int64
+	if denomParam := r.URL.Query().Get("denom"); denomParam != "" {
+		val, err := strconv.ParseUint(denomParam, 10, 64)
+		if err != nil {
+			exp.StatusPage(w, defaultErrorCode, "invalid denom value", "", ExpStatusError)
+			return
+		}
+		denom = int64(val)
+	}
+
+	denoms, err := exp.dataSource.MixDenomSummary()
+	if exp.timeoutErrorPage(w, err, "MixDenomSummary") {
+		return
+	} else if err != nil {
+		exp.StatusPage(w, defaultErrorCode, err.Error(), "", ExpStatusError)
+		return
+	}
+	txns, count, err := exp.dataSource.MixTxns(limitN, offset, denom)
+	if exp.timeoutErrorPage(w, err, "MixTxns") {
+		return
+	} else if err != nil {
+		exp.StatusPage(w, defaultErrorCode, err.Error(), "", ExpStatusError)
+		return
+	}
+
+	totals := new(dbtypes.MixStats)
+	for _, ds := range denoms {
+		totals.AddDenom(ds)
+	}
+
+	linkTemplate := fmt.Sprintf("/decred/mixes?start=%%d&n=%d", limitN)
+	if denom > 0 {
+		linkTemplate = fmt.Sprintf("%s&denom=%d", linkTemplate, denom)
+	}
+	str, err := exp.templates.exec("mixes", struct {
+		*CommonPageData
+		Totals *dbtypes.MixStats
+		Txns   []*dbtypes.MixTx
+		Count  int64
+		Denom  int64
+		Limit  int64
+		Offset int64
+		Pages  []pageNumber
+	}{
+		CommonPageData: exp.commonData(r),
+		Totals:         totals,
+		Txns:           txns,
+		Count:          count,
+		Denom:          denom,
+		Limit:          limitN,
+		Offset:         offset,
+		Pages:          calcPages(int(count), int(limitN), int(offset), linkTemplate),
+	})
+	if err != nil {
+		log.Errorf("Template execute failure: %v", err)
+		exp.StatusPage(w, defaultErrorCode, defaultErrorMessage, "", ExpStatusError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Turbolinks-Location", r.URL.RequestURI())
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, str)
+}
+
 // TreasuryPage is the page handler for the "/treasury" path
 func (exp *ExplorerUI) TreasuryPage(w http.ResponseWriter, r *http.Request) {
 	ctx := context.WithValue(r.Context(), ctxAddress, exp.pageData.HomeInfo.DevAddress)
@@ -2793,6 +2927,9 @@ func (exp *ExplorerUI) AddressPage(w http.ResponseWriter, r *http.Request) {
 
 	// Set page parameters.
 	addrData.Path = r.URL.Path
+	if !isZeroAddress {
+		exp.setAddressFiatValues(r, mutilchain.TYPEDCR, addrData)
+	}
 	// If exchange monitoring is active, prepare a fiat balance conversion
 	conversion := exp.xcBot.Conversion(dcrutil.Amount(addrData.Balance.TotalUnspent).ToCoin())
 
@@ -2845,6 +2982,7 @@ func (exp *ExplorerUI) MutilchainAddressPage(w http.ResponseWriter, r *http.Requ
 		Pages     []pageNumber
 		ChainType string
 		Maintain  bool
+		Retention *dbtypes.RetentionWindow
 	}
 
 	chainType := chi.URLParam(r, "chaintype")
@@ -2883,6 +3021,7 @@ func (exp *ExplorerUI) MutilchainAddressPage(w http.ResponseWriter, r *http.Requ
 
 	// Set page parameters.
 	addrData.Path = r.URL.Path
+	exp.setAddressFiatValues(r, chainType, addrData)
 
 	if limitN == 0 {
 		limitN = 20
@@ -2901,6 +3040,7 @@ func (exp *ExplorerUI) MutilchainAddressPage(w http.ResponseWriter, r *http.Requ
 		ChainType:      chainType,
 		Pages:          calcPages(int(addrData.TxnCount), int(limitN), int(offsetAddrOuts), linkTemplate),
 		Maintain:       true,
+		Retention:      exp.dataSource.MultichainRetentionWindow(chainType),
 	}
 	str, err := exp.templates.exec("chain_address", pageData)
 	if err != nil {
@@ -2957,6 +3097,7 @@ func (exp *ExplorerUI) MutilchainAddressTable(w http.ResponseWriter, r *http.Req
 		Pages:    calcPages(int(addrData.TxnCount), int(limitN), int(offsetAddrOuts), linkTemplate),
 	}
 	addrData.ChainType = chainType
+	exp.setAddressFiatValues(r, chainType, addrData)
 	response.HTML, err = exp.templates.exec("chain_addresstable", struct {
 		Data *dbtypes.AddressInfo
 	}{
@@ -2994,7 +3135,22 @@ func (exp *ExplorerUI) AddressTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	addrData, err := exp.AddressListData(address, txnType, limitN, offsetAddrOuts, time)
+	// The cursor URL query parameter selects cursor pagination, which
+	// replaces the start offset. It is not available for the merged and
+	// unspent views, or with the time filter.
+	var addrData *dbtypes.AddressInfo
+	if query := r.URL.Query(); query.Has("cursor") {
+		var cursor *dbtypes.PageCursor
+		cursor, err = dbtypes.ParsePageCursor(query.Get("cursor"))
+		if err != nil || time != "" || (txnType != dbtypes.AddrTxnAll &&
+			txnType != dbtypes.AddrTxnCredit && txnType != dbtypes.AddrTxnDebit) {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		addrData, err = exp.AddressCursorListData(address, txnType, limitN, cursor)
+	} else {
+		addrData, err = exp.AddressListData(address, txnType, limitN, offsetAddrOuts, time)
+	}
 	if err != nil {
 		log.Errorf("AddressListData error: %v", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError),
@@ -3015,6 +3171,7 @@ func (exp *ExplorerUI) AddressTable(w http.ResponseWriter, r *http.Request) {
 		Pages:    calcPages(int(addrData.TxnCount), int(limitN), int(offsetAddrOuts), linkTemplate),
 	}
 
+	exp.setAddressFiatValues(r, mutilchain.TYPEDCR, addrData)
 	response.HTML, err = exp.templates.exec("addresstable", struct {
 		Data *dbtypes.AddressInfo
 	}{
@@ -3075,7 +3232,23 @@ func (exp *ExplorerUI) AtomicSwapsTable(w http.ResponseWriter, r *http.Request)
 	status := strings.TrimSpace(r.URL.Query().Get("status"))
 	searchKey := strings.TrimSpace(r.URL.Query().Get("search"))
 	listMode := strings.TrimSpace(r.URL.Query().Get("mode"))
-	atomicSwapTxs, allFilterCount, err := exp.dataSource.GetAtomicSwapList(limitN, offset, pair, status, searchKey)
+	// The cursor URL query parameter selects cursor pagination, which
+	// replaces the start offset.
+	var atomicSwapTxs []*dbtypes.AtomicSwapFullData
+	var allFilterCount int64
+	var nextCursor string
+	var err error
+	if query := r.URL.Query(); query.Has("cursor") {
+		var cursor *dbtypes.PageCursor
+		cursor, err = dbtypes.ParsePageCursor(query.Get("cursor"))
+		if err != nil {
+			exp.StatusPage(w, defaultErrorCode, "invalid cursor value", "", ExpStatusError)
+			return
+		}
+		atomicSwapTxs, allFilterCount, nextCursor, err = exp.dataSource.GetAtomicSwapListByCursor(limitN, cursor, pair, status, searchKey)
+	} else {
+		atomicSwapTxs, allFilterCount, err = exp.dataSource.GetAtomicSwapList(limitN, offset, pair, status, searchKey)
+	}
 	if exp.timeoutErrorPage(w, err, "AtomicSwaps") {
 		return
 	} else if err != nil {
@@ -3102,11 +3275,13 @@ func (exp *ExplorerUI) AtomicSwapsTable(w http.ResponseWriter, r *http.Request)
 		CurrentCount int                           `json:"current_count"`
 		SwapsList    []*dbtypes.AtomicSwapFullData `json:"swaps_list"`
 		Pages        []pageNumber                  `json:"pages"`
+		NextCursor   string                        `json:"next_cursor,omitempty"`
 	}{
 		TxCount:      allFilterCount,
 		CurrentCount: len(atomicSwapTxs),
 		SwapsList:    atomicSwapTxs,
 		Pages:        calcPages(int(allFilterCount), int(limitN), int(offset), linkTemplate),
+		NextCursor:   nextCursor,
 	}
 
 	response.HTML, err = exp.templates.exec("atomicswaps_table", struct {
@@ -3164,7 +3339,21 @@ func (exp *ExplorerUI) TreasuryTable(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	txns, err := exp.dataSource.TreasuryTxnsWithPeriod(limitN, offset, txType, year, month)
+	// The cursor URL query parameter selects cursor pagination, which
+	// replaces the start offset.
+	var txns []*dbtypes.TreasuryTx
+	var nextCursor string
+	if query := r.URL.Query(); query.Has("cursor") {
+		var cursor *dbtypes.PageCursor
+		cursor, err = dbtypes.ParsePageCursor(query.Get("cursor"))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		txns, nextCursor, err = exp.dataSource.TreasuryTxnsByCursor(limitN, cursor, txType, year, month)
+	} else {
+		txns, err = exp.dataSource.TreasuryTxnsWithPeriod(limitN, offset, txType, year, month)
+	}
 	if exp.timeoutErrorPage(w, err, "TreasuryTxns") {
 		return
 	} else if err != nil {
@@ -3192,12 +3381,14 @@ func (exp *ExplorerUI) TreasuryTable(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := struct {
-		TxnCount int64        `json:"tx_count"`
-		HTML     string       `json:"html"`
-		Pages    []pageNumber `json:"pages"`
+		TxnCount   int64        `json:"tx_count"`
+		HTML       string       `json:"html"`
+		Pages      []pageNumber `json:"pages"`
+		NextCursor string       `json:"next_cursor,omitempty"`
 	}{
-		TxnCount: treasuryTypeCount(bal, txType),
-		Pages:    calcPages(int(treasuryTypeCount(bal, txType)), int(limitN), int(offset), linkTemplate),
+		TxnCount:   treasuryTypeCount(bal, txType),
+		Pages:      calcPages(int(treasuryTypeCount(bal, txType)), int(limitN), int(offset), linkTemplate),
+		NextCursor: nextCursor,
 	}
 
 	type txData struct {
@@ -3381,7 +3572,27 @@ func (exp *ExplorerUI) AddressListData(address string, txnType dbtypes.AddrTxnVi
 		err = fmt.Errorf(defaultErrorMessage)
 		return nil, err
 	}
-	// check swap tx type for transactions
+	exp.setAddressSwapTypes(addrData)
+	return
+}
+
+// AddressCursorListData is like AddressListData, but for the page of inputs and
+// outputs that follows the page cursor.
+func (exp *ExplorerUI) AddressCursorListData(address string, txnType dbtypes.AddrTxnViewType, limitN int64,
+	cursor *dbtypes.PageCursor) (*dbtypes.AddressInfo, error) {
+	addrData, err := exp.dataSource.AddressDataByCursor(address, limitN, cursor, txnType)
+	if dbtypes.IsTimeoutErr(err) {
+		return nil, err
+	} else if err != nil {
+		log.Errorf("AddressDataByCursor error encountered: %v", err)
+		return nil, fmt.Errorf(defaultErrorMessage)
+	}
+	exp.setAddressSwapTypes(addrData)
+	return addrData, nil
+}
+
+// setAddressSwapTypes sets the atomic swap type of the address transactions.
+func (exp *ExplorerUI) setAddressSwapTypes(addrData *dbtypes.AddressInfo) {
 	for index, transaction := range addrData.Transactions {
 		transaction.SwapsType = exp.dataSource.GetSwapType(transaction.TxID)
 		if transaction.SwapsType != "" {
@@ -3389,7 +3600,6 @@ func (exp *ExplorerUI) AddressListData(address string, txnType dbtypes.AddrTxnVi
 		}
 		addrData.Transactions[index] = transaction
 	}
-	return
 }
 
 func (exp *ExplorerUI) MutilchainAddressListData(address string, txnType dbtypes.AddrTxnViewType, limitN,
@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package explorer
+
+import (
+	"net/http"
+
+	"github.com/decred/dcrdata/exchanges/v3"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// fiatCurrency is the currency of the values at block time on the transaction
+// and address pages: the one of the ?currency query parameter, or else the
+// index of the exchange monitor, or USD.
+func (exp *ExplorerUI) fiatCurrency(r *http.Request) string {
+	if currency := dbtypes.NormalizeCurrency(r.URL.Query().Get("currency")); currency != "" {
+		return currency
+	}
+	if exp.xcBot != nil && exp.xcBot.BtcIndex != "" {
+		return dbtypes.NormalizeCurrency(exp.xcBot.BtcIndex)
+	}
+	return "USD"
+}
+
+// fiatPrices retrieves the daily prices of the coin of the chain in the
+// currency of the page for the Unix times from to to. The prices are nil if
+// there are none, so that the pages show no values at block time.
+func (exp *ExplorerUI) fiatPrices(r *http.Request, chainType string, from, to int64) *dbtypes.FiatPrices {
+	if from <= 0 {
+		return nil
+	}
+	prices, err := exp.dataSource.FiatPrices(chainType, exp.fiatCurrency(r), from, to)
+	if err != nil {
+		log.Warnf("Unable to get the %s daily prices: %v", chainType, err)
+		return nil
+	}
+	if len(prices.Prices) == 0 {
+		return nil
+	}
+	return prices
+}
+
+// blockTimeConversion values an amount of coins at the price of the day of the
+// Unix time t. It is nil if there is no price.
+func blockTimeConversion(prices *dbtypes.FiatPrices, t int64, amount float64) *exchanges.Conversion {
+	price := prices.PriceAt(t)
+	if price == 0 {
+		return nil
+	}
+	return &exchanges.Conversion{
+		Value: amount * price,
+		Index: prices.Currency,
+	}
+}
+
+// setAddressFiatValues values the rows of an address table at the prices of
+// the day of their block.
+func (exp *ExplorerUI) setAddressFiatValues(r *http.Request, chainType string, addrData *dbtypes.AddressInfo) {
+	from, to := addrData.TimeRange()
+	if prices := exp.fiatPrices(r, chainType, from, to); prices != nil {
+		addrData.SetFiatValues(prices)
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package health checks the health of the chains of dcrdata: the connectivity
+// and height of their nodes, the height and age of the best block of the DB,
+// the freshness of their mempool monitors, the use of the external APIs in
+// place of the DB, and the staleness of their exchange rates. The checks run
+// periodically, so that frequent health probes do not reach the nodes.
+package health
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/exchanges/v3"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/mutilchain/externalapi"
+)
+
+const (
+	// DefaultPollInterval is how often the chains are checked.
+	DefaultPollInterval = 15 * time.Second
+	// nodeTimeout bounds the node requests of a check.
+	nodeTimeout = 10 * time.Second
+)
+
+// Thresholds are the limits of the health checks. A zero threshold disables
+// its check.
+type Thresholds struct {
+	// MaxHeightLag is the number of blocks the DB may be behind the node.
+	MaxHeightLag int64
+	// BlockAgeSpacings is the maximum age of the best block, in target block
+	// spacings of the chain.
+	BlockAgeSpacings float64
+	// MaxMempoolAge is the maximum time since the last update of the mempool
+	// monitor.
+	MaxMempoolAge time.Duration
+	// MaxExchangeAge is the maximum time since the last update of the
+	// exchange rates of the chain.
+	MaxExchangeAge time.Duration
+	// FallbackWindow is how long a use of the external APIs in place of the
+	// DB is reported.
+	FallbackWindow time.Duration
+}
+
+// NodeStatus is the status of the node of a chain.
+type NodeStatus struct {
+	Connections int64
+	Height      int64
+}
+
+// Chain is a chain whose health is checked.
+type Chain struct {
+	Name          string
+	TargetSpacing time.Duration
+	// Node requests the status of the node.
+	Node func(ctx context.Context) (*NodeStatus, error)
+	// BestBlock returns the height and time of the best block of the DB.
+	BestBlock func() (height int64, blockTime time.Time, err error)
+	// Markets are the markets of the exchange bot that price the chain.
+	Markets []string
+}
+
+// MempoolMonitor is a mempool monitor of a chain.
+type MempoolMonitor interface {
+	LastUpdate() time.Time
+}
+
+// MempoolFunc is a function returning the time of the last update of a
+// mempool, as a MempoolMonitor.
+type MempoolFunc func() time.Time
+
+// LastUpdate calls f.
+func (f MempoolFunc) LastUpdate() time.Time {
+	return f()
+}
+
+// ExchangeMonitor reports the status of the exchanges of the exchange bot.
+type ExchangeMonitor interface {
+	ExchangeHealth() []*exchanges.ExchangeHealth
+}
+
+// Config is the configuration of the Monitor.
+type Config struct {
+	Chains       []*Chain
+	Exchanges    ExchangeMonitor // optional
+	Thresholds   Thresholds
+	PollInterval time.Duration
+}
+
+// Monitor checks the health of the chains periodically, and keeps their
+// latest reports.
+type Monitor struct {
+	cfg Config
+
+	mtx      sync.RWMutex
+	mempools map[string]MempoolMonitor
+	reports  map[string]*apitypes.ChainHealth
+}
+
+// NewMonitor creates a Monitor. The chains are not checked until Run.
+func NewMonitor(cfg *Config) *Monitor {
+	m := &Monitor{
+		cfg:      *cfg,
+		mempools: make(map[string]MempoolMonitor),
+		reports:  make(map[string]*apitypes.ChainHealth),
+	}
+	if m.cfg.PollInterval <= 0 {
+		m.cfg.PollInterval = DefaultPollInterval
+	}
+	return m
+}
+
+// SetMempool sets the mempool monitor of a chain. The mempool freshness of
+// chains without a mempool monitor is not checked.
+func (m *Monitor) SetMempool(chainType string, mp MempoolMonitor) {
+	m.mtx.Lock()
+	m.mempools[chainType] = mp
+	m.mtx.Unlock()
+}
+
+// Run checks the chains until the context is canceled.
+func (m *Monitor) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		m.check(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	var xcs []*exchanges.ExchangeHealth
+	if m.cfg.Exchanges != nil {
+		xcs = m.cfg.Exchanges.ExchangeHealth()
+	}
+	for _, chain := range m.cfg.Chains {
+		m.mtx.RLock()
+		mp := m.mempools[chain.Name]
+		m.mtx.RUnlock()
+		report := m.checkChain(ctx, chain, mp, xcs, time.Now())
+		if !report.Healthy() && ctx.Err() == nil {
+			log.Debugf("%s is %s: %v", chain.Name, report.Status, report.Failures)
+		}
+		m.mtx.Lock()
+		m.reports[chain.Name] = report
+		m.mtx.Unlock()
+	}
+}
+
+// ageSeconds is the age in seconds of t, or -1 if t is zero.
+func ageSeconds(now, t time.Time) int64 {
+	if t.IsZero() {
+		return -1
+	}
+	return int64(now.Sub(t).Seconds())
+}
+
+func (m *Monitor) checkChain(ctx context.Context, chain *Chain, mp MempoolMonitor,
+	xcs []*exchanges.ExchangeHealth, now time.Time) *apitypes.ChainHealth {
+	th := &m.cfg.Thresholds
+	h := &apitypes.ChainHealth{
+		Chain:         chain.Name,
+		NodeHeight:    -1,
+		DBHeight:      -1,
+		HeightLag:     -1,
+		BlockAge:      -1,
+		TargetSpacing: int64(chain.TargetSpacing.Seconds()),
+		MempoolAge:    -1,
+		ExchangeAge:   -1,
+		CheckTime:     now.Unix(),
+	}
+	fail := func(format string, args ...any) {
+		h.Failures = append(h.Failures, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...any) {
+		h.Warnings = append(h.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	// Node connectivity.
+	ctxNode, cancel := context.WithTimeout(ctx, nodeTimeout)
+	node, err := chain.Node(ctxNode)
+	cancel()
+	if err != nil {
+		fail("node unreachable: %v", err)
+	} else {
+		h.NodeConnected = true
+		h.NodeConnections = node.Connections
+		h.NodeHeight = node.Height
+		if node.Connections == 0 {
+			fail("node has no peers")
+		}
+	}
+
+	// Height lag and best block age.
+	height, blockTime, err := chain.BestBlock()
+	if err != nil {
+		fail("DB best block unavailable: %v", err)
+	} else {
+		h.DBHeight = height
+		h.BlockAge = ageSeconds(now, blockTime)
+	}
+	if h.NodeHeight >= 0 && h.DBHeight >= 0 {
+		h.HeightLag = h.NodeHeight - h.DBHeight
+		if th.MaxHeightLag > 0 && h.HeightLag > th.MaxHeightLag {
+			fail("DB is %d blocks behind the node", h.HeightLag)
+		}
+	}
+	if th.BlockAgeSpacings > 0 && h.BlockAge >= 0 && chain.TargetSpacing > 0 {
+		maxAge := time.Duration(th.BlockAgeSpacings * float64(chain.TargetSpacing))
+		if age := time.Duration(h.BlockAge) * time.Second; age > maxAge {
+			fail("best block is %v old, over %v", age, maxAge)
+		}
+	}
+
+	// Mempool monitor freshness.
+	if mp != nil {
+		h.MempoolAge = ageSeconds(now, mp.LastUpdate())
+		if th.MaxMempoolAge > 0 {
+			if h.MempoolAge < 0 {
+				fail("mempool not monitored yet")
+			} else if age := time.Duration(h.MempoolAge) * time.Second; age > th.MaxMempoolAge {
+				fail("mempool not updated for %v", age)
+			}
+		}
+	}
+
+	// Use of the external APIs in place of the DB.
+	if fallbacks := externalapi.Fallbacks(chain.Name); !fallbacks.Last.IsZero() &&
+		now.Sub(fallbacks.Last) < th.FallbackWindow {
+		h.ExternalAPIUsed = true
+		warn("external APIs used in place of the DB %d times (%d failed)",
+			fallbacks.Count, fallbacks.Failures)
+	}
+
+	// Exchange rate staleness, from the latest update of a working exchange.
+	var numExchanges int
+	var lastUpdate time.Time
+	for _, xc := range xcs {
+		if !slices.Contains(chain.Markets, xc.Market) {
+			continue
+		}
+		numExchanges++
+		if !xc.Failed && xc.LastUpdate.After(lastUpdate) {
+			lastUpdate = xc.LastUpdate
+		}
+	}
+	if numExchanges > 0 {
+		h.ExchangeAge = ageSeconds(now, lastUpdate)
+		if th.MaxExchangeAge > 0 {
+			if h.ExchangeAge < 0 {
+				warn("no exchange rates")
+			} else if age := time.Duration(h.ExchangeAge) * time.Second; age > th.MaxExchangeAge {
+				warn("exchange rates not updated for %v", age)
+			}
+		}
+	}
+
+	switch {
+	case len(h.Failures) > 0:
+		h.Status = apitypes.HealthFailing
+	case len(h.Warnings) > 0:
+		h.Status = apitypes.HealthDegraded
+	default:
+		h.Status = apitypes.HealthOK
+	}
+	return h
+}
+
+// notChecked is the report of a chain before its first check.
+func notChecked(chainType string) *apitypes.ChainHealth {
+	return &apitypes.ChainHealth{
+		Chain:    chainType,
+		Status:   apitypes.HealthFailing,
+		Failures: []string{"not checked yet"},
+	}
+}
+
+// ChainHealth returns the latest report of a chain, and false if the chain is
+// not checked.
+func (m *Monitor) ChainHealth(chainType string) (*apitypes.ChainHealth, bool) {
+	for _, chain := range m.cfg.Chains {
+		if chain.Name != chainType {
+			continue
+		}
+		m.mtx.RLock()
+		defer m.mtx.RUnlock()
+		if report := m.reports[chainType]; report != nil {
+			return report, true
+		}
+		return notChecked(chainType), true
+	}
+	return nil, false
+}
+
+// Readiness returns the latest reports of all the chains. dcrdata is ready if
+// no chain is failing.
+func (m *Monitor) Readiness() *apitypes.Readiness {
+	r := &apitypes.Readiness{
+		Ready:  true,
+		Status: apitypes.HealthOK,
+		Chains: make([]*apitypes.ChainHealth, 0, len(m.cfg.Chains)),
+	}
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	for _, chain := range m.cfg.Chains {
+		report := m.reports[chain.Name]
+		if report == nil {
+			report = notChecked(chain.Name)
+		}
+		r.Chains = append(r.Chains, report)
+		switch report.Status {
+		case apitypes.HealthFailing:
+			r.Ready = false
+			r.Status = apitypes.HealthFailing
+		case apitypes.HealthDegraded:
+			if r.Status == apitypes.HealthOK {
+				r.Status = apitypes.HealthDegraded
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/exchanges/v3"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+)
+
+type fakeExchanges []*exchanges.ExchangeHealth
+
+func (f fakeExchanges) ExchangeHealth() []*exchanges.ExchangeHealth { return f }
+
+func TestMonitor(t *testing.T) {
+	now := time.Now()
+	chain := func(name string, nodeErr error, nodeHeight, dbHeight int64, blockAge time.Duration) *Chain {
+		return &Chain{
+			Name:          name,
+			TargetSpacing: 5 * time.Minute,
+			Node: func(context.Context) (*NodeStatus, error) {
+				if nodeErr != nil {
+					return nil, nodeErr
+				}
+				return &NodeStatus{Connections: 8, Height: nodeHeight}, nil
+			},
+			BestBlock: func() (int64, time.Time, error) {
+				return dbHeight, now.Add(-blockAge), nil
+			},
+			Markets: []string{name},
+		}
+	}
+	m := NewMonitor(&Config{
+		Chains: []*Chain{
+			chain("dcr", nil, 100, 100, time.Minute),
+			chain("btc", nil, 100, 90, time.Minute),
+			chain("ltc", errors.New("connection refused"), 0, 100, 2*time.Hour),
+			chain("xmr", nil, 100, 100, time.Minute),
+		},
+		Exchanges: fakeExchanges{
+			{Market: "dcr", LastUpdate: now.Add(-time.Minute)},
+			{Market: "xmr", LastUpdate: now.Add(-2 * time.Hour)},
+			{Market: "xmr", LastUpdate: now, Failed: true},
+		},
+		Thresholds: Thresholds{
+			MaxHeightLag:     3,
+			BlockAgeSpacings: 12,
+			MaxMempoolAge:    20 * time.Minute,
+			MaxExchangeAge:   30 * time.Minute,
+			FallbackWindow:   time.Hour,
+		},
+	})
+
+	if r := m.Readiness(); r.Ready {
+		t.Errorf("ready before the first check")
+	}
+
+	m.SetMempool("dcr", MempoolFunc(func() time.Time { return now }))
+	m.SetMempool("btc", MempoolFunc(func() time.Time { return time.Time{} }))
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go m.Run(ctx, &wg)
+	cancel()
+	wg.Wait()
+
+	tests := []struct {
+		chain    string
+		status   string
+		failures int
+	}{
+		{"dcr", apitypes.HealthOK, 0},
+		{"btc", apitypes.HealthFailing, 2},  // lag and mempool
+		{"ltc", apitypes.HealthFailing, 2},  // node and block age
+		{"xmr", apitypes.HealthDegraded, 0}, // stale exchange rates
+	}
+	for _, tt := range tests {
+		h, ok := m.ChainHealth(tt.chain)
+		if !ok {
+			t.Fatalf("no report for %s", tt.chain)
+		}
+		if h.Status != tt.status || len(h.Failures) != tt.failures {
+			t.Errorf("%s: status %s with failures %v, want %s with %d failures",
+				tt.chain, h.Status, h.Failures, tt.status, tt.failures)
+		}
+	}
+	if h, _ := m.ChainHealth("btc"); h.HeightLag != 10 || h.MempoolAge != -1 {
+		t.Errorf("btc: lag %d, mempool age %d", h.HeightLag, h.MempoolAge)
+	}
+	if h, _ := m.ChainHealth("xmr"); h.ExchangeAge != 7200 || h.MempoolAge != -1 {
+		t.Errorf("xmr: exchange age %d, mempool age %d", h.ExchangeAge, h.MempoolAge)
+	}
+	if _, ok := m.ChainHealth("doge"); ok {
+		t.Errorf("report for an unknown chain")
+	}
+
+	r := m.Readiness()
+	if r.Ready || r.Status != apitypes.HealthFailing || len(r.Chains) != 4 {
+		t.Errorf("readiness %v %s with %d chains", r.Ready, r.Status, len(r.Chains))
+	}
+}
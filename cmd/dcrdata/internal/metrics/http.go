@@ -0,0 +1,54 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware records the duration of the requests of a chi router by route
+// pattern, e.g. "/api/block/{idx}", rather than by path. Requests that match
+// no route share the "unmatched" route.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+		m.httpDuration.WithLabelValues(route, methodLabel(r.Method), codeClass(ww.Status())).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// methodLabel bounds the methods of the requests to the standard ones.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "other"
+}
+
+// codeClass returns the class of a status code, e.g. "2xx".
+func codeClass(code int) string {
+	if code == 0 {
+		code = http.StatusOK // nothing written
+	}
+	if code < 100 || code > 599 {
+		return "other"
+	}
+	return strconv.Itoa(code/100) + "xx"
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package metrics exports the operational metrics of dcrdata in the Prometheus
+// text format. The labels of every metric have a bounded set of values: chain
+// names, cache kinds, pubsub signals, configured exchanges and the patterns of
+// the routes of the HTTP routers.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/exchanges/v3"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/cache"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+const (
+	namespace = "dcrdata"
+
+	// DefaultPollInterval is how often the heights of the nodes are polled.
+	DefaultPollInterval = 30 * time.Second
+	// nodeHeightTimeout bounds the height request of a poll.
+	nodeHeightTimeout = 10 * time.Second
+	// syncRateTTL is the age after which a sync rate is reported as zero since
+	// the import that reported it has finished.
+	syncRateTTL = 5 * time.Minute
+)
+
+// Chain is a chain whose node and database heights are exported.
+type Chain struct {
+	Name string
+	// NodeHeight requests the best block height of the node of the chain.
+	NodeHeight func(ctx context.Context) (int64, error)
+	// DBHeight is the best block height of the database.
+	DBHeight func() int64
+	// Charts returns the chart cache of the chain, or nil if it is not ready.
+	Charts func() ChartCache
+}
+
+// ChartCache is a chart cache with a state that changes with its data.
+type ChartCache interface {
+	StateID() uint64
+	Height() int32
+}
+
+// PubSub is the websocket hub of the pubsub server.
+type PubSub interface {
+	NumClients() int
+	SubscriptionCounts() map[pstypes.HubSignal]int
+}
+
+// ExchangeMonitor reports the status of the exchanges of the exchange bot.
+type ExchangeMonitor interface {
+	ExchangeHealth() []*exchanges.ExchangeHealth
+}
+
+// Config specifies the sources of the Metrics. Nil sources are not exported.
+type Config struct {
+	Chains       []*Chain
+	APICache     *apitypes.APICache
+	AddressCache *cache.AddressCache
+	PubSub       PubSub
+	Exchanges    ExchangeMonitor
+	PollInterval time.Duration
+}
+
+// Metrics collects the metrics of dcrdata. The latencies of the Postgres
+// queries and the HTTP handlers are observed with ObserveQuery and Middleware
+// from the creation of the Metrics, so that the queries of the startup are
+// included. The metrics of the sources of the Config given to Register are
+// read on each scrape, except for the node heights that are polled by Run.
+type Metrics struct {
+	cfg      Config
+	registry *prometheus.Registry
+
+	heightsMtx  sync.RWMutex
+	nodeHeights map[string]int64
+	nodeUp      map[string]bool
+
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+
+	nodeHeightDesc     *prometheus.Desc
+	nodeUpDesc         *prometheus.Desc
+	dbHeightDesc       *prometheus.Desc
+	syncBlocksDesc     *prometheus.Desc
+	syncTxsDesc        *prometheus.Desc
+	syncVinsDesc       *prometheus.Desc
+	syncVoutsDesc      *prometheus.Desc
+	apiCacheHitsDesc   *prometheus.Desc
+	apiCacheMissDesc   *prometheus.Desc
+	apiCacheUtilDesc   *prometheus.Desc
+	apiCacheBlocksDesc *prometheus.Desc
+	addrCacheHitsDesc  *prometheus.Desc
+	addrCacheMissDesc  *prometheus.Desc
+	addrCacheAddrsDesc *prometheus.Desc
+	chartStateDesc     *prometheus.Desc
+	chartHeightDesc    *prometheus.Desc
+	pubsubClientsDesc  *prometheus.Desc
+	pubsubSubsDesc     *prometheus.Desc
+	xcLastUpdateDesc   *prometheus.Desc
+	xcFailedDesc       *prometheus.Desc
+	xcWsErrorsDesc     *prometheus.Desc
+}
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+// New creates the Metrics with the Go runtime and process metrics.
+func New() *Metrics {
+	m := &Metrics{
+		registry:    prometheus.NewRegistry(),
+		nodeHeights: make(map[string]int64),
+		nodeUp:      make(map[string]bool),
+
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "postgres_query_duration_seconds",
+			Help:      "Duration of the PostgreSQL queries (query) and statements (exec).",
+			Buckets:   []float64{.001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"kind"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "postgres_query_errors_total",
+			Help:      "Failed PostgreSQL queries and statements.",
+		}, []string{"kind"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by route pattern, method and status class.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "code"}),
+
+		nodeHeightDesc:     newDesc("node_height", "Best block height of the node.", "chain"),
+		nodeUpDesc:         newDesc("node_up", "Whether the last height request to the node succeeded.", "chain"),
+		dbHeightDesc:       newDesc("db_height", "Best block height of the database.", "chain"),
+		syncBlocksDesc:     newDesc("sync_blocks_per_second", "Block import rate, zero when no import is running.", "chain"),
+		syncTxsDesc:        newDesc("sync_txs_per_second", "Transaction import rate, zero when no import is running.", "chain"),
+		syncVinsDesc:       newDesc("sync_vins_per_second", "Input import rate, zero when no import is running.", "chain"),
+		syncVoutsDesc:      newDesc("sync_vouts_per_second", "Output import rate, zero when no import is running.", "chain"),
+		apiCacheHitsDesc:   newDesc("apicache_hits_total", "Block cache hits."),
+		apiCacheMissDesc:   newDesc("apicache_misses_total", "Block cache misses."),
+		apiCacheUtilDesc:   newDesc("apicache_utilization_ratio", "Fraction of the block cache capacity in use."),
+		apiCacheBlocksDesc: newDesc("apicache_blocks", "Blocks in the block cache."),
+		addrCacheHitsDesc:  newDesc("addresscache_hits_total", "Address cache hits by kind of data.", "kind"),
+		addrCacheMissDesc:  newDesc("addresscache_misses_total", "Address cache misses by kind of data.", "kind"),
+		addrCacheAddrsDesc: newDesc("addresscache_addresses", "Addresses in the address cache."),
+		chartStateDesc:     newDesc("chart_state_id", "State ID of the chart cache, which changes with its data.", "chain"),
+		chartHeightDesc:    newDesc("chart_height", "Block height of the chart cache.", "chain"),
+		pubsubClientsDesc:  newDesc("pubsub_clients", "Connected pubsub websocket clients."),
+		pubsubSubsDesc:     newDesc("pubsub_subscriptions", "Pubsub clients subscribed to each signal.", "signal"),
+		xcLastUpdateDesc:   newDesc("exchange_last_update_timestamp_seconds", "Time of the last successful update of the exchange.", "exchange", "market"),
+		xcFailedDesc:       newDesc("exchange_failed", "Whether the last update of the exchange failed.", "exchange", "market"),
+		xcWsErrorsDesc:     newDesc("exchange_websocket_errors", "Websocket errors of the exchange since its last websocket update.", "exchange", "market"),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.queryDuration, m.queryErrors, m.httpDuration,
+	)
+	return m
+}
+
+// Register adds the metrics of the sources of the Config. It must be called
+// once, before Run.
+func (m *Metrics) Register(cfg *Config) error {
+	m.cfg = *cfg
+	if m.cfg.PollInterval <= 0 {
+		m.cfg.PollInterval = DefaultPollInterval
+	}
+	return m.registry.Register(m)
+}
+
+// Handler serves the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveQuery records the duration of a PostgreSQL query. It is a
+// dcrpg.QueryObserver.
+func (m *Metrics) ObserveQuery(kind string, elapsed time.Duration, err error) {
+	m.queryDuration.WithLabelValues(kind).Observe(elapsed.Seconds())
+	if err != nil {
+		m.queryErrors.WithLabelValues(kind).Inc()
+	}
+}
+
+// Run polls the heights of the nodes until the context is canceled.
+func (m *Metrics) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		m.pollNodeHeights(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Metrics) pollNodeHeights(ctx context.Context) {
+	for _, chain := range m.cfg.Chains {
+		if chain.NodeHeight == nil {
+			continue
+		}
+		ctxHeight, cancel := context.WithTimeout(ctx, nodeHeightTimeout)
+		height, err := chain.NodeHeight(ctxHeight)
+		cancel()
+		if err != nil && ctx.Err() == nil {
+			log.Debugf("Unable to get the %s node height: %v", chain.Name, err)
+		}
+		m.heightsMtx.Lock()
+		m.nodeUp[chain.Name] = err == nil
+		if err == nil {
+			m.nodeHeights[chain.Name] = height
+		}
+		m.heightsMtx.Unlock()
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		m.nodeHeightDesc, m.nodeUpDesc, m.dbHeightDesc,
+		m.syncBlocksDesc, m.syncTxsDesc, m.syncVinsDesc, m.syncVoutsDesc,
+		m.apiCacheHitsDesc, m.apiCacheMissDesc, m.apiCacheUtilDesc, m.apiCacheBlocksDesc,
+		m.addrCacheHitsDesc, m.addrCacheMissDesc, m.addrCacheAddrsDesc,
+		m.chartStateDesc, m.chartHeightDesc,
+		m.pubsubClientsDesc, m.pubsubSubsDesc,
+		m.xcLastUpdateDesc, m.xcFailedDesc, m.xcWsErrorsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	gauge := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labels...)
+	}
+	counter := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, labels...)
+	}
+	boolean := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	// Heights and charts.
+	m.heightsMtx.RLock()
+	for _, chain := range m.cfg.Chains {
+		if height, ok := m.nodeHeights[chain.Name]; ok {
+			gauge(m.nodeHeightDesc, float64(height), chain.Name)
+		}
+		if up, ok := m.nodeUp[chain.Name]; ok {
+			gauge(m.nodeUpDesc, boolean(up), chain.Name)
+		}
+	}
+	m.heightsMtx.RUnlock()
+	for _, chain := range m.cfg.Chains {
+		if chain.DBHeight != nil {
+			gauge(m.dbHeightDesc, float64(chain.DBHeight()), chain.Name)
+		}
+		if chain.Charts == nil {
+			continue
+		}
+		if charts := chain.Charts(); charts != nil {
+			gauge(m.chartStateDesc, float64(charts.StateID()), chain.Name)
+			gauge(m.chartHeightDesc, float64(charts.Height()), chain.Name)
+		}
+	}
+
+	// Sync rates.
+	for chain, rate := range dcrpg.SyncRates() {
+		if time.Since(rate.Time) > syncRateTTL {
+			rate = dcrpg.SyncRate{}
+		}
+		gauge(m.syncBlocksDesc, rate.BlocksPerSec, chain)
+		gauge(m.syncTxsDesc, rate.TxPerSec, chain)
+		gauge(m.syncVinsDesc, rate.VinsPerSec, chain)
+		gauge(m.syncVoutsDesc, rate.VoutsPerSec, chain)
+	}
+
+	// Caches.
+	if apic := m.cfg.APICache; apic != nil {
+		counter(m.apiCacheHitsDesc, float64(apic.Hits()))
+		counter(m.apiCacheMissDesc, float64(apic.Misses()))
+		gauge(m.apiCacheUtilDesc, apic.Utilization()/100)
+		gauge(m.apiCacheBlocksDesc, float64(apic.UtilizationBlocks()))
+	}
+	if ac := m.cfg.AddressCache; ac != nil {
+		stats := []struct {
+			kind  string
+			stats func() (int, int)
+		}{
+			{"row", ac.RowStats},
+			{"balance", ac.BalanceStats},
+			{"utxo", ac.UtxoStats},
+			{"history", ac.HistoryStats},
+		}
+		for _, s := range stats {
+			hits, misses := s.stats()
+			counter(m.addrCacheHitsDesc, float64(hits), s.kind)
+			counter(m.addrCacheMissDesc, float64(misses), s.kind)
+		}
+		gauge(m.addrCacheAddrsDesc, float64(ac.NumAddresses()))
+	}
+	// Pubsub.
+	if ps := m.cfg.PubSub; ps != nil {
+		gauge(m.pubsubClientsDesc, float64(ps.NumClients()))
+		for sig, n := range ps.SubscriptionCounts() {
+			gauge(m.pubsubSubsDesc, float64(n), sig.String())
+		}
+	}
+
+	// Exchanges.
+	if m.cfg.Exchanges != nil {
+		for _, xc := range m.cfg.Exchanges.ExchangeHealth() {
+			if !xc.LastUpdate.IsZero() {
+				gauge(m.xcLastUpdateDesc, float64(xc.LastUpdate.Unix()), xc.Token, xc.Market)
+			}
+			gauge(m.xcFailedDesc, boolean(xc.Failed), xc.Token, xc.Market)
+			gauge(m.xcWsErrorsDesc, float64(xc.WsErrors), xc.Token, xc.Market)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+type fakePubSub struct{}
+
+func (fakePubSub) NumClients() int { return 3 }
+
+func (fakePubSub) SubscriptionCounts() map[pstypes.HubSignal]int {
+	return map[pstypes.HubSignal]int{pstypes.SigNewBlock: 2}
+}
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape returned status %d", rec.Code)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := New()
+	err := m.Register(&Config{
+		Chains: []*Chain{{
+			Name:       "dcr",
+			NodeHeight: func(context.Context) (int64, error) { return 110, nil },
+			DBHeight:   func() int64 { return 100 },
+			Charts:     func() ChartCache { return nil },
+		}, {
+			Name:       "btc",
+			NodeHeight: func(context.Context) (int64, error) { return 0, errors.New("down") },
+		}},
+		PubSub: fakePubSub{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.pollNodeHeights(context.Background())
+	m.ObserveQuery("query", 20*time.Millisecond, nil)
+	m.ObserveQuery("exec", time.Millisecond, errors.New("fail"))
+
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/block/{idx}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	for _, path := range []string{"/block/1", "/block/2", "/nonsense"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`dcrdata_node_height{chain="dcr"} 110`,
+		`dcrdata_node_up{chain="dcr"} 1`,
+		`dcrdata_node_up{chain="btc"} 0`,
+		`dcrdata_db_height{chain="dcr"} 100`,
+		`dcrdata_pubsub_clients 3`,
+		`dcrdata_pubsub_subscriptions{signal="newblock"} 2`,
+		`dcrdata_postgres_query_duration_seconds_count{kind="query"} 1`,
+		`dcrdata_postgres_query_errors_total{kind="exec"} 1`,
+		`dcrdata_http_request_duration_seconds_count{code="4xx",method="GET",route="/block/{idx}"} 2`,
+		`dcrdata_http_request_duration_seconds_count{code="4xx",method="GET",route="unmatched"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %q", want)
+		}
+	}
+	if strings.Contains(body, `dcrdata_node_height{chain="btc"}`) {
+		t.Errorf("height of an unreachable node exported")
+	}
+}
+
+func Test_codeClass(t *testing.T) {
+	tests := map[int]string{0: "2xx", 200: "2xx", 301: "3xx", 503: "5xx", 999: "other"}
+	for code, want := range tests {
+		if got := codeClass(code); got != want {
+			t.Errorf("codeClass(%d) = %s, want %s", code, got, want)
+		}
+	}
+}
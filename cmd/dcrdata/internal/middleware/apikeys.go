@@ -0,0 +1,338 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/didip/tollbooth/v6"
+	"github.com/didip/tollbooth/v6/limiter"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// APIKeyHeader is the request header with the API key of a request.
+	APIKeyHeader = "X-API-Key"
+	// APIKeyQueryParam is the URL query parameter with the API key of a
+	// request without an APIKeyHeader.
+	APIKeyQueryParam = "apikey"
+
+	// apiKeyCacheTTL is how long a key is used before it is retrieved again,
+	// which is how long it takes for a revocation or a change of limits to
+	// take effect.
+	apiKeyCacheTTL = time.Minute
+
+	// maxUnknownAPIKeys is the maximum number of unknown keys that are cached.
+	maxUnknownAPIKeys = 10000
+
+	// unmatchedEndpoint is the usage endpoint of the requests that match no
+	// route, so that the paths of such requests do not each add a row.
+	unmatchedEndpoint = "unmatched"
+)
+
+// APIKeyStore is the storage of the API keys and their usage.
+type APIKeyStore interface {
+	// APIKeyByHash returns dbtypes.ErrNoResult for an unknown key.
+	APIKeyByHash(keyHash string) (*dbtypes.APIKey, error)
+	APIKeyDailyUsage(keyID int64, day time.Time) (int64, error)
+	StoreAPIKeyUsage(usage []*dbtypes.APIKeyUsage) error
+}
+
+// apiKeyEntry is a cached API key, or an unknown key if key is nil.
+type apiKeyEntry struct {
+	key     *dbtypes.APIKey
+	limiter *limiter.Limiter
+	fetched time.Time
+	// used is the number of requests made with the key on day.
+	day  time.Time
+	used int64
+}
+
+type apiKeyUsageKey struct {
+	keyID    int64
+	day      time.Time
+	endpoint string
+}
+
+// APIKeys authenticates requests made with an API key, applies the rate limit
+// and daily quota of the key, and counts the requests to each endpoint. The
+// counts are kept in memory and stored by Run. Requests without an API key
+// are passed through, and are left to the IP based limits of Tollbooth.
+// Requests with a key that is not cached as a valid key are limited by IP
+// before the key is retrieved, so that random keys cannot be used to bypass
+// the IP limits or to query the database at will.
+//
+// The daily quotas are enforced per process. Instances of dcrdata sharing a
+// database each allow up to the quota of a key until they retrieve the counts
+// stored by the others, which happens when a key is retrieved again.
+type APIKeys struct {
+	store         APIKeyStore
+	lookupLimiter *Limiter
+	now           func() time.Time
+	maxUnknown    int
+
+	mtx     sync.Mutex
+	keys    map[string]*apiKeyEntry // by key hash
+	unknown int                     // number of unknown keys in keys
+	usage   map[apiKeyUsageKey]int64
+}
+
+// NewAPIKeys creates a new APIKeys for the keys of the APIKeyStore. The
+// lookupLimiter limits by IP the requests with a key that is not cached as a
+// valid key.
+func NewAPIKeys(store APIKeyStore, lookupLimiter *Limiter) *APIKeys {
+	return &APIKeys{
+		store:         store,
+		lookupLimiter: lookupLimiter,
+		now:           time.Now,
+		maxUnknown:    maxUnknownAPIKeys,
+		keys:          make(map[string]*apiKeyEntry),
+		usage:         make(map[apiKeyUsageKey]int64),
+	}
+}
+
+// RequestAPIKey returns the API key of a request that was authenticated by
+// APIKeys.Middleware, or nil for a request without an API key.
+func RequestAPIKey(r *http.Request) *dbtypes.APIKey {
+	key, _ := r.Context().Value(ctxAPIKey).(*dbtypes.APIKey)
+	return key
+}
+
+// Middleware authenticates the API key of a request from the APIKeyHeader
+// header or the APIKeyQueryParam URL query parameter. A request with an
+// unknown or revoked key is rejected with 401, and a request exceeding the
+// rate limit or the daily quota of its key, or the IP limit of the key
+// lookups, with 429. The API key of an accepted request is added to its
+// context.
+func (a *APIKeys) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(APIKeyHeader)
+		if key == "" {
+			key = r.URL.Query().Get(APIKeyQueryParam)
+		}
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		keyHash := dbtypes.HashAPIKey(key)
+		if !a.isCachedValid(keyHash) && limitByRequest(a.lookupLimiter, w, r) {
+			return
+		}
+
+		apiKey, status, retryAfter := a.admit(keyHash)
+		switch status {
+		case http.StatusOK:
+		case http.StatusTooManyRequests:
+			if retryAfter > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+			}
+			http.Error(w, "API key request limit exceeded.", status)
+			return
+		case http.StatusUnauthorized:
+			http.Error(w, "Invalid API key.", status)
+			return
+		default:
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), ctxAPIKey, apiKey))
+		next.ServeHTTP(w, r)
+
+		// The route pattern is complete once the request has been routed by
+		// the mounted routers. A request that matches no route of a mounted
+		// router has the pattern of the mount.
+		endpoint := unmatchedEndpoint
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			endpoint = rctx.RoutePattern()
+		}
+		a.mtx.Lock()
+		a.usage[apiKeyUsageKey{apiKey.ID, utcDay(a.now()), endpoint}]++
+		a.mtx.Unlock()
+	})
+}
+
+// isCachedValid checks if the key of the given hash is cached as a valid key.
+// The cache entry may be expired, since a valid key is retrieved at most once
+// per apiKeyCacheTTL.
+func (a *APIKeys) isCachedValid(keyHash string) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	entry := a.keys[keyHash]
+	return entry != nil && entry.key != nil && !entry.key.Revoked()
+}
+
+// admit checks and counts a request with the key of the given hash. The HTTP
+// status of the check is returned, with the time until the request would be
+// allowed when the daily quota is exceeded.
+func (a *APIKeys) admit(keyHash string) (*dbtypes.APIKey, int, time.Duration) {
+	now := a.now()
+	day := utcDay(now)
+
+	a.mtx.Lock()
+	entry := a.keys[keyHash]
+	a.mtx.Unlock()
+
+	if entry == nil || now.Sub(entry.fetched) > apiKeyCacheTTL {
+		var err error
+		entry, err = a.fetch(keyHash, now)
+		if err != nil {
+			apiLog.Errorf("Unable to retrieve API key: %v", err)
+			return nil, http.StatusServiceUnavailable, 0
+		}
+	}
+	if entry.key == nil || entry.key.Revoked() {
+		return nil, http.StatusUnauthorized, 0
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if !entry.day.Equal(day) {
+		entry.day, entry.used = day, 0
+	}
+	if quota := entry.key.DailyQuota; quota > 0 && entry.used >= quota {
+		return nil, http.StatusTooManyRequests, day.AddDate(0, 0, 1).Sub(now)
+	}
+	if entry.limiter != nil {
+		if httpError := tollbooth.LimitByKeys(entry.limiter, []string{keyHash}); httpError != nil {
+			return nil, http.StatusTooManyRequests, 0
+		}
+	}
+	entry.used++
+	return entry.key, http.StatusOK, 0
+}
+
+// fetch retrieves a key and the number of requests made with it today, and
+// replaces its cache entry. Unknown keys are cached too so that they do not
+// each cost a query, up to maxUnknown of them.
+func (a *APIKeys) fetch(keyHash string, now time.Time) (*apiKeyEntry, error) {
+	key, err := a.store.APIKeyByHash(keyHash)
+	if errors.Is(err, dbtypes.ErrNoResult) {
+		key, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	day := utcDay(now)
+	entry := &apiKeyEntry{key: key, fetched: now, day: day}
+	if key != nil && !key.Revoked() {
+		if entry.used, err = a.store.APIKeyDailyUsage(key.ID, day); err != nil {
+			return nil, err
+		}
+		if key.RateLimit > 0 {
+			entry.limiter = tollbooth.NewLimiter(key.RateLimit, nil).SetBurst(max(key.Burst, 1))
+		}
+	}
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if key != nil {
+		// Add the requests that are not stored yet.
+		for k, count := range a.usage {
+			if k.keyID == key.ID && k.day.Equal(day) {
+				entry.used += count
+			}
+		}
+		// Keep the token bucket of a key with unchanged limits.
+		if old := a.keys[keyHash]; old != nil && old.limiter != nil && entry.limiter != nil &&
+			old.key.RateLimit == key.RateLimit && old.key.Burst == key.Burst {
+			entry.limiter = old.limiter
+		}
+	}
+	a.setEntry(keyHash, entry)
+	return entry, nil
+}
+
+// setEntry replaces the cache entry of a key, evicting another unknown key if
+// the cache is full of them. The mutex must be held.
+func (a *APIKeys) setEntry(keyHash string, entry *apiKeyEntry) {
+	if old := a.keys[keyHash]; old != nil && old.key == nil {
+		a.unknown--
+	}
+	if entry.key == nil {
+		if a.unknown >= a.maxUnknown {
+			for h, e := range a.keys {
+				if e.key == nil {
+					delete(a.keys, h)
+					a.unknown--
+					break
+				}
+			}
+		}
+		a.unknown++
+	}
+	a.keys[keyHash] = entry
+}
+
+// Flush stores the request counts that are not stored yet, and drops the
+// expired keys from the cache. The counts are kept for the next Flush if they
+// cannot be stored.
+func (a *APIKeys) Flush() error {
+	now := a.now()
+	a.mtx.Lock()
+	usage := make([]*dbtypes.APIKeyUsage, 0, len(a.usage))
+	for k, count := range a.usage {
+		usage = append(usage, &dbtypes.APIKeyUsage{
+			KeyID:    k.keyID,
+			Day:      k.day,
+			Endpoint: k.endpoint,
+			Count:    count,
+		})
+	}
+	a.usage = make(map[apiKeyUsageKey]int64)
+	for keyHash, entry := range a.keys {
+		if now.Sub(entry.fetched) > apiKeyCacheTTL {
+			if entry.key == nil {
+				a.unknown--
+			}
+			delete(a.keys, keyHash)
+		}
+	}
+	a.mtx.Unlock()
+
+	if err := a.store.StoreAPIKeyUsage(usage); err != nil {
+		a.mtx.Lock()
+		for _, u := range usage {
+			a.usage[apiKeyUsageKey{u.KeyID, u.Day, u.Endpoint}] += u.Count
+		}
+		a.mtx.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Run stores the request counts every flushInterval until the context is
+// canceled, and once more before returning.
+func (a *APIKeys) Run(ctx context.Context, wg *sync.WaitGroup, flushInterval time.Duration) {
+	defer wg.Done()
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := a.Flush(); err != nil {
+				apiLog.Errorf("Unable to store API key usage: %v", err)
+			}
+		case <-ctx.Done():
+			if err := a.Flush(); err != nil {
+				apiLog.Errorf("Unable to store API key usage: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// utcDay returns the UTC date of t, at midnight.
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
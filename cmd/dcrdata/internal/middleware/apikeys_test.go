@@ -0,0 +1,204 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/go-chi/chi/v5"
+)
+
+type testAPIKeyStore struct {
+	keys    map[string]*dbtypes.APIKey
+	used    int64
+	stored  []*dbtypes.APIKeyUsage
+	lookups int
+}
+
+func (s *testAPIKeyStore) APIKeyByHash(keyHash string) (*dbtypes.APIKey, error) {
+	s.lookups++
+	if key, ok := s.keys[keyHash]; ok {
+		return key, nil
+	}
+	return nil, dbtypes.ErrNoResult
+}
+
+func (s *testAPIKeyStore) APIKeyDailyUsage(int64, time.Time) (int64, error) {
+	return s.used, nil
+}
+
+func (s *testAPIKeyStore) StoreAPIKeyUsage(usage []*dbtypes.APIKeyUsage) error {
+	s.stored = append(s.stored, usage...)
+	return nil
+}
+
+func TestAPIKeys(t *testing.T) {
+	revokedAt := time.Now()
+	store := &testAPIKeyStore{
+		keys: map[string]*dbtypes.APIKey{
+			dbtypes.HashAPIKey("quota"):   {ID: 1, DailyQuota: 3},
+			dbtypes.HashAPIKey("rate"):    {ID: 2, RateLimit: 0.001, Burst: 2},
+			dbtypes.HashAPIKey("revoked"): {ID: 3, RevokedAt: &revokedAt},
+			dbtypes.HashAPIKey("plain"):   {ID: 4},
+		},
+		used: 1,
+	}
+	// The unknown, revoked, quota, rate and plain keys are each looked up
+	// once.
+	lookupLimiter := NewLimiter(0.001)
+	lookupLimiter.SetBurst(5)
+	apiKeys := NewAPIKeys(store, lookupLimiter)
+	now := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	apiKeys.now = func() time.Time { return now }
+
+	ipLimiter := NewLimiter(0.001)
+	r := chi.NewRouter()
+	r.Use(apiKeys.Middleware)
+	r.Route("/api", func(r chi.Router) {
+		r.Use(Tollbooth(ipLimiter))
+		r.Get("/block/{idx}", func(w http.ResponseWriter, r *http.Request) {})
+	})
+
+	get := func(key string, inQuery bool) *httptest.ResponseRecorder {
+		return getPath(r, "/api/block/1", key, inQuery)
+	}
+	getAt := func(path, key string) *httptest.ResponseRecorder {
+		return getPath(r, path, key, false)
+	}
+
+	// Unkeyed requests are limited by IP.
+	if w := get("", false); w.Code != http.StatusOK {
+		t.Fatalf("unkeyed request: got %d", w.Code)
+	}
+	if w := get("", false); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("unkeyed request over the IP limit: got %d", w.Code)
+	}
+
+	for _, key := range []string{"unknown", "revoked"} {
+		if w := get(key, false); w.Code != http.StatusUnauthorized {
+			t.Errorf("key %q: got %d, want 401", key, w.Code)
+		}
+	}
+
+	// One request of the quota was made before. Keyed requests skip the IP
+	// limit.
+	for i, inQuery := range []bool{false, true} {
+		if w := get("quota", inQuery); w.Code != http.StatusOK {
+			t.Fatalf("quota request %d: got %d", i, w.Code)
+		}
+	}
+	w := get("quota", false)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the quota: got %d", w.Code)
+	}
+	if retry := w.Header().Get("Retry-After"); retry != "3600" {
+		t.Errorf("got Retry-After %q, want 3600", retry)
+	}
+	// The quota is reset the next day.
+	now = now.Add(2 * time.Hour)
+	if w := get("quota", false); w.Code != http.StatusOK {
+		t.Fatalf("quota request on the next day: got %d", w.Code)
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := get("rate", false); w.Code != http.StatusOK {
+			t.Fatalf("burst request %d: got %d", i, w.Code)
+		}
+	}
+	if w := get("rate", false); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over the rate limit: got %d", w.Code)
+	}
+
+	// The requests that match no route share an endpoint, and those that
+	// match no route of a mounted router are counted for the mount.
+	for _, path := range []string{"/nope/1", "/nope/2", "/api/nope/1"} {
+		if w := getAt(path, "plain"); w.Code != http.StatusNotFound {
+			t.Fatalf("unmatched request: got %d", w.Code)
+		}
+	}
+
+	if err := apiKeys.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[int64]int64)
+	endpoints := make(map[string]int64)
+	for _, u := range store.stored {
+		counts[u.KeyID] += u.Count
+		endpoints[u.Endpoint] += u.Count
+	}
+	if counts[1] != 3 || counts[2] != 2 || counts[4] != 3 || len(counts) != 3 {
+		t.Errorf("got usage counts %v", counts)
+	}
+	if endpoints["/api/block/{idx}"] != 5 || endpoints[unmatchedEndpoint] != 2 ||
+		endpoints["/api/*"] != 1 || len(endpoints) != 3 {
+		t.Errorf("got endpoint counts %v", endpoints)
+	}
+}
+
+func getPath(h http.Handler, path, key string, inQuery bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if inQuery {
+		req.URL.RawQuery = APIKeyQueryParam + "=" + key
+	} else if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeysRandomKeys(t *testing.T) {
+	store := &testAPIKeyStore{
+		keys: map[string]*dbtypes.APIKey{
+			dbtypes.HashAPIKey("valid"): {ID: 1},
+		},
+	}
+	lookupLimiter := NewLimiter(0.001)
+	lookupLimiter.SetBurst(3)
+	apiKeys := NewAPIKeys(store, lookupLimiter)
+	apiKeys.maxUnknown = 1
+
+	r := chi.NewRouter()
+	r.Use(apiKeys.Middleware)
+	r.Get("/api/block/{idx}", func(w http.ResponseWriter, r *http.Request) {})
+
+	// The valid key is looked up once, and is then not limited by IP.
+	if w := getPath(r, "/api/block/1", "valid", false); w.Code != http.StatusOK {
+		t.Fatalf("valid key: got %d", w.Code)
+	}
+
+	// A burst of random keys is limited by IP before the keys are looked up.
+	var unauthorized, limited int
+	for i := 0; i < 100; i++ {
+		switch w := getPath(r, "/api/block/1", fmt.Sprintf("random%d", i), false); w.Code {
+		case http.StatusUnauthorized:
+			unauthorized++
+		case http.StatusTooManyRequests:
+			limited++
+		default:
+			t.Fatalf("random key %d: got %d", i, w.Code)
+		}
+	}
+	if unauthorized != 2 || limited != 98 {
+		t.Errorf("got %d unauthorized and %d limited requests", unauthorized, limited)
+	}
+	if store.lookups != 3 {
+		t.Errorf("got %d key lookups, want 3", store.lookups)
+	}
+	// Only maxUnknown unknown keys are cached.
+	if apiKeys.unknown != 1 || len(apiKeys.keys) != 2 {
+		t.Errorf("got %d cached keys with %d unknown", len(apiKeys.keys), apiKeys.unknown)
+	}
+
+	for i := 0; i < 3; i++ {
+		if w := getPath(r, "/api/block/1", "valid", false); w.Code != http.StatusOK {
+			t.Fatalf("valid key after the burst: got %d", w.Code)
+		}
+	}
+}
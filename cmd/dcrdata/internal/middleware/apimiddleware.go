@@ -14,6 +14,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/dcrd/wire"
 	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
 	"github.com/didip/tollbooth/v6"
 	"github.com/didip/tollbooth/v6/limiter"
 	"github.com/go-chi/chi/v5"
@@ -62,6 +64,7 @@ const (
 	ctxIndent
 	ctxChainType
 	ctxTSpendHash
+	ctxAPIKey
 )
 
 type DataSource interface {
@@ -70,6 +73,13 @@ type DataSource interface {
 	GetBlockHash(idx int64) (string, error)
 }
 
+// ChainDataSource specifies the methods needed to resolve the blocks of the
+// other chains.
+type ChainDataSource interface {
+	MutilchainBestBlock(chainType string) (int64, string, error)
+	MutilchainBlockHashAtHeight(height int64, chainType string) (string, error)
+}
+
 type StakeVersionsLatest func() (*chainjson.StakeVersions, error)
 
 // writeHTMLBadRequest is used for the Insight API error response for a BAD REQUEST.
@@ -97,15 +107,13 @@ func Tollbooth(l *Limiter) func(http.Handler) http.Handler {
 	// Create a middleware, capturing the Limiter.
 	return func(next http.Handler) http.Handler {
 		hf := func(w http.ResponseWriter, r *http.Request) {
-			// Rate limit using request header.
-			httpError := tollbooth.LimitByRequest(l.Limiter, w, r)
-			if httpError != nil {
-				// Bad client.
-				l.ExecOnLimitReached(w, r)
-				w.Header().Add("Content-Type", l.GetMessageContentType())
-				w.WriteHeader(httpError.StatusCode)
-				// The client may be gone, so just ignore any error on Write.
-				_, _ = w.Write([]byte(httpError.Message))
+			// Requests with a valid API key are limited by APIKeys instead.
+			if RequestAPIKey(r) != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if limitByRequest(l, w, r) {
 				return
 			}
 
@@ -116,6 +124,23 @@ func Tollbooth(l *Limiter) func(http.Handler) http.Handler {
 	}
 }
 
+// limitByRequest applies the IP rate limit of the Limiter to a request. The
+// limit response is written if the request is over the limit.
+func limitByRequest(l *Limiter, w http.ResponseWriter, r *http.Request) bool {
+	// Rate limit using request header.
+	httpError := tollbooth.LimitByRequest(l.Limiter, w, r)
+	if httpError == nil {
+		return false
+	}
+	// Bad client.
+	l.ExecOnLimitReached(w, r)
+	w.Header().Add("Content-Type", l.GetMessageContentType())
+	w.WriteHeader(httpError.StatusCode)
+	// The client may be gone, so just ignore any error on Write.
+	_, _ = w.Write([]byte(httpError.Message))
+	return true
+}
+
 // RequestBodyLimiter creates a middleware that wraps the request body using
 // MaxBytesReader for a certain number of bytes.
 func RequestBodyLimiter(lim int64) func(http.Handler) http.Handler {
@@ -221,6 +246,21 @@ func GetRawHexTx(r *http.Request) (string, error) {
 	return rawHexTx, nil
 }
 
+// GetMultichainRawHexTx retrieves the ctxRawHexTx data from the request
+// context without deserializing it as a Decred transaction. The transaction of
+// the other chains must be decoded by the caller.
+func GetMultichainRawHexTx(r *http.Request) (string, error) {
+	rawHexTx, ok := r.Context().Value(ctxRawHexTx).(string)
+	if !ok || rawHexTx == "" {
+		apiLog.Trace("hex transaction id not set")
+		return "", fmt.Errorf("hex transaction id not set")
+	}
+	if _, err := hex.DecodeString(rawHexTx); err != nil {
+		return "", fmt.Errorf("invalid hex transaction: %w", err)
+	}
+	return rawHexTx, nil
+}
+
 // NoOrigin removes any Origin from the request header.
 func NoOrigin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -322,6 +362,17 @@ func GetMultichainTxID(r *http.Request) (string, string) {
 	return chainType, hashStr
 }
 
+// GetChainTypeCtx retrieves the ctxChainType data from the request context. If
+// not set, the return value is an empty string.
+func GetChainTypeCtx(r *http.Request) string {
+	chainType, ok := r.Context().Value(ctxChainType).(string)
+	if !ok {
+		apiLog.Trace("chain type not set")
+		return ""
+	}
+	return chainType
+}
+
 // GetTxnsCtx retrieves the ctxTxns data from the request context. If not set,
 // the return value is an empty string slice.
 func GetTxnsCtx(r *http.Request) ([]*chainhash.Hash, error) {
@@ -767,6 +818,26 @@ func MultichainTxHashCtx(next http.Handler) http.Handler {
 	})
 }
 
+// ChainTypePathCtx returns a middleware that embeds the value at the url part
+// {chaintype} into the request context, after checking that it is one of the
+// other chains (see dbtypes.MutilchainList) and that it is not disabled.
+// Unknown and disabled chains get the same 404 response, so that clients need
+// not distinguish a chain that is not supported from one that is switched off
+// with --disabledchain.
+func ChainTypePathCtx(disabledChains map[string]bool) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			chainType := chi.URLParam(r, "chaintype")
+			if !slices.Contains(dbtypes.MutilchainList, chainType) || disabledChains[chainType] {
+				http.Error(w, fmt.Sprintf("chain %q is not enabled", chainType), http.StatusNotFound)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ctxChainType, chainType)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // TransactionIOIndexCtx returns a http.HandlerFunc that embeds the value at the
 // url part {txinoutindex} into the request context
 func TransactionIOIndexCtx(next http.Handler) http.Handler {
@@ -1056,6 +1127,29 @@ func BlockHashLatestCtx(r *http.Request, source DataSource) context.Context {
 	return context.WithValue(ctx, ctxBlockHash, hash)
 }
 
+// ChainBlockHashCtx embeds the hash of the block of the chain set by
+// ChainTypePathCtx into a request context. The block is the one at the url part
+// {blockhash} or {idx} if set, and the best block otherwise. The hash is empty
+// if the block cannot be found.
+func ChainBlockHashCtx(r *http.Request, source ChainDataSource) context.Context {
+	chainType := GetChainTypeCtx(r)
+	hash := chi.URLParam(r, "blockhash")
+	if hash == "" {
+		var err error
+		if idxStr := chi.URLParam(r, "idx"); idxStr != "" {
+			idx, perr := strconv.ParseInt(idxStr, 10, 64)
+			if perr != nil || idx < 0 {
+				apiLog.Infof("No/invalid idx value (int64): %v", idxStr)
+			} else if hash, err = source.MutilchainBlockHashAtHeight(idx, chainType); err != nil {
+				apiLog.Errorf("Unable to get the hash of %s block %d: %v", chainType, idx, err)
+			}
+		} else if _, hash, err = source.MutilchainBestBlock(chainType); err != nil {
+			apiLog.Errorf("Unable to get the best %s block: %v", chainType, err)
+		}
+	}
+	return context.WithValue(r.Context(), ctxBlockHash, hash)
+}
+
 // StakeVersionLatestCtx embeds the specified StakeVersionsLatest function into
 // a request context.
 func StakeVersionLatestCtx(r *http.Request, stakeVerFun StakeVersionsLatest) context.Context {
@@ -131,3 +131,35 @@ func TestGetAddressCtx(t *testing.T) {
 		})
 	}
 }
+
+func TestChainTypePathCtx(t *testing.T) {
+	disabled := map[string]bool{"ltc": true}
+	tests := []struct {
+		chainType string
+		wantCode  int
+	}{
+		{"btc", http.StatusOK},
+		{"xmr", http.StatusOK},
+		{"ltc", http.StatusNotFound},
+		{"dcr", http.StatusNotFound},
+		{"doge", http.StatusNotFound},
+	}
+
+	r := chi.NewRouter()
+	r.With(ChainTypePathCtx(disabled)).Get("/chain/{chaintype}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(GetChainTypeCtx(r)))
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.chainType, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/chain/"+tt.chainType, nil))
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			if tt.wantCode == http.StatusOK && w.Body.String() != tt.chainType {
+				t.Errorf("expected chain type %q in context, got %q", tt.chainType, w.Body.String())
+			}
+		})
+	}
+}
@@ -17,8 +17,13 @@ import (
 	"github.com/jrick/logrotate/rotator"
 
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/api"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/electrum"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/esplora"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/graphql"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/insight"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/explorer"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/health"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/metrics"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
 	notify "github.com/decred/dcrdata/cmd/dcrdata/internal/notification"
 
@@ -36,6 +41,7 @@ import (
 	"github.com/decred/dcrdata/v8/pubsub"
 	"github.com/decred/dcrdata/v8/rpcutils"
 	"github.com/decred/dcrdata/v8/stakedb"
+	"github.com/decred/dcrdata/v8/webhook"
 )
 
 var (
@@ -54,6 +60,9 @@ var (
 	apiLog          slog.Logger
 	log             slog.Logger
 	iapiLog         slog.Logger
+	eapiLog         slog.Logger
+	electrumLog     slog.Logger
+	graphqlLog      slog.Logger
 	pubsubLog       slog.Logger
 	xcBotLog        slog.Logger
 	agendasLog      slog.Logger
@@ -62,6 +71,9 @@ var (
 	btcBlockdataLog slog.Logger
 	ltcBlockdataLog slog.Logger
 	xmrBlockdataLog slog.Logger
+	webhookLog      slog.Logger
+	metricsLog      slog.Logger
+	healthLog       slog.Logger
 	// filled after init so setLogLevels works
 	subsystemLoggers map[string]slog.Logger
 )
@@ -238,6 +250,9 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 	apiLog = backendLog.Logger("JAPI")
 	log = backendLog.Logger("DATD")
 	iapiLog = backendLog.Logger("IAPI")
+	eapiLog = backendLog.Logger("EAPI")
+	electrumLog = backendLog.Logger("ELEC")
+	graphqlLog = backendLog.Logger("GQL")
 	pubsubLog = backendLog.Logger("PUBS")
 	xcBotLog = backendLog.Logger("XBOT")
 	agendasLog = backendLog.Logger("AGDB")
@@ -246,11 +261,15 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 	btcBlockdataLog = backendLog.Logger("BTCBLKD")
 	ltcBlockdataLog = backendLog.Logger("LTCBLKD")
 	xmrBlockdataLog = backendLog.Logger("XMRBLKD")
+	webhookLog = backendLog.Logger("HOOK")
+	metricsLog = backendLog.Logger("METR")
+	healthLog = backendLog.Logger("HLTH")
 	all := []slog.Logger{
 		notifyLog, postgresqlLog, stakedbLog, BlockdataLog, clientLog,
-		mempoolLog, expLog, apiLog, log, iapiLog, pubsubLog,
-		xcBotLog, agendasLog, proposalsLog, externalLog, btcBlockdataLog,
-		ltcBlockdataLog, xmrBlockdataLog,
+		mempoolLog, expLog, apiLog, log, iapiLog, eapiLog, electrumLog,
+		graphqlLog, pubsubLog, xcBotLog, agendasLog, proposalsLog, externalLog,
+		btcBlockdataLog, ltcBlockdataLog, xmrBlockdataLog, webhookLog,
+		metricsLog, healthLog,
 	}
 	for _, lg := range all {
 		lg.SetLevel(slog.LevelDebug)
@@ -266,6 +285,9 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 	explorer.UseLogger(expLog)
 	api.UseLogger(apiLog)
 	insight.UseLogger(iapiLog)
+	esplora.UseLogger(eapiLog)
+	electrum.UseLogger(electrumLog)
+	graphql.UseLogger(graphqlLog)
 	middleware.UseLogger(apiLog)
 	notify.UseLogger(notifyLog)
 	pubsub.UseLogger(pubsubLog)
@@ -276,6 +298,9 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 	blockdatabtc.UseLogger(btcBlockdataLog)
 	blockdataltc.UseLogger(ltcBlockdataLog)
 	blockdataxmr.UseLogger(xmrBlockdataLog)
+	webhook.UseLogger(webhookLog)
+	metrics.UseLogger(metricsLog)
+	health.UseLogger(healthLog)
 
 	// Save map to use setLogLevels laters
 	subsystemLoggers = map[string]slog.Logger{
@@ -288,6 +313,9 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 		"EXPR":    expLog,
 		"JAPI":    apiLog,
 		"IAPI":    iapiLog,
+		"EAPI":    eapiLog,
+		"ELEC":    electrumLog,
+		"GQL":     graphqlLog,
 		"DATD":    log,
 		"PUBS":    pubsubLog,
 		"XBOT":    xcBotLog,
@@ -297,6 +325,9 @@ func initLogRotators(logPath, debugLogPath string, maxRolls int) {
 		"BTCBLKD": btcBlockdataLog,
 		"LTCBLKD": ltcBlockdataLog,
 		"XMRBLKD": xmrBlockdataLog,
+		"HOOK":    webhookLog,
+		"METR":    metricsLog,
+		"HLTH":    healthLog,
 	}
 }
 
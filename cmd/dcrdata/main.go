@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -39,6 +40,7 @@ import (
 	"github.com/decred/dcrdata/v8/db/cache"
 	"github.com/decred/dcrdata/v8/db/dbtypes"
 	"github.com/decred/dcrdata/v8/mempool"
+	"github.com/decred/dcrdata/v8/mempool/mempoolbtc"
 	"github.com/decred/dcrdata/v8/mempool/mempoolltc"
 	"github.com/decred/dcrdata/v8/mutilchain"
 	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
@@ -49,6 +51,7 @@ import (
 	"github.com/decred/dcrdata/v8/rpcutils"
 	"github.com/decred/dcrdata/v8/semver"
 	"github.com/decred/dcrdata/v8/stakedb"
+	"github.com/decred/dcrdata/v8/webhook"
 	"github.com/decred/dcrdata/v8/xmr/xmrclient"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -57,9 +60,14 @@ import (
 	ltcClient "github.com/ltcsuite/ltcd/rpcclient"
 
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/api"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/electrum"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/esplora"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/graphql"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/api/insight"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/chainsocket"
 	"github.com/decred/dcrdata/cmd/dcrdata/internal/explorer"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/health"
+	"github.com/decred/dcrdata/cmd/dcrdata/internal/metrics"
 	mw "github.com/decred/dcrdata/cmd/dcrdata/internal/middleware"
 	notify "github.com/decred/dcrdata/cmd/dcrdata/internal/notification"
 )
@@ -216,6 +224,29 @@ func _main(ctx context.Context) error {
 		SyncChainDBFlag:      cfg.SyncChainDB,
 		XmrSyncFlag:          cfg.XmrSyncDB,
 		OkLinkAPIKey:         cfg.OkLinkKey,
+		BulkBatchBlocks:      cfg.MultichainBulkBatch,
+		BTCRetention: dcrpg.RetentionPolicy{
+			Blocks:        cfg.BTCRetainBlocks,
+			Age:           cfg.BTCRetainAge,
+			AddressBlocks: cfg.BTCRetainAddressBlocks,
+			AddressAge:    cfg.BTCRetainAddressAge,
+		},
+		LTCRetention: dcrpg.RetentionPolicy{
+			Blocks:        cfg.LTCRetainBlocks,
+			Age:           cfg.LTCRetainAge,
+			AddressBlocks: cfg.LTCRetainAddressBlocks,
+			AddressAge:    cfg.LTCRetainAddressAge,
+		},
+		PruneBatchRows:       cfg.RetentionPruneBatch,
+		UtxoSnapshotInterval: cfg.UtxoSnapshotInterval,
+	}
+
+	// The metrics observe the PostgreSQL queries from the connection of the
+	// DB, and the sources of the other metrics are registered once they exist.
+	var mtr *metrics.Metrics
+	if cfg.EnableMetrics {
+		mtr = metrics.New()
+		dcrpg.UseQueryObserver(mtr.ObserveQuery)
 	}
 
 	mpChecker := rpcutils.NewMempoolAddressChecker(dcrdClient, activeChain)
@@ -607,10 +638,24 @@ func _main(ctx context.Context) error {
 			MasterBot:      cfg.RateMaster,
 			MasterCertFile: cfg.RateCertificate,
 			BinanceAPIURL:  cfg.BinanceAPI,
+			Aggregation: &exchanges.AggregationConfig{
+				MaxQuoteAge:  cfg.ExchangeMaxQuoteAge,
+				MADThreshold: cfg.ExchangeMADThreshold,
+				MinExchanges: cfg.ExchangeMinExchanges,
+			},
 		}
 		if cfg.DisabledExchanges != "" {
 			botCfg.Disabled = strings.Split(cfg.DisabledExchanges, ",")
 		}
+		// Keep the candlesticks and order books in the DB for the market
+		// charts.
+		if !cfg.NoMarketStore {
+			if err = chainDB.CheckAndCreateMarketTables(); err != nil {
+				return fmt.Errorf("check and create market tables failed: %w", err)
+			}
+			botCfg.Store = &marketStore{db: chainDB}
+			botCfg.DepthSnapshotInterval = cfg.MarketDepthInterval
+		}
 		xcBot, err = exchanges.NewExchangeBot(&botCfg)
 		if err != nil {
 			log.Errorf("Could not create exchange monitor. Exchange info will be disabled: %v", err)
@@ -726,6 +771,44 @@ func _main(ctx context.Context) error {
 	blockDataSavers = append(blockDataSavers, psHub)
 	mempoolSavers = append(mempoolSavers, psHub) // individual transactions are from mempool monitor
 
+	// The webhook subscriptions belong to API keys.
+	if err = chainDB.CheckAndCreateAPIKeyTables(); err != nil {
+		return fmt.Errorf("check and create API key tables failed: %w", err)
+	}
+
+	// The webhook dispatcher derives its events from the same hub signals as
+	// the pubsubhub, after the blocks are stored by the ChainDB.
+	var webhooks *webhook.Dispatcher
+	if cfg.EnableWebhooks {
+		if err = chainDB.CheckAndCreateWebhookTables(); err != nil {
+			return fmt.Errorf("check and create webhook tables failed: %w", err)
+		}
+		whCfg := &webhook.Config{
+			Store:  chainDB,
+			Params: activeChain,
+		}
+		for _, cidr := range cfg.WebhookAllowedNets {
+			_, allowed, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("invalid webhook-allownet %q: %w", cidr, err)
+			}
+			whCfg.AllowedNets = append(whCfg.AllowedNets, allowed)
+		}
+		if !btcDisabled {
+			whCfg.BTCParams = btcActiveChain
+		}
+		if !ltcDisabled {
+			whCfg.LTCParams = ltcActiveChain
+		}
+		webhooks, err = webhook.NewDispatcher(whCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook dispatcher: %w", err)
+		}
+		blockDataSavers = append(blockDataSavers, webhooks)
+		wg.Add(1)
+		go webhooks.Run(ctx, &wg)
+	}
+
 	// Store explorerUI data after pubsubhub.
 	blockDataSavers = append(blockDataSavers, explore)
 	mempoolSavers = append(mempoolSavers, explore)
@@ -750,6 +833,9 @@ func _main(ctx context.Context) error {
 	signalToPSHub := psHub.HubRelay()
 	signalToExplorer := explore.MempoolSignal()
 	mempoolSigOuts := []chan<- pstypes.HubMessage{signalToPSHub, signalToExplorer}
+	if webhooks != nil {
+		mempoolSigOuts = append(mempoolSigOuts, webhooks.Relay())
+	}
 	mpm, err := mempool.NewMempoolMonitor(ctx, mpoolCollector, mempoolSavers,
 		activeChain, mempoolSigOuts, true)
 
@@ -850,6 +936,166 @@ func _main(ctx context.Context) error {
 	defer insightSocketServer.Close()
 	blockDataSavers = append(blockDataSavers, insightSocketServer)
 
+	// Create the Insight socket.io servers of the enabled BTC and LTC chains.
+	// Their tx and block handlers are registered with the chain notifiers.
+	var btcInsightSocketServer, ltcInsightSocketServer *insight.ChainSocketServer
+	if !btcDisabled {
+		btcInsightSocketServer, err = insight.NewChainSocketServer(mutilchain.TYPEBTC, chainDB)
+		if err != nil {
+			return fmt.Errorf("Could not create BTC Insight socket.io server: %v", err)
+		}
+		defer btcInsightSocketServer.Close()
+	}
+	if !ltcDisabled {
+		ltcInsightSocketServer, err = insight.NewChainSocketServer(mutilchain.TYPELTC, chainDB)
+		if err != nil {
+			return fmt.Errorf("Could not create LTC Insight socket.io server: %v", err)
+		}
+		defer ltcInsightSocketServer.Close()
+	}
+
+	// Create the Electrum protocol servers of the enabled BTC and LTC chains
+	// with a listen address, which require syncchaindb. They listen once the
+	// whole chain is synced, and their tx and block handlers are registered
+	// with the chain notifiers.
+	var btcElectrumServer, ltcElectrumServer *electrum.Server
+	if !btcDisabled && (cfg.BTCElectrumListen != "" || cfg.BTCElectrumTLSListen != "") {
+		btcElectrumServer = electrum.NewServer(mutilchain.TYPEBTC, chainDB, "dcrdata "+Version())
+	}
+	if !ltcDisabled && (cfg.LTCElectrumListen != "" || cfg.LTCElectrumTLSListen != "") {
+		ltcElectrumServer = electrum.NewServer(mutilchain.TYPELTC, chainDB, "dcrdata "+Version())
+	}
+
+	// Check the health of the enabled chains for /api/status/{chain} and
+	// /api/status/ready. The mempool monitors of the other chains are set
+	// when they start.
+	healthCfg := &health.Config{
+		Thresholds: health.Thresholds{
+			MaxHeightLag:     int64(cfg.HealthMaxLag),
+			BlockAgeSpacings: cfg.HealthBlockAge,
+			MaxMempoolAge:    cfg.HealthMempoolAge,
+			MaxExchangeAge:   cfg.HealthExchangeAge,
+			FallbackWindow:   cfg.HealthFallbackWindow,
+		},
+	}
+	if xcBot != nil {
+		healthCfg.Exchanges = xcBot
+	}
+	mutilchainBestBlock := func(chainType string) func() (int64, time.Time, error) {
+		return func() (int64, time.Time, error) {
+			height, _, err := chainDB.MutilchainBestBlockHeightHash(chainType)
+			if err != nil {
+				return 0, time.Time{}, err
+			}
+			var blockTime time.Time
+			if t := chainDB.MutilchainBestBlockTime(chainType); t > 0 {
+				blockTime = time.Unix(t, 0)
+			}
+			return height, blockTime, nil
+		}
+	}
+	if !dcrDisabled {
+		healthCfg.Chains = append(healthCfg.Chains, &health.Chain{
+			Name:          mutilchain.TYPEDCR,
+			TargetSpacing: activeChain.TargetTimePerBlock,
+			Node: func(ctx context.Context) (*health.NodeStatus, error) {
+				conns, err := dcrdClient.GetConnectionCount(ctx)
+				if err != nil {
+					return nil, err
+				}
+				height, err := dcrdClient.GetBlockCount(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return &health.NodeStatus{Connections: conns, Height: height}, nil
+			},
+			BestBlock: func() (int64, time.Time, error) {
+				summary := chainDB.GetBestBlockSummary()
+				if summary == nil {
+					return 0, time.Time{}, fmt.Errorf("no best block")
+				}
+				return int64(summary.Height), summary.Time.S.T, nil
+			},
+			Markets: []string{"index", exchanges.TYPEDCR},
+		})
+	}
+	if !btcDisabled {
+		healthCfg.Chains = append(healthCfg.Chains, &health.Chain{
+			Name:          mutilchain.TYPEBTC,
+			TargetSpacing: btcActiveChain.TargetTimePerBlock,
+			Node: func(context.Context) (*health.NodeStatus, error) {
+				conns, err := btcdClient.GetConnectionCount()
+				if err != nil {
+					return nil, err
+				}
+				height, err := btcdClient.GetBlockCount()
+				if err != nil {
+					return nil, err
+				}
+				return &health.NodeStatus{Connections: conns, Height: height}, nil
+			},
+			BestBlock: mutilchainBestBlock(mutilchain.TYPEBTC),
+			Markets:   []string{exchanges.TYPEBTC},
+		})
+	}
+	if !ltcDisabled {
+		healthCfg.Chains = append(healthCfg.Chains, &health.Chain{
+			Name:          mutilchain.TYPELTC,
+			TargetSpacing: ltcActiveChain.TargetTimePerBlock,
+			Node: func(context.Context) (*health.NodeStatus, error) {
+				conns, err := ltcdClient.GetConnectionCount()
+				if err != nil {
+					return nil, err
+				}
+				height, err := ltcdClient.GetBlockCount()
+				if err != nil {
+					return nil, err
+				}
+				return &health.NodeStatus{Connections: conns, Height: height}, nil
+			},
+			BestBlock: mutilchainBestBlock(mutilchain.TYPELTC),
+			Markets:   []string{exchanges.TYPELTC},
+		})
+	}
+	if !xmrDisabled {
+		healthCfg.Chains = append(healthCfg.Chains, &health.Chain{
+			Name:          mutilchain.TYPEXMR,
+			TargetSpacing: 2 * time.Minute, // DIFFICULTY_TARGET_V2 of monerod
+			Node: func(context.Context) (*health.NodeStatus, error) {
+				info, err := xmrClient.GetInfo()
+				if err != nil {
+					return nil, err
+				}
+				header, err := xmrClient.GetLastBlockHeader()
+				if err != nil {
+					return nil, err
+				}
+				return &health.NodeStatus{
+					Connections: int64(info.IncomingConnections + info.OutgoingConnections),
+					Height:      int64(header.Height),
+				}, nil
+			},
+			BestBlock: mutilchainBestBlock(mutilchain.TYPEXMR),
+			Markets:   []string{exchanges.TYPEXMR},
+		})
+	}
+	healthMonitor := health.NewMonitor(healthCfg)
+	healthMonitor.SetMempool(mutilchain.TYPEDCR, mpm)
+	wg.Add(1)
+	go healthMonitor.Run(ctx, &wg)
+
+	// The GraphQL API over the same data as the JSON API.
+	gqlServer, err := graphql.NewServer(&graphql.Config{
+		DataSource:    chainDB,
+		ProposalsDB:   proposalsDB,
+		Hub:           psHub,
+		Params:        activeChain,
+		ChainDisabled: chainDisabledMap,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the GraphQL server: %w", err)
+	}
+
 	// Start dcrdata's JSON web API.
 	app := api.NewContext(&api.AppContextConfig{
 		Client:            dcrdClient,
@@ -861,9 +1107,15 @@ func _main(ctx context.Context) error {
 		AgendasDBInstance: agendaDB,
 		ProposalsDB:       proposalsDB,
 		MaxAddrs:          cfg.MaxCSVAddrs,
+		MaxExportRows:     int64(cfg.MaxExportRows),
+		MaxExports:        cfg.ConcurrentExports,
 		Charts:            charts,
 		ChainDisabledMap:  chainDisabledMap,
 		CoinCaps:          coinCaps,
+		Webhooks:          webhooks,
+		EventStream:       psHub.SSEHandler,
+		GraphQL:           gqlServer,
+		Health:            healthMonitor,
 	})
 	getMarketCapData := func() {
 		//get coin cap data from extenal api
@@ -898,14 +1150,112 @@ func _main(ctx context.Context) error {
 		chainDB.SignalHeight(uint32(chainDBHeight))
 	}
 
+	// Requests made with an API key have the rate limit and daily quota of
+	// their key instead of the IP based limits. The usage counts of the keys
+	// are stored periodically. Requests with a key that is not known to be
+	// valid are limited by IP before the key is looked up.
+	keyLookupLimiter := mw.NewLimiter(1)
+	keyLookupLimiter.SetBurst(10)
+	keyLookupLimiter.SetMessage("You have reached the maximum API key lookup limit (1 req/s)")
+	if cfg.UseRealIP {
+		keyLookupLimiter.SetIPLookups([]string{"RemoteAddr"})
+	} else {
+		keyLookupLimiter.SetIPLookups([]string{"X-Forwarded-For", "X-Real-IP", "RemoteAddr"})
+	}
+	apiKeys := mw.NewAPIKeys(chainDB, keyLookupLimiter)
+	wg.Add(1)
+	go apiKeys.Run(ctx, &wg, 30*time.Second)
+
 	// Configure the URL path to http handler router for the API.
 	apiMux := api.NewAPIRouter(app, cfg.IndentJSON, cfg.UseRealIP, cfg.CompressAPI)
 
 	// File downloads piggy-back on the API.
 	fileMux := api.NewFileRouter(app, cfg.UseRealIP)
 
+	if mtr != nil {
+		mtrCfg := &metrics.Config{
+			APICache:     chainDB.BlockCache,
+			AddressCache: chainDB.AddressCache,
+			PubSub:       psHub.WsHub,
+		}
+		if xcBot != nil {
+			mtrCfg.Exchanges = xcBot
+		}
+		if !dcrDisabled {
+			mtrCfg.Chains = append(mtrCfg.Chains, &metrics.Chain{
+				Name:       mutilchain.TYPEDCR,
+				NodeHeight: dcrdClient.GetBlockCount,
+				DBHeight:   chainDB.Height,
+				Charts:     func() metrics.ChartCache { return charts },
+			})
+		}
+		// The chart caches of the other chains are created after their sync.
+		mutilchainCharts := func(chainType string) func() metrics.ChartCache {
+			return func() metrics.ChartCache {
+				var charts *cache.MutilchainChartData
+				switch chainType {
+				case mutilchain.TYPEBTC:
+					charts = psHub.BtcCharts
+				case mutilchain.TYPELTC:
+					charts = psHub.LtcCharts
+				case mutilchain.TYPEXMR:
+					charts = psHub.XmrCharts
+				}
+				if charts == nil {
+					return nil
+				}
+				return charts
+			}
+		}
+		mutilchainDBHeight := func(chainType string) func() int64 {
+			return func() int64 {
+				height, _ := chainDB.GetMutilchainBestBlock(chainType)
+				return height
+			}
+		}
+		if !btcDisabled {
+			mtrCfg.Chains = append(mtrCfg.Chains, &metrics.Chain{
+				Name:       mutilchain.TYPEBTC,
+				NodeHeight: func(context.Context) (int64, error) { return btcdClient.GetBlockCount() },
+				DBHeight:   mutilchainDBHeight(mutilchain.TYPEBTC),
+				Charts:     mutilchainCharts(mutilchain.TYPEBTC),
+			})
+		}
+		if !ltcDisabled {
+			mtrCfg.Chains = append(mtrCfg.Chains, &metrics.Chain{
+				Name:       mutilchain.TYPELTC,
+				NodeHeight: func(context.Context) (int64, error) { return ltcdClient.GetBlockCount() },
+				DBHeight:   mutilchainDBHeight(mutilchain.TYPELTC),
+				Charts:     mutilchainCharts(mutilchain.TYPELTC),
+			})
+		}
+		if !xmrDisabled {
+			mtrCfg.Chains = append(mtrCfg.Chains, &metrics.Chain{
+				Name: mutilchain.TYPEXMR,
+				NodeHeight: func(context.Context) (int64, error) {
+					info, err := xmrClient.GetInfo()
+					if err != nil {
+						return 0, err
+					}
+					return int64(info.Height), nil
+				},
+				DBHeight: mutilchainDBHeight(mutilchain.TYPEXMR),
+				Charts:   mutilchainCharts(mutilchain.TYPEXMR),
+			})
+		}
+		if err = mtr.Register(mtrCfg); err != nil {
+			return fmt.Errorf("failed to register metrics: %w", err)
+		}
+		wg.Add(1)
+		go mtr.Run(ctx, &wg)
+	}
+
 	// Configure the explorer web pages router.
 	webMux := chi.NewRouter()
+	if mtr != nil {
+		// Before the other middleware, so that all the requests are timed.
+		webMux.Use(mtr.Middleware)
+	}
 	if cfg.ServerHeader != "" {
 		log.Debugf("Using Server HTTP response header %q", cfg.ServerHeader)
 		webMux.Use(mw.Server(cfg.ServerHeader))
@@ -940,6 +1290,9 @@ func _main(ctx context.Context) error {
 	})
 	webMux.Get("/ws", explore.RootWebsocket)
 	webMux.Get("/ps", psHub.WebSocketHandler)
+	if mtr != nil {
+		webMux.Get("/metrics", mtr.Handler().ServeHTTP)
+	}
 
 	// Make the static assets available under a path with the given prefix.
 	mountAssetPaths := func(pathPrefix string) {
@@ -972,7 +1325,7 @@ func _main(ctx context.Context) error {
 
 	// SyncStatusAPIIntercept returns a json response if the sync status page is
 	// enabled (no the full explorer while syncing).
-	webMux.With(explore.SyncStatusAPIIntercept).Group(func(r chi.Router) {
+	webMux.With(explore.SyncStatusAPIIntercept, apiKeys.Middleware).Group(func(r chi.Router) {
 		// Mount the dcrdata's REST API.
 		r.Mount("/api", apiMux.Mux)
 		// Setup and mount the Insight API.
@@ -982,14 +1335,44 @@ func _main(ctx context.Context) error {
 		insightMux := insight.NewInsightAPIRouter(insightApp, cfg.UseRealIP,
 			cfg.CompressAPI, cfg.MaxCSVAddrs)
 		r.Mount("/insight/api", insightMux.Mux)
+		apiMux.DocumentRouter("/insight/api", "insight", insightMux.Mux)
 
 		if insightSocketServer != nil {
 			r.With(mw.NoOrigin).Get("/insight/socket.io/", insightSocketServer.ServeHTTP)
 		}
+
+		// Setup and mount the Insight and Esplora APIs of the BTC and LTC
+		// chains.
+		chainInsightSockets := map[string]*insight.ChainSocketServer{
+			mutilchain.TYPEBTC: btcInsightSocketServer,
+			mutilchain.TYPELTC: ltcInsightSocketServer,
+		}
+		for _, chainType := range []string{mutilchain.TYPEBTC, mutilchain.TYPELTC} {
+			if chainDB.ChainDisabledMap[chainType] {
+				continue
+			}
+			chainInsightApp := insight.NewChainInsightAPI(chainType, chainDB, cfg.IndentJSON)
+			chainInsightApp.SetReqRateLimit(cfg.InsightReqRateLimit)
+			chainInsightMux := insight.NewChainInsightAPIRouter(chainInsightApp, cfg.UseRealIP,
+				cfg.CompressAPI, cfg.MaxCSVAddrs)
+			prefix := "/insight/" + chainType
+			r.Mount(prefix+"/api", chainInsightMux.Mux)
+			apiMux.DocumentRouter(prefix+"/api", "insight-"+chainType, chainInsightMux.Mux)
+			if socketServer := chainInsightSockets[chainType]; socketServer != nil {
+				r.With(mw.NoOrigin).Get(prefix+"/socket.io/", socketServer.ServeHTTP)
+			}
+
+			// Setup and mount the Esplora API of the chain.
+			esploraApp := esplora.NewEsploraAPI(chainType, chainDB, cfg.IndentJSON)
+			esploraApp.SetReqRateLimit(cfg.EsploraReqRateLimit)
+			esploraMux := esplora.NewEsploraAPIRouter(esploraApp, cfg.UseRealIP, cfg.CompressAPI)
+			r.Mount("/esplora/"+chainType+"/api", esploraMux.Mux)
+			apiMux.DocumentRouter("/esplora/"+chainType+"/api", "esplora-"+chainType, esploraMux.Mux)
+		}
 	})
 
 	// HTTP Error 503 StatusServiceUnavailable for file requests before sync.
-	webMux.With(explore.SyncStatusFileIntercept).Group(func(r chi.Router) {
+	webMux.With(explore.SyncStatusFileIntercept, apiKeys.Middleware).Group(func(r chi.Router) {
 		r.Mount("/download", fileMux.Mux)
 	})
 
@@ -1042,6 +1425,7 @@ func _main(ctx context.Context) error {
 			rd.Get("/finance-report/detail", explore.FinanceDetailPage)
 			rd.Get("/supply", explore.SupplyPage)
 			rd.Get("/atomic-swaps", explore.AtomicSwapsPage)
+			rd.Get("/mixes", explore.MixesPage)
 		})
 		mainRedirect := func(url string) http.HandlerFunc {
 			return func(w http.ResponseWriter, r *http.Request) {
@@ -1126,6 +1510,7 @@ func _main(ctx context.Context) error {
 		r.Get("/finance-report/detail", mainRedirect("/decred/finance-report/detail"))
 		r.Get("/supply", mainRedirect("/decred/supply"))
 		r.Get("/atomic-swaps", mainRedirect("/decred/atomic-swaps"))
+		r.Get("/mixes", mainRedirect("/decred/mixes"))
 		r.Get("/about", explore.AboutPage)
 		r.Get("/whatsnew", explore.WhatsNewPage)
 		// MenuFormParser will typically redirect, but going to the homepage as a
@@ -1164,6 +1549,37 @@ func _main(ctx context.Context) error {
 	// Start the web server.
 	listenAndServeProto(ctx, &wg, cfg.APIListen, cfg.APIProto, webMux)
 
+	// Start the Electrum protocol servers.
+	if btcElectrumServer != nil || ltcElectrumServer != nil {
+		var tlsConfig *tls.Config
+		if cfg.BTCElectrumTLSListen != "" || cfg.LTCElectrumTLSListen != "" {
+			cert, err := tls.LoadX509KeyPair(cfg.ElectrumTLSCert, cfg.ElectrumTLSKey)
+			if err != nil {
+				return fmt.Errorf("Failed to load the Electrum TLS certificate and key: %w", err)
+			}
+			tlsConfig = &tls.Config{
+				Certificates: []tls.Certificate{cert},
+				MinVersion:   tls.VersionTLS12,
+			}
+		}
+		electrumListeners := []struct {
+			srv       *electrum.Server
+			listen    string
+			tlsConfig *tls.Config
+		}{
+			{btcElectrumServer, cfg.BTCElectrumListen, nil},
+			{btcElectrumServer, cfg.BTCElectrumTLSListen, tlsConfig},
+			{ltcElectrumServer, cfg.LTCElectrumListen, nil},
+			{ltcElectrumServer, cfg.LTCElectrumTLSListen, tlsConfig},
+		}
+		for _, l := range electrumListeners {
+			if l.srv == nil || l.listen == "" {
+				continue
+			}
+			listenAndServeElectrum(ctx, &wg, l.srv, l.listen, l.tlsConfig)
+		}
+	}
+
 	// Last chance to quit before syncing if the web server could not start.
 	if shutdownRequested(ctx) {
 		return nil
@@ -1423,9 +1839,60 @@ func _main(ctx context.Context) error {
 			return err
 		}
 		log.Infof("Finish checking and syncing coin age tables...")
+
+		// sync mix statistics table
+		err = chainDB.CheckAndCreateMixStatsTable()
+		if err != nil {
+			return fmt.Errorf("check and create mix_stats table failed: %v", err)
+		}
+		err = chainDB.SyncMixStatsTable()
+		if err != nil {
+			return fmt.Errorf("sync mix_stats table failed: %v", err)
+		}
+
+		// The UTXO set statistics catch up in the background, and then
+		// follow the new blocks.
+		if err = chainDB.CheckAndCreateUtxoSetTables(); err != nil {
+			return fmt.Errorf("check and create UTXO set tables failed: %w", err)
+		}
+		go func() {
+			if err := chainDB.SyncUtxoSet(mutilchain.TYPEDCR); err != nil {
+				log.Errorf("Sync UTXO set failed: %v", err)
+			}
+		}()
 	}
 	log.Debugf("Start sync btc/ltc tx count")
 	go chainDB.SyncMultichainMetaInfo(btcDisabled, ltcDisabled)
+
+	// Backfill the daily fiat prices of the coins for the valuation of the
+	// transactions and addresses at block time, and keep them up to date.
+	if !cfg.NoFiatHistory {
+		if err = chainDB.CheckAndCreateDailyPriceTable(); err != nil {
+			return fmt.Errorf("check and create daily_price table failed: %w", err)
+		}
+		var fiatChains []string
+		for _, chainType := range []string{mutilchain.TYPEDCR, mutilchain.TYPEBTC, mutilchain.TYPELTC, mutilchain.TYPEXMR} {
+			if !chainDisabledMap[chainType] {
+				fiatChains = append(fiatChains, chainType)
+			}
+		}
+		fiatCurrencies := strings.Split(cfg.FiatHistoryCurrencies, ",")
+		go func() {
+			ticker := time.NewTicker(24 * time.Hour)
+			defer ticker.Stop()
+			for {
+				log.Infof("Starting daily fiat price sync...")
+				if err := chainDB.SyncDailyPrices(ctx, fiatChains, fiatCurrencies); err != nil {
+					log.Errorf("Sync daily fiat prices failed: %v", err)
+				}
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
 	// After sync and indexing, must use upsert statement, which checks for
 	// duplicate entries and updates instead of erroring. SyncChainDB should
 	// set this on successful sync, but do it again anyway.
@@ -1603,6 +2070,10 @@ func _main(ctx context.Context) error {
 			if err != nil {
 				return err
 			}
+			err = chainDB.SyncMixStatsTable()
+			if err != nil {
+				return fmt.Errorf("sync mix_stats table failed: %v", err)
+			}
 		}
 	}
 
@@ -1801,6 +2272,7 @@ func _main(ctx context.Context) error {
 			return fmt.Errorf("XMR RPC client error: %v", cerr)
 		}
 		go explore.UpdateXMRMempoolData(xmrClient, make(chan struct{}))
+		healthMonitor.SetMempool(mutilchain.TYPEXMR, health.MempoolFunc(explore.XMRMempoolUpdateTime))
 	}
 
 	// handler syncing for XMR blockchain on background
@@ -1816,6 +2288,7 @@ func _main(ctx context.Context) error {
 			return fmt.Errorf("Check and create table for blockchain %s errors: %w", mutilchain.TYPELTC, checkErr)
 		}
 		//first, use external socket api to get mempool info
+		var ltcMpm *mempoolltc.MempoolMonitor
 		mainSocket, err := chainsocket.NewMutilchainInfoSocket(explore, mutilchain.TYPELTC)
 		if err == nil {
 			err = mainSocket.StartMempoolConnectAndUpdate()
@@ -1834,8 +2307,8 @@ func _main(ctx context.Context) error {
 					return fmt.Errorf("Failed to create LTC mempool data collector")
 				}
 
-				mpm, err := mempoolltc.NewMempoolMonitor(ctx, ltcMpoolCollector, ltcMempoolSavers,
-					ltcActiveChain, true)
+				ltcMpm, err = mempoolltc.NewMempoolMonitor(ctx, ltcMpoolCollector, ltcMempoolSavers,
+					ltcActiveChain, []chan<- pstypes.HubMessage{signalToPSHub}, true)
 
 				// Ensure the initial collect/store succeeded.
 				if err != nil {
@@ -1845,7 +2318,8 @@ func _main(ctx context.Context) error {
 				}
 
 				// Use the MempoolMonitor in DB to get unconfirmed transaction data.
-				chainDB.UseLTCMempoolChecker(mpm)
+				chainDB.UseLTCMempoolChecker(ltcMpm)
+				healthMonitor.SetMempool(mutilchain.TYPELTC, ltcMpm)
 			}
 		}
 
@@ -1959,6 +2433,9 @@ func _main(ctx context.Context) error {
 		ltcBlockDataSavers := []blockdataltc.BlockDataSaver{}
 		ltcBlockDataSavers = append(ltcBlockDataSavers, chainDB)
 		ltcBlockDataSavers = append(ltcBlockDataSavers, psHub)
+		if webhooks != nil {
+			ltcBlockDataSavers = append(ltcBlockDataSavers, webhooks)
+		}
 		ltcBlockDataSavers = append(ltcBlockDataSavers, explore)
 		// Add charts saver method after explorer and database stores. This may run
 		// asynchronously.
@@ -1972,6 +2449,22 @@ func _main(ctx context.Context) error {
 			ltcReorgBlockDataSavers)
 
 		ltcNotifier.RegisterBlockHandlerGroup(ltcBdChainMonitor.ConnectBlock)
+		// Relay new blocks and mempool transactions to the LTC Insight
+		// socket.io clients, and keep the mempool monitor, if any, current.
+		ltcNotifier.RegisterBlockHandlerGroup(ltcInsightSocketServer.LTCBlockHandler)
+		ltcTxHandlers := []notify.LtcTxHandler{ltcInsightSocketServer.SendLTCTx}
+		if ltcMpm != nil {
+			ltcNotifier.RegisterBlockHandlerGroup(func(*mutilchain.LtcBlockHeader) error {
+				return ltcMpm.CollectAndStore()
+			})
+			ltcTxHandlers = append(ltcTxHandlers, ltcMpm.TxHandler)
+		}
+		ltcNotifier.RegisterTxHandlerGroup(ltcTxHandlers...)
+		// Update the Electrum sessions after the mempool monitor, if any.
+		if ltcElectrumServer != nil {
+			ltcNotifier.RegisterBlockHandlerGroup(ltcElectrumServer.LTCBlockHandler)
+			ltcNotifier.RegisterTxHandlerGroup(ltcElectrumServer.LTCTxHandler)
+		}
 		// Register for notifications from dcrd. This also sets the daemon RPC
 		// client used by other functions in the notify/notification package (i.e.
 		// common ancestor identification in processReorg).
@@ -1991,12 +2484,39 @@ func _main(ctx context.Context) error {
 		}
 
 		//first, use external socket api to get mempool info
+		var btcMpm *mempoolbtc.MempoolMonitor
 		mainSocket, err := chainsocket.NewMutilchainInfoSocket(explore, mutilchain.TYPEBTC)
 		if err == nil {
 			err = mainSocket.StartMempoolConnectAndUpdate()
 		}
 		if err != nil {
 			log.Infof("Create external API socket failed. Start initialize mempool data with Mempool collector")
+			if !chainDB.ChainDBDisabled {
+				//handler mempool with Mempool monitor
+				btcMempoolSavers := []mempoolbtc.MempoolDataSaver{chainDB.BTCMPC}
+				btcMempoolSavers = append(btcMempoolSavers, explore)
+				// Create the mempool data collector.
+				btcMpoolCollector := mempoolbtc.NewDataCollector(btcdClient, btcActiveChain)
+				if btcMpoolCollector == nil {
+					// Shutdown goroutines.
+					requestShutdown()
+					return fmt.Errorf("Failed to create BTC mempool data collector")
+				}
+
+				btcMpm, err = mempoolbtc.NewMempoolMonitor(ctx, btcMpoolCollector, btcMempoolSavers,
+					btcActiveChain, []chan<- pstypes.HubMessage{signalToPSHub}, true)
+
+				// Ensure the initial collect/store succeeded.
+				if err != nil {
+					// Shutdown goroutines.
+					requestShutdown()
+					return fmt.Errorf("NewMempoolMonitor: %v", err)
+				}
+
+				// Use the MempoolMonitor in DB to get unconfirmed transaction data.
+				chainDB.UseBTCMempoolChecker(btcMpm)
+				healthMonitor.SetMempool(mutilchain.TYPEBTC, btcMpm)
+			}
 		}
 
 		//Start - BTC Sync handler
@@ -2109,6 +2629,9 @@ func _main(ctx context.Context) error {
 		btcBlockDataSavers := []blockdatabtc.BlockDataSaver{}
 		btcBlockDataSavers = append(btcBlockDataSavers, chainDB)
 		btcBlockDataSavers = append(btcBlockDataSavers, psHub)
+		if webhooks != nil {
+			btcBlockDataSavers = append(btcBlockDataSavers, webhooks)
+		}
 		btcBlockDataSavers = append(btcBlockDataSavers, explore)
 		// Add charts saver method after explorer and database stores. This may run
 		// asynchronously.
@@ -2123,6 +2646,22 @@ func _main(ctx context.Context) error {
 			btcReorgBlockDataSavers)
 
 		btcNotifier.RegisterBlockHandlerGroup(btcBdChainMonitor.ConnectBlock)
+		// Relay new blocks and mempool transactions to the BTC Insight
+		// socket.io clients, and keep the mempool monitor, if any, current.
+		btcNotifier.RegisterBlockHandlerGroup(btcInsightSocketServer.BTCBlockHandler)
+		btcTxHandlers := []notify.BtcTxHandler{btcInsightSocketServer.SendBTCTx}
+		if btcMpm != nil {
+			btcNotifier.RegisterBlockHandlerGroup(func(*mutilchain.BtcBlockHeader) error {
+				return btcMpm.CollectAndStore()
+			})
+			btcTxHandlers = append(btcTxHandlers, btcMpm.TxHandler)
+		}
+		btcNotifier.RegisterTxHandlerGroup(btcTxHandlers...)
+		// Update the Electrum sessions after the mempool monitor, if any.
+		if btcElectrumServer != nil {
+			btcNotifier.RegisterBlockHandlerGroup(btcElectrumServer.BTCBlockHandler)
+			btcNotifier.RegisterTxHandlerGroup(btcElectrumServer.BTCTxHandler)
+		}
 		// Register for notifications from dcrd. This also sets the daemon RPC
 		// client used by other functions in the notify/notification package (i.e.
 		// common ancestor identification in processReorg).
@@ -2137,13 +2676,22 @@ func _main(ctx context.Context) error {
 	// check and sync atomic swap for Decred before sync for btc, ltc
 	chainDB.SyncDecredAtomicSwap()
 
+	// Prune the BTC and LTC tables to their retention windows in the
+	// background, unless the whole chains are synced.
+	if !btcDisabled {
+		go chainDB.RunRetentionPruner(mutilchain.TYPEBTC)
+	}
+	if !ltcDisabled {
+		go chainDB.RunRetentionPruner(mutilchain.TYPELTC)
+	}
+
 	go func() {
 		if chainDB.ChainDBDisabled && !btcDisabled {
-			err = chainDB.SyncLast20BTCBlocks(btcHeight)
+			err = chainDB.SyncRecentBTCBlocks(btcHeight)
 			if err != nil {
 				log.Error(err)
 			} else {
-				log.Infof("Sync last 20 BTC Blocks successfully")
+				log.Infof("Sync recent BTC blocks successfully")
 			}
 			// handler older btc blocks height
 			// check oldest block height
@@ -2168,13 +2716,13 @@ func _main(ctx context.Context) error {
 	}()
 
 	go func() {
-		//sync last 20 blocks
+		//sync recent blocks
 		if chainDB.ChainDBDisabled && !ltcDisabled {
-			err = chainDB.SyncLast20LTCBlocks(ltcHeight)
+			err = chainDB.SyncRecentLTCBlocks(ltcHeight)
 			if err != nil {
 				log.Error(err)
 			} else {
-				log.Infof("Sync last 20 LTC Blocks successfully")
+				log.Infof("Sync recent LTC blocks successfully")
 			}
 			// handler older ltc blocks height
 			// check oldest block height
@@ -2376,6 +2924,36 @@ func listenAndServeProto(ctx context.Context, wg *sync.WaitGroup, listen, proto
 	time.Sleep(250 * time.Millisecond)
 }
 
+// listenAndServeElectrum starts serving an Electrum protocol server on the
+// listen address, with TLS if tlsConfig is not nil, once the whole-chain
+// tables of its chain are synced. The server stops when the context is
+// canceled.
+func listenAndServeElectrum(ctx context.Context, wg *sync.WaitGroup, srv *electrum.Server, listen string, tlsConfig *tls.Config) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if srv.WaitWholeChainSynced(ctx) != nil {
+			return
+		}
+		var ln net.Listener
+		var err error
+		if tlsConfig != nil {
+			ln, err = tls.Listen("tcp", listen, tlsConfig)
+		} else {
+			ln, err = net.Listen("tcp", listen)
+		}
+		if err != nil {
+			log.Errorf("Failed to start the Electrum server on %s: %v", listen, err)
+			requestShutdown()
+			return
+		}
+		if err = srv.Serve(ctx, ln); err != nil {
+			log.Errorf("Electrum server on %s failed: %v", listen, err)
+			requestShutdown()
+		}
+	}()
+}
+
 // FileServer conveniently sets up a http.FileServer handler to serve static
 // files from path on the file system. Directory listings are denied, as are URL
 // paths containing "..".
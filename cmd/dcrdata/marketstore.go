@@ -0,0 +1,94 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/exchanges/v3"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// marketStore stores the candlesticks and order book snapshots of the
+// ExchangeBot in the market tables of the ChainDB. It satisfies
+// exchanges.MarketStore.
+type marketStore struct {
+	db *dcrpg.ChainDB
+}
+
+func (s *marketStore) StoreCandlesticks(chainType, token, bin string, sticks exchanges.Candlesticks) error {
+	candles := make([]*dbtypes.MarketCandle, 0, len(sticks))
+	for _, stick := range sticks {
+		candles = append(candles, &dbtypes.MarketCandle{
+			Start:  stick.Start,
+			Open:   stick.Open,
+			High:   stick.High,
+			Low:    stick.Low,
+			Close:  stick.Close,
+			Volume: stick.Volume,
+		})
+	}
+	return s.db.StoreMarketCandles(chainType, token, bin, candles)
+}
+
+func (s *marketStore) Candlesticks(chainType, token, bin string, limit int) (exchanges.Candlesticks, error) {
+	candles, err := s.db.MarketCandles(chainType, token, bin, limit)
+	if err != nil {
+		return nil, err
+	}
+	sticks := make(exchanges.Candlesticks, 0, len(candles))
+	for _, c := range candles {
+		sticks = append(sticks, exchanges.Candlestick{
+			High:   c.High,
+			Low:    c.Low,
+			Open:   c.Open,
+			Close:  c.Close,
+			Volume: c.Volume,
+			Start:  c.Start,
+		})
+	}
+	return sticks, nil
+}
+
+func (s *marketStore) StoreDepthSnapshot(chainType, token string, snapshot *exchanges.DepthSnapshot) error {
+	bids, err := json.Marshal(snapshot.Bids)
+	if err != nil {
+		return err
+	}
+	asks, err := json.Marshal(snapshot.Asks)
+	if err != nil {
+		return err
+	}
+	return s.db.InsertMarketDepth(chainType, token, &dbtypes.MarketDepthSnapshot{
+		MarketLiquidity: dbtypes.MarketLiquidity{
+			Time:     time.Unix(snapshot.Time, 0),
+			MidGap:   snapshot.MidGap,
+			Spread:   snapshot.Spread,
+			BidDepth: snapshot.BidDepth,
+			AskDepth: snapshot.AskDepth,
+		},
+		Bids: bids,
+		Asks: asks,
+	})
+}
+
+func (s *marketStore) Liquidity(chainType, token string) ([]*exchanges.Liquidity, error) {
+	rows, err := s.db.MarketLiquidity(chainType, token)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := make([]*exchanges.Liquidity, 0, len(rows))
+	for _, l := range rows {
+		liquidity = append(liquidity, &exchanges.Liquidity{
+			Time:     l.Time.Unix(),
+			MidGap:   l.MidGap,
+			Spread:   l.Spread,
+			BidDepth: l.BidDepth,
+			AskDepth: l.AskDepth,
+		})
+	}
+	return liquidity, nil
+}
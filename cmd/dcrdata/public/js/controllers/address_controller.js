@@ -94,7 +94,7 @@ function setTxnCountText (el, count) {
   }
 }
 
-let commonOptions, typesGraphOptions, amountFlowGraphOptions, balanceGraphOptions
+let commonOptions, typesGraphOptions, amountFlowGraphOptions, fiatFlowGraphOptions, balanceGraphOptions
 // Cannot set these until DyGraph is fetched.
 function createOptions () {
   commonOptions = {
@@ -134,6 +134,18 @@ function createOptions () {
     fillGraph: false
   }
 
+  // The amounts valued at the daily price of their block time.
+  fiatFlowGraphOptions = {
+    labels: ['Date', 'Received', 'Spent', 'Net Received', 'Net Spent'],
+    colors: ['#2971FF', '#2ED6A1', '#41BF53', '#FF0090'],
+    ylabel: 'Total (USD)',
+    visibility: [true, false, false, false],
+    digitsAfterDecimal: 2,
+    legendFormatter: formatter,
+    stackedGraph: true,
+    fillGraph: false
+  }
+
   balanceGraphOptions = {
     labels: ['Date', 'Balance'],
     colors: ['#41BF53'],
@@ -283,7 +295,8 @@ export default class extends Controller {
 
   makeTableUrl (txType, count, offset, time) {
     const root = this.dcrAddress === 'treasury' ? 'treasurytable' : `addresstable/${this.dcrAddress}`
-    return `/${root}?txntype=${txType}&n=${count}&start=${offset}${time && time !== '' ? '&time=' + time : ''}`
+    const currency = new URL(window.location).searchParams.get('currency')
+    return `/${root}?txntype=${txType}&n=${count}&start=${offset}${time && time !== '' ? '&time=' + time : ''}${currency ? '&currency=' + currency : ''}`
   }
 
   changePageSize () {
@@ -487,6 +500,7 @@ export default class extends Controller {
     if (this.dcrAddress !== 'treasury') {
       const chartKey = chart === 'balance' ? 'amountflow' : chart
       url = '/api/address/' + ctrl.dcrAddress + '/' + chartKey + '/' + bin
+      if (chart === 'fiatflow') url += '?currency=' + ctrl.fiatCurrency
     }
 
     const graphDataResponse = await requestJSON(url)
@@ -508,6 +522,8 @@ export default class extends Controller {
       const processed = amountFlowProcessor(data, binSize)
       ctrl.retrievedData['amountflow-' + bin] = processed.flow
       ctrl.retrievedData['balance-' + bin] = processed.balance
+    } else if (chart === 'fiatflow') {
+      ctrl.retrievedData['fiatflow-' + bin] = amountFlowProcessor(data, binSize).flow
     } else return
     setTimeout(() => {
       ctrl.popChartCache(chart, bin)
@@ -537,6 +553,13 @@ export default class extends Controller {
         ctrl.flowTarget.classList.remove('d-hide')
         break
 
+      case 'fiatflow':
+        options = fiatFlowGraphOptions
+        options.ylabel = `Total (${ctrl.fiatCurrency})`
+        options.plotter = sizedBarPlotter(binSize)
+        ctrl.flowTarget.classList.remove('d-hide')
+        break
+
       case 'balance':
         options = balanceGraphOptions
         break
@@ -551,7 +574,7 @@ export default class extends Controller {
         ...options
       })
     }
-    if (chart === 'amountflow') {
+    if (chart === 'amountflow' || chart === 'fiatflow') {
       ctrl.updateFlow()
     }
     ctrl.chartLoaderTarget.classList.remove('loading')
@@ -829,6 +852,10 @@ export default class extends Controller {
     return this.optionsTarget.value
   }
 
+  get fiatCurrency () {
+    return this.optionsTarget.namedItem('fiatflow').dataset.currency
+  }
+
   get activeView () {
     let view = null
     this.viewTargets.forEach((button) => {
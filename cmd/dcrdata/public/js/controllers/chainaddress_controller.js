@@ -7,8 +7,17 @@ import humanize from '../helpers/humanize_helper.js'
 import txInBlock from '../helpers/block_helper.js'
 import { fadeIn, animationFrame } from '../helpers/animation_helper.js'
 import { requestJSON } from '../helpers/http.js'
+import { sizedBarPlotter } from '../helpers/chart_helper.js'
+import Zoom from '../helpers/zoom_helper.js'
 
 const maxAddrRows = 160
+let Dygraph // lazy loaded on connect
+
+// fiatFlowProcessor converts the fiat flow chart data to dygraph rows of
+// received, sent and net amounts.
+function fiatFlowProcessor (d) {
+  return d.time.map((t, i) => [new Date(t), d.received[i], d.sent[i], d.net[i]])
+}
 function setTxnCountText (el, count) {
   if (el.dataset.formatted) {
     el.textContent = count + ' transaction' + (count > 1 ? 's' : '')
@@ -27,7 +36,8 @@ export default class extends Controller {
       'paginator', 'pageplus', 'pageminus', 'listbox', 'table',
       'range', 'noconfirms', 'pagebuttons',
       'pending', 'hash', 'matchhash', 'view', 'listLoader',
-      'tablePagination', 'paginationheader']
+      'tablePagination', 'paginationheader',
+      'chart', 'chartLoader', 'interval']
   }
 
   async connect () {
@@ -58,11 +68,71 @@ export default class extends Controller {
 
     // Get initial view settings from the url
     ctrl.query.update(settings)
+
+    // The chart of the amounts received and sent, valued at block time, is
+    // only there when the address has priced transactions.
+    if (ctrl.hasChartTarget) {
+      ctrl.fiatCurrency = ctrl.chartTarget.dataset.currency
+      ctrl.bin = 'month'
+      Dygraph = await getDefault(
+        import(/* webpackChunkName: "dygraphs" */ '../vendor/dygraphs.min.js')
+      )
+      ctrl.drawFiatFlow()
+    }
+  }
+
+  async drawFiatFlow () {
+    const bin = ctrl.bin
+    ctrl.chartLoaderTarget.classList.add('loading')
+    const url = `/api/chain/${ctrl.chainType}/address/${ctrl.dcrAddress}/fiatflow/${bin}?currency=${ctrl.fiatCurrency}`
+    let data
+    try {
+      data = await requestJSON(url)
+    } catch (err) {
+      console.error(err)
+    }
+    ctrl.chartLoaderTarget.classList.remove('loading')
+    if (bin !== ctrl.bin) return
+    if (!data || !data.time || data.time.length === 0) {
+      ctrl.noconfirmsTarget.classList.remove('d-hide')
+      ctrl.chartTarget.classList.add('d-hide')
+      return
+    }
+    ctrl.noconfirmsTarget.classList.add('d-hide')
+    ctrl.chartTarget.classList.remove('d-hide')
+    const options = {
+      labels: ['Date', 'Received', 'Spent', 'Net'],
+      colors: ['#2971FF', '#2ED6A1', '#41BF53'],
+      ylabel: `Total (${ctrl.fiatCurrency})`,
+      digitsAfterDecimal: 2,
+      legend: 'follow',
+      labelsKMB: true,
+      labelsUTC: true,
+      fillGraph: false,
+      plotter: sizedBarPlotter(Zoom.mapValue(bin))
+    }
+    const rows = fiatFlowProcessor(data)
+    if (ctrl.graph) {
+      ctrl.graph.updateOptions({ file: rows, ...options })
+    } else {
+      ctrl.graph = new Dygraph(ctrl.chartTarget, rows, options)
+    }
+  }
+
+  changeBin (e) {
+    const target = e.srcElement || e.target
+    if (target.nodeName !== 'BUTTON') return
+    ctrl.bin = target.name
+    ctrl.intervalTarget.querySelectorAll('button').forEach((button) => {
+      button.classList.toggle('btn-selected', button.name === ctrl.bin)
+    })
+    ctrl.drawFiatFlow()
   }
 
   disconnect () {
     globalEventBus.off('BLOCK_RECEIVED', this.confirmMempoolTxs)
     this.retrievedData = {}
+    if (this.graph) this.graph.destroy()
   }
 
   bindElements () {
@@ -109,7 +179,8 @@ export default class extends Controller {
 
   makeTableUrl (txType, count, offset) {
     const root = `${this.chainType}/addresstable/${this.dcrAddress}`
-    return `/${root}?txntype=${txType}&n=${count}&start=${offset}`
+    const currency = new URL(window.location).searchParams.get('currency')
+    return `/${root}?txntype=${txType}&n=${count}&start=${offset}${currency ? '&currency=' + currency : ''}`
   }
 
   changePageSize () {
@@ -19,12 +19,18 @@ const aMonth = 30 // in days
 const atomsToDCR = 1e-8
 const windowScales = ['ticket-price', 'missed-votes']
 const hybridScales = ['privacy-participation']
-const lineScales = ['ticket-price', 'privacy-participation']
+const lineScales = ['ticket-price', 'privacy-participation', 'coin-age-bands']
 const modeScales = ['ticket-price']
 let globalChainType = ''
 // index 0 represents y1 and 1 represents y2 axes.
 const yValueRanges = { 'ticket-price': [1] }
 const hashrateUnits = ['Th/s', 'Ph/s', 'Eh/s']
+// The age bands of the HODL waves, oldest first.
+const coinAgeBandKeys = ['greaterThan7Year', 'fiveYearTo7Year', 'threeYearTo5Year', 'twoYearTo3Year',
+  'yearTo2Year', 'halfYearToYear', 'monthToHalfYear', 'weekToMonth', 'dayToWeek', 'less1Day']
+const coinAgeBandsLabels = ['>7Y', '5-7Y', '3-5Y', '2-3Y', '1-2Y', '6M-1Y', '1-6M', '1W-1M', '1D-1W', '<1D']
+const coinAgeBandsColors = ['#152b83', '#dc3912', '#ff9900', '#109618', '#990099', '#0099c6',
+  '#dd4477', '#66aa00', '#b82e2e', '#576812ff']
 let premine, stakeValHeight, stakeShare
 let baseSubsidy, subsidyInterval, subsidyExponent, avgBlockTime
 let yFormatter, legendEntry, legendMarker, legendElement
@@ -42,7 +48,19 @@ const yAxisLabelWidth = {
     'mempool-size': 40,
     'mempool-txs': 50,
     'coin-supply': 30,
-    fees: 50
+    fees: 50,
+    'avg-age-days': 50,
+    'coin-days-destroyed': 50,
+    'coin-age-bands': 30,
+    'mean-coin-age': 50,
+    'total-coin-days': 50,
+    'utxo-count': 50,
+    'utxo-value': 50,
+    'utxo-set-size': 40,
+    'utxo-dust': 50
+  },
+  y2: {
+    'utxo-dust': 40
   }
 }
 
@@ -206,6 +224,32 @@ function zip2D (data, ys, yMult, offset) {
   return zipTvY(data.t, ys, yMult)
 }
 
+// zipUtxoSet zips the UTXO set snapshots, which come with their own heights or
+// times whatever the bin.
+function zipUtxoSet (data, ys, yMult) {
+  yMult = yMult || 1
+  if (data.h) return data.h.map((h, i) => [h, ys[i] * yMult])
+  return zipTvY(data.t, ys, yMult)
+}
+
+function zipUtxoSetYZ (data, ys, zs, yMult, zMult) {
+  yMult = yMult || 1
+  zMult = zMult || 1
+  const xs = data.h ? data.h : data.t.map(t => new Date(t * 1000))
+  return xs.map((x, i) => [x, ys[i] * yMult, zs[i] * zMult])
+}
+
+// coinAgeBandsFunc maps the value of each age band to its percentage of the
+// UTXO set.
+function coinAgeBandsFunc (data) {
+  const xs = zip2D(data, data.ageBands.map(() => 0)).map(pt => pt[0])
+  return data.ageBands.map((bands, i) => {
+    const values = coinAgeBandKeys.map(k => Number(bands[k]))
+    const total = values.reduce((sum, v) => sum + v, 0)
+    return [xs[i], ...values.map(v => total > 0 ? (v / total) * 100 : 0)]
+  })
+}
+
 function powDiffFunc (data) {
   if (data.t) return zipWindowTvY(data.t, data.diff)
   return zipWindowHvY(data.diff, data.window)
@@ -582,6 +626,8 @@ export default class extends Controller {
       visibility: null,
       y2label: null,
       stepPlot: this.settings.mode === 'stepped',
+      stackedGraph: false,
+      fillGraph: false,
       axes: {},
       series: null,
       inflation: null
@@ -692,6 +738,83 @@ export default class extends Controller {
           false, 'Network Hashrate (petahash/s)', true, false))
         yFormatter = customYFormatter(y => withBigUnits(y * 1e3, hashrateUnits))
         break
+
+      case 'avg-age-days':
+        d = zip2D(data, data.avgAge)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Average Age Days'], true,
+          'Average Age Days (days)', true, false))
+        yFormatter = customYFormatter(y => y.toFixed(2) + ' days')
+        break
+
+      case 'coin-days-destroyed':
+        d = zip2D(data, data.cdd)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Coin Days Destroyed'], true,
+          'Coin Days Destroyed (coin-days)', true, false))
+        yFormatter = customYFormatter(y => humanize.formatNumber(y, 2, true) + ' coin-days')
+        break
+
+      case 'mean-coin-age':
+        d = zip2D(data, data.meanCoinAge)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Mean Coin Age'], true,
+          'Mean Coin Age (days)', true, false))
+        yFormatter = customYFormatter(y => humanize.formatNumber(y, 2, true) + ' days')
+        break
+
+      case 'total-coin-days':
+        d = zip2D(data, data.totalCoinDays)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Total Coin Days'], true,
+          'Total Coin Days (coin-days)', true, false))
+        yFormatter = customYFormatter(y => humanize.formatNumber(y, 2, true) + ' coin-days')
+        break
+
+      case 'coin-age-bands':
+        d = coinAgeBandsFunc(data)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, ...coinAgeBandsLabels], false,
+          'HODL Wave (%)', false, false))
+        gOptions.colors = coinAgeBandsColors
+        gOptions.logscale = false
+        gOptions.valueRange = [0, 100]
+        gOptions.stackedGraph = true
+        gOptions.fillGraph = true
+        yFormatter = customYFormatter(y => y.toFixed(2) + ' %')
+        break
+
+      case 'utxo-count':
+        d = zipUtxoSetYZ(data, data.count, data.dust_count)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Unspent Outputs', 'Dust Outputs'], true,
+          'Unspent Outputs', true, false))
+        yFormatter = customYFormatter(y => intComma(y) + ' outputs')
+        break
+
+      case 'utxo-value':
+        d = zipUtxoSet(data, data.value)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'UTXO Value'], true,
+          'UTXO Value (' + globalChainType.toUpperCase() + ')', true, false))
+        yFormatter = customYFormatter(y => intComma(y) + ' ' + globalChainType.toUpperCase())
+        break
+
+      case 'utxo-set-size':
+        d = zipUtxoSet(data, data.size)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'UTXO Set Size'], true,
+          'UTXO Set Size', false, true))
+        yFormatter = customYFormatter(y => humanize.bytes(y))
+        break
+
+      case 'utxo-dust':
+        d = zipUtxoSetYZ(data, data.dust_count, data.dust_value)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Dust Outputs', 'Dust Value'], true,
+          'Dust Outputs', true, false))
+        gOptions.y2label = 'Dust Value (' + globalChainType.toUpperCase() + ')'
+        gOptions.series = { 'Dust Value': { axis: 'y2' } }
+        gOptions.axes.y2 = {
+          axisLabelWidth: isMobile() ? yAxisLabelWidth.y2['utxo-dust'] : yAxisLabelWidth.y2['utxo-dust'] + 5
+        }
+        yFormatter = (div, data) => {
+          if (!data.series || data.series.length === 0) return
+          addLegendEntryFmt(div, data.series[0], y => intComma(y) + ' outputs')
+          addLegendEntryFmt(div, data.series[1], y => y.toFixed(8) + ' ' + globalChainType.toUpperCase())
+        }
+        break
     }
     gOptions.axes.y = {
       axisLabelWidth: isMobile() ? yAxisLabelWidth.y1[chartName] : yAxisLabelWidth.y1[chartName] + 5
@@ -808,6 +931,24 @@ export default class extends Controller {
         return `Shows the average time interval between consecutive ${this.getChainName()} blocks over time.`
       case 'hashrate':
         return `Total computational power securing the ${this.getChainName()} network over time.`
+      case 'avg-age-days':
+        return `Shows the average age (in days) of the coins spent in each ${this.getChainName()} block, reflecting how long they were held before being transacted.`
+      case 'coin-days-destroyed':
+        return `Represents the coin-days destroyed per ${this.getChainName()} block, the amount of each coin moved multiplied by its age.`
+      case 'coin-age-bands':
+        return `Visualizes the distribution of the unspent ${this.getChainName()} coins by age band, showing how long coins have been held without moving.`
+      case 'mean-coin-age':
+        return `Shows the average age of all unspent ${this.getChainName()} coins, indicating how long coins have been held without moving.`
+      case 'total-coin-days':
+        return `Represents the total coin-days of the unspent ${this.getChainName()} coins, measuring how long coins have remained unmoved.`
+      case 'utxo-count':
+        return `Shows the number of unspent ${this.getChainName()} outputs at each UTXO set snapshot, and how many of them are dust.`
+      case 'utxo-value':
+        return `Shows the total value of the unspent ${this.getChainName()} outputs at each UTXO set snapshot.`
+      case 'utxo-set-size':
+        return `Shows the serialized size of the unspent ${this.getChainName()} outputs at each UTXO set snapshot.`
+      case 'utxo-dust':
+        return `Shows the number and value of the unspent ${this.getChainName()} outputs worth less than the fee to spend them at the dust relay fee rate.`
       default:
         return ''
     }
@@ -841,6 +982,24 @@ export default class extends Controller {
         return 'Mempool Size'
       case 'address-number':
         return 'Active Addresses'
+      case 'avg-age-days':
+        return 'Average Age Days'
+      case 'coin-days-destroyed':
+        return 'Coin Days Destroyed'
+      case 'coin-age-bands':
+        return 'HODL Age Bands'
+      case 'mean-coin-age':
+        return 'Mean Coin Age'
+      case 'total-coin-days':
+        return 'Total Coin Days'
+      case 'utxo-count':
+        return 'UTXO Count'
+      case 'utxo-value':
+        return 'UTXO Value'
+      case 'utxo-set-size':
+        return 'UTXO Set Size'
+      case 'utxo-dust':
+        return 'UTXO Dust'
       default:
         return ''
     }
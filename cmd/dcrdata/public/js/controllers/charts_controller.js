@@ -70,7 +70,11 @@ const yAxisLabelWidth = {
     'coin-days-destroyed': 50,
     'coin-age-bands': 30,
     'mean-coin-age': 50,
-    'total-coin-days': 50
+    'total-coin-days': 50,
+    'utxo-count': 50,
+    'utxo-value': 50,
+    'utxo-set-size': 40,
+    'utxo-dust': 50
   },
   y2: {
     'ticket-price': 45,
@@ -78,7 +82,8 @@ const yAxisLabelWidth = {
     'coin-days-destroyed': 30,
     'coin-age-bands': 30,
     'mean-coin-age': 30,
-    'total-coin-days': 30
+    'total-coin-days': 30,
+    'utxo-dust': 40
   }
 }
 
@@ -574,6 +579,21 @@ function zipHvY (heights, ys, yMult, offset) {
   })
 }
 
+// zipUtxoSet zips the UTXO set snapshots, which come with their own heights or
+// times whatever the bin.
+function zipUtxoSet (data, ys, yMult) {
+  yMult = yMult || 1
+  if (data.h) return data.h.map((h, i) => [h, ys[i] * yMult])
+  return zipTvY(data.t, ys, yMult)
+}
+
+function zipUtxoSetYZ (data, ys, zs, yMult, zMult) {
+  yMult = yMult || 1
+  zMult = zMult || 1
+  const xs = data.h ? data.h : data.t.map(t => new Date(t * 1000))
+  return xs.map((x, i) => [x, ys[i] * yMult, zs[i] * zMult])
+}
+
 function zip2D (data, ys, yMult, offset) {
   yMult = yMult || 1
   if (data.axis === 'height') {
@@ -1366,6 +1386,43 @@ export default class extends Controller {
         assign(gOptions, mapDygraphOptions(d, [xlabel, 'Missed Votes'], false,
           'Missed Votes per Window', true, false))
         break
+
+      case 'utxo-count':
+        d = zipUtxoSetYZ(data, data.count, data.dust_count)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Unspent Outputs', 'Dust Outputs'], true,
+          'Unspent Outputs', true, false))
+        yFormatter = customYFormatter(y => intComma(y) + ' outputs')
+        break
+
+      case 'utxo-value':
+        d = zipUtxoSet(data, data.value)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'UTXO Value'], true,
+          'UTXO Value (DCR)', true, false))
+        yFormatter = customYFormatter(y => intComma(y) + ' DCR')
+        break
+
+      case 'utxo-set-size':
+        d = zipUtxoSet(data, data.size)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'UTXO Set Size'], true,
+          'UTXO Set Size', false, true))
+        yFormatter = customYFormatter(y => humanize.bytes(y))
+        break
+
+      case 'utxo-dust':
+        d = zipUtxoSetYZ(data, data.dust_count, data.dust_value)
+        assign(gOptions, mapDygraphOptions(d, [xlabel, 'Dust Outputs', 'Dust Value'], true,
+          'Dust Outputs', true, false))
+        gOptions.y2label = 'Dust Value (DCR)'
+        gOptions.series = { 'Dust Value': { axis: 'y2' } }
+        gOptions.axes.y2 = {
+          axisLabelWidth: isMobile() ? yAxisLabelWidth.y2['utxo-dust'] : yAxisLabelWidth.y2['utxo-dust'] + 15
+        }
+        yFormatter = (div, data, i) => {
+          if (!data.series || data.series.length === 0) return
+          addLegendEntryFmt(div, data.series[0], y => intComma(y) + ' outputs')
+          addLegendEntryFmt(div, data.series[1], y => y.toFixed(8) + ' DCR')
+        }
+        break
     }
     gOptions.axes.y = {
       axisLabelWidth: isMobile() ? yAxisLabelWidth.y1[chartName] : yAxisLabelWidth.y1[chartName] + 15
@@ -1554,6 +1611,14 @@ export default class extends Controller {
         return 'Shows the average age of all Decred coins in circulation, indicating how long coins have been held without moving.'
       case 'total-coin-days':
         return 'Represents the cumulative total of all coin-days in the Decred network, measuring how long coins have remained unmoved.'
+      case 'utxo-count':
+        return 'Shows the number of unspent Decred outputs at each UTXO set snapshot, and how many of them are dust.'
+      case 'utxo-value':
+        return 'Shows the total value of the unspent Decred outputs at each UTXO set snapshot.'
+      case 'utxo-set-size':
+        return 'Shows the serialized size of the unspent Decred outputs at each UTXO set snapshot.'
+      case 'utxo-dust':
+        return 'Shows the number and value of the unspent Decred outputs worth less than the fee to spend them at the dust relay fee rate.'
       default:
         return ''
     }
@@ -1601,6 +1666,14 @@ export default class extends Controller {
         return 'Mean Coin Age'
       case 'total-coin-days':
         return 'Total Coin Days'
+      case 'utxo-count':
+        return 'UTXO Count'
+      case 'utxo-value':
+        return 'UTXO Value'
+      case 'utxo-set-size':
+        return 'UTXO Set Size'
+      case 'utxo-dust':
+        return 'UTXO Dust'
       default:
         return ''
     }
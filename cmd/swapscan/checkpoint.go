@@ -0,0 +1,56 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// checkpoint records the progress of a scan so that it may be resumed. Height
+// is the highest block for which every block from Start up to and including
+// it has been scanned and its swaps written.
+type checkpoint struct {
+	Chain   string `json:"chain"`
+	Output  string `json:"output"`
+	Start   int64  `json:"start"`
+	Height  int64  `json:"height"`
+	Swaps   int64  `json:"swaps"`
+	Updated int64  `json:"updated"`
+}
+
+// loadCheckpoint reads the checkpoint file at path. A nil checkpoint and nil
+// error are returned if the file does not exist.
+func loadCheckpoint(path string) (*checkpoint, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := new(checkpoint)
+	if err = json.Unmarshal(b, cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+// save writes the checkpoint to a temporary file and renames it over path so
+// that an interrupted write never leaves a corrupt checkpoint behind.
+func (cp *checkpoint) save(path string) error {
+	cp.Updated = time.Now().Unix()
+	b, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -1,29 +1,41 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
 package main
 
 import (
-	"fmt"
-	"io"
 	"os"
-	"path/filepath"
-)
 
-var logFILE *os.File
+	"github.com/decred/dcrd/rpcclient/v8"
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
+	"github.com/decred/dcrdata/v8/mutilchain/ltcrpcutils"
+	"github.com/decred/dcrdata/v8/rpcutils"
+	"github.com/decred/slog"
+)
 
-var outw io.Writer
+var (
+	backendLog = slog.NewBackend(os.Stderr)
+	log        = backendLog.Logger("SWAP")
+	dbLogger   = backendLog.Logger("PSQL")
+	rpcLogger  = backendLog.Logger("RPCC")
+)
 
-const logFile = "swapscan.txt"
+// initializeLogging sets the level of the swapscan loggers and registers them
+// with the packages used to talk to the nodes and the database.
+func initializeLogging(logLevel string) {
+	dcrpg.UseLogger(dbLogger)
+	rpcclient.UseLogger(rpcLogger)
+	rpcutils.UseLogger(rpcLogger)
+	btcrpcutils.UseLogger(rpcLogger)
+	ltcrpcutils.UseLogger(rpcLogger)
 
-// InitLogger starts the logger
-func InitLogger() error {
-	logFilePath, _ := filepath.Abs(logFile)
-	var err error
-	logFILE, err = os.OpenFile(logFilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND,
-		0664)
-	if err != nil {
-		return fmt.Errorf("Error opening log file: %v", err)
+	level, ok := slog.LevelFromString(logLevel)
+	if !ok {
+		log.Infof("Unable to assign logging level=%s. Falling back to level=info", logLevel)
+		level = slog.LevelInfo
 	}
-
-	outw = io.MultiWriter(logFILE, os.Stdout)
-
-	return nil
+	log.SetLevel(level)
+	dbLogger.SetLevel(level)
+	rpcLogger.SetLevel(level)
 }
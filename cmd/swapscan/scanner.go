@@ -0,0 +1,267 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
+	btcrpcclient "github.com/btcsuite/btcd/rpcclient"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/rpcclient/v8"
+	ltcchaincfg "github.com/ltcsuite/ltcd/chaincfg"
+	ltcrpcclient "github.com/ltcsuite/ltcd/rpcclient"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/txhelpers"
+	"github.com/decred/dcrdata/v8/txhelpers/btctxhelper"
+	"github.com/decred/dcrdata/v8/txhelpers/ltctxhelper"
+)
+
+const (
+	swapTypeRedeem = "redeem"
+	swapTypeRefund = "refund"
+)
+
+// swapRecord is a single redemption or refund of an atomic swap contract found
+// in a block. The exported fields are the JSONL output format. Exactly one of
+// dcrSwap and mcSwap is set, depending on the chain, for the database writer.
+type swapRecord struct {
+	Chain        string `json:"chain"`
+	Height       int64  `json:"height"`
+	BlockHash    string `json:"block_hash"`
+	Type         string `json:"type"`
+	SpendTx      string `json:"spend_tx"`
+	SpendVin     uint32 `json:"spend_vin"`
+	ContractTx   string `json:"contract_tx"`
+	ContractVout uint32 `json:"contract_vout"`
+	ContractAddr string `json:"contract_address"`
+	Value        int64  `json:"value"`
+	Locktime     int64  `json:"locktime"`
+	SecretHash   string `json:"secret_hash"`
+	Secret       string `json:"secret,omitempty"`
+
+	dcrSwap *txhelpers.AtomicSwapData
+	mcSwap  *txhelpers.MultichainAtomicSwapData
+}
+
+// blockSwaps holds all of the swap spends found in one block.
+type blockSwaps struct {
+	height int64
+	hash   string
+	swaps  []*swapRecord
+}
+
+// chainScanner finds the atomic swap redemptions and refunds in the blocks of
+// a single chain. Implementations must be safe for concurrent use since blocks
+// are scanned by multiple workers.
+type chainScanner interface {
+	chainType() string
+	bestHeight(ctx context.Context) (int64, error)
+	scanBlock(ctx context.Context, height int64) (*blockSwaps, error)
+}
+
+// sortedVins returns the keys of a redemption or refund map in ascending
+// order so that output is deterministic.
+func sortedVins[T any](m map[uint32]T) []uint32 {
+	vins := make([]uint32, 0, len(m))
+	for vin := range m {
+		vins = append(vins, vin)
+	}
+	sort.Slice(vins, func(i, j int) bool { return vins[i] < vins[j] })
+	return vins
+}
+
+func newMultichainRecord(chain string, height int64, hash string, swap *txhelpers.MultichainAtomicSwapData) *swapRecord {
+	rec := &swapRecord{
+		Chain:        chain,
+		Height:       height,
+		BlockHash:    hash,
+		Type:         swapTypeRedeem,
+		SpendTx:      swap.SpendTx,
+		SpendVin:     swap.SpendVin,
+		ContractTx:   swap.ContractTx,
+		ContractVout: swap.ContractVout,
+		ContractAddr: swap.ContractAddress,
+		Value:        swap.Value,
+		Locktime:     swap.Locktime,
+		SecretHash:   hex.EncodeToString(swap.SecretHash[:]),
+		Secret:       hex.EncodeToString(swap.Secret),
+		mcSwap:       swap,
+	}
+	if swap.IsRefund {
+		rec.Type = swapTypeRefund
+	}
+	return rec
+}
+
+// dcrScanner scans Decred blocks using a dcrd RPC client.
+type dcrScanner struct {
+	client *rpcclient.Client
+	params *chaincfg.Params
+}
+
+func (s *dcrScanner) chainType() string {
+	return mutilchain.TYPEDCR
+}
+
+func (s *dcrScanner) bestHeight(ctx context.Context) (int64, error) {
+	_, height, err := s.client.GetBestBlock(ctx)
+	return height, err
+}
+
+func (s *dcrScanner) scanBlock(ctx context.Context, height int64) (*blockSwaps, error) {
+	blockhash, err := s.client.GetBlockHash(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockHash(%d) failed: %w", height, err)
+	}
+	msgBlock, err := s.client.GetBlock(ctx, blockhash)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlock failed (%s): %w", blockhash, err)
+	}
+
+	bs := &blockSwaps{height: height, hash: blockhash.String()}
+	// Check all regular tree txns except coinbase.
+	for _, tx := range msgBlock.Transactions[1:] {
+		swapRes, err := txhelpers.MsgTxAtomicSwapsInfo(tx, nil, s.params)
+		if err != nil {
+			return nil, err
+		}
+		if swapRes == nil || swapRes.Found == "" {
+			continue
+		}
+		for _, swaps := range []map[uint32]*txhelpers.AtomicSwapData{swapRes.Redemptions, swapRes.Refunds} {
+			for _, vin := range sortedVins(swaps) {
+				swap := swaps[vin]
+				rec := &swapRecord{
+					Chain:        mutilchain.TYPEDCR,
+					Height:       height,
+					BlockHash:    bs.hash,
+					Type:         swapTypeRedeem,
+					SpendTx:      swap.SpendTx.String(),
+					SpendVin:     swap.SpendVin,
+					ContractTx:   swap.ContractTx.String(),
+					ContractVout: swap.ContractVout,
+					ContractAddr: swap.ContractAddress,
+					Value:        swap.Value,
+					Locktime:     swap.Locktime,
+					SecretHash:   hex.EncodeToString(swap.SecretHash[:]),
+					Secret:       hex.EncodeToString(swap.Secret),
+					dcrSwap:      swap,
+				}
+				if swap.IsRefund {
+					rec.Type = swapTypeRefund
+				}
+				bs.swaps = append(bs.swaps, rec)
+			}
+		}
+	}
+	return bs, nil
+}
+
+// btcScanner scans Bitcoin blocks using a btcd RPC client. The node must have
+// a transaction index since contract outputs are looked up to get swap values.
+type btcScanner struct {
+	client *btcrpcclient.Client
+	params *btcchaincfg.Params
+}
+
+func (s *btcScanner) chainType() string {
+	return mutilchain.TYPEBTC
+}
+
+func (s *btcScanner) bestHeight(_ context.Context) (int64, error) {
+	return s.client.GetBlockCount()
+}
+
+func (s *btcScanner) scanBlock(_ context.Context, height int64) (*blockSwaps, error) {
+	blockhash, err := s.client.GetBlockHash(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockHash(%d) failed: %w", height, err)
+	}
+	msgBlock, err := s.client.GetBlock(blockhash)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlock failed (%s): %w", blockhash, err)
+	}
+
+	bs := &blockSwaps{height: height, hash: blockhash.String()}
+	// Check all txns except coinbase.
+	for _, tx := range msgBlock.Transactions[1:] {
+		swapRes, err := btctxhelper.MsgTxAtomicSwapsInfo(tx, nil, s.params)
+		if err != nil {
+			return nil, err
+		}
+		if swapRes == nil || swapRes.Found == "" {
+			continue
+		}
+		for _, swaps := range []map[uint32]*txhelpers.MultichainAtomicSwapData{swapRes.Redemptions, swapRes.Refunds} {
+			for _, vin := range sortedVins(swaps) {
+				swap := swaps[vin]
+				contractHash := &tx.TxIn[vin].PreviousOutPoint.Hash
+				contractTx, err := s.client.GetRawTransaction(contractHash)
+				if err != nil {
+					return nil, fmt.Errorf("GetRawTransaction failed (%s): %w", contractHash, err)
+				}
+				swap.Value = contractTx.MsgTx().TxOut[swap.ContractVout].Value
+				bs.swaps = append(bs.swaps, newMultichainRecord(mutilchain.TYPEBTC, height, bs.hash, swap))
+			}
+		}
+	}
+	return bs, nil
+}
+
+// ltcScanner scans Litecoin blocks using an ltcd RPC client. The node must
+// have a transaction index since contract outputs are looked up to get swap
+// values.
+type ltcScanner struct {
+	client *ltcrpcclient.Client
+	params *ltcchaincfg.Params
+}
+
+func (s *ltcScanner) chainType() string {
+	return mutilchain.TYPELTC
+}
+
+func (s *ltcScanner) bestHeight(_ context.Context) (int64, error) {
+	return s.client.GetBlockCount()
+}
+
+func (s *ltcScanner) scanBlock(_ context.Context, height int64) (*blockSwaps, error) {
+	blockhash, err := s.client.GetBlockHash(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlockHash(%d) failed: %w", height, err)
+	}
+	msgBlock, err := s.client.GetBlock(blockhash)
+	if err != nil {
+		return nil, fmt.Errorf("GetBlock failed (%s): %w", blockhash, err)
+	}
+
+	bs := &blockSwaps{height: height, hash: blockhash.String()}
+	// Check all txns except coinbase.
+	for _, tx := range msgBlock.Transactions[1:] {
+		swapRes, err := ltctxhelper.MsgTxAtomicSwapsInfo(tx, nil, s.params)
+		if err != nil {
+			return nil, err
+		}
+		if swapRes == nil || swapRes.Found == "" {
+			continue
+		}
+		for _, swaps := range []map[uint32]*txhelpers.MultichainAtomicSwapData{swapRes.Redemptions, swapRes.Refunds} {
+			for _, vin := range sortedVins(swaps) {
+				swap := swaps[vin]
+				contractHash := &tx.TxIn[vin].PreviousOutPoint.Hash
+				contractTx, err := s.client.GetRawTransaction(contractHash)
+				if err != nil {
+					return nil, fmt.Errorf("GetRawTransaction failed (%s): %w", contractHash, err)
+				}
+				swap.Value = contractTx.MsgTx().TxOut[swap.ContractVout].Value
+				bs.swaps = append(bs.swaps, newMultichainRecord(mutilchain.TYPELTC, height, bs.hash, swap))
+			}
+		}
+	}
+	return bs, nil
+}
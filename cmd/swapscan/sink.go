@@ -0,0 +1,115 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// swapSink receives the swaps of each scanned block, in height order.
+type swapSink interface {
+	writeBlock(ctx context.Context, bs *blockSwaps) error
+	// flush is called before each checkpoint so that the checkpoint never
+	// gets ahead of the data that was actually written.
+	flush() error
+	close() error
+}
+
+// dbSink writes swaps into the explorer's swaps, btc_swaps and ltc_swaps
+// tables. Rows are upserted on (spend_tx, spend_vin), so rescanning a range
+// is safe.
+type dbSink struct {
+	db *sql.DB
+	// bg is required for Decred swaps to look up the contract transaction
+	// time.
+	bg dcrpg.BlockGetter
+}
+
+func newDBSink(db *sql.DB, bg dcrpg.BlockGetter) (*dbSink, error) {
+	if err := dcrpg.CheckCreateSwapsTables(db); err != nil {
+		return nil, fmt.Errorf("unable to create swaps tables: %w", err)
+	}
+	return &dbSink{db: db, bg: bg}, nil
+}
+
+func (s *dbSink) writeBlock(ctx context.Context, bs *blockSwaps) error {
+	for _, rec := range bs.swaps {
+		var err error
+		switch rec.Chain {
+		case mutilchain.TYPEDCR:
+			err = dcrpg.InsertSwap(s.db, ctx, s.bg, rec.Height, rec.dcrSwap, rec.dcrSwap.IsRefund)
+		case mutilchain.TYPEBTC:
+			err = dcrpg.InsertBtcSwap(s.db, rec.Height, rec.mcSwap)
+		case mutilchain.TYPELTC:
+			err = dcrpg.InsertLtcSwap(s.db, rec.Height, rec.mcSwap)
+		default:
+			err = fmt.Errorf("unsupported chain %q", rec.Chain)
+		}
+		if err != nil {
+			return fmt.Errorf("insert %s swap %s:%d failed: %w", rec.Chain, rec.SpendTx, rec.SpendVin, err)
+		}
+	}
+	return nil
+}
+
+func (s *dbSink) flush() error {
+	return nil
+}
+
+func (s *dbSink) close() error {
+	return s.db.Close()
+}
+
+// jsonlSink writes one JSON object per swap to a file. When resuming, the file
+// is appended to.
+type jsonlSink struct {
+	f   *os.File
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func newJSONLSink(path string, appendFile bool) (*jsonlSink, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendFile {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &jsonlSink{f: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (s *jsonlSink) writeBlock(_ context.Context, bs *blockSwaps) error {
+	for _, rec := range bs.swaps {
+		if err := s.enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonlSink) flush() error {
+	if err := s.w.Flush(); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *jsonlSink) close() error {
+	if err := s.flush(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
@@ -1,178 +1,374 @@
 // Copyright (c) 2021, The Decred developers
 // See LICENSE for details.
 
+// swapscan scans a range of DCR, BTC or LTC blocks for atomic swap redemptions
+// and refunds. Results are written to the explorer's swaps tables or to a JSONL
+// file. Progress is checkpointed so that an interrupted scan may be resumed,
+// which also makes it suitable for backfilling swaps after a schema change
+// without resyncing the explorer.
 package main
 
 import (
 	"context"
-	"encoding/csv"
-	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
-	"strconv"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
+	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
-	"github.com/decred/dcrd/rpcclient/v8"
+	ltcchaincfg "github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
 
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
+	"github.com/decred/dcrdata/v8/mutilchain/ltcrpcutils"
 	"github.com/decred/dcrdata/v8/rpcutils"
-	"github.com/decred/dcrdata/v8/txhelpers"
-	"github.com/decred/slog"
 )
 
-var host = flag.String("host", "127.0.0.1:9109", "node RPC host:port")
-var user = flag.String("user", "dcrd", "node RPC username")
-var pass = flag.String("pass", "bananas", "node RPC password")
-var cert = flag.String("cert", "dcrd.cert", "node RPC TLS certificate (when notls=false)")
-var notls = flag.Bool("notls", false, "Disable use of TLS for node connection")
-var start = flag.Uint64("start", 491_705, "Start block height")
-var end = flag.Uint64("end", 1<<32, "End block height")
+const (
+	outputDB    = "db"
+	outputJSONL = "jsonl"
+)
 
 var (
-	backendLog      *slog.Backend
-	rpcclientLogger slog.Logger
+	chain   = flag.String("chain", mutilchain.TYPEDCR, "Chain to scan {dcr, btc, ltc}")
+	testnet = flag.Bool("testnet", false, "Use the test network (default mainnet)")
+
+	host  = flag.String("host", "", "node RPC host:port (default depends on chain and network)")
+	user  = flag.String("user", "", "node RPC username")
+	pass  = flag.String("pass", "", "node RPC password")
+	cert  = flag.String("cert", "", "node RPC TLS certificate when notls=false (default is rpc.cert in the node's app data dir)")
+	notls = flag.Bool("notls", false, "Disable use of TLS for node connection")
+
+	start   = flag.Int64("start", -1, "Start block height (default depends on chain)")
+	end     = flag.Int64("end", -1, "End block height (default is the node's best block)")
+	workers = flag.Int("workers", 4, "Number of blocks scanned in parallel")
+
+	output    = flag.String("output", outputDB, "Where to write swaps {db, jsonl}")
+	jsonlFile = flag.String("jsonl", "", "JSONL output file when output=jsonl (default swapscan-<chain>.jsonl)")
+
+	checkpointFile  = flag.String("checkpoint", "", "Checkpoint file (default swapscan-<chain>-<output>.checkpoint.json)")
+	checkpointEvery = flag.Int64("checkpointevery", 100, "Number of blocks between checkpoints")
+	resume          = flag.Bool("resume", true, "Resume from the checkpoint file if it exists")
+
+	dbHostPort = flag.String("dbhost", "127.0.0.1:5432", "DB host:port, or an absolute path to a UNIX socket")
+	dbUser     = flag.String("dbuser", "dcrdata", "DB user")
+	dbPass     = flag.String("dbpass", "", "DB pass")
+	dbName     = flag.String("dbname", "dcrdata", "DB name")
+
+	debugLevel = flag.String("debuglevel", "info", "Logging level {trace, debug, info, warn, error, critical}")
 )
 
-func mainCore() int {
-	//defer profile.Start(profile.CPUProfile).Stop()
-	defer func() {
-		logFILE.Close()
-		os.Stdout.Sync()
-	}()
+// chainDefaults are the per-chain values used when the corresponding flag is
+// not set.
+type chainDefaults struct {
+	mainnetHost, testnetHost string
+	certDir                  string
+	// mainnetStart is just before the first known swap on mainnet. Testnet
+	// scans start at genesis by default.
+	mainnetStart int64
+}
+
+var defaults = map[string]chainDefaults{
+	mutilchain.TYPEDCR: {"127.0.0.1:9109", "127.0.0.1:19109", dcrutil.AppDataDir("dcrd", false), 491_705},
+	mutilchain.TYPEBTC: {"127.0.0.1:8334", "127.0.0.1:18334", btcutil.AppDataDir("btcd", false), 590_000},
+	// Swap contracts are spent with witness data, so nothing can be found
+	// before segwit activated on litecoin.
+	mutilchain.TYPELTC: {"127.0.0.1:9334", "127.0.0.1:19334", ltcutil.AppDataDir("ltcd", false), 1_201_536},
+}
+
+func mainCore(ctx context.Context) error {
 	flag.Parse()
+	initializeLogging(*debugLevel)
 
-	csvfile, err := os.Create("swapscan.csv")
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error creating utxos file: %v\n", err)
-		return 1
+	def, ok := defaults[*chain]
+	if !ok {
+		return fmt.Errorf("unsupported chain %q", *chain)
+	}
+	if *output != outputDB && *output != outputJSONL {
+		return fmt.Errorf("unsupported output %q", *output)
+	}
+	if *workers < 1 {
+		return fmt.Errorf("workers must be at least 1")
+	}
+	if *checkpointEvery < 1 {
+		return fmt.Errorf("checkpointevery must be at least 1")
+	}
+	if *host == "" {
+		*host = def.mainnetHost
+		if *testnet {
+			*host = def.testnetHost
+		}
+	}
+	if *cert == "" {
+		*cert = filepath.Join(def.certDir, "rpc.cert")
+	}
+	if *start < 0 {
+		*start = def.mainnetStart
+		if *testnet {
+			*start = 0
+		}
+	}
+	if *jsonlFile == "" {
+		*jsonlFile = fmt.Sprintf("swapscan-%s.jsonl", *chain)
+	}
+	if *checkpointFile == "" {
+		*checkpointFile = fmt.Sprintf("swapscan-%s-%s.checkpoint.json", *chain, *output)
 	}
-	defer csvfile.Close()
-
-	csvwriter := csv.NewWriter(csvfile)
-	defer csvwriter.Flush()
 
-	client, _, err := rpcutils.ConnectNodeRPC(*host, *user, *pass, *cert, *notls, false)
+	scanner, bg, err := connectScanner(*chain)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: Unable to connect to RPC server: %v\n", err)
-		return 1
+		return err
 	}
 
-	infoResult, err := client.GetInfo(context.TODO())
+	bestHeight, err := scanner.bestHeight(ctx)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: GetInfo failed: %v\n", err)
-		return 1
+		return fmt.Errorf("unable to get best block height: %w", err)
+	}
+	endHeight := bestHeight
+	if *end >= 0 && *end < bestHeight {
+		endHeight = *end
 	}
-	fmt.Printf("Node connection count: %d\n", infoResult.Connections)
 
-	_, height, err := client.GetBestBlock(context.TODO())
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "ERROR: GetBestBlock failed: %v\n", err)
-		return 2
+	cp := &checkpoint{Chain: *chain, Output: *output, Start: *start, Height: *start - 1}
+	if *resume {
+		saved, err := loadCheckpoint(*checkpointFile)
+		if err != nil {
+			return err
+		}
+		if saved != nil {
+			if saved.Chain != *chain || saved.Output != *output {
+				return fmt.Errorf("checkpoint %s is for chain %s, output %s. Use a different -checkpoint file",
+					*checkpointFile, saved.Chain, saved.Output)
+			}
+			if saved.Height >= *start {
+				cp = saved
+				log.Infof("Resuming %s scan after height %d (%d swaps found so far).",
+					*chain, cp.Height, cp.Swaps)
+			}
+		}
 	}
-	if *end != 1<<32 {
-		height = int64(*end)
+	startHeight := cp.Height + 1
+	if startHeight > endHeight {
+		log.Infof("Nothing to scan. Checkpoint height %d, end height %d.", cp.Height, endHeight)
+		return nil
 	}
 
-	params := chaincfg.MainNetParams()
+	var sink swapSink
+	switch *output {
+	case outputDB:
+		dbHost, dbPort := *dbHostPort, ""
+		if !strings.HasPrefix(dbHost, "/") {
+			dbHost, dbPort, err = net.SplitHostPort(*dbHostPort)
+			if err != nil {
+				return fmt.Errorf("SplitHostPort failed: %w", err)
+			}
+		}
+		db, err := dcrpg.Connect(dbHost, dbPort, *dbUser, *dbPass, *dbName)
+		if err != nil {
+			return fmt.Errorf("unable to connect to PostgreSQL: %w", err)
+		}
+		sink, err = newDBSink(db, bg)
+		if err != nil {
+			db.Close()
+			return err
+		}
+	case outputJSONL:
+		sink, err = newJSONLSink(*jsonlFile, cp.Height >= cp.Start)
+		if err != nil {
+			return err
+		}
+	}
+	defer func() {
+		if err := sink.close(); err != nil {
+			log.Errorf("Failed to close %s output: %v", *output, err)
+		}
+	}()
 
-	err = csvwriter.Write([]string{"height", "type", "spend_tx", "spend_vin", "DCR",
-		"contract_tx", "contract_vout", "secret"})
+	log.Infof("Scanning %s blocks %d to %d with %d workers, writing to %s.",
+		*chain, startHeight, endHeight, *workers, *output)
+	err = runScan(ctx, scanner, sink, cp, endHeight)
+	if errors.Is(err, context.Canceled) {
+		log.Infof("Scan interrupted at height %d. Run again to resume.", cp.Height)
+		return nil
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "csvwriter.Write: %s\n", err)
-		return 6
+		return fmt.Errorf("scan stopped after height %d: %w", cp.Height, err)
 	}
+	log.Infof("Finished scanning %s up to height %d. %d swaps found.", *chain, cp.Height, cp.Swaps)
+	return nil
+}
 
-	var found bool
-	for i := int64(*start); i <= height; i++ {
-		blockhash, err := client.GetBlockHash(context.TODO(), i)
+// connectScanner connects to the node for the given chain. For Decred, the
+// node client is also returned for use by the database writer.
+func connectScanner(chainType string) (chainScanner, dcrpg.BlockGetter, error) {
+	switch chainType {
+	case mutilchain.TYPEDCR:
+		client, _, err := rpcutils.ConnectNodeRPC(*host, *user, *pass, *cert, *notls, false)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "ERROR: GetBlockHash(%d) failed: %v\n", i, err)
-			return 3
+			return nil, nil, fmt.Errorf("unable to connect to dcrd RPC server: %w", err)
 		}
-
-		msgBlock, err := client.GetBlock(context.TODO(), blockhash)
+		params := chaincfg.MainNetParams()
+		if *testnet {
+			params = chaincfg.TestNet3Params()
+		}
+		return &dcrScanner{client: client, params: params}, client, nil
+	case mutilchain.TYPEBTC:
+		client, _, err := btcrpcutils.ConnectNodeRPC(*host, *user, *pass, *cert, *notls, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to connect to btcd RPC server: %w", err)
+		}
+		params := &btcchaincfg.MainNetParams
+		if *testnet {
+			params = &btcchaincfg.TestNet3Params
+		}
+		return &btcScanner{client: client, params: params}, nil, nil
+	case mutilchain.TYPELTC:
+		client, _, err := ltcrpcutils.ConnectNodeRPC(*host, *user, *pass, *cert, *notls, false)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "ERROR: GetBlock failed (%s): %v\n", blockhash, err)
-			return 4
+			return nil, nil, fmt.Errorf("unable to connect to ltcd RPC server: %w", err)
 		}
+		params := &ltcchaincfg.MainNetParams
+		if *testnet {
+			params = &ltcchaincfg.TestNet4Params
+		}
+		return &ltcScanner{client: client, params: params}, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unsupported chain %q", chainType)
+}
 
-		if i%1000 == 0 {
-			fmt.Printf("%d\n", msgBlock.Header.Height)
+// runScan scans blocks cp.Height+1 through endHeight in parallel and writes
+// their swaps to sink in height order. The checkpoint is advanced as each block
+// is written and saved every checkpointEvery blocks and on return.
+func runScan(ctx context.Context, scanner chainScanner, sink swapSink, cp *checkpoint, endHeight int64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The window limits how far scanning may run ahead of the next block to
+	// be written, which bounds the number of blocks held in memory.
+	window := make(chan struct{}, *workers*4)
+	heights := make(chan int64)
+	results := make(chan *blockSwaps, *workers)
+	errChan := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case errChan <- err:
+		default:
 		}
+		cancel()
+	}
 
-		// Check all regular tree txns except coinbase.
-		for _, tx := range msgBlock.Transactions[1:] {
-			swapRes, err := txhelpers.MsgTxAtomicSwapsInfo(tx, nil, params)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "ERROR: %v", err)
-				return 5
-			}
-			if swapRes == nil || swapRes.Found == "" {
-				continue
+	go func() {
+		defer close(heights)
+		for h := cp.Height + 1; h <= endHeight; h++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
 			}
-			if !found {
-				fmt.Printf("First swap at height %d\n", i)
-				found = true
+			select {
+			case heights <- h:
+			case <-ctx.Done():
+				return
 			}
-			for vin, red := range swapRes.Redemptions {
-				fmt.Fprintf(outw, "Redeem (%s:%d): %v from contract output %v:%d\n",
-					red.SpendTx, red.SpendVin, dcrutil.Amount(red.Value), red.ContractTx, red.ContractVout)
-
-				// redeem tx, redeem vin, amt, contract tx, contract vout
-				err = csvwriter.Write([]string{
-					strconv.FormatInt(i, 10),
-					"redeem",
-					tx.TxHash().String(),
-					strconv.FormatInt(int64(vin), 10),
-					strconv.FormatFloat(dcrutil.Amount(red.Value).ToCoin(), 'f', -1, 64),
-					red.ContractTx.String(),
-					strconv.FormatInt(int64(red.ContractVout), 10),
-					hex.EncodeToString(red.Secret),
-				})
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for h := range heights {
+				bs, err := scanner.scanBlock(ctx, h)
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "csvwriter.Write: %s\n", err)
-					return 6
+					fail(fmt.Errorf("block %d: %w", h, err))
+					return
+				}
+				select {
+				case results <- bs:
+				case <-ctx.Done():
+					return
 				}
 			}
-			for vin, ref := range swapRes.Refunds {
-				fmt.Fprintf(outw, "Refund (%s:%d): %v from contract output %v:%d\n",
-					ref.SpendTx, ref.SpendVin, dcrutil.Amount(ref.Value), ref.ContractTx, ref.ContractVout)
-
-				err = csvwriter.Write([]string{
-					strconv.FormatInt(i, 10),
-					"refund",
-					tx.TxHash().String(),
-					strconv.FormatInt(int64(vin), 10),
-					strconv.FormatFloat(dcrutil.Amount(ref.Value).ToCoin(), 'f', -1, 64),
-					ref.ContractTx.String(),
-					strconv.FormatInt(int64(ref.ContractVout), 10),
-					"", // no secret with a refund
-				})
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "csvwriter.Write: %s\n", err)
-					return 6
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	save := func() error {
+		if err := sink.flush(); err != nil {
+			return err
+		}
+		return cp.save(*checkpointFile)
+	}
+
+	startTime, startHeight := time.Now(), cp.Height
+	pending := make(map[int64]*blockSwaps)
+	for bs := range results {
+		if ctx.Err() != nil {
+			continue // drain until the workers quit
+		}
+		pending[bs.height] = bs
+		for {
+			next, ok := pending[cp.Height+1]
+			if !ok {
+				break
+			}
+			delete(pending, next.height)
+			<-window
+			if err := sink.writeBlock(ctx, next); err != nil {
+				fail(err)
+				break
+			}
+			cp.Height = next.height
+			cp.Swaps += int64(len(next.swaps))
+			if len(next.swaps) > 0 {
+				log.Debugf("Found %d swaps in block %d (%s).", len(next.swaps), next.height, next.hash)
+			}
+			if (cp.Height-startHeight)%*checkpointEvery == 0 {
+				if err := save(); err != nil {
+					fail(fmt.Errorf("unable to save checkpoint: %w", err))
+					break
 				}
 			}
+			if cp.Height%1000 == 0 {
+				rate := float64(cp.Height-startHeight) / time.Since(startTime).Seconds()
+				log.Infof("Scanned to height %d (%.1f blocks/s), %d swaps found.", cp.Height, rate, cp.Swaps)
+			}
 		}
 	}
 
-	return 0
-}
+	if err := save(); err != nil {
+		log.Errorf("Unable to save checkpoint: %v", err)
+	}
 
-func main() {
-	os.Exit(mainCore())
+	select {
+	case err := <-errChan:
+		return err
+	default:
+	}
+	return ctx.Err()
 }
 
-func init() {
-	err := InitLogger()
-	if err != nil {
-		fmt.Printf("Unable to start logger: %v", err)
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := mainCore(ctx); err != nil {
+		log.Error(err)
 		os.Exit(1)
 	}
-
-	backendLog = slog.NewBackend(os.Stderr)
-	rpcclientLogger = backendLog.Logger("RPC")
-	rpcclient.UseLogger(rpcclientLogger)
-	rpcutils.UseLogger(rpcclientLogger)
 }
@@ -529,6 +529,7 @@ type ChartData struct {
 	Blocks       *ZoomSet
 	Windows      *windowSet
 	Days         *ZoomSet
+	UtxoSet      *UtxoSetSeries
 	cacheMtx     sync.RWMutex
 	cache        map[string]*cachedChart
 	updateMtx    sync.Mutex
@@ -1090,6 +1091,7 @@ func NewChartData(ctx context.Context, height uint32, chainParams *chaincfg.Para
 		Blocks:       newBlockSet(size),
 		Windows:      newWindowSet(windows),
 		Days:         newDaySet(days),
+		UtxoSet:      newUtxoSetSeries(),
 		cache:        make(map[string]*cachedChart),
 		updaters:     make([]ChartUpdater, 0),
 	}
@@ -1199,6 +1201,10 @@ var chartMakers = map[string]ChartMaker{
 	CoinAgeBands:      coinAgeBands,
 	MeanCoinAge:       meanCoinAge,
 	TotalCoinDays:     totalCoinDays,
+	UtxoCount:         utxoSetMaker(utxoCountChart),
+	UtxoValue:         utxoSetMaker(utxoValueChart),
+	UtxoSetSize:       utxoSetMaker(utxoSetSizeChart),
+	UtxoDust:          utxoSetMaker(utxoDustChart),
 }
 
 var customMakers = map[string]CustomUintsMaker{
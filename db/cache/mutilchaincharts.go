@@ -47,15 +47,22 @@ type MutilchainChartData struct {
 	APIHashrate         *ZoomSet
 	APIDifficulty       *ZoomSet
 	APIAddressCount     *ZoomSet
-	cacheMtx            sync.RWMutex
-	cache               map[string]*cachedChart
-	updateMtx           sync.Mutex
-	updaters            []ChartMutilchainUpdater
-	TimePerBlocks       float64
-	ChainType           string
-	UseSyncDB           bool
-	UseAPI              bool
-	LastUpdatedTime     time.Time
+	// CoinAge holds the coin age data of the BTC and LTC blocks, from the
+	// genesis block, and CoinAgeDays the data of the complete days. Both are
+	// nil for the other chains, like UtxoSet, the UTXO set snapshots.
+	CoinAge         *ZoomSet
+	CoinAgeDays     *ZoomSet
+	CoinAgeTipHash  string
+	UtxoSet         *UtxoSetSeries
+	cacheMtx        sync.RWMutex
+	cache           map[string]*cachedChart
+	updateMtx       sync.Mutex
+	updaters        []ChartMutilchainUpdater
+	TimePerBlocks   float64
+	ChainType       string
+	UseSyncDB       bool
+	UseAPI          bool
+	LastUpdatedTime time.Time
 }
 
 // Lengthen performs data validation and populates the Days zoomSet. If there is
@@ -370,6 +377,68 @@ func (charts *MutilchainChartData) PoolSizeTip() int32 {
 	return int32(len(charts.Blocks.PoolSize)) - 1
 }
 
+// CoinAgeTip is the height of the coin age data, -1 if there is none.
+func (charts *MutilchainChartData) CoinAgeTip() int32 {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	if charts.CoinAge == nil {
+		return -1
+	}
+	return int32(len(charts.CoinAge.Time)) - 1
+}
+
+// lengthenCoinAge populates the CoinAgeDays zoomSet from the CoinAge data, and
+// drops the cached coin age charts. The days are rebuilt from the start since
+// a reorg or a rebuild of the coin age tables may change any of them.
+func (charts *MutilchainChartData) lengthenCoinAge() {
+	charts.mtx.Lock()
+	defer charts.mtx.Unlock()
+	if charts.CoinAge == nil {
+		return
+	}
+
+	blocks := charts.CoinAge
+	days := newDaySet(cap(charts.CoinAgeDays.Time))
+	appendDay := func(s, e int, dayStart uint64) {
+		// Coin days destroyed are summed and the average age of the spent coins
+		// averaged. The rest describes the UTXO set at the end of the day.
+		days.Time = append(days.Time, dayStart)
+		days.Height = append(days.Height, blocks.Height[e-1])
+		days.CoinDaysDestroyed = append(days.CoinDaysDestroyed, blocks.CoinDaysDestroyed.Sum(s, e))
+		days.AvgCoinAge = append(days.AvgCoinAge, blocks.AvgCoinAge.Avg(s, e))
+		days.MeanCoinAge = append(days.MeanCoinAge, blocks.MeanCoinAge[e-1])
+		days.TotalCoinDays = append(days.TotalCoinDays, blocks.TotalCoinDays[e-1])
+		days.CoinAgeBands = append(days.CoinAgeBands, blocks.CoinAgeBands[e-1])
+	}
+	if len(blocks.Time) > 0 {
+		// The last day is not complete. Block times are not strictly increasing,
+		// so a block dated before the current day is kept in it.
+		end := midnight(blocks.Time[len(blocks.Time)-1])
+		start, startIdx := midnight(blocks.Time[0]), 0
+		for i, t := range blocks.Time {
+			if t < start+aDay {
+				continue
+			}
+			appendDay(startIdx, i, start)
+			start, startIdx = midnight(t), i
+			if start >= end {
+				break
+			}
+		}
+	}
+	charts.CoinAgeDays = days
+
+	charts.cacheMtx.Lock()
+	defer charts.cacheMtx.Unlock()
+	for _, chartID := range []string{AvgAgeDays, CoinDaysDestroyed, CoinAgeBands, MeanCoinAge, TotalCoinDays} {
+		for _, bin := range []binLevel{BlockBin, DayBin} {
+			for _, axis := range []axisType{HeightAxis, TimeAxis} {
+				delete(charts.cache, cacheKey(chartID, bin, axis, ""))
+			}
+		}
+	}
+}
+
 // AddUpdater adds a ChartUpdater to the Updaters slice. Updaters are run
 // sequentially during (*ChartData).Update.
 func (charts *MutilchainChartData) AddUpdater(updater ChartMutilchainUpdater) {
@@ -419,6 +488,7 @@ func (charts *MutilchainChartData) Update() error {
 	log.Debugf("Charts updaters complete at height %d in %f seconds.",
 		charts.Height(), time.Since(t).Seconds())
 	charts.LastUpdatedTime = time.Now()
+	charts.lengthenCoinAge()
 	// Since the charts db data query is complete. Update chart.Days derived dataset.
 	if err := charts.Lengthen(); err != nil {
 		return fmt.Errorf("(*ChartData).Lengthen failed: %v", err)
@@ -437,6 +507,9 @@ func NewLTCChartData(ctx context.Context, height uint32, chainParams *ltcchaincf
 		Days:            newDaySet(days),
 		cache:           make(map[string]*cachedChart),
 		updaters:        make([]ChartMutilchainUpdater, 0),
+		CoinAge:         newBlockSet(size),
+		CoinAgeDays:     newDaySet(days),
+		UtxoSet:         newUtxoSetSeries(),
 		TimePerBlocks:   float64(chainParams.TargetTimePerBlock),
 		ChainType:       mutilchain.TYPELTC,
 		LastBlockHeight: lastBlockHeight,
@@ -455,6 +528,9 @@ func NewBTCChartData(ctx context.Context, height uint32, chainParams *btcchaincf
 		Days:            newDaySet(days),
 		cache:           make(map[string]*cachedChart),
 		updaters:        make([]ChartMutilchainUpdater, 0),
+		CoinAge:         newBlockSet(size),
+		CoinAgeDays:     newDaySet(days),
+		UtxoSet:         newUtxoSetSeries(),
 		TimePerBlocks:   float64(chainParams.TargetTimePerBlock),
 		ChainType:       mutilchain.TYPEBTC,
 		LastBlockHeight: lastBlockHeight,
@@ -531,19 +607,28 @@ func (charts *MutilchainChartData) cacheChart(chartID string, bin binLevel, axis
 type MutilchainChartMaker func(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error)
 
 var mutilchainChartMaker = map[string]MutilchainChartMaker{
-	BlockSize:      MutilchainBlockSizeChart,
-	BlockChainSize: MutilchainBlockchainSizeChart,
-	CoinSupply:     MutilchainCoinSupplyChart,
-	DurationBTW:    MutilchainDurationBTWChart,
-	HashRate:       MutilchainHashRateChart,
-	POWDifficulty:  MutilchainDifficultyChart,
-	TxCount:        MutilchainTxCountChart,
-	Fees:           MutilchainFeesChart,
-	TxNumPerBlock:  MutilchainTxNumPerBlock,
-	MinedBlocks:    MutilchainMinedBlocks,
-	MempoolTxCount: MutilchainMempoolTxCount,
-	MempoolSize:    MutilchainMempoolSize,
-	AddressNumber:  MutilchainAddressNumber,
+	BlockSize:         MutilchainBlockSizeChart,
+	BlockChainSize:    MutilchainBlockchainSizeChart,
+	CoinSupply:        MutilchainCoinSupplyChart,
+	DurationBTW:       MutilchainDurationBTWChart,
+	HashRate:          MutilchainHashRateChart,
+	POWDifficulty:     MutilchainDifficultyChart,
+	TxCount:           MutilchainTxCountChart,
+	Fees:              MutilchainFeesChart,
+	TxNumPerBlock:     MutilchainTxNumPerBlock,
+	MinedBlocks:       MutilchainMinedBlocks,
+	MempoolTxCount:    MutilchainMempoolTxCount,
+	MempoolSize:       MutilchainMempoolSize,
+	AddressNumber:     MutilchainAddressNumber,
+	AvgAgeDays:        MutilchainAvgAgeDaysChart,
+	CoinDaysDestroyed: MutilchainCoinDaysDestroyedChart,
+	CoinAgeBands:      MutilchainCoinAgeBandsChart,
+	MeanCoinAge:       MutilchainMeanCoinAgeChart,
+	TotalCoinDays:     MutilchainTotalCoinDaysChart,
+	UtxoCount:         mutilchainUtxoSetMaker(utxoCountChart),
+	UtxoValue:         mutilchainUtxoSetMaker(utxoValueChart),
+	UtxoSetSize:       mutilchainUtxoSetMaker(utxoSetSizeChart),
+	UtxoDust:          mutilchainUtxoSetMaker(utxoDustChart),
 }
 
 var xmrChartMaker = map[string]MutilchainChartMaker{
@@ -1315,3 +1400,71 @@ func MutilchainFeesChart(charts *MutilchainChartData, bin binLevel, axis axisTyp
 	}
 	return nil, InvalidBinErr
 }
+
+// mutilchainCoinAgeChart encodes a coin age dataset, taken from the CoinAge or
+// CoinAgeDays zoomSet by data. Block-binned data begins at the genesis block,
+// so the height is implicit.
+func mutilchainCoinAgeChart(charts *MutilchainChartData, bin binLevel, axis axisType, key string, data func(*ZoomSet) lengther) ([]byte, error) {
+	if charts.CoinAge == nil {
+		return nil, UnknownChartErr
+	}
+	seed := binAxisSeed(bin, axis)
+	switch bin {
+	case BlockBin:
+		switch axis {
+		case HeightAxis:
+			return encode(lengtherMap{
+				key: data(charts.CoinAge),
+			}, seed)
+		default:
+			return encode(lengtherMap{
+				timeKey: charts.CoinAge.Time,
+				key:     data(charts.CoinAge),
+			}, seed)
+		}
+	case DayBin:
+		switch axis {
+		case HeightAxis:
+			return encode(lengtherMap{
+				heightKey: charts.CoinAgeDays.Height,
+				key:       data(charts.CoinAgeDays),
+			}, seed)
+		default:
+			return encode(lengtherMap{
+				timeKey: charts.CoinAgeDays.Time,
+				key:     data(charts.CoinAgeDays),
+			}, seed)
+		}
+	}
+	return nil, InvalidBinErr
+}
+
+func MutilchainAvgAgeDaysChart(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return mutilchainCoinAgeChart(charts, bin, axis, avgCoinAgeKey, func(set *ZoomSet) lengther {
+		return set.AvgCoinAge
+	})
+}
+
+func MutilchainCoinDaysDestroyedChart(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return mutilchainCoinAgeChart(charts, bin, axis, cddKey, func(set *ZoomSet) lengther {
+		return set.CoinDaysDestroyed
+	})
+}
+
+func MutilchainCoinAgeBandsChart(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return mutilchainCoinAgeChart(charts, bin, axis, ageBandKey, func(set *ZoomSet) lengther {
+		return set.CoinAgeBands
+	})
+}
+
+func MutilchainMeanCoinAgeChart(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return mutilchainCoinAgeChart(charts, bin, axis, meanCoinAgeKey, func(set *ZoomSet) lengther {
+		return set.MeanCoinAge
+	})
+}
+
+func MutilchainTotalCoinDaysChart(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+	return mutilchainCoinAgeChart(charts, bin, axis, totalCoinDaysKey, func(set *ZoomSet) lengther {
+		return set.TotalCoinDays
+	})
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package cache
+
+// Chart IDs of the UTXO set charts of the DCR, BTC and LTC chains.
+const (
+	UtxoCount   = "utxo-count"
+	UtxoValue   = "utxo-value"
+	UtxoSetSize = "utxo-set-size"
+	UtxoDust    = "utxo-dust"
+)
+
+// Keys of the UTXO set chart data.
+const (
+	utxoCountKey     = "count"
+	utxoValueKey     = "value"
+	utxoSizeKey      = "size"
+	utxoDustCountKey = "dust_count"
+	utxoDustValueKey = "dust_value"
+)
+
+var utxoSetChartIDs = []string{UtxoCount, UtxoValue, UtxoSetSize, UtxoDust}
+
+// UtxoSetSeries is the time series of the UTXO set snapshots of a chain, taken
+// every so many blocks. The values are in coins, and the sizes in bytes.
+type UtxoSetSeries struct {
+	Height    ChartUints
+	Time      ChartUints
+	Count     ChartUints
+	Value     ChartFloats
+	Size      ChartUints
+	DustCount ChartUints
+	DustValue ChartFloats
+	// TipHash is the hash of the block of the last snapshot.
+	TipHash string
+}
+
+func newUtxoSetSeries() *UtxoSetSeries {
+	return new(UtxoSetSeries)
+}
+
+// Tip is the height of the last snapshot, -1 if there is none.
+func (s *UtxoSetSeries) Tip() int64 {
+	if len(s.Height) == 0 {
+		return -1
+	}
+	return int64(s.Height[len(s.Height)-1])
+}
+
+// Append appends a snapshot.
+func (s *UtxoSetSeries) Append(height, t, count uint64, value float64, size, dustCount uint64, dustValue float64) {
+	s.Height = append(s.Height, height)
+	s.Time = append(s.Time, t)
+	s.Count = append(s.Count, count)
+	s.Value = append(s.Value, value)
+	s.Size = append(s.Size, size)
+	s.DustCount = append(s.DustCount, dustCount)
+	s.DustValue = append(s.DustValue, dustValue)
+}
+
+// SnipFrom drops the snapshots at or above the height.
+func (s *UtxoSetSeries) SnipFrom(height uint64) {
+	n := len(s.Height)
+	for n > 0 && s.Height[n-1] >= height {
+		n--
+	}
+	s.Height = s.Height[:n]
+	s.Time = s.Time[:n]
+	s.Count = s.Count[:n]
+	s.Value = s.Value[:n]
+	s.Size = s.Size[:n]
+	s.DustCount = s.DustCount[:n]
+	s.DustValue = s.DustValue[:n]
+	if n == 0 {
+		s.TipHash = ""
+	}
+}
+
+// utxoSetChart encodes the data sets of the UTXO set series. The snapshots are
+// not binned, so the block and day bins give the same data, with the height or
+// time of the snapshots.
+func utxoSetChart(s *UtxoSetSeries, bin binLevel, axis axisType, data lengtherMap) ([]byte, error) {
+	if bin != BlockBin && bin != DayBin {
+		return nil, InvalidBinErr
+	}
+	if axis == HeightAxis {
+		data[heightKey] = s.Height
+	} else {
+		data[timeKey] = s.Time
+	}
+	return encode(data, binAxisSeed(bin, axis))
+}
+
+func utxoCountChart(s *UtxoSetSeries, bin binLevel, axis axisType) ([]byte, error) {
+	if s == nil {
+		return nil, UnknownChartErr
+	}
+	return utxoSetChart(s, bin, axis, lengtherMap{
+		utxoCountKey:     s.Count,
+		utxoDustCountKey: s.DustCount,
+	})
+}
+
+func utxoValueChart(s *UtxoSetSeries, bin binLevel, axis axisType) ([]byte, error) {
+	if s == nil {
+		return nil, UnknownChartErr
+	}
+	return utxoSetChart(s, bin, axis, lengtherMap{
+		utxoValueKey: s.Value,
+	})
+}
+
+func utxoSetSizeChart(s *UtxoSetSeries, bin binLevel, axis axisType) ([]byte, error) {
+	if s == nil {
+		return nil, UnknownChartErr
+	}
+	return utxoSetChart(s, bin, axis, lengtherMap{
+		utxoSizeKey: s.Size,
+	})
+}
+
+func utxoDustChart(s *UtxoSetSeries, bin binLevel, axis axisType) ([]byte, error) {
+	if s == nil {
+		return nil, UnknownChartErr
+	}
+	return utxoSetChart(s, bin, axis, lengtherMap{
+		utxoDustCountKey: s.DustCount,
+		utxoDustValueKey: s.DustValue,
+	})
+}
+
+// utxoSetMaker makes the ChartMaker of a UTXO set chart of the DCR charts.
+func utxoSetMaker(chart func(*UtxoSetSeries, binLevel, axisType) ([]byte, error)) ChartMaker {
+	return func(charts *ChartData, bin binLevel, axis axisType, _ string) ([]byte, error) {
+		return chart(charts.UtxoSet, bin, axis)
+	}
+}
+
+// mutilchainUtxoSetMaker makes the MutilchainChartMaker of a UTXO set chart of
+// the BTC and LTC charts.
+func mutilchainUtxoSetMaker(chart func(*UtxoSetSeries, binLevel, axisType) ([]byte, error)) MutilchainChartMaker {
+	return func(charts *MutilchainChartData, bin binLevel, axis axisType) ([]byte, error) {
+		return chart(charts.UtxoSet, bin, axis)
+	}
+}
+
+// UtxoSetTip is the height and block hash of the last UTXO set snapshot of the
+// charts, -1 if there is none.
+func (charts *ChartData) UtxoSetTip() (int64, string) {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	return charts.UtxoSet.Tip(), charts.UtxoSet.TipHash
+}
+
+// DropUtxoSetCharts drops the cached UTXO set charts, which do not follow the
+// day bins of the other charts.
+func (charts *ChartData) DropUtxoSetCharts() {
+	charts.cacheMtx.Lock()
+	defer charts.cacheMtx.Unlock()
+	dropUtxoSetCharts(charts.cache)
+}
+
+// UtxoSetTip is the height and block hash of the last UTXO set snapshot of the
+// charts, -1 if there is none.
+func (charts *MutilchainChartData) UtxoSetTip() (int64, string) {
+	charts.mtx.RLock()
+	defer charts.mtx.RUnlock()
+	if charts.UtxoSet == nil {
+		return -1, ""
+	}
+	return charts.UtxoSet.Tip(), charts.UtxoSet.TipHash
+}
+
+// DropUtxoSetCharts drops the cached UTXO set charts, which do not follow the
+// day bins of the other charts.
+func (charts *MutilchainChartData) DropUtxoSetCharts() {
+	charts.cacheMtx.Lock()
+	defer charts.cacheMtx.Unlock()
+	dropUtxoSetCharts(charts.cache)
+}
+
+func dropUtxoSetCharts(cache map[string]*cachedChart) {
+	for _, chartID := range utxoSetChartIDs {
+		for _, bin := range []binLevel{BlockBin, DayBin} {
+			for _, axis := range []axisType{HeightAxis, TimeAxis} {
+				delete(cache, cacheKey(chartID, bin, axis, ""))
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+const (
+	// APIKeyPrefix starts every API key, which makes leaked keys easy to
+	// recognize.
+	APIKeyPrefix = "dcrdata_"
+
+	// apiKeyEntropy is the number of random bytes of an API key.
+	apiKeyEntropy = 32
+
+	// apiKeyDisplayLen is the length of the start of a key that is stored in
+	// the clear to identify it in listings.
+	apiKeyDisplayLen = len(APIKeyPrefix) + 8
+)
+
+// APIKey is an API key as stored in the api_keys table. The key itself is not
+// stored, only its SHA-256 hash and its first few characters.
+type APIKey struct {
+	ID      int64
+	Name    string
+	Display string
+	// RateLimit is the sustained number of requests per second allowed for
+	// the key, and Burst the number of requests that may exceed it at once.
+	// A zero RateLimit does not limit the rate of requests.
+	RateLimit float64
+	Burst     int
+	// DailyQuota is the number of requests allowed per UTC day, or zero for
+	// no quota.
+	DailyQuota int64
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// Revoked checks if the key was revoked.
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// APIKeyUsage is the number of requests to an endpoint made with an API key
+// on a UTC day.
+type APIKeyUsage struct {
+	KeyID    int64
+	Day      time.Time
+	Endpoint string
+	Count    int64
+}
+
+// NewAPIKey generates a random API key. The returned key is shown once to its
+// owner. Only its hash, from HashAPIKey, and APIKeyDisplay should be stored.
+func NewAPIKey() (string, error) {
+	b := make([]byte, apiKeyEntropy)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of an API key, which is how
+// keys are looked up.
+func HashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// APIKeyDisplay returns the start of an API key, which identifies the key
+// without revealing it.
+func APIKeyDisplay(key string) string {
+	if len(key) <= apiKeyDisplayLen {
+		return key
+	}
+	return key[:apiKeyDisplayLen]
+}
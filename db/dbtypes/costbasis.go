@@ -0,0 +1,248 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ledgerAtomsPerCoin converts the atoms of the address tables to coins, for
+// DCR, BTC and LTC alike.
+const ledgerAtomsPerCoin = 1e8
+
+// LedgerTx is the net effect of a mainchain transaction on a set of addresses
+// of a chain. Received and Sent are the atoms of the outputs paid to and spent
+// from the addresses, Fees and Spent the fees and total input atoms of the
+// whole transaction. ReceivedLinks are the transactions spending the received
+// outputs, and SentLinks the transactions funding the spent outputs.
+type LedgerTx struct {
+	Chain         string
+	TxHash        string
+	Time          int64
+	Received      int64
+	Sent          int64
+	Fees          int64
+	Spent         int64
+	ReceivedLinks []string
+	SentLinks     []string
+}
+
+// CostBasisMethod is the order in which the acquired lots of coins are
+// matched to the disposals.
+type CostBasisMethod string
+
+const (
+	CostBasisFIFO CostBasisMethod = "fifo"
+	CostBasisLIFO CostBasisMethod = "lifo"
+)
+
+// CostBasisMethodFromStr parses a cost basis method, with FIFO as the
+// default.
+func CostBasisMethodFromStr(s string) (CostBasisMethod, error) {
+	switch CostBasisMethod(strings.ToLower(s)) {
+	case "", CostBasisFIFO:
+		return CostBasisFIFO, nil
+	case CostBasisLIFO:
+		return CostBasisLIFO, nil
+	}
+	return "", fmt.Errorf("unknown cost basis method %q", s)
+}
+
+// LedgerEntry is a row of a cost basis report. Direction is "in" for the
+// acquisitions and "out" for the disposals. Amount is the net amount of coins,
+// with the FeeShare of the disposals included, i.e. the share of the
+// transaction fee in proportion of the inputs spent from the addresses.
+// Counterparty lists the linked transactions of the direction. CostBasis and
+// Gain are only set for the disposals, whose proceeds are the fiat value of
+// the amount less the fee share.
+type LedgerEntry struct {
+	Chain        string  `json:"chain"`
+	TxHash       string  `json:"tx_hash"`
+	Time         int64   `json:"time"`
+	Direction    string  `json:"direction"`
+	Amount       float64 `json:"amount"`
+	FeeShare     float64 `json:"fee_share"`
+	Counterparty string  `json:"counterparty_tx_hashes"`
+	Price        float64 `json:"price"`
+	FiatValue    float64 `json:"fiat_value"`
+	CostBasis    float64 `json:"cost_basis"`
+	Gain         float64 `json:"realized_gain"`
+}
+
+// CostBasisSummary sums up the entries of a chain in the range of a cost
+// basis report. The opening and closing amounts and cost bases are those of
+// the lots held before and after the range. Unmatched is the amount of coins
+// disposed of in excess of the held lots, with no cost basis, and Unpriced the
+// number of entries without a daily price, valued at zero.
+type CostBasisSummary struct {
+	Chain          string          `json:"chain"`
+	Currency       string          `json:"currency"`
+	Method         CostBasisMethod `json:"method"`
+	From           int64           `json:"from"`
+	To             int64           `json:"to"`
+	OpeningAmount  float64         `json:"opening_amount"`
+	OpeningCost    float64         `json:"opening_cost_basis"`
+	AcquiredAmount float64         `json:"acquired_amount"`
+	AcquiredCost   float64         `json:"acquired_cost"`
+	DisposedAmount float64         `json:"disposed_amount"`
+	Proceeds       float64         `json:"proceeds"`
+	DisposedCost   float64         `json:"disposed_cost_basis"`
+	RealizedGain   float64         `json:"realized_gain"`
+	Fees           float64         `json:"fees"`
+	ClosingAmount  float64         `json:"closing_amount"`
+	ClosingCost    float64         `json:"closing_cost_basis"`
+	Unmatched      float64         `json:"unmatched_amount"`
+	Unpriced       int             `json:"unpriced_entries"`
+}
+
+// costLot is an acquired amount of coins and its remaining cost basis.
+type costLot struct {
+	amount float64
+	cost   float64
+}
+
+// lotQueue holds the lots of coins in the order of acquisition.
+type lotQueue struct {
+	lots   []costLot
+	method CostBasisMethod
+}
+
+func (q *lotQueue) add(amount, cost float64) {
+	if amount > 0 {
+		q.lots = append(q.lots, costLot{amount, cost})
+	}
+}
+
+// take removes an amount of coins from the lots, the oldest or newest first
+// depending on the method, and returns their cost basis and the amount that
+// was not held.
+func (q *lotQueue) take(amount float64) (cost, unmatched float64) {
+	for amount > 0 && len(q.lots) > 0 {
+		i := 0
+		if q.method == CostBasisLIFO {
+			i = len(q.lots) - 1
+		}
+		lot := &q.lots[i]
+		if lot.amount > amount {
+			part := lot.cost * amount / lot.amount
+			lot.cost -= part
+			lot.amount -= amount
+			return cost + part, 0
+		}
+		cost += lot.cost
+		amount -= lot.amount
+		if q.method == CostBasisLIFO {
+			q.lots = q.lots[:i]
+		} else {
+			q.lots = q.lots[1:]
+		}
+	}
+	return cost, amount
+}
+
+func (q *lotQueue) holdings() (amount, cost float64) {
+	for _, lot := range q.lots {
+		amount += lot.amount
+		cost += lot.cost
+	}
+	return
+}
+
+// CostBasisReport matches the disposals of the ledger transactions to the
+// lots acquired before them with the method, chain by chain, valued with the
+// daily prices of each chain. The transactions of each chain must be in
+// ascending order of time, and include those before from for the lots held at
+// the start of the range. The entries of the Unix time range [from, to] are
+// returned in order of time, and the summaries in order of chain.
+func CostBasisReport(txs []*LedgerTx, prices map[string]*FiatPrices, method CostBasisMethod,
+	from, to int64) ([]*LedgerEntry, []*CostBasisSummary) {
+	queues := make(map[string]*lotQueue)
+	summaries := make(map[string]*CostBasisSummary)
+	var entries []*LedgerEntry
+	for _, tx := range txs {
+		if tx.Time > to {
+			continue
+		}
+		q := queues[tx.Chain]
+		if q == nil {
+			q = &lotQueue{method: method}
+			queues[tx.Chain] = q
+		}
+		s := summaries[tx.Chain]
+		if s == nil {
+			s = &CostBasisSummary{Chain: tx.Chain, Method: method, From: from, To: to}
+			if p := prices[tx.Chain]; p != nil {
+				s.Currency = p.Currency
+			}
+			summaries[tx.Chain] = s
+		}
+		net := tx.Received - tx.Sent
+		if net == 0 {
+			continue
+		}
+		entry := &LedgerEntry{
+			Chain:  tx.Chain,
+			TxHash: tx.TxHash,
+			Time:   tx.Time,
+			Amount: float64(net) / ledgerAtomsPerCoin,
+			Price:  prices[tx.Chain].PriceAt(tx.Time),
+		}
+		if tx.Sent > 0 && tx.Spent > 0 {
+			entry.FeeShare = float64(tx.Fees) * float64(tx.Sent) / float64(tx.Spent) / ledgerAtomsPerCoin
+		}
+		if net > 0 {
+			entry.Direction = "in"
+			entry.Counterparty = strings.Join(tx.ReceivedLinks, " ")
+			entry.FiatValue = entry.Amount * entry.Price
+			q.add(entry.Amount, entry.FiatValue)
+		} else {
+			entry.Direction = "out"
+			entry.Counterparty = strings.Join(tx.SentLinks, " ")
+			entry.Amount = -entry.Amount
+			entry.FiatValue = (entry.Amount - entry.FeeShare) * entry.Price
+			var unmatched float64
+			entry.CostBasis, unmatched = q.take(entry.Amount)
+			entry.Gain = entry.FiatValue - entry.CostBasis
+			if tx.Time >= from {
+				s.Unmatched += unmatched
+			}
+		}
+
+		if tx.Time < from {
+			s.OpeningAmount, s.OpeningCost = q.holdings()
+			continue
+		}
+		if entry.Price == 0 {
+			s.Unpriced++
+		}
+		if entry.Direction == "in" {
+			s.AcquiredAmount += entry.Amount
+			s.AcquiredCost += entry.FiatValue
+		} else {
+			s.DisposedAmount += entry.Amount
+			s.Proceeds += entry.FiatValue
+			s.DisposedCost += entry.CostBasis
+			s.RealizedGain += entry.Gain
+			s.Fees += entry.FeeShare
+		}
+		entries = append(entries, entry)
+	}
+
+	chains := make([]string, 0, len(summaries))
+	for chain, s := range summaries {
+		s.ClosingAmount, s.ClosingCost = queues[chain].holdings()
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	sums := make([]*CostBasisSummary, 0, len(chains))
+	for _, chain := range chains {
+		sums = append(sums, summaries[chain])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time < entries[j].Time
+	})
+	return entries, sums
+}
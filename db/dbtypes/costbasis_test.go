@@ -0,0 +1,68 @@
+package dbtypes
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCostBasisReport(t *testing.T) {
+	const day = 86400
+	prices := map[string]*FiatPrices{
+		"dcr": {
+			Currency: "USD",
+			Prices: []DailyPrice{
+				{Date: 1 * day, Price: 10},
+				{Date: 2 * day, Price: 20},
+				{Date: 3 * day, Price: 30},
+			},
+		},
+	}
+	// 2 DCR bought at 10, 2 DCR at 20, then 3 DCR spent at 30 with a fee of
+	// 0.1 DCR paid from all the inputs of the addresses.
+	txs := []*LedgerTx{
+		{Chain: "dcr", TxHash: "a", Time: 1*day + 60, Received: 2e8},
+		{Chain: "dcr", TxHash: "b", Time: 2*day + 60, Received: 2e8},
+		{Chain: "dcr", TxHash: "c", Time: 3*day + 60, Received: 1e8, Sent: 4e8, Fees: 1e7, Spent: 4e8,
+			SentLinks: []string{"a", "b"}},
+	}
+
+	tests := []struct {
+		method    CostBasisMethod
+		from      int64
+		entries   int
+		costBasis float64
+		opening   float64
+		closing   float64
+	}{
+		{CostBasisFIFO, 0, 3, 2*10 + 20, 0, 20},
+		{CostBasisLIFO, 0, 3, 2*20 + 10, 0, 10},
+		{CostBasisFIFO, 2 * day, 2, 2*10 + 20, 20, 20},
+	}
+	for _, tt := range tests {
+		entries, sums := CostBasisReport(txs, prices, tt.method, tt.from, 4*day)
+		if len(entries) != tt.entries || len(sums) != 1 {
+			t.Fatalf("%s from %d: %d entries, %d summaries", tt.method, tt.from, len(entries), len(sums))
+		}
+		out := entries[len(entries)-1]
+		if out.Direction != "out" || out.Amount != 3 || out.Counterparty != "a b" {
+			t.Errorf("%s: disposal %+v", tt.method, out)
+		}
+		if math.Abs(out.FeeShare-0.1) > 1e-9 || math.Abs(out.FiatValue-2.9*30) > 1e-9 {
+			t.Errorf("%s: fee share %f, proceeds %f", tt.method, out.FeeShare, out.FiatValue)
+		}
+		if math.Abs(out.CostBasis-tt.costBasis) > 1e-9 || math.Abs(out.Gain-(87-tt.costBasis)) > 1e-9 {
+			t.Errorf("%s: cost basis %f, gain %f", tt.method, out.CostBasis, out.Gain)
+		}
+		s := sums[0]
+		if s.Currency != "USD" || s.OpeningCost != tt.opening || s.ClosingAmount != 1 || s.ClosingCost != tt.closing {
+			t.Errorf("%s from %d: summary %+v", tt.method, tt.from, s)
+		}
+		if s.Unmatched != 0 || s.Unpriced != 0 {
+			t.Errorf("%s: unmatched %f, unpriced %d", tt.method, s.Unmatched, s.Unpriced)
+		}
+	}
+
+	if _, err := CostBasisMethodFromStr("hifo"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
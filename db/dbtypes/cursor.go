@@ -0,0 +1,130 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math"
+	"time"
+)
+
+// StakeTreeTxIndexOffset is added to the block index of stake tree
+// transactions to form the TxIndex of a PageCursor, so that stake transactions
+// sort after the regular transactions of their block.
+const StakeTreeTxIndexOffset = 1 << 16
+
+// pageCursorVersion is the version of the PageCursor encoding.
+const pageCursorVersion = 2
+
+// numCursorFields is the number of integer fields of a PageCursor.
+const numCursorFields = 4
+
+// ErrInvalidCursor is returned when decoding a malformed page cursor.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// PageCursor identifies the last item of a page of a list in descending order
+// of an indexed key. The next page starts with the item that follows the
+// cursor, so that pages remain stable when new blocks are connected. Items of
+// blocks that are orphaned by a reorg are not repeated or skipped, since the
+// position of the cursor does not depend on them.
+//
+// Each list sets only the fields of its key. Lists of transactions are ordered
+// by (Height, TxIndex), address rows by (Time, ID), which are the block time
+// in seconds and the row ID, and swap groups by (Time, Hash), which are the
+// contract time and the group transaction hash.
+type PageCursor struct {
+	Height  int64
+	TxIndex int64
+	Time    int64
+	ID      int64
+	Hash    string
+}
+
+// NewAddressRowCursor creates the cursor of the address row with the specified
+// block time and row ID.
+func NewAddressRowCursor(blockTime time.Time, rowID uint64) *PageCursor {
+	return &PageCursor{
+		Time: blockTime.Unix(),
+		ID:   int64(rowID),
+	}
+}
+
+// String encodes the cursor as an opaque URL-safe string. The Hash must be
+// empty or a hex encoded 32-byte hash.
+func (c *PageCursor) String() string {
+	b := make([]byte, 0, 1+numCursorFields*binary.MaxVarintLen64+32)
+	b = append(b, pageCursorVersion)
+	for _, v := range [numCursorFields]int64{c.Height, c.TxIndex, c.Time, c.ID} {
+		b = binary.AppendUvarint(b, uint64(v))
+	}
+	if hash, err := hex.DecodeString(c.Hash); err == nil && len(hash) == 32 {
+		b = append(b, hash...)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePageCursor decodes a cursor encoded with PageCursor.String.
+func DecodePageCursor(s string) (*PageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 || b[0] != pageCursorVersion {
+		return nil, ErrInvalidCursor
+	}
+	b = b[1:]
+	var fields [numCursorFields]int64
+	for i := range fields {
+		v, n := binary.Uvarint(b)
+		if n <= 0 || v > 1<<62 {
+			return nil, ErrInvalidCursor
+		}
+		fields[i] = int64(v)
+		b = b[n:]
+	}
+	var hash string
+	switch len(b) {
+	case 0:
+	case 32:
+		hash = hex.EncodeToString(b)
+	default:
+		return nil, ErrInvalidCursor
+	}
+	return &PageCursor{
+		Height:  fields[0],
+		TxIndex: fields[1],
+		Time:    fields[2],
+		ID:      fields[3],
+		Hash:    hash,
+	}, nil
+}
+
+// ParsePageCursor parses the cursor query parameter of a list endpoint. An
+// empty value is the cursor of the first page, which precedes all items.
+func ParsePageCursor(s string) (*PageCursor, error) {
+	if s == "" {
+		return &PageCursor{
+			Height: math.MaxInt64,
+			Time:   math.MaxInt64,
+			ID:     math.MaxInt64,
+		}, nil
+	}
+	return DecodePageCursor(s)
+}
+
+// CursorTime returns the Time of the cursor as a time.Time. The Time of the
+// first page cursor is clamped to a time that follows every block.
+func (c *PageCursor) CursorTime() time.Time {
+	const maxCursorTime = 1 << 40 // year 36812
+	return time.Unix(min(c.Time, maxCursorTime), 0)
+}
+
+// NextPageCursor returns the encoded cursor of the last of numItems items of a
+// page of the specified size, or an empty string if the page is the last one.
+func NextPageCursor(last *PageCursor, numItems, pageSize int) string {
+	if last == nil || numItems < pageSize {
+		return ""
+	}
+	return last.String()
+}
@@ -0,0 +1,66 @@
+package dbtypes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPageCursorRoundTrip(t *testing.T) {
+	cursors := []PageCursor{
+		{},
+		{Height: 1, TxIndex: 2},
+		{Height: 899123, TxIndex: StakeTreeTxIndexOffset + 7},
+		{Time: 1712345678, ID: 123456789},
+		{Time: 1712345678, Hash: "6d0d4f9ea2d8fba29a2e5b58d8b8a1ef2a6e6d0df16ba3a06fbea1e2ae6bd1f3"},
+	}
+	for _, c := range cursors {
+		s := c.String()
+		got, err := DecodePageCursor(s)
+		if err != nil {
+			t.Fatalf("DecodePageCursor(%q) failed: %v", s, err)
+		}
+		if *got != c {
+			t.Errorf("DecodePageCursor(%q) = %+v, want %+v", s, *got, c)
+		}
+	}
+}
+
+func TestDecodePageCursorInvalid(t *testing.T) {
+	valid := (&PageCursor{Height: 10, TxIndex: 1, Time: 2, ID: 3}).String()
+	withHash := (&PageCursor{Time: 2, Hash: strings.Repeat("ab", 32)}).String()
+	for _, s := range []string{
+		"",
+		"not base64!",
+		"AA",                       // wrong version
+		valid[:len(valid)-2],       // truncated
+		valid + "AA",               // trailing bytes
+		withHash[:len(withHash)-2], // truncated hash
+	} {
+		if _, err := DecodePageCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodePageCursor(%q): expected ErrInvalidCursor, got %v", s, err)
+		}
+	}
+}
+
+func TestParsePageCursor(t *testing.T) {
+	first, err := ParsePageCursor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := &PageCursor{Height: 1 << 40, TxIndex: StakeTreeTxIndexOffset, Time: 1 << 33, ID: 1 << 40}
+	if first.Height <= last.Height || first.Time <= last.Time || first.ID <= last.ID {
+		t.Errorf("first page cursor %+v does not precede %+v", *first, *last)
+	}
+	got, err := ParsePageCursor(last.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *last {
+		t.Errorf("ParsePageCursor = %+v, want %+v", *got, *last)
+	}
+	if !first.CursorTime().After(time.Unix(last.Time, 0)) {
+		t.Errorf("first page cursor time %v does not follow %v", first.CursorTime(), time.Unix(last.Time, 0))
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the file format of a data export.
+type ExportFormat string
+
+const (
+	ExportCSV    ExportFormat = "csv"
+	ExportNDJSON ExportFormat = "ndjson"
+)
+
+// ExportFormatFromStr parses an export format, with CSV as the default.
+func ExportFormatFromStr(s string) (ExportFormat, error) {
+	switch ExportFormat(s) {
+	case "", ExportCSV:
+		return ExportCSV, nil
+	case ExportNDJSON:
+		return ExportNDJSON, nil
+	}
+	return "", fmt.Errorf("unknown export format %q", s)
+}
+
+// ContentType is the MIME type of the export format.
+func (f ExportFormat) ContentType() string {
+	if f == ExportNDJSON {
+		return "application/x-ndjson; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportWriter writes the rows of a data export. WriteHeader is called once
+// with the column names before the rows, and the values of each row are in
+// the same order as the columns. The values are those of database/sql scans
+// into interface values, i.e. nil, int64, float64, bool, []byte, string or
+// time.Time.
+type ExportWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []any) error
+	Flush() error
+}
+
+// NewExportWriter creates an ExportWriter of the specified format. The CRLF
+// line ending is only used for CSV.
+func NewExportWriter(w io.Writer, format ExportFormat, crlf bool) ExportWriter {
+	if format == ExportNDJSON {
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}
+	}
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = crlf
+	return &csvExportWriter{w: cw}
+}
+
+type csvExportWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (cw *csvExportWriter) WriteHeader(columns []string) error {
+	cw.record = make([]string, len(columns))
+	return cw.w.Write(columns)
+}
+
+func (cw *csvExportWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		cw.record[i] = exportValueString(v)
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvExportWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func exportValueString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return strconv.FormatInt(v.Unix(), 10)
+	}
+	return fmt.Sprint(v)
+}
+
+type ndjsonExportWriter struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+func (nw *ndjsonExportWriter) WriteHeader(columns []string) error {
+	nw.columns = columns
+	return nil
+}
+
+func (nw *ndjsonExportWriter) WriteRow(values []any) error {
+	// A json.Marshaler preserves the column order, unlike a map.
+	return nw.enc.Encode(exportRow{nw.columns, values})
+}
+
+func (nw *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+type exportRow struct {
+	columns []string
+	values  []any
+}
+
+func (er exportRow) MarshalJSON() ([]byte, error) {
+	b := []byte{'{'}
+	for i, col := range er.columns {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendQuote(b, col)
+		b = append(b, ':')
+		v := er.values[i]
+		switch t := v.(type) {
+		case []byte:
+			v = string(t)
+		case time.Time:
+			v = t.Unix()
+		}
+		vb, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, vb...)
+	}
+	return append(b, '}'), nil
+}
@@ -0,0 +1,58 @@
+package dbtypes
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestExportWriter(t *testing.T) {
+	columns := []string{"tx_hash", "value", "price", "is_funding", "time", "memo"}
+	rows := [][]any{
+		{[]byte("ab01"), int64(-5), 1.5, true, time.Unix(1700000000, 0), nil},
+		{"cd02", int64(7), nil, false, time.Unix(1700000001, 0), `a "quoted", text`},
+	}
+	tests := []struct {
+		format ExportFormat
+		crlf   bool
+		want   string
+	}{
+		{ExportCSV, false, "tx_hash,value,price,is_funding,time,memo\n" +
+			"ab01,-5,1.5,1,1700000000,\n" +
+			"cd02,7,,0,1700000001,\"a \"\"quoted\"\", text\"\n"},
+		{ExportCSV, true, "tx_hash,value,price,is_funding,time,memo\r\n" +
+			"ab01,-5,1.5,1,1700000000,\r\n" +
+			"cd02,7,,0,1700000001,\"a \"\"quoted\"\", text\"\r\n"},
+		{ExportNDJSON, true, `{"tx_hash":"ab01","value":-5,"price":1.5,"is_funding":true,"time":1700000000,"memo":null}` + "\n" +
+			`{"tx_hash":"cd02","value":7,"price":null,"is_funding":false,"time":1700000001,"memo":"a \"quoted\", text"}` + "\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		ew := NewExportWriter(&buf, tt.format, tt.crlf)
+		if err := ew.WriteHeader(columns); err != nil {
+			t.Fatal(err)
+		}
+		for _, row := range rows {
+			if err := ew.WriteRow(row); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := ew.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s export (crlf=%v):\ngot  %q\nwant %q", tt.format, tt.crlf, got, tt.want)
+		}
+	}
+}
+
+func TestExportFormatFromStr(t *testing.T) {
+	for s, want := range map[string]ExportFormat{"": ExportCSV, "csv": ExportCSV, "ndjson": ExportNDJSON} {
+		if got, err := ExportFormatFromStr(s); err != nil || got != want {
+			t.Errorf("ExportFormatFromStr(%q) = %q, %v; want %q", s, got, err, want)
+		}
+	}
+	if _, err := ExportFormatFromStr("xlsx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
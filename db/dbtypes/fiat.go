@@ -0,0 +1,50 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxPriceAge is how long before a time its latest daily price may be, in
+// seconds, for the price to apply. It allows for the days not backfilled yet.
+const maxPriceAge = 3 * 86400
+
+// DailyPrice is the closing price of a coin on a UTC day in a fiat currency.
+// Date is the Unix time of the start of the day.
+type DailyPrice struct {
+	Date  int64   `json:"date"`
+	Price float64 `json:"price"`
+}
+
+// FiatPrices are the daily prices of the coin of a chain in a fiat currency,
+// in ascending order of date.
+type FiatPrices struct {
+	Chain    string
+	Currency string
+	Prices   []DailyPrice
+}
+
+// PriceAt is the price of the day of the Unix time t, or of the latest day
+// before it with a price. It is zero if there is no price for the last three
+// days before t.
+func (p *FiatPrices) PriceAt(t int64) float64 {
+	if p == nil {
+		return 0
+	}
+	i := sort.Search(len(p.Prices), func(i int) bool {
+		return p.Prices[i].Date > t
+	})
+	if i == 0 || t-p.Prices[i-1].Date > maxPriceAge {
+		return 0
+	}
+	return p.Prices[i-1].Price
+}
+
+// NormalizeCurrency converts a currency code to the upper case codes of the
+// daily_price table.
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
@@ -0,0 +1,64 @@
+package dbtypes
+
+import "testing"
+
+func TestFiatPricesPriceAt(t *testing.T) {
+	const day = 86400
+	prices := &FiatPrices{
+		Currency: "USD",
+		Prices: []DailyPrice{
+			{Date: 10 * day, Price: 20},
+			{Date: 11 * day, Price: 21},
+			{Date: 13 * day, Price: 23},
+		},
+	}
+	tests := []struct {
+		name  string
+		t     int64
+		price float64
+	}{
+		{"before the first day", 10*day - 1, 0},
+		{"start of a day", 10 * day, 20},
+		{"during a day", 11*day + 3600, 21},
+		{"day without a price", 12*day + 3600, 21},
+		{"last day", 13*day + 3600, 23},
+		{"within three days of the last day", 16 * day, 23},
+		{"more than three days after the last day", 16*day + 1, 0},
+	}
+	for _, tt := range tests {
+		if price := prices.PriceAt(tt.t); price != tt.price {
+			t.Errorf("%s: price %f, expecting %f", tt.name, price, tt.price)
+		}
+	}
+	var none *FiatPrices
+	if price := none.PriceAt(10 * day); price != 0 {
+		t.Errorf("nil prices: price %f, expecting 0", price)
+	}
+}
+
+func TestAddressInfoSetFiatValues(t *testing.T) {
+	const day = 86400
+	prices := &FiatPrices{
+		Currency: "EUR",
+		Prices:   []DailyPrice{{Date: 10 * day, Price: 2}},
+	}
+	addrInfo := &AddressInfo{
+		Transactions: []*AddressTx{
+			{Time: NewTimeDefFromUNIX(10*day + 60), ReceivedTotal: 3, IsFunding: true},
+			{Time: NewTimeDefFromUNIX(11*day + 60), SentTotal: 1.5},
+			{Time: NewTimeDefFromUNIX(12*day + 60), ReceivedTotal: 5, IsFunding: true, IsUnconfirmed: true},
+		},
+	}
+	if from, to := addrInfo.TimeRange(); from != 10*day+60 || to != 11*day+60 {
+		t.Errorf("time range %d-%d", from, to)
+	}
+	addrInfo.SetFiatValues(prices)
+	if addrInfo.FiatCurrency != "EUR" {
+		t.Errorf("fiat currency %q, expecting EUR", addrInfo.FiatCurrency)
+	}
+	for i, value := range []float64{6, 3, 0} {
+		if fv := addrInfo.Transactions[i].FiatValue; fv != value {
+			t.Errorf("transaction %d: fiat value %f, expecting %f", i, fv, value)
+		}
+	}
+}
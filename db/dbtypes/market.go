@@ -0,0 +1,38 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MarketCandle is a candlestick of an exchange market.
+type MarketCandle struct {
+	Start  time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// MarketLiquidity is the liquidity of an order book snapshot of an exchange
+// market: the mid-gap price, the spread between the best ask and bid, and the
+// quantities of the bids and asks near the mid-gap.
+type MarketLiquidity struct {
+	Time     time.Time
+	MidGap   float64
+	Spread   float64
+	BidDepth float64
+	AskDepth float64
+}
+
+// MarketDepthSnapshot is an order book snapshot of an exchange market. Bids
+// and Asks are the JSON-encoded orders.
+type MarketDepthSnapshot struct {
+	MarketLiquidity
+	Bids json.RawMessage
+	Asks json.RawMessage
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+// RetentionWindow is the range of blocks of a chain whose rows are kept in the
+// DB when the whole chain is not synced. The inputs and outputs, transactions
+// and address rows of the blocks below their FromHeight are pruned, with the
+// FromTime the time of that block. A FromHeight of 0 means that the rows of
+// all the blocks are kept.
+type RetentionWindow struct {
+	Chain             string `json:"chain"`
+	Pruned            bool   `json:"pruned"`
+	RawFromHeight     int64  `json:"raw_from_height"`
+	RawFromTime       int64  `json:"raw_from_time"`
+	TxFromHeight      int64  `json:"tx_from_height"`
+	TxFromTime        int64  `json:"tx_from_time"`
+	AddressFromHeight int64  `json:"address_from_height"`
+	AddressFromTime   int64  `json:"address_from_time"`
+}
+
+// RawPruned reports whether the inputs and outputs of the block at the height
+// are beyond the window.
+func (w *RetentionWindow) RawPruned(height int64) bool {
+	return w != nil && height < w.RawFromHeight
+}
+
+// AddressesPruned reports whether the address rows funded in the block at the
+// height are beyond the window.
+func (w *RetentionWindow) AddressesPruned(height int64) bool {
+	return w != nil && height < w.AddressFromHeight
+}
+
+// AddressesPrunedAt reports whether the address rows funded at the Unix time
+// are beyond the window.
+func (w *RetentionWindow) AddressesPrunedAt(t int64) bool {
+	return w != nil && w.AddressFromHeight > 0 && t < w.AddressFromTime
+}
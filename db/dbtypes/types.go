@@ -886,7 +886,7 @@ type DeletionSummary struct {
 	Blocks, Vins, Vouts, Addresses, Transactions int64
 	VoutSpendTxIDs                               int64
 	Tickets, Votes, Misses                       int64
-	Treasury, Swaps                              int64
+	Treasury, Swaps, MixStats                    int64
 	Timings                                      *DeletionSummary // durations
 }
 
@@ -903,7 +903,8 @@ func (s DeletionSummary) String() string {
 		summary += fmt.Sprintf("%9d Votes purged\n", s.Votes)
 		summary += fmt.Sprintf("%9d Misses purged\n", s.Misses)
 		summary += fmt.Sprintf("%9d Treasury transactions purged\n", s.Treasury)
-		summary += fmt.Sprintf("%9d Swaps purged", s.Swaps)
+		summary += fmt.Sprintf("%9d Swaps purged\n", s.Swaps)
+		summary += fmt.Sprintf("%9d Mix stats purged", s.MixStats)
 		return summary
 	}
 
@@ -917,7 +918,8 @@ func (s DeletionSummary) String() string {
 	summary += fmt.Sprintf("%9d Votes purged in %v\n", s.Votes, time.Duration(s.Timings.Votes))
 	summary += fmt.Sprintf("%9d Misses purged in %v\n", s.Misses, time.Duration(s.Timings.Misses))
 	summary += fmt.Sprintf("%9d Treasury transactions purged in %v\n", s.Treasury, time.Duration(s.Timings.Treasury))
-	summary += fmt.Sprintf("%9d Swaps purged in %v", s.Swaps, time.Duration(s.Timings.Swaps))
+	summary += fmt.Sprintf("%9d Swaps purged in %v\n", s.Swaps, time.Duration(s.Timings.Swaps))
+	summary += fmt.Sprintf("%9d Mix stats purged in %v", s.MixStats, time.Duration(s.Timings.MixStats))
 	return summary
 }
 
@@ -940,6 +942,7 @@ func (ds DeletionSummarySlice) Reduce() DeletionSummary {
 		s.Misses += ds[i].Misses
 		s.Treasury += ds[i].Treasury
 		s.Swaps += ds[i].Swaps
+		s.MixStats += ds[i].MixStats
 		if ds[i].Timings != nil {
 			timings = append(timings, *ds[i].Timings)
 		}
@@ -2001,16 +2004,41 @@ type AddressTxnOutput struct {
 }
 
 type MutilchainAddressTxnOutput struct {
-	Address  string
-	PkScript string
-	TxHash   string
-	//BlockHash chainhash.Hash
+	Address   string
+	PkScript  string
+	TxHash    string
+	BlockHash string
 	Vout      uint32
 	Height    int32
 	BlockTime int64
 	Atoms     int64
 }
 
+// MutilchainAddressTxHeight is a transaction of a BTC or LTC address with the
+// height of the block that mined it.
+type MutilchainAddressTxHeight struct {
+	TxHash      string
+	BlockHeight int64
+}
+
+// MutilchainMempoolAddress summarizes the mempool transactions of a BTC or LTC
+// address. Received is the sum of the NumReceived unconfirmed outputs paying
+// to the address, and Sent is the sum of the previous outputs of the address
+// spent by mempool transactions. SpentOutpoints lists the "txid:vout"
+// outpoints of the address spent by mempool transactions, and SpendingTxids
+// the hashes of the spending transactions in the same order.
+type MutilchainMempoolAddress struct {
+	Address        string
+	Txids          []string
+	TxTimes        []int64
+	NumReceived    int64
+	Received       int64
+	Sent           int64
+	UTXOs          []*MutilchainAddressTxnOutput
+	SpentOutpoints []string
+	SpendingTxids  []string
+}
+
 // AddressMetrics defines address metrics needed to make decisions by which
 // grouping buttons on the address history page charts should be disabled or
 // enabled by default.
@@ -2260,6 +2288,9 @@ type AddressTx struct {
 	SwapsType        string
 	SwapsTypeDisplay string
 	Coinbase         bool
+	// FiatValue is the value of the row at the price of the day of its block,
+	// when the address is valued in AddressInfo.FiatCurrency.
+	FiatValue float64 `json:",omitempty"`
 }
 
 type MonthlyUsdPrice struct {
@@ -2308,6 +2339,46 @@ type TreasuryTx struct {
 	TSpendMeta  *TreasurySpendVotesSummaryData
 }
 
+// MixDenomStats holds the CoinShuffle++ mix statistics for a single mix
+// denomination. Participants is the number of mixed outputs, and Volume is the
+// total value of the mixed outputs in atoms.
+type MixDenomStats struct {
+	Denom        int64 `json:"denom"`
+	Mixes        int64 `json:"mixes"`
+	Participants int64 `json:"participants"`
+	Volume       int64 `json:"volume"`
+}
+
+// MixStats holds the mix statistics for a block, or for a day when Height is
+// not set, with a breakdown by denomination.
+type MixStats struct {
+	Height       int64            `json:"height,omitempty"`
+	Time         TimeDef          `json:"time"`
+	Mixes        int64            `json:"mixes"`
+	Participants int64            `json:"participants"`
+	Volume       int64            `json:"volume"`
+	Denoms       []*MixDenomStats `json:"denoms"`
+}
+
+// AddDenom adds the statistics for a denomination to the totals.
+func (ms *MixStats) AddDenom(ds *MixDenomStats) {
+	ms.Mixes += ds.Mixes
+	ms.Participants += ds.Participants
+	ms.Volume += ds.Volume
+	ms.Denoms = append(ms.Denoms, ds)
+}
+
+// MixTx describes a mainchain CoinShuffle++ mix transaction.
+type MixTx struct {
+	TxID        string  `json:"txid"`
+	BlockHeight int64   `json:"block_height"`
+	BlockTime   TimeDef `json:"block_time"`
+	MixCount    int64   `json:"mix_count"`
+	MixDenom    int64   `json:"mix_denom"`
+	Fees        int64   `json:"fees"`
+	Size        int64   `json:"size"`
+}
+
 type TreasurySummary struct {
 	Month               string    `json:"month"`
 	MonthTime           time.Time `json:"monthTime"`
@@ -2430,6 +2501,7 @@ type AddressInfo struct {
 	Path          string
 	Limit, Offset int64  // ?n=Limit&start=Offset
 	TxnType       string // ?txntype=TxnType
+	NextCursor    string // ?cursor=NextCursor for the next page
 	TxnCount      int64
 	MonthRowIndex int64
 	IsMerged      bool
@@ -2461,6 +2533,43 @@ type AddressInfo struct {
 	KnownFundingTxns  int64
 	KnownSpendingTxns int64
 	ChainType         string
+
+	// FiatCurrency is the currency of the FiatValue of the Transactions, when
+	// they are valued at the price of the day of their block.
+	FiatCurrency string
+}
+
+// SetFiatValues values the confirmed Transactions at the prices of the day of
+// their block.
+func (a *AddressInfo) SetFiatValues(prices *FiatPrices) {
+	for _, tx := range a.Transactions {
+		if tx.IsUnconfirmed {
+			continue
+		}
+		amount := tx.SentTotal
+		if tx.IsFunding {
+			amount = tx.ReceivedTotal
+		}
+		tx.FiatValue = amount * prices.PriceAt(tx.Time.UNIX())
+	}
+	a.FiatCurrency = prices.Currency
+}
+
+// TimeRange is the range of the block times of the confirmed Transactions.
+func (a *AddressInfo) TimeRange() (from, to int64) {
+	for _, tx := range a.Transactions {
+		if tx.IsUnconfirmed {
+			continue
+		}
+		t := tx.Time.UNIX()
+		if from == 0 || t < from {
+			from = t
+		}
+		if t > to {
+			to = t
+		}
+	}
+	return
 }
 
 // AddressBalance represents the number and value of spent and unspent outputs
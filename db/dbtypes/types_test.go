@@ -154,3 +154,21 @@ func TestTimeDef_Scan(t *testing.T) {
 		t.Fatal("TimeDef.Scan(int64) should have failed")
 	}
 }
+
+func TestMixStatsAddDenom(t *testing.T) {
+	var ms MixStats
+	denoms := []*MixDenomStats{
+		{Denom: 1e8, Mixes: 2, Participants: 8, Volume: 8e8},
+		{Denom: 1e7, Mixes: 1, Participants: 5, Volume: 5e7},
+	}
+	for _, ds := range denoms {
+		ms.AddDenom(ds)
+	}
+	if ms.Mixes != 3 || ms.Participants != 13 || ms.Volume != 8e8+5e7 {
+		t.Errorf("wrong totals: %d mixes, %d participants, volume %d",
+			ms.Mixes, ms.Participants, ms.Volume)
+	}
+	if len(ms.Denoms) != 2 || ms.Denoms[0] != denoms[0] || ms.Denoms[1] != denoms[1] {
+		t.Errorf("wrong denominations: %v", ms.Denoms)
+	}
+}
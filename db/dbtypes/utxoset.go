@@ -0,0 +1,73 @@
+package dbtypes
+
+// UtxoValueBuckets are the labels of the value buckets of the UTXO set
+// statistics, in coins. The first bucket holds the outputs of less than 1000
+// atoms, and each next bucket the outputs up to ten times larger, to the last
+// bucket of the outputs of 10000 coins or more.
+var UtxoValueBuckets = [...]string{
+	"<0.00001", "0.00001-0.0001", "0.0001-0.001", "0.001-0.01", "0.01-0.1",
+	"0.1-1", "1-10", "10-100", "100-1k", "1k-10k", ">=10k",
+}
+
+// UtxoSetBucket is the part of the UTXO set in a value bucket or of a script
+// type. The values are in atoms, and the sizes are the serialize sizes of the
+// outputs in bytes.
+type UtxoSetBucket struct {
+	Bucket    string `json:"bucket"`
+	Count     int64  `json:"count"`
+	Value     int64  `json:"value"`
+	Size      int64  `json:"size"`
+	DustCount int64  `json:"dust_count"`
+	DustValue int64  `json:"dust_value"`
+}
+
+// UtxoSetSnapshot is the UTXO set of a chain at a block. Dust outputs are those
+// whose value is less than the fee to spend them at the dust relay fee rate of
+// the chain. The provably unspendable outputs are not in the set.
+type UtxoSetSnapshot struct {
+	Chain        string           `json:"chain"`
+	Height       int64            `json:"height"`
+	Hash         string           `json:"hash"`
+	Time         int64            `json:"time"`
+	Count        int64            `json:"count"`
+	Value        int64            `json:"value"`
+	Size         int64            `json:"size"`
+	DustCount    int64            `json:"dust_count"`
+	DustValue    int64            `json:"dust_value"`
+	ValueBuckets []*UtxoSetBucket `json:"value_buckets"`
+	ScriptTypes  []*UtxoSetBucket `json:"script_types"`
+}
+
+// AddRow adds a row of the UTXO set, the outputs of a value bucket and script
+// type that are dust or not, to the value and script type distributions.
+func (s *UtxoSetSnapshot) AddRow(valueBucket int, scriptType string, dust bool, count, value, size int64) {
+	if s.ValueBuckets == nil {
+		s.ValueBuckets = make([]*UtxoSetBucket, len(UtxoValueBuckets))
+		for i, label := range UtxoValueBuckets {
+			s.ValueBuckets[i] = &UtxoSetBucket{Bucket: label}
+		}
+	}
+	if valueBucket < 0 || valueBucket >= len(s.ValueBuckets) {
+		return
+	}
+	var scriptBucket *UtxoSetBucket
+	for _, b := range s.ScriptTypes {
+		if b.Bucket == scriptType {
+			scriptBucket = b
+			break
+		}
+	}
+	if scriptBucket == nil {
+		scriptBucket = &UtxoSetBucket{Bucket: scriptType}
+		s.ScriptTypes = append(s.ScriptTypes, scriptBucket)
+	}
+	for _, b := range []*UtxoSetBucket{s.ValueBuckets[valueBucket], scriptBucket} {
+		b.Count += count
+		b.Value += value
+		b.Size += size
+		if dust {
+			b.DustCount += count
+			b.DustValue += value
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dbtypes
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription is a callback URL registered by the owner of an API key
+// for the events of a chain. Target is the address of an address
+// subscription, the transaction of a confirmation subscription, or the
+// optional contract transaction of a swap subscription. Confirmation
+// subscriptions are deactivated once they are triggered.
+type WebhookSubscription struct {
+	ID            int64     `json:"id"`
+	KeyID         int64     `json:"-"`
+	URL           string    `json:"url"`
+	Secret        string    `json:"secret,omitempty"`
+	Event         string    `json:"event"`
+	Chain         string    `json:"chain"`
+	Target        string    `json:"target,omitempty"`
+	Confirmations int       `json:"confirmations,omitempty"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is a delivery of an event in the webhook outbox. URL and
+// Secret are those of the subscription when the delivery is claimed.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	Event          string
+	Payload        json.RawMessage
+	Attempts       int
+	CreatedAt      time.Time
+	URL            string
+	Secret         string
+}
+
+// WebhookDeadLetter is a delivery that failed too many times to be retried.
+type WebhookDeadLetter struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int64           `json:"subscription_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+	FailedAt       time.Time       `json:"failed_at"`
+}
+
+// WebhookAddressActivity is the funding or spending of an output of an
+// address by a transaction.
+type WebhookAddressActivity struct {
+	Address   string `json:"address"`
+	TxHash    string `json:"tx_hash"`
+	IsFunding bool   `json:"is_funding"`
+	Value     int64  `json:"value"`
+}
+
+// WebhookTxBlock is the block of a transaction.
+type WebhookTxBlock struct {
+	Height int64  `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// WebhookSwapRedemption is the redemption of an atomic swap contract.
+type WebhookSwapRedemption struct {
+	ContractTx string `json:"contract_tx"`
+	SpendTx    string `json:"spend_tx"`
+	SpendVin   int    `json:"spend_vin"`
+	Value      int64  `json:"value"`
+	SecretHash string `json:"secret_hash"`
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// CheckCreateAPIKeyTables creates the api_keys and api_key_usage tables if
+// they do not already exist.
+func CheckCreateAPIKeyTables(db *sql.DB) error {
+	if err := createTable(db, "api_keys", internal.CreateAPIKeysTable); err != nil {
+		return err
+	}
+	return createTable(db, "api_key_usage", internal.CreateAPIKeyUsageTable)
+}
+
+// InsertAPIKey generates and stores a new API key with the given name and
+// limits. The returned key is not stored and cannot be recovered later.
+func InsertAPIKey(ctx context.Context, db *sql.DB, name string, rateLimit float64, burst int,
+	dailyQuota int64) (string, *dbtypes.APIKey, error) {
+	if rateLimit < 0 || burst < 0 || dailyQuota < 0 {
+		return "", nil, fmt.Errorf("API key limits may not be negative")
+	}
+	key, err := dbtypes.NewAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+	apiKey := &dbtypes.APIKey{
+		Name:       name,
+		Display:    dbtypes.APIKeyDisplay(key),
+		RateLimit:  rateLimit,
+		Burst:      burst,
+		DailyQuota: dailyQuota,
+	}
+	err = db.QueryRowContext(ctx, internal.InsertAPIKey, dbtypes.HashAPIKey(key), apiKey.Display,
+		name, rateLimit, burst, dailyQuota).Scan(&apiKey.ID, &apiKey.CreatedAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return key, apiKey, nil
+}
+
+// RevokeAPIKey revokes the API key with the given id. dbtypes.ErrNoResult is
+// returned if there is no such key.
+func RevokeAPIKey(ctx context.Context, db *sql.DB, id int64) error {
+	return execAPIKeyUpdate(ctx, db, internal.RevokeAPIKey, id)
+}
+
+// UpdateAPIKeyLimits sets the rate limit, burst and daily quota of the API key
+// with the given id. dbtypes.ErrNoResult is returned if there is no such key.
+func UpdateAPIKeyLimits(ctx context.Context, db *sql.DB, id int64, rateLimit float64, burst int,
+	dailyQuota int64) error {
+	if rateLimit < 0 || burst < 0 || dailyQuota < 0 {
+		return fmt.Errorf("API key limits may not be negative")
+	}
+	return execAPIKeyUpdate(ctx, db, internal.UpdateAPIKeyLimits, id, rateLimit, burst, dailyQuota)
+}
+
+func execAPIKeyUpdate(ctx context.Context, db *sql.DB, stmt string, args ...any) error {
+	res, err := db.ExecContext(ctx, stmt, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return dbtypes.ErrNoResult
+	}
+	return nil
+}
+
+func scanAPIKey(scanner interface{ Scan(...any) error }) (*dbtypes.APIKey, error) {
+	var key dbtypes.APIKey
+	var revokedAt sql.NullTime
+	err := scanner.Scan(&key.ID, &key.Name, &key.Display, &key.RateLimit, &key.Burst,
+		&key.DailyQuota, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return &key, nil
+}
+
+// RetrieveAPIKeyByHash retrieves the API key with the given hash, including
+// revoked keys. dbtypes.ErrNoResult is returned if there is no such key.
+func RetrieveAPIKeyByHash(ctx context.Context, db *sql.DB, keyHash string) (*dbtypes.APIKey, error) {
+	key, err := scanAPIKey(db.QueryRowContext(ctx, internal.SelectAPIKeyByHash, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, dbtypes.ErrNoResult
+	}
+	return key, err
+}
+
+// RetrieveAPIKeys retrieves all the API keys, including revoked keys.
+func RetrieveAPIKeys(ctx context.Context, db *sql.DB) ([]*dbtypes.APIKey, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var keys []*dbtypes.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// RetrieveAPIKeyDailyUsage retrieves the number of requests made with an API
+// key on the UTC day of the given time.
+func RetrieveAPIKeyDailyUsage(ctx context.Context, db *sql.DB, keyID int64, day time.Time) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, internal.SelectAPIKeyDailyUsage, keyID, utcDay(day)).Scan(&count)
+	return count, err
+}
+
+// RetrieveAPIKeyUsage retrieves the request counts of an API key by day and
+// endpoint, starting with the UTC day of since.
+func RetrieveAPIKeyUsage(ctx context.Context, db *sql.DB, keyID int64, since time.Time) ([]*dbtypes.APIKeyUsage, error) {
+	rows, err := db.QueryContext(ctx, internal.SelectAPIKeyUsageSince, keyID, utcDay(since))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var usage []*dbtypes.APIKeyUsage
+	for rows.Next() {
+		var u dbtypes.APIKeyUsage
+		if err = rows.Scan(&u.KeyID, &u.Day, &u.Endpoint, &u.Count); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+	return usage, rows.Err()
+}
+
+// InsertAPIKeyUsage adds request counts to the api_key_usage table in a single
+// transaction.
+func InsertAPIKeyUsage(ctx context.Context, db *sql.DB, usage []*dbtypes.APIKeyUsage) error {
+	if len(usage) == 0 {
+		return nil
+	}
+	dbTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %w", err)
+	}
+	stmt, err := dbTx.PrepareContext(ctx, internal.UpsertAPIKeyUsage)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, u := range usage {
+		if _, err = stmt.ExecContext(ctx, u.KeyID, utcDay(u.Day), u.Endpoint, u.Count); err != nil {
+			_ = dbTx.Rollback()
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
+// utcDay returns the UTC date of t, at midnight.
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// CheckAndCreateAPIKeyTables creates the API key tables if they do not
+// already exist.
+func (pgb *ChainDB) CheckAndCreateAPIKeyTables() error {
+	return CheckCreateAPIKeyTables(pgb.db)
+}
+
+// APIKeyByHash retrieves the API key with the given hash. dbtypes.ErrNoResult
+// is returned if there is no such key.
+func (pgb *ChainDB) APIKeyByHash(keyHash string) (*dbtypes.APIKey, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	key, err := RetrieveAPIKeyByHash(ctx, pgb.db, keyHash)
+	return key, pgb.replaceCancelError(err)
+}
+
+// APIKeyDailyUsage retrieves the number of requests made with an API key on
+// the UTC day of the given time.
+func (pgb *ChainDB) APIKeyDailyUsage(keyID int64, day time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	count, err := RetrieveAPIKeyDailyUsage(ctx, pgb.db, keyID, day)
+	return count, pgb.replaceCancelError(err)
+}
+
+// StoreAPIKeyUsage adds request counts to the api_key_usage table. The
+// counts are stored even if the ChainDB is shutting down, so that the last
+// counts are not lost.
+func (pgb *ChainDB) StoreAPIKeyUsage(usage []*dbtypes.APIKeyUsage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pgb.queryTimeout)
+	defer cancel()
+	return pgb.replaceCancelError(InsertAPIKeyUsage(ctx, pgb.db, usage))
+}
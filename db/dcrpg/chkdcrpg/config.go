@@ -12,10 +12,15 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrdata/v8/mutilchain"
 	"github.com/decred/dcrdata/v8/netparams"
+	"github.com/decred/dcrdata/v8/netparams/btcnetparams"
+	"github.com/decred/dcrdata/v8/netparams/ltcnetparams"
 	flags "github.com/jessevdk/go-flags"
+	"github.com/ltcsuite/ltcd/ltcutil"
 )
 
 const (
@@ -27,12 +32,21 @@ const (
 
 var activeNet = &netparams.MainNetParams
 var activeChain = chaincfg.MainNetParams()
+var btcActiveNet = &btcnetparams.MainNetParams
+var ltcActiveNet = &ltcnetparams.MainNetParams
 
 var (
 	dcrdHomeDir              = dcrutil.AppDataDir("dcrd", false)
 	defaultDcrdHost          = "localhost"
 	defaultDaemonRPCCertFile = filepath.Join(dcrdHomeDir, "rpc.cert")
 
+	btcdHomeDir                 = btcutil.AppDataDir("btcd", false)
+	defaultBTCDaemonRPCCertFile = filepath.Join(btcdHomeDir, "rpc.cert")
+	ltcdHomeDir                 = ltcutil.AppDataDir("ltcd", false)
+	defaultLTCDaemonRPCCertFile = filepath.Join(ltcdHomeDir, "rpc.cert")
+	defaultXMRServer            = "http://127.0.0.1:18081/json_rpc"
+	defaultChains               = "btc,ltc,xmr"
+
 	dcrdataHomeDir = dcrutil.AppDataDir("dcrdata", false)
 	dcrdataDataDir = filepath.Join(dcrdataHomeDir, defaultDataDirName)
 
@@ -75,6 +89,22 @@ type config struct {
 	DcrdServ         string `long:"dcrdserv" description:"Hostname/IP and port of dcrd RPC server to connect to (default localhost:9109, testnet: localhost:19109, simnet: localhost:19556)"`
 	DcrdCert         string `long:"dcrdcert" description:"File containing the dcrd certificate file"`
 	DisableDaemonTLS bool   `long:"nodaemontls" description:"Disable TLS for the daemon RPC client -- NOTE: This is only allowed if the RPC client is connecting to localhost"`
+
+	// Multichain checks. The nodes are only needed to repair.
+	Chains   string `long:"chains" description:"Comma-separated list of the chains whose tables are checked, of btc, ltc and xmr. The chains without tables are skipped. (default btc,ltc,xmr)"`
+	Repair   bool   `long:"repair" description:"Repair the inconsistencies found in the BTC, LTC and XMR tables, fetching the offending blocks from the nodes"`
+	BtcdUser string `long:"btcduser" description:"BTC daemon RPC user name"`
+	BtcdPass string `long:"btcdpass" description:"BTC daemon RPC password"`
+	BtcdServ string `long:"btcdserv" description:"Hostname/IP and port of btcd RPC server to connect to (default localhost:8334, testnet: localhost:18334, simnet: localhost:18556)"`
+	BtcdCert string `long:"btcdcert" description:"File containing the btcd certificate file"`
+	LtcdUser string `long:"ltcduser" description:"LTC daemon RPC user name"`
+	LtcdPass string `long:"ltcdpass" description:"LTC daemon RPC password"`
+	LtcdServ string `long:"ltcdserv" description:"Hostname/IP and port of ltcd RPC server to connect to (default localhost:9334, testnet: localhost:19334, simnet: localhost:19556)"`
+	LtcdCert string `long:"ltcdcert" description:"File containing the ltcd certificate file"`
+	XmrServ  string `long:"xmrserv" description:"Endpoint of monerod RPC server to connect to (default http://127.0.0.1:18081/json_rpc)"`
+
+	// chainTypes are the chains of Chains.
+	chainTypes []string
 }
 
 var defaultConfig = config{
@@ -87,6 +117,10 @@ var defaultConfig = config{
 	DBPass:       defaultDBPass,
 	DBName:       defaultDBName,
 	DcrdCert:     defaultDaemonRPCCertFile,
+	Chains:       defaultChains,
+	BtcdCert:     defaultBTCDaemonRPCCertFile,
+	LtcdCert:     defaultLTCDaemonRPCCertFile,
+	XmrServ:      defaultXMRServer,
 }
 
 func loadConfig() (*config, error) {
@@ -175,10 +209,14 @@ func loadConfig() (*config, error) {
 	// mainnet is set by default.
 	if cfg.TestNet {
 		activeNet = &netparams.TestNet3Params
+		btcActiveNet = &btcnetparams.TestNet3Params
+		ltcActiveNet = &ltcnetparams.TestNet3Params
 		numNetsSet++
 	}
 	if cfg.SimNet {
 		activeNet = &netparams.SimNetParams
+		btcActiveNet = &btcnetparams.SimNetParams
+		ltcActiveNet = &ltcnetparams.SimNetParams
 		numNetsSet++
 	}
 	activeChain = activeNet.Params
@@ -202,6 +240,26 @@ func loadConfig() (*config, error) {
 	if cfg.DcrdServ == "" {
 		cfg.DcrdServ = defaultDcrdHost + ":" + activeNet.JSONRPCClientPort
 	}
+	if cfg.BtcdServ == "" {
+		cfg.BtcdServ = defaultDcrdHost + ":" + btcActiveNet.JSONRPCClientPort
+	}
+	if cfg.LtcdServ == "" {
+		cfg.LtcdServ = defaultDcrdHost + ":" + ltcActiveNet.JSONRPCClientPort
+	}
+
+	for _, chainType := range strings.Split(cfg.Chains, ",") {
+		chainType = strings.ToLower(strings.TrimSpace(chainType))
+		if chainType == "" {
+			continue
+		}
+		if chainType != mutilchain.TYPEBTC && chainType != mutilchain.TYPELTC &&
+			chainType != mutilchain.TYPEXMR {
+			return nil, fmt.Errorf("invalid chain %q in chains, must be one of btc, ltc and xmr", chainType)
+		}
+		cfg.chainTypes = append(cfg.chainTypes, chainType)
+	}
+	cfg.BtcdCert = cleanAndExpandPath(cfg.BtcdCert)
+	cfg.LtcdCert = cleanAndExpandPath(cfg.LtcdCert)
 
 	return &cfg, nil
 }
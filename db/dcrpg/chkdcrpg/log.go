@@ -10,6 +10,8 @@ import (
 
 	"github.com/decred/dcrd/rpcclient/v8"
 	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
+	"github.com/decred/dcrdata/v8/mutilchain/ltcrpcutils"
 	"github.com/decred/dcrdata/v8/rpcutils"
 	"github.com/decred/dcrdata/v8/stakedb"
 	"github.com/decred/slog"
@@ -43,6 +45,8 @@ func initializeLogging(logFile, logLevel string) {
 	stakedb.UseLogger(sdbLogger)
 	rpcclient.UseLogger(rpcLogger)
 	rpcutils.UseLogger(rpcLogger)
+	btcrpcutils.UseLogger(rpcLogger)
+	ltcrpcutils.UseLogger(rpcLogger)
 
 	logDir, _ := filepath.Split(logFile)
 	err := os.MkdirAll(logDir, 0700)
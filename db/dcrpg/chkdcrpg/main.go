@@ -127,6 +127,8 @@ func mainCore(ctx context.Context) error {
 	dbCfg := dcrpg.ChainDBCfg{
 		DBi:                  &dbi,
 		Params:               activeChain,
+		BTCParams:            btcActiveNet.Params,
+		LTCParams:            ltcActiveNet.Params,
 		DevPrefetch:          false,
 		HidePGConfig:         cfg.HidePGConfig,
 		AddrCacheAddrCap:     1 << 10,
@@ -169,6 +171,13 @@ func mainCore(ctx context.Context) error {
 	}
 	log.Infof("Loaded ChainDB at height %d", lastBlock)
 
+	// The repair of the multichain tables fetches blocks from their nodes.
+	if cfg.Repair {
+		if err = connectMultichainNodes(cfg, db); err != nil {
+			return err
+		}
+	}
+
 	// // Ensure that stakedb is at PG DB height.
 	// var rewindTo int64
 	// if lastBlock > 0 {
@@ -419,6 +428,16 @@ func mainCore(ctx context.Context) error {
 		}
 	}
 
+	// Check the BTC, LTC and XMR tables.
+	for _, chainType := range cfg.chainTypes {
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+		if err = checkMultichain(ctx, db, chainType, cfg.Repair); err != nil {
+			return err
+		}
+	}
+
 	log.Info("Done!")
 
 	return nil
@@ -0,0 +1,271 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
+	"github.com/decred/dcrdata/v8/mutilchain/ltcrpcutils"
+	"github.com/decred/dcrdata/v8/xmr/xmrclient"
+)
+
+// connectMultichainNodes connects the ChainDB to the nodes of the chains to
+// repair.
+func connectMultichainNodes(cfg *config, db *dcrpg.ChainDB) error {
+	for _, chainType := range cfg.chainTypes {
+		switch chainType {
+		case mutilchain.TYPEBTC:
+			client, _, err := btcrpcutils.ConnectNodeRPC(cfg.BtcdServ, cfg.BtcdUser,
+				cfg.BtcdPass, cfg.BtcdCert, cfg.DisableDaemonTLS, false)
+			if err != nil {
+				return fmt.Errorf("Unable to connect to BTC RPC server: %v", err)
+			}
+			db.BtcClient = client
+		case mutilchain.TYPELTC:
+			client, _, err := ltcrpcutils.ConnectNodeRPC(cfg.LtcdServ, cfg.LtcdUser,
+				cfg.LtcdPass, cfg.LtcdCert, cfg.DisableDaemonTLS, false)
+			if err != nil {
+				return fmt.Errorf("Unable to connect to LTC RPC server: %v", err)
+			}
+			db.LtcClient = client
+		case mutilchain.TYPEXMR:
+			client := xmrclient.NewXMRClient(cfg.XmrServ)
+			if _, err := client.GetLastBlockHeader(); err != nil {
+				return fmt.Errorf("Unable to connect to XMR RPC server: %v", err)
+			}
+			db.XmrClient = client
+		}
+	}
+	return nil
+}
+
+// checkMultichain runs the checks of the tables of a chain, repairing the
+// inconsistencies found if repair is true.
+func checkMultichain(ctx context.Context, db *dcrpg.ChainDB, chainType string, repair bool) error {
+	chain := strings.ToUpper(chainType)
+	blocksTable := chainType + "blocks"
+	if chainType == mutilchain.TYPEXMR {
+		blocksTable = "xmrblocks_all"
+	}
+	exists, err := dcrpg.TableExists(db.SqlDB(), blocksTable)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Infof("%s: No tables, skipping.", chain)
+		return nil
+	}
+	whole, err := dcrpg.MultichainWholeChainSynced(ctx, db.SqlDB(), chainType)
+	if err != nil {
+		return err
+	}
+	if whole {
+		log.Infof("%s: Checking the whole-chain tables...", chain)
+	} else {
+		log.Infof("%s: Checking the light tables...", chain)
+	}
+
+	// The heights of the whole-chain blocks to fetch again from the node.
+	var refetch []int64
+
+	if chainType != mutilchain.TYPEXMR {
+		log.Infof("%s: Checking the block_chain table for blocks with a next "+
+			"block that is missing or does not link back to them...", chain)
+		if ids, heights, hashes, nexts, err := dcrpg.CheckMultichainBlockChainLinks(ctx,
+			db.SqlDB(), chainType); err != nil {
+			log.Errorf("CheckMultichainBlockChainLinks: %v", err)
+		} else if len(ids) > 0 {
+			log.Warnf("%s: Found broken block chain links!", chain)
+			for i := range ids {
+				log.Warnf("\tblock_chain rowid %d, height %d, hash %s, next %s",
+					ids[i], heights[i], hashes[i], nexts[i])
+			}
+			if repair {
+				n, err := db.RepairMultichainBlockLinks(chainType, ids, heights)
+				if err != nil {
+					log.Errorf("RepairMultichainBlockLinks: %v", err)
+				}
+				log.Infof("%s: Repaired %d block chain links.", chain, n)
+			}
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+
+		log.Infof("%s: Checking the addresses table for rows funding a stored "+
+			"input that are not flagged as spent by it...", chain)
+		if ids, txHashes, txIndexes, prevHashes, prevIndexes, heights, err :=
+			dcrpg.CheckMultichainUnsetSpending(ctx, db.SqlDB(), chainType, whole); err != nil {
+			log.Errorf("CheckMultichainUnsetSpending: %v", err)
+		} else if len(ids) > 0 {
+			log.Warnf("%s: Found address rows with unset spending info!", chain)
+			for i := range ids {
+				log.Warnf("\tvins rowid %d, input %s:%d, spends %s:%d, height %d",
+					ids[i], txHashes[i], txIndexes[i], prevHashes[i], prevIndexes[i], heights[i])
+			}
+			if repair {
+				n, err := dcrpg.RepairMultichainUnsetSpending(ctx, db.SqlDB(), chainType, whole, ids)
+				if err != nil {
+					log.Errorf("RepairMultichainUnsetSpending: %v", err)
+				}
+				log.Infof("%s: Set the spending info of %d address rows.", chain, n)
+			}
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+	}
+
+	if whole && chainType != mutilchain.TYPEXMR {
+		log.Infof("%s: Checking the vins_all table for inputs spending an "+
+			"output that is not stored...", chain)
+		if ids, txHashes, txIndexes, prevHashes, prevIndexes, heights, err :=
+			dcrpg.CheckMultichainMissingFundingOutpoints(ctx, db.SqlDB(), chainType); err != nil {
+			log.Errorf("CheckMultichainMissingFundingOutpoints: %v", err)
+		} else if len(ids) > 0 {
+			log.Warnf("%s: Found inputs spending missing outputs!", chain)
+			for i := range ids {
+				log.Warnf("\tvins_all rowid %d, input %s:%d, spends %s:%d, funding height %d",
+					ids[i], txHashes[i], txIndexes[i], prevHashes[i], prevIndexes[i], heights[i])
+				if heights[i] < 0 {
+					log.Warnf("\t(the funding transaction %s is not stored, the block to fetch is unknown)",
+						prevHashes[i])
+				}
+			}
+			refetch = append(refetch, heights...)
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+
+		log.Infof("%s: Checking the swaps table for spends whose spending or "+
+			"contract transaction is not stored...", chain)
+		if spendTxs, spendVins, missingTxs, heights, err := dcrpg.CheckMultichainSwapsMissingTx(ctx,
+			db.SqlDB(), chainType); err != nil {
+			log.Errorf("CheckMultichainSwapsMissingTx: %v", err)
+		} else if len(spendTxs) > 0 {
+			log.Warnf("%s: Found swaps with missing transactions!", chain)
+			for i := range spendTxs {
+				log.Warnf("\tspend %s:%d, missing %s, height %d", spendTxs[i], spendVins[i],
+					missingTxs[i], heights[i])
+				if heights[i] < 0 {
+					log.Warnf("\t(the contract transaction %s is not stored, the block to fetch is unknown)",
+						missingTxs[i])
+				}
+			}
+			refetch = append(refetch, heights...)
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+	}
+
+	if whole {
+		log.Infof("%s: Checking the blocks_all table for blocks whose number of "+
+			"transactions differs from the number stored...", chain)
+		if heights, hashes, numTxs, stored, err := dcrpg.CheckMultichainBlockTxCounts(ctx,
+			db.SqlDB(), chainType); err != nil {
+			log.Errorf("CheckMultichainBlockTxCounts: %v", err)
+		} else if len(heights) > 0 {
+			log.Warnf("%s: Found blocks with missing or extra transactions!", chain)
+			for i := range heights {
+				log.Warnf("\theight %d, hash %s, numtx %d, stored %d",
+					heights[i], hashes[i], numTxs[i], stored[i])
+			}
+			refetch = append(refetch, heights...)
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+	}
+
+	if chainType == mutilchain.TYPEXMR {
+		log.Infof("%s: Checking the monero_key_images table for key images "+
+			"of transactions that are not stored...", chain)
+		if ids, keyImages, txHashes, heights, err := dcrpg.CheckXMROrphanedKeyImages(ctx,
+			db.SqlDB()); err != nil {
+			log.Errorf("CheckXMROrphanedKeyImages: %v", err)
+		} else if len(ids) > 0 {
+			log.Warnf("%s: Found orphaned key images!", chain)
+			for i := range ids {
+				log.Warnf("\tmonero_key_images rowid %d, key image %s, tx %s, spend height %d",
+					ids[i], keyImages[i], txHashes[i], heights[i])
+			}
+			if repair {
+				n, err := dcrpg.RepairXMROrphanedKeyImages(ctx, db.SqlDB(), ids)
+				if err != nil {
+					log.Errorf("RepairXMROrphanedKeyImages: %v", err)
+				}
+				log.Infof("%s: Deleted %d orphaned key images.", chain, n)
+			}
+			refetch = append(refetch, heights...)
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+
+		log.Infof("%s: Checking the stored transactions for key images spent "+
+			"by more than one input...", chain)
+		if keyImages, inputs, err := dcrpg.CheckXMRReusedKeyImages(ctx, db.SqlDB()); err != nil {
+			log.Errorf("CheckXMRReusedKeyImages: %v", err)
+		} else if len(keyImages) > 0 {
+			// There is no automatic repair: the transactions of the wrong
+			// block must be found and removed by hand.
+			log.Warnf("%s: Found key images spent more than once!", chain)
+			for i := range keyImages {
+				log.Warnf("\tkey image %s, inputs %s", keyImages[i], strings.Join(inputs[i], ", "))
+			}
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+
+		log.Infof("%s: Checking the monero_ring_members table for ring members "+
+			"of transactions that are not stored...", chain)
+		if ids, txHashes, inputIndexes, err := dcrpg.CheckXMROrphanedRingMembers(ctx,
+			db.SqlDB()); err != nil {
+			log.Errorf("CheckXMROrphanedRingMembers: %v", err)
+		} else if len(ids) > 0 {
+			log.Warnf("%s: Found orphaned ring members!", chain)
+			for i := range ids {
+				log.Warnf("\tmonero_ring_members rowid %d, input %s:%d",
+					ids[i], txHashes[i], inputIndexes[i])
+			}
+			if repair {
+				n, err := dcrpg.RepairXMROrphanedRingMembers(ctx, db.SqlDB(), ids)
+				if err != nil {
+					log.Errorf("RepairXMROrphanedRingMembers: %v", err)
+				}
+				log.Infof("%s: Deleted %d orphaned ring members.", chain, n)
+			}
+		}
+
+		if shutdownRequested(ctx) {
+			return fmt.Errorf("Shutdown requested.")
+		}
+	}
+
+	if repair && len(refetch) > 0 {
+		log.Infof("%s: Fetching the offending blocks from the node...", chain)
+		n, err := db.RepairMultichainBlocks(chainType, refetch)
+		if err != nil {
+			log.Errorf("RepairMultichainBlocks: %v", err)
+		}
+		log.Infof("%s: Stored %d blocks again.", chain, n)
+	}
+
+	return nil
+}
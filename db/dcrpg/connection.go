@@ -5,13 +5,88 @@
 package dcrpg
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/lib/pq" // Start the PostgreSQL driver
+	"github.com/lib/pq"
 )
 
+// QueryObserver receives the duration of each query or statement that is run
+// on a connection of the database, where kind is "query" or "exec". Prepared
+// statements are not observed.
+type QueryObserver func(kind string, elapsed time.Duration, err error)
+
+var queryObserver atomic.Pointer[QueryObserver]
+
+// UseQueryObserver sets the QueryObserver of the connections, e.g. to collect
+// query latency metrics. It must not block.
+func UseQueryObserver(observer QueryObserver) {
+	queryObserver.Store(&observer)
+}
+
+func observeQuery(kind string, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return // retried by database/sql with a prepared statement
+	}
+	if observer := queryObserver.Load(); observer != nil && *observer != nil {
+		(*observer)(kind, time.Since(start), err)
+	}
+}
+
+// timedConnector opens the timedConns of the database.
+type timedConnector struct {
+	driver.Connector
+}
+
+func (c timedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pc, ok := conn.(pqConn)
+	if !ok {
+		return conn, nil
+	}
+	return &timedConn{pc}, nil
+}
+
+// pqConn is the subset of the methods of the connections of lib/pq that
+// database/sql uses.
+type pqConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.QueryerContext
+	driver.ExecerContext
+	driver.Pinger
+}
+
+// timedConn is a pqConn that reports the duration of its queries to the
+// QueryObserver.
+type timedConn struct {
+	pqConn
+}
+
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := c.pqConn.QueryContext(ctx, query, args)
+	observeQuery("query", start, err)
+	return rows, err
+}
+
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	res, err := c.pqConn.ExecContext(ctx, query, args)
+	observeQuery("exec", start, err)
+	return res, err
+}
+
 // Connect opens a connection to a PostgreSQL database. The caller is
 // responsible for calling Close() on the returned db when finished using it.
 // The input host may be an IP address for TCP connection, or an absolute path
@@ -32,10 +107,11 @@ func Connect(host, port, user, pass, dbname string) (*sql.DB, error) {
 		psqlInfo += fmt.Sprintf(" port=%s", port)
 	}
 
-	db, err := sql.Open("postgres", psqlInfo)
+	connector, err := pq.NewConnector(psqlInfo)
 	if err != nil {
 		return nil, err
 	}
+	db := sql.OpenDB(timedConnector{connector})
 
 	return db, db.Ping()
 }
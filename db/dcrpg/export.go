@@ -0,0 +1,179 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// exportFetchSize is the number of rows fetched from the cursor of an export
+// at a time.
+const exportFetchSize = 1000
+
+// exportQuery streams the rows of a query to an ExportWriter through a server
+// side cursor, so that only exportFetchSize rows are held in memory. The
+// cursor is declared in a read-only transaction, which provides a consistent
+// snapshot of the rows for the whole export. The writer is flushed after each
+// batch of rows.
+func (pgb *ChainDB) exportQuery(ctx context.Context, ew dbtypes.ExportWriter, query string, args ...any) error {
+	tx, err := pgb.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+	// The transaction is only read from, so it is always rolled back.
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.ExecContext(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return pgb.replaceCancelError(err)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM export_cursor;", exportFetchSize)
+	var values []any
+	for {
+		numRows, err := func() (int, error) {
+			rows, err := tx.QueryContext(ctx, fetch)
+			if err != nil {
+				return 0, err
+			}
+			defer closeRows(rows)
+
+			if values == nil {
+				columns, err := rows.Columns()
+				if err != nil {
+					return 0, err
+				}
+				if err = ew.WriteHeader(columns); err != nil {
+					return 0, err
+				}
+				values = make([]any, len(columns))
+			}
+			ptrs := make([]any, len(values))
+			for i := range values {
+				ptrs[i] = &values[i]
+			}
+
+			var n int
+			for rows.Next() {
+				if err = rows.Scan(ptrs...); err != nil {
+					return n, err
+				}
+				if err = ew.WriteRow(values); err != nil {
+					return n, err
+				}
+				n++
+			}
+			return n, rows.Err()
+		}()
+		if err != nil {
+			return pgb.replaceCancelError(err)
+		}
+		if err = ew.Flush(); err != nil {
+			return err
+		}
+		if numRows < exportFetchSize {
+			return nil
+		}
+	}
+}
+
+// ExportAddressRows writes at most maxRows rows of the addresses table for a
+// Decred address, most recent first, with the optional historical USD price
+// and value of each row.
+func (pgb *ChainDB) ExportAddressRows(ctx context.Context, ew dbtypes.ExportWriter, address string,
+	fiat bool, maxRows int64) error {
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectAddressExportRows(fiat), address, maxRows)
+}
+
+// ExportBlocks writes the summaries of the mainchain Decred blocks in the
+// height range [from, to], up to maxRows blocks, with the optional historical
+// USD price of the day of each block.
+func (pgb *ChainDB) ExportBlocks(ctx context.Context, ew dbtypes.ExportWriter, from, to int64,
+	fiat bool, maxRows int64) error {
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectBlockExportRows(fiat), from, to, maxRows)
+}
+
+// ExportTreasuryTxns writes at most maxRows mainchain treasury transactions,
+// most recent first, with the optional historical USD price and value of each
+// transaction.
+func (pgb *ChainDB) ExportTreasuryTxns(ctx context.Context, ew dbtypes.ExportWriter, fiat bool, maxRows int64) error {
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectTreasuryExportRows(fiat), maxRows)
+}
+
+// ExportAtomicSwaps writes at most maxRows atomic swap spends, most recent
+// contract first, with the optional historical USD price and value of each
+// contract.
+func (pgb *ChainDB) ExportAtomicSwaps(ctx context.Context, ew dbtypes.ExportWriter, fiat bool, maxRows int64) error {
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectSwapExportRows(fiat), maxRows)
+}
+
+// ExportMutilchainAddressRows writes at most maxRows funding and spending rows
+// of a BTC or LTC address, most recent first.
+func (pgb *ChainDB) ExportMutilchainAddressRows(ctx context.Context, ew dbtypes.ExportWriter, chainType, address string,
+	maxRows int64) error {
+	if chainType != mutilchain.TYPEBTC && chainType != mutilchain.TYPELTC {
+		return fmt.Errorf("address export is not supported for chain %q", chainType)
+	}
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectMultichainAddressExportRows(chainType), address, maxRows)
+}
+
+// ExportMutilchainBlocks writes the summaries of the BTC or LTC blocks in the
+// height range [from, to], up to maxRows blocks.
+func (pgb *ChainDB) ExportMutilchainBlocks(ctx context.Context, ew dbtypes.ExportWriter, chainType string,
+	from, to, maxRows int64) error {
+	if chainType != mutilchain.TYPEBTC && chainType != mutilchain.TYPELTC {
+		return fmt.Errorf("block export is not supported for chain %q", chainType)
+	}
+	return pgb.exportQuery(ctx, ew, internal.MakeSelectMultichainBlockExportRows(chainType), from, to, maxRows)
+}
+
+// AddressLedger retrieves the mainchain transactions of a set of DCR, BTC or
+// LTC addresses up to the Unix time to, netted by transaction, in ascending
+// order of time. An error is returned if there are more than maxRows
+// transactions, since a partial ledger would give wrong cost bases.
+func (pgb *ChainDB) AddressLedger(ctx context.Context, chainType string, addresses []string,
+	to, maxRows int64) ([]*dbtypes.LedgerTx, error) {
+	var query string
+	switch chainType {
+	case mutilchain.TYPEDCR:
+		query = internal.SelectAddressLedger
+	case mutilchain.TYPEBTC, mutilchain.TYPELTC:
+		query = internal.MakeSelectMultichainAddressLedger(chainType)
+	default:
+		return nil, fmt.Errorf("address ledger is not supported for chain %q", chainType)
+	}
+	ctx, cancel := context.WithTimeout(ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, query, pq.Array(addresses), to, maxRows+1)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var txs []*dbtypes.LedgerTx
+	for rows.Next() {
+		tx := &dbtypes.LedgerTx{Chain: chainType}
+		var receivedLinks, sentLinks pq.StringArray
+		if err = rows.Scan(&tx.TxHash, &tx.Time, &tx.Received, &tx.Sent, &tx.Fees, &tx.Spent,
+			&receivedLinks, &sentLinks); err != nil {
+			return nil, err
+		}
+		tx.ReceivedLinks, tx.SentLinks = receivedLinks, sentLinks
+		txs = append(txs, tx)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	if int64(len(txs)) > maxRows {
+		return nil, fmt.Errorf("the %s addresses have more than %d transactions", chainType, maxRows)
+	}
+	return txs, nil
+}
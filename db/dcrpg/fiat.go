@@ -0,0 +1,217 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain/externalapi"
+)
+
+const (
+	secondsPerDay = 86400
+	// atomsPerCoin converts the atoms of the address tables to coins, for DCR,
+	// BTC and LTC alike.
+	atomsPerCoin = 1e8
+	// priceRequestInterval spaces the requests of the price history.
+	priceRequestInterval = time.Second
+)
+
+// CheckAndCreateDailyPriceTable creates the daily_price table of the
+// historical fiat prices if it does not already exist.
+func (pgb *ChainDB) CheckAndCreateDailyPriceTable() error {
+	return createTable(pgb.db, "daily_price", internal.CreateDailyPriceTable)
+}
+
+// StoreDailyPrices stores the daily prices of the coin of a chain in a fiat
+// currency, replacing the stored prices of the same days.
+func (pgb *ChainDB) StoreDailyPrices(chainType, currency string, prices []dbtypes.DailyPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	dbTx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %w", err)
+	}
+	stmt, err := dbTx.PrepareContext(ctx, internal.UpsertDailyPrice)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return pgb.replaceCancelError(err)
+	}
+	defer stmt.Close()
+
+	for _, p := range prices {
+		if _, err = stmt.ExecContext(ctx, chainType, currency, p.Date, p.Price); err != nil {
+			_ = dbTx.Rollback()
+			return pgb.replaceCancelError(err)
+		}
+	}
+	return dbTx.Commit()
+}
+
+// DailyPriceRange retrieves the first and last days with a price of the coin
+// of a chain in a fiat currency, or zeros if there are no prices.
+func (pgb *ChainDB) DailyPriceRange(chainType, currency string) (first, last int64, err error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	err = pgb.db.QueryRowContext(ctx, internal.SelectFirstLastDailyPriceDates,
+		chainType, currency).Scan(&first, &last)
+	err = pgb.replaceCancelError(err)
+	return
+}
+
+// FiatPrices retrieves the daily prices of the coin of a chain in a fiat
+// currency for the days from the Unix time from to the Unix time to. The
+// latest price before from is included, so that FiatPrices.PriceAt can value
+// the days without a price.
+func (pgb *ChainDB) FiatPrices(chainType, currency string, from, to int64) (*dbtypes.FiatPrices, error) {
+	currency = dbtypes.NormalizeCurrency(currency)
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectDailyPrices, chainType, currency, from, to)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	prices := &dbtypes.FiatPrices{
+		Chain:    chainType,
+		Currency: currency,
+	}
+	for rows.Next() {
+		var p dbtypes.DailyPrice
+		if err = rows.Scan(&p.Date, &p.Price); err != nil {
+			return nil, err
+		}
+		prices.Prices = append(prices.Prices, p)
+	}
+	return prices, pgb.replaceCancelError(rows.Err())
+}
+
+// FiatCurrencies retrieves the fiat currencies with daily prices of the coin
+// of a chain.
+func (pgb *ChainDB) FiatCurrencies(chainType string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectDailyPriceCurrencies, chainType)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var currencies []string
+	for rows.Next() {
+		var currency string
+		if err = rows.Scan(&currency); err != nil {
+			return nil, err
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies, pgb.replaceCancelError(rows.Err())
+}
+
+// AddressFiatFlow retrieves the amounts received and sent by an address of a
+// chain, valued in a fiat currency at the price of the day of their block,
+// grouped by the time grouping. Amounts without a price are left out.
+func (pgb *ChainDB) AddressFiatFlow(chainType, address, currency string,
+	grouping dbtypes.TimeBasedGrouping) (*dbtypes.ChartsData, error) {
+	if grouping >= dbtypes.NumIntervals {
+		return nil, fmt.Errorf("invalid time grouping %d", grouping)
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.MakeSelectAddressFiatFlow(chainType, grouping.String()),
+		address, dbtypes.NormalizeCurrency(currency))
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	cd := new(dbtypes.ChartsData)
+	for rows.Next() {
+		var t time.Time
+		var received, sent float64
+		if err = rows.Scan(&t, &received, &sent); err != nil {
+			return nil, err
+		}
+		cd.Time = append(cd.Time, dbtypes.NewTimeDef(t))
+		cd.Received = append(cd.Received, received/atomsPerCoin)
+		cd.Sent = append(cd.Sent, sent/atomsPerCoin)
+		cd.Net = append(cd.Net, (received-sent)/atomsPerCoin)
+	}
+	return cd, pgb.replaceCancelError(rows.Err())
+}
+
+// backfillDailyPrices stores the daily prices of the coin of a chain in a
+// fiat currency, page by page backwards from the day of the Unix time toTs,
+// down to the day after stop or to the first day with a price. The number of
+// stored prices is returned.
+func (pgb *ChainDB) backfillDailyPrices(ctx context.Context, chainType, currency string, toTs, stop int64) (int, error) {
+	var n int
+	for toTs > stop {
+		prices, err := externalapi.GetDailyPriceHistory(chainType, currency, toTs, externalapi.CryptoCompareMaxDays)
+		if err != nil {
+			return n, err
+		}
+		i := sort.Search(len(prices), func(i int) bool {
+			return prices[i].Date > stop
+		})
+		if len(prices[i:]) == 0 {
+			return n, nil
+		}
+		if err = pgb.StoreDailyPrices(chainType, currency, prices[i:]); err != nil {
+			return n, err
+		}
+		n += len(prices) - i
+		if i > 0 {
+			return n, nil
+		}
+		toTs = prices[0].Date - secondsPerDay
+		select {
+		case <-ctx.Done():
+			return n, ctx.Err()
+		case <-time.After(priceRequestInterval):
+		}
+	}
+	return n, nil
+}
+
+// SyncDailyPrices backfills the daily prices of the coins of the chains in
+// the fiat currencies. The days since the last stored price are retrieved
+// first, the last one included since it may have been in progress, and then
+// the days before the first stored price, if an earlier backfill did not
+// complete.
+func (pgb *ChainDB) SyncDailyPrices(ctx context.Context, chains, currencies []string) error {
+	for _, chainType := range chains {
+		for _, currency := range currencies {
+			currency = dbtypes.NormalizeCurrency(currency)
+			first, last, err := pgb.DailyPriceRange(chainType, currency)
+			if err != nil {
+				return err
+			}
+			n, err := pgb.backfillDailyPrices(ctx, chainType, currency, time.Now().Unix(), last-secondsPerDay)
+			if err == nil && first > 0 {
+				var older int
+				older, err = pgb.backfillDailyPrices(ctx, chainType, currency, first-secondsPerDay, 0)
+				n += older
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				log.Warnf("Failed to sync the %s daily prices in %s: %v", chainType, currency, err)
+				continue
+			}
+			log.Infof("Stored %d %s daily prices in %s", n, chainType, currency)
+		}
+	}
+	return nil
+}
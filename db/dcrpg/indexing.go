@@ -187,6 +187,18 @@ func DeindexAddressTableOnVoutID(db *sql.DB) (err error) {
 	return
 }
 
+// IndexAddressTableOnAddressBlockTime creates the index for the addresses
+// table over address, block time and row ID, which cursor pagination seeks on.
+func IndexAddressTableOnAddressBlockTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexAddressTableOnAddressBlockTime)
+	return
+}
+
+func DeindexAddressTableOnAddressBlockTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexAddressTableOnAddressBlockTime)
+	return
+}
+
 // IndexAddressTableOnTxHash creates the index for the addresses table over
 // transaction hash.
 func IndexAddressTableOnTxHash(db *sql.DB) (err error) {
@@ -412,6 +424,31 @@ func DeindexSwapsTableOnHeight(db *sql.DB) (err error) {
 	return
 }
 
+// IndexSwapsTableOnGroupTime creates the index for the swaps table over the
+// contract time and group tx of the first contract of each group, which the
+// contract group list is ordered by.
+func IndexSwapsTableOnGroupTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexSwapsOnGroupTime)
+	return
+}
+
+func DeindexSwapsTableOnGroupTime(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexSwapsOnGroupTime)
+	return
+}
+
+// IndexSwapsTableOnGroupTx creates the index for the swaps table over group
+// tx.
+func IndexSwapsTableOnGroupTx(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.IndexSwapsOnGroupTx)
+	return
+}
+
+func DeindexSwapsTableOnGroupTx(db *sql.DB) (err error) {
+	_, err = db.Exec(internal.DeindexSwapsOnGroupTx)
+	return
+}
+
 func DeindexBtcSwapsTableOnHeight(db *sql.DB) (err error) {
 	_, err = db.Exec(internal.DeindexBtcSwapsOnHeight)
 	return
@@ -569,6 +606,10 @@ func (pgb *ChainDB) DeindexMutilchainWholeTable(chainType string) error {
 	if err != nil {
 		return err
 	}
+	err = HandlerDeindexFunc(pgb.db, mutilchainquery.MakeDeindexVoutAllTableOnScripthash(chainType))
+	if err != nil {
+		return err
+	}
 
 	if chainType == mutilchain.TYPEXMR {
 		// monero_outputs table
@@ -773,6 +814,7 @@ func (pgb *ChainDB) DeindexAll() error {
 		{DeindexAddressTableOnAddress},
 		{DeindexAddressTableOnVoutID},
 		{DeindexAddressTableOnTxHash},
+		{DeindexAddressTableOnAddressBlockTime},
 
 		// votes table
 		{DeindexVotesTableOnCandidate},
@@ -806,6 +848,8 @@ func (pgb *ChainDB) DeindexAll() error {
 
 		// swaps table
 		{DeindexSwapsTableOnHeight},
+		{DeindexSwapsTableOnGroupTime},
+		{DeindexSwapsTableOnGroupTx},
 		{DeindexBtcSwapsTableOnHeight},
 		{DeindexLtcSwapsTableOnHeight},
 	}
@@ -865,6 +909,9 @@ func (pgb *ChainDB) IndexMutilchainWholeTable(chainType string) error {
 	if err = HandlerMultichainIndexFunc(pgb.db, fmt.Sprintf("%svout_all on tx hash idx", chainType), mutilchainquery.MakeIndexVoutAllTableOnTxHashIdx(chainType)); err != nil {
 		return err
 	}
+	if err = HandlerMultichainIndexFunc(pgb.db, fmt.Sprintf("%svout_all on scripthash", chainType), mutilchainquery.MakeIndexVoutAllTableOnScripthash(chainType)); err != nil {
+		return err
+	}
 
 	if chainType == mutilchain.TYPEXMR {
 		// monero_outputs
@@ -1064,6 +1111,7 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 		{Msg: "addresses table on block time", IndexFunc: IndexBlockTimeOnTableAddress},
 		{Msg: "addresses table on address", IndexFunc: IndexAddressTableOnAddress}, // TODO: remove or redefine this or IndexAddressTableOnVoutID since that includes address too
 		{Msg: "addresses table on vout DB ID", IndexFunc: IndexAddressTableOnVoutID},
+		{Msg: "addresses table on address and block time", IndexFunc: IndexAddressTableOnAddressBlockTime},
 		//{Msg: "addresses table on matching tx hash", IndexFunc: IndexAddressTableOnMatchingTxHash},
 
 		// stats table
@@ -1075,6 +1123,8 @@ func (pgb *ChainDB) IndexAll(barLoad chan *dbtypes.ProgressBarLoad) error {
 
 		// swaps table
 		{Msg: "swaps on spend height", IndexFunc: IndexSwapsTableOnHeight},
+		{Msg: "swaps on group contract time", IndexFunc: IndexSwapsTableOnGroupTime},
+		{Msg: "swaps on group tx", IndexFunc: IndexSwapsTableOnGroupTx},
 		{Msg: "btc swaps on spend height", IndexFunc: IndexBtcSwapsTableOnHeight},
 		{Msg: "ltc swaps on spend height", IndexFunc: IndexLtcSwapsTableOnHeight},
 	}
@@ -1190,6 +1240,7 @@ func (pgb *ChainDB) IndexAddressTable(barLoad chan *dbtypes.ProgressBarLoad) err
 		{Msg: "block time", IndexFunc: IndexBlockTimeOnTableAddress},
 		{Msg: "vout Db ID", IndexFunc: IndexAddressTableOnVoutID},
 		{Msg: "tx hash", IndexFunc: IndexAddressTableOnTxHash},
+		{Msg: "address and block time", IndexFunc: IndexAddressTableOnAddressBlockTime},
 	}
 
 	for _, val := range addressesTableIndexes {
@@ -1219,6 +1270,7 @@ func (pgb *ChainDB) DeindexAddressTable() error {
 		{DeindexBlockTimeOnTableAddress},
 		{DeindexAddressTableOnVoutID},
 		{DeindexAddressTableOnTxHash},
+		{DeindexAddressTableOnAddressBlockTime},
 	}
 
 	var err error
@@ -190,6 +190,8 @@ func (pgb *ChainDB) GetMultichainTransactionHex(txid, chainType string) string {
 		return pgb.GetBTCTxHex(txid)
 	case mutilchain.TYPELTC:
 		return pgb.GetLTCTxHex(txid)
+	case mutilchain.TYPEXMR:
+		return pgb.GetXMRTxHex(txid)
 	}
 	return ""
 }
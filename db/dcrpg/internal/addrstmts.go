@@ -115,6 +115,12 @@ const (
 		` ON addresses(address);`
 	DeindexAddressTableOnAddress = `DROP INDEX IF EXISTS ` + IndexOfAddressTableOnAddress + ` CASCADE;`
 
+	// IndexAddressTableOnAddressBlockTime creates the index on (address,
+	// block_time DESC, id DESC) that selectAddressRowsByCursor seeks on.
+	IndexAddressTableOnAddressBlockTime = `CREATE INDEX IF NOT EXISTS ` + IndexOfAddressTableOnAddrTime +
+		` ON addresses(address, block_time DESC, id DESC);`
+	DeindexAddressTableOnAddressBlockTime = `DROP INDEX IF EXISTS ` + IndexOfAddressTableOnAddrTime + ` CASCADE;`
+
 	IndexAddressTableOnTxHash = `CREATE INDEX IF NOT EXISTS ` + IndexOfAddressTableOnTx +
 		` ON addresses(tx_hash, tx_vin_vout_index, is_funding);` // INCLUDE (valid_mainchain)? it's mutable tho
 	DeindexAddressTableOnTxHash = `DROP INDEX IF EXISTS ` + IndexOfAddressTableOnTx + ` CASCADE;`
@@ -326,6 +332,18 @@ ORDER BY count, is_funding;`
 		ORDER BY block_time DESC, tx_hash ASC
 		LIMIT $4 OFFSET $5;`
 
+	// selectAddressRowsByCursor selects the valid mainchain rows of an address
+	// after the page cursor (block_time $2, id $3), in descending (block_time,
+	// id) order, which is a range scan of IndexOfAddressTableOnAddrTime. See
+	// dbtypes.PageCursor. The %s verb is for the credit or debit condition.
+	selectAddressRowsByCursor = `SELECT id, address, matching_tx_hash, tx_hash, tx_type,
+			valid_mainchain, tx_vin_vout_index, block_time, tx_vin_vout_row_id, value, is_funding
+		FROM addresses
+		WHERE address = $1 AND valid_mainchain %s
+			AND (block_time, id) < ($2, $3)
+		ORDER BY block_time DESC, id DESC
+		LIMIT $4;`
+
 	// SelectAddressLimitNByAddressSubQry was used in certain cases prior to
 	// sorting the block_time_index.
 	// SelectAddressLimitNByAddressSubQry = `WITH these AS (SELECT ` + addrsColumnNames +
@@ -643,6 +661,19 @@ func MakeSelectAddressTimeGroupingCount(group string) string {
 	return formatGroupingQuery(selectAddressTimeGroupingCount, group, "block_time")
 }
 
+// MakeSelectAddressRowsByCursor returns the selectAddressRowsByCursor query
+// for all rows of an address, or for only its credit or debit rows.
+func MakeSelectAddressRowsByCursor(credit, debit bool) string {
+	var cond string
+	switch {
+	case credit:
+		cond = "AND is_funding"
+	case debit:
+		cond = "AND NOT is_funding"
+	}
+	return fmt.Sprintf(selectAddressRowsByCursor, cond)
+}
+
 // Since date_trunc function doesn't have an option to group by "all" grouping,
 // formatGroupingQuery removes the date_trunc from the sql query as its not applicable.
 func formatGroupingQuery(mainQuery, group, column string) string {
@@ -0,0 +1,65 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// These queries relate to the "api_keys" table, which holds the API keys and
+// their limits, and to the "api_key_usage" table, which counts the requests
+// made with each key per UTC day and endpoint.
+const (
+	CreateAPIKeysTable = `CREATE TABLE IF NOT EXISTS api_keys (
+		id SERIAL8 PRIMARY KEY,
+		key_hash TEXT NOT NULL UNIQUE,
+		display TEXT NOT NULL,
+		name TEXT NOT NULL,
+		rate_limit FLOAT8 NOT NULL,
+		burst INT4 NOT NULL,
+		daily_quota INT8 NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		revoked_at TIMESTAMPTZ
+	);`
+
+	CreateAPIKeyUsageTable = `CREATE TABLE IF NOT EXISTS api_key_usage (
+		key_id INT8 NOT NULL REFERENCES api_keys(id),
+		day DATE NOT NULL,
+		endpoint TEXT NOT NULL,
+		count INT8 NOT NULL,
+		PRIMARY KEY (key_id, day, endpoint)
+	);`
+
+	InsertAPIKey = `INSERT INTO api_keys (key_hash, display, name, rate_limit, burst, daily_quota)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at;`
+
+	// RevokeAPIKey revokes a key by id. Revoking a revoked key keeps its
+	// original revocation time.
+	RevokeAPIKey = `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1;`
+
+	// UpdateAPIKeyLimits sets the rate, burst and daily quota of a key.
+	UpdateAPIKeyLimits = `UPDATE api_keys SET rate_limit = $2, burst = $3, daily_quota = $4
+		WHERE id = $1;`
+
+	selectAPIKeyColumns = `SELECT id, name, display, rate_limit, burst, daily_quota,
+			created_at, revoked_at
+		FROM api_keys`
+
+	SelectAPIKeyByHash = selectAPIKeyColumns + ` WHERE key_hash = $1;`
+
+	SelectAPIKeys = selectAPIKeyColumns + ` ORDER BY id;`
+
+	// UpsertAPIKeyUsage adds to the request count of a key, day and endpoint.
+	UpsertAPIKeyUsage = `INSERT INTO api_key_usage (key_id, day, endpoint, count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (key_id, day, endpoint) DO UPDATE
+		SET count = api_key_usage.count + EXCLUDED.count;`
+
+	SelectAPIKeyDailyUsage = `SELECT COALESCE(SUM(count), 0) FROM api_key_usage
+		WHERE key_id = $1 AND day = $2;`
+
+	// SelectAPIKeyUsageSince lists the request counts of a key by day and
+	// endpoint, starting with day $2.
+	SelectAPIKeyUsageSince = `SELECT key_id, day, endpoint, count FROM api_key_usage
+		WHERE key_id = $1 AND day >= $2
+		ORDER BY day, endpoint;`
+)
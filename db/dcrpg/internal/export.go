@@ -0,0 +1,172 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+import "fmt"
+
+// These queries are used for the data exports. The first %s verb of each query
+// is for the optional fiat value columns, and the second one for the join of
+// the daily_market table that they require. The last argument of each query is
+// the maximum number of rows.
+const (
+	selectAddressExportRows = `SELECT addresses.tx_hash,
+			CASE WHEN addresses.is_funding THEN 1 ELSE -1 END AS direction,
+			addresses.tx_vin_vout_index AS io_index, addresses.valid_mainchain, addresses.value,
+			addresses.block_time AS time_stamp, addresses.tx_type, addresses.matching_tx_hash %s
+		FROM addresses %s
+		WHERE addresses.address = $1
+		ORDER BY addresses.block_time DESC, addresses.tx_hash ASC
+		LIMIT $2;`
+
+	selectBlockExportRows = `SELECT blocks.height, blocks.hash, blocks.time, blocks.size, blocks.numtx,
+			blocks.num_rtx, blocks.num_stx, blocks.voters, blocks.fresh_stake, blocks.revocations,
+			blocks.pool_size, blocks.sbits, blocks.difficulty, blocks.is_valid %s
+		FROM blocks %s
+		WHERE blocks.is_mainchain AND blocks.height BETWEEN $1 AND $2
+		ORDER BY blocks.height
+		LIMIT $3;`
+
+	selectTreasuryExportRows = `SELECT treasury.tx_hash, treasury.tx_type, treasury.value,
+			treasury.block_hash, treasury.block_height, treasury.block_time %s
+		FROM treasury %s
+		WHERE treasury.is_mainchain
+		ORDER BY treasury.block_height DESC, treasury.tx_hash
+		LIMIT $1;`
+
+	selectSwapExportRows = `SELECT swaps.group_tx, swaps.contract_tx, swaps.contract_vout,
+			swaps.contract_time, swaps.spend_tx, swaps.spend_vin, swaps.spend_height,
+			swaps.p2sh_addr, swaps.value, swaps.lock_time, swaps.target_token, swaps.is_refund,
+			encode(swaps.secret_hash, 'hex') AS secret_hash %s
+		FROM swaps %s
+		ORDER BY swaps.contract_time DESC, swaps.spend_tx, swaps.spend_vin
+		LIMIT $1;`
+
+	// exportFiatPriceColumn is the daily close price of DCR in USD, and
+	// exportFiatValueColumn the USD value of the atoms of the row.
+	exportFiatPriceColumn = `, daily_market.close AS price_usd`
+	exportFiatValueColumn = `, ROUND((%s * daily_market.close / 1e8)::NUMERIC, 2)::FLOAT8 AS value_usd`
+
+	// exportFiatJoin joins the daily_market row of the UTC day of a time.
+	exportFiatJoin = `LEFT JOIN daily_market
+			ON (to_timestamp(daily_market.date) AT TIME ZONE 'UTC')::DATE = (%s AT TIME ZONE 'UTC')::DATE`
+
+	// The BTC and LTC address exports list the funding and spending rows of
+	// the outputs of an address. The %[1]s verb is the chain type.
+	selectMultichainAddressExportRows = `SELECT * FROM (
+			SELECT a.funding_tx_hash AS tx_hash, 1 AS direction, a.funding_tx_vout_index AS io_index,
+				a.value, tx.block_height, tx.block_time AS time_stamp
+			FROM %[1]saddresses a
+			JOIN %[1]stransactions tx ON tx.id = a.funding_tx_row_id
+			WHERE a.address = $1
+			UNION ALL
+			SELECT a.spending_tx_hash, -1, a.spending_tx_vin_index,
+				a.value, tx.block_height, tx.block_time
+			FROM %[1]saddresses a
+			JOIN %[1]stransactions tx ON tx.id = a.spending_tx_row_id
+			WHERE a.address = $1 AND a.spending_tx_row_id > 0
+		) io
+		ORDER BY block_height DESC, tx_hash, direction, io_index
+		LIMIT $2;`
+
+	selectMultichainBlockExportRows = `SELECT height, hash, time, size, numtx, num_vins, num_vouts,
+			total_sent, fees, difficulty
+		FROM %sblocks
+		WHERE height BETWEEN $1 AND $2
+		ORDER BY height
+		LIMIT $3;`
+
+	// The ledger queries of the cost basis reports net the rows of a set of
+	// addresses by transaction, up to a Unix time, with the fees and total
+	// inputs of the transactions and the transactions linked to the received
+	// and spent outputs. The arguments are the addresses, the time and the
+	// maximum number of rows.
+	SelectAddressLedger = `SELECT a.tx_hash, EXTRACT(EPOCH FROM a.block_time)::INT8 AS time_stamp,
+			SUM(CASE WHEN a.is_funding THEN a.value ELSE 0 END)::INT8 AS received,
+			SUM(CASE WHEN a.is_funding THEN 0 ELSE a.value END)::INT8 AS sent,
+			COALESCE(MAX(tx.fees), 0) AS fees, COALESCE(MAX(tx.spent), 0) AS spent,
+			array_agg(DISTINCT a.matching_tx_hash) FILTER (WHERE a.is_funding AND a.matching_tx_hash <> '') AS received_links,
+			array_agg(DISTINCT a.matching_tx_hash) FILTER (WHERE NOT a.is_funding AND a.matching_tx_hash <> '') AS sent_links
+		FROM addresses a
+		LEFT JOIN transactions tx ON tx.tx_hash = a.tx_hash AND tx.is_mainchain
+		WHERE a.address = ANY($1) AND a.valid_mainchain AND a.block_time <= to_timestamp($2)
+		GROUP BY a.tx_hash, a.block_time
+		ORDER BY a.block_time, a.tx_hash
+		LIMIT $3;`
+
+	// The %[1]s verb is the chain type.
+	selectMultichainAddressLedger = `SELECT tx.tx_hash, tx.block_time AS time_stamp,
+			SUM(io.received)::INT8 AS received, SUM(io.sent)::INT8 AS sent,
+			COALESCE(MAX(tx.fees), 0) AS fees, COALESCE(MAX(tx.spent), 0) AS spent,
+			array_agg(DISTINCT io.link) FILTER (WHERE io.received > 0 AND io.link <> '') AS received_links,
+			array_agg(DISTINCT io.link) FILTER (WHERE io.sent > 0 AND io.link <> '') AS sent_links
+		FROM (
+			SELECT funding_tx_row_id AS tx_id, value AS received, 0::INT8 AS sent, spending_tx_hash AS link
+			FROM %[1]saddresses
+			WHERE address = ANY($1)
+			UNION ALL
+			SELECT spending_tx_row_id, 0, value, funding_tx_hash
+			FROM %[1]saddresses
+			WHERE address = ANY($1) AND spending_tx_row_id > 0
+		) io
+		JOIN %[1]stransactions tx ON tx.id = io.tx_id
+		WHERE tx.block_time <= $2
+		GROUP BY tx.id, tx.tx_hash, tx.block_time
+		ORDER BY tx.block_time, tx.id
+		LIMIT $3;`
+)
+
+// makeExportQuery formats an export query with the fiat columns if fiat is
+// true. The value column is omitted if valueExpr is empty.
+func makeExportQuery(query string, fiat bool, valueExpr, timeExpr string) string {
+	if !fiat {
+		return fmt.Sprintf(query, "", "")
+	}
+	columns := exportFiatPriceColumn
+	if valueExpr != "" {
+		columns += fmt.Sprintf(exportFiatValueColumn, valueExpr)
+	}
+	return fmt.Sprintf(query, columns, fmt.Sprintf(exportFiatJoin, timeExpr))
+}
+
+// MakeSelectAddressExportRows returns the address rows export query, with
+// optional fiat values.
+func MakeSelectAddressExportRows(fiat bool) string {
+	return makeExportQuery(selectAddressExportRows, fiat, "addresses.value", "addresses.block_time")
+}
+
+// MakeSelectBlockExportRows returns the block summaries export query, with the
+// optional fiat price of the day of each block.
+func MakeSelectBlockExportRows(fiat bool) string {
+	return makeExportQuery(selectBlockExportRows, fiat, "", "blocks.time")
+}
+
+// MakeSelectTreasuryExportRows returns the treasury txns export query, with
+// optional fiat values.
+func MakeSelectTreasuryExportRows(fiat bool) string {
+	return makeExportQuery(selectTreasuryExportRows, fiat, "treasury.value", "treasury.block_time")
+}
+
+// MakeSelectSwapExportRows returns the atomic swaps export query, with
+// optional fiat values.
+func MakeSelectSwapExportRows(fiat bool) string {
+	return makeExportQuery(selectSwapExportRows, fiat, "swaps.value", "to_timestamp(swaps.contract_time)")
+}
+
+// MakeSelectMultichainAddressExportRows returns the address rows export query
+// of a BTC or LTC chain.
+func MakeSelectMultichainAddressExportRows(chainType string) string {
+	return fmt.Sprintf(selectMultichainAddressExportRows, chainType)
+}
+
+// MakeSelectMultichainBlockExportRows returns the block summaries export query
+// of a BTC or LTC chain.
+func MakeSelectMultichainBlockExportRows(chainType string) string {
+	return fmt.Sprintf(selectMultichainBlockExportRows, chainType)
+}
+
+// MakeSelectMultichainAddressLedger returns the cost basis ledger query of a
+// BTC or LTC chain.
+func MakeSelectMultichainAddressLedger(chainType string) string {
+	return fmt.Sprintf(selectMultichainAddressLedger, chainType)
+}
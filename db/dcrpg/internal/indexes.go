@@ -38,6 +38,7 @@ const (
 	IndexOfAddressTableOnBlockTime  = "block_time_index"
 	IndexOfAddressTableOnTx         = "uix_addresses_funding_tx"
 	IndexOfAddressTableOnMatchingTx = "matching_tx_hash_index"
+	IndexOfAddressTableOnAddrTime   = "uix_addresses_address_block_time"
 
 	// tickets table
 
@@ -81,12 +82,25 @@ const (
 
 	IndexOfTreasuryTableOnTxHash = "uix_treasury_tx_hash"
 	IndexOfTreasuryTableOnHeight = "idx_treasury_height"
+
+	// mix_stats table
+
+	IndexOfMixStatsOnBlockTime = "idx_mix_stats_block_time"
+
+	// webhook_outbox table
+
+	IndexOfWebhookOutboxOnNextAttempt = "idx_webhook_outbox_next_attempt"
+
+	// market_depth table
+
+	IndexOfMarketDepthOnMarketTime = "idx_market_depth_market_time"
 )
 
 // AddressesIndexNames are the names of the indexes on the addresses table.
 var AddressesIndexNames = []string{IndexOfAddressTableOnAddress,
 	IndexOfAddressTableOnVoutID, IndexOfAddressTableOnBlockTime,
-	IndexOfAddressTableOnTx, IndexOfAddressTableOnMatchingTx}
+	IndexOfAddressTableOnTx, IndexOfAddressTableOnMatchingTx,
+	IndexOfAddressTableOnAddrTime}
 
 func GetMutilchainAddressesIndexNames(chainType string) []string {
 	res := make([]string, 0)
@@ -155,6 +169,11 @@ func GetMutilchainIndexDescriptionsMap(chainType string) map[string]string {
 	tempIndex = fmt.Sprintf("uix_%svout_all_txhash_ind", chainType)
 	result[tempIndex] = fmt.Sprintf("create %s index on %s", tempIndex, chainType)
 
+	if chainType == mutilchain.TYPEBTC || chainType == mutilchain.TYPELTC {
+		tempIndex = fmt.Sprintf("ix_%svout_scripthash", chainType)
+		result[tempIndex] = fmt.Sprintf("create %s index on %s", tempIndex, chainType)
+	}
+
 	if chainType == mutilchain.TYPEXMR {
 		tempIndex = "uix_monero_outputs_txhash"
 		result[tempIndex] = fmt.Sprintf("create %s index on monero", tempIndex)
@@ -340,6 +359,7 @@ var IndexDescriptions = map[string]string{
 	IndexOfAddressTableOnBlockTime:        "addresses table on block time",
 	IndexOfAddressTableOnTx:               "addresses table on transaction hash",
 	IndexOfAddressTableOnMatchingTx:       "addresses table on matching tx hash",
+	IndexOfAddressTableOnAddrTime:         "addresses table on address, block time, and row id",
 	IndexOfTicketsTableOnHashes:           "tickets table on block hash and transaction hash",
 	IndexOfTicketsTableOnTxRowID:          "tickets table on transactions table row ID",
 	IndexOfTicketsTableOnPoolStatus:       "tickets table on pool status",
@@ -0,0 +1,67 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// These queries relate to the market tables. The "market_candles" table holds
+// the candlesticks of the exchange markets by bin, and the "market_depth"
+// table periodic snapshots of their order books with their liquidity.
+const (
+	CreateMarketCandlesTable = `CREATE TABLE IF NOT EXISTS market_candles (
+		chain TEXT NOT NULL,
+		token TEXT NOT NULL,
+		bin TEXT NOT NULL,
+		start TIMESTAMPTZ NOT NULL,
+		open FLOAT8 NOT NULL,
+		high FLOAT8 NOT NULL,
+		low FLOAT8 NOT NULL,
+		close FLOAT8 NOT NULL,
+		volume FLOAT8 NOT NULL,
+		PRIMARY KEY (chain, token, bin, start)
+	);`
+
+	CreateMarketDepthTable = `CREATE TABLE IF NOT EXISTS market_depth (
+		id SERIAL8 PRIMARY KEY,
+		chain TEXT NOT NULL,
+		token TEXT NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		mid_gap FLOAT8 NOT NULL,
+		spread FLOAT8 NOT NULL,
+		bid_depth FLOAT8 NOT NULL,
+		ask_depth FLOAT8 NOT NULL,
+		bids JSONB NOT NULL,
+		asks JSONB NOT NULL
+	);`
+
+	IndexMarketDepthOnMarketTime = `CREATE INDEX IF NOT EXISTS ` + IndexOfMarketDepthOnMarketTime +
+		` ON market_depth(chain, token, time);`
+
+	// UpsertMarketCandle replaces a candlestick that was in progress when it
+	// was stored.
+	UpsertMarketCandle = `INSERT INTO market_candles (chain, token, bin, start,
+			open, high, low, close, volume)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (chain, token, bin, start) DO UPDATE
+		SET open = $5, high = $6, low = $7, close = $8, volume = $9;`
+
+	// SelectMarketCandles selects the latest $4 candlesticks of a market and
+	// bin in ascending order of start time.
+	SelectMarketCandles = `SELECT start, open, high, low, close, volume
+		FROM (
+			SELECT start, open, high, low, close, volume
+			FROM market_candles
+			WHERE chain = $1 AND token = $2 AND bin = $3
+			ORDER BY start DESC
+			LIMIT $4
+		) AS latest
+		ORDER BY start;`
+
+	InsertMarketDepth = `INSERT INTO market_depth (chain, token, time, mid_gap,
+			spread, bid_depth, ask_depth, bids, asks)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+
+	SelectMarketLiquidity = `SELECT time, mid_gap, spread, bid_depth, ask_depth
+		FROM market_depth
+		WHERE chain = $1 AND token = $2
+		ORDER BY time;`
+)
@@ -0,0 +1,94 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+// These queries relate primarily to the "mix_stats" table, which holds
+// per-block, per-denomination CoinShuffle++ mix statistics derived from the
+// mix_count and mix_denom columns of the transactions table.
+const (
+	CreateMixStatsTable = `CREATE TABLE IF NOT EXISTS mix_stats (
+		block_height INT8 NOT NULL,
+		block_time TIMESTAMPTZ NOT NULL,
+		mix_denom INT8 NOT NULL,
+		mixes INT4 NOT NULL,
+		participants INT8 NOT NULL,
+		volume INT8 NOT NULL,
+		PRIMARY KEY (block_height, mix_denom)
+	);`
+
+	IndexMixStatsOnBlockTime = `CREATE INDEX IF NOT EXISTS ` + IndexOfMixStatsOnBlockTime +
+		` ON mix_stats(block_time);`
+
+	SelectMixStatsMaxHeight = `SELECT COALESCE(MAX(block_height), -1) FROM mix_stats;`
+
+	// DeleteMixStatsFromHeight removes the rows at and above the given height,
+	// which is required before recomputing a block that may replace a block
+	// from a different chain.
+	DeleteMixStatsFromHeight = `DELETE FROM mix_stats WHERE block_height >= $1;`
+
+	// InsertMixStatsRange aggregates the mainchain mix transactions in the
+	// block range [$1, $2] into mix_stats rows. The number of participants of
+	// a mix is its number of equal-valued mixed outputs.
+	InsertMixStatsRange = `INSERT INTO mix_stats (block_height, block_time,
+			mix_denom, mixes, participants, volume)
+		SELECT block_height, MIN(block_time), mix_denom, COUNT(*),
+			SUM(mix_count), SUM(mix_count * mix_denom)
+		FROM transactions
+		WHERE is_mainchain AND mix_count > 0
+			AND block_height BETWEEN $1 AND $2
+		GROUP BY block_height, mix_denom
+		ON CONFLICT (block_height, mix_denom) DO UPDATE
+		SET block_time = EXCLUDED.block_time,
+			mixes = EXCLUDED.mixes,
+			participants = EXCLUDED.participants,
+			volume = EXCLUDED.volume;`
+
+	SelectMixStatsByBlockRange = `SELECT block_height, block_time, mix_denom,
+			mixes, participants, volume
+		FROM mix_stats
+		WHERE block_height BETWEEN $1 AND $2
+		ORDER BY block_height, mix_denom;`
+
+	SelectMixStatsByDay = `SELECT date_trunc('day', block_time AT TIME ZONE 'UTC') AS day,
+			mix_denom, SUM(mixes), SUM(participants), SUM(volume)
+		FROM mix_stats
+		WHERE block_time >= $1 AND block_time < $2
+		GROUP BY day, mix_denom
+		ORDER BY day, mix_denom;`
+
+	SelectMixStatsByDenom = `SELECT mix_denom, SUM(mixes), SUM(participants), SUM(volume)
+		FROM mix_stats
+		GROUP BY mix_denom
+		ORDER BY mix_denom;`
+
+	SelectMixTxnsCount        = `SELECT COALESCE(SUM(mixes), 0) FROM mix_stats;`
+	SelectMixTxnsCountByDenom = `SELECT COALESCE(SUM(mixes), 0) FROM mix_stats WHERE mix_denom = $1;`
+
+	SelectMixTxns = `SELECT tx_hash, block_height, block_time, mix_count, mix_denom, fees, size
+		FROM transactions
+		WHERE is_mainchain AND mix_count > 0
+		ORDER BY block_height DESC, block_index
+		LIMIT $1 OFFSET $2;`
+
+	SelectMixTxnsByDenom = `SELECT tx_hash, block_height, block_time, mix_count, mix_denom, fees, size
+		FROM transactions
+		WHERE is_mainchain AND mix_count > 0 AND mix_denom = $3
+		ORDER BY block_height DESC, block_index
+		LIMIT $1 OFFSET $2;`
+
+	// SelectMixTxnsByCursor selects the mix transactions after the page
+	// cursor ($2, $3), in descending (block height, tx index) order. Mixes
+	// are regular tree transactions, so the tx index is the block index.
+	SelectMixTxnsByCursor = `SELECT tx_hash, block_height, block_time, mix_count, mix_denom, fees, size, block_index
+		FROM transactions
+		WHERE is_mainchain AND mix_count > 0 AND (block_height, block_index) < ($2, $3)
+		ORDER BY block_height DESC, block_index DESC
+		LIMIT $1;`
+
+	SelectMixTxnsByDenomByCursor = `SELECT tx_hash, block_height, block_time, mix_count, mix_denom, fees, size, block_index
+		FROM transactions
+		WHERE is_mainchain AND mix_count > 0 AND mix_denom = $4 AND (block_height, block_index) < ($2, $3)
+		ORDER BY block_height DESC, block_index DESC
+		LIMIT $1;`
+)
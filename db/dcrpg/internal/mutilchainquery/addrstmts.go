@@ -168,6 +168,51 @@ LIMIT 1;`
 	SelectAddressIDByVoutIDAddress = `SELECT id FROM %saddresses
 		WHERE address=$1 and vout_row_id=$2;`
 
+	// SelectAddressUnspentWithTxn selects the unspent outputs paying to an
+	// address, with the height, hash and time of the funding block and the
+	// pkScript.
+	SelectAddressUnspentWithTxn = `SELECT a.funding_tx_hash, a.funding_tx_vout_index, a.value,
+			t.block_height, t.block_hash, t.block_time, v.pkscript
+		FROM %saddresses a
+		JOIN %stransactions t ON t.tx_hash = a.funding_tx_hash
+		JOIN %svouts v ON v.id = a.vout_row_id
+		WHERE a.address = $1 AND a.spending_tx_row_id IS NULL
+		ORDER BY t.block_height DESC, a.funding_tx_hash, a.funding_tx_vout_index;`
+
+	// SelectAddressesTxHashes selects the hashes of all funding and spending
+	// transactions of a list of addresses, most recent first.
+	SelectAddressesTxHashes = `SELECT h.tx_hash
+		FROM (
+			SELECT funding_tx_hash AS tx_hash FROM %saddresses WHERE address = ANY($1)
+			UNION
+			SELECT spending_tx_hash FROM %saddresses
+			WHERE address = ANY($1) AND spending_tx_hash <> ''
+		) h
+		JOIN %stransactions t ON t.tx_hash = h.tx_hash
+		GROUP BY h.tx_hash
+		ORDER BY MAX(t.block_time) DESC, h.tx_hash;`
+
+	// SelectAddressTxHashesWithHeight selects the hashes and block heights of
+	// all funding and spending transactions of an address, in block order.
+	SelectAddressTxHashesWithHeight = `SELECT h.tx_hash, MAX(t.block_height)
+		FROM (
+			SELECT funding_tx_hash AS tx_hash FROM %saddresses WHERE address = $1
+			UNION
+			SELECT spending_tx_hash FROM %saddresses
+			WHERE address = $1 AND spending_tx_hash <> ''
+		) h
+		JOIN %stransactions t ON t.tx_hash = h.tx_hash
+		GROUP BY h.tx_hash
+		ORDER BY MAX(t.block_height), MAX(t.block_index), h.tx_hash;`
+
+	// SelectSpendingTxnsByFundingTxWithHeight selects the spending transaction,
+	// input index and block height for each spent output of a transaction.
+	SelectSpendingTxnsByFundingTxWithHeight = `SELECT a.funding_tx_vout_index,
+			a.spending_tx_hash, a.spending_tx_vin_index, t.block_height
+		FROM %saddresses a
+		LEFT JOIN %stransactions t ON t.tx_hash = a.spending_tx_hash
+		WHERE a.funding_tx_hash = $1 AND a.spending_tx_hash <> '';`
+
 	SetAddressSpendingForID = `UPDATE %saddresses SET spending_tx_row_id = $2, 
 		spending_tx_hash = $3, spending_tx_vin_index = $4, vin_row_id = $5 
 		WHERE id=$1;`
@@ -228,6 +273,22 @@ func MakeSelectAddressLimitNByAddress(chainType string) string {
 	return fmt.Sprintf(SelectAddressLimitNByAddress, chainType)
 }
 
+func MakeSelectAddressUnspentWithTxn(chainType string) string {
+	return fmt.Sprintf(SelectAddressUnspentWithTxn, chainType, chainType, chainType)
+}
+
+func MakeSelectAddressesTxHashes(chainType string) string {
+	return fmt.Sprintf(SelectAddressesTxHashes, chainType, chainType, chainType)
+}
+
+func MakeSelectAddressTxHashesWithHeight(chainType string) string {
+	return fmt.Sprintf(SelectAddressTxHashesWithHeight, chainType, chainType, chainType)
+}
+
+func MakeSelectSpendingTxnsByFundingTxWithHeight(chainType string) string {
+	return fmt.Sprintf(SelectSpendingTxnsByFundingTxWithHeight, chainType, chainType)
+}
+
 func IndexAddressTableOnFundingTxStmt(chainType string) string {
 	return fmt.Sprintf(IndexAddressTableOnFundingTx, chainType, chainType)
 }
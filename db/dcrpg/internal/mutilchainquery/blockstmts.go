@@ -136,7 +136,6 @@ const (
 	CheckExistBLock         = `SELECT EXISTS(SELECT 1 FROM %sblocks WHERE height = $1);`
 	SelectBlockHeightByHash = `SELECT height FROM %sblocks WHERE hash = $1;`
 	SelectBlockHashByHeight = `SELECT hash FROM %sblocks WHERE height = $1;`
-	DeleteOlderThan20Blocks = `DELETE FROM %sblocks WHERE height < $1;`
 	SelectMinBlockHeight    = `SELECT min(height) FROM %sblocks;`
 )
 
@@ -184,10 +183,6 @@ func MakeCheckExistBLock(chainType string) string {
 	return fmt.Sprintf(CheckExistBLock, chainType)
 }
 
-func MakeDeleteOlderThan20Blocks(chainType string) string {
-	return fmt.Sprintf(DeleteOlderThan20Blocks, chainType)
-}
-
 func MakeSelectBlockHeightByHash(chainType string) string {
 	return fmt.Sprintf(SelectBlockHeightByHash, chainType)
 }
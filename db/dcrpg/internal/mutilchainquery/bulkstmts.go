@@ -0,0 +1,133 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// Statements of the COPY-based import of the whole chain. The rows of a batch
+// of blocks are copied to temporary staging tables, dropped at the end of the
+// DB transaction of the batch, and moved to the whole-chain tables with their
+// pre-assigned IDs.
+const (
+	CreateSyncCheckpointTable = `CREATE TABLE IF NOT EXISTS multichain_sync_checkpoints (
+		chain_type TEXT PRIMARY KEY,
+		height INT8 NOT NULL,
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);`
+
+	SelectSyncCheckpoint = `SELECT height FROM multichain_sync_checkpoints WHERE chain_type = $1;`
+
+	UpsertSyncCheckpoint = `INSERT INTO multichain_sync_checkpoints (chain_type, height, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (chain_type) DO UPDATE
+		SET height = GREATEST(multichain_sync_checkpoints.height, EXCLUDED.height), updated_at = now();`
+
+	// SelectRemainingNotSyncedHeightsFrom is SelectRemainingNotSyncedHeights
+	// limited to the heights after a checkpoint.
+	SelectRemainingNotSyncedHeightsFrom = `SELECT a.height
+		FROM generate_series($2::INT8, $1::INT8) AS a(height)
+		WHERE NOT EXISTS (SELECT 1 FROM %sblocks_all b WHERE b.height = a.height AND b.synced = true)
+		ORDER BY a.height ASC;`
+
+	DeleteUnsyncedBlocksAll = `DELETE FROM %sblocks_all WHERE synced IS NOT TRUE;`
+
+	// ReserveRowIDs takes the IDs of the given number of rows from the serial
+	// sequence of a table.
+	ReserveRowIDs = `SELECT nextval(pg_get_serial_sequence('%s', 'id')) FROM generate_series(1, $1);`
+
+	CreateBulkVinStage     = `CREATE TEMP TABLE %sbulk_vins (LIKE %svins_all, tx_row_id INT8) ON COMMIT DROP;`
+	CreateBulkVoutStage    = `CREATE TEMP TABLE %sbulk_vouts (LIKE %svouts_all) ON COMMIT DROP;`
+	CreateBulkTxStage      = `CREATE TEMP TABLE %sbulk_transactions (LIKE %stransactions) ON COMMIT DROP;`
+	CreateBulkAddressStage = `CREATE TEMP TABLE %sbulk_addresses (LIKE %saddresses) ON COMMIT DROP;`
+
+	DeleteBulkVouts     = `DELETE FROM %svouts_all WHERE tx_hash = ANY($1);`
+	DeleteBulkTxns      = `DELETE FROM %stransactions WHERE tx_hash = ANY($1);`
+	DeleteBulkAddresses = `DELETE FROM %saddresses WHERE funding_tx_hash = ANY($1);`
+
+	MoveBulkVins = `INSERT INTO %svins_all (id, tx_hash, tx_index, tx_tree, prev_tx_hash, prev_tx_index,
+		prev_tx_tree, value_in)
+		SELECT id, tx_hash, tx_index, tx_tree, prev_tx_hash, prev_tx_index, prev_tx_tree, value_in
+		FROM %sbulk_vins;`
+	MoveBulkVouts = `INSERT INTO %svouts_all (id, tx_hash, tx_index, tx_tree, value, version, pkscript,
+		script_req_sigs, script_type, script_addresses)
+		SELECT id, tx_hash, tx_index, tx_tree, value, version, pkscript, script_req_sigs, script_type,
+		script_addresses FROM %sbulk_vouts;`
+	MoveBulkTxns = `INSERT INTO %stransactions (id, block_hash, block_height, block_time, time, tx_type,
+		version, tree, tx_hash, block_index, lock_time, expiry, size, spent, sent, fees, num_vin, vins,
+		vin_db_ids, num_vout, vouts, vout_db_ids)
+		SELECT id, block_hash, block_height, block_time, time, tx_type, version, tree, tx_hash,
+		block_index, lock_time, expiry, size, spent, sent, fees, num_vin, vins, vin_db_ids, num_vout,
+		vouts, vout_db_ids FROM %sbulk_transactions;`
+	MoveBulkAddresses = `INSERT INTO %saddresses (id, address, funding_tx_row_id, funding_tx_hash,
+		funding_tx_vout_index, vout_row_id, value)
+		SELECT id, address, funding_tx_row_id, funding_tx_hash, funding_tx_vout_index, vout_row_id, value
+		FROM %sbulk_addresses;`
+
+	// SetBulkAddressesSpending sets the spending fields of the address rows of
+	// the outpoints spent by the staged inputs, including those funded in the
+	// same batch.
+	SetBulkAddressesSpending = `UPDATE %saddresses a
+		SET spending_tx_row_id = v.tx_row_id, spending_tx_hash = v.tx_hash,
+			spending_tx_vin_index = v.tx_index, vin_row_id = v.id
+		FROM %sbulk_vins v
+		WHERE a.funding_tx_hash = v.prev_tx_hash AND a.funding_tx_vout_index = v.prev_tx_index;`
+
+	insertBlockAllSyncedRow = `INSERT INTO %sblocks_all (
+		hash, height, size, is_valid, version,
+		numtx, time, nonce, pool_size, bits,
+		difficulty, previous_hash, num_vins, num_vouts, fees, total_sent, synced)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, true);`
+)
+
+func MakeSelectRemainingNotSyncedHeightsFrom(chainType string) string {
+	return fmt.Sprintf(SelectRemainingNotSyncedHeightsFrom, chainType)
+}
+
+func MakeDeleteUnsyncedBlocksAll(chainType string) string {
+	return fmt.Sprintf(DeleteUnsyncedBlocksAll, chainType)
+}
+
+func MakeReserveRowIDs(table string) string {
+	return fmt.Sprintf(ReserveRowIDs, table)
+}
+
+// MakeCreateBulkStages returns the statements creating the staging tables of
+// the inputs, outputs, transactions and address rows of a chain.
+func MakeCreateBulkStages(chainType string) []string {
+	return []string{
+		fmt.Sprintf(CreateBulkVinStage, chainType, chainType),
+		fmt.Sprintf(CreateBulkVoutStage, chainType, chainType),
+		fmt.Sprintf(CreateBulkTxStage, chainType, chainType),
+		fmt.Sprintf(CreateBulkAddressStage, chainType, chainType),
+	}
+}
+
+// MakeDeleteBulkConflicts returns the statements deleting the rows of a list
+// of transactions left by an earlier, interrupted store of their blocks.
+func MakeDeleteBulkConflicts(chainType string) []string {
+	return []string{
+		fmt.Sprintf(DeleteVinAllWithTxHashArray, chainType),
+		fmt.Sprintf(DeleteBulkVouts, chainType),
+		fmt.Sprintf(DeleteBulkTxns, chainType),
+		fmt.Sprintf(DeleteBulkAddresses, chainType),
+	}
+}
+
+// MakeMoveBulkStages returns the statements moving the staged rows of a chain
+// to its tables.
+func MakeMoveBulkStages(chainType string) []string {
+	return []string{
+		fmt.Sprintf(MoveBulkVins, chainType, chainType),
+		fmt.Sprintf(MoveBulkVouts, chainType, chainType),
+		fmt.Sprintf(MoveBulkTxns, chainType, chainType),
+		fmt.Sprintf(MoveBulkAddresses, chainType, chainType),
+	}
+}
+
+func MakeSetBulkAddressesSpending(chainType string) string {
+	return fmt.Sprintf(SetBulkAddressesSpending, chainType, chainType)
+}
+
+func MakeInsertBlockAllSynced(chainType string) string {
+	return fmt.Sprintf(insertBlockAllSyncedRow, chainType)
+}
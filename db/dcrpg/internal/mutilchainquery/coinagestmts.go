@@ -0,0 +1,273 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// The coin age tables of the BTC and LTC chains, computed from the whole-chain
+// tables one block at a time. The utxo_age table holds the unspent value of the
+// chain by the day of its creation, with the sum of the value times the
+// creation time of each output, which gives the exact coin days of the UTXO
+// set at any later time. The utxo_age_deltas table holds the changes of the
+// recent blocks to utxo_age, which are reverted on a reorg. The values are in
+// atoms, and the times are unix times.
+const (
+	CreateUtxoAgeTable = `CREATE TABLE IF NOT EXISTS %sutxo_age (
+		day INT8 PRIMARY KEY,
+		value INT8 NOT NULL,
+		value_time NUMERIC NOT NULL
+	);`
+
+	CreateUtxoAgeDeltasTable = `CREATE TABLE IF NOT EXISTS %sutxo_age_deltas (
+		height INT8 NOT NULL,
+		day INT8 NOT NULL,
+		spent BOOLEAN NOT NULL,
+		value INT8 NOT NULL,
+		value_time NUMERIC NOT NULL,
+		PRIMARY KEY (height, day, spent)
+	);`
+
+	CreateCoinAgeTable = `CREATE TABLE IF NOT EXISTS %scoin_age (
+		height INT8 PRIMARY KEY,
+		hash TEXT NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		coin_days_destroyed FLOAT8 NOT NULL,
+		avg_coin_days FLOAT8 NOT NULL
+	);`
+
+	CreateCoinAgeBandsTable = `CREATE TABLE IF NOT EXISTS %scoin_age_bands (
+		height INT8 NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		age_band TEXT NOT NULL,
+		value INT8 NOT NULL,
+		UNIQUE (height, age_band)
+	);`
+
+	CreateMeanCoinAgeTable = `CREATE TABLE IF NOT EXISTS %smca_snapshots (
+		block_height INT8 PRIMARY KEY,
+		block_time TIMESTAMPTZ NOT NULL,
+		total_coin_days NUMERIC NOT NULL,
+		total_supply INT8 NOT NULL,
+		mean_coin_age NUMERIC NOT NULL
+	);`
+
+	SelectCoinAgeTip = `SELECT height, hash FROM %scoin_age ORDER BY height DESC LIMIT 1;`
+
+	SelectCoinAgeHash = `SELECT hash FROM %scoin_age WHERE height = $1;`
+
+	// SelectUtxoAgeDeltasMinHeight is the height of the oldest block whose
+	// changes can be reverted.
+	SelectUtxoAgeDeltasMinHeight = `SELECT COALESCE(MIN(height), -1) FROM %sutxo_age_deltas;`
+
+	// SelectCoinAgeCandidateBlocks selects the synced whole-chain blocks at the
+	// height $1, of which there are several after a reorg.
+	SelectCoinAgeCandidateBlocks = `SELECT hash, previous_hash, time FROM %sblocks_all
+		WHERE height = $1 AND synced = true;`
+
+	// InsertUtxoAgeDeltas stores the changes to utxo_age of the block with the
+	// hash $1 at the height $2 and time $3: the outputs it creates, other than
+	// the provably unspendable ones, and the outputs its inputs spend, by the
+	// day of the funding block. The height selects the transactions by index.
+	InsertUtxoAgeDeltas = `INSERT INTO %[1]sutxo_age_deltas (height, day, spent, value, value_time)
+		SELECT $2::INT8, d.day, d.spent, SUM(d.value), SUM(d.value_time)
+		FROM (
+			SELECT $3::INT8 / 86400 AS day, false AS spent, o.value,
+				o.value::NUMERIC * $3::INT8 AS value_time
+			FROM %[1]stransactions t
+			JOIN %[1]svouts_all o ON o.tx_hash = t.tx_hash
+			WHERE t.block_height = $2 AND t.block_hash = $1
+				AND (o.pkscript IS NULL OR substr(o.pkscript, 1, 1) <> '\x6a'::BYTEA)
+			UNION ALL
+			SELECT f.block_time / 86400, true, -o.value, -(o.value::NUMERIC * f.block_time)
+			FROM %[1]stransactions t
+			JOIN %[1]svins_all v ON v.tx_hash = t.tx_hash
+			JOIN %[1]svouts_all o ON o.tx_hash = v.prev_tx_hash AND o.tx_index = v.prev_tx_index
+			JOIN LATERAL (SELECT ft.block_time FROM %[1]stransactions ft
+				WHERE ft.tx_hash = v.prev_tx_hash ORDER BY ft.block_height LIMIT 1) f ON true
+			WHERE t.block_height = $2 AND t.block_hash = $1
+		) d
+		GROUP BY d.day, d.spent
+		ON CONFLICT (height, day, spent) DO UPDATE
+		SET value = EXCLUDED.value, value_time = EXCLUDED.value_time;`
+
+	// ApplyUtxoAgeDeltas adds the changes of the block at the height $1 to
+	// utxo_age.
+	ApplyUtxoAgeDeltas = `INSERT INTO %[1]sutxo_age AS u (day, value, value_time)
+		SELECT day, SUM(value), SUM(value_time) FROM %[1]sutxo_age_deltas
+		WHERE height = $1
+		GROUP BY day
+		ON CONFLICT (day) DO UPDATE
+		SET value = u.value + EXCLUDED.value, value_time = u.value_time + EXCLUDED.value_time;`
+
+	// RevertUtxoAgeDeltas subtracts the changes of the blocks above the height
+	// $1 from utxo_age.
+	RevertUtxoAgeDeltas = `UPDATE %[1]sutxo_age u
+		SET value = u.value - d.value, value_time = u.value_time - d.value_time
+		FROM (SELECT day, SUM(value) AS value, SUM(value_time) AS value_time
+			FROM %[1]sutxo_age_deltas WHERE height > $1 GROUP BY day) d
+		WHERE u.day = d.day;`
+
+	DeleteEmptyUtxoAge       = `DELETE FROM %sutxo_age WHERE value = 0 AND value_time = 0;`
+	DeleteUtxoAgeDeltasAbove = `DELETE FROM %sutxo_age_deltas WHERE height > $1;`
+	DeleteUtxoAgeDeltasUpTo  = `DELETE FROM %sutxo_age_deltas WHERE height <= $1;`
+	DeleteCoinAgeAbove       = `DELETE FROM %scoin_age WHERE height > $1;`
+	DeleteCoinAgeBandsAbove  = `DELETE FROM %scoin_age_bands WHERE height > $1;`
+	DeleteMeanCoinAgeAbove   = `DELETE FROM %smca_snapshots WHERE block_height > $1;`
+	TruncateCoinAgeTables    = `TRUNCATE %[1]sutxo_age, %[1]sutxo_age_deltas, %[1]scoin_age, %[1]scoin_age_bands, %[1]smca_snapshots;`
+
+	// InsertCoinAgeRow stores the coin days destroyed by the block with the
+	// hash $2 at the height $1 and time $3, from its spent changes, and the
+	// mean age in days of the value it spends.
+	InsertCoinAgeRow = `INSERT INTO %[1]scoin_age (height, hash, time, coin_days_destroyed, avg_coin_days)
+		SELECT $1::INT8, $2::TEXT, to_timestamp($3::INT8), COALESCE(s.cdd, 0),
+			COALESCE(s.cdd / NULLIF(s.spent, 0), 0)
+		FROM (SELECT SUM(value_time - value::NUMERIC * $3::INT8) / 86400 AS cdd,
+				-SUM(value) AS spent
+			FROM %[1]sutxo_age_deltas WHERE height = $1 AND spent) s
+		ON CONFLICT (height) DO UPDATE
+		SET hash = EXCLUDED.hash, time = EXCLUDED.time,
+			coin_days_destroyed = EXCLUDED.coin_days_destroyed,
+			avg_coin_days = EXCLUDED.avg_coin_days;`
+
+	// InsertCoinAgeBandsRows stores the unspent value by age band at the height
+	// $1 and time $2, with the ages in whole days.
+	InsertCoinAgeBandsRows = `INSERT INTO %[1]scoin_age_bands (height, time, age_band, value)
+		SELECT $1::INT8, to_timestamp($2::INT8), b.age_band, SUM(b.value)
+		FROM (
+			SELECT CASE
+				WHEN $2::INT8 / 86400 - day < 1 THEN '<1d'
+				WHEN $2::INT8 / 86400 - day < 7 THEN '1d-1w'
+				WHEN $2::INT8 / 86400 - day < 30 THEN '1w-1m'
+				WHEN $2::INT8 / 86400 - day < 180 THEN '1m-6m'
+				WHEN $2::INT8 / 86400 - day < 365 THEN '6m-1y'
+				WHEN $2::INT8 / 86400 - day < 2 * 365 THEN '1y-2y'
+				WHEN $2::INT8 / 86400 - day < 3 * 365 THEN '2y-3y'
+				WHEN $2::INT8 / 86400 - day < 5 * 365 THEN '3y-5y'
+				WHEN $2::INT8 / 86400 - day < 7 * 365 THEN '5y-7y'
+				ELSE '>7y'
+			END AS age_band, value
+			FROM %[1]sutxo_age WHERE value <> 0
+		) b
+		GROUP BY b.age_band
+		ON CONFLICT (height, age_band) DO UPDATE
+		SET time = EXCLUDED.time, value = EXCLUDED.value;`
+
+	// InsertMeanCoinAgeRow stores the coin days of the UTXO set at the height
+	// $1 and time $2, and its mean age in days.
+	InsertMeanCoinAgeRow = `INSERT INTO %[1]smca_snapshots (block_height, block_time,
+			total_coin_days, total_supply, mean_coin_age)
+		SELECT $1::INT8, to_timestamp($2::INT8), s.coin_days, s.supply,
+			COALESCE(s.coin_days / NULLIF(s.supply, 0), 0)
+		FROM (SELECT COALESCE(SUM(value::NUMERIC * $2::INT8 - value_time) / 86400, 0) AS coin_days,
+				COALESCE(SUM(value), 0) AS supply
+			FROM %[1]sutxo_age) s
+		ON CONFLICT (block_height) DO UPDATE
+		SET block_time = EXCLUDED.block_time, total_coin_days = EXCLUDED.total_coin_days,
+			total_supply = EXCLUDED.total_supply, mean_coin_age = EXCLUDED.mean_coin_age;`
+
+	// SelectCoinAgeChartRows selects the coin age data of the blocks from the
+	// height $1, with the value of each age band. Each row starts with $1, and
+	// the first row is a marker with the height -1, so that the start height
+	// is known even if there are no blocks from it, e.g. after the tables are
+	// rebuilt.
+	SelectCoinAgeChartRows = `SELECT $1::INT8, -1::INT8, '', 0::INT8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0
+		UNION ALL (
+		SELECT $1::INT8, c.height, c.hash, EXTRACT(EPOCH FROM c.time)::INT8,
+			c.coin_days_destroyed, c.avg_coin_days, m.total_coin_days, m.mean_coin_age,
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '<1d'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '1d-1w'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '1w-1m'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '1m-6m'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '6m-1y'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '1y-2y'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '2y-3y'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '3y-5y'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '5y-7y'), 0),
+			COALESCE(SUM(b.value) FILTER (WHERE b.age_band = '>7y'), 0)
+		FROM %[1]scoin_age c
+		JOIN %[1]smca_snapshots m ON m.block_height = c.height
+		LEFT JOIN %[1]scoin_age_bands b ON b.height = c.height
+		WHERE c.height >= $1
+		GROUP BY c.height, c.hash, c.time, c.coin_days_destroyed, c.avg_coin_days,
+			m.total_coin_days, m.mean_coin_age)
+		ORDER BY 2;`
+)
+
+// MakeCoinAgeTables returns the names and creation statements of the coin age
+// tables of a chain.
+func MakeCoinAgeTables(chainType string) [][2]string {
+	return [][2]string{
+		{chainType + "utxo_age", fmt.Sprintf(CreateUtxoAgeTable, chainType)},
+		{chainType + "utxo_age_deltas", fmt.Sprintf(CreateUtxoAgeDeltasTable, chainType)},
+		{chainType + "coin_age", fmt.Sprintf(CreateCoinAgeTable, chainType)},
+		{chainType + "coin_age_bands", fmt.Sprintf(CreateCoinAgeBandsTable, chainType)},
+		{chainType + "mca_snapshots", fmt.Sprintf(CreateMeanCoinAgeTable, chainType)},
+	}
+}
+
+func MakeSelectCoinAgeTip(chainType string) string {
+	return fmt.Sprintf(SelectCoinAgeTip, chainType)
+}
+
+func MakeSelectCoinAgeHash(chainType string) string {
+	return fmt.Sprintf(SelectCoinAgeHash, chainType)
+}
+
+func MakeSelectUtxoAgeDeltasMinHeight(chainType string) string {
+	return fmt.Sprintf(SelectUtxoAgeDeltasMinHeight, chainType)
+}
+
+func MakeSelectCoinAgeCandidateBlocks(chainType string) string {
+	return fmt.Sprintf(SelectCoinAgeCandidateBlocks, chainType)
+}
+
+func MakeInsertUtxoAgeDeltas(chainType string) string {
+	return fmt.Sprintf(InsertUtxoAgeDeltas, chainType)
+}
+
+func MakeApplyUtxoAgeDeltas(chainType string) string {
+	return fmt.Sprintf(ApplyUtxoAgeDeltas, chainType)
+}
+
+func MakeRevertUtxoAgeDeltas(chainType string) string {
+	return fmt.Sprintf(RevertUtxoAgeDeltas, chainType)
+}
+
+// MakeRewindCoinAgeStmts returns the statements, after RevertUtxoAgeDeltas,
+// deleting the coin age data of the blocks above the height $1.
+func MakeRewindCoinAgeStmts(chainType string) []string {
+	return []string{
+		fmt.Sprintf(DeleteUtxoAgeDeltasAbove, chainType),
+		fmt.Sprintf(DeleteCoinAgeAbove, chainType),
+		fmt.Sprintf(DeleteCoinAgeBandsAbove, chainType),
+		fmt.Sprintf(DeleteMeanCoinAgeAbove, chainType),
+	}
+}
+
+func MakeDeleteEmptyUtxoAge(chainType string) string {
+	return fmt.Sprintf(DeleteEmptyUtxoAge, chainType)
+}
+
+func MakeDeleteUtxoAgeDeltasUpTo(chainType string) string {
+	return fmt.Sprintf(DeleteUtxoAgeDeltasUpTo, chainType)
+}
+
+func MakeTruncateCoinAgeTables(chainType string) string {
+	return fmt.Sprintf(TruncateCoinAgeTables, chainType)
+}
+
+func MakeInsertCoinAgeRow(chainType string) string {
+	return fmt.Sprintf(InsertCoinAgeRow, chainType)
+}
+
+func MakeInsertCoinAgeBandsRows(chainType string) string {
+	return fmt.Sprintf(InsertCoinAgeBandsRows, chainType)
+}
+
+func MakeInsertMeanCoinAgeRow(chainType string) string {
+	return fmt.Sprintf(InsertMeanCoinAgeRow, chainType)
+}
+
+func MakeSelectCoinAgeChartRows(chainType string) string {
+	return fmt.Sprintf(SelectCoinAgeChartRows, chainType)
+}
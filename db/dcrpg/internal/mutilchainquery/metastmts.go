@@ -0,0 +1,107 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// The schema of the tables of each chain is versioned in the multichain_meta
+// table, like the Decred tables in the meta table. upgrade_height is the next
+// height of the backfill of the running upgrade, or -1.
+const (
+	CreateMultichainMetaTable = `CREATE TABLE IF NOT EXISTS multichain_meta (
+		chain_type TEXT PRIMARY KEY,
+		compat_version INT4 NOT NULL,
+		schema_version INT4 NOT NULL,
+		maint_version INT4 NOT NULL,
+		upgrade_height INT8 NOT NULL DEFAULT -1
+	);`
+
+	SelectMultichainDBVersions = `SELECT compat_version, schema_version, maint_version
+		FROM multichain_meta WHERE chain_type = $1;`
+
+	// UpsertMultichainDBVersions sets the versions of a chain and resets the
+	// backfill height of its upgrade.
+	UpsertMultichainDBVersions = `INSERT INTO multichain_meta (chain_type, compat_version,
+		schema_version, maint_version, upgrade_height)
+		VALUES ($1, $2, $3, $4, -1)
+		ON CONFLICT (chain_type) DO UPDATE
+		SET compat_version = EXCLUDED.compat_version, schema_version = EXCLUDED.schema_version,
+			maint_version = EXCLUDED.maint_version, upgrade_height = -1;`
+
+	SelectMultichainUpgradeHeight = `SELECT upgrade_height FROM multichain_meta WHERE chain_type = $1;`
+	SetMultichainUpgradeHeight    = `UPDATE multichain_meta SET upgrade_height = $2 WHERE chain_type = $1;`
+
+	// Schema 1.1.0 adds the columns of the current table definitions missing
+	// from the tables created by older releases.
+	addMissingBlocksAllColumns = `ALTER TABLE %sblocks_all
+		ADD COLUMN IF NOT EXISTS block_blob BYTEA,
+		ADD COLUMN IF NOT EXISTS difficulty_num NUMERIC(40,0),
+		ADD COLUMN IF NOT EXISTS cumulative_difficulty NUMERIC(40,0),
+		ADD COLUMN IF NOT EXISTS pow_algo TEXT,
+		ADD COLUMN IF NOT EXISTS num_vins INT4,
+		ADD COLUMN IF NOT EXISTS num_vouts INT4,
+		ADD COLUMN IF NOT EXISTS fees INT8,
+		ADD COLUMN IF NOT EXISTS total_sent INT8,
+		ADD COLUMN IF NOT EXISTS reward INT8,
+		ADD COLUMN IF NOT EXISTS address_updated BOOLEAN DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS synced BOOLEAN DEFAULT FALSE;`
+	addMissingTransactionColumns = `ALTER TABLE %stransactions
+		ADD COLUMN IF NOT EXISTS tx_blob BYTEA,
+		ADD COLUMN IF NOT EXISTS tx_extra JSONB,
+		ADD COLUMN IF NOT EXISTS is_ringct BOOLEAN DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS rct_type INT,
+		ADD COLUMN IF NOT EXISTS tx_public_key TEXT,
+		ADD COLUMN IF NOT EXISTS prunable_size INT;`
+	addMissingAddressColumns = `ALTER TABLE %saddresses
+		ADD COLUMN IF NOT EXISTS out_pk TEXT NULL,
+		ADD COLUMN IF NOT EXISTS global_index BIGINT NULL,
+		ADD COLUMN IF NOT EXISTS amount_commitment BYTEA NULL,
+		ADD COLUMN IF NOT EXISTS amount_known BOOLEAN DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS amount BIGINT NULL,
+		ADD COLUMN IF NOT EXISTS key_image TEXT NULL,
+		ADD COLUMN IF NOT EXISTS first_seen_block_height BIGINT NULL,
+		ADD COLUMN IF NOT EXISTS account_index INT4 NULL,
+		ADD COLUMN IF NOT EXISTS address_index INT4 NULL,
+		ADD COLUMN IF NOT EXISTS is_subaddress BOOLEAN DEFAULT FALSE,
+		ADD COLUMN IF NOT EXISTS tx_pub_key TEXT NULL,
+		ADD COLUMN IF NOT EXISTS payment_id TEXT NULL,
+		ADD COLUMN IF NOT EXISTS last_updated TIMESTAMPTZ DEFAULT now();`
+
+	// Schema 1.2.0 indexes the transactions on block height, which the light
+	// tables were not, for the pruning to the retention window.
+	IndexOfTransactionTableOnBlockHeight = `ix_%stx_block_height`
+
+	// Schema 1.3.0 backfills the input, output, fee and sent totals of the
+	// synced whole-chain blocks lacking them, for the heights [$1, $2).
+	SelectBlocksAllMaxHeight = `SELECT COALESCE(MAX(height), -1) FROM %sblocks_all;`
+	BackfillBlocksAllTotals  = `UPDATE %sblocks_all b
+		SET num_vins = t.num_vins, num_vouts = t.num_vouts, fees = t.fees, total_sent = t.total_sent
+		FROM (SELECT block_height, SUM(num_vin) AS num_vins, SUM(num_vout) AS num_vouts,
+			SUM(fees) AS fees, SUM(sent) AS total_sent
+			FROM %stransactions
+			WHERE block_height >= $1 AND block_height < $2
+			GROUP BY block_height) t
+		WHERE b.height = t.block_height AND b.synced = true AND b.num_vins IS NULL;`
+)
+
+// MakeAddMissingColumns returns the statements adding the missing columns of
+// the tables of a chain.
+func MakeAddMissingColumns(chainType string) []string {
+	return []string{
+		fmt.Sprintf(addMissingBlocksAllColumns, chainType),
+		fmt.Sprintf(addMissingTransactionColumns, chainType),
+		fmt.Sprintf(addMissingAddressColumns, chainType),
+	}
+}
+
+func MakeIndexOfTransactionTableOnBlockHeight(chainType string) string {
+	return fmt.Sprintf(IndexOfTransactionTableOnBlockHeight, chainType)
+}
+
+func MakeSelectBlocksAllMaxHeight(chainType string) string {
+	return fmt.Sprintf(SelectBlocksAllMaxHeight, chainType)
+}
+
+func MakeBackfillBlocksAllTotals(chainType string) string {
+	return fmt.Sprintf(BackfillBlocksAllTotals, chainType, chainType)
+}
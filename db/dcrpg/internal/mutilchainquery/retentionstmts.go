@@ -0,0 +1,56 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// Statements of the pruning of the tables of a chain to its retention window.
+// The rows are deleted in batches of at most $3 rows, for the transactions of
+// the blocks in the height range [$1, $2).
+const (
+	SelectRetentionMinHeight = `SELECT COALESCE(MIN(height), 0) FROM %sblocks;`
+
+	// SelectRetentionAgeCutoff returns the height after the last block older
+	// than the time $1, or the estimate $2 if there is none.
+	SelectRetentionAgeCutoff = `SELECT COALESCE(MAX(height) + 1, $2) FROM %sblocks WHERE time < $1;`
+
+	SelectRetentionBlockTime = `SELECT COALESCE(MIN(time), 0) FROM %sblocks WHERE height = $1;`
+
+	pruneRowsOfBlocks = `DELETE FROM %s%s WHERE id IN (
+		SELECT r.id FROM %s%s r
+		JOIN (SELECT unnest(tx) AS tx_hash FROM %sblocks WHERE height >= $1 AND height < $2) b
+		ON b.tx_hash = r.%s
+		LIMIT $3);`
+
+	PruneTransactions = `DELETE FROM %stransactions WHERE id IN (
+		SELECT id FROM %stransactions WHERE block_height >= $1 AND block_height < $2 LIMIT $3);`
+)
+
+func MakeSelectRetentionMinHeight(chainType string) string {
+	return fmt.Sprintf(SelectRetentionMinHeight, chainType)
+}
+
+func MakeSelectRetentionAgeCutoff(chainType string) string {
+	return fmt.Sprintf(SelectRetentionAgeCutoff, chainType)
+}
+
+func MakeSelectRetentionBlockTime(chainType string) string {
+	return fmt.Sprintf(SelectRetentionBlockTime, chainType)
+}
+
+// MakePruneRawRows returns the statements pruning the inputs and outputs of
+// the light tables of a chain.
+func MakePruneRawRows(chainType string) []string {
+	return []string{
+		fmt.Sprintf(pruneRowsOfBlocks, chainType, "vins", chainType, "vins", chainType, "tx_hash"),
+		fmt.Sprintf(pruneRowsOfBlocks, chainType, "vouts", chainType, "vouts", chainType, "tx_hash"),
+	}
+}
+
+func MakePruneTransactions(chainType string) string {
+	return fmt.Sprintf(PruneTransactions, chainType, chainType)
+}
+
+func MakePruneAddresses(chainType string) string {
+	return fmt.Sprintf(pruneRowsOfBlocks, chainType, "addresses", chainType, "addresses", chainType, "funding_tx_hash")
+}
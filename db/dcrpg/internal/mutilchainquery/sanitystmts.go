@@ -0,0 +1,157 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// Consistency checks of the tables of a chain. Each query returns the rows
+// violating an invariant, with the height of the block to repair if known.
+const (
+	SelectWholeChainSynced = `SELECT EXISTS(SELECT 1 FROM %sblocks_all WHERE synced = true);`
+
+	// BrokenBlockChainLinks selects the block_chain rows with a next block
+	// that is missing or does not link back to them.
+	BrokenBlockChainLinks = `SELECT c.block_db_id, b.height, c.this_hash, c.next_hash
+		FROM %[1]sblock_chain c
+		JOIN %[1]sblocks b ON b.id = c.block_db_id
+		LEFT JOIN %[1]sblock_chain n ON n.this_hash = c.next_hash
+		WHERE c.next_hash <> '' AND (n.this_hash IS NULL OR n.prev_hash <> c.this_hash)
+		ORDER BY b.height;`
+
+	// UnsetSpendingAddresses selects the inputs spending an address row which
+	// is not flagged as spent by them. The vins table is %[2]s.
+	UnsetSpendingAddresses = `SELECT DISTINCT ON (v.id) v.id, v.tx_hash, v.tx_index,
+			v.prev_tx_hash, v.prev_tx_index, COALESCE(t.block_height, -1)
+		FROM %[1]s%[2]s v
+		JOIN %[1]saddresses a ON a.funding_tx_hash = v.prev_tx_hash
+			AND a.funding_tx_vout_index = v.prev_tx_index
+		LEFT JOIN %[1]stransactions t ON t.tx_hash = v.tx_hash
+		WHERE a.spending_tx_hash IS NULL OR a.spending_tx_hash = ''
+			OR a.spending_tx_hash <> v.tx_hash
+		ORDER BY v.id, t.block_height DESC;`
+
+	// RelinkUnsetSpendingAddresses flags the address rows funding the inputs
+	// with the IDs $1 as spent by them.
+	RelinkUnsetSpendingAddresses = `UPDATE %[1]saddresses a
+		SET spending_tx_row_id = t.id, spending_tx_hash = v.tx_hash,
+			spending_tx_vin_index = v.tx_index, vin_row_id = v.id
+		FROM %[1]s%[2]s v
+		JOIN %[1]stransactions t ON t.tx_hash = v.tx_hash
+		WHERE v.id = ANY($1) AND a.funding_tx_hash = v.prev_tx_hash
+			AND a.funding_tx_vout_index = v.prev_tx_index;`
+
+	// MissingFundingOutpoints selects the whole-chain inputs, other than the
+	// coinbase inputs spending the zero hash $1, whose previous output is not
+	// stored. The height is that of the funding transaction, if stored.
+	MissingFundingOutpoints = `SELECT v.id, v.tx_hash, v.tx_index, v.prev_tx_hash, v.prev_tx_index,
+			COALESCE((SELECT MAX(t.block_height) FROM %[1]stransactions t
+				WHERE t.tx_hash = v.prev_tx_hash), -1)
+		FROM %[1]svins_all v
+		LEFT JOIN %[1]svouts_all o ON o.tx_hash = v.prev_tx_hash AND o.tx_index = v.prev_tx_index
+		WHERE o.id IS NULL AND v.prev_tx_hash <> $1
+		ORDER BY v.id;`
+
+	// BlockTxCountMismatch selects the synced whole-chain blocks whose number
+	// of transactions differs from that stored for them.
+	BlockTxCountMismatch = `SELECT b.height, b.hash, b.numtx, COALESCE(t.num, 0)
+		FROM %[1]sblocks_all b
+		LEFT JOIN (SELECT block_hash, COUNT(*) AS num FROM %[1]stransactions
+			GROUP BY block_hash) t ON t.block_hash = b.hash
+		WHERE b.synced = true AND b.numtx <> COALESCE(t.num, 0)
+		ORDER BY b.height;`
+
+	// SwapsMissingTx selects the atomic swap spends of synced whole-chain
+	// blocks whose spending or contract transaction is not stored, with the
+	// missing transaction and the height of the block to fetch. The height
+	// of a missing contract transaction is not known, and is -1.
+	SwapsMissingTx = `SELECT s.spend_tx, s.spend_vin, s.spend_tx AS missing_tx, s.spend_height AS height
+		FROM %[1]s_swaps s
+		JOIN %[1]sblocks_all b ON b.height = s.spend_height AND b.synced = true
+		WHERE NOT EXISTS (SELECT 1 FROM %[1]stransactions t WHERE t.tx_hash = s.spend_tx)
+		UNION ALL
+		SELECT s.spend_tx, s.spend_vin, s.contract_tx, -1
+		FROM %[1]s_swaps s
+		JOIN %[1]sblocks_all b ON b.height = s.spend_height AND b.synced = true
+		WHERE NOT EXISTS (SELECT 1 FROM %[1]stransactions t WHERE t.tx_hash = s.contract_tx)
+		ORDER BY height, spend_tx, spend_vin;`
+
+	// OrphanedMoneroKeyImages selects the key images whose spending
+	// transaction is not stored. The sync records the spending transaction
+	// of a key image as its first_seen_tx_hash, and leaves spent_tx_hash
+	// unset.
+	OrphanedMoneroKeyImages = `SELECT k.id, k.key_image,
+			COALESCE(k.spent_tx_hash, k.first_seen_tx_hash, ''),
+			COALESCE(k.spent_block_height, k.first_seen_block_height, -1)
+		FROM monero_key_images k
+		LEFT JOIN xmrtransactions t
+			ON t.tx_hash = COALESCE(k.spent_tx_hash, k.first_seen_tx_hash)
+		WHERE t.id IS NULL
+		ORDER BY k.id;`
+
+	DeleteMoneroKeyImagesByIDs = `DELETE FROM monero_key_images WHERE id = ANY($1);`
+
+	// ReusedMoneroKeyImages selects the key images of more than one stored
+	// input, which would be a double spend. monero_key_images has one row per
+	// key image, so the inputs are read from the raw vin JSON of the stored
+	// transactions, and each transaction input is counted once. This reads
+	// the vins of every stored transaction.
+	ReusedMoneroKeyImages = `WITH inputs AS (
+			SELECT DISTINCT t.tx_hash, v.idx - 1 AS tx_input_index,
+				v.vin::jsonb -> 'key' ->> 'k_image' AS key_image
+			FROM xmrtransactions t
+			CROSS JOIN LATERAL unnest(t.vins::text[]) WITH ORDINALITY AS v(vin, idx)
+			WHERE t.vins IS NOT NULL
+		)
+		SELECT key_image, array_agg(tx_hash || ':' || tx_input_index ORDER BY tx_hash, tx_input_index)
+		FROM inputs
+		WHERE key_image IS NOT NULL
+		GROUP BY key_image
+		HAVING COUNT(*) > 1
+		ORDER BY key_image;`
+
+	// OrphanedMoneroRingMembers selects the ring members of transactions that
+	// are not stored.
+	OrphanedMoneroRingMembers = `SELECT r.id, r.tx_hash, r.tx_input_index
+		FROM monero_ring_members r
+		LEFT JOIN xmrtransactions t ON t.tx_hash = r.tx_hash
+		WHERE t.id IS NULL
+		ORDER BY r.id;`
+
+	DeleteMoneroRingMembersByIDs = `DELETE FROM monero_ring_members WHERE id = ANY($1);`
+)
+
+// vinsTable is the vins table of the light or whole-chain tables of a chain.
+func vinsTable(whole bool) string {
+	if whole {
+		return "vins_all"
+	}
+	return "vins"
+}
+
+func MakeSelectWholeChainSynced(chainType string) string {
+	return fmt.Sprintf(SelectWholeChainSynced, chainType)
+}
+
+func MakeBrokenBlockChainLinks(chainType string) string {
+	return fmt.Sprintf(BrokenBlockChainLinks, chainType)
+}
+
+func MakeUnsetSpendingAddresses(chainType string, whole bool) string {
+	return fmt.Sprintf(UnsetSpendingAddresses, chainType, vinsTable(whole))
+}
+
+func MakeRelinkUnsetSpendingAddresses(chainType string, whole bool) string {
+	return fmt.Sprintf(RelinkUnsetSpendingAddresses, chainType, vinsTable(whole))
+}
+
+func MakeMissingFundingOutpoints(chainType string) string {
+	return fmt.Sprintf(MissingFundingOutpoints, chainType)
+}
+
+func MakeBlockTxCountMismatch(chainType string) string {
+	return fmt.Sprintf(BlockTxCountMismatch, chainType)
+}
+
+func MakeSwapsMissingTx(chainType string) string {
+	return fmt.Sprintf(SwapsMissingTx, chainType)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package mutilchainquery
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+// createColumns returns the columns of a CREATE TABLE statement.
+func createColumns(create string) map[string]bool {
+	cols := make(map[string]bool)
+	body := create[strings.Index(create, "(")+1 : strings.LastIndex(create, ")")]
+	for _, line := range strings.Split(body, "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && !strings.HasPrefix(fields[0], "/*") {
+			cols[fields[0]] = true
+		}
+	}
+	return cols
+}
+
+// TestMoneroKeyImageChecksColumns checks that the key image checks only use
+// the columns of their tables.
+func TestMoneroKeyImageChecksColumns(t *testing.T) {
+	keyImages := createColumns(CreateMoneroKeyImagesTable)
+	txns := createColumns(CreateTransactionTableFunc("xmr"))
+	columnRef := regexp.MustCompile(`\b([kt])\.([a-z_]+)`)
+	for name, query := range map[string]string{
+		"OrphanedMoneroKeyImages": OrphanedMoneroKeyImages,
+		"ReusedMoneroKeyImages":   ReusedMoneroKeyImages,
+	} {
+		for _, m := range columnRef.FindAllStringSubmatch(query, -1) {
+			cols := txns
+			if m[1] == "k" {
+				cols = keyImages
+			}
+			if !cols[m[2]] {
+				t.Errorf("%s: unknown column %s", name, m[0])
+			}
+		}
+	}
+}
+
+// TestReusedMoneroKeyImagesVins checks the key image path of
+// ReusedMoneroKeyImages against the vins stored by the sync: the vin JSON of
+// monerod, marshaled and stored as a text array in xmrtransactions.vins.
+func TestReusedMoneroKeyImagesVins(t *testing.T) {
+	// monero_key_images has a unique key image, so the inputs must be read
+	// from the transactions.
+	if strings.Contains(ReusedMoneroKeyImages, "monero_key_images") {
+		t.Error("ReusedMoneroKeyImages must not count the unique monero_key_images rows")
+	}
+	path := regexp.MustCompile(`v\.vin::jsonb -> '([a-z_]+)' ->> '([a-z_]+)'`).
+		FindStringSubmatch(ReusedMoneroKeyImages)
+	if path == nil {
+		t.Fatal("ReusedMoneroKeyImages has no key image path")
+	}
+	if !strings.Contains(ReusedMoneroKeyImages, "unnest(t.vins::text[])") {
+		t.Error("ReusedMoneroKeyImages does not unnest the vins text array")
+	}
+
+	const keyImage = "1c9c1e2a3f1b4d5e6f708192a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6"
+	var txMap map[string]any
+	err := json.Unmarshal([]byte(`{"vin": [{"key": {"amount": 0,
+		"key_offsets": [8563, 1234], "k_image": "`+keyImage+`"}}]}`), &txMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vins []string
+	for _, vi := range txMap["vin"].([]any) {
+		bs, _ := json.Marshal(vi)
+		vins = append(vins, string(bs))
+	}
+	stored, err := pq.Array(vins).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The text[] cast of the stored text.
+	var unnested pq.StringArray
+	if err = unnested.Scan(stored); err != nil {
+		t.Fatal(err)
+	}
+	if len(unnested) != 1 {
+		t.Fatalf("expected 1 vin, got %d", len(unnested))
+	}
+	var vin map[string]map[string]any
+	if err = json.Unmarshal([]byte(unnested[0]), &vin); err != nil {
+		t.Fatal(err)
+	}
+	if got := vin[path[1]][path[2]]; got != keyImage {
+		t.Errorf("key image path %s.%s gives %v, expected %s", path[1], path[2], got, keyImage)
+	}
+}
@@ -122,17 +122,12 @@ const (
 	WHERE block_height > $1
 	GROUP BY block_height
 	ORDER BY block_height;`
-	DeleteTxsOfOlderThan20Blocks = `DELETE FROM %stransactions WHERE block_height < $1;`
 )
 
 func MakeSelectFeesPerBlockAboveHeight(chainType string) string {
 	return fmt.Sprintf(SelectFeesPerBlockAboveHeight, chainType)
 }
 
-func MakeDeleteTxsOfOlderThan20Blocks(chainType string) string {
-	return fmt.Sprintf(DeleteTxsOfOlderThan20Blocks, chainType)
-}
-
 func MakeSelectTotalTransaction(chainType string) string {
 	return fmt.Sprintf(SelectTotalTransaction, chainType)
 }
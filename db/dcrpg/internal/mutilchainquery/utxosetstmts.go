@@ -0,0 +1,25 @@
+package mutilchainquery
+
+import (
+	"fmt"
+)
+
+// SelectUtxoSetBlockOutputs is the source of the changes to the UTXO set
+// tables of the BTC and LTC chains: the outputs created and spent by the
+// whole-chain block with the hash $1 at the height $2, other than the provably
+// unspendable ones. The height selects the transactions by index.
+const SelectUtxoSetBlockOutputs = `SELECT 1 AS sign, o.value, o.pkscript, o.script_type
+	FROM %[1]stransactions t
+	JOIN %[1]svouts_all o ON o.tx_hash = t.tx_hash
+	WHERE t.block_height = $2 AND t.block_hash = $1
+		AND (o.pkscript IS NULL OR substr(o.pkscript, 1, 1) <> '\x6a'::BYTEA)
+	UNION ALL
+	SELECT -1, o.value, o.pkscript, o.script_type
+	FROM %[1]stransactions t
+	JOIN %[1]svins_all v ON v.tx_hash = t.tx_hash
+	JOIN %[1]svouts_all o ON o.tx_hash = v.prev_tx_hash AND o.tx_index = v.prev_tx_index
+	WHERE t.block_height = $2 AND t.block_hash = $1`
+
+func MakeSelectUtxoSetBlockOutputs(chainType string) string {
+	return fmt.Sprintf(SelectUtxoSetBlockOutputs, chainType)
+}
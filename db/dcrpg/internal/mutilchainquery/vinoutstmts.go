@@ -103,6 +103,14 @@ const (
 	SelectVoutIDByOutpoint = `SELECT id FROM %svouts WHERE tx_hash=$1 and tx_index=$2;`
 	SelectVoutByID         = `SELECT * FROM %svouts WHERE id=$1;`
 
+	// SelectVoutsByOutpoints selects the value, addresses and pkScript of the
+	// outputs identified by the arrays of transaction hashes ($1) and output
+	// indexes ($2).
+	SelectVoutsByOutpoints = `SELECT v.tx_hash, v.tx_index, v.value, v.script_addresses, v.pkscript
+		FROM %svouts v
+		JOIN unnest($1::TEXT[], $2::INT4[]) AS o(tx_hash, tx_index)
+		ON v.tx_hash = o.tx_hash AND v.tx_index = o.tx_index;`
+
 	RetrieveVoutValue  = `SELECT value FROM %svouts WHERE tx_hash=$1 and tx_index=$2;`
 	RetrieveVoutValues = `SELECT value, tx_index, tx_tree FROM %svouts WHERE tx_hash=$1;`
 
@@ -130,23 +138,12 @@ const (
 	AND {chaintype}transactions.block_height > $1
 	GROUP BY {chaintype}transactions.block_time, {chaintype}transactions.block_height
 	ORDER BY {chaintype}transactions.block_time;`
-
-	DeleteVinsOfOlderThan20Blocks  = `DELETE FROM %svins WHERE tx_hash IN (SELECT tx_hash FROM %stransactions WHERE block_height < $1);`
-	DeleteVoutsOfOlderThan20Blocks = `DELETE FROM %svouts WHERE tx_hash IN (SELECT tx_hash FROM %stransactions WHERE block_height < $1);`
 )
 
 func MakeSelectCoinSupply(chainType string) string {
 	return strings.ReplaceAll(SelectCoinSupply, "{chaintype}", chainType)
 }
 
-func MakeDeleteVinsOfOlderThan20Blocks(chainType string) string {
-	return fmt.Sprintf(DeleteVinsOfOlderThan20Blocks, chainType, chainType)
-}
-
-func MakeDeleteVoutsOfOlderThan20Blocks(chainType string) string {
-	return fmt.Sprintf(DeleteVoutsOfOlderThan20Blocks, chainType, chainType)
-}
-
 func MakeCountTotalVouts(chainType string) string {
 	return fmt.Sprintf(CountTotalVouts, chainType)
 }
@@ -171,6 +168,10 @@ func MakeSelectVoutByID(chainType string) string {
 	return fmt.Sprintf(SelectVoutByID, chainType)
 }
 
+func MakeSelectVoutsByOutpoints(chainType string) string {
+	return fmt.Sprintf(SelectVoutsByOutpoints, chainType)
+}
+
 func MakeVoutInsertStatement(checked bool, chainType string) string {
 	if checked {
 		return fmt.Sprintf(insertVoutRowChecked, chainType)
@@ -88,6 +88,19 @@ const (
 	IndexVoutAllTableOnTxHash = `CREATE INDEX uix_%svout_all_txhash
 		ON %svouts_all(tx_hash);`
 	DeindexVoutAllTableOnTxHash = `DROP INDEX uix_%svout_all_txhash;`
+
+	// IndexVoutAllTableOnScripthash indexes the outputs of the whole chain by
+	// the SHA256 hash of the pkScript, which identifies a script in the
+	// Esplora and Electrum APIs.
+	IndexVoutAllTableOnScripthash = `CREATE INDEX IF NOT EXISTS ix_%svout_all_scripthash
+		ON %svouts_all(sha256(pkscript));`
+	DeindexVoutAllTableOnScripthash = `DROP INDEX IF EXISTS ix_%svout_all_scripthash;`
+
+	// SelectVoutAllAddressesByScripthash selects the addresses of an output of
+	// the whole chain with the given SHA256 hash of the pkScript.
+	SelectVoutAllAddressesByScripthash = `SELECT script_addresses FROM %svouts_all
+		WHERE sha256(pkscript) = $1 AND cardinality(script_addresses) > 0
+		LIMIT 1;`
 )
 
 func MakeCountTotalVoutsAll(chainType string) string {
@@ -183,3 +196,15 @@ func MakeDeindexVoutAllTableOnTxHash(chainType string) string {
 func MakeDeleteVinAllWithTxHashArrayQuery(chainType string) string {
 	return fmt.Sprintf(DeleteVinAllWithTxHashArray, chainType)
 }
+
+func MakeIndexVoutAllTableOnScripthash(chainType string) string {
+	return fmt.Sprintf(IndexVoutAllTableOnScripthash, chainType, chainType)
+}
+
+func MakeDeindexVoutAllTableOnScripthash(chainType string) string {
+	return fmt.Sprintf(DeindexVoutAllTableOnScripthash, chainType)
+}
+
+func MakeSelectVoutAllAddressesByScripthash(chainType string) string {
+	return fmt.Sprintf(SelectVoutAllAddressesByScripthash, chainType)
+}
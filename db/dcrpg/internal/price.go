@@ -1,5 +1,11 @@
 package internal
 
+import (
+	"fmt"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
 // These queries relate primarily to the "monthly_price" table.
 const (
 	CreateMonthlyPriceTable = `CREATE TABLE IF NOT EXISTS monthly_price (
@@ -52,4 +58,88 @@ const (
 		FROM daily_market
 		WHERE to_timestamp(date)::date >= to_timestamp($1)::date;
 	`
+
+	// The "daily_price" table holds the daily closing prices of the coin of
+	// each chain in the backfilled fiat currencies. date is the Unix time of
+	// the start of the UTC day.
+	CreateDailyPriceTable = `CREATE TABLE IF NOT EXISTS daily_price (
+		chain TEXT NOT NULL,
+		currency TEXT NOT NULL,
+		date INT8 NOT NULL,
+		price FLOAT8 NOT NULL,
+		PRIMARY KEY (chain, currency, date)
+	);`
+
+	UpsertDailyPrice = `INSERT INTO daily_price (chain, currency, date, price)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chain, currency, date) DO UPDATE SET price = $4;`
+
+	SelectFirstLastDailyPriceDates = `SELECT COALESCE(MIN(date), 0), COALESCE(MAX(date), 0)
+		FROM daily_price
+		WHERE chain = $1 AND currency = $2;`
+
+	// SelectDailyPrices selects the daily prices from $3 to $4, starting from
+	// the latest day with a price at or before $3.
+	SelectDailyPrices = `SELECT date, price
+		FROM daily_price
+		WHERE chain = $1 AND currency = $2 AND date <= $4
+			AND date >= (SELECT COALESCE(MAX(date), 0) FROM daily_price
+				WHERE chain = $1 AND currency = $2 AND date <= $3)
+		ORDER BY date;`
+
+	SelectDailyPriceCurrencies = `SELECT DISTINCT currency FROM daily_price
+		WHERE chain = $1 ORDER BY currency;`
+
+	// selectDCRAddressFiatFlow sums the received and sent amounts of an
+	// address valued at the price of the day of their block, in atoms times
+	// the price. The last price within 3 days before the block applies.
+	selectDCRAddressFiatFlow = `SELECT %s AS timestamp,
+			SUM(CASE WHEN a.is_funding THEN a.value * p.price ELSE 0 END),
+			SUM(CASE WHEN a.is_funding THEN 0 ELSE a.value * p.price END)
+		FROM addresses a
+		JOIN LATERAL (SELECT price FROM daily_price
+			WHERE chain = 'dcr' AND currency = $2
+				AND date <= EXTRACT(EPOCH FROM a.block_time)
+				AND date >= EXTRACT(EPOCH FROM a.block_time) - 259200
+			ORDER BY date DESC LIMIT 1) p ON TRUE
+		WHERE a.address = $1 AND a.valid_mainchain
+		GROUP BY timestamp
+		ORDER BY timestamp;`
+
+	// selectMutilchainAddressFiatFlow is selectDCRAddressFiatFlow for the
+	// BTC and LTC address tables, whose rows are both the funding and the
+	// spending of an output.
+	selectMutilchainAddressFiatFlow = `WITH flows AS (
+			SELECT to_timestamp(t.block_time) AS block_time, a.value AS received, 0 AS sent
+			FROM %[1]saddresses a
+			JOIN %[1]stransactions t ON t.tx_hash = a.funding_tx_hash
+			WHERE a.address = $1
+			UNION ALL
+			SELECT to_timestamp(t.block_time), 0, a.value
+			FROM %[1]saddresses a
+			JOIN %[1]stransactions t ON t.tx_hash = a.spending_tx_hash
+			WHERE a.address = $1 AND a.spending_tx_hash <> ''
+		)
+		SELECT %[2]s AS timestamp, SUM(f.received * p.price), SUM(f.sent * p.price)
+		FROM flows f
+		JOIN LATERAL (SELECT price FROM daily_price
+			WHERE chain = '%[1]s' AND currency = $2
+				AND date <= EXTRACT(EPOCH FROM f.block_time)
+				AND date >= EXTRACT(EPOCH FROM f.block_time) - 259200
+			ORDER BY date DESC LIMIT 1) p ON TRUE
+		GROUP BY timestamp
+		ORDER BY timestamp;`
 )
+
+// MakeSelectAddressFiatFlow returns the query of the fiat flow of an address
+// of a chain, grouped by the time interval group.
+func MakeSelectAddressFiatFlow(chainType, group string) string {
+	if chainType == mutilchain.TYPEDCR {
+		return formatGroupingQuery(selectDCRAddressFiatFlow, group, "a.block_time")
+	}
+	timestamp := "f.block_time"
+	if group != "all" {
+		timestamp = fmt.Sprintf("date_trunc('%s', f.block_time)", group)
+	}
+	return fmt.Sprintf(selectMutilchainAddressFiatFlow, chainType, timestamp)
+}
@@ -230,6 +230,9 @@ const (
 	DeleteSwaps = `DELETE FROM swaps
 		WHERE spend_height=$1;`
 
+	DeleteMixStats = `DELETE FROM mix_stats
+		WHERE block_height=$1;`
+
 	DeleteBlock = `DELETE FROM blocks
 		WHERE hash=$1;`
 
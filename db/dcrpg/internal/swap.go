@@ -3,6 +3,8 @@ package internal
 import (
 	"fmt"
 	"strings"
+
+	"github.com/lib/pq"
 )
 
 const (
@@ -39,32 +41,47 @@ const (
 	IndexSwapsOnHeight   = IndexSwapsOnHeightV0
 	DeindexSwapsOnHeight = `DROP INDEX idx_swaps_height;`
 
-	SelectAtomicSwapsContractGroupWithFilter = `SELECT gr1.group_tx, gr1.target, gr1.refund FROM (SELECT group_tx, MAX(target_token) AS target, (ARRAY_AGG(is_refund))[1] AS refund FROM swaps %s 
-		GROUP BY group_tx ORDER BY MAX(contract_time) DESC) AS gr1 %s
-		LIMIT $1 OFFSET $2;`
+	// IndexSwapsOnGroupTime creates the partial index of the first contract of
+	// each group, the row with contract_tx = group_tx, in the descending
+	// (contract_time, group_tx) order of the contract group list.
+	IndexSwapsOnGroupTime = `CREATE INDEX IF NOT EXISTS idx_swaps_group_time
+		ON swaps (contract_time DESC, group_tx DESC) WHERE contract_tx = group_tx;`
+	DeindexSwapsOnGroupTime = `DROP INDEX IF EXISTS idx_swaps_group_time;`
+
+	IndexSwapsOnGroupTx   = `CREATE INDEX IF NOT EXISTS idx_swaps_group_tx ON swaps (group_tx);`
+	DeindexSwapsOnGroupTx = `DROP INDEX IF EXISTS idx_swaps_group_tx;`
+
+	// selectAtomicSwapsContractGroups selects the contract groups in the
+	// descending (contract_time, group_tx) order of their first contract, the
+	// row with contract_tx = group_tx, which is a scan of idx_swaps_group_time.
+	// The first %s verb is for the filter conditions of
+	// makeAtomicSwapsGroupConds and the second one for the LIMIT clause.
+	selectAtomicSwapsContractGroups = `SELECT DISTINCT ON (g.contract_time, g.group_tx) g.group_tx, g.contract_time,
+			(SELECT MAX(s.target_token) FROM swaps s WHERE s.group_tx = g.group_tx) AS target
+		FROM swaps g
+		WHERE g.contract_tx = g.group_tx %s
+		ORDER BY g.contract_time DESC, g.group_tx DESC
+		%s;`
+
+	// countAtomicSwapsContractGroups counts the contract groups selected by
+	// selectAtomicSwapsContractGroups.
+	countAtomicSwapsContractGroups = `SELECT COUNT(DISTINCT g.group_tx) FROM swaps g
+		WHERE g.contract_tx = g.group_tx %s;`
+
+	// atomicSwapsGroupSearchCond selects the groups with a contract or spend
+	// transaction, on Decred or on the other chain of the swap, of hash $1.
+	atomicSwapsGroupSearchCond = `g.group_tx IN (SELECT group_tx FROM swaps WHERE contract_tx = $1 OR spend_tx = $1
+		OR contract_tx IN (SELECT decred_contract_tx FROM btc_swaps WHERE contract_tx = $1 OR spend_tx = $1)
+		OR contract_tx IN (SELECT decred_contract_tx FROM ltc_swaps WHERE contract_tx = $1 OR spend_tx = $1))`
 
 	SelectMultichainSwapInfoRows = `SELECT * FROM %s_swaps WHERE contract_tx = $1 OR spend_tx = $1 ORDER BY lock_time DESC;`
 
-	SelectAtomicSwapsContractGroupWithSearchFilter = `SELECT gr1.group_tx, gr1.target, gr1.refund FROM (SELECT group_tx, MAX(target_token) AS target, (ARRAY_AGG(is_refund))[1] AS refund FROM swaps WHERE (contract_tx = $1 OR spend_tx = $1 
-		OR contract_tx IN (SELECT decred_contract_tx FROM btc_swaps WHERE contract_tx = $1 OR spend_tx = $1)
-		OR contract_tx IN (SELECT decred_contract_tx FROM ltc_swaps WHERE contract_tx = $1 OR spend_tx = $1))
-		 %s 
- 		GROUP BY group_tx ORDER BY MAX(contract_time) DESC) AS gr1 %s
-		LIMIT $2 OFFSET $3;`
-
 	SelectAtomicSpendsByContractTx = `SELECT spend_tx, spend_vin, spend_height, value, lock_time FROM swaps WHERE contract_tx = $1 AND group_tx = $2 ORDER BY lock_time;`
 
 	SelectContractListByGroupTx = `SELECT ctx.contract_tx, MAX(ctx.contract_time), SUM(value) FROM (SELECT contract_tx, contract_time, value FROM swaps 
 		WHERE group_tx = $1 ORDER BY contract_time,lock_time DESC) AS ctx GROUP BY ctx.contract_tx;`
 
-	SelectSwapGroupTx                   = `SELECT group_tx FROM swaps WHERE contract_tx = $1 OR spend_tx = $2 LIMIT 1`
-	CountAtomicSwapsRowWithFilter       = `SELECT COUNT(1) FROM (SELECT group_tx, MAX(target_token) AS target FROM swaps %s GROUP BY group_tx) AS gr1 %s;`
-	CountAtomicSwapsRowWithSearchFilter = `SELECT COUNT(1) FROM (SELECT group_tx, MAX(target_token) AS target FROM swaps 
-	WHERE (contract_tx = $1 OR spend_tx = $1 
-	OR contract_tx IN (SELECT decred_contract_tx FROM btc_swaps WHERE contract_tx = $1 OR spend_tx = $1)
-	OR contract_tx IN (SELECT decred_contract_tx FROM ltc_swaps WHERE contract_tx = $1 OR spend_tx = $1))
-	%s 
- 	GROUP BY group_tx) AS gr1 %s;`
+	SelectSwapGroupTx = `SELECT group_tx FROM swaps WHERE contract_tx = $1 OR spend_tx = $2 LIMIT 1`
 
 	SelectDecredMinTime       = `SELECT COALESCE(MIN(lock_time), 0) AS min_time FROM swaps`
 	CountAtomicSwapsRow       = `SELECT COUNT(1) FROM (SELECT group_tx FROM swaps GROUP BY group_tx) AS ctx;`
@@ -150,50 +167,68 @@ const (
 	SelectMultichainGroupTxsFromTxs      = `SELECT decred_contract_tx FROM %s_swaps WHERE contract_tx = ANY($1) OR spend_tx = ANY($1) GROUP BY decred_contract_tx ORDER BY MAX(lock_time) DESC;`
 )
 
+// MakeSelectAtomicSwapsContractGroupWithFilter returns the contract group
+// query for a page at an offset. The limit and offset arguments are first.
 func MakeSelectAtomicSwapsContractGroupWithFilter(pair, status string) string {
-	return MakeSelectWithFilter(SelectAtomicSwapsContractGroupWithFilter, pair, status, false)
+	return fmt.Sprintf(selectAtomicSwapsContractGroups, makeAtomicSwapsGroupConds(pair, status, false),
+		"LIMIT $1 OFFSET $2")
 }
 
+// MakeSelectAtomicSwapsContractGroupWithSearchFilter is like
+// MakeSelectAtomicSwapsContractGroupWithFilter, but for the groups of the
+// search key argument, which precedes the limit and offset arguments.
 func MakeSelectAtomicSwapsContractGroupWithSearchFilter(pair, status string) string {
-	return MakeSelectWithFilter(SelectAtomicSwapsContractGroupWithSearchFilter, pair, status, true)
+	return fmt.Sprintf(selectAtomicSwapsContractGroups, makeAtomicSwapsGroupConds(pair, status, true),
+		"LIMIT $2 OFFSET $3")
+}
+
+// MakeSelectAtomicSwapsContractGroupByCursor returns the contract group query
+// for a page cursor, which seeks on (contract_time, group_tx). The cursor and
+// limit arguments are first, or follow the search key argument if search is
+// true.
+func MakeSelectAtomicSwapsContractGroupByCursor(pair, status string, search bool) string {
+	first := 1
+	if search {
+		first = 2
+	}
+	conds := makeAtomicSwapsGroupConds(pair, status, search) +
+		fmt.Sprintf(" AND (g.contract_time, g.group_tx) < ($%d, $%d)", first, first+1)
+	return fmt.Sprintf(selectAtomicSwapsContractGroups, conds, fmt.Sprintf("LIMIT $%d", first+2))
 }
 
 func MakeCountAtomicSwapsRowWithFilter(pair, status string) string {
-	return MakeSelectWithFilter(CountAtomicSwapsRowWithFilter, pair, status, false)
+	return fmt.Sprintf(countAtomicSwapsContractGroups, makeAtomicSwapsGroupConds(pair, status, false))
 }
 
 func MakeCountAtomicSwapsRowWithSearchFilter(pair, status string) string {
-	return MakeSelectWithFilter(CountAtomicSwapsRowWithSearchFilter, pair, status, true)
+	return fmt.Sprintf(countAtomicSwapsContractGroups, makeAtomicSwapsGroupConds(pair, status, true))
 }
 
-func MakeSelectWithFilter(input, pair, status string, withoutWhere bool) string {
-	queries := make([]string, 0)
-	pairCond := ""
+// makeAtomicSwapsGroupConds returns the conditions on the first contract g of
+// a group for the pair and status filters, and for the search key if search
+// is true. A group matches a status if any of its spends does.
+func makeAtomicSwapsGroupConds(pair, status string, search bool) string {
+	var conds []string
+	if search {
+		conds = append(conds, atomicSwapsGroupSearchCond)
+	}
 	if pair == "unknown" {
-		pairCond = "WHERE gr1.target IS NULL OR gr1.target = ''"
+		conds = append(conds, `NOT EXISTS (SELECT 1 FROM swaps s WHERE s.group_tx = g.group_tx
+			AND s.target_token IS NOT NULL AND s.target_token <> '')`)
 	} else if pair != "" && pair != "all" {
-		pairCond = fmt.Sprintf("WHERE gr1.target = '%s'", pair)
+		conds = append(conds, fmt.Sprintf(`EXISTS (SELECT 1 FROM swaps s WHERE s.group_tx = g.group_tx
+			AND s.target_token = %s)`, pq.QuoteLiteral(pair)))
 	}
-	if status != "" && status != "all" {
-		switch status {
-		case "refund":
-			queries = append(queries, "is_refund = true")
-		case "redemption":
-			queries = append(queries, "is_refund = false")
-		default:
-		}
+	switch status {
+	case "refund":
+		conds = append(conds, "EXISTS (SELECT 1 FROM swaps s WHERE s.group_tx = g.group_tx AND s.is_refund)")
+	case "redemption":
+		conds = append(conds, "EXISTS (SELECT 1 FROM swaps s WHERE s.group_tx = g.group_tx AND NOT s.is_refund)")
 	}
-	query := ""
-	if len(queries) > 0 {
-		var cond string
-		if !withoutWhere {
-			cond = "WHERE "
-		} else {
-			cond = " AND "
-		}
-		query = cond + strings.Join(queries, " AND ")
+	if len(conds) == 0 {
+		return ""
 	}
-	return fmt.Sprintf(input, query, pairCond)
+	return "AND " + strings.Join(conds, " AND ")
 }
 
 func MakeSelectSwapsAmount(group string) string {
@@ -3,6 +3,8 @@
 
 package internal
 
+import "fmt"
+
 // These queries relate primarily to the "treasury" table.
 const (
 	CreateTreasuryTable = `CREATE TABLE IF NOT EXISTS treasury (
@@ -207,6 +209,18 @@ WHERE EXTRACT(YEAR FROM time AT TIME ZONE 'UTC') = $1 GROUP BY tx_year;`
 		FROM treasury WHERE is_mainchain AND block_time >= $1 AND block_time <= $2 AND block_height <= $3
 		ORDER BY block_time, tx_hash ASC;`
 	SelectTreasuryBalanceChangeIn24h = `SELECT SUM(value) FROM treasury WHERE block_time >= NOW() - INTERVAL '24 hours';`
+
+	// selectTreasuryTxnsByCursor selects the mainchain treasury transactions
+	// after the page cursor ($1, $2), in descending (block height, tx index)
+	// order. See dbtypes.PageCursor. The %s verb is for the type and period
+	// conditions.
+	selectTreasuryTxnsByCursor = `SELECT treasury.*, ` + TxIndexExpr + `
+		FROM treasury
+		JOIN transactions txs ON txs.tx_hash = treasury.tx_hash AND txs.block_hash = treasury.block_hash
+		WHERE treasury.is_mainchain %s
+			AND (treasury.block_height, ` + TxIndexExpr + `) < ($1, $2)
+		ORDER BY treasury.block_height DESC, txs.tree DESC, txs.block_index DESC
+		LIMIT $3;`
 )
 
 // MakeTreasuryInsertStatement returns the appropriate treasury insert statement
@@ -228,6 +242,26 @@ func MakeTreasuryInsertStatement(checked, updateOnConflict bool) string {
 	return InsertTreasuryRowOnConflictDoNothing
 }
 
+// MakeSelectTreasuryTxnsByCursor returns the selectTreasuryTxnsByCursor query
+// with the optional tx type, year and month conditions. The arguments of the
+// conditions follow the cursor and limit arguments, in the same order.
+func MakeSelectTreasuryTxnsByCursor(typed, year, month bool) string {
+	var cond string
+	arg := 4
+	if typed {
+		cond += fmt.Sprintf(" AND treasury.tx_type = $%d", arg)
+		arg++
+	}
+	if year {
+		cond += fmt.Sprintf(" AND EXTRACT(YEAR FROM treasury.block_time AT TIME ZONE 'UTC') = $%d", arg)
+		arg++
+	}
+	if month {
+		cond += fmt.Sprintf(" AND EXTRACT(MONTH FROM treasury.block_time AT TIME ZONE 'UTC') = $%d", arg)
+	}
+	return fmt.Sprintf(selectTreasuryTxnsByCursor, cond)
+}
+
 func MakeSelectTreasuryIOStatement(group string) string {
 	return formatGroupingQuery(selectBinnedIO, group, "block_time")
 }
@@ -12,6 +12,11 @@ import (
 
 // These queries relate primarily to the "transactions" table.
 const (
+	// TxIndexExpr is the position of a transaction of the transactions table,
+	// aliased txs, in its block, as used by the TxIndex of dbtypes.PageCursor.
+	// Stake tree transactions are offset by dbtypes.StakeTreeTxIndexOffset.
+	TxIndexExpr = `(txs.tree * 65536 + txs.block_index)`
+
 	CreateTransactionTable = `CREATE TABLE IF NOT EXISTS transactions (
 		id SERIAL8 PRIMARY KEY,
 		block_hash TEXT,
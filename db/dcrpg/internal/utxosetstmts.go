@@ -0,0 +1,305 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrdata/v8/txhelpers"
+)
+
+// The UTXO set tables of the DCR, BTC and LTC chains, with the table name
+// prefix of the chain ("" for DCR). The utxo_set table holds the unspent
+// outputs of the chain aggregated by value bucket, script type and dust, which
+// is updated one block at a time from the changes of the block kept in
+// utxo_set_deltas for the recent blocks, to revert them on a reorg. The
+// utxo_set_blocks table holds the recent blocks applied to utxo_set, with the
+// validity of their regular transactions on DCR, set by the next block. Every
+// snapshot interval, the totals of the set are stored in utxo_set_snapshots
+// and the aggregated rows in utxo_set_history. The values are in atoms, and
+// the sizes in bytes are the serialize sizes of the outputs.
+const (
+	CreateUtxoSetTable = `CREATE TABLE IF NOT EXISTS %sutxo_set (
+		value_bucket INT2 NOT NULL,
+		script_type TEXT NOT NULL,
+		dust BOOLEAN NOT NULL,
+		count INT8 NOT NULL,
+		value INT8 NOT NULL,
+		size INT8 NOT NULL,
+		PRIMARY KEY (value_bucket, script_type, dust)
+	);`
+
+	CreateUtxoSetDeltasTable = `CREATE TABLE IF NOT EXISTS %sutxo_set_deltas (
+		height INT8 NOT NULL,
+		value_bucket INT2 NOT NULL,
+		script_type TEXT NOT NULL,
+		dust BOOLEAN NOT NULL,
+		count INT8 NOT NULL,
+		value INT8 NOT NULL,
+		size INT8 NOT NULL,
+		PRIMARY KEY (height, value_bucket, script_type, dust)
+	);`
+
+	CreateUtxoSetBlocksTable = `CREATE TABLE IF NOT EXISTS %sutxo_set_blocks (
+		height INT8 PRIMARY KEY,
+		hash TEXT NOT NULL,
+		is_valid BOOLEAN NOT NULL
+	);`
+
+	CreateUtxoSetSnapshotsTable = `CREATE TABLE IF NOT EXISTS %sutxo_set_snapshots (
+		height INT8 PRIMARY KEY,
+		hash TEXT NOT NULL,
+		time TIMESTAMPTZ NOT NULL,
+		count INT8 NOT NULL,
+		value INT8 NOT NULL,
+		size INT8 NOT NULL,
+		dust_count INT8 NOT NULL,
+		dust_value INT8 NOT NULL
+	);`
+
+	CreateUtxoSetHistoryTable = `CREATE TABLE IF NOT EXISTS %sutxo_set_history (
+		height INT8 NOT NULL,
+		value_bucket INT2 NOT NULL,
+		script_type TEXT NOT NULL,
+		dust BOOLEAN NOT NULL,
+		count INT8 NOT NULL,
+		value INT8 NOT NULL,
+		size INT8 NOT NULL,
+		PRIMARY KEY (height, value_bucket, script_type, dust)
+	);`
+
+	SelectUtxoSetTip = `SELECT height, hash, is_valid FROM %sutxo_set_blocks ORDER BY height DESC LIMIT 1;`
+
+	SelectUtxoSetHash = `SELECT hash, is_valid FROM %sutxo_set_blocks WHERE height = $1;`
+
+	// SelectUtxoSetBlocksMinHeight is the height of the oldest block whose
+	// changes can be reverted.
+	SelectUtxoSetBlocksMinHeight = `SELECT COALESCE(MIN(height), -1) FROM %sutxo_set_blocks;`
+
+	// insertUtxoSetDeltas stores the changes to utxo_set of the block at the
+	// height $2, from the outputs of the source subquery with the columns
+	// sign (1 for a created output, -1 for a spent one), value, pkscript and
+	// script_type. The script type expression is on the columns of the source,
+	// and the size and dust expressions on the script and its length in k.
+	// utxoValueBucket is the index in dbtypes.UtxoValueBuckets of the value
+	// of an output.
+	utxoValueBucket = `CASE WHEN s.value < 1000 THEN 0 ELSE LEAST(length(s.value::TEXT) - 3, 10) END`
+
+	insertUtxoSetDeltas = `INSERT INTO %[1]sutxo_set_deltas (height, value_bucket, script_type, dust, count, value, size)
+		SELECT $2::INT8, d.value_bucket, d.script_type, d.dust, SUM(d.sign), SUM(d.sign * d.value),
+			SUM(d.sign * d.size)
+		FROM (
+			SELECT s.sign, s.value,
+				` + utxoValueBucket + ` AS value_bucket,
+				%[2]s AS script_type, %[3]s AS size, %[4]s AS dust
+			FROM (%[5]s) s,
+				LATERAL (SELECT COALESCE(s.pkscript, ''::BYTEA) AS pkscript,
+					length(COALESCE(s.pkscript, ''::BYTEA)) AS len) k
+		) d
+		GROUP BY d.value_bucket, d.script_type, d.dust
+		ON CONFLICT (height, value_bucket, script_type, dust) DO UPDATE
+		SET count = EXCLUDED.count, value = EXCLUDED.value, size = EXCLUDED.size;`
+
+	// ApplyUtxoSetDeltas adds the changes of the block at the height $1 to
+	// utxo_set.
+	ApplyUtxoSetDeltas = `INSERT INTO %[1]sutxo_set AS u (value_bucket, script_type, dust, count, value, size)
+		SELECT value_bucket, script_type, dust, count, value, size FROM %[1]sutxo_set_deltas
+		WHERE height = $1
+		ON CONFLICT (value_bucket, script_type, dust) DO UPDATE
+		SET count = u.count + EXCLUDED.count, value = u.value + EXCLUDED.value,
+			size = u.size + EXCLUDED.size;`
+
+	// RevertUtxoSetDeltas subtracts the changes of the blocks above the height
+	// $1 from utxo_set.
+	RevertUtxoSetDeltas = `UPDATE %[1]sutxo_set u
+		SET count = u.count - d.count, value = u.value - d.value, size = u.size - d.size
+		FROM (SELECT value_bucket, script_type, dust, SUM(count) AS count, SUM(value) AS value,
+				SUM(size) AS size
+			FROM %[1]sutxo_set_deltas WHERE height > $1
+			GROUP BY value_bucket, script_type, dust) d
+		WHERE u.value_bucket = d.value_bucket AND u.script_type = d.script_type AND u.dust = d.dust;`
+
+	InsertUtxoSetBlock = `INSERT INTO %sutxo_set_blocks (height, hash, is_valid) VALUES ($1, $2, $3)
+		ON CONFLICT (height) DO UPDATE SET hash = EXCLUDED.hash, is_valid = EXCLUDED.is_valid;`
+
+	// InsertUtxoSetSnapshot stores the totals of utxo_set at the block with the
+	// height $1, hash $2 and time $3.
+	InsertUtxoSetSnapshot = `INSERT INTO %[1]sutxo_set_snapshots (height, hash, time, count, value, size,
+			dust_count, dust_value)
+		SELECT $1::INT8, $2::TEXT, to_timestamp($3::INT8), COALESCE(SUM(count), 0),
+			COALESCE(SUM(value), 0), COALESCE(SUM(size), 0),
+			COALESCE(SUM(count) FILTER (WHERE dust), 0), COALESCE(SUM(value) FILTER (WHERE dust), 0)
+		FROM %[1]sutxo_set
+		ON CONFLICT (height) DO UPDATE
+		SET hash = EXCLUDED.hash, time = EXCLUDED.time, count = EXCLUDED.count,
+			value = EXCLUDED.value, size = EXCLUDED.size, dust_count = EXCLUDED.dust_count,
+			dust_value = EXCLUDED.dust_value;`
+
+	// InsertUtxoSetHistory stores the rows of utxo_set at the height $1.
+	InsertUtxoSetHistory = `INSERT INTO %[1]sutxo_set_history (height, value_bucket, script_type, dust,
+			count, value, size)
+		SELECT $1::INT8, value_bucket, script_type, dust, count, value, size
+		FROM %[1]sutxo_set WHERE count <> 0
+		ON CONFLICT (height, value_bucket, script_type, dust) DO UPDATE
+		SET count = EXCLUDED.count, value = EXCLUDED.value, size = EXCLUDED.size;`
+
+	DeleteEmptyUtxoSet          = `DELETE FROM %sutxo_set WHERE count = 0;`
+	DeleteUtxoSetDeltasAbove    = `DELETE FROM %sutxo_set_deltas WHERE height > $1;`
+	DeleteUtxoSetDeltasUpTo     = `DELETE FROM %sutxo_set_deltas WHERE height <= $1;`
+	DeleteUtxoSetBlocksAbove    = `DELETE FROM %sutxo_set_blocks WHERE height > $1;`
+	DeleteUtxoSetBlocksUpTo     = `DELETE FROM %sutxo_set_blocks WHERE height <= $1;`
+	DeleteUtxoSetSnapshotsAbove = `DELETE FROM %sutxo_set_snapshots WHERE height > $1;`
+	DeleteUtxoSetHistoryAbove   = `DELETE FROM %sutxo_set_history WHERE height > $1;`
+	TruncateUtxoSetTables       = `TRUNCATE %[1]sutxo_set, %[1]sutxo_set_deltas, %[1]sutxo_set_blocks, %[1]sutxo_set_snapshots, %[1]sutxo_set_history;`
+
+	SelectUtxoSetSnapshotHash  = `SELECT hash FROM %sutxo_set_snapshots WHERE height = $1;`
+	SelectUtxoSetSnapshotsFrom = `SELECT height, hash, EXTRACT(EPOCH FROM time)::INT8, count, value, size, dust_count, dust_value
+		FROM %sutxo_set_snapshots WHERE height >= $1 ORDER BY height;`
+	SelectLatestUtxoSetSnapshot = `SELECT height, hash, EXTRACT(EPOCH FROM time)::INT8, count, value, size, dust_count, dust_value
+		FROM %sutxo_set_snapshots ORDER BY height DESC LIMIT 1;`
+	SelectUtxoSetHistoryAtHeight = `SELECT value_bucket, script_type, dust, count, value, size
+		FROM %sutxo_set_history WHERE height = $1;`
+
+	// SelectDCRUtxoSetBlockOutputs is the source of InsertUtxoSetDeltas on DCR:
+	// the outputs created and spent by the valid main chain transactions of the
+	// block with the hash $1. The outputs that are not spendable, the null data,
+	// ticket commitment and treasury add outputs, are left out.
+	SelectDCRUtxoSetBlockOutputs = `SELECT 1 AS sign, o.value, o.pkscript, o.script_type
+		FROM transactions t
+		JOIN vouts o ON o.tx_hash = t.tx_hash AND o.tx_tree = t.tree
+		WHERE t.block_hash = $1 AND t.is_valid AND t.is_mainchain
+			AND o.script_type NOT IN ('nulldata', 'sstxcommitment', 'treasuryadd')
+		UNION ALL
+		SELECT -1, o.value, o.pkscript, o.script_type
+		FROM transactions t
+		JOIN vins v ON v.tx_hash = t.tx_hash AND v.tx_tree = t.tree
+		JOIN vouts o ON o.tx_hash = v.prev_tx_hash AND o.tx_index = v.prev_tx_index
+			AND o.tx_tree = v.prev_tx_tree
+		WHERE t.block_hash = $1 AND t.is_valid AND t.is_mainchain`
+
+	// SelectDCRUtxoSetBlock selects the main chain block at the height $1, if
+	// the validity of its regular transactions is set by the next block.
+	SelectDCRUtxoSetBlock = `SELECT b.hash, b.previous_hash, EXTRACT(EPOCH FROM b.time)::INT8, b.is_valid
+		FROM blocks b
+		WHERE b.height = $1 AND b.is_mainchain
+			AND EXISTS (SELECT 1 FROM blocks n WHERE n.height = $1 + 1 AND n.is_mainchain);`
+
+	SelectDCRMainchainBlockValidity = `SELECT hash, is_valid FROM blocks WHERE height = $1 AND is_mainchain;`
+)
+
+// MakeUtxoSetTables returns the names and creation statements of the UTXO set
+// tables of a chain.
+func MakeUtxoSetTables(prefix string) [][2]string {
+	return [][2]string{
+		{prefix + "utxo_set", fmt.Sprintf(CreateUtxoSetTable, prefix)},
+		{prefix + "utxo_set_deltas", fmt.Sprintf(CreateUtxoSetDeltasTable, prefix)},
+		{prefix + "utxo_set_blocks", fmt.Sprintf(CreateUtxoSetBlocksTable, prefix)},
+		{prefix + "utxo_set_snapshots", fmt.Sprintf(CreateUtxoSetSnapshotsTable, prefix)},
+		{prefix + "utxo_set_history", fmt.Sprintf(CreateUtxoSetHistoryTable, prefix)},
+	}
+}
+
+// MakeInsertUtxoSetDeltas returns the statement storing the changes to the
+// UTXO set of a block, from the outputs selected by source, with the script
+// type given by the scriptType expression on its script_type column, and the
+// sizes and dust of the outputs by the dust rule of the chain.
+func MakeInsertUtxoSetDeltas(prefix, source, scriptType string, rule txhelpers.DustRule) string {
+	size, dust := utxoSizeDust(rule)
+	return fmt.Sprintf(insertUtxoSetDeltas, prefix, scriptType, size, dust, source)
+}
+
+// utxoSizeDust returns the expressions of the serialize size of an output and
+// of its dust flag by the dust rule, the SQL counterparts of
+// txhelpers.DustRule.OutputSize and IsDust, on the value s.value and the
+// script k.pkscript of length k.len.
+func utxoSizeDust(rule txhelpers.DustRule) (size, dust string) {
+	size = fmt.Sprintf(`(%d + CASE WHEN k.len < 253 THEN 1 WHEN k.len <= 65535 THEN 3 ELSE 5 END + k.len)`,
+		rule.OutputOverhead)
+	inputSize := fmt.Sprint(rule.InputSize)
+	if rule.WitnessInputSize > 0 {
+		// See txhelpers.IsWitnessProgram. The bytes of the script are only
+		// read in the inner CASE, after its length is checked, since the
+		// operands of AND may be evaluated in any order.
+		inputSize = fmt.Sprintf(`CASE WHEN k.len BETWEEN 4 AND 42 THEN
+				CASE WHEN (get_byte(k.pkscript, 0) = 0 OR get_byte(k.pkscript, 0) BETWEEN 81 AND 96)
+					AND get_byte(k.pkscript, 1) = k.len - 2
+				THEN %d ELSE %d END
+			ELSE %d END`, rule.WitnessInputSize, rule.InputSize, rule.InputSize)
+	}
+	dust = fmt.Sprintf(`s.value * 1000 < %d * (%s + %s)`, rule.DustRelayFeePerKb, size, inputSize)
+	return size, dust
+}
+
+func MakeSelectUtxoSetTip(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetTip, prefix)
+}
+
+func MakeSelectUtxoSetHash(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetHash, prefix)
+}
+
+func MakeSelectUtxoSetBlocksMinHeight(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetBlocksMinHeight, prefix)
+}
+
+func MakeApplyUtxoSetDeltas(prefix string) string {
+	return fmt.Sprintf(ApplyUtxoSetDeltas, prefix)
+}
+
+func MakeInsertUtxoSetBlock(prefix string) string {
+	return fmt.Sprintf(InsertUtxoSetBlock, prefix)
+}
+
+func MakeInsertUtxoSetSnapshot(prefix string) string {
+	return fmt.Sprintf(InsertUtxoSetSnapshot, prefix)
+}
+
+func MakeInsertUtxoSetHistory(prefix string) string {
+	return fmt.Sprintf(InsertUtxoSetHistory, prefix)
+}
+
+// MakeRewindUtxoSetStmts returns the statements reverting the UTXO set tables
+// to the block at the height $1.
+func MakeRewindUtxoSetStmts(prefix string) []string {
+	return []string{
+		fmt.Sprintf(RevertUtxoSetDeltas, prefix),
+		fmt.Sprintf(DeleteUtxoSetDeltasAbove, prefix),
+		fmt.Sprintf(DeleteUtxoSetBlocksAbove, prefix),
+		fmt.Sprintf(DeleteUtxoSetSnapshotsAbove, prefix),
+		fmt.Sprintf(DeleteUtxoSetHistoryAbove, prefix),
+	}
+}
+
+func MakeDeleteEmptyUtxoSet(prefix string) string {
+	return fmt.Sprintf(DeleteEmptyUtxoSet, prefix)
+}
+
+// MakePruneUtxoSetStmts returns the statements deleting the changes of the
+// blocks up to the height $1, which can no longer be reverted.
+func MakePruneUtxoSetStmts(prefix string) []string {
+	return []string{
+		fmt.Sprintf(DeleteUtxoSetDeltasUpTo, prefix),
+		fmt.Sprintf(DeleteUtxoSetBlocksUpTo, prefix),
+	}
+}
+
+func MakeTruncateUtxoSetTables(prefix string) string {
+	return fmt.Sprintf(TruncateUtxoSetTables, prefix)
+}
+
+func MakeSelectUtxoSetSnapshotHash(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetSnapshotHash, prefix)
+}
+
+func MakeSelectUtxoSetSnapshotsFrom(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetSnapshotsFrom, prefix)
+}
+
+func MakeSelectLatestUtxoSetSnapshot(prefix string) string {
+	return fmt.Sprintf(SelectLatestUtxoSetSnapshot, prefix)
+}
+
+func MakeSelectUtxoSetHistoryAtHeight(prefix string) string {
+	return fmt.Sprintf(SelectUtxoSetHistoryAtHeight, prefix)
+}
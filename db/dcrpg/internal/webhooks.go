@@ -0,0 +1,167 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package internal
+
+import "fmt"
+
+// These queries relate to the webhook tables. The "webhook_subscriptions"
+// table holds the callback URLs registered by the owners of API keys, the
+// "webhook_outbox" table the pending deliveries of events, and the
+// "webhook_dead_letters" table the deliveries that failed too many times.
+const (
+	CreateWebhookSubscriptionsTable = `CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+		id SERIAL8 PRIMARY KEY,
+		key_id INT8 NOT NULL REFERENCES api_keys(id),
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		event TEXT NOT NULL,
+		chain TEXT NOT NULL,
+		target TEXT NOT NULL DEFAULT '',
+		confirmations INT4 NOT NULL DEFAULT 0,
+		active BOOLEAN NOT NULL DEFAULT TRUE,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`
+
+	CreateWebhookOutboxTable = `CREATE TABLE IF NOT EXISTS webhook_outbox (
+		id SERIAL8 PRIMARY KEY,
+		subscription_id INT8 NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INT4 NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		next_attempt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`
+
+	CreateWebhookDeadLettersTable = `CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id INT8 PRIMARY KEY,
+		subscription_id INT8 NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INT4 NOT NULL,
+		last_error TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL,
+		failed_at TIMESTAMPTZ NOT NULL
+	);`
+
+	IndexWebhookOutboxOnNextAttempt = `CREATE INDEX IF NOT EXISTS ` + IndexOfWebhookOutboxOnNextAttempt +
+		` ON webhook_outbox(next_attempt);`
+
+	InsertWebhookSubscription = `INSERT INTO webhook_subscriptions (key_id, url, secret, event,
+			chain, target, confirmations)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, active, created_at;`
+
+	selectWebhookSubscriptionColumns = `SELECT id, key_id, url, secret, event, chain, target,
+			confirmations, active, created_at
+		FROM webhook_subscriptions`
+
+	SelectActiveWebhookSubscriptions = selectWebhookSubscriptionColumns + ` WHERE active ORDER BY id;`
+
+	SelectWebhookSubscriptionsByKey = selectWebhookSubscriptionColumns + ` WHERE key_id = $1 ORDER BY id;`
+
+	DeleteWebhookSubscription = `DELETE FROM webhook_subscriptions WHERE id = $1 AND key_id = $2;`
+
+	DeactivateWebhookSubscription = `UPDATE webhook_subscriptions SET active = FALSE WHERE id = $1;`
+
+	InsertWebhookDelivery = `INSERT INTO webhook_outbox (subscription_id, event, payload)
+		VALUES ($1, $2, $3);`
+
+	// ClaimWebhookDeliveries leases the due deliveries for $2 seconds, so
+	// that they are retried if the process delivering them stops, and
+	// returns them with the URL and secret of their subscription. Locked
+	// rows are skipped, so several instances may deliver from the outbox.
+	ClaimWebhookDeliveries = `WITH due AS (
+			SELECT id FROM webhook_outbox
+			WHERE next_attempt <= NOW()
+			ORDER BY next_attempt
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_outbox o SET next_attempt = NOW() + $2 * INTERVAL '1 second'
+		FROM due, webhook_subscriptions s
+		WHERE o.id = due.id AND s.id = o.subscription_id
+		RETURNING o.id, o.subscription_id, o.event, o.payload, o.attempts, o.created_at,
+			s.url, s.secret;`
+
+	DeleteWebhookDelivery = `DELETE FROM webhook_outbox WHERE id = $1;`
+
+	RetryWebhookDelivery = `UPDATE webhook_outbox
+		SET attempts = attempts + 1, next_attempt = $2, last_error = $3
+		WHERE id = $1;`
+
+	// DeadLetterWebhookDelivery moves a failed delivery from the outbox to
+	// the dead letters.
+	DeadLetterWebhookDelivery = `WITH failed AS (
+			DELETE FROM webhook_outbox WHERE id = $1 RETURNING *
+		)
+		INSERT INTO webhook_dead_letters (id, subscription_id, event, payload,
+			attempts, last_error, created_at, failed_at)
+		SELECT id, subscription_id, event, payload, attempts + 1, $2, created_at, NOW()
+		FROM failed;`
+
+	SelectWebhookDeadLettersByKey = `SELECT d.id, d.subscription_id, d.event, d.payload,
+			d.attempts, d.last_error, d.created_at, d.failed_at
+		FROM webhook_dead_letters d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE s.key_id = $1
+		ORDER BY d.failed_at DESC
+		LIMIT $2;`
+
+	// SelectAddressActivityInTxns lists the funding and spending rows of
+	// the addresses in $1 by the mainchain transactions in $2.
+	SelectAddressActivityInTxns = `SELECT address, tx_hash, is_funding, value
+		FROM addresses
+		WHERE address = ANY($1) AND tx_hash = ANY($2) AND valid_mainchain;`
+
+	// SelectTxsMainchainBlocks selects the mainchain block of each of the
+	// transactions in $1.
+	SelectTxsMainchainBlocks = `SELECT DISTINCT ON (tx_hash) tx_hash, block_height, block_hash
+		FROM transactions
+		WHERE tx_hash = ANY($1) AND is_mainchain
+		ORDER BY tx_hash, block_height DESC;`
+
+	SelectSwapRedemptionsAtHeight = `SELECT contract_tx, spend_tx, spend_vin, value,
+			encode(secret_hash, 'hex')
+		FROM swaps
+		WHERE spend_height = $1 AND NOT is_refund;`
+
+	// The BTC and LTC queries are formatted with the chain type.
+	selectMultichainAddressActivityInTxns = `SELECT address, funding_tx_hash, TRUE, value
+			FROM %[1]saddresses
+			WHERE address = ANY($1) AND funding_tx_hash = ANY($2)
+		UNION ALL
+		SELECT address, spending_tx_hash, FALSE, value
+			FROM %[1]saddresses
+			WHERE address = ANY($1) AND spending_tx_hash = ANY($2);`
+
+	selectMultichainTxsBlocks = `SELECT DISTINCT ON (tx_hash) tx_hash, block_height, block_hash
+		FROM %stransactions
+		WHERE tx_hash = ANY($1)
+		ORDER BY tx_hash, block_height DESC;`
+
+	// Refunds of BTC and LTC contracts have no secret.
+	selectMultichainSwapRedemptionsAtHeight = `SELECT contract_tx, spend_tx, spend_vin, value,
+			encode(secret_hash, 'hex')
+		FROM %s_swaps
+		WHERE spend_height = $1 AND secret IS NOT NULL;`
+)
+
+// MakeSelectMultichainAddressActivityInTxns returns the address activity query
+// of a BTC or LTC chain.
+func MakeSelectMultichainAddressActivityInTxns(chainType string) string {
+	return fmt.Sprintf(selectMultichainAddressActivityInTxns, chainType)
+}
+
+// MakeSelectMultichainTxsBlocks returns the transaction blocks query of a BTC
+// or LTC chain.
+func MakeSelectMultichainTxsBlocks(chainType string) string {
+	return fmt.Sprintf(selectMultichainTxsBlocks, chainType)
+}
+
+// MakeSelectMultichainSwapRedemptionsAtHeight returns the swap redemptions
+// query of a BTC or LTC chain.
+func MakeSelectMultichainSwapRedemptionsAtHeight(chainType string) string {
+	return fmt.Sprintf(selectMultichainSwapRedemptionsAtHeight, chainType)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// CheckAndCreateMarketTables creates the tables of the exchange market
+// candlesticks and order book snapshots if they do not already exist.
+func (pgb *ChainDB) CheckAndCreateMarketTables() error {
+	for _, table := range []struct{ name, stmt string }{
+		{"market_candles", internal.CreateMarketCandlesTable},
+		{"market_depth", internal.CreateMarketDepthTable},
+	} {
+		if err := createTable(pgb.db, table.name, table.stmt); err != nil {
+			return err
+		}
+	}
+	_, err := pgb.db.Exec(internal.IndexMarketDepthOnMarketTime)
+	return err
+}
+
+// StoreMarketCandles stores the candlesticks of a market and bin in a single
+// transaction, replacing the stored ones with the same start time.
+func (pgb *ChainDB) StoreMarketCandles(chainType, token, bin string, candles []*dbtypes.MarketCandle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	dbTx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %w", err)
+	}
+	stmt, err := dbTx.PrepareContext(ctx, internal.UpsertMarketCandle)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return pgb.replaceCancelError(err)
+	}
+	defer stmt.Close()
+
+	for _, c := range candles {
+		_, err = stmt.ExecContext(ctx, chainType, token, bin, c.Start,
+			c.Open, c.High, c.Low, c.Close, c.Volume)
+		if err != nil {
+			_ = dbTx.Rollback()
+			return pgb.replaceCancelError(err)
+		}
+	}
+	return dbTx.Commit()
+}
+
+// MarketCandles retrieves the latest candlesticks of a market and bin, at
+// most limit, in ascending order of start time.
+func (pgb *ChainDB) MarketCandles(chainType, token, bin string, limit int) ([]*dbtypes.MarketCandle, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectMarketCandles, chainType, token, bin, limit)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var candles []*dbtypes.MarketCandle
+	for rows.Next() {
+		var c dbtypes.MarketCandle
+		if err = rows.Scan(&c.Start, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, err
+		}
+		candles = append(candles, &c)
+	}
+	return candles, pgb.replaceCancelError(rows.Err())
+}
+
+// InsertMarketDepth stores an order book snapshot of a market.
+func (pgb *ChainDB) InsertMarketDepth(chainType, token string, snapshot *dbtypes.MarketDepthSnapshot) error {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	_, err := pgb.db.ExecContext(ctx, internal.InsertMarketDepth, chainType, token,
+		snapshot.Time, snapshot.MidGap, snapshot.Spread, snapshot.BidDepth,
+		snapshot.AskDepth, string(snapshot.Bids), string(snapshot.Asks))
+	return pgb.replaceCancelError(err)
+}
+
+// MarketLiquidity retrieves the liquidity of the order book snapshots of a
+// market in ascending order of time.
+func (pgb *ChainDB) MarketLiquidity(chainType, token string) ([]*dbtypes.MarketLiquidity, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectMarketLiquidity, chainType, token)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var liquidity []*dbtypes.MarketLiquidity
+	for rows.Next() {
+		var l dbtypes.MarketLiquidity
+		if err = rows.Scan(&l.Time, &l.MidGap, &l.Spread, &l.BidDepth, &l.AskDepth); err != nil {
+			return nil, err
+		}
+		liquidity = append(liquidity, &l)
+	}
+	return liquidity, pgb.replaceCancelError(rows.Err())
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2021, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// mixStatsSyncBatch is the number of blocks aggregated per statement when
+// backfilling the mix_stats table.
+const mixStatsSyncBatch = 10000
+
+// CheckAndCreateMixStatsTable creates the mix_stats table and its block time
+// index if they do not already exist.
+func (pgb *ChainDB) CheckAndCreateMixStatsTable() error {
+	exists, err := TableExists(pgb.db, "mix_stats")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Infof(`tables of %s empty. Creating tables...`, "mix_stats")
+		if err = createTable(pgb.db, "mix_stats", internal.CreateMixStatsTable); err != nil {
+			return fmt.Errorf("failed to create tables: %w", err)
+		}
+	}
+	_, err = pgb.db.Exec(internal.IndexMixStatsOnBlockTime)
+	return err
+}
+
+// SyncMixStatsTable backfills the mix_stats table from the transactions table,
+// starting at the last block with mix statistics and continuing to the best
+// block.
+func (pgb *ChainDB) SyncMixStatsTable() error {
+	pgb.mixStatsSync.Lock()
+	defer pgb.mixStatsSync.Unlock()
+
+	var maxHeight int64
+	err := pgb.db.QueryRowContext(pgb.ctx, internal.SelectMixStatsMaxHeight).Scan(&maxHeight)
+	if err != nil {
+		return fmt.Errorf("get max height of mix_stats failed: %w", err)
+	}
+	bestHeight := pgb.bestBlock.Height()
+	startHeight := maxHeight
+	if startHeight < 0 {
+		startHeight = 0
+	}
+	if startHeight > bestHeight {
+		return nil
+	}
+	log.Infof("Syncing mix_stats table from height %d to %d...", startHeight, bestHeight)
+	start := time.Now()
+	for from := startHeight; from <= bestHeight; from += mixStatsSyncBatch {
+		to := from + mixStatsSyncBatch - 1
+		if to > bestHeight {
+			to = bestHeight
+		}
+		if _, err = pgb.db.ExecContext(pgb.ctx, internal.InsertMixStatsRange, from, to); err != nil {
+			return fmt.Errorf("insert mix_stats rows for blocks %d-%d failed: %w", from, to, err)
+		}
+		log.Debugf("Synced mix_stats to height %d.", to)
+	}
+	log.Infof("Finished syncing mix_stats table in %v.", time.Since(start))
+	return nil
+}
+
+// SyncMixStatsForBlock recomputes the mix statistics for the mainchain block at
+// the given height. Any rows at or above the height are removed first, so that
+// statistics of blocks that were moved to a side chain do not linger.
+func (pgb *ChainDB) SyncMixStatsForBlock(height int64) error {
+	pgb.mixStatsSync.Lock()
+	defer pgb.mixStatsSync.Unlock()
+
+	dbTx, err := pgb.db.BeginTx(pgb.ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start new DB transaction: %w", err)
+	}
+	if _, err = dbTx.Exec(internal.DeleteMixStatsFromHeight, height); err != nil {
+		_ = dbTx.Rollback()
+		return fmt.Errorf("delete mix_stats rows from height %d failed: %w", height, err)
+	}
+	if _, err = dbTx.Exec(internal.InsertMixStatsRange, height, height); err != nil {
+		_ = dbTx.Rollback()
+		return fmt.Errorf("insert mix_stats rows for height %d failed: %w", height, err)
+	}
+	return dbTx.Commit()
+}
+
+// scanMixStats groups rows of (period, height, time, denom, mixes,
+// participants, volume) ordered by period into a MixStats per period.
+func scanMixStats(rows *sql.Rows, byDay bool) ([]*dbtypes.MixStats, error) {
+	defer closeRows(rows)
+
+	var stats []*dbtypes.MixStats
+	var cur *dbtypes.MixStats
+	for rows.Next() {
+		var height int64
+		var t time.Time
+		ds := new(dbtypes.MixDenomStats)
+		var err error
+		if byDay {
+			err = rows.Scan(&t, &ds.Denom, &ds.Mixes, &ds.Participants, &ds.Volume)
+		} else {
+			err = rows.Scan(&height, &t, &ds.Denom, &ds.Mixes, &ds.Participants, &ds.Volume)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if cur == nil || cur.Height != height || !cur.Time.T.Equal(t) {
+			cur = &dbtypes.MixStats{
+				Height: height,
+				Time:   dbtypes.NewTimeDef(t),
+			}
+			stats = append(stats, cur)
+		}
+		cur.AddDenom(ds)
+	}
+	return stats, rows.Err()
+}
+
+// MixStatsByBlockRange retrieves the mix statistics of the blocks in the
+// height range [from, to]. Blocks without mixes are omitted.
+func (pgb *ChainDB) MixStatsByBlockRange(from, to int64) ([]*dbtypes.MixStats, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectMixStatsByBlockRange, from, to)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	stats, err := scanMixStats(rows, false)
+	return stats, pgb.replaceCancelError(err)
+}
+
+// MixStatsByDay retrieves the daily (UTC) mix statistics for the days starting
+// in the time range [from, to). Days without mixes are omitted.
+func (pgb *ChainDB) MixStatsByDay(from, to time.Time) ([]*dbtypes.MixStats, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectMixStatsByDay, from, to)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	stats, err := scanMixStats(rows, true)
+	return stats, pgb.replaceCancelError(err)
+}
+
+// MixDenomSummary retrieves the all-time mix statistics for each mix
+// denomination.
+func (pgb *ChainDB) MixDenomSummary() ([]*dbtypes.MixDenomStats, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectMixStatsByDenom)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var denoms []*dbtypes.MixDenomStats
+	for rows.Next() {
+		ds := new(dbtypes.MixDenomStats)
+		if err = rows.Scan(&ds.Denom, &ds.Mixes, &ds.Participants, &ds.Volume); err != nil {
+			return nil, err
+		}
+		denoms = append(denoms, ds)
+	}
+	return denoms, pgb.replaceCancelError(rows.Err())
+}
+
+// MixTxns retrieves a page of mainchain mix transactions, most recent first,
+// and the total number of mix transactions. If denom is non-zero, only mixes
+// of that denomination are included.
+func (pgb *ChainDB) MixTxns(n, offset, denom int64) ([]*dbtypes.MixTx, int64, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	var count int64
+	var rows *sql.Rows
+	var err error
+	if denom > 0 {
+		err = pgb.db.QueryRowContext(ctx, internal.SelectMixTxnsCountByDenom, denom).Scan(&count)
+		if err == nil {
+			rows, err = pgb.db.QueryContext(ctx, internal.SelectMixTxnsByDenom, n, offset, denom)
+		}
+	} else {
+		err = pgb.db.QueryRowContext(ctx, internal.SelectMixTxnsCount).Scan(&count)
+		if err == nil {
+			rows, err = pgb.db.QueryContext(ctx, internal.SelectMixTxns, n, offset)
+		}
+	}
+	if err != nil {
+		return nil, 0, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var txns []*dbtypes.MixTx
+	for rows.Next() {
+		tx := new(dbtypes.MixTx)
+		err = rows.Scan(&tx.TxID, &tx.BlockHeight, &tx.BlockTime, &tx.MixCount,
+			&tx.MixDenom, &tx.Fees, &tx.Size)
+		if err != nil {
+			return nil, 0, err
+		}
+		txns = append(txns, tx)
+	}
+	return txns, count, pgb.replaceCancelError(rows.Err())
+}
+
+// MixTxnsByCursor retrieves at most n mainchain mix transactions that follow
+// the page cursor, most recent first, and the total number of mix
+// transactions. If denom is non-zero, only mixes of that denomination are
+// included. The encoded cursor of the next page is also returned, or an empty
+// string for the last page.
+func (pgb *ChainDB) MixTxnsByCursor(n int64, cursor *dbtypes.PageCursor, denom int64) ([]*dbtypes.MixTx, int64, string, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	var count int64
+	var rows *sql.Rows
+	var err error
+	if denom > 0 {
+		err = pgb.db.QueryRowContext(ctx, internal.SelectMixTxnsCountByDenom, denom).Scan(&count)
+		if err == nil {
+			rows, err = pgb.db.QueryContext(ctx, internal.SelectMixTxnsByDenomByCursor,
+				n, cursor.Height, cursor.TxIndex, denom)
+		}
+	} else {
+		err = pgb.db.QueryRowContext(ctx, internal.SelectMixTxnsCount).Scan(&count)
+		if err == nil {
+			rows, err = pgb.db.QueryContext(ctx, internal.SelectMixTxnsByCursor,
+				n, cursor.Height, cursor.TxIndex)
+		}
+	}
+	if err != nil {
+		return nil, 0, "", pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var txns []*dbtypes.MixTx
+	var last *dbtypes.PageCursor
+	for rows.Next() {
+		tx := new(dbtypes.MixTx)
+		var blockIndex int64
+		err = rows.Scan(&tx.TxID, &tx.BlockHeight, &tx.BlockTime, &tx.MixCount,
+			&tx.MixDenom, &tx.Fees, &tx.Size, &blockIndex)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		txns = append(txns, tx)
+		last = &dbtypes.PageCursor{Height: tx.BlockHeight, TxIndex: blockIndex}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, "", pgb.replaceCancelError(err)
+	}
+	return txns, count, dbtypes.NextPageCursor(last, len(txns), int(n)), nil
+}
@@ -0,0 +1,150 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// mixTestHeight is the first block height of the mix statistics tests, far
+// above any block of the test database.
+const mixTestHeight = 90_000_000
+
+const mixTestBlockHash = "mixstats-test"
+
+type mixTestTx struct {
+	hash      string
+	height    int64
+	mixCount  int64
+	mixDenom  int64
+	mainchain bool
+}
+
+// seedMixTxns replaces the transactions of the mix statistics tests, and
+// removes the mix statistics of the test heights.
+func seedMixTxns(t *testing.T, txns ...mixTestTx) {
+	t.Helper()
+	if err := db.CheckAndCreateMixStatsTable(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.Exec(`DELETE FROM transactions WHERE block_hash = $1;`, mixTestBlockHash); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.Exec(`DELETE FROM mix_stats WHERE block_height >= $1;`, mixTestHeight); err != nil {
+		t.Fatal(err)
+	}
+	blockTime := time.Unix(trefUNIX, 0)
+	for i, tx := range txns {
+		_, err := db.db.Exec(`INSERT INTO transactions (block_hash, block_height, block_time,
+				tx_hash, block_index, fees, size, mix_count, mix_denom, is_valid, is_mainchain)
+			VALUES ($1, $2, $3, $4, $5, 0, 0, $6, $7, TRUE, $8);`,
+			mixTestBlockHash, tx.height, blockTime.Add(time.Duration(tx.height-mixTestHeight)*time.Minute),
+			tx.hash, i, tx.mixCount, tx.mixDenom, tx.mainchain)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(`DELETE FROM transactions WHERE block_hash = $1;`, mixTestBlockHash)
+		_, _ = db.db.Exec(`DELETE FROM mix_stats WHERE block_height >= $1;`, mixTestHeight)
+	})
+}
+
+func mixStatsAt(t *testing.T, from, to int64) map[int64]*dbtypes.MixStats {
+	t.Helper()
+	stats, err := db.MixStatsByBlockRange(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byHeight := make(map[int64]*dbtypes.MixStats, len(stats))
+	for _, ms := range stats {
+		byHeight[ms.Height] = ms
+	}
+	return byHeight
+}
+
+func TestMixStatsPerDenom(t *testing.T) {
+	const h = mixTestHeight
+	seedMixTxns(t,
+		mixTestTx{"a", h, 3, 1e8, true},
+		mixTestTx{"b", h, 5, 1e8, true},
+		mixTestTx{"c", h, 4, 1e7, true},
+		mixTestTx{"d", h, 0, 0, true},    // not a mix
+		mixTestTx{"e", h, 9, 1e8, false}, // side chain
+		mixTestTx{"f", h + 1, 2, 1e9, true},
+	)
+	for _, height := range []int64{h, h + 1} {
+		if err := db.SyncMixStatsForBlock(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := mixStatsAt(t, h, h+1)
+	if len(stats) != 2 {
+		t.Fatalf("expected the stats of 2 blocks, got %d", len(stats))
+	}
+	ms := stats[h]
+	if ms.Mixes != 3 || ms.Participants != 12 || ms.Volume != 8e8+4e7 {
+		t.Errorf("block %d: %d mixes, %d participants, volume %d", h,
+			ms.Mixes, ms.Participants, ms.Volume)
+	}
+	want := []dbtypes.MixDenomStats{
+		{Denom: 1e7, Mixes: 1, Participants: 4, Volume: 4e7},
+		{Denom: 1e8, Mixes: 2, Participants: 8, Volume: 8e8},
+	}
+	if len(ms.Denoms) != len(want) {
+		t.Fatalf("block %d: expected %d denominations, got %d", h, len(want), len(ms.Denoms))
+	}
+	for i, ds := range ms.Denoms {
+		if *ds != want[i] {
+			t.Errorf("block %d: denomination %d is %+v, expected %+v", h, i, *ds, want[i])
+		}
+	}
+	if ms := stats[h+1]; ms.Mixes != 1 || ms.Volume != 2e9 {
+		t.Errorf("block %d: %d mixes, volume %d", h+1, ms.Mixes, ms.Volume)
+	}
+}
+
+func TestMixStatsRewind(t *testing.T) {
+	const h = mixTestHeight
+	seedMixTxns(t,
+		mixTestTx{"a", h, 3, 1e8, true},
+		mixTestTx{"b", h + 1, 4, 1e8, true},
+		mixTestTx{"c", h + 2, 5, 1e8, true},
+	)
+	for _, height := range []int64{h, h + 1, h + 2} {
+		if err := db.SyncMixStatsForBlock(height); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A side chain block replaces the block at h+1 with a block without
+	// mixes. The stats of the blocks at and above it are removed.
+	_, err := db.db.Exec(`UPDATE transactions SET is_mainchain = FALSE
+		WHERE block_hash = $1 AND block_height >= $2;`, mixTestBlockHash, h+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.SyncMixStatsForBlock(h + 1); err != nil {
+		t.Fatal(err)
+	}
+	stats := mixStatsAt(t, h, h+2)
+	if len(stats) != 1 || stats[h] == nil || stats[h].Mixes != 1 {
+		t.Fatalf("expected only the stats of block %d, got %v", h, stats)
+	}
+
+	// Disconnecting the block at h removes its stats.
+	n, err := deleteMixStatsForBlockHeight(db.db, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 deleted row, got %d", n)
+	}
+	if stats = mixStatsAt(t, h, h+2); len(stats) != 0 {
+		t.Errorf("expected no stats, got %v", stats)
+	}
+}
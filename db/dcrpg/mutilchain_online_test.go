@@ -0,0 +1,62 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/lib/pq"
+)
+
+// createMultichainTestTables creates the types and tables of a chain in the
+// test database if they do not exist.
+func createMultichainTestTables(t *testing.T, chainType string) {
+	t.Helper()
+	if err := CreateMutilchainTypes(db.db, chainType); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateMutilchainTables(db.db, chainType); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRetrieveMutilchainScripthashAddress(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	createMultichainTestTables(t, chainType)
+
+	// The output is only in the whole-chain table, as the outputs of the
+	// older blocks are pruned from the light table.
+	const txHash = "scripthash-test"
+	pkScript := []byte{0x00, 0x14, 0xde, 0xad, 0xbe, 0xef}
+	const address = "bc1qscripthashtest"
+	_, err := db.db.Exec(`INSERT INTO btcvouts_all (tx_hash, tx_index, tx_tree, value,
+			version, pkscript, script_req_sigs, script_type, script_addresses)
+		VALUES ($1, 0, 0, 1000, 0, $2, 1, 'witness_v0_keyhash', $3);`,
+		txHash, pkScript, pq.Array([]string{address}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(`DELETE FROM btcvouts_all WHERE tx_hash = $1;`, txHash)
+	})
+
+	scripthash := sha256.Sum256(pkScript)
+	got, err := RetrieveMutilchainScripthashAddress(db.ctx, db.db, scripthash[:], chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != address {
+		t.Errorf("got address %q, want %q", got, address)
+	}
+
+	unknown := sha256.Sum256([]byte{0x6a})
+	got, err = RetrieveMutilchainScripthashAddress(db.ctx, db.db, unknown[:], chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("got address %q for an unknown script", got)
+	}
+}
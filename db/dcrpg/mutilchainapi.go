@@ -0,0 +1,462 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/utils"
+	"github.com/decred/dcrdata/v8/xmr/xmrclient"
+)
+
+// This file implements the data sources of the chain-scoped API of the BTC, LTC
+// and XMR chains. BTC and LTC data come from the node and the vouts table, and
+// XMR data from monerod, since XMR blocks are not stored in the DB.
+
+// mutilchainSubsidyParams returns the subsidy of the first block of the BTC or
+// LTC chain, in satoshis, and the number of blocks between subsidy halvings.
+func (pgb *ChainDB) mutilchainSubsidyParams(chainType string) (int64, int64, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		return mutilchain.BTCStartBlockReward * btcutil.SatoshiPerBitcoin,
+			int64(pgb.btcChainParams.SubsidyReductionInterval), nil
+	case mutilchain.TYPELTC:
+		return mutilchain.LTCStartBlockReward * btcutil.SatoshiPerBitcoin,
+			int64(pgb.ltcChainParams.SubsidyReductionInterval), nil
+	}
+	return 0, 0, fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// halvingSubsidy returns the subsidy of the block at the specified height of a
+// chain whose subsidy halves every interval blocks.
+func halvingSubsidy(height, base, interval int64) int64 {
+	halvings := height / interval
+	if halvings >= 64 {
+		return 0
+	}
+	return base >> uint(halvings)
+}
+
+// halvingSupply returns the total subsidy of the blocks up to and including
+// the specified height of a chain whose subsidy halves every interval blocks.
+// A negative height returns the ultimate supply.
+func halvingSupply(height, base, interval int64) uint64 {
+	var supply uint64
+	for start := int64(0); height < 0 || start <= height; start += interval {
+		subsidy := halvingSubsidy(start, base, interval)
+		if subsidy == 0 {
+			break
+		}
+		end := start + interval - 1
+		if height >= 0 && end > height {
+			end = height
+		}
+		supply += uint64(end-start+1) * uint64(subsidy)
+	}
+	return supply
+}
+
+// MutilchainBlockSummary retrieves the summary of the BTC, LTC or XMR block
+// with the specified hash from the node.
+func (pgb *ChainDB) MutilchainBlockSummary(hash, chainType string) (*apitypes.ChainBlockSummary, error) {
+	if chainType == mutilchain.TYPEXMR {
+		return pgb.xmrBlockSummary(hash)
+	}
+	block, err := pgb.mutilchainBlockVerbose(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return &apitypes.ChainBlockSummary{
+		Height:        block.Height,
+		Hash:          block.Hash,
+		Version:       block.Version,
+		Size:          int64(block.Size),
+		Time:          block.Time,
+		NumTx:         len(block.Tx),
+		Difficulty:    block.Difficulty,
+		Nonce:         uint64(block.Nonce),
+		Confirmations: block.Confirmations,
+		PreviousHash:  block.PreviousHash,
+		NextHash:      block.NextHash,
+	}, nil
+}
+
+// xmrBlockSummary retrieves the summary of the XMR block with the specified
+// hash from monerod. The transaction count includes the miner transaction.
+func (pgb *ChainDB) xmrBlockSummary(hash string) (*apitypes.ChainBlockSummary, error) {
+	header, err := pgb.XmrClient.GetBlockHeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	difficulty, _ := header.Difficulty.Float64()
+	summary := &apitypes.ChainBlockSummary{
+		Height:        int64(header.Height),
+		Hash:          header.Hash,
+		Version:       int32(header.MajorVersion),
+		Size:          int64(header.BlockSize),
+		Time:          int64(header.Timestamp),
+		NumTx:         int(header.NumTxes) + 1,
+		Difficulty:    difficulty,
+		Nonce:         header.Nonce,
+		Confirmations: int64(header.Depth) + 1,
+		PreviousHash:  header.PrevHash,
+	}
+	if header.Depth > 0 {
+		next, err := pgb.XmrClient.GetBlockHeaderByHeight(header.Height + 1)
+		if err != nil {
+			return nil, err
+		}
+		summary.NextHash = next.Hash
+	}
+	return summary, nil
+}
+
+// MutilchainBlockSubsidy retrieves the reward of the BTC, LTC or XMR block with
+// the specified hash. The fees of BTC and LTC blocks are the part of the
+// coinbase outputs exceeding the subsidy, and the subsidy of XMR blocks is the
+// part of the reward exceeding the fees of the block's transactions.
+func (pgb *ChainDB) MutilchainBlockSubsidy(hash, chainType string) (*apitypes.ChainBlockSubsidy, error) {
+	if chainType == mutilchain.TYPEXMR {
+		return pgb.xmrBlockSubsidy(hash)
+	}
+	base, interval, err := pgb.mutilchainSubsidyParams(chainType)
+	if err != nil {
+		return nil, err
+	}
+	block, err := pgb.mutilchainBlockVerbose(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Tx) == 0 {
+		return nil, fmt.Errorf("%s block %s has no coinbase", chainType, hash)
+	}
+	coinbase, err := pgb.mutilchainRawTransactionVerbose(block.Tx[0], chainType)
+	if err != nil {
+		return nil, err
+	}
+	var reward int64
+	for i := range coinbase.Vout {
+		amt, err := btcutil.NewAmount(coinbase.Vout[i].Value)
+		if err != nil {
+			return nil, err
+		}
+		reward += int64(amt)
+	}
+	subsidy := &apitypes.ChainBlockSubsidy{
+		Height:  block.Height,
+		Hash:    block.Hash,
+		Subsidy: halvingSubsidy(block.Height, base, interval),
+		Reward:  reward,
+	}
+	// A miner may claim less than the subsidy and fees, which burns the
+	// difference.
+	if reward > subsidy.Subsidy {
+		subsidy.Fees = reward - subsidy.Subsidy
+	}
+	return subsidy, nil
+}
+
+// xmrBlockSubsidy retrieves the reward of the XMR block with the specified
+// hash from monerod.
+func (pgb *ChainDB) xmrBlockSubsidy(hash string) (*apitypes.ChainBlockSubsidy, error) {
+	header, err := pgb.XmrClient.GetBlockHeaderByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := pgb.XmrClient.GetBlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	var fees int64
+	if len(block.TxHashes) > 0 {
+		txs, err := pgb.XmrClient.GetTransactions(block.TxHashes, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, txJSON := range txs.TxsAsJSON {
+			var tx xmrTxJSON
+			if err = json.Unmarshal([]byte(txJSON), &tx); err != nil {
+				return nil, err
+			}
+			if fee := tx.fee(); fee != nil {
+				fees += *fee
+			}
+		}
+	}
+	return &apitypes.ChainBlockSubsidy{
+		Height:  int64(header.Height),
+		Hash:    header.Hash,
+		Subsidy: int64(header.Reward) - fees,
+		Fees:    fees,
+		Reward:  int64(header.Reward),
+	}, nil
+}
+
+// MutilchainTx retrieves the BTC, LTC or XMR transaction with the specified
+// hash. The previous outputs of BTC and LTC inputs are looked up in the vouts
+// table, falling back to the node.
+func (pgb *ChainDB) MutilchainTx(txid, chainType string) (*apitypes.ChainTx, error) {
+	if chainType == mutilchain.TYPEXMR {
+		return pgb.xmrTx(txid)
+	}
+	tx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+	if err != nil {
+		return nil, err
+	}
+	status, err := pgb.mutilchainTxStatus(tx.BlockHash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	esTx, err := pgb.esploraTx(tx, status, chainType)
+	if err != nil {
+		return nil, err
+	}
+
+	chainTx := &apitypes.ChainTx{
+		TxID:          esTx.Txid,
+		Version:       esTx.Version,
+		Size:          int64(esTx.Size),
+		LockTime:      uint64(esTx.Locktime),
+		BlockHeight:   esTx.Status.BlockHeight,
+		BlockHash:     esTx.Status.BlockHash,
+		Time:          esTx.Status.BlockTime,
+		Confirmations: int64(tx.Confirmations),
+		Vin:           make([]apitypes.ChainTxIn, 0, len(esTx.Vin)),
+		Vout:          make([]apitypes.ChainTxOut, 0, len(esTx.Vout)),
+	}
+	feeKnown := true
+	for i, vin := range esTx.Vin {
+		in := apitypes.ChainTxIn{
+			Index:    uint32(i),
+			Coinbase: vin.IsCoinbase,
+			Sequence: vin.Sequence,
+		}
+		switch {
+		case vin.IsCoinbase:
+			chainTx.Coinbase = true
+		case vin.Prevout != nil:
+			in.PrevTxID, in.PrevVout = vin.Txid, vin.Vout
+			in.Amount = vin.Prevout.Value
+			in.Address = vin.Prevout.ScriptPubKeyAddress
+		default:
+			in.PrevTxID, in.PrevVout = vin.Txid, vin.Vout
+			feeKnown = false
+		}
+		chainTx.Vin = append(chainTx.Vin, in)
+	}
+	for i, vout := range esTx.Vout {
+		out := apitypes.ChainTxOut{
+			Index:    uint32(i),
+			Amount:   vout.Value,
+			Type:     vout.ScriptPubKeyType,
+			PkScript: vout.ScriptPubKey,
+		}
+		if vout.ScriptPubKeyAddress != "" {
+			out.Addresses = []string{vout.ScriptPubKeyAddress}
+		}
+		chainTx.Vout = append(chainTx.Vout, out)
+	}
+	if feeKnown {
+		fee := esTx.Fee
+		chainTx.Fee = &fee
+	}
+	return chainTx, nil
+}
+
+// xmrTxJSON is the part of the JSON representation of an XMR transaction used
+// by the chain-scoped API.
+type xmrTxJSON struct {
+	Version    int32  `json:"version"`
+	UnlockTime uint64 `json:"unlock_time"`
+	Vin        []struct {
+		Gen *struct {
+			Height uint64 `json:"height"`
+		} `json:"gen"`
+		Key *struct {
+			Amount     uint64   `json:"amount"`
+			KeyOffsets []uint64 `json:"key_offsets"`
+			KeyImage   string   `json:"k_image"`
+		} `json:"key"`
+	} `json:"vin"`
+	Vout []struct {
+		Amount uint64 `json:"amount"`
+		Target struct {
+			Key       string `json:"key"`
+			TaggedKey *struct {
+				Key string `json:"key"`
+			} `json:"tagged_key"`
+		} `json:"target"`
+	} `json:"vout"`
+	RctSignatures *struct {
+		TxnFee uint64 `json:"txnFee"`
+	} `json:"rct_signatures"`
+}
+
+// isCoinbase checks whether the transaction is a miner transaction.
+func (tx *xmrTxJSON) isCoinbase() bool {
+	return len(tx.Vin) > 0 && tx.Vin[0].Gen != nil
+}
+
+// fee returns the fee of the transaction, which is zero for miner
+// transactions. The fee of RingCT transactions is explicit, and that of older
+// transactions is the difference of their visible input and output amounts.
+func (tx *xmrTxJSON) fee() *int64 {
+	fee := new(int64)
+	switch {
+	case tx.isCoinbase():
+	case tx.RctSignatures != nil:
+		*fee = int64(tx.RctSignatures.TxnFee)
+	default:
+		for _, vin := range tx.Vin {
+			if vin.Key != nil {
+				*fee += int64(vin.Key.Amount)
+			}
+		}
+		for _, vout := range tx.Vout {
+			*fee -= int64(vout.Amount)
+		}
+	}
+	return fee
+}
+
+// xmrTransaction retrieves the XMR transaction with the specified hash from
+// monerod, with its JSON representation.
+func (pgb *ChainDB) xmrTransaction(txid string) (*xmrclient.TxInfo, error) {
+	res, err := pgb.XmrClient.GetTransactions([]string{txid}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Txs) == 0 {
+		return nil, fmt.Errorf("xmr transaction %s not found", txid)
+	}
+	tx := res.Txs[0]
+	if tx.AsJSON == "" && len(res.TxsAsJSON) > 0 {
+		tx.AsJSON = res.TxsAsJSON[0]
+	}
+	if tx.AsHex == "" && len(res.TxsAsHex) > 0 {
+		tx.AsHex = res.TxsAsHex[0]
+	}
+	return &tx, nil
+}
+
+// xmrTx retrieves the XMR transaction with the specified hash from monerod.
+func (pgb *ChainDB) xmrTx(txid string) (*apitypes.ChainTx, error) {
+	info, err := pgb.xmrTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+	var tx xmrTxJSON
+	if err = json.Unmarshal([]byte(info.AsJSON), &tx); err != nil {
+		return nil, err
+	}
+
+	chainTx := &apitypes.ChainTx{
+		TxID:     info.TxHash,
+		Version:  tx.Version,
+		Size:     int64(len(info.AsHex) / 2),
+		LockTime: tx.UnlockTime,
+		Coinbase: tx.isCoinbase(),
+		Fee:      tx.fee(),
+		Vin:      make([]apitypes.ChainTxIn, 0, len(tx.Vin)),
+		Vout:     make([]apitypes.ChainTxOut, 0, len(tx.Vout)),
+	}
+	if !info.InPool {
+		header, err := pgb.XmrClient.GetBlockHeaderByHeight(uint64(info.BlockHeight))
+		if err != nil {
+			return nil, err
+		}
+		chainTx.BlockHeight = info.BlockHeight
+		chainTx.BlockHash = header.Hash
+		chainTx.Time = int64(info.BlockTimestamp)
+		chainTx.Confirmations = int64(header.Depth) + 1
+	}
+	for i, vin := range tx.Vin {
+		in := apitypes.ChainTxIn{
+			Index:    uint32(i),
+			Coinbase: vin.Gen != nil,
+		}
+		if vin.Key != nil {
+			in.Amount = int64(vin.Key.Amount)
+			in.KeyImage = vin.Key.KeyImage
+			in.RingSize = len(vin.Key.KeyOffsets)
+		}
+		chainTx.Vin = append(chainTx.Vin, in)
+	}
+	for i, vout := range tx.Vout {
+		out := apitypes.ChainTxOut{
+			Index:  uint32(i),
+			Amount: int64(vout.Amount),
+			Key:    vout.Target.Key,
+		}
+		if vout.Target.TaggedKey != nil {
+			out.Key = vout.Target.TaggedKey.Key
+		}
+		chainTx.Vout = append(chainTx.Vout, out)
+	}
+	return chainTx, nil
+}
+
+// GetXMRTxHex returns the hex encoded serialized XMR transaction with the
+// specified hash, or an empty string if it is not found.
+func (pgb *ChainDB) GetXMRTxHex(txid string) string {
+	tx, err := pgb.xmrTransaction(txid)
+	if err != nil {
+		log.Errorf("Get XMR transaction failed: %v", err)
+		return ""
+	}
+	return tx.AsHex
+}
+
+// MutilchainAddressTotals retrieves the number and value of the spent and
+// unspent outputs of a BTC or LTC address.
+func (pgb *ChainDB) MutilchainAddressTotals(address, chainType string) (*apitypes.ChainAddressTotals, error) {
+	if !pgb.IsMutilchainValidAddress(chainType, address) {
+		return nil, fmt.Errorf("invalid %s address %q", chainType, address)
+	}
+	bal, _, err := pgb.MutilchainAddressBalance(address, chainType)
+	if err != nil {
+		return nil, err
+	}
+	if bal == nil {
+		return nil, fmt.Errorf("no balance for %s address %s", chainType, address)
+	}
+	return &apitypes.ChainAddressTotals{
+		Address:       address,
+		NumSpent:      bal.NumSpent,
+		NumUnspent:    bal.NumUnspent,
+		TotalReceived: bal.TotalSpent + bal.TotalUnspent,
+		TotalSpent:    bal.TotalSpent,
+		Balance:       bal.TotalUnspent,
+	}, nil
+}
+
+// MutilchainCoinSupply returns the coin supply of the BTC, LTC or XMR chain at
+// the best block of its node. The supply of BTC and LTC is the total subsidy of
+// the blocks, and the supply of XMR is the estimated emission.
+func (pgb *ChainDB) MutilchainCoinSupply(chainType string) (*apitypes.ChainCoinSupply, error) {
+	height, hash, err := pgb.MutilchainBestBlock(chainType)
+	if err != nil {
+		return nil, err
+	}
+	supply := &apitypes.ChainCoinSupply{
+		Height: height,
+		Hash:   hash,
+	}
+	if chainType == mutilchain.TYPEXMR {
+		supply.Mined = utils.GetCirculatingSupply(uint64(height))
+		return supply, nil
+	}
+	base, interval, err := pgb.mutilchainSubsidyParams(chainType)
+	if err != nil {
+		return nil, err
+	}
+	supply.Mined = halvingSupply(height, base, interval)
+	supply.Ultimate = halvingSupply(-1, base, interval)
+	return supply, nil
+}
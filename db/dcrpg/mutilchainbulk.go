@@ -0,0 +1,594 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/mutilchain/btcrpcutils"
+	"github.com/decred/dcrdata/v8/mutilchain/ltcrpcutils"
+
+	"github.com/lib/pq"
+)
+
+// defaultBulkBatchBlocks is the number of blocks of a batch of the whole-chain
+// import when none is configured.
+const defaultBulkBatchBlocks = 50
+
+// bulkBlock is a block of the whole-chain import with the rows of its
+// transactions, before their row IDs are assigned.
+type bulkBlock struct {
+	block *dbtypes.Block
+	txs   []*dbtypes.Tx
+	vouts [][]*dbtypes.Vout
+	vins  []dbtypes.VinTxPropertyARRAY
+}
+
+// newBulkBlock sets the input, output, fee and sent totals of the block from
+// its transactions.
+func newBulkBlock(block *dbtypes.Block, txs []*dbtypes.Tx, vouts [][]*dbtypes.Vout,
+	vins []dbtypes.VinTxPropertyARRAY) *bulkBlock {
+	var fees, sent int64
+	var numVins, numVouts int
+	for it, tx := range txs {
+		fees += tx.Fees
+		numVins += len(vins[it])
+		numVouts += len(vouts[it])
+		for _, vout := range vouts[it] {
+			sent += int64(vout.Value)
+		}
+	}
+	block.NumVins = uint32(numVins)
+	block.NumVouts = uint32(numVouts)
+	block.Fees = uint64(fees)
+	block.TotalSent = uint64(sent)
+	return &bulkBlock{block: block, txs: txs, vouts: vouts, vins: vins}
+}
+
+// bulkBatch is a batch of fetched blocks, or the error fetching them.
+type bulkBatch struct {
+	blocks []*bulkBlock
+	err    error
+}
+
+// fetchBTCBulkBlock gets a BTC block from the node and extracts its rows.
+func (pgb *ChainDB) fetchBTCBulkBlock(height int64) (*bulkBlock, error) {
+	block, _, err := btcrpcutils.GetBlock(height, pgb.BtcClient)
+	if err != nil {
+		return nil, err
+	}
+	msgBlock := block.MsgBlock()
+	dbBlock := dbtypes.MsgBTCBlockToDBBlock(pgb.BtcClient, msgBlock, pgb.btcChainParams)
+	txs, vouts, vins := dbtypes.ExtractBTCBlockTransactions(pgb.BtcClient, dbBlock, msgBlock, pgb.btcChainParams)
+	return newBulkBlock(dbBlock, txs, vouts, vins), nil
+}
+
+// fetchLTCBulkBlock gets a LTC block from the node and extracts its rows.
+func (pgb *ChainDB) fetchLTCBulkBlock(height int64) (*bulkBlock, error) {
+	block, _, err := ltcrpcutils.GetBlock(height, pgb.LtcClient)
+	if err != nil {
+		return nil, err
+	}
+	msgBlock := block.MsgBlock()
+	dbBlock := dbtypes.MsgLTCBlockToDBBlock(pgb.LtcClient, msgBlock, pgb.ltcChainParams)
+	txs, vouts, vins := dbtypes.ExtractLTCBlockTransactions(pgb.LtcClient, dbBlock, msgBlock, pgb.ltcChainParams)
+	return newBulkBlock(dbBlock, txs, vouts, vins), nil
+}
+
+// fetchBulkBatch fetches the blocks of the heights with the given number of
+// workers, returning them in order of height.
+func fetchBulkBatch(ctx context.Context, heights []int64, workers int,
+	fetch func(height int64) (*bulkBlock, error)) ([]*bulkBlock, error) {
+	blocks := make([]*bulkBlock, len(heights))
+	next := make(chan int)
+	var errOnce sync.Once
+	var fetchErr error
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				block, err := fetch(heights[i])
+				if err != nil {
+					errOnce.Do(func() { fetchErr = fmt.Errorf("GetBlock failed (%d): %w", heights[i], err) })
+					continue
+				}
+				blocks[i] = block
+			}
+		}()
+	}
+	for i := range heights {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	return blocks, ctx.Err()
+}
+
+// reserveRowIDs takes n IDs from the serial sequence of a table.
+func reserveRowIDs(ctx context.Context, dbtx *sql.Tx, table string, n int) ([]uint64, error) {
+	ids := make([]uint64, 0, n)
+	if n == 0 {
+		return ids, nil
+	}
+	rows, err := dbtx.QueryContext(ctx, mutilchainquery.MakeReserveRowIDs(table), n)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+	for rows.Next() {
+		var id uint64
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// copyRows streams rows to a table with COPY. The rows function calls add
+// once per row with the values of the columns.
+func copyRows(ctx context.Context, dbtx *sql.Tx, table string, columns []string,
+	rows func(add func(values ...interface{}) error) error) error {
+	stmt, err := dbtx.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return err
+	}
+	err = rows(func(values ...interface{}) error {
+		_, err := stmt.ExecContext(ctx, values...)
+		return err
+	})
+	if err == nil {
+		// Flush the buffered rows.
+		_, err = stmt.ExecContext(ctx)
+	}
+	if err != nil {
+		_ = stmt.Close()
+		return err
+	}
+	return stmt.Close()
+}
+
+// storeBulkBatch stores the blocks of a batch with one DB transaction. The
+// rows of the transactions, inputs, outputs and addresses are assigned their
+// IDs in bulk and copied to staging tables, from which they are moved to the
+// chain's tables. The spending fields of the address rows of the outpoints
+// spent in the batch are then set with a single UPDATE, and the blocks are
+// inserted as synced along with the checkpoint of the chain. When
+// checkConflict is set, the rows left by an earlier store of the transactions
+// are deleted first. The numbers of transactions, inputs and outputs stored
+// are returned.
+func (pgb *ChainDB) storeBulkBatch(chainType string, blocks []*bulkBlock, checkConflict bool) (numTxs, numVins, numVouts int64, err error) {
+	ctx := pgb.ctx
+	dbtx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("unable to begin database transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = dbtx.Rollback()
+		}
+	}()
+
+	var numAddrs int
+	txHashes := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		for it, tx := range b.txs {
+			txHashes = append(txHashes, tx.TxID)
+			numVins += int64(len(b.vins[it]))
+			numVouts += int64(len(b.vouts[it]))
+			for _, vout := range b.vouts[it] {
+				numAddrs += len(vout.ScriptPubKeyData.Addresses)
+			}
+		}
+	}
+	numTxs = int64(len(txHashes))
+
+	if checkConflict {
+		for _, stmt := range mutilchainquery.MakeDeleteBulkConflicts(chainType) {
+			if _, err = dbtx.ExecContext(ctx, stmt, pq.Array(txHashes)); err != nil {
+				return
+			}
+		}
+	}
+
+	txIDs, err := reserveRowIDs(ctx, dbtx, chainType+"transactions", int(numTxs))
+	if err != nil {
+		return
+	}
+	vinIDs, err := reserveRowIDs(ctx, dbtx, chainType+"vins_all", int(numVins))
+	if err != nil {
+		return
+	}
+	voutIDs, err := reserveRowIDs(ctx, dbtx, chainType+"vouts_all", int(numVouts))
+	if err != nil {
+		return
+	}
+	addrIDs, err := reserveRowIDs(ctx, dbtx, chainType+"addresses", numAddrs)
+	if err != nil {
+		return
+	}
+
+	// Assign the IDs of the rows of each transaction.
+	var txIdx, vinIdx, voutIdx int
+	txDbIDs := make(map[*dbtypes.Tx]uint64, numTxs)
+	for _, b := range blocks {
+		for it, tx := range b.txs {
+			txDbIDs[tx] = txIDs[txIdx]
+			txIdx++
+			tx.VinDbIds = vinIDs[vinIdx : vinIdx+len(b.vins[it])]
+			vinIdx += len(b.vins[it])
+			tx.VoutDbIds = voutIDs[voutIdx : voutIdx+len(b.vouts[it])]
+			voutIdx += len(b.vouts[it])
+		}
+	}
+
+	for _, stmt := range mutilchainquery.MakeCreateBulkStages(chainType) {
+		if _, err = dbtx.ExecContext(ctx, stmt); err != nil {
+			return
+		}
+	}
+
+	err = copyRows(ctx, dbtx, chainType+"bulk_transactions", []string{"id", "block_hash",
+		"block_height", "block_time", "time", "tx_type", "version", "tree", "tx_hash",
+		"block_index", "lock_time", "expiry", "size", "spent", "sent", "fees", "num_vin",
+		"vins", "vin_db_ids", "num_vout", "vouts", "vout_db_ids"},
+		func(add func(...interface{}) error) error {
+			for _, b := range blocks {
+				for _, tx := range b.txs {
+					if err := add(txDbIDs[tx], tx.BlockHash, tx.BlockHeight, tx.BlockTime.UNIX(),
+						tx.Time.UNIX(), tx.TxType, tx.Version, tx.Tree, tx.TxID, tx.BlockIndex,
+						0, tx.Expiry, tx.Size, tx.Spent, tx.Sent, tx.Fees, tx.NumVin, "",
+						dbtypes.UInt64Array(tx.VinDbIds), tx.NumVout, pq.Array(tx.Vouts),
+						dbtypes.UInt64Array(tx.VoutDbIds)); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return
+	}
+
+	err = copyRows(ctx, dbtx, chainType+"bulk_vins", []string{"id", "tx_hash", "tx_index",
+		"tx_tree", "prev_tx_hash", "prev_tx_index", "prev_tx_tree", "value_in", "tx_row_id"},
+		func(add func(...interface{}) error) error {
+			for _, b := range blocks {
+				for it, tx := range b.txs {
+					for iv := range b.vins[it] {
+						vin := &b.vins[it][iv]
+						if err := add(tx.VinDbIds[iv], vin.TxID, vin.TxIndex, vin.TxTree,
+							vin.PrevTxHash, vin.PrevTxIndex, vin.PrevTxTree, vin.ValueIn,
+							txDbIDs[tx]); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return
+	}
+
+	err = copyRows(ctx, dbtx, chainType+"bulk_vouts", []string{"id", "tx_hash", "tx_index",
+		"tx_tree", "value", "version", "pkscript", "script_req_sigs", "script_type",
+		"script_addresses"},
+		func(add func(...interface{}) error) error {
+			for _, b := range blocks {
+				for it, tx := range b.txs {
+					for iv, vout := range b.vouts[it] {
+						if err := add(tx.VoutDbIds[iv], vout.TxHash, vout.TxIndex, vout.TxTree,
+							vout.Value, vout.Version, vout.ScriptPubKey,
+							vout.ScriptPubKeyData.ReqSigs, vout.ScriptPubKeyData.Type,
+							pq.Array(vout.ScriptPubKeyData.Addresses)); err != nil {
+							return err
+						}
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return
+	}
+
+	var addrIdx int
+	err = copyRows(ctx, dbtx, chainType+"bulk_addresses", []string{"id", "address",
+		"funding_tx_row_id", "funding_tx_hash", "funding_tx_vout_index", "vout_row_id", "value"},
+		func(add func(...interface{}) error) error {
+			for _, b := range blocks {
+				for it, tx := range b.txs {
+					for iv, vout := range b.vouts[it] {
+						for _, addr := range vout.ScriptPubKeyData.Addresses {
+							if err := add(addrIDs[addrIdx], addr, txDbIDs[tx], vout.TxHash,
+								vout.TxIndex, tx.VoutDbIds[iv], vout.Value); err != nil {
+								return err
+							}
+							addrIdx++
+						}
+					}
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return
+	}
+
+	for _, stmt := range mutilchainquery.MakeMoveBulkStages(chainType) {
+		if _, err = dbtx.ExecContext(ctx, stmt); err != nil {
+			return
+		}
+	}
+	if _, err = dbtx.ExecContext(ctx, mutilchainquery.MakeSetBulkAddressesSpending(chainType)); err != nil {
+		return
+	}
+
+	var checkpoint int64
+	for _, b := range blocks {
+		dbBlock := b.block
+		_, err = dbtx.ExecContext(ctx, mutilchainquery.MakeInsertBlockAllSynced(chainType),
+			dbBlock.Hash, dbBlock.Height, dbBlock.Size, true, dbBlock.Version,
+			dbBlock.NumTx, dbBlock.Time.UNIX(), dbBlock.Nonce, dbBlock.PoolSize, dbBlock.Bits,
+			dbBlock.Difficulty, dbBlock.PreviousHash, dbBlock.NumVins, dbBlock.NumVouts,
+			dbBlock.Fees, dbBlock.TotalSent)
+		if err != nil {
+			return
+		}
+		if int64(dbBlock.Height) > checkpoint {
+			checkpoint = int64(dbBlock.Height)
+		}
+	}
+	if _, err = dbtx.ExecContext(ctx, mutilchainquery.UpsertSyncCheckpoint, chainType, checkpoint); err != nil {
+		return
+	}
+
+	err = dbtx.Commit()
+	return
+}
+
+// retrieveSyncCheckpoint returns the height of the last batch of the
+// whole-chain import of a chain, or -1 if none was stored.
+func (pgb *ChainDB) retrieveSyncCheckpoint(chainType string) (int64, error) {
+	var height int64
+	err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.SelectSyncCheckpoint, chainType).Scan(&height)
+	if err == sql.ErrNoRows {
+		return -1, nil
+	}
+	return height, err
+}
+
+// syncWholeChainBulk imports the blocks of a chain up to bestHeight that are
+// not yet synced to its whole-chain tables. The blocks are fetched in batches
+// by the given number of workers while the previous batch is stored, each
+// batch with its own DB transaction and checkpoint, so an interrupted import
+// resumes after the last stored batch.
+func (pgb *ChainDB) syncWholeChainBulk(chainType string, bestHeight int64, workers int,
+	fetch func(height int64) (*bulkBlock, error)) {
+	chain := strings.ToUpper(chainType)
+
+	checkpoint, err := pgb.retrieveSyncCheckpoint(chainType)
+	if err != nil {
+		log.Errorf("%s: Retrieve sync checkpoint failed: %v", chain, err)
+		return
+	}
+	rows, err := pgb.db.QueryContext(pgb.ctx, mutilchainquery.MakeSelectRemainingNotSyncedHeightsFrom(chainType),
+		bestHeight, checkpoint+1)
+	if err != nil {
+		log.Errorf("%s: Query remaining syncing blocks height list failed: %v", chain, err)
+		return
+	}
+	remainingHeights, err := getRemainingHeightsFromSqlRows(rows)
+	if err != nil {
+		log.Errorf("%s: Get remaining blocks height list failed: %v", chain, err)
+		return
+	}
+	if len(remainingHeights) == 0 {
+		log.Infof("%s: No more blocks to synchronize with the whole daemon", chain)
+		pgb.setWholeChainSynced(chainType)
+		return
+	}
+	if checkpoint >= 0 {
+		log.Infof("%s: Resuming the whole chain sync after the checkpoint at height %d", chain, checkpoint)
+	}
+	log.Infof("%s: Start sync for %d blocks. Minimum height: %d, Maximum height: %d", chain,
+		len(remainingHeights), remainingHeights[0], remainingHeights[len(remainingHeights)-1])
+
+	// The blocks of an interrupted store of a single block are stored again.
+	if _, err = pgb.db.ExecContext(pgb.ctx, mutilchainquery.MakeDeleteUnsyncedBlocksAll(chainType)); err != nil {
+		log.Errorf("%s: Delete unsynced blocks failed: %v", chain, err)
+		return
+	}
+
+	reindexing := int64(len(remainingHeights)) > bestHeight/50
+	checkConflict := !reindexing
+	if reindexing {
+		log.Infof("%s: Large bulk load: Removing indexes", chain)
+		// The lookups by scripthash wait for the indexes.
+		if flag := pgb.wholeChainSyncedFlag(chainType); flag != nil {
+			flag.Store(false)
+		}
+		if err = pgb.DeindexMutilchainWholeTable(chainType); err != nil &&
+			!strings.Contains(err.Error(), "does not exist") &&
+			!strings.Contains(err.Error(), "不存在") {
+			log.Errorf("%s: Deindex for multichain whole table: %v", chain, err)
+			return
+		}
+	}
+
+	batchSize := pgb.bulkBatchBlocks
+	if batchSize <= 0 {
+		batchSize = defaultBulkBatchBlocks
+	}
+
+	ctx, cancel := context.WithCancel(pgb.ctx)
+	defer cancel()
+
+	// Fetch the next batch while the current one is stored.
+	batches := make(chan bulkBatch, 1)
+	go func() {
+		defer close(batches)
+		for start := 0; start < len(remainingHeights); start += batchSize {
+			end := start + batchSize
+			if end > len(remainingHeights) {
+				end = len(remainingHeights)
+			}
+			blocks, err := fetchBulkBatch(ctx, remainingHeights[start:end], workers, fetch)
+			select {
+			case batches <- bulkBatch{blocks, err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	tickTime := 20 * time.Second
+	ticker := time.NewTicker(tickTime)
+	defer ticker.Stop()
+	startTime := time.Now()
+	var totalBlocks, totalTxs, totalVins, totalVouts int64
+	var lastBlocks, lastTxs, lastVins, lastVouts int64
+	var nextLog int
+	for batch := range batches {
+		if batch.err != nil {
+			log.Errorf("%s: sync aborted due to error: %v", chain, batch.err)
+			return
+		}
+		first := batch.blocks[0].block.Height
+		if int(totalBlocks) >= nextLog {
+			log.Infof("%s: Processing blocks %d to %d...", chain, first,
+				batch.blocks[len(batch.blocks)-1].block.Height)
+			nextLog += btcRescanLogBlockChunk
+		}
+		numTxs, numVins, numVouts, err := pgb.storeBulkBatch(chainType, batch.blocks, checkConflict)
+		if err != nil {
+			log.Errorf("%s: sync aborted due to error: batch from height %d: %v", chain, first, err)
+			return
+		}
+		totalBlocks += int64(len(batch.blocks))
+		totalTxs += numTxs
+		totalVins += numVins
+		totalVouts += numVouts
+
+		select {
+		case <-ticker.C:
+			blocksPerSec := float64(totalBlocks-lastBlocks) / tickTime.Seconds()
+			txPerSec := float64(totalTxs-lastTxs) / tickTime.Seconds()
+			vinsPerSec := float64(totalVins-lastVins) / tickTime.Seconds()
+			voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
+			log.Infof("%s: (%.3f blk/s, %.3f tx/s, %.3f vin/s, %.3f vout/s)", chain,
+				blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
+			recordSyncRate(chainType, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
+			lastBlocks, lastTxs = totalBlocks, totalTxs
+			lastVins, lastVouts = totalVins, totalVouts
+		default:
+		}
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	if totalElapsed := time.Since(startTime).Seconds(); totalElapsed >= 1 {
+		log.Infof("%s: Avg. speed: %d tx/s, %d vout/s", chain,
+			totalTxs/int64(totalElapsed), totalVouts/int64(totalElapsed))
+	}
+
+	if reindexing {
+		if err := pgb.IndexMutilchainWholeTable(chainType); err != nil {
+			log.Errorf("%s: Re-index failed: %v", chain, err)
+			return
+		}
+	}
+
+	log.Infof("%s: Finish sync for %d blocks. Minimum height: %d, Maximum height: %d "+
+		"(%d tx total, %d vin total, %d vout total)", chain, totalBlocks,
+		remainingHeights[0], remainingHeights[len(remainingHeights)-1], totalTxs, totalVins, totalVouts)
+	pgb.setWholeChainSynced(chainType)
+}
+
+// wholeChainSyncedFlag returns the flag of a BTC or LTC chain that is set once
+// its whole-chain import reaches the best block, or nil for other chains.
+func (pgb *ChainDB) wholeChainSyncedFlag(chainType string) *atomic.Bool {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		return &pgb.btcWholeSynced
+	case mutilchain.TYPELTC:
+		return &pgb.ltcWholeSynced
+	}
+	return nil
+}
+
+// setWholeChainSynced marks the whole-chain tables of a chain as synced, after
+// creating the scripthash index of the outputs if a previous version of
+// dcrdata imported the chain without it.
+func (pgb *ChainDB) setWholeChainSynced(chainType string) {
+	flag := pgb.wholeChainSyncedFlag(chainType)
+	if flag == nil || flag.Load() {
+		return
+	}
+	_, err := pgb.db.ExecContext(pgb.ctx, mutilchainquery.MakeIndexVoutAllTableOnScripthash(chainType))
+	if err != nil {
+		log.Errorf("%s: Unable to index the outputs on scripthash: %v", strings.ToUpper(chainType), err)
+		return
+	}
+	flag.Store(true)
+}
+
+// MutilchainWholeChainSynced reports whether the whole-chain tables of a BTC
+// or LTC chain have been synced to the best block, which the lookups of the
+// outputs of the whole chain, such as those by scripthash, require. The light
+// tables only have the outputs of the recent blocks.
+func (pgb *ChainDB) MutilchainWholeChainSynced(chainType string) bool {
+	flag := pgb.wholeChainSyncedFlag(chainType)
+	return flag != nil && flag.Load()
+}
+
+func (pgb *ChainDB) SyncBTCWholeChain() {
+	pgb.btcWholeSyncMtx.Lock()
+	pgb.syncWholeChainBulk(mutilchain.TYPEBTC, pgb.BtcBestBlock.Height, 3, pgb.fetchBTCBulkBlock)
+	pgb.btcWholeSyncMtx.Unlock()
+	// The coin age and the UTXO set catch up outside of the lock, not to hold
+	// back the sync of the new blocks.
+	if err := pgb.SyncMultichainCoinAge(mutilchain.TYPEBTC); err != nil {
+		log.Errorf("BTC: coin age sync failed: %v", err)
+	}
+	if err := pgb.SyncUtxoSet(mutilchain.TYPEBTC); err != nil {
+		log.Errorf("BTC: UTXO set sync failed: %v", err)
+	}
+}
+
+func (pgb *ChainDB) SyncLTCWholeChain() {
+	pgb.ltcWholeSyncMtx.Lock()
+	pgb.syncWholeChainBulk(mutilchain.TYPELTC, pgb.LtcBestBlock.Height, 2, pgb.fetchLTCBulkBlock)
+	pgb.ltcWholeSyncMtx.Unlock()
+	// The coin age and the UTXO set catch up outside of the lock, not to hold
+	// back the sync of the new blocks.
+	if err := pgb.SyncMultichainCoinAge(mutilchain.TYPELTC); err != nil {
+		log.Errorf("LTC: coin age sync failed: %v", err)
+	}
+	if err := pgb.SyncUtxoSet(mutilchain.TYPELTC); err != nil {
+		log.Errorf("LTC: UTXO set sync failed: %v", err)
+	}
+}
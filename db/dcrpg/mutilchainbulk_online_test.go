@@ -0,0 +1,210 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/lib/pq"
+)
+
+// bulkTestTx is a transaction of a test block of the whole-chain import, with
+// its inputs spending the given outpoints and an output to each address.
+type bulkTestTx struct {
+	hash    string
+	spends  []dbtypes.VinTxProperty
+	addrs   []string
+	outputs []uint64
+}
+
+func newBulkTestBlock(height int64, txs ...bulkTestTx) *bulkBlock {
+	blockHash := fmt.Sprintf("bulktest-block-%d", height)
+	blockTime := dbtypes.NewTimeDef(time.Unix(1700000000+height*600, 0))
+	var dbTxs []*dbtypes.Tx
+	var vouts [][]*dbtypes.Vout
+	var vins []dbtypes.VinTxPropertyARRAY
+	for i, t := range txs {
+		dbTxs = append(dbTxs, &dbtypes.Tx{
+			BlockHash:   blockHash,
+			BlockHeight: height,
+			BlockTime:   blockTime,
+			Time:        blockTime,
+			TxID:        t.hash,
+			BlockIndex:  uint32(i),
+			NumVin:      uint32(len(t.spends)),
+			NumVout:     uint32(len(t.addrs)),
+		})
+		var txVins dbtypes.VinTxPropertyARRAY
+		for iv, prev := range t.spends {
+			prev.TxID, prev.TxIndex = t.hash, uint32(iv)
+			txVins = append(txVins, prev)
+		}
+		vins = append(vins, txVins)
+		var txVouts []*dbtypes.Vout
+		for iv, addr := range t.addrs {
+			txVouts = append(txVouts, &dbtypes.Vout{
+				TxHash:       t.hash,
+				TxIndex:      uint32(iv),
+				Value:        t.outputs[iv],
+				ScriptPubKey: []byte{0x76, 0xa9, byte(iv)},
+				ScriptPubKeyData: dbtypes.ScriptPubKeyData{
+					ReqSigs:   1,
+					Type:      dbtypes.SCPubKeyHash,
+					Addresses: []string{addr},
+				},
+			})
+		}
+		vouts = append(vouts, txVouts)
+	}
+	block := &dbtypes.Block{
+		Hash:   blockHash,
+		Height: uint32(height),
+		NumTx:  uint32(len(txs)),
+		Time:   blockTime,
+	}
+	return newBulkBlock(block, dbTxs, vouts, vins)
+}
+
+func TestStoreBulkBatch(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	createMultichainTestTables(t, chainType)
+
+	const h1, h2 = 990001, 990002
+	txA, txB, txC := "bulktest-tx-a", "bulktest-tx-b", "bulktest-tx-c"
+	txHashes := []string{txA, txB, txC}
+
+	// The checkpoint of the chain is restored after the test.
+	prevCheckpoint, err := db.retrieveSyncCheckpoint(chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cleanUp := func() {
+		for _, stmt := range mutilchainquery.MakeDeleteBulkConflicts(chainType) {
+			_, _ = db.db.Exec(stmt, pq.Array(txHashes))
+		}
+		_, _ = db.db.Exec(`DELETE FROM btcblocks_all WHERE height IN ($1, $2);`, h1, h2)
+		if prevCheckpoint < 0 {
+			_, _ = db.db.Exec(`DELETE FROM multichain_sync_checkpoints WHERE chain_type = $1;`, chainType)
+		} else {
+			_, _ = db.db.Exec(`INSERT INTO multichain_sync_checkpoints (chain_type, height) VALUES ($1, $2)
+				ON CONFLICT (chain_type) DO UPDATE SET height = EXCLUDED.height;`, chainType, prevCheckpoint)
+		}
+	}
+	cleanUp()
+	t.Cleanup(cleanUp)
+	_, err = db.db.Exec(`DELETE FROM multichain_sync_checkpoints WHERE chain_type = $1;`, chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first batch is the block of txA, which has no inputs.
+	batch1 := []*bulkBlock{newBulkTestBlock(h1,
+		bulkTestTx{hash: txA, addrs: []string{"bulktest-x", "bulktest-y"}, outputs: []uint64{5000, 100}})}
+	numTxs, numVins, numVouts, err := db.storeBulkBatch(chainType, batch1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numTxs != 1 || numVins != 0 || numVouts != 2 {
+		t.Errorf("stored %d txs, %d vins and %d vouts, expecting 1, 0 and 2", numTxs, numVins, numVouts)
+	}
+
+	// The sync resumes after the checkpoint of the first batch.
+	checkpoint, err := db.retrieveSyncCheckpoint(chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint != h1 {
+		t.Fatalf("checkpoint %d, expecting %d", checkpoint, h1)
+	}
+	remaining := func() []int64 {
+		t.Helper()
+		rows, err := db.db.Query(mutilchainquery.MakeSelectRemainingNotSyncedHeightsFrom(chainType), h2, checkpoint+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		heights, err := getRemainingHeightsFromSqlRows(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return heights
+	}
+	if heights := remaining(); len(heights) != 1 || heights[0] != h2 {
+		t.Fatalf("remaining heights %v after the checkpoint, expecting [%d]", heights, h2)
+	}
+
+	// The second batch spends the output of txA to X with txB, and the output
+	// of txB with txC in the same batch.
+	batch2 := []*bulkBlock{newBulkTestBlock(h2,
+		bulkTestTx{hash: txB, spends: []dbtypes.VinTxProperty{{PrevTxHash: txA, PrevTxIndex: 0, ValueIn: 5000}},
+			addrs: []string{"bulktest-z"}, outputs: []uint64{4000}},
+		bulkTestTx{hash: txC, spends: []dbtypes.VinTxProperty{{PrevTxHash: txB, PrevTxIndex: 0, ValueIn: 4000}},
+			addrs: []string{"bulktest-x"}, outputs: []uint64{3000}})}
+	if _, _, _, err = db.storeBulkBatch(chainType, batch2, false); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint, err = db.retrieveSyncCheckpoint(chainType); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint != h2 {
+		t.Fatalf("checkpoint %d, expecting %d", checkpoint, h2)
+	}
+	if heights := remaining(); len(heights) != 0 {
+		t.Errorf("remaining heights %v after the last batch", heights)
+	}
+
+	// The address rows are linked to the inputs spending them, and to the IDs
+	// of the copied rows.
+	spending := func(fundingTx string, voutIndex int) (spendingTx sql.NullString, vinRowID sql.NullInt64) {
+		t.Helper()
+		err := db.db.QueryRow(`SELECT spending_tx_hash, vin_row_id FROM btcaddresses
+			WHERE funding_tx_hash = $1 AND funding_tx_vout_index = $2;`, fundingTx, voutIndex).
+			Scan(&spendingTx, &vinRowID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	for _, tt := range []struct {
+		fundingTx string
+		vout      int
+		spentBy   string
+	}{
+		{txA, 0, txB},
+		{txA, 1, ""},
+		{txB, 0, txC},
+		{txC, 0, ""},
+	} {
+		spendingTx, vinRowID := spending(tt.fundingTx, tt.vout)
+		if spendingTx.String != tt.spentBy {
+			t.Errorf("%s:%d spent by %q, expecting %q", tt.fundingTx, tt.vout, spendingTx.String, tt.spentBy)
+			continue
+		}
+		if tt.spentBy == "" {
+			continue
+		}
+		var vinTx string
+		if err = db.db.QueryRow(`SELECT tx_hash FROM btcvins_all WHERE id = $1;`, vinRowID.Int64).Scan(&vinTx); err != nil {
+			t.Fatal(err)
+		}
+		if vinTx != tt.spentBy {
+			t.Errorf("%s:%d links the input of %s, expecting %s", tt.fundingTx, tt.vout, vinTx, tt.spentBy)
+		}
+	}
+
+	var numSynced int
+	var sent int64
+	err = db.db.QueryRow(`SELECT COUNT(*), SUM(total_sent) FROM btcblocks_all
+		WHERE height IN ($1, $2) AND synced;`, h1, h2).Scan(&numSynced, &sent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if numSynced != 2 || sent != 5100+4000+3000 {
+		t.Errorf("%d synced blocks with %d sent, expecting 2 with %d", numSynced, sent, 5100+4000+3000)
+	}
+}
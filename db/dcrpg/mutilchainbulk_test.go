@@ -0,0 +1,139 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+func testBulkBlock(height int64) (*bulkBlock, error) {
+	return &bulkBlock{block: &dbtypes.Block{Height: uint32(height)}}, nil
+}
+
+func TestFetchBulkBatchOrder(t *testing.T) {
+	heights := make([]int64, 40)
+	for i := range heights {
+		heights[i] = int64(1000 + i)
+	}
+	// The blocks are fetched out of order by the workers.
+	blocks, err := fetchBulkBatch(context.Background(), heights, 8, func(height int64) (*bulkBlock, error) {
+		time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+		return testBulkBlock(height)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != len(heights) {
+		t.Fatalf("got %d blocks, expecting %d", len(blocks), len(heights))
+	}
+	for i, b := range blocks {
+		if int64(b.block.Height) != heights[i] {
+			t.Errorf("block %d has height %d, expecting %d", i, b.block.Height, heights[i])
+		}
+	}
+}
+
+func TestFetchBulkBatchFirstError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	var fetched atomic.Int32
+	// With a single worker, the heights are fetched in order, and the error of
+	// the first failed height is returned.
+	_, err := fetchBulkBatch(context.Background(), []int64{1, 2, 3, 4, 5}, 1, func(height int64) (*bulkBlock, error) {
+		fetched.Add(1)
+		switch height {
+		case 2:
+			return nil, errFetch
+		case 4:
+			return nil, errors.New("later error")
+		}
+		return testBulkBlock(height)
+	})
+	if !errors.Is(err, errFetch) {
+		t.Fatalf("got error %v, expecting %v", err, errFetch)
+	}
+	if want := "GetBlock failed (2): fetch failed"; err.Error() != want {
+		t.Errorf("got error %q, expecting %q", err, want)
+	}
+	if fetched.Load() != 5 {
+		t.Errorf("fetched %d blocks, expecting 5", fetched.Load())
+	}
+}
+
+func TestFetchBulkBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	heights := make([]int64, 20)
+	for i := range heights {
+		heights[i] = int64(i)
+	}
+	var fetched atomic.Int32
+	_, err := fetchBulkBatch(ctx, heights, 1, func(height int64) (*bulkBlock, error) {
+		fetched.Add(1)
+		if height == 2 {
+			cancel()
+		}
+		return testBulkBlock(height)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, expecting %v", err, context.Canceled)
+	}
+	// No height is dispatched once the context is canceled, but the one
+	// already waiting for the worker.
+	if n := fetched.Load(); n > 4 {
+		t.Errorf("fetched %d blocks after the cancellation at the third", n)
+	}
+
+	// A batch with a canceled context fetches nothing.
+	fetched.Store(0)
+	if _, err = fetchBulkBatch(ctx, heights, 4, func(height int64) (*bulkBlock, error) {
+		fetched.Add(1)
+		return testBulkBlock(height)
+	}); !errors.Is(err, context.Canceled) || fetched.Load() != 0 {
+		t.Errorf("got error %v after %d fetches, expecting %v and none", err, fetched.Load(), context.Canceled)
+	}
+}
+
+func TestNewBulkBlock(t *testing.T) {
+	txs := []*dbtypes.Tx{{Fees: 0}, {Fees: 250}, {Fees: 1000}}
+	vouts := [][]*dbtypes.Vout{
+		{{Value: 625000000}, {Value: 0}},
+		{{Value: 5000}},
+		{{Value: 100000}, {Value: 20000}, {Value: 3}},
+	}
+	vins := []dbtypes.VinTxPropertyARRAY{
+		{{}},
+		{{ValueIn: 5250}},
+		{{ValueIn: 60000}, {ValueIn: 61003}},
+	}
+	block := &dbtypes.Block{Height: 7, NumTx: 3}
+	b := newBulkBlock(block, txs, vouts, vins)
+	if b.block != block || len(b.txs) != 3 || len(b.vouts) != 3 || len(b.vins) != 3 {
+		t.Fatalf("unexpected bulk block %+v", b)
+	}
+	if block.NumVins != 4 {
+		t.Errorf("NumVins = %d, expecting 4", block.NumVins)
+	}
+	if block.NumVouts != 6 {
+		t.Errorf("NumVouts = %d, expecting 6", block.NumVouts)
+	}
+	if block.Fees != 1250 {
+		t.Errorf("Fees = %d, expecting 1250", block.Fees)
+	}
+	if block.TotalSent != 625125003 {
+		t.Errorf("TotalSent = %d, expecting 625125003", block.TotalSent)
+	}
+
+	// An empty block has zero totals.
+	empty := newBulkBlock(&dbtypes.Block{NumVins: 9, Fees: 9}, nil, nil, nil)
+	if empty.block.NumVins != 0 || empty.block.NumVouts != 0 || empty.block.Fees != 0 || empty.block.TotalSent != 0 {
+		t.Errorf("unexpected totals of an empty block %+v", empty.block)
+	}
+}
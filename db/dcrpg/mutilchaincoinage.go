@@ -0,0 +1,269 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// coinAgeReorgDepth is the number of blocks below the coin age tip whose
+// changes to the UTXO age distribution are kept. A deeper reorg rebuilds the
+// coin age tables of the chain.
+const coinAgeReorgDepth = 1000
+
+// coinAgeLogInterval is the number of blocks between the progress messages of
+// the coin age sync.
+const coinAgeLogInterval = 10000
+
+func (pgb *ChainDB) coinAgeSyncMtx(chainType string) *sync.Mutex {
+	if chainType == mutilchain.TYPELTC {
+		return &pgb.ltcCoinAgeSyncMtx
+	}
+	return &pgb.btcCoinAgeSyncMtx
+}
+
+// SyncMultichainCoinAge brings the coin age tables of the BTC or LTC chain up
+// to date with the synced whole-chain blocks, one block at a time from the coin
+// age tip. The blocks of the tables that are no longer in the main chain of the
+// node are rewound first. It returns at once if a sync of the chain is already
+// running, since that sync picks up the new blocks.
+func (pgb *ChainDB) SyncMultichainCoinAge(chainType string) error {
+	if chainType != mutilchain.TYPEBTC && chainType != mutilchain.TYPELTC {
+		return fmt.Errorf("no coin age for the chain type %q", chainType)
+	}
+	mtx := pgb.coinAgeSyncMtx(chainType)
+	if !mtx.TryLock() {
+		return nil
+	}
+	defer mtx.Unlock()
+
+	chain := strings.ToUpper(chainType)
+	height, hash, err := pgb.rewindMultichainCoinAge(chainType)
+	if err != nil {
+		return err
+	}
+
+	start, t := height, time.Now()
+	for {
+		if err = pgb.ctx.Err(); err != nil {
+			return err
+		}
+		next, blockTime, found, err := pgb.nextMultichainSyncedBlock(chainType, height+1, hash)
+		if err != nil {
+			return err
+		}
+		if !found {
+			break
+		}
+		if err = pgb.storeMultichainCoinAge(chainType, height+1, next, blockTime); err != nil {
+			return fmt.Errorf("coin age of block %d: %w", height+1, err)
+		}
+		height, hash = height+1, next
+		if (height-start)%coinAgeLogInterval == 0 {
+			log.Infof("%s: Coin age synced to block %d", chain, height)
+		}
+	}
+	if height > start {
+		log.Debugf("%s: Coin age synced from block %d to %d in %v", chain, start+1, height,
+			time.Since(t))
+	}
+	return nil
+}
+
+// rewindMultichainCoinAge returns the height and hash of the tip of the coin
+// age tables of a chain, after rewinding the blocks that are not in the main
+// chain of the node. The height is -1 if the tables are empty.
+func (pgb *ChainDB) rewindMultichainCoinAge(chainType string) (int64, string, error) {
+	var tip int64
+	var hash string
+	err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectCoinAgeTip(chainType)).Scan(&tip, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	mainHash := func(height int64) (string, error) {
+		return pgb.MutilchainBlockHashAtHeight(height, chainType)
+	}
+	storedHash := func(height int64) (string, error) {
+		var hash string
+		err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectCoinAgeHash(chainType), height).Scan(&hash)
+		return hash, err
+	}
+	height, hash, err := coinAgeForkPoint(tip, hash, mainHash, storedHash)
+	if err != nil {
+		return 0, "", err
+	}
+	if height == tip {
+		return height, hash, nil
+	}
+
+	chain := strings.ToUpper(chainType)
+	var minHeight int64
+	err = pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectUtxoAgeDeltasMinHeight(chainType)).Scan(&minHeight)
+	if err != nil {
+		return 0, "", err
+	}
+	if !coinAgeRevertible(height, minHeight) {
+		log.Warnf("%s: Coin age reorg from block %d deeper than the kept changes. "+
+			"Rebuilding the coin age tables.", chain, tip)
+		_, err = pgb.db.ExecContext(pgb.ctx, mutilchainquery.MakeTruncateCoinAgeTables(chainType))
+		return -1, "", err
+	}
+
+	log.Infof("%s: Rewinding the coin age from block %d to %d", chain, tip, height)
+	dbTx, err := pgb.db.BeginTx(pgb.ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	stmts := append([]string{mutilchainquery.MakeRevertUtxoAgeDeltas(chainType)},
+		mutilchainquery.MakeRewindCoinAgeStmts(chainType)...)
+	for _, stmt := range stmts {
+		if _, err = dbTx.Exec(stmt, height); err != nil {
+			_ = dbTx.Rollback()
+			return 0, "", err
+		}
+	}
+	if _, err = dbTx.Exec(mutilchainquery.MakeDeleteEmptyUtxoAge(chainType)); err != nil {
+		_ = dbTx.Rollback()
+		return 0, "", err
+	}
+	return height, hash, dbTx.Commit()
+}
+
+// coinAgeForkPoint walks down from the coin age tip at the height tip with the
+// hash tipHash to the highest block of the coin age tables that is in the main
+// chain of the node. mainHash returns the hash of the main chain block at a
+// height, and storedHash that of the coin age tables. The height is -1 if no
+// block of the tables is in the main chain.
+func coinAgeForkPoint(tip int64, tipHash string, mainHash, storedHash func(int64) (string, error)) (int64, string, error) {
+	height, hash := tip, tipHash
+	for height >= 0 {
+		main, err := mainHash(height)
+		if err != nil {
+			return 0, "", err
+		}
+		if main == hash {
+			break
+		}
+		height--
+		if height < 0 {
+			break
+		}
+		if hash, err = storedHash(height); err != nil {
+			return 0, "", err
+		}
+	}
+	return height, hash, nil
+}
+
+// coinAgeRevertible checks if the coin age tables can be rewound to the block
+// at height with the kept changes of the blocks from minHeight, -1 if there
+// are none. The tables are rebuilt otherwise.
+func coinAgeRevertible(height, minHeight int64) bool {
+	return height >= 0 && minHeight >= 0 && height+1 >= minHeight
+}
+
+// coinAgeCandidate is a synced whole-chain block that may follow the coin age
+// tip.
+type coinAgeCandidate struct {
+	hash, prevHash string
+	time           int64
+}
+
+// pickCoinAgeBlock picks the block at height following the block with the
+// hash prevHash from the candidate blocks at that height, taking that in the
+// main chain of the node, with the hash from mainHash, if there are several.
+// found is false if there is no such block.
+func pickCoinAgeBlock(height int64, prevHash string, candidates []coinAgeCandidate,
+	mainHash func(int64) (string, error)) (hash string, blockTime int64, found bool, err error) {
+	var next []coinAgeCandidate
+	for _, c := range candidates {
+		if height == 0 || c.prevHash == prevHash {
+			next = append(next, c)
+		}
+	}
+
+	switch len(next) {
+	case 0:
+		return "", 0, false, nil
+	case 1:
+		return next[0].hash, next[0].time, true, nil
+	}
+	main, err := mainHash(height)
+	if err != nil {
+		return "", 0, false, err
+	}
+	for _, c := range next {
+		if c.hash == main {
+			return c.hash, c.time, true, nil
+		}
+	}
+	return "", 0, false, nil
+}
+
+// nextMultichainSyncedBlock returns the hash and time of the synced
+// whole-chain block at the height following the block with the hash prevHash.
+// Of the competing blocks left by a reorg, that in the main chain of the node
+// is taken. found is false if there is no such block yet.
+func (pgb *ChainDB) nextMultichainSyncedBlock(chainType string, height int64, prevHash string) (hash string, blockTime int64, found bool, err error) {
+	rows, err := pgb.db.QueryContext(pgb.ctx, mutilchainquery.MakeSelectCoinAgeCandidateBlocks(chainType), height)
+	if err != nil {
+		return "", 0, false, err
+	}
+	defer closeRows(rows)
+
+	var candidates []coinAgeCandidate
+	for rows.Next() {
+		var c coinAgeCandidate
+		if err = rows.Scan(&c.hash, &c.prevHash, &c.time); err != nil {
+			return "", 0, false, err
+		}
+		candidates = append(candidates, c)
+	}
+	if err = rows.Err(); err != nil {
+		return "", 0, false, err
+	}
+
+	return pickCoinAgeBlock(height, prevHash, candidates, func(height int64) (string, error) {
+		return pgb.MutilchainBlockHashAtHeight(height, chainType)
+	})
+}
+
+// storeMultichainCoinAge applies the changes of a block to the UTXO age
+// distribution of a chain, and stores the coin age data at the block.
+func (pgb *ChainDB) storeMultichainCoinAge(chainType string, height int64, hash string, blockTime int64) error {
+	dbTx, err := pgb.db.BeginTx(pgb.ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmts := []struct {
+		query string
+		args  []interface{}
+	}{
+		{mutilchainquery.MakeInsertUtxoAgeDeltas(chainType), []interface{}{hash, height, blockTime}},
+		{mutilchainquery.MakeApplyUtxoAgeDeltas(chainType), []interface{}{height}},
+		{mutilchainquery.MakeInsertCoinAgeRow(chainType), []interface{}{height, hash, blockTime}},
+		{mutilchainquery.MakeInsertCoinAgeBandsRows(chainType), []interface{}{height, blockTime}},
+		{mutilchainquery.MakeInsertMeanCoinAgeRow(chainType), []interface{}{height, blockTime}},
+		{mutilchainquery.MakeDeleteUtxoAgeDeltasUpTo(chainType), []interface{}{height - coinAgeReorgDepth}},
+	}
+	for _, stmt := range stmts {
+		if _, err = dbTx.Exec(stmt.query, stmt.args...); err != nil {
+			_ = dbTx.Rollback()
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
@@ -0,0 +1,144 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/cache"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// insertCoinAgeBlock stores the coin age data of a block, with 1 coin in the
+// youngest age band and 3 coins in the oldest.
+func insertCoinAgeBlock(t *testing.T, chainType string, height int64, hash string) {
+	t.Helper()
+	blockTime := 1600000000 + height*600
+	stmts := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO %scoin_age (height, hash, time, coin_days_destroyed, avg_coin_days)
+			VALUES ($1, $2, to_timestamp($3), 2e8, 10.5);`, []interface{}{height, hash, blockTime}},
+		{`INSERT INTO %smca_snapshots (block_height, block_time, total_coin_days, total_supply, mean_coin_age)
+			VALUES ($1, to_timestamp($2), 4e8, 4e8, 20.5);`, []interface{}{height, blockTime}},
+		{`INSERT INTO %scoin_age_bands (height, time, age_band, value)
+			VALUES ($1, to_timestamp($2), '<1d', 1e8), ($1, to_timestamp($2), '>7y', 3e8);`,
+			[]interface{}{height, blockTime}},
+	}
+	for _, stmt := range stmts {
+		if _, err := db.db.Exec(fmt.Sprintf(stmt.query, chainType), stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// deleteCoinAgeBlocks deletes the coin age data of the blocks from height.
+func deleteCoinAgeBlocks(t *testing.T, chainType string, height int64) {
+	t.Helper()
+	for _, stmt := range mutilchainquery.MakeRewindCoinAgeStmts(chainType) {
+		if _, err := db.db.Exec(stmt, height-1); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMutilchainCoinAgeChart(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	createMultichainTestTables(t, chainType)
+	var count int64
+	if err := db.db.QueryRow(`SELECT COUNT(*) FROM btccoin_age;`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count > 0 {
+		t.Skip("btccoin_age table is not empty")
+	}
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(mutilchainquery.MakeTruncateCoinAgeTables(chainType))
+	})
+
+	charts := &cache.MutilchainChartData{ChainType: chainType, CoinAge: &cache.ZoomSet{}}
+	update := func() error {
+		t.Helper()
+		rows, err := retrieveMutilchainCoinAge(db.ctx, db.db, charts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return appendMutilchainCoinAge(charts, rows)
+	}
+	checkTip := func(height int32, hash string) {
+		t.Helper()
+		if charts.CoinAgeTip() != height || charts.CoinAgeTipHash != hash {
+			t.Fatalf("coin age tip %d %q, expecting %d %q", charts.CoinAgeTip(), charts.CoinAgeTipHash,
+				height, hash)
+		}
+		if len(charts.CoinAge.CoinAgeBands) != int(height)+1 || len(charts.CoinAge.MeanCoinAge) != int(height)+1 {
+			t.Errorf("series of %d and %d blocks, expecting %d", len(charts.CoinAge.CoinAgeBands),
+				len(charts.CoinAge.MeanCoinAge), height+1)
+		}
+	}
+
+	for h, hash := range []string{"a0", "a1", "a2"} {
+		insertCoinAgeBlock(t, chainType, int64(h), hash)
+	}
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	checkTip(2, "a2")
+	if got := charts.CoinAge.CoinDaysDestroyed[1]; got != 2 {
+		t.Errorf("%v coin days destroyed, expecting 2", got)
+	}
+	if got := charts.CoinAge.TotalCoinDays[1]; got != 4 {
+		t.Errorf("%v total coin days, expecting 4", got)
+	}
+	if got := charts.CoinAge.MeanCoinAge[1]; got != 20.5 {
+		t.Errorf("mean coin age %v, expecting 20.5", got)
+	}
+	if got := charts.CoinAge.Time[2]; got != 1600001200 {
+		t.Errorf("time %d, expecting 1600001200", got)
+	}
+	if bands := charts.CoinAge.CoinAgeBands[2]; bands.Less1Day != 1 || bands.GreaterThan7Year != 3 ||
+		bands.DayToWeek != 0 {
+		t.Errorf("unexpected age bands %+v", *bands)
+	}
+
+	// New blocks are appended.
+	insertCoinAgeBlock(t, chainType, 3, "a3")
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	checkTip(3, "a3")
+
+	// No new blocks.
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	checkTip(3, "a3")
+
+	// A reorg refetches the data from the start, which replaces the cached
+	// blocks, including those above the new tip.
+	deleteCoinAgeBlocks(t, chainType, 1)
+	insertCoinAgeBlock(t, chainType, 1, "b1")
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	checkTip(1, "b1")
+
+	// The cached data of rebuilt tables is dropped.
+	if _, err := db.db.Exec(mutilchainquery.MakeTruncateCoinAgeTables(chainType)); err != nil {
+		t.Fatal(err)
+	}
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	checkTip(-1, "")
+
+	// A gap is an error.
+	insertCoinAgeBlock(t, chainType, 0, "a0")
+	insertCoinAgeBlock(t, chainType, 2, "a2")
+	if err := update(); err == nil {
+		t.Fatal("expected an error for a missing block")
+	}
+}
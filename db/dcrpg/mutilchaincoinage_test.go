@@ -0,0 +1,121 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+func TestCoinAgeForkPoint(t *testing.T) {
+	main := []string{"a0", "a1", "a2", "a3"}
+	mainHash := func(h int64) (string, error) { return main[h], nil }
+	tests := []struct {
+		name       string
+		stored     []string
+		wantHeight int64
+		wantHash   string
+	}{
+		{"main chain", []string{"a0", "a1", "a2"}, 2, "a2"},
+		{"reorg", []string{"a0", "a1", "b2", "b3"}, 1, "a1"},
+		{"other chain", []string{"b0", "b1"}, -1, "b0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storedHash := func(h int64) (string, error) { return tt.stored[h], nil }
+			tip := int64(len(tt.stored) - 1)
+			height, hash, err := coinAgeForkPoint(tip, tt.stored[tip], mainHash, storedHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if height != tt.wantHeight || (height >= 0 && hash != tt.wantHash) {
+				t.Errorf("got %d %s, expected %d %s", height, hash, tt.wantHeight, tt.wantHash)
+			}
+		})
+	}
+
+	nodeErr := errors.New("node down")
+	_, _, err := coinAgeForkPoint(1, "a1", func(int64) (string, error) { return "", nodeErr }, nil)
+	if !errors.Is(err, nodeErr) {
+		t.Errorf("expected the node error, got %v", err)
+	}
+}
+
+func TestCoinAgeRevertible(t *testing.T) {
+	tests := []struct {
+		height, minHeight int64
+		want              bool
+	}{
+		{10, 5, true},
+		{10, 11, true},
+		{10, 12, false},
+		{-1, 0, false},
+		{10, -1, false},
+	}
+	for _, tt := range tests {
+		if got := coinAgeRevertible(tt.height, tt.minHeight); got != tt.want {
+			t.Errorf("coinAgeRevertible(%d, %d) = %v, want %v", tt.height, tt.minHeight, got, tt.want)
+		}
+	}
+}
+
+func TestPickCoinAgeBlock(t *testing.T) {
+	var mainLookups int
+	mainHash := func(int64) (string, error) {
+		mainLookups++
+		return "b5", nil
+	}
+	tests := []struct {
+		name       string
+		height     int64
+		prevHash   string
+		candidates []coinAgeCandidate
+		wantHash   string
+		wantFound  bool
+		wantLookup bool
+	}{
+		{"none", 5, "a4", nil, "", false, false},
+		{"one", 5, "a4", []coinAgeCandidate{{"a5", "a4", 50}}, "a5", true, false},
+		{"other parent", 5, "a4", []coinAgeCandidate{{"c5", "c4", 50}}, "", false, false},
+		{"genesis", 0, "", []coinAgeCandidate{{"g", "", 1}}, "g", true, false},
+		{"competing", 5, "a4", []coinAgeCandidate{{"a5", "a4", 50}, {"b5", "a4", 51}}, "b5", true, true},
+		{"competing off main", 5, "a4", []coinAgeCandidate{{"a5", "a4", 50}, {"c5", "a4", 51}}, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mainLookups = 0
+			hash, _, found, err := pickCoinAgeBlock(tt.height, tt.prevHash, tt.candidates, mainHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash != tt.wantHash || found != tt.wantFound {
+				t.Errorf("got %q %v, expected %q %v", hash, found, tt.wantHash, tt.wantFound)
+			}
+			if (mainLookups > 0) != tt.wantLookup {
+				t.Errorf("unexpected %d node lookups", mainLookups)
+			}
+		})
+	}
+}
+
+func TestSelectCoinAgeChartRowsMarker(t *testing.T) {
+	// The marker row has as many columns as the rows of the blocks, and
+	// sorts first.
+	query := mutilchainquery.MakeSelectCoinAgeChartRows(mutilchain.TYPEBTC)
+	parts := strings.SplitN(query, "UNION ALL", 2)
+	if len(parts) != 2 {
+		t.Fatal("no marker row")
+	}
+	// The rows have the 18 columns scanned by appendMutilchainCoinAge.
+	if n := 18; strings.Count(parts[0], ",")+1 != n {
+		t.Errorf("the marker row does not have %d columns: %s", n, parts[0])
+	}
+	if !strings.Contains(parts[0], "-1::INT8") || !strings.HasSuffix(strings.TrimSpace(query), "ORDER BY 2;") {
+		t.Error("the marker row is not sorted first by height")
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcd/btcjson"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// MutilchainAddressTxHeights retrieves the confirmed transactions funding or
+// spending from a BTC or LTC address with their block heights, in block order.
+func (pgb *ChainDB) MutilchainAddressTxHeights(address, chainType string) ([]*dbtypes.MutilchainAddressTxHeight, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	txs, err := RetrieveMutilchainAddressTxHeights(ctx, pgb.db, address, chainType)
+	return txs, pgb.replaceCancelError(err)
+}
+
+// MutilchainRawTransactionVerbose retrieves the verbose BTC or LTC transaction
+// from the node. LTC transactions are converted to the identically shaped BTC
+// type.
+func (pgb *ChainDB) MutilchainRawTransactionVerbose(txid, chainType string) (*btcjson.TxRawResult, error) {
+	return pgb.mutilchainRawTransactionVerbose(txid, chainType)
+}
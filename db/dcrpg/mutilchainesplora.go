@@ -0,0 +1,737 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	btcchainhash "github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	ltcchainhash "github.com/ltcsuite/ltcd/chaincfg/chainhash"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// esploraFeeTargets are the confirmation targets, in blocks, of the Esplora
+// fee estimates.
+var esploraFeeTargets = []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14,
+	15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 144, 504, 1008}
+
+// esploraScriptType returns the Esplora name of the type of a pkScript.
+func esploraScriptType(pkScript []byte) string {
+	switch txscript.GetScriptClass(pkScript) {
+	case txscript.PubKeyTy:
+		return "p2pk"
+	case txscript.PubKeyHashTy:
+		return "p2pkh"
+	case txscript.ScriptHashTy:
+		return "p2sh"
+	case txscript.WitnessV0PubKeyHashTy:
+		return "v0_p2wpkh"
+	case txscript.WitnessV0ScriptHashTy:
+		return "v0_p2wsh"
+	case txscript.WitnessV1TaprootTy:
+		return "v1_p2tr"
+	case txscript.MultiSigTy:
+		return "multisig"
+	case txscript.NullDataTy:
+		return "op_return"
+	}
+	return "unknown"
+}
+
+// esploraVout describes an output with the given pkScript, addresses and value
+// in satoshis in the Esplora format.
+func esploraVout(pkScript []byte, addrs []string, value int64) *apitypes.EsploraVout {
+	asm, _ := txscript.DisasmString(pkScript)
+	vout := &apitypes.EsploraVout{
+		ScriptPubKey:     hex.EncodeToString(pkScript),
+		ScriptPubKeyAsm:  asm,
+		ScriptPubKeyType: esploraScriptType(pkScript),
+		Value:            value,
+	}
+	if len(addrs) > 0 {
+		vout.ScriptPubKeyAddress = addrs[0]
+	}
+	return vout
+}
+
+// esploraVerboseVout converts an output of a verbose transaction to the Esplora
+// format.
+func esploraVerboseVout(v *btcjson.Vout) *apitypes.EsploraVout {
+	pkScript, _ := hex.DecodeString(v.ScriptPubKey.Hex)
+	amt, _ := btcutil.NewAmount(v.Value)
+	return esploraVout(pkScript, scriptPubKeyAddresses(&v.ScriptPubKey), int64(amt))
+}
+
+// mutilchainBlockHeaderVerbose retrieves the verbose header of the BTC or LTC
+// block with the specified hash from the node.
+func (pgb *ChainDB) mutilchainBlockHeaderVerbose(hash, chainType string) (*btcjson.GetBlockHeaderVerboseResult, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		blockHash, err := btcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return nil, err
+		}
+		return pgb.BtcClient.GetBlockHeaderVerbose(blockHash)
+	case mutilchain.TYPELTC:
+		blockHash, err := ltcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return nil, err
+		}
+		header, err := pgb.LtcClient.GetBlockHeaderVerbose(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		return &btcjson.GetBlockHeaderVerboseResult{
+			Hash:          header.Hash,
+			Confirmations: header.Confirmations,
+			Height:        header.Height,
+			Version:       header.Version,
+			VersionHex:    header.VersionHex,
+			MerkleRoot:    header.MerkleRoot,
+			Time:          header.Time,
+			Nonce:         header.Nonce,
+			Bits:          header.Bits,
+			Difficulty:    header.Difficulty,
+			PreviousHash:  header.PreviousHash,
+			NextHash:      header.NextHash,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// mutilchainBlockVerbose retrieves the BTC or LTC block with the specified
+// hash, with the hashes of its transactions, from the node.
+func (pgb *ChainDB) mutilchainBlockVerbose(hash, chainType string) (*btcjson.GetBlockVerboseResult, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		blockHash, err := btcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return nil, err
+		}
+		return pgb.BtcClient.GetBlockVerbose(blockHash)
+	case mutilchain.TYPELTC:
+		blockHash, err := ltcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return nil, err
+		}
+		block, err := pgb.LtcClient.GetBlockVerbose(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		return &btcjson.GetBlockVerboseResult{
+			Hash:          block.Hash,
+			Confirmations: block.Confirmations,
+			StrippedSize:  block.StrippedSize,
+			Size:          block.Size,
+			Weight:        block.Weight,
+			Height:        block.Height,
+			Version:       block.Version,
+			VersionHex:    block.VersionHex,
+			MerkleRoot:    block.MerkleRoot,
+			Tx:            block.Tx,
+			Time:          block.Time,
+			Nonce:         block.Nonce,
+			Bits:          block.Bits,
+			Difficulty:    block.Difficulty,
+			PreviousHash:  block.PreviousHash,
+			NextHash:      block.NextHash,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// MutilchainBlockHashAtHeight returns the hash of the BTC, LTC or XMR main
+// chain block at the specified height according to the node.
+func (pgb *ChainDB) MutilchainBlockHashAtHeight(height int64, chainType string) (string, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		hash, err := pgb.BtcClient.GetBlockHash(height)
+		if err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	case mutilchain.TYPELTC:
+		hash, err := pgb.LtcClient.GetBlockHash(height)
+		if err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	case mutilchain.TYPEXMR:
+		header, err := pgb.XmrClient.GetBlockHeaderByHeight(uint64(height))
+		if err != nil {
+			return "", err
+		}
+		return header.Hash, nil
+	}
+	return "", fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// MutilchainBestBlock returns the height and hash of the best block of the
+// BTC, LTC or XMR node.
+func (pgb *ChainDB) MutilchainBestBlock(chainType string) (int64, string, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		info, err := pgb.BtcClient.GetBlockChainInfo()
+		if err != nil {
+			return 0, "", err
+		}
+		return int64(info.Blocks), info.BestBlockHash, nil
+	case mutilchain.TYPELTC:
+		info, err := pgb.LtcClient.GetBlockChainInfo()
+		if err != nil {
+			return 0, "", err
+		}
+		return int64(info.Blocks), info.BestBlockHash, nil
+	case mutilchain.TYPEXMR:
+		header, err := pgb.XmrClient.GetLastBlockHeader()
+		if err != nil {
+			return 0, "", err
+		}
+		return int64(header.Height), header.Hash, nil
+	}
+	return 0, "", fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// MutilchainMempoolTxids returns the hashes of the transactions in the mempool
+// of the BTC, LTC or XMR node.
+func (pgb *ChainDB) MutilchainMempoolTxids(chainType string) ([]string, error) {
+	var txids []string
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		hashes, err := pgb.BtcClient.GetRawMempool()
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range hashes {
+			txids = append(txids, hash.String())
+		}
+	case mutilchain.TYPELTC:
+		hashes, err := pgb.LtcClient.GetRawMempool()
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range hashes {
+			txids = append(txids, hash.String())
+		}
+	case mutilchain.TYPEXMR:
+		hashes, err := pgb.XmrClient.GetTransactionPoolHashes()
+		if err != nil {
+			return nil, err
+		}
+		txids = append(txids, hashes...)
+	default:
+		return nil, fmt.Errorf("unsupported chain type %q", chainType)
+	}
+	return txids, nil
+}
+
+// MutilchainBlockHeaderHex retrieves the serialized header of the BTC or LTC
+// block with the specified hash as a hex encoded string.
+func (pgb *ChainDB) MutilchainBlockHeaderHex(hash, chainType string) (string, error) {
+	var headerHex bytes.Buffer
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		blockHash, err := btcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return "", err
+		}
+		header, err := pgb.BtcClient.GetBlockHeader(blockHash)
+		if err != nil {
+			return "", err
+		}
+		if err = header.Serialize(&headerHex); err != nil {
+			return "", err
+		}
+	case mutilchain.TYPELTC:
+		blockHash, err := ltcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return "", err
+		}
+		header, err := pgb.LtcClient.GetBlockHeader(blockHash)
+		if err != nil {
+			return "", err
+		}
+		if err = header.Serialize(&headerHex); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("unsupported chain type %q", chainType)
+	}
+	return hex.EncodeToString(headerHex.Bytes()), nil
+}
+
+// mutilchainTxStatus returns the Esplora status of a transaction mined in the
+// block with the specified hash, which is empty for mempool transactions.
+func (pgb *ChainDB) mutilchainTxStatus(blockHash, chainType string) (*apitypes.EsploraTxStatus, error) {
+	if blockHash == "" {
+		return &apitypes.EsploraTxStatus{}, nil
+	}
+	header, err := pgb.mutilchainBlockHeaderVerbose(blockHash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	if header.Confirmations < 1 {
+		// The block was orphaned, so the transaction is not confirmed.
+		return &apitypes.EsploraTxStatus{}, nil
+	}
+	return &apitypes.EsploraTxStatus{
+		Confirmed:   true,
+		BlockHeight: int64(header.Height),
+		BlockHash:   header.Hash,
+		BlockTime:   header.Time,
+	}, nil
+}
+
+// mutilchainTxStatusAtHeight returns the Esplora status of a transaction mined
+// in the main chain block at the specified height.
+func (pgb *ChainDB) mutilchainTxStatusAtHeight(height int64, chainType string) (*apitypes.EsploraTxStatus, error) {
+	hash, err := pgb.MutilchainBlockHashAtHeight(height, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return pgb.mutilchainTxStatus(hash, chainType)
+}
+
+// esploraTx converts a verbose BTC or LTC transaction to the Esplora format.
+// The previous outputs of the inputs are looked up in the vouts table, falling
+// back to the node for previous outputs that are not stored.
+func (pgb *ChainDB) esploraTx(tx *btcjson.TxRawResult, status *apitypes.EsploraTxStatus, chainType string) (*apitypes.EsploraTx, error) {
+	var prevHashes []string
+	var prevIndexes []int32
+	for _, vin := range tx.Vin {
+		if !vin.IsCoinBase() {
+			prevHashes = append(prevHashes, vin.Txid)
+			prevIndexes = append(prevIndexes, int32(vin.Vout))
+		}
+	}
+	prevVouts, err := pgb.mutilchainVoutsByOutpoints(prevHashes, prevIndexes, chainType)
+	if err != nil {
+		return nil, err
+	}
+
+	esTx := &apitypes.EsploraTx{
+		Txid:     tx.Txid,
+		Version:  int32(tx.Version),
+		Locktime: tx.LockTime,
+		Vin:      make([]apitypes.EsploraVin, 0, len(tx.Vin)),
+		Vout:     make([]apitypes.EsploraVout, 0, len(tx.Vout)),
+		Size:     tx.Size,
+		Weight:   tx.Weight,
+		Status:   *status,
+	}
+	if esTx.Size == 0 {
+		esTx.Size = int32(len(tx.Hex) / 2)
+	}
+	if esTx.Weight == 0 {
+		esTx.Weight = 4 * esTx.Size
+	}
+
+	var isCoinbase bool
+	var valueIn, valueOut int64
+	for _, vin := range tx.Vin {
+		esVin := apitypes.EsploraVin{
+			Txid:       vin.Txid,
+			Vout:       vin.Vout,
+			Witness:    vin.Witness,
+			IsCoinbase: vin.IsCoinBase(),
+			Sequence:   vin.Sequence,
+		}
+		if vin.ScriptSig != nil {
+			esVin.ScriptSig = vin.ScriptSig.Hex
+			esVin.ScriptSigAsm = vin.ScriptSig.Asm
+		}
+		if esVin.IsCoinbase {
+			isCoinbase = true
+			esVin.Txid = "0000000000000000000000000000000000000000000000000000000000000000"
+			esVin.Vout = 0xffffffff
+			esVin.ScriptSig = vin.Coinbase
+			esTx.Vin = append(esTx.Vin, esVin)
+			continue
+		}
+		if prevVout := prevVouts[fmt.Sprintf("%s:%d", vin.Txid, vin.Vout)]; prevVout != nil {
+			esVin.Prevout = esploraVout(prevVout.ScriptPubKey,
+				prevVout.ScriptPubKeyData.Addresses, int64(prevVout.Value))
+		} else if prevTx, err := pgb.mutilchainRawTransactionVerbose(vin.Txid, chainType); err != nil {
+			log.Errorf("Unable to get %s previous outpoint %s:%d: %v",
+				chainType, vin.Txid, vin.Vout, err)
+		} else if int(vin.Vout) < len(prevTx.Vout) {
+			esVin.Prevout = esploraVerboseVout(&prevTx.Vout[vin.Vout])
+		}
+		if esVin.Prevout != nil {
+			valueIn += esVin.Prevout.Value
+		}
+		esTx.Vin = append(esTx.Vin, esVin)
+	}
+	for i := range tx.Vout {
+		esVout := esploraVerboseVout(&tx.Vout[i])
+		valueOut += esVout.Value
+		esTx.Vout = append(esTx.Vout, *esVout)
+	}
+	// The coinbase transaction collects the fees of the block, but has no
+	// input accounting for them, so its fee is zero.
+	if !isCoinbase {
+		esTx.Fee = valueIn - valueOut
+	}
+	return esTx, nil
+}
+
+// MutilchainEsploraTxs retrieves the specified BTC or LTC transactions in the
+// Esplora API format.
+func (pgb *ChainDB) MutilchainEsploraTxs(txids []string, chainType string) ([]*apitypes.EsploraTx, error) {
+	txs := make([]*apitypes.EsploraTx, 0, len(txids))
+	for _, txid := range txids {
+		tx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s transaction %s: %w", chainType, txid, err)
+		}
+		status, err := pgb.mutilchainTxStatus(tx.BlockHash, chainType)
+		if err != nil {
+			return nil, err
+		}
+		esTx, err := pgb.esploraTx(tx, status, chainType)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, esTx)
+	}
+	return txs, nil
+}
+
+// MutilchainEsploraTxStatus retrieves the confirmation status of a BTC or LTC
+// transaction.
+func (pgb *ChainDB) MutilchainEsploraTxStatus(txid, chainType string) (*apitypes.EsploraTxStatus, error) {
+	tx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return pgb.mutilchainTxStatus(tx.BlockHash, chainType)
+}
+
+// MutilchainEsploraOutspends retrieves the spending status of all outputs of a
+// BTC or LTC transaction. Confirmed spends are looked up in the addresses
+// table, and unconfirmed spends with the mempool monitor of the chain.
+func (pgb *ChainDB) MutilchainEsploraOutspends(txid, chainType string) ([]apitypes.EsploraOutspend, error) {
+	tx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+	if err != nil {
+		return nil, err
+	}
+	outspends := make([]apitypes.EsploraOutspend, len(tx.Vout))
+
+	spends, err := pgb.MutilchainSpendDetailsForFundingTx(txid, chainType)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[int64]*apitypes.EsploraTxStatus)
+	for _, spend := range spends {
+		if int(spend.FundingTxVoutIndex) >= len(outspends) {
+			continue
+		}
+		spendingTx, _ := spend.SpendingTxHash.(string)
+		vin, _ := spend.SpendingTxVinIndex.(int32)
+		vinIndex := uint32(vin)
+		outspend := apitypes.EsploraOutspend{
+			Spent:  true,
+			Txid:   spendingTx,
+			Vin:    &vinIndex,
+			Status: &apitypes.EsploraTxStatus{},
+		}
+		if height, ok := spend.BlockHeight.(int64); ok {
+			status, found := statuses[height]
+			if !found {
+				if status, err = pgb.mutilchainTxStatusAtHeight(height, chainType); err != nil {
+					return nil, err
+				}
+				statuses[height] = status
+			}
+			outspend.Status = status
+		}
+		outspends[spend.FundingTxVoutIndex] = outspend
+	}
+
+	// Check the mempool for spends of the remaining outputs.
+	for i := range tx.Vout {
+		if outspends[i].Spent {
+			continue
+		}
+		op := fmt.Sprintf("%s:%d", txid, i)
+		for _, addr := range scriptPubKeyAddresses(&tx.Vout[i].ScriptPubKey) {
+			mp, err := pgb.MutilchainUnconfirmedTxnsForAddress(addr, chainType)
+			if err != nil {
+				return nil, err
+			}
+			for j, spentOp := range mp.SpentOutpoints {
+				if spentOp != op {
+					continue
+				}
+				outspend := apitypes.EsploraOutspend{
+					Spent:  true,
+					Txid:   mp.SpendingTxids[j],
+					Status: &apitypes.EsploraTxStatus{},
+				}
+				if spendingTx, err := pgb.mutilchainRawTransactionVerbose(outspend.Txid, chainType); err == nil {
+					for vinIndex, vin := range spendingTx.Vin {
+						if vin.Txid == txid && vin.Vout == uint32(i) {
+							idx := uint32(vinIndex)
+							outspend.Vin = &idx
+							break
+						}
+					}
+				}
+				outspends[i] = outspend
+				break
+			}
+			if outspends[i].Spent {
+				break
+			}
+		}
+	}
+	return outspends, nil
+}
+
+// MutilchainEsploraAddress retrieves the confirmed and mempool output
+// statistics of a BTC or LTC address.
+func (pgb *ChainDB) MutilchainEsploraAddress(address, chainType string) (*apitypes.EsploraAddress, error) {
+	bal, _, err := pgb.MutilchainAddressBalance(address, chainType)
+	if err != nil {
+		return nil, err
+	}
+	txids, err := pgb.MutilchainInsightAddressTransactions([]string{address}, chainType)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := pgb.MutilchainUnconfirmedTxnsForAddress(address, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return &apitypes.EsploraAddress{
+		Address: address,
+		ChainStats: apitypes.EsploraAddressStats{
+			FundedTxoCount: bal.NumSpent + bal.NumUnspent,
+			FundedTxoSum:   bal.TotalSpent + bal.TotalUnspent,
+			SpentTxoCount:  bal.NumSpent,
+			SpentTxoSum:    bal.TotalSpent,
+			TxCount:        int64(len(txids)),
+		},
+		MempoolStats: apitypes.EsploraAddressStats{
+			FundedTxoCount: mp.NumReceived,
+			FundedTxoSum:   mp.Received,
+			SpentTxoCount:  int64(len(mp.SpentOutpoints)),
+			SpentTxoSum:    mp.Sent,
+			TxCount:        int64(len(mp.Txids)),
+		},
+	}, nil
+}
+
+// MutilchainEsploraUTXOs retrieves the unspent outputs of a BTC or LTC
+// address, including the unconfirmed ones and excluding those spent by mempool
+// transactions.
+func (pgb *ChainDB) MutilchainEsploraUTXOs(address, chainType string) ([]apitypes.EsploraUTXO, error) {
+	confirmed, err := pgb.MutilchainAddressUTXO(address, chainType)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := pgb.MutilchainUnconfirmedTxnsForAddress(address, chainType)
+	if err != nil {
+		return nil, err
+	}
+	spent := make(map[string]bool, len(mp.SpentOutpoints))
+	for _, op := range mp.SpentOutpoints {
+		spent[op] = true
+	}
+
+	utxos := make([]apitypes.EsploraUTXO, 0, len(confirmed)+len(mp.UTXOs))
+	for _, utxo := range confirmed {
+		if spent[fmt.Sprintf("%s:%d", utxo.TxHash, utxo.Vout)] {
+			continue
+		}
+		utxos = append(utxos, apitypes.EsploraUTXO{
+			Txid:  utxo.TxHash,
+			Vout:  utxo.Vout,
+			Value: utxo.Atoms,
+			Status: apitypes.EsploraTxStatus{
+				Confirmed:   true,
+				BlockHeight: int64(utxo.Height),
+				BlockHash:   utxo.BlockHash,
+				BlockTime:   utxo.BlockTime,
+			},
+		})
+	}
+	for _, utxo := range mp.UTXOs {
+		utxos = append(utxos, apitypes.EsploraUTXO{
+			Txid:  utxo.TxHash,
+			Vout:  utxo.Vout,
+			Value: utxo.Atoms,
+		})
+	}
+	return utxos, nil
+}
+
+// MutilchainScripthashAddress returns the BTC or LTC address of the pkScript
+// with the given SHA256 hash, or an empty string if no stored output pays to
+// such a pkScript with an address.
+func (pgb *ChainDB) MutilchainScripthashAddress(scripthash []byte, chainType string) (string, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	address, err := RetrieveMutilchainScripthashAddress(ctx, pgb.db, scripthash, chainType)
+	return address, pgb.replaceCancelError(err)
+}
+
+// MutilchainEsploraBlock retrieves the BTC or LTC block with the specified
+// hash in the Esplora API format.
+func (pgb *ChainDB) MutilchainEsploraBlock(hash, chainType string) (*apitypes.EsploraBlock, error) {
+	block, err := pgb.mutilchainBlockVerbose(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	bits, err := strconv.ParseUint(block.Bits, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bits %q: %w", block.Bits, err)
+	}
+	return &apitypes.EsploraBlock{
+		ID:                block.Hash,
+		Height:            block.Height,
+		Version:           block.Version,
+		Timestamp:         block.Time,
+		TxCount:           len(block.Tx),
+		Size:              block.Size,
+		Weight:            block.Weight,
+		MerkleRoot:        block.MerkleRoot,
+		PreviousBlockHash: block.PreviousHash,
+		Nonce:             block.Nonce,
+		Bits:              uint32(bits),
+		Difficulty:        block.Difficulty,
+	}, nil
+}
+
+// MutilchainEsploraBlockStatus retrieves the main chain status of the BTC or
+// LTC block with the specified hash.
+func (pgb *ChainDB) MutilchainEsploraBlockStatus(hash, chainType string) (*apitypes.EsploraBlockStatus, error) {
+	header, err := pgb.mutilchainBlockHeaderVerbose(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	// The node reports -1 confirmations for blocks off the main chain.
+	if header.Confirmations < 0 {
+		return &apitypes.EsploraBlockStatus{}, nil
+	}
+	return &apitypes.EsploraBlockStatus{
+		InBestChain: true,
+		Height:      int64(header.Height),
+		NextBest:    header.NextHash,
+	}, nil
+}
+
+// MutilchainBlockTxids retrieves the hashes of the transactions of the BTC,
+// LTC or XMR block with the specified hash, in block order.
+func (pgb *ChainDB) MutilchainBlockTxids(hash, chainType string) ([]string, error) {
+	if chainType == mutilchain.TYPEXMR {
+		block, err := pgb.XmrClient.GetBlockByHash(hash)
+		if err != nil {
+			return nil, err
+		}
+		return append([]string{block.MinerTxHash}, block.TxHashes...), nil
+	}
+	block, err := pgb.mutilchainBlockVerbose(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	return block.Tx, nil
+}
+
+// MutilchainBlockRawTxs retrieves the verbose transactions of the BTC or LTC
+// block with the specified hash from the node.
+func (pgb *ChainDB) MutilchainBlockRawTxs(hash, chainType string) ([]btcjson.TxRawResult, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		block := pgb.GetBTCBlockVerboseTxByHash(hash)
+		if block == nil {
+			return nil, fmt.Errorf("unable to get %s block %s", chainType, hash)
+		}
+		return block.Tx, nil
+	case mutilchain.TYPELTC:
+		block := pgb.GetLTCBlockVerboseTxByHash(hash)
+		if block == nil {
+			return nil, fmt.Errorf("unable to get %s block %s", chainType, hash)
+		}
+		txs := make([]btcjson.TxRawResult, 0, len(block.Tx))
+		for i := range block.Tx {
+			txs = append(txs, *btcTxRawResultFromLTC(&block.Tx[i]))
+		}
+		return txs, nil
+	default:
+		return nil, fmt.Errorf("unsupported chain type %q", chainType)
+	}
+}
+
+// MutilchainEsploraBlockTxs retrieves up to count transactions of the BTC or
+// LTC block with the specified hash, starting at index start, in the Esplora
+// API format.
+func (pgb *ChainDB) MutilchainEsploraBlockTxs(hash string, start, count int, chainType string) ([]*apitypes.EsploraTx, error) {
+	txs, err := pgb.MutilchainBlockRawTxs(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	if start < 0 || start >= len(txs) {
+		return nil, fmt.Errorf("start index %d out of range [0, %d)", start, len(txs))
+	}
+	end := start + count
+	if end > len(txs) {
+		end = len(txs)
+	}
+
+	status, err := pgb.mutilchainTxStatus(hash, chainType)
+	if err != nil {
+		return nil, err
+	}
+	esTxs := make([]*apitypes.EsploraTx, 0, end-start)
+	for i := start; i < end; i++ {
+		esTx, err := pgb.esploraTx(&txs[i], status, chainType)
+		if err != nil {
+			return nil, err
+		}
+		esTxs = append(esTxs, esTx)
+	}
+	return esTxs, nil
+}
+
+// MutilchainEsploraFeeEstimates returns the estimated fee rates, in sat/vB, for
+// a BTC or LTC transaction to confirm within each of the Esplora confirmation
+// targets, keyed by the target. Targets the node cannot estimate are omitted.
+func (pgb *ChainDB) MutilchainEsploraFeeEstimates(chainType string) (map[string]float64, error) {
+	estimates := make(map[string]float64, len(esploraFeeTargets))
+	for _, target := range esploraFeeTargets {
+		var feeRate *float64
+		switch chainType {
+		case mutilchain.TYPEBTC:
+			res, err := pgb.BtcClient.EstimateSmartFee(target, nil)
+			if err != nil {
+				return nil, err
+			}
+			feeRate = res.FeeRate
+		case mutilchain.TYPELTC:
+			res, err := pgb.LtcClient.EstimateSmartFee(target, nil)
+			if err != nil {
+				return nil, err
+			}
+			feeRate = res.FeeRate
+		default:
+			return nil, fmt.Errorf("unsupported chain type %q", chainType)
+		}
+		if feeRate == nil || *feeRate <= 0 {
+			continue
+		}
+		// The node estimates the fee rate in coins per kilovbyte.
+		estimates[strconv.FormatInt(target, 10)] = *feeRate * btcutil.SatoshiPerBitcoin / 1000
+	}
+	return estimates, nil
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func Test_esploraVout(t *testing.T) {
+	hash20 := strings.Repeat("11", 20)
+	hash32 := strings.Repeat("22", 32)
+	tests := []struct {
+		name     string
+		pkScript string
+		wantType string
+		wantAsm  string
+	}{
+		{"p2pkh", "76a914" + hash20 + "88ac", "p2pkh",
+			"OP_DUP OP_HASH160 " + hash20 + " OP_EQUALVERIFY OP_CHECKSIG"},
+		{"p2sh", "a914" + hash20 + "87", "p2sh", "OP_HASH160 " + hash20 + " OP_EQUAL"},
+		{"p2wpkh", "0014" + hash20, "v0_p2wpkh", "0 " + hash20},
+		{"p2wsh", "0020" + hash32, "v0_p2wsh", "0 " + hash32},
+		{"p2tr", "5120" + hash32, "v1_p2tr", "1 " + hash32},
+		{"op_return", "6a04deadbeef", "op_return", "OP_RETURN deadbeef"},
+		{"nonstandard", "51", "unknown", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkScript, err := hex.DecodeString(tt.pkScript)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vout := esploraVout(pkScript, []string{"addr"}, 1234)
+			if vout.ScriptPubKeyType != tt.wantType {
+				t.Errorf("got type %q, want %q", vout.ScriptPubKeyType, tt.wantType)
+			}
+			if vout.ScriptPubKeyAsm != tt.wantAsm {
+				t.Errorf("got asm %q, want %q", vout.ScriptPubKeyAsm, tt.wantAsm)
+			}
+			if vout.ScriptPubKey != tt.pkScript || vout.ScriptPubKeyAddress != "addr" || vout.Value != 1234 {
+				t.Errorf("unexpected output %+v", vout)
+			}
+		})
+	}
+}
@@ -0,0 +1,759 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	btcchainhash "github.com/btcsuite/btcd/chaincfg/chainhash"
+	btcwire "github.com/btcsuite/btcd/wire"
+	ltcjson "github.com/ltcsuite/ltcd/btcjson"
+	ltcchainhash "github.com/ltcsuite/ltcd/chaincfg/chainhash"
+	ltcwire "github.com/ltcsuite/ltcd/wire"
+
+	apitypes "github.com/decred/dcrdata/v8/api/types"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/txhelpers"
+)
+
+// MutilchainAddressUTXO returns the confirmed unspent transaction outputs
+// paying to the specified BTC or LTC address.
+func (pgb *ChainDB) MutilchainAddressUTXO(address, chainType string) ([]*dbtypes.MutilchainAddressTxnOutput, error) {
+	if !pgb.IsMutilchainValidAddress(chainType, address) {
+		return nil, fmt.Errorf("invalid %s address %q", chainType, address)
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	utxos, err := RetrieveMutilchainAddressUTXOs(ctx, pgb.db, address, chainType)
+	return utxos, pgb.replaceCancelError(err)
+}
+
+// MutilchainInsightAddressTransactions returns the hashes of all confirmed
+// transactions funding or spending from the specified addresses, in descending
+// order by block time, then ascending order by hash.
+func (pgb *ChainDB) MutilchainInsightAddressTransactions(addrs []string, chainType string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	txHashes, err := RetrieveMutilchainAddressesTxHashes(ctx, pgb.db, addrs, chainType)
+	return txHashes, pgb.replaceCancelError(err)
+}
+
+// MutilchainSpendDetailsForFundingTx returns the details of the spending
+// transactions (tx, index, block height) of the outputs of a BTC or LTC
+// funding transaction.
+func (pgb *ChainDB) MutilchainSpendDetailsForFundingTx(fundHash, chainType string) ([]*apitypes.SpendByFundingHash, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	spends, err := RetrieveMutilchainSpendingTxnsByFundingTx(ctx, pgb.db, fundHash, chainType)
+	return spends, pgb.replaceCancelError(err)
+}
+
+// mutilchainVoutsByOutpoints looks up the value and addresses of the given
+// "txid:vout" outpoints in the vouts table.
+func (pgb *ChainDB) mutilchainVoutsByOutpoints(txHashes []string, indexes []int32, chainType string) (map[string]*dbtypes.Vout, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	vouts, err := RetrieveMutilchainVoutsByOutpoints(ctx, pgb.db, txHashes, indexes, chainType)
+	return vouts, pgb.replaceCancelError(err)
+}
+
+// mempoolAddressOut is an output of a mempool transaction paying to an
+// address.
+type mempoolAddressOut struct {
+	txid     string
+	vout     uint32
+	value    int64
+	pkScript []byte
+	time     int64
+}
+
+// mempoolAddressSpend is an input of a mempool transaction spending a previous
+// output of an address.
+type mempoolAddressSpend struct {
+	txid     string
+	prevTxid string
+	prevVout uint32
+	time     int64
+}
+
+// MutilchainUnconfirmedTxnsForAddress summarizes the mempool transactions of a
+// BTC or LTC address using the chain's MempoolAddressChecker. An empty summary
+// is returned if there is no mempool checker for the chain.
+func (pgb *ChainDB) MutilchainUnconfirmedTxnsForAddress(address, chainType string) (*dbtypes.MutilchainMempoolAddress, error) {
+	var outs []*mempoolAddressOut
+	var spends []*mempoolAddressSpend
+	// Values of the mempool outputs that may be spent by other mempool
+	// transactions, by "txid:vout".
+	mempoolValues := make(map[string]int64)
+
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		if pgb.btcMp == nil {
+			break
+		}
+		addrOuts, _, err := pgb.btcMp.UnconfirmedTxnsForAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		if addrOuts == nil {
+			break
+		}
+		for hash, tx := range addrOuts.TxnsStore {
+			if tx == nil || tx.Tx == nil {
+				continue
+			}
+			for i, txOut := range tx.Tx.TxOut {
+				mempoolValues[fmt.Sprintf("%s:%d", hash, i)] = txOut.Value
+			}
+		}
+		for _, op := range addrOuts.Outpoints {
+			tx := addrOuts.TxnsStore[op.Hash]
+			if tx == nil || tx.Tx == nil || int(op.Index) >= len(tx.Tx.TxOut) {
+				continue
+			}
+			outs = append(outs, &mempoolAddressOut{
+				txid:     op.Hash.String(),
+				vout:     op.Index,
+				value:    tx.Tx.TxOut[op.Index].Value,
+				pkScript: tx.Tx.TxOut[op.Index].PkScript,
+				time:     tx.MemPoolTime,
+			})
+		}
+		for _, prev := range addrOuts.PrevOuts {
+			spend := &mempoolAddressSpend{
+				txid:     prev.TxSpending.String(),
+				prevTxid: prev.PreviousOutpoint.Hash.String(),
+				prevVout: prev.PreviousOutpoint.Index,
+			}
+			if tx := addrOuts.TxnsStore[prev.TxSpending]; tx != nil {
+				spend.time = tx.MemPoolTime
+			}
+			spends = append(spends, spend)
+		}
+	case mutilchain.TYPELTC:
+		if pgb.ltcMp == nil {
+			break
+		}
+		addrOuts, _, err := pgb.ltcMp.UnconfirmedTxnsForAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		if addrOuts == nil {
+			break
+		}
+		for hash, tx := range addrOuts.TxnsStore {
+			if tx == nil || tx.Tx == nil {
+				continue
+			}
+			for i, txOut := range tx.Tx.TxOut {
+				mempoolValues[fmt.Sprintf("%s:%d", hash, i)] = txOut.Value
+			}
+		}
+		for _, op := range addrOuts.Outpoints {
+			tx := addrOuts.TxnsStore[op.Hash]
+			if tx == nil || tx.Tx == nil || int(op.Index) >= len(tx.Tx.TxOut) {
+				continue
+			}
+			outs = append(outs, &mempoolAddressOut{
+				txid:     op.Hash.String(),
+				vout:     op.Index,
+				value:    tx.Tx.TxOut[op.Index].Value,
+				pkScript: tx.Tx.TxOut[op.Index].PkScript,
+				time:     tx.MemPoolTime,
+			})
+		}
+		for _, prev := range addrOuts.PrevOuts {
+			spend := &mempoolAddressSpend{
+				txid:     prev.TxSpending.String(),
+				prevTxid: prev.PreviousOutpoint.Hash.String(),
+				prevVout: prev.PreviousOutpoint.Index,
+			}
+			if tx := addrOuts.TxnsStore[prev.TxSpending]; tx != nil {
+				spend.time = tx.MemPoolTime
+			}
+			spends = append(spends, spend)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported chain type %q", chainType)
+	}
+
+	summary := &dbtypes.MutilchainMempoolAddress{Address: address}
+	seen := make(map[string]bool)
+	addTx := func(txid string, t int64) {
+		if !seen[txid] {
+			seen[txid] = true
+			summary.Txids = append(summary.Txids, txid)
+			summary.TxTimes = append(summary.TxTimes, t)
+		}
+	}
+
+	// The values of previous outputs that were confirmed must be looked up in
+	// the vouts table.
+	spent := make(map[string]bool, len(spends))
+	var prevHashes []string
+	var prevIndexes []int32
+	for _, s := range spends {
+		op := fmt.Sprintf("%s:%d", s.prevTxid, s.prevVout)
+		spent[op] = true
+		summary.SpentOutpoints = append(summary.SpentOutpoints, op)
+		summary.SpendingTxids = append(summary.SpendingTxids, s.txid)
+		if _, found := mempoolValues[op]; !found {
+			prevHashes = append(prevHashes, s.prevTxid)
+			prevIndexes = append(prevIndexes, int32(s.prevVout))
+		}
+	}
+	prevVouts, err := pgb.mutilchainVoutsByOutpoints(prevHashes, prevIndexes, chainType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range outs {
+		summary.NumReceived++
+		summary.Received += o.value
+		addTx(o.txid, o.time)
+		if spent[fmt.Sprintf("%s:%d", o.txid, o.vout)] {
+			continue
+		}
+		summary.UTXOs = append(summary.UTXOs, &dbtypes.MutilchainAddressTxnOutput{
+			Address:   address,
+			PkScript:  hex.EncodeToString(o.pkScript),
+			TxHash:    o.txid,
+			Vout:      o.vout,
+			BlockTime: o.time,
+			Atoms:     o.value,
+		})
+	}
+	for _, s := range spends {
+		op := fmt.Sprintf("%s:%d", s.prevTxid, s.prevVout)
+		if value, found := mempoolValues[op]; found {
+			summary.Sent += value
+		} else if vout := prevVouts[op]; vout != nil {
+			summary.Sent += int64(vout.Value)
+		} else {
+			log.Warnf("Unable to find value of %s outpoint %s spent in mempool.", chainType, op)
+		}
+		addTx(s.txid, s.time)
+	}
+	return summary, nil
+}
+
+// btcTxRawResultFromLTC copies an ltcjson.TxRawResult into the identically
+// shaped btcjson.TxRawResult so that BTC and LTC transactions can share the
+// Insight conversion.
+func btcTxRawResultFromLTC(tx *ltcjson.TxRawResult) *btcjson.TxRawResult {
+	btcTx := &btcjson.TxRawResult{
+		Hex:           tx.Hex,
+		Txid:          tx.Txid,
+		Hash:          tx.Hash,
+		Size:          tx.Size,
+		Vsize:         tx.Vsize,
+		Weight:        tx.Weight,
+		Version:       tx.Version,
+		LockTime:      tx.LockTime,
+		Vin:           make([]btcjson.Vin, 0, len(tx.Vin)),
+		Vout:          make([]btcjson.Vout, 0, len(tx.Vout)),
+		BlockHash:     tx.BlockHash,
+		Confirmations: tx.Confirmations,
+		Time:          tx.Time,
+		Blocktime:     tx.Blocktime,
+	}
+	for _, vin := range tx.Vin {
+		btcVin := btcjson.Vin{
+			Coinbase: vin.Coinbase,
+			Txid:     vin.Txid,
+			Vout:     vin.Vout,
+			Sequence: vin.Sequence,
+			Witness:  vin.Witness,
+		}
+		if vin.ScriptSig != nil {
+			btcVin.ScriptSig = &btcjson.ScriptSig{
+				Asm: vin.ScriptSig.Asm,
+				Hex: vin.ScriptSig.Hex,
+			}
+		}
+		btcTx.Vin = append(btcTx.Vin, btcVin)
+	}
+	for _, vout := range tx.Vout {
+		btcTx.Vout = append(btcTx.Vout, btcjson.Vout{
+			Value: vout.Value,
+			N:     vout.N,
+			ScriptPubKey: btcjson.ScriptPubKeyResult{
+				Asm:       vout.ScriptPubKey.Asm,
+				Hex:       vout.ScriptPubKey.Hex,
+				ReqSigs:   vout.ScriptPubKey.ReqSigs,
+				Type:      vout.ScriptPubKey.Type,
+				Address:   vout.ScriptPubKey.Address,
+				Addresses: vout.ScriptPubKey.Addresses,
+			},
+		})
+	}
+	return btcTx
+}
+
+// mutilchainRawTransactionVerbose retrieves the verbose transaction from the
+// BTC or LTC node.
+func (pgb *ChainDB) mutilchainRawTransactionVerbose(txid, chainType string) (*btcjson.TxRawResult, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		hash, err := btcchainhash.NewHashFromStr(txid)
+		if err != nil {
+			return nil, err
+		}
+		return pgb.BtcClient.GetRawTransactionVerbose(hash)
+	case mutilchain.TYPELTC:
+		hash, err := ltcchainhash.NewHashFromStr(txid)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := pgb.LtcClient.GetRawTransactionVerbose(hash)
+		if err != nil {
+			return nil, err
+		}
+		return btcTxRawResultFromLTC(tx), nil
+	}
+	return nil, fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// scriptPubKeyAddresses returns the addresses of a verbose output, which are
+// in Address for recent node versions and in Addresses for older ones.
+func scriptPubKeyAddresses(spk *btcjson.ScriptPubKeyResult) []string {
+	if len(spk.Addresses) > 0 {
+		return spk.Addresses
+	}
+	if spk.Address != "" {
+		return []string{spk.Address}
+	}
+	return nil
+}
+
+// MutilchainOutPointAddresses returns the addresses and value of the BTC or LTC
+// outpoint txid:vout, looking up the vouts table first and then the node.
+func (pgb *ChainDB) MutilchainOutPointAddresses(txid string, vout uint32, chainType string) ([]string, int64, error) {
+	prevVouts, err := pgb.mutilchainVoutsByOutpoints([]string{txid}, []int32{int32(vout)}, chainType)
+	if err != nil {
+		return nil, 0, err
+	}
+	if prevVout := prevVouts[fmt.Sprintf("%s:%d", txid, vout)]; prevVout != nil {
+		return prevVout.ScriptPubKeyData.Addresses, int64(prevVout.Value), nil
+	}
+	prevTx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+	if err != nil {
+		return nil, 0, err
+	}
+	if int(vout) >= len(prevTx.Vout) {
+		return nil, 0, fmt.Errorf("invalid outpoint %s:%d", txid, vout)
+	}
+	prevOut := &prevTx.Vout[vout]
+	amt, err := btcutil.NewAmount(prevOut.Value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return scriptPubKeyAddresses(&prevOut.ScriptPubKey), int64(amt), nil
+}
+
+// MutilchainOutPointsAddresses retrieves the addresses paid by the stored
+// previous outputs of a BTC or LTC chain in one query, keyed by "txid:vout".
+// The outpoints that are not stored are missing from the result.
+func (pgb *ChainDB) MutilchainOutPointsAddresses(txids []string, vouts []uint32, chainType string) (map[string][]string, error) {
+	indexes := make([]int32, len(vouts))
+	for i, vout := range vouts {
+		indexes[i] = int32(vout)
+	}
+	prevVouts, err := pgb.mutilchainVoutsByOutpoints(txids, indexes, chainType)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make(map[string][]string, len(prevVouts))
+	for outpoint, prevVout := range prevVouts {
+		addresses[outpoint] = prevVout.ScriptPubKeyData.Addresses
+	}
+	return addresses, nil
+}
+
+// MutilchainInsightTransactions retrieves the specified BTC or LTC
+// transactions in the Insight API format. The input values and addresses are
+// looked up in the vouts table, falling back to the node for previous outputs
+// that are not stored (e.g. unconfirmed). The asm, scriptSig, and spending
+// status may be skipped by setting the appropriate input arguments.
+func (pgb *ChainDB) MutilchainInsightTransactions(txids []string, chainType string, noAsm, noScriptSig, noSpent bool) ([]apitypes.InsightTx, error) {
+	bestHeight := pgb.MutilchainHeight(chainType)
+	newTxs := make([]apitypes.InsightTx, 0, len(txids))
+	for _, txid := range txids {
+		tx, err := pgb.mutilchainRawTransactionVerbose(txid, chainType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get %s transaction %s: %w", chainType, txid, err)
+		}
+
+		txNew := apitypes.InsightTx{
+			Txid:          tx.Txid,
+			Version:       int32(tx.Version),
+			Locktime:      tx.LockTime,
+			Blockhash:     tx.BlockHash,
+			Confirmations: int64(tx.Confirmations),
+			Time:          tx.Time,
+			Blocktime:     tx.Blocktime,
+			Size:          uint32(len(tx.Hex) / 2),
+		}
+		if tx.Confirmations > 0 {
+			txNew.Blockheight = bestHeight - int64(tx.Confirmations) + 1
+		} else {
+			txNew.Blockheight = -1
+		}
+
+		// Look up the previous outputs of all non-coinbase inputs.
+		var prevHashes []string
+		var prevIndexes []int32
+		for _, vin := range tx.Vin {
+			if !vin.IsCoinBase() {
+				prevHashes = append(prevHashes, vin.Txid)
+				prevIndexes = append(prevIndexes, int32(vin.Vout))
+			}
+		}
+		prevVouts, err := pgb.mutilchainVoutsByOutpoints(prevHashes, prevIndexes, chainType)
+		if err != nil {
+			return nil, err
+		}
+
+		// Vins
+		var vInSum int64
+		for vinID, vin := range tx.Vin {
+			insightVin := &apitypes.InsightVin{
+				N:        vinID,
+				CoinBase: vin.Coinbase,
+			}
+			if !noScriptSig {
+				insightVin.ScriptSig = new(apitypes.InsightScriptSig)
+				if vin.ScriptSig != nil {
+					if !noAsm {
+						insightVin.ScriptSig.Asm = vin.ScriptSig.Asm
+					}
+					insightVin.ScriptSig.Hex = vin.ScriptSig.Hex
+				}
+			}
+			sequence := vin.Sequence
+			insightVin.Sequence = &sequence
+			if vin.IsCoinBase() {
+				txNew.IsCoinBase = true
+				txNew.Vins = append(txNew.Vins, insightVin)
+				continue
+			}
+			vout := vin.Vout
+			insightVin.Txid = vin.Txid
+			insightVin.Vout = &vout
+
+			var value int64
+			var addrs []string
+			if prevVout := prevVouts[fmt.Sprintf("%s:%d", vin.Txid, vin.Vout)]; prevVout != nil {
+				value = int64(prevVout.Value)
+				addrs = prevVout.ScriptPubKeyData.Addresses
+			} else {
+				// The previous output is not stored, so ask the node.
+				addrs, value, err = pgb.MutilchainOutPointAddresses(vin.Txid, vin.Vout, chainType)
+				if err != nil {
+					log.Errorf("Unable to get %s previous outpoint %s:%d: %v",
+						chainType, vin.Txid, vin.Vout, err)
+				}
+			}
+			if len(addrs) > 0 {
+				insightVin.Addr = addrs[0]
+			}
+			insightVin.ValueSat = value
+			insightVin.Value = btcutil.Amount(value).ToBTC()
+			vInSum += value
+
+			txNew.Vins = append(txNew.Vins, insightVin)
+		}
+
+		// Vouts
+		var vOutSum int64
+		for _, v := range tx.Vout {
+			insightVout := &apitypes.InsightVout{
+				Value: v.Value,
+				N:     v.N,
+				ScriptPubKey: apitypes.InsightScriptPubKey{
+					Addresses: scriptPubKeyAddresses(&v.ScriptPubKey),
+					Type:      v.ScriptPubKey.Type,
+					Hex:       v.ScriptPubKey.Hex,
+				},
+			}
+			if !noAsm {
+				insightVout.ScriptPubKey.Asm = v.ScriptPubKey.Asm
+			}
+			amt, _ := btcutil.NewAmount(v.Value)
+			vOutSum += int64(amt)
+
+			txNew.Vouts = append(txNew.Vouts, insightVout)
+		}
+
+		txNew.ValueIn = btcutil.Amount(vInSum).ToBTC()
+		txNew.ValueOut = btcutil.Amount(vOutSum).ToBTC()
+		// The coinbase transaction collects the fees of the block, but has no
+		// input accounting for them, so never compute its fee.
+		if !txNew.IsCoinBase {
+			txNew.Fees = btcutil.Amount(vInSum - vOutSum).ToBTC()
+		}
+
+		if !noSpent {
+			// Populate the spending status of all vouts from the database,
+			// which does not include mempool transactions.
+			spends, err := pgb.MutilchainSpendDetailsForFundingTx(txNew.Txid, chainType)
+			if err != nil {
+				return nil, err
+			}
+			for _, spend := range spends {
+				if int(spend.FundingTxVoutIndex) >= len(txNew.Vouts) {
+					continue
+				}
+				txNew.Vouts[spend.FundingTxVoutIndex].SpentIndex = spend.SpendingTxVinIndex
+				txNew.Vouts[spend.FundingTxVoutIndex].SpentTxID = spend.SpendingTxHash
+				txNew.Vouts[spend.FundingTxVoutIndex].SpentHeight = spend.BlockHeight
+			}
+		}
+		newTxs = append(newTxs, txNew)
+	}
+	return newTxs, nil
+}
+
+// MutilchainInsightBlock retrieves the BTC or LTC block with the specified
+// hash in the Insight API format. The reward is the total value of the
+// coinbase outputs, which includes the transaction fees of the block.
+func (pgb *ChainDB) MutilchainInsightBlock(hash, chainType string) (*apitypes.InsightBlockResult, error) {
+	var block *apitypes.InsightBlockResult
+	var coinbase []float64
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		b := pgb.GetBTCBlockVerboseTxByHash(hash)
+		if b == nil {
+			return nil, fmt.Errorf("unable to get %s block %s", chainType, hash)
+		}
+		block = &apitypes.InsightBlockResult{
+			Hash:          b.Hash,
+			Confirmations: b.Confirmations,
+			Size:          b.Size,
+			Height:        b.Height,
+			Version:       b.Version,
+			MerkleRoot:    b.MerkleRoot,
+			Time:          b.Time,
+			Nonce:         b.Nonce,
+			Bits:          b.Bits,
+			Difficulty:    b.Difficulty,
+			PreviousHash:  b.PreviousHash,
+			NextHash:      b.NextHash,
+		}
+		for i := range b.Tx {
+			block.Tx = append(block.Tx, b.Tx[i].Txid)
+		}
+		if len(b.Tx) > 0 {
+			for _, v := range b.Tx[0].Vout {
+				coinbase = append(coinbase, v.Value)
+			}
+		}
+	case mutilchain.TYPELTC:
+		b := pgb.GetLTCBlockVerboseTxByHash(hash)
+		if b == nil {
+			return nil, fmt.Errorf("unable to get %s block %s", chainType, hash)
+		}
+		block = &apitypes.InsightBlockResult{
+			Hash:          b.Hash,
+			Confirmations: b.Confirmations,
+			Size:          b.Size,
+			Height:        b.Height,
+			Version:       b.Version,
+			MerkleRoot:    b.MerkleRoot,
+			Time:          b.Time,
+			Nonce:         b.Nonce,
+			Bits:          b.Bits,
+			Difficulty:    b.Difficulty,
+			PreviousHash:  b.PreviousHash,
+			NextHash:      b.NextHash,
+		}
+		for i := range b.Tx {
+			block.Tx = append(block.Tx, b.Tx[i].Txid)
+		}
+		if len(b.Tx) > 0 {
+			for _, v := range b.Tx[0].Vout {
+				coinbase = append(coinbase, v.Value)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported chain type %q", chainType)
+	}
+
+	var reward btcutil.Amount
+	for _, value := range coinbase {
+		amt, _ := btcutil.NewAmount(value)
+		reward += amt
+	}
+	block.Reward = reward.ToBTC()
+	block.IsMainChain = block.Confirmations > 0
+	return block, nil
+}
+
+// MutilchainRawBlock retrieves the serialized BTC, LTC or XMR block with the
+// specified hash as a hex encoded string.
+func (pgb *ChainDB) MutilchainRawBlock(hash, chainType string) (string, error) {
+	var blockHex bytes.Buffer
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		blockHash, err := btcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return "", err
+		}
+		msgBlock, err := pgb.BtcClient.GetBlock(blockHash)
+		if err != nil {
+			return "", err
+		}
+		if err = msgBlock.Serialize(&blockHex); err != nil {
+			return "", err
+		}
+	case mutilchain.TYPELTC:
+		blockHash, err := ltcchainhash.NewHashFromStr(hash)
+		if err != nil {
+			return "", err
+		}
+		msgBlock, err := pgb.LtcClient.GetBlock(blockHash)
+		if err != nil {
+			return "", err
+		}
+		if err = msgBlock.Serialize(&blockHex); err != nil {
+			return "", err
+		}
+	case mutilchain.TYPEXMR:
+		block, err := pgb.XmrClient.GetBlockByHash(hash)
+		if err != nil {
+			return "", err
+		}
+		return block.Blob, nil
+	default:
+		return "", fmt.Errorf("unsupported chain type %q", chainType)
+	}
+	return hex.EncodeToString(blockHex.Bytes()), nil
+}
+
+// MutilchainSendRawTransaction broadcasts a hex encoded BTC or LTC transaction
+// and returns its hash.
+func (pgb *ChainDB) MutilchainSendRawTransaction(txhex, chainType string) (string, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		msgTx, err := txhelpers.BTCMsgTxFromHex(txhex, btcwire.TxVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to deserialize tx: %w", err)
+		}
+		hash, err := pgb.BtcClient.SendRawTransaction(msgTx, false)
+		if err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	case mutilchain.TYPELTC:
+		msgTx, err := txhelpers.LTCMsgTxFromHex(txhex, ltcwire.TxVersion)
+		if err != nil {
+			return "", fmt.Errorf("failed to deserialize tx: %w", err)
+		}
+		hash, err := pgb.LtcClient.SendRawTransaction(msgTx, false)
+		if err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	}
+	return "", fmt.Errorf("unsupported chain type %q", chainType)
+}
+
+// MutilchainInsightStatus retrieves the node information of the BTC or LTC
+// node, and the node's best block hash. The network info is optional since not
+// all node implementations support getnetworkinfo.
+func (pgb *ChainDB) MutilchainInsightStatus(chainType string) (*apitypes.InsightStatusInfo, string, error) {
+	var chainInfo *btcjson.GetBlockChainInfoResult
+	var netInfo *btcjson.GetNetworkInfoResult
+	var connections int64
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		var err error
+		if chainInfo, err = pgb.BtcClient.GetBlockChainInfo(); err != nil {
+			return nil, "", err
+		}
+		netInfo, _ = pgb.BtcClient.GetNetworkInfo()
+		connections, _ = pgb.BtcClient.GetConnectionCount()
+	case mutilchain.TYPELTC:
+		ltcChainInfo, err := pgb.LtcClient.GetBlockChainInfo()
+		if err != nil {
+			return nil, "", err
+		}
+		chainInfo = &btcjson.GetBlockChainInfoResult{
+			Chain:         ltcChainInfo.Chain,
+			Blocks:        ltcChainInfo.Blocks,
+			BestBlockHash: ltcChainInfo.BestBlockHash,
+			Difficulty:    ltcChainInfo.Difficulty,
+		}
+		if ltcNetInfo, err := pgb.LtcClient.GetNetworkInfo(); err == nil {
+			netInfo = &btcjson.GetNetworkInfoResult{
+				Version:         ltcNetInfo.Version,
+				ProtocolVersion: ltcNetInfo.ProtocolVersion,
+				TimeOffset:      ltcNetInfo.TimeOffset,
+				RelayFee:        ltcNetInfo.RelayFee,
+				Warnings:        ltcNetInfo.Warnings,
+			}
+			for _, n := range ltcNetInfo.Networks {
+				netInfo.Networks = append(netInfo.Networks, btcjson.NetworksResult{Proxy: n.Proxy})
+			}
+		}
+		connections, _ = pgb.LtcClient.GetConnectionCount()
+	default:
+		return nil, "", fmt.Errorf("unsupported chain type %q", chainType)
+	}
+
+	info := &apitypes.InsightStatusInfo{
+		Blocks:          int64(chainInfo.Blocks),
+		NodeConnections: int32(connections),
+		Difficulty:      chainInfo.Difficulty,
+		Testnet:         chainInfo.Chain != "main",
+	}
+	if netInfo != nil {
+		info.Version = netInfo.Version
+		info.Protocolversion = netInfo.ProtocolVersion
+		info.NodeTimeoffset = netInfo.TimeOffset
+		info.Relayfee = netInfo.RelayFee
+		info.Errors = netInfo.Warnings
+		for _, n := range netInfo.Networks {
+			if n.Proxy != "" {
+				info.Proxy = n.Proxy
+				break
+			}
+		}
+	}
+	return info, chainInfo.BestBlockHash, nil
+}
+
+// MutilchainEstimateFee returns the estimated fee rate, in coins per kilobyte,
+// for a BTC or LTC transaction to confirm within nbBlocks blocks. The relay fee
+// of the node is returned if the node cannot estimate the fee rate.
+func (pgb *ChainDB) MutilchainEstimateFee(nbBlocks int64, chainType string) (float64, error) {
+	var feeRate *float64
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		if res, err := pgb.BtcClient.EstimateSmartFee(nbBlocks, nil); err == nil {
+			feeRate = res.FeeRate
+		}
+	case mutilchain.TYPELTC:
+		if res, err := pgb.LtcClient.EstimateSmartFee(nbBlocks, nil); err == nil {
+			feeRate = res.FeeRate
+		}
+	default:
+		return 0, fmt.Errorf("unsupported chain type %q", chainType)
+	}
+	if feeRate != nil && *feeRate > 0 && !math.IsInf(*feeRate, 0) {
+		return *feeRate, nil
+	}
+	info, _, err := pgb.MutilchainInsightStatus(chainType)
+	if err != nil {
+		return 0, err
+	}
+	return info.Relayfee, nil
+}
@@ -6,10 +6,12 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	apitypes "github.com/decred/dcrdata/v8/api/types"
 	"github.com/decred/dcrdata/v8/db/dbtypes"
 	"github.com/decred/dcrdata/v8/mutilchain"
 	"github.com/decred/dcrdata/v8/xmr/xmrhelper"
@@ -967,30 +969,6 @@ func RetrieveMutilchainVoutsCount(ctx context.Context, db *sql.DB, chainType str
 	return count, err
 }
 
-func DeleteOlderThan20Blocks(ctx context.Context, db *sql.DB, chainType string, oldestBlockHeight int64) error {
-	queryBuilder := mutilchainquery.MakeDeleteOlderThan20Blocks(chainType)
-	_, err := db.Exec(queryBuilder, oldestBlockHeight)
-	return err
-}
-
-func DeleteTxsOfOlderThan20Blocks(ctx context.Context, db *sql.DB, chainType string, oldestBlockHeight int64) error {
-	queryBuilder := mutilchainquery.MakeDeleteTxsOfOlderThan20Blocks(chainType)
-	_, err := db.Exec(queryBuilder, oldestBlockHeight)
-	return err
-}
-
-func DeleteVinsOfOlderThan20Blocks(ctx context.Context, db *sql.DB, chainType string, oldestBlockHeight int64) error {
-	queryBuilder := mutilchainquery.MakeDeleteVinsOfOlderThan20Blocks(chainType)
-	_, err := db.Exec(queryBuilder, oldestBlockHeight)
-	return err
-}
-
-func DeleteVoutsOfOlderThan20Blocks(ctx context.Context, db *sql.DB, chainType string, oldestBlockHeight int64) error {
-	queryBuilder := mutilchainquery.MakeDeleteVoutsOfOlderThan20Blocks(chainType)
-	_, err := db.Exec(queryBuilder, oldestBlockHeight)
-	return err
-}
-
 func CheckBlockExistOnDB(ctx context.Context, db *sql.DB, chainType string, height int64) (bool, error) {
 	queryBuilder := mutilchainquery.MakeCheckExistBLock(chainType)
 	var exist bool
@@ -1003,3 +981,148 @@ func RetrieveMutilchainAddressesCount(ctx context.Context, db *sql.DB, chainType
 	err := db.QueryRowContext(ctx, mutilchainquery.MakeSelectCountTotalAddress(chainType)).Scan(&count)
 	return count, err
 }
+
+// RetrieveMutilchainAddressUTXOs retrieves the unspent outputs paying to the
+// given address, most recent first.
+func RetrieveMutilchainAddressUTXOs(ctx context.Context, db *sql.DB, address, chainType string) ([]*dbtypes.MutilchainAddressTxnOutput, error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSelectAddressUnspentWithTxn(chainType), address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var outputs []*dbtypes.MutilchainAddressTxnOutput
+	for rows.Next() {
+		var pkScript []byte
+		var height int64
+		txnOutput := &dbtypes.MutilchainAddressTxnOutput{Address: address}
+		err = rows.Scan(&txnOutput.TxHash, &txnOutput.Vout, &txnOutput.Atoms,
+			&height, &txnOutput.BlockHash, &txnOutput.BlockTime, &pkScript)
+		if err != nil {
+			return nil, err
+		}
+		txnOutput.Height = int32(height)
+		txnOutput.PkScript = hex.EncodeToString(pkScript)
+		outputs = append(outputs, txnOutput)
+	}
+	return outputs, rows.Err()
+}
+
+// RetrieveMutilchainAddressesTxHashes retrieves the hashes of the transactions
+// funding or spending from any of the given addresses, most recent first.
+func RetrieveMutilchainAddressesTxHashes(ctx context.Context, db *sql.DB, addresses []string, chainType string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSelectAddressesTxHashes(chainType),
+		pq.Array(addresses))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var txHashes []string
+	for rows.Next() {
+		var txHash string
+		if err = rows.Scan(&txHash); err != nil {
+			return nil, err
+		}
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes, rows.Err()
+}
+
+// RetrieveMutilchainAddressTxHeights retrieves the hashes and block heights of
+// the transactions funding or spending from an address, in block order.
+func RetrieveMutilchainAddressTxHeights(ctx context.Context, db *sql.DB, address, chainType string) ([]*dbtypes.MutilchainAddressTxHeight, error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSelectAddressTxHashesWithHeight(chainType),
+		address)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var txs []*dbtypes.MutilchainAddressTxHeight
+	for rows.Next() {
+		tx := new(dbtypes.MutilchainAddressTxHeight)
+		if err = rows.Scan(&tx.TxHash, &tx.BlockHeight); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, rows.Err()
+}
+
+// RetrieveMutilchainSpendingTxnsByFundingTx retrieves the spending transaction,
+// input index and block height for each spent output of the funding
+// transaction. The block height is nil if the spending transaction is not
+// stored.
+func RetrieveMutilchainSpendingTxnsByFundingTx(ctx context.Context, db *sql.DB, fundingTxHash, chainType string) ([]*apitypes.SpendByFundingHash, error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSelectSpendingTxnsByFundingTxWithHeight(chainType),
+		fundingTxHash)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	var spends []*apitypes.SpendByFundingHash
+	for rows.Next() {
+		var voutIndex uint32
+		var spendingTxHash string
+		var vinIndex int32
+		var height sql.NullInt64
+		if err = rows.Scan(&voutIndex, &spendingTxHash, &vinIndex, &height); err != nil {
+			return nil, err
+		}
+		spend := &apitypes.SpendByFundingHash{
+			FundingTxVoutIndex: voutIndex,
+			SpendingTxVinIndex: vinIndex,
+			SpendingTxHash:     spendingTxHash,
+		}
+		if height.Valid {
+			spend.BlockHeight = height.Int64
+		}
+		spends = append(spends, spend)
+	}
+	return spends, rows.Err()
+}
+
+// RetrieveMutilchainVoutsByOutpoints retrieves the value, addresses and
+// pkScript of the outputs identified by the given outpoints. Outpoints that are not stored are
+// omitted from the returned map.
+func RetrieveMutilchainVoutsByOutpoints(ctx context.Context, db *sql.DB, txHashes []string, indexes []int32, chainType string) (map[string]*dbtypes.Vout, error) {
+	vouts := make(map[string]*dbtypes.Vout, len(txHashes))
+	if len(txHashes) == 0 {
+		return vouts, nil
+	}
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSelectVoutsByOutpoints(chainType),
+		pq.Array(txHashes), pq.Array(indexes))
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		vout := new(dbtypes.Vout)
+		err = rows.Scan(&vout.TxHash, &vout.TxIndex, &vout.Value,
+			pq.Array(&vout.ScriptPubKeyData.Addresses), &vout.ScriptPubKey)
+		if err != nil {
+			return nil, err
+		}
+		vouts[fmt.Sprintf("%s:%d", vout.TxHash, vout.TxIndex)] = vout
+	}
+	return vouts, rows.Err()
+}
+
+// RetrieveMutilchainScripthashAddress retrieves the address of an output of the
+// whole chain whose pkScript has the given SHA256 hash. An empty address is
+// returned if there is no such output.
+func RetrieveMutilchainScripthashAddress(ctx context.Context, db *sql.DB, scripthash []byte, chainType string) (string, error) {
+	var addrs []string
+	err := db.QueryRowContext(ctx, mutilchainquery.MakeSelectVoutAllAddressesByScripthash(chainType),
+		scripthash).Scan(pq.Array(&addrs))
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil || len(addrs) == 0 {
+		return "", err
+	}
+	return addrs[0], nil
+}
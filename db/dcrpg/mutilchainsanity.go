@@ -0,0 +1,481 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/lib/pq"
+)
+
+// MultichainWholeChainSynced checks if the whole-chain tables of a chain have
+// synced blocks, in which case the whole-chain inputs and outputs are checked
+// rather than those of the light tables.
+func MultichainWholeChainSynced(ctx context.Context, db *sql.DB, chainType string) (bool, error) {
+	exists, err := TableExists(db, chainType+"blocks_all")
+	if err != nil || !exists {
+		return false, err
+	}
+	var synced bool
+	err = db.QueryRowContext(ctx, mutilchainquery.MakeSelectWholeChainSynced(chainType)).Scan(&synced)
+	return synced, err
+}
+
+// CheckMultichainBlockChainLinks checks the block_chain table of a chain for
+// blocks with a next block that is not stored or does not link back to them.
+// The row IDs, heights, hashes and next hashes of the blocks are returned.
+// Non-zero length sizes is an indication of database corruption.
+func CheckMultichainBlockChainLinks(ctx context.Context, db *sql.DB, chainType string) (ids []uint64, heights []int64, hashes, nextHashes []string, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeBrokenBlockChainLinks(chainType))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id uint64
+		var height int64
+		var hash, next string
+		err = rows.Scan(&id, &height, &hash, &next)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		ids = append(ids, id)
+		heights = append(heights, height)
+		hashes = append(hashes, hash)
+		nextHashes = append(nextHashes, next)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckMultichainUnsetSpending checks the addresses table of a chain for rows
+// funding a stored input that are not flagged as spent by it. The inputs of
+// the whole-chain tables are checked if whole is true. The input row IDs,
+// spending outpoints, funding outpoints and spending block heights (-1 if not
+// known) are returned. Non-zero length sizes is an indication of database
+// corruption.
+func CheckMultichainUnsetSpending(ctx context.Context, db *sql.DB, chainType string, whole bool) (vinIDs []uint64,
+	txHashes []string, txIndexes []uint32, prevHashes []string, prevIndexes []uint32, heights []int64, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeUnsetSpendingAddresses(chainType, whole))
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id uint64
+		var txHash, prevHash string
+		var txIndex, prevIndex uint32
+		var height int64
+		err = rows.Scan(&id, &txHash, &txIndex, &prevHash, &prevIndex, &height)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
+		vinIDs = append(vinIDs, id)
+		txHashes = append(txHashes, txHash)
+		txIndexes = append(txIndexes, txIndex)
+		prevHashes = append(prevHashes, prevHash)
+		prevIndexes = append(prevIndexes, prevIndex)
+		heights = append(heights, height)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckMultichainMissingFundingOutpoints checks the whole-chain vins table of a
+// chain for non-coinbase inputs spending an output that is not stored. The
+// input row IDs, spending outpoints, funding outpoints and funding block
+// heights (-1 if the funding transaction is not stored either) are returned.
+// Non-zero length sizes is an indication of database corruption.
+func CheckMultichainMissingFundingOutpoints(ctx context.Context, db *sql.DB, chainType string) (vinIDs []uint64,
+	txHashes []string, txIndexes []uint32, prevHashes []string, prevIndexes []uint32, heights []int64, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeMissingFundingOutpoints(chainType),
+		string(zeroHashStringBytes))
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id uint64
+		var txHash, prevHash string
+		var txIndex, prevIndex uint32
+		var height int64
+		err = rows.Scan(&id, &txHash, &txIndex, &prevHash, &prevIndex, &height)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, err
+		}
+		vinIDs = append(vinIDs, id)
+		txHashes = append(txHashes, txHash)
+		txIndexes = append(txIndexes, txIndex)
+		prevHashes = append(prevHashes, prevHash)
+		prevIndexes = append(prevIndexes, prevIndex)
+		heights = append(heights, height)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckMultichainBlockTxCounts checks the synced whole-chain blocks of a chain
+// for blocks whose number of transactions in the header differs from the
+// number of transactions stored for them. The heights, hashes, header counts
+// and stored counts are returned. Non-zero length sizes is an indication of
+// database corruption.
+func CheckMultichainBlockTxCounts(ctx context.Context, db *sql.DB, chainType string) (heights []int64, hashes []string, numTxs, stored []int64, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeBlockTxCountMismatch(chainType))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var height, numTx, num int64
+		var hash string
+		err = rows.Scan(&height, &hash, &numTx, &num)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		heights = append(heights, height)
+		hashes = append(hashes, hash)
+		numTxs = append(numTxs, numTx)
+		stored = append(stored, num)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckMultichainSwapsMissingTx checks the atomic swaps table of a BTC or LTC
+// chain for spends in synced whole-chain blocks whose spending or contract
+// transaction is not stored. The spending transaction hashes and input
+// indexes of the spends, the missing transaction hashes, and the heights of
+// the blocks to fetch (-1 if not known) are returned. Non-zero length sizes is
+// an indication of database corruption.
+func CheckMultichainSwapsMissingTx(ctx context.Context, db *sql.DB, chainType string) (spendTxs []string, spendVins []uint32, missingTxs []string, heights []int64, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.MakeSwapsMissingTx(chainType))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var spendTx, missingTx string
+		var spendVin uint32
+		var height int64
+		err = rows.Scan(&spendTx, &spendVin, &missingTx, &height)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		spendTxs = append(spendTxs, spendTx)
+		spendVins = append(spendVins, spendVin)
+		missingTxs = append(missingTxs, missingTx)
+		heights = append(heights, height)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckXMROrphanedKeyImages checks the monero_key_images table for key images
+// whose spending transaction is not stored. The row IDs, key images, spending
+// transaction hashes and spend heights (-1 if not known) are returned.
+// Non-zero length sizes is an indication of database corruption.
+func CheckXMROrphanedKeyImages(ctx context.Context, db *sql.DB) (ids []uint64, keyImages, txHashes []string, heights []int64, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.OrphanedMoneroKeyImages)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id uint64
+		var keyImage, txHash string
+		var height int64
+		err = rows.Scan(&id, &keyImage, &txHash, &height)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		ids = append(ids, id)
+		keyImages = append(keyImages, keyImage)
+		txHashes = append(txHashes, txHash)
+		heights = append(heights, height)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	return
+}
+
+// CheckXMRReusedKeyImages checks the stored XMR transactions for key images
+// of more than one input, i.e. spent more than once. The key images and their
+// inputs as "txhash:index" are returned. Non-zero length sizes is an
+// indication of database corruption, such as the transactions of an orphaned
+// block remaining stored.
+func CheckXMRReusedKeyImages(ctx context.Context, db *sql.DB) (keyImages []string, inputs [][]string, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.ReusedMoneroKeyImages)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var keyImage string
+		var keyInputs []string
+		err = rows.Scan(&keyImage, pq.Array(&keyInputs))
+		if err != nil {
+			return nil, nil, err
+		}
+		keyImages = append(keyImages, keyImage)
+		inputs = append(inputs, keyInputs)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return
+}
+
+// CheckXMROrphanedRingMembers checks the monero_ring_members table for ring
+// members of transactions that are not stored. The row IDs, transaction hashes
+// and input indexes are returned. Non-zero length sizes is an indication of
+// database corruption.
+func CheckXMROrphanedRingMembers(ctx context.Context, db *sql.DB) (ids []uint64, txHashes []string, inputIndexes []uint32, err error) {
+	rows, err := db.QueryContext(ctx, mutilchainquery.OrphanedMoneroRingMembers)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer closeRows(rows)
+
+	for rows.Next() {
+		var id uint64
+		var txHash string
+		var inputIndex uint32
+		err = rows.Scan(&id, &txHash, &inputIndex)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ids = append(ids, id)
+		txHashes = append(txHashes, txHash)
+		inputIndexes = append(inputIndexes, inputIndex)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return
+}
+
+// RepairMultichainUnsetSpending flags the address rows funding the inputs with
+// the given row IDs, as found by CheckMultichainUnsetSpending, as spent by
+// them. The number of address rows updated is returned.
+func RepairMultichainUnsetSpending(ctx context.Context, db *sql.DB, chainType string, whole bool, vinIDs []uint64) (int64, error) {
+	res, err := db.ExecContext(ctx, mutilchainquery.MakeRelinkUnsetSpendingAddresses(chainType, whole),
+		dbtypes.UInt64Array(vinIDs))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RepairXMROrphanedKeyImages deletes the key images with the given row IDs, as
+// found by CheckXMROrphanedKeyImages. They are stored again with their
+// transactions if the blocks of the transactions are synced again.
+func RepairXMROrphanedKeyImages(ctx context.Context, db *sql.DB, ids []uint64) (int64, error) {
+	res, err := db.ExecContext(ctx, mutilchainquery.DeleteMoneroKeyImagesByIDs, dbtypes.UInt64Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RepairXMROrphanedRingMembers deletes the ring members with the given row IDs,
+// as found by CheckXMROrphanedRingMembers. They are stored again with their
+// transactions if the blocks of the transactions are synced again.
+func RepairXMROrphanedRingMembers(ctx context.Context, db *sql.DB, ids []uint64) (int64, error) {
+	res, err := db.ExecContext(ctx, mutilchainquery.DeleteMoneroRingMembersByIDs, dbtypes.UInt64Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RepairMultichainBlocks fetches the blocks at the given heights from the node
+// of a chain and stores them again in the whole-chain tables, restoring their
+// missing rows and the spending info of the address rows they spend. Negative
+// and repeated heights are skipped. The number of blocks stored is returned.
+func (pgb *ChainDB) RepairMultichainBlocks(chainType string, heights []int64) (int, error) {
+	var repaired int
+	done := make(map[int64]bool, len(heights))
+	for _, height := range heights {
+		if height < 0 || done[height] {
+			continue
+		}
+		done[height] = true
+		if err := pgb.ctx.Err(); err != nil {
+			return repaired, err
+		}
+		var err error
+		switch chainType {
+		case mutilchain.TYPEBTC:
+			if pgb.BtcClient == nil {
+				return repaired, fmt.Errorf("no BTC node connection")
+			}
+			hash, herr := pgb.BtcClient.GetBlockHash(height)
+			if herr != nil {
+				return repaired, fmt.Errorf("BTC: GetBlockHash(%d) failed: %w", height, herr)
+			}
+			msgBlock, berr := pgb.BtcClient.GetBlock(hash)
+			if berr != nil {
+				return repaired, fmt.Errorf("BTC: GetBlock(%d) failed: %w", height, berr)
+			}
+			err = pgb.SyncOneBTCWholeBlock(pgb.BtcClient, msgBlock)
+		case mutilchain.TYPELTC:
+			if pgb.LtcClient == nil {
+				return repaired, fmt.Errorf("no LTC node connection")
+			}
+			hash, herr := pgb.LtcClient.GetBlockHash(height)
+			if herr != nil {
+				return repaired, fmt.Errorf("LTC: GetBlockHash(%d) failed: %w", height, herr)
+			}
+			msgBlock, berr := pgb.LtcClient.GetBlock(hash)
+			if berr != nil {
+				return repaired, fmt.Errorf("LTC: GetBlock(%d) failed: %w", height, berr)
+			}
+			err = pgb.SyncOneLTCWholeBlock(pgb.LtcClient, msgBlock)
+		case mutilchain.TYPEXMR:
+			if pgb.XmrClient == nil {
+				return repaired, fmt.Errorf("no XMR node connection")
+			}
+			pgb.xmrWholeSyncMtx.Lock()
+			_, _, _, err = pgb.StoreXMRWholeBlock(pgb.XmrClient, true, true, height)
+			pgb.xmrWholeSyncMtx.Unlock()
+		default:
+			return repaired, fmt.Errorf("unsupported chain type %s", chainType)
+		}
+		if err != nil {
+			return repaired, fmt.Errorf("%s: failed to store block %d: %w",
+				strings.ToUpper(chainType), height, err)
+		}
+		repaired++
+	}
+	return repaired, nil
+}
+
+// RepairMultichainBlockLinks sets the next block of the block_chain rows with
+// the given block row IDs and heights, as found by
+// CheckMultichainBlockChainLinks, to the block at the next height on the node
+// of a BTC or LTC chain. A next block that is not stored is fetched from the
+// node and stored first. The number of rows updated is returned.
+func (pgb *ChainDB) RepairMultichainBlockLinks(chainType string, ids []uint64, heights []int64) (int, error) {
+	var repaired int
+	for i, id := range ids {
+		if err := pgb.ctx.Err(); err != nil {
+			return repaired, err
+		}
+		next, err := pgb.storeMultichainNextBlock(chainType, heights[i])
+		if err != nil {
+			return repaired, err
+		}
+		if err = UpdateMutilchainBlockNext(pgb.db, id, next, chainType); err != nil {
+			return repaired, err
+		}
+		repaired++
+	}
+	return repaired, nil
+}
+
+// storeMultichainNextBlock returns the hash of the block after the height on
+// the node, or an empty string if the node has no such block, storing the
+// block in the light tables if it is not stored.
+func (pgb *ChainDB) storeMultichainNextBlock(chainType string, height int64) (string, error) {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		if pgb.BtcClient == nil {
+			return "", fmt.Errorf("no BTC node connection")
+		}
+		tip, err := pgb.BtcClient.GetBlockCount()
+		if err != nil {
+			return "", err
+		}
+		if height >= tip {
+			return "", nil
+		}
+		hash, err := pgb.BtcClient.GetBlockHash(height + 1)
+		if err != nil {
+			return "", err
+		}
+		if stored, err := pgb.multichainBlockStored(chainType, hash.String()); err != nil || stored {
+			return hash.String(), err
+		}
+		msgBlock, err := pgb.BtcClient.GetBlock(hash)
+		if err != nil {
+			return "", err
+		}
+		if _, _, err = pgb.StoreBTCBlock(pgb.BtcClient, msgBlock, true, true); err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	case mutilchain.TYPELTC:
+		if pgb.LtcClient == nil {
+			return "", fmt.Errorf("no LTC node connection")
+		}
+		tip, err := pgb.LtcClient.GetBlockCount()
+		if err != nil {
+			return "", err
+		}
+		if height >= tip {
+			return "", nil
+		}
+		hash, err := pgb.LtcClient.GetBlockHash(height + 1)
+		if err != nil {
+			return "", err
+		}
+		if stored, err := pgb.multichainBlockStored(chainType, hash.String()); err != nil || stored {
+			return hash.String(), err
+		}
+		msgBlock, err := pgb.LtcClient.GetBlock(hash)
+		if err != nil {
+			return "", err
+		}
+		if _, _, err = pgb.StoreLTCBlock(pgb.LtcClient, msgBlock, true, true); err != nil {
+			return "", err
+		}
+		return hash.String(), nil
+	default:
+		return "", fmt.Errorf("the %s chain has no block_chain table", chainType)
+	}
+}
+
+// multichainBlockStored checks if the block with the hash is stored in the
+// light tables of a chain.
+func (pgb *ChainDB) multichainBlockStored(chainType, hash string) (bool, error) {
+	var height int64
+	err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectBlockHeightByHash(chainType), hash).Scan(&height)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,135 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+func TestCheckXMROrphanedKeyImages(t *testing.T) {
+	createMultichainTestTables(t, mutilchain.TYPEXMR)
+
+	const storedTx, missingTx = "sanitytest-xmr-stored", "sanitytest-xmr-missing"
+	_, err := db.db.Exec(`INSERT INTO xmrtransactions (tx_hash, block_height) VALUES ($1, 100);`, storedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(`DELETE FROM monero_key_images WHERE key_image LIKE 'sanitytest-%';`)
+		_, _ = db.db.Exec(`DELETE FROM xmrtransactions WHERE tx_hash = $1;`, storedTx)
+	})
+
+	// The sync records the spending transaction of a key image as the
+	// transaction it was first seen in.
+	keyImages := []struct {
+		keyImage           string
+		spentTx, firstSeen any
+		height             int64
+	}{
+		{"sanitytest-first-seen-stored", nil, storedTx, 100},
+		{"sanitytest-spent-stored", storedTx, missingTx, 100},
+		{"sanitytest-orphaned", nil, missingTx, 101},
+	}
+	for _, k := range keyImages {
+		_, err = db.db.Exec(`INSERT INTO monero_key_images (key_image, spent_tx_hash,
+				first_seen_tx_hash, first_seen_block_height)
+			VALUES ($1, $2, $3, $4);`, k.keyImage, k.spentTx, k.firstSeen, k.height)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, images, txHashes, heights, err := CheckXMROrphanedKeyImages(db.ctx, db.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != len(ids) || len(txHashes) != len(ids) || len(heights) != len(ids) {
+		t.Fatalf("got %d ids, %d key images, %d transactions and %d heights",
+			len(ids), len(images), len(txHashes), len(heights))
+	}
+	var found int
+	for i, keyImage := range images {
+		switch keyImage {
+		case "sanitytest-orphaned":
+			found++
+			if txHashes[i] != missingTx || heights[i] != 101 {
+				t.Errorf("orphaned key image spent by %s at %d, expecting %s at 101",
+					txHashes[i], heights[i], missingTx)
+			}
+		case "sanitytest-first-seen-stored", "sanitytest-spent-stored":
+			t.Errorf("key image %s of a stored transaction reported as orphaned", keyImage)
+		}
+	}
+	if found != 1 {
+		t.Errorf("found the orphaned key image %d times, expecting once", found)
+	}
+}
+
+func TestCheckMultichainSwapsMissingTx(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	createMultichainTestTables(t, chainType)
+
+	const height = 970001
+	const contractTx, spendTx = "sanitytest-contract", "sanitytest-spend"
+	const missingContract, missingSpend = "sanitytest-contract-missing", "sanitytest-spend-missing"
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(`DELETE FROM btc_swaps WHERE spend_tx LIKE 'sanitytest-%';`)
+		_, _ = db.db.Exec(`DELETE FROM btctransactions WHERE tx_hash LIKE 'sanitytest-%';`)
+		_, _ = db.db.Exec(`DELETE FROM btcblocks_all WHERE height = $1;`, height)
+	})
+	_, err := db.db.Exec(`INSERT INTO btcblocks_all (hash, height, synced) VALUES ('sanitytest-block', $1, true);`,
+		height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, txHash := range []string{contractTx, spendTx} {
+		_, err = db.db.Exec(`INSERT INTO btctransactions (tx_hash, block_height) VALUES ($1, $2);`, txHash, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, s := range [][2]string{
+		{contractTx, spendTx},
+		{contractTx, missingSpend},
+		{missingContract, spendTx},
+	} {
+		_, err = db.db.Exec(`INSERT INTO btc_swaps (contract_tx, contract_vout, spend_tx, spend_vin, spend_height)
+			VALUES ($1, 0, $2, $3, $4);`, s[0], s[1], i, height)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spendTxs, spendVins, missingTxs, heights, err := CheckMultichainSwapsMissingTx(db.ctx, db.db, chainType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type missing struct {
+		spend   string
+		vin     uint32
+		missing string
+		height  int64
+	}
+	var got []missing
+	for i := range spendTxs {
+		if strings.HasPrefix(spendTxs[i], "sanitytest-") {
+			got = append(got, missing{spendTxs[i], spendVins[i], missingTxs[i], heights[i]})
+		}
+	}
+	// The block of a missing contract transaction is not known.
+	want := []missing{
+		{spendTx, 2, missingContract, -1},
+		{missingSpend, 1, missingSpend, height},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got missing transactions %v, expecting %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got missing transaction %v, expecting %v", got[i], want[i])
+		}
+	}
+}
@@ -151,6 +151,7 @@ func (db *ChainDB) SyncBTCChainDB(client *btcClient.Client, quit chan struct{},
 			voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
 			log.Infof("(%3d blk/s,%5d tx/s,%5d vin/sec,%5d vout/s)", int64(blocksPerSec),
 				int64(txPerSec), int64(vinsPerSec), int64(voutPerSec))
+			recordSyncRate(mutilchain.TYPEBTC, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 			lastBlock, lastTxs = ib, totalTxs
 			lastVins, lastVouts = totalVins, totalVouts
 		default:
@@ -208,48 +209,26 @@ func (db *ChainDB) SyncBTCChainDB(client *btcClient.Client, quit chan struct{},
 	return int64(nodeHeight), err
 }
 
-func (pgb *ChainDB) IsLTC20BlocksSyncing() bool {
-	return pgb.LTC20BlocksSyncing
+func (pgb *ChainDB) IsLTCRecentBlocksSyncing() bool {
+	return pgb.LTCRecentBlocksSyncing
 }
 
-func (pgb *ChainDB) IsBTC20BlocksSyncing() bool {
-	return pgb.BTC20BlocksSyncing
+func (pgb *ChainDB) IsBTCRecentBlocksSyncing() bool {
+	return pgb.BTCRecentBlocksSyncing
 }
 
-func (pgb *ChainDB) SyncLast20BTCBlocks(nodeHeight int32) error {
-	if pgb.BTC20BlocksSyncing {
+// SyncRecentBTCBlocks stores the BTC blocks of the window of the raw rows up to
+// nodeHeight that are not in the DB, and triggers the pruning of the rows
+// beyond the retention window.
+func (pgb *ChainDB) SyncRecentBTCBlocks(nodeHeight int32) error {
+	if pgb.BTCRecentBlocksSyncing {
 		return fmt.Errorf("BTC: There is another sync task running")
 	}
-	pgb.BTC20BlocksSyncing = true
-	//preprocessing, check from DB
+	pgb.BTCRecentBlocksSyncing = true
 	// Total and rate statistics
 	var totalTxs, totalVins, totalVouts int64
 	var lastTxs, lastVins, lastVouts int64
-	startHeight := nodeHeight - 25
-	//Delete all blocks data and blocks related data older than start block
-	//Delete vins, vouts
-	err := DeleteVinsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPEBTC, int64(startHeight))
-	if err != nil {
-		pgb.BTC20BlocksSyncing = false
-		return err
-	}
-	err = DeleteVoutsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPEBTC, int64(startHeight))
-	if err != nil {
-		pgb.BTC20BlocksSyncing = false
-		return err
-	}
-	//delete txs
-	// err = DeleteTxsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPEBTC, int64(startHeight))
-	// if err != nil {
-	// 	pgb.BTC20BlocksSyncing = false
-	// 	return err
-	// }
-	//delete blocks
-	// err = DeleteOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPEBTC, int64(startHeight))
-	// if err != nil {
-	// 	pgb.BTC20BlocksSyncing = false
-	// 	return err
-	// }
+	startHeight := int32(pgb.recentBlocksStart(mutilchain.TYPEBTC, int64(nodeHeight)))
 	tickTime := 20 * time.Second
 	ticker := time.NewTicker(tickTime)
 	startTime := time.Now()
@@ -282,6 +261,7 @@ func (pgb *ChainDB) SyncLast20BTCBlocks(nodeHeight int32) error {
 			voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
 			log.Infof("BTC: (%3d blk/s,%5d tx/s,%5d vin/sec,%5d vout/s)", int64(blocksPerSec),
 				int64(txPerSec), int64(vinsPerSec), int64(voutPerSec))
+			recordSyncRate(mutilchain.TYPEBTC, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 			lastBlock, lastTxs = ib, totalTxs
 			lastVins, lastVouts = totalVins, totalVouts
 		default:
@@ -289,12 +269,12 @@ func (pgb *ChainDB) SyncLast20BTCBlocks(nodeHeight int32) error {
 
 		block, blockHash, err := btcrpcutils.GetBlock(int64(ib), pgb.BtcClient)
 		if err != nil {
-			pgb.BTC20BlocksSyncing = false
+			pgb.BTCRecentBlocksSyncing = false
 			return fmt.Errorf("BTC: GetBlock failed (%s): %v", blockHash, err)
 		}
 		var numVins, numVouts int64
 		if numVins, numVouts, err = pgb.StoreBTCBlockInfo(pgb.BtcClient, block.MsgBlock(), int64(ib), false); err != nil {
-			pgb.BTC20BlocksSyncing = false
+			pgb.BTCRecentBlocksSyncing = false
 			return fmt.Errorf("BTC StoreBlock failed: %v", err)
 		}
 		totalVins += numVins
@@ -303,52 +283,32 @@ func (pgb *ChainDB) SyncLast20BTCBlocks(nodeHeight int32) error {
 		totalTxs += numRTx
 		// update height, the end condition for the loop
 		if _, nodeHeight, err = pgb.BtcClient.GetBestBlock(); err != nil {
-			pgb.BTC20BlocksSyncing = false
+			pgb.BTCRecentBlocksSyncing = false
 			return fmt.Errorf("BTC: GetBestBlock failed: %v", err)
 		}
 	}
 
 	speedReport()
 
-	log.Debugf("BTC: Sync last 20 Blocks of BTC finished at height %d. Delta: %d blocks, %d transactions, %d ins, %d outs",
+	log.Debugf("BTC: Sync of the recent blocks finished at height %d. Delta: %d blocks, %d transactions, %d ins, %d outs",
 		nodeHeight, int64(nodeHeight)-int64(startHeight)+1, totalTxs, totalVins, totalVouts)
-	pgb.BTC20BlocksSyncing = false
-	return err
+	pgb.BTCRecentBlocksSyncing = false
+	pgb.TriggerRetentionPrune(mutilchain.TYPEBTC, int64(nodeHeight))
+	return nil
 }
 
-func (pgb *ChainDB) SyncLast20LTCBlocks(nodeHeight int32) error {
-	if pgb.LTC20BlocksSyncing {
+// SyncRecentLTCBlocks stores the LTC blocks of the window of the raw rows up to
+// nodeHeight that are not in the DB, and triggers the pruning of the rows
+// beyond the retention window.
+func (pgb *ChainDB) SyncRecentLTCBlocks(nodeHeight int32) error {
+	if pgb.LTCRecentBlocksSyncing {
 		return fmt.Errorf("LTC: There is another sync task running")
 	}
-	pgb.LTC20BlocksSyncing = true
+	pgb.LTCRecentBlocksSyncing = true
 	// Total and rate statistics
 	var totalTxs, totalVins, totalVouts int64
 	var lastTxs, lastVins, lastVouts int64
-	startHeight := nodeHeight - 25
-	//Delete all blocks data and blocks related data older than start block
-	//Delete vins, vouts
-	err := DeleteVinsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPELTC, int64(startHeight))
-	if err != nil {
-		pgb.LTC20BlocksSyncing = false
-		return err
-	}
-	err = DeleteVoutsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPELTC, int64(startHeight))
-	if err != nil {
-		pgb.LTC20BlocksSyncing = false
-		return err
-	}
-	//delete txs
-	// err = DeleteTxsOfOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPELTC, int64(startHeight))
-	// if err != nil {
-	// 	pgb.LTC20BlocksSyncing = false
-	// 	return err
-	// }
-	//delete blocks
-	// err = DeleteOlderThan20Blocks(pgb.ctx, pgb.db, mutilchain.TYPELTC, int64(startHeight))
-	// if err != nil {
-	// 	pgb.LTC20BlocksSyncing = false
-	// 	return err
-	// }
+	startHeight := int32(pgb.recentBlocksStart(mutilchain.TYPELTC, int64(nodeHeight)))
 	tickTime := 20 * time.Second
 	ticker := time.NewTicker(tickTime)
 	startTime := time.Now()
@@ -381,6 +341,7 @@ func (pgb *ChainDB) SyncLast20LTCBlocks(nodeHeight int32) error {
 			voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
 			log.Infof("LTC: (%3d blk/s,%5d tx/s,%5d vin/sec,%5d vout/s)", int64(blocksPerSec),
 				int64(txPerSec), int64(vinsPerSec), int64(voutPerSec))
+			recordSyncRate(mutilchain.TYPELTC, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 			lastBlock, lastTxs = ib, totalTxs
 			lastVins, lastVouts = totalVins, totalVouts
 		default:
@@ -388,12 +349,12 @@ func (pgb *ChainDB) SyncLast20LTCBlocks(nodeHeight int32) error {
 
 		block, blockHash, err := ltcrpcutils.GetBlock(int64(ib), pgb.LtcClient)
 		if err != nil {
-			pgb.LTC20BlocksSyncing = false
+			pgb.LTCRecentBlocksSyncing = false
 			return fmt.Errorf("LTC: GetBlock failed (%s): %v", blockHash, err)
 		}
 		var numVins, numVouts int64
 		if numVins, numVouts, err = pgb.StoreLTCBlockInfo(pgb.LtcClient, block.MsgBlock(), int64(ib), false); err != nil {
-			pgb.LTC20BlocksSyncing = false
+			pgb.LTCRecentBlocksSyncing = false
 			return fmt.Errorf("LTC StoreBlock failed: %v", err)
 		}
 		totalVins += numVins
@@ -402,17 +363,18 @@ func (pgb *ChainDB) SyncLast20LTCBlocks(nodeHeight int32) error {
 		totalTxs += numRTx
 		// update height, the end condition for the loop
 		if _, nodeHeight, err = pgb.LtcClient.GetBestBlock(); err != nil {
-			pgb.LTC20BlocksSyncing = false
+			pgb.LTCRecentBlocksSyncing = false
 			return fmt.Errorf("LTC: GetBestBlock failed: %v", err)
 		}
 	}
 
 	speedReport()
 
-	log.Debugf("LTC: Sync last 20 Blocks of LTC finished at height %d. Delta: %d blocks, %d transactions, %d ins, %d outs",
+	log.Debugf("LTC: Sync of the recent blocks finished at height %d. Delta: %d blocks, %d transactions, %d ins, %d outs",
 		nodeHeight, int64(nodeHeight)-int64(startHeight)+1, totalTxs, totalVins, totalVouts)
-	pgb.LTC20BlocksSyncing = false
-	return err
+	pgb.LTCRecentBlocksSyncing = false
+	pgb.TriggerRetentionPrune(mutilchain.TYPELTC, int64(nodeHeight))
+	return nil
 }
 
 func (db *ChainDB) SyncLTCChainDB(client *ltcClient.Client, quit chan struct{},
@@ -497,6 +459,7 @@ func (db *ChainDB) SyncLTCChainDB(client *ltcClient.Client, quit chan struct{},
 			voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
 			log.Infof("(%3d blk/s,%5d tx/s,%5d vin/sec,%5d vout/s)", int64(blocksPerSec),
 				int64(txPerSec), int64(vinsPerSec), int64(voutPerSec))
+			recordSyncRate(mutilchain.TYPELTC, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 			lastBlock, lastTxs = ib, totalTxs
 			lastVins, lastVouts = totalVins, totalVouts
 		default:
@@ -1672,217 +1635,6 @@ func (pgb *ChainDB) SyncMultichainWholeChain(chainType string) {
 	}
 }
 
-func (pgb *ChainDB) SyncBTCWholeChain() {
-	pgb.btcWholeSyncMtx.Lock()
-	defer pgb.btcWholeSyncMtx.Unlock()
-
-	// config concurrency
-	const maxWorkers = 3
-
-	var totalTxs int64
-	var totalVins int64
-	var totalVouts int64
-	var processedBlocks int64
-
-	tickTime := 20 * time.Second
-	startTime := time.Now()
-
-	// speed (use atomic reads)
-	speedReporter := func() {
-		totalElapsed := time.Since(startTime).Seconds()
-		if totalElapsed < 1.0 {
-			return
-		}
-		tTx := atomic.LoadInt64(&totalTxs)
-		tVouts := atomic.LoadInt64(&totalVouts)
-		totalVoutPerSec := tVouts / int64(totalElapsed)
-		totalTxPerSec := tTx / int64(totalElapsed)
-		log.Infof("BTC: Avg. speed: %d tx/s, %d vout/s", totalTxPerSec, totalVoutPerSec)
-	}
-	var once sync.Once
-	defer once.Do(speedReporter)
-
-	// Get remaining heights
-	btcBestBlockHeight := pgb.BtcBestBlock.Height
-	rows, err := pgb.db.QueryContext(pgb.ctx, mutilchainquery.CreateSelectRemainingNotSyncedHeights(mutilchain.TYPEBTC), btcBestBlockHeight)
-	if err != nil {
-		log.Errorf("BTC: Query remaining syncing blocks height list failed: %v", err)
-		return
-	}
-	remaingHeights, err := getRemainingHeightsFromSqlRows(rows)
-	if err != nil {
-		log.Errorf("BTC: Get remaining blocks height list failed: %v", err)
-		return
-	}
-	if len(remaingHeights) == 0 {
-		log.Infof("BTC: No more blocks to synchronize with the whole daemon")
-		return
-	}
-	log.Infof("BTC: Start sync for %d blocks. Minimum height: %d, Maximum height: %d", len(remaingHeights), remaingHeights[0], remaingHeights[len(remaingHeights)-1])
-
-	reindexing := int64(len(remaingHeights)) > pgb.BtcBestBlock.Height/50
-	checkConflict := true
-	if reindexing {
-		checkConflict = false
-		log.Info("BTC: Large bulk load: Removing indexes")
-		if err = pgb.DeindexMutilchainWholeTable(mutilchain.TYPEBTC); err != nil &&
-			!strings.Contains(err.Error(), "does not exist") &&
-			!strings.Contains(err.Error(), "不存在") {
-			log.Errorf("BTC: Deindex for multichain whole table: %v", err)
-			return
-		}
-	}
-
-	// context to cancel on first error
-	ctx, cancel := context.WithCancel(pgb.ctx)
-	defer cancel()
-
-	// worker semaphore & waitgroup
-	sem := make(chan struct{}, maxWorkers)
-	var wg sync.WaitGroup
-
-	// first error storage (atomic.Value to avoid locking complexity)
-	var firstErr atomic.Value // will store error
-
-	// ticker goroutine to log speed with tickTime
-	ticker := time.NewTicker(tickTime)
-	defer ticker.Stop()
-
-	// save local state for logs with tick
-	var lastProcessed int64
-	var lastTxs int64
-	var lastVins int64
-	var lastVouts int64
-
-	// ticker logger goroutine
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				curProcessed := atomic.LoadInt64(&processedBlocks)
-				curTxs := atomic.LoadInt64(&totalTxs)
-				curVins := atomic.LoadInt64(&totalVins)
-				curVouts := atomic.LoadInt64(&totalVouts)
-
-				blocksPerSec := float64(curProcessed-lastProcessed) / tickTime.Seconds()
-				txPerSec := float64(curTxs-lastTxs) / tickTime.Seconds()
-				vinsPerSec := float64(curVins-lastVins) / tickTime.Seconds()
-				voutPerSec := float64(curVouts-lastVouts) / tickTime.Seconds()
-
-				log.Infof("BTC: (%.3f blk/s, %.3f tx/s, %.3f vin/s, %.3f vout/s)", blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
-
-				lastProcessed = curProcessed
-				lastTxs = curTxs
-				lastVins = curVins
-				lastVouts = curVouts
-			}
-		}
-	}()
-
-	// optional: if GetBlock or StoreBTCWholeBlock is NOT thread-safe, uncomment these mutexes and wrap calls.
-	// var getBlockMu sync.Mutex
-	// var storeBlockMu sync.Mutex
-
-	for idx, height := range remaingHeights {
-		// early exit if cancelled
-		if ctx.Err() != nil {
-			break
-		}
-
-		// occasional info logs similar to original behavior
-		if (idx-1)%btcRescanLogBlockChunk == 0 || idx == 0 {
-			if remaingHeights[idx] == 0 {
-				log.Infof("BTC: Scanning genesis block.")
-			} else {
-				curInd := (idx - 1) / btcRescanLogBlockChunk
-				endRangeBlockIdx := (curInd + 1) * btcRescanLogBlockChunk
-				if endRangeBlockIdx >= len(remaingHeights) {
-					endRangeBlockIdx = len(remaingHeights) - 1
-				}
-				log.Infof("BTC: Processing blocks %d to %d...", height, remaingHeights[endRangeBlockIdx])
-			}
-		}
-
-		// acquire worker slot
-		select {
-		case sem <- struct{}{}:
-			// got slot
-		case <-ctx.Done():
-			break
-		}
-
-		wg.Add(1)
-		go func(h int64) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			// Check cancellation once more
-			if ctx.Err() != nil {
-				return
-			}
-
-			// get block (if GetBlock is NOT thread-safe, guard with getBlockMu)
-			// getBlockMu.Lock()
-			block, _, err := btcrpcutils.GetBlock(h, pgb.BtcClient)
-			// getBlockMu.Unlock()
-			if err != nil {
-				// store first error and cancel
-				if firstErr.Load() == nil {
-					firstErr.Store(err)
-					cancel()
-				}
-				log.Errorf("BTC: GetBlock failed (%d): %v", h, err)
-				return
-			}
-
-			// store block (if StoreBTCWholeBlock is NOT thread-safe, guard with storeBlockMu)
-			// storeBlockMu.Lock()
-			numVins, numVouts, err := pgb.StoreBTCWholeBlock(pgb.BtcClient, block.MsgBlock(), checkConflict, false)
-			// storeBlockMu.Unlock()
-			if err != nil {
-				if firstErr.Load() == nil {
-					firstErr.Store(err)
-					cancel()
-				}
-				log.Errorf("BTC StoreBlock failed (height %d): %v", h, err)
-				return
-			}
-
-			// update atomic counters
-			atomic.AddInt64(&totalVins, numVins)
-			atomic.AddInt64(&totalVouts, numVouts)
-			atomic.AddInt64(&totalTxs, int64(len(block.Transactions())))
-			atomic.AddInt64(&processedBlocks, 1)
-		}(height)
-	}
-
-	// wait worker finish
-	wg.Wait()
-
-	if v := firstErr.Load(); v != nil {
-		err = v.(error)
-		log.Errorf("BTC: sync aborted due to error: %v", err)
-		return
-	}
-
-	// final speed report
-	once.Do(speedReporter)
-
-	// rebuild index
-	if reindexing {
-		if err := pgb.IndexMutilchainWholeTable(mutilchain.TYPEBTC); err != nil {
-			log.Errorf("BTC: Re-index failed: %v", err)
-			return
-		}
-	}
-
-	log.Infof("BTC: Finish sync for %d blocks. Minimum height: %d, Maximum height: %d (processed %d blocks, %d tx total, %d vin total, %d vout total)",
-		len(remaingHeights), remaingHeights[0], remaingHeights[len(remaingHeights)-1],
-		atomic.LoadInt64(&processedBlocks), atomic.LoadInt64(&totalTxs), atomic.LoadInt64(&totalVins), atomic.LoadInt64(&totalVouts))
-}
-
 func (pgb *ChainDB) SyncOneBTCWholeBlock(client *btcClient.Client, msgBlock *btcwire.MsgBlock) (err error) {
 	pgb.btcWholeSyncMtx.Lock()
 	defer pgb.btcWholeSyncMtx.Unlock()
@@ -1897,217 +1649,6 @@ func (pgb *ChainDB) SyncOneLTCWholeBlock(client *ltcClient.Client, msgBlock *wir
 	return err
 }
 
-func (pgb *ChainDB) SyncLTCWholeChain() {
-	pgb.ltcWholeSyncMtx.Lock()
-	defer pgb.ltcWholeSyncMtx.Unlock()
-
-	const maxWorkers = 2
-
-	// atomic counters
-	var totalTxs int64
-	var totalVins int64
-	var totalVouts int64
-	var processedBlocks int64
-
-	tickTime := 20 * time.Second
-	startTime := time.Now()
-
-	speedReporter := func() {
-		totalElapsed := time.Since(startTime).Seconds()
-		if totalElapsed < 1.0 {
-			return
-		}
-		tTx := atomic.LoadInt64(&totalTxs)
-		tVouts := atomic.LoadInt64(&totalVouts)
-		totalVoutPerSec := tVouts / int64(totalElapsed)
-		totalTxPerSec := tTx / int64(totalElapsed)
-		log.Infof("LTC: Avg. speed: %d tx/s, %d vout/s", totalTxPerSec, totalVoutPerSec)
-	}
-	var once sync.Once
-	defer once.Do(speedReporter)
-
-	// get remaining heights
-	ltcBestBlockHeight := pgb.LtcBestBlock.Height
-	rows, err := pgb.db.QueryContext(pgb.ctx, mutilchainquery.CreateSelectRemainingNotSyncedHeights(mutilchain.TYPELTC), ltcBestBlockHeight)
-	if err != nil {
-		log.Errorf("LTC: Query remaining syncing blocks height list failed: %v", err)
-		return
-	}
-	remaingHeights, err := getRemainingHeightsFromSqlRows(rows)
-	if err != nil {
-		log.Errorf("LTC: Get remaining blocks height list failed: %v", err)
-		return
-	}
-	if len(remaingHeights) == 0 {
-		log.Infof("LTC: No more blocks to synchronize with the whole daemon")
-		return
-	}
-	log.Infof("LTC: Start sync for %d blocks. Minimum height: %d, Maximum height: %d", len(remaingHeights), remaingHeights[0], remaingHeights[len(remaingHeights)-1])
-
-	reindexing := int64(len(remaingHeights)) > pgb.LtcBestBlock.Height/50
-	conflictCheck := true
-	if reindexing {
-		conflictCheck = false
-		log.Info("LTC: Large bulk load: Removing indexes")
-		if err = pgb.DeindexMutilchainWholeTable(mutilchain.TYPELTC); err != nil &&
-			!strings.Contains(err.Error(), "does not exist") &&
-			!strings.Contains(err.Error(), "不存在") {
-			log.Errorf("LTC: Deindex for multichain whole table: %v", err)
-			return
-		}
-	}
-
-	// context to cancel on first error
-	ctx, cancel := context.WithCancel(pgb.ctx)
-	defer cancel()
-
-	// semaphore to limit concurrency
-	sem := make(chan struct{}, maxWorkers)
-	var wg sync.WaitGroup
-
-	// store first error (atomic.Value)
-	var firstErr atomic.Value
-
-	// ticker for periodic speed logs
-	ticker := time.NewTicker(tickTime)
-	defer ticker.Stop()
-
-	// local last stats for per-tick delta
-	var lastProcessed int64
-	var lastTxs int64
-	var lastVins int64
-	var lastVouts int64
-
-	// ticker goroutine: log per tick
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				curProcessed := atomic.LoadInt64(&processedBlocks)
-				curTxs := atomic.LoadInt64(&totalTxs)
-				curVins := atomic.LoadInt64(&totalVins)
-				curVouts := atomic.LoadInt64(&totalVouts)
-
-				blocksPerSec := float64(curProcessed-lastProcessed) / tickTime.Seconds()
-				txPerSec := float64(curTxs-lastTxs) / tickTime.Seconds()
-				vinsPerSec := float64(curVins-lastVins) / tickTime.Seconds()
-				voutPerSec := float64(curVouts-lastVouts) / tickTime.Seconds()
-
-				log.Infof("LTC: (%.3f blk/s, %.3f tx/s, %.3f vin/s, %.3f vout/s)", blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
-
-				lastProcessed = curProcessed
-				lastTxs = curTxs
-				lastVins = curVins
-				lastVouts = curVouts
-			}
-		}
-	}()
-
-	// If GetBlock or StoreLTCWholeBlock are NOT thread-safe, uncomment mutexes below:
-	// var getBlockMu sync.Mutex
-	// var storeBlockMu sync.Mutex
-
-	// iterate heights and spawn workers
-	for idx, height := range remaingHeights {
-		// early exit if cancelled
-		if ctx.Err() != nil {
-			break
-		}
-
-		// periodic chunk logs to keep parity với code gốc
-		if (idx-1)%ltcRescanLogBlockChunk == 0 || idx == 0 {
-			if remaingHeights[idx] == 0 {
-				log.Infof("LTC: Scanning genesis block.")
-			} else {
-				curInd := (idx - 1) / ltcRescanLogBlockChunk
-				endRangeBlockIdx := (curInd + 1) * ltcRescanLogBlockChunk
-				if endRangeBlockIdx >= len(remaingHeights) {
-					endRangeBlockIdx = len(remaingHeights) - 1
-				}
-				log.Infof("LTC: Processing blocks %d to %d...", height, remaingHeights[endRangeBlockIdx])
-			}
-		}
-
-		// acquire worker slot (or exit if cancelled)
-		select {
-		case sem <- struct{}{}:
-			// acquired
-		case <-ctx.Done():
-			break
-		}
-
-		wg.Add(1)
-		go func(h int64) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			if ctx.Err() != nil {
-				return
-			}
-
-			// get block (wrap with mutex if needed)
-			// getBlockMu.Lock()
-			block, _, err := ltcrpcutils.GetBlock(h, pgb.LtcClient)
-			// getBlockMu.Unlock()
-			if err != nil {
-				if firstErr.Load() == nil {
-					firstErr.Store(err)
-					cancel()
-				}
-				log.Errorf("LTC: GetBlock failed (%d): %v", h, err)
-				return
-			}
-
-			// store block (wrap with mutex if needed)
-			// storeBlockMu.Lock()
-			numVins, numVouts, err := pgb.StoreLTCWholeBlock(pgb.LtcClient, block.MsgBlock(), conflictCheck, false)
-			// storeBlockMu.Unlock()
-			if err != nil {
-				if firstErr.Load() == nil {
-					firstErr.Store(err)
-					cancel()
-				}
-				log.Errorf("LTC StoreBlock failed (height %d): %v", h, err)
-				return
-			}
-
-			// update counters
-			atomic.AddInt64(&totalVins, numVins)
-			atomic.AddInt64(&totalVouts, numVouts)
-			atomic.AddInt64(&totalTxs, int64(len(block.Transactions())))
-			atomic.AddInt64(&processedBlocks, 1)
-		}(height)
-	}
-
-	// wait for workers
-	wg.Wait()
-
-	// if any error occurred, log and return
-	if v := firstErr.Load(); v != nil {
-		if err, ok := v.(error); ok && err != nil {
-			log.Errorf("LTC: sync aborted due to error: %v", err)
-			return
-		}
-	}
-
-	// final speed report
-	once.Do(speedReporter)
-
-	// reindex if needed
-	if reindexing {
-		if err := pgb.IndexMutilchainWholeTable(mutilchain.TYPELTC); err != nil {
-			log.Errorf("LTC: Re-index failed: %v", err)
-			return
-		}
-	}
-
-	log.Infof("LTC: Finish sync for %d blocks. Minimum height: %d, Maximum height: %d (processed %d blocks, %d tx total, %d vin total, %d vout total)",
-		len(remaingHeights), remaingHeights[0], remaingHeights[len(remaingHeights)-1],
-		atomic.LoadInt64(&processedBlocks), atomic.LoadInt64(&totalTxs), atomic.LoadInt64(&totalVins), atomic.LoadInt64(&totalVouts))
-}
-
 func (pgb *ChainDB) SyncXMRWholeChain() {
 	pgb.xmrWholeSyncMtx.Lock()
 	defer pgb.xmrWholeSyncMtx.Unlock()
@@ -2206,6 +1747,7 @@ func (pgb *ChainDB) SyncXMRWholeChain() {
 				voutPerSec := float64(curVouts-lastVouts) / tickTime.Seconds()
 
 				log.Infof("XMR: (%.3f blk/s, %.3f tx/s, %.3f vin/s, %.3f vout/s)", blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
+				recordSyncRate(mutilchain.TYPEXMR, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 
 				lastProcessed = curProcessed
 				lastTxs = curTxs
@@ -0,0 +1,249 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// The schema of the tables of each of the BTC, LTC and XMR chains is versioned
+// in the multichain_meta table, with the same meaning of the versions as for
+// the Decred tables. The tables created before the versioning are 1.0.0.
+const (
+	mutilchainCompatVersion = 1
+	mutilchainSchemaVersion = 5
+	mutilchainMaintVersion  = 0
+)
+
+var (
+	targetMutilchainVersion = &DatabaseVersion{
+		compat: mutilchainCompatVersion,
+		schema: mutilchainSchemaVersion,
+		maint:  mutilchainMaintVersion,
+	}
+
+	legacyMutilchainVersion = &DatabaseVersion{compat: 1}
+)
+
+// backfillHeightStep is the number of blocks of the batches of the backfills
+// of the upgrades.
+const backfillHeightStep = 10000
+
+// mutilchainUpgrade is the upgrade of the tables of a chain from one schema
+// version to the next. The upgrades must be idempotent, since an interrupted
+// upgrade is run again from the start, or resume from the upgrade height.
+type mutilchainUpgrade struct {
+	desc    string
+	upgrade func(u *mutilchainUpgrader) error
+}
+
+// mutilchainUpgrades are the upgrades of the schema versions, in order. The
+// upgrade at index i takes the schema from version i to i+1.
+var mutilchainUpgrades = []mutilchainUpgrade{
+	{"add the columns missing from the tables of older releases", (*mutilchainUpgrader).addMissingColumns},
+	{"index the transactions on block height", (*mutilchainUpgrader).indexTransactionsOnBlockHeight},
+	{"backfill the totals of the whole-chain blocks", (*mutilchainUpgrader).backfillBlocksAllTotals},
+	{"create the coin age tables", (*mutilchainUpgrader).createCoinAgeTables},
+	{"create the UTXO set tables", (*mutilchainUpgrader).createUtxoSetTables},
+}
+
+// mutilchainUpgrader upgrades the tables of a chain.
+type mutilchainUpgrader struct {
+	ctx       context.Context
+	db        *sql.DB
+	chainType string
+}
+
+// MutilchainDBVersion retrieves the version of the tables of a chain from the
+// multichain_meta table. found is false if the version of the chain is not
+// stored.
+func MutilchainDBVersion(db *sql.DB, chainType string) (ver DatabaseVersion, found bool, err error) {
+	err = db.QueryRow(mutilchainquery.SelectMultichainDBVersions, chainType).Scan(&ver.compat,
+		&ver.schema, &ver.maint)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ver, false, nil
+	}
+	return ver, err == nil, err
+}
+
+func storeMutilchainVersion(db *sql.DB, chainType string, ver *DatabaseVersion) error {
+	_, err := db.Exec(mutilchainquery.UpsertMultichainDBVersions, chainType,
+		ver.compat, ver.schema, ver.maint)
+	if err != nil {
+		return fmt.Errorf("failed to update the %s schema version: %w", chainType, err)
+	}
+	return nil
+}
+
+// checkMutilchainVersion returns the error of the tables of a chain at the
+// version that cannot be upgraded to the target version.
+func checkMutilchainVersion(chainType string, ver *DatabaseVersion) error {
+	switch ver.NeededToReach(targetMutilchainVersion) {
+	case OK, Upgrade, Maintenance:
+		return nil
+	case TimeTravel:
+		return fmt.Errorf("the current %s table version is newer than supported: "+
+			"%v > %v", chainType, ver, targetMutilchainVersion)
+	default:
+		return fmt.Errorf("rebuild of the %s tables required (%v -> %v)", chainType,
+			ver, targetMutilchainVersion)
+	}
+}
+
+// upgradeMutilchainTables upgrades the existing tables of a chain to the target
+// version.
+func (pgb *ChainDB) upgradeMutilchainTables(chainType string) error {
+	ver, found, err := MutilchainDBVersion(pgb.db, chainType)
+	if err != nil {
+		return err
+	}
+	if !found {
+		ver = *legacyMutilchainVersion
+		log.Infof("%s: Unversioned DB tables, upgrading from version %v", strings.ToUpper(chainType), ver)
+		if err = storeMutilchainVersion(pgb.db, chainType, &ver); err != nil {
+			return err
+		}
+	}
+	if err = checkMutilchainVersion(chainType, &ver); err != nil {
+		return err
+	}
+	if ver.NeededToReach(targetMutilchainVersion) == OK {
+		log.Infof("%s: DB schema version %v", strings.ToUpper(chainType), ver)
+		return nil
+	}
+
+	u := &mutilchainUpgrader{
+		ctx:       pgb.ctx,
+		db:        pgb.db,
+		chainType: chainType,
+	}
+	for ver.schema < targetMutilchainVersion.schema {
+		next := ver
+		next.schema++
+		next.maint = 0
+		step := mutilchainUpgrades[ver.schema]
+		log.Infof("%s: Performing database upgrade %v -> %v: %s", strings.ToUpper(chainType),
+			ver, next, step.desc)
+		if err = step.upgrade(u); err != nil {
+			return fmt.Errorf("failed to upgrade the %s tables %v to %v: %w", chainType, ver, next, err)
+		}
+		if err = storeMutilchainVersion(pgb.db, chainType, &next); err != nil {
+			return err
+		}
+		ver = next
+	}
+	// There is no maintenance of the current schema version.
+	if ver.maint != targetMutilchainVersion.maint {
+		ver.maint = targetMutilchainVersion.maint
+		if err = storeMutilchainVersion(pgb.db, chainType, &ver); err != nil {
+			return err
+		}
+	}
+	log.Infof("%s: DB schema upgraded to version %v", strings.ToUpper(chainType), ver)
+	return nil
+}
+
+func (u *mutilchainUpgrader) addMissingColumns() error {
+	for _, stmt := range mutilchainquery.MakeAddMissingColumns(u.chainType) {
+		if _, err := u.db.ExecContext(u.ctx, stmt); err != nil {
+			return err
+		}
+	}
+	// The checkpoints of the whole-chain sync are shared by the chains.
+	return createTable(u.db, "multichain_sync_checkpoints", mutilchainquery.CreateSyncCheckpointTable)
+}
+
+func (u *mutilchainUpgrader) indexTransactionsOnBlockHeight() error {
+	exists, err := ExistsIndex(u.db, mutilchainquery.MakeIndexOfTransactionTableOnBlockHeight(u.chainType))
+	if err != nil || exists {
+		return err
+	}
+	log.Infof("Indexing %stransactions on block height...", u.chainType)
+	_, err = u.db.ExecContext(u.ctx, mutilchainquery.MakeIndexTransactionTableOnBlockHeight(u.chainType))
+	return err
+}
+
+// backfillBlocksAllTotals sets the totals of the synced whole-chain blocks of
+// the BTC and LTC chains lacking them, from their transactions. The backfill
+// resumes from the upgrade height.
+func (u *mutilchainUpgrader) backfillBlocksAllTotals() error {
+	if u.chainType == mutilchain.TYPEXMR {
+		return nil
+	}
+	var height, maxHeight int64
+	err := u.db.QueryRowContext(u.ctx, mutilchainquery.SelectMultichainUpgradeHeight, u.chainType).Scan(&height)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if height < 0 {
+		height = 0
+	}
+	err = u.db.QueryRowContext(u.ctx, mutilchainquery.MakeSelectBlocksAllMaxHeight(u.chainType)).Scan(&maxHeight)
+	if err != nil {
+		return err
+	}
+
+	stmt := mutilchainquery.MakeBackfillBlocksAllTotals(u.chainType)
+	var updated int64
+	for height <= maxHeight {
+		if err = u.ctx.Err(); err != nil {
+			return err
+		}
+		end := height + backfillHeightStep
+		res, err := u.db.ExecContext(u.ctx, stmt, height, end)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		updated += n
+		// Record the progress, so an interrupted backfill resumes here.
+		_, err = u.db.ExecContext(u.ctx, mutilchainquery.SetMultichainUpgradeHeight, u.chainType, end)
+		if err != nil {
+			return err
+		}
+		log.Infof("%s: Backfilled the totals of blocks [%d,%d) of %d (%d updated)",
+			strings.ToUpper(u.chainType), height, end, maxHeight+1, updated)
+		height = end
+	}
+	return nil
+}
+
+// createCoinAgeTables creates the coin age tables of the BTC and LTC chains,
+// which are filled from the whole-chain tables by SyncMultichainCoinAge.
+func (u *mutilchainUpgrader) createCoinAgeTables() error {
+	if u.chainType == mutilchain.TYPEXMR {
+		return nil
+	}
+	for _, pair := range mutilchainquery.MakeCoinAgeTables(u.chainType) {
+		if err := createTable(u.db, pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createUtxoSetTables creates the UTXO set tables of the BTC and LTC chains,
+// which are filled from the whole-chain tables by SyncUtxoSet.
+func (u *mutilchainUpgrader) createUtxoSetTables() error {
+	if u.chainType == mutilchain.TYPEXMR {
+		return nil
+	}
+	for _, pair := range internal.MakeUtxoSetTables(u.chainType) {
+		if err := createTable(u.db, pair[0], pair[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/btcd/btcjson"
@@ -332,8 +333,8 @@ type ChainDB struct {
 	SyncChainDBFlag        bool
 	XmrSyncFlag            bool
 	OkLinkAPIKey           string
-	BTC20BlocksSyncing     bool
-	LTC20BlocksSyncing     bool
+	BTCRecentBlocksSyncing bool
+	LTCRecentBlocksSyncing bool
 	AddressSummarySyncing  bool
 	TreasurySummarySyncing bool
 	lastExplorerBlock      struct {
@@ -374,11 +375,23 @@ type ChainDB struct {
 	}
 	coinAgeSync               sync.Mutex
 	utxoHistorySync           sync.Mutex
+	mixStatsSync              sync.Mutex
 	multichainBtcMetaInfoSync sync.Mutex
 	multichainLtcMetaInfoSync sync.Mutex
 	btcWholeSyncMtx           sync.Mutex
 	ltcWholeSyncMtx           sync.Mutex
 	xmrWholeSyncMtx           sync.Mutex
+	btcWholeSynced            atomic.Bool
+	ltcWholeSynced            atomic.Bool
+	btcCoinAgeSyncMtx         sync.Mutex
+	ltcCoinAgeSyncMtx         sync.Mutex
+	dcrUtxoSetSyncMtx         sync.Mutex
+	btcUtxoSetSyncMtx         sync.Mutex
+	ltcUtxoSetSyncMtx         sync.Mutex
+	bulkBatchBlocks           int
+	pruners                   map[string]*chainPruner
+	pruneBatchRows            int
+	utxoSnapshotBlocks        int64
 }
 
 // ChainDeployments is mutex-protected blockchain deployment data.
@@ -571,6 +584,17 @@ type ChainDBCfg struct {
 	SyncChainDBFlag                   bool
 	XmrSyncFlag                       bool
 	OkLinkAPIKey                      string
+	// BulkBatchBlocks is the number of blocks of a batch of the BTC and LTC
+	// whole-chain import.
+	BulkBatchBlocks int
+	// BTCRetention and LTCRetention are the windows of the BTC and LTC tables
+	// when the whole chains are not synced, pruned in batches of at most
+	// PruneBatchRows rows.
+	BTCRetention, LTCRetention RetentionPolicy
+	PruneBatchRows             int
+	// UtxoSnapshotInterval is the number of blocks between the UTXO set
+	// snapshots of the DCR, BTC and LTC chains.
+	UtxoSnapshotInterval int64
 }
 
 // The minimum required PostgreSQL version in integer format as returned by
@@ -860,6 +884,10 @@ func NewChainDB(ctx context.Context, cfg *ChainDBCfg, stakeDB *stakedb.StakeData
 		SyncChainDBFlag:    cfg.SyncChainDBFlag,
 		XmrSyncFlag:        cfg.XmrSyncFlag,
 		OkLinkAPIKey:       cfg.OkLinkAPIKey,
+		bulkBatchBlocks:    cfg.BulkBatchBlocks,
+		pruners:            newChainPruners(cfg),
+		pruneBatchRows:     cfg.PruneBatchRows,
+		utxoSnapshotBlocks: cfg.UtxoSnapshotInterval,
 	}
 	chainDB.lastExplorerBlock.difficulties = make(map[int64]float64)
 	// Update the current chain state in the ChainDB
@@ -996,23 +1024,35 @@ func (pgb *ChainDB) CheckTableExist(table string) (bool, error) {
 	return exists, nil
 }
 
+// MutilchainCheckAndCreateTable creates the tables of a chain at the current
+// schema version, or upgrades the existing tables to it. The upgrade fails if
+// the tables are newer than supported.
 func (pgb *ChainDB) MutilchainCheckAndCreateTable(chainType string) error {
+	err := createTable(pgb.db, "multichain_meta", mutilchainquery.CreateMultichainMetaTable)
+	if err != nil {
+		return err
+	}
 	exists, err := TableExists(pgb.db, fmt.Sprintf("%sblocks", chainType))
 	if err != nil {
 		return err
 	}
-	if !exists {
-		//Create type
-		if err := CreateMutilchainTypes(pgb.db, chainType); err != nil {
-			return err
-		}
-		// Empty database (no blocks table). Proceed to setupTables.
-		log.Infof(`tables of %s empty. Creating tables...`, chainType)
-		if err = CreateMutilchainTables(pgb.db, chainType); err != nil {
-			return fmt.Errorf("failed to create tables: %w", err)
-		}
+	if exists {
+		return pgb.upgradeMutilchainTables(chainType)
 	}
-	return nil
+	//Create type
+	if err := CreateMutilchainTypes(pgb.db, chainType); err != nil {
+		return err
+	}
+	// Empty database (no blocks table). Proceed to setupTables.
+	log.Infof(`tables of %s empty. Creating tables...`, chainType)
+	if err = CreateMutilchainTables(pgb.db, chainType); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+	err = createTable(pgb.db, "multichain_sync_checkpoints", mutilchainquery.CreateSyncCheckpointTable)
+	if err != nil {
+		return err
+	}
+	return storeMutilchainVersion(pgb.db, chainType, targetMutilchainVersion)
 }
 
 func (pgb *ChainDB) CheckAndCreateCoinAgeTable() error {
@@ -1247,6 +1287,12 @@ func (pgb *ChainDB) RegisterCharts(charts *cache.ChartData) {
 		Appender: appendMcaSnapshot,
 	})
 
+	charts.AddUpdater(cache.ChartUpdater{
+		Tag:      "UTXO set",
+		Fetcher:  pgb.chartUtxoSet,
+		Appender: appendChartUtxoSet,
+	})
+
 	charts.AddUpdater(cache.ChartUpdater{
 		Tag:      "market price",
 		Fetcher:  pgb.marketPrice,
@@ -1297,6 +1343,20 @@ func (pgb *ChainDB) RegisterMutilchainCharts(charts *cache.MutilchainChartData)
 			Fetcher:  pgb.chartMutilchainBlocks,
 			Appender: appendMutilchainChartBlocks,
 		})
+		// The coin age and the UTXO set are computed from the whole-chain
+		// tables.
+		if pgb.SyncChainDBFlag {
+			charts.AddUpdater(cache.ChartMutilchainUpdater{
+				Tag:      fmt.Sprintf("%s coin age", charts.ChainType),
+				Fetcher:  pgb.chartMutilchainCoinAge,
+				Appender: appendMutilchainCoinAge,
+			})
+			charts.AddUpdater(cache.ChartMutilchainUpdater{
+				Tag:      fmt.Sprintf("%s UTXO set", charts.ChainType),
+				Fetcher:  pgb.chartMutilchainUtxoSet,
+				Appender: appendMutilchainUtxoSet,
+			})
+		}
 		return
 	}
 
@@ -1454,55 +1514,64 @@ func (pgb *ChainDB) MutilchainHeight(chainType string) int64 {
 }
 
 func (pgb *ChainDB) GetMutilchainBestBlock(chainType string) (int64, string) {
+	height, hash, _ := pgb.MutilchainBestBlockHeightHash(chainType)
+	return height, hash
+}
+
+// MutilchainBestBlockHeightHash returns the height and hash of the best block
+// of a chain, retrieving it if it is not yet known. The error of the retrieval
+// is returned.
+func (pgb *ChainDB) MutilchainBestBlockHeightHash(chainType string) (int64, string, error) {
 	switch chainType {
 	case mutilchain.TYPELTC:
-		if pgb.LtcBestBlock != nil {
-			return pgb.LtcBestBlock.MutilchainHeightHash()
-		}
-		err := pgb.GetLTCBestBlock()
-		if err != nil {
-			return 0, ""
+		if pgb.LtcBestBlock == nil {
+			if err := pgb.GetLTCBestBlock(); err != nil {
+				return 0, "", err
+			}
 		}
-		return pgb.LtcBestBlock.MutilchainHeightHash()
+		height, hash := pgb.LtcBestBlock.MutilchainHeightHash()
+		return height, hash, nil
 	case mutilchain.TYPEBTC:
-		if pgb.BtcBestBlock != nil {
-			return pgb.BtcBestBlock.MutilchainHeightHash()
-		}
-		err := pgb.GetBTCBestBlock()
-		if err != nil {
-			return 0, ""
+		if pgb.BtcBestBlock == nil {
+			if err := pgb.GetBTCBestBlock(); err != nil {
+				return 0, "", err
+			}
 		}
-		return pgb.BtcBestBlock.MutilchainHeightHash()
+		height, hash := pgb.BtcBestBlock.MutilchainHeightHash()
+		return height, hash, nil
 	case mutilchain.TYPEXMR:
-		if pgb.XmrBestBlock != nil {
-			return pgb.XmrBestBlock.MutilchainHeightHash()
-		}
-		err := pgb.GetXMRBestBlock()
-		if err != nil {
-			return 0, ""
+		if pgb.XmrBestBlock == nil {
+			if err := pgb.GetXMRBestBlock(); err != nil {
+				return 0, "", err
+			}
 		}
-		return pgb.XmrBestBlock.MutilchainHeightHash()
+		height, hash := pgb.XmrBestBlock.MutilchainHeightHash()
+		return height, hash, nil
 	default:
-		if pgb.bestBlock != nil {
-			return pgb.bestBlock.HeightHash()
-		}
-		err := pgb.GetDecredBestBlock()
-		if err != nil {
-			return 0, ""
+		if pgb.bestBlock == nil {
+			if err := pgb.GetDecredBestBlock(); err != nil {
+				return 0, "", err
+			}
 		}
-		return pgb.bestBlock.HeightHash()
+		height, hash := pgb.bestBlock.HeightHash()
+		return height, hash, nil
 	}
 }
 
 func (pgb *ChainDB) MutilchainBestBlockTime(chainType string) int64 {
+	var bestBlock *MutilchainBestBlock
 	switch chainType {
 	case mutilchain.TYPELTC:
-		return pgb.LtcBestBlock.MutilchainTime()
+		bestBlock = pgb.LtcBestBlock
 	case mutilchain.TYPEBTC:
-		return pgb.BtcBestBlock.MutilchainTime()
-	default:
+		bestBlock = pgb.BtcBestBlock
+	case mutilchain.TYPEXMR:
+		bestBlock = pgb.XmrBestBlock
+	}
+	if bestBlock == nil {
 		return 0
 	}
+	return bestBlock.MutilchainTime()
 }
 
 // Height uses the last stored height.
@@ -3589,7 +3658,8 @@ func (pgb *ChainDB) GetContractSwapDataByGroup(groupTx, targetTokenString string
 	return cSwapData, nil
 }
 
-// GetAtomicSwapList fetches filtered atomic swap list.
+// GetAtomicSwapList fetches filtered atomic swap list, ordered by the
+// contract time of the first contract of each group, most recent first.
 func (pgb *ChainDB) GetAtomicSwapList(n, offset int64, pair, status, searchKey string) (swaps []*dbtypes.AtomicSwapFullData, allFilterCount int64, err error) {
 	// get count all atomic swaps with filter pair, status
 	if searchKey != "" {
@@ -3617,9 +3687,9 @@ func (pgb *ChainDB) GetAtomicSwapList(n, offset int64, pair, status, searchKey s
 	defer rows.Close()
 	for rows.Next() {
 		var groupTx string
+		var contractTime int64
 		var targetToken sql.NullString
-		var isRefund bool
-		err = rows.Scan(&groupTx, &targetToken, &isRefund)
+		err = rows.Scan(&groupTx, &contractTime, &targetToken)
 		if err != nil {
 			return
 		}
@@ -3638,6 +3708,58 @@ func (pgb *ChainDB) GetAtomicSwapList(n, offset int64, pair, status, searchKey s
 	return
 }
 
+// GetAtomicSwapListByCursor is like GetAtomicSwapList, but for the n contract
+// groups that follow the page cursor, in the same order. The encoded cursor of
+// the next page is also returned, or an empty string for the last page.
+func (pgb *ChainDB) GetAtomicSwapListByCursor(n int64, cursor *dbtypes.PageCursor, pair, status,
+	searchKey string) (swaps []*dbtypes.AtomicSwapFullData, allFilterCount int64, nextCursor string, err error) {
+	// get count all atomic swaps with filter pair, status
+	if searchKey != "" {
+		err = pgb.db.QueryRow(internal.MakeCountAtomicSwapsRowWithSearchFilter(pair, status), searchKey).Scan(&allFilterCount)
+	} else {
+		err = pgb.db.QueryRow(internal.MakeCountAtomicSwapsRowWithFilter(pair, status)).Scan(&allFilterCount)
+	}
+	if err != nil {
+		log.Errorf("Get count atomic swaps faled: %v", err)
+		return
+	}
+	var rows *sql.Rows
+	query := internal.MakeSelectAtomicSwapsContractGroupByCursor(pair, status, searchKey != "")
+	if searchKey != "" {
+		rows, err = pgb.db.QueryContext(pgb.ctx, query, searchKey, cursor.Time, cursor.Hash, n)
+	} else {
+		rows, err = pgb.db.QueryContext(pgb.ctx, query, cursor.Time, cursor.Hash, n)
+	}
+	if err != nil {
+		log.Errorf("Get atomic swaps list faled: %v", err)
+		return
+	}
+
+	defer rows.Close()
+	var last *dbtypes.PageCursor
+	for rows.Next() {
+		var groupTx string
+		var contractTime int64
+		var targetToken sql.NullString
+		err = rows.Scan(&groupTx, &contractTime, &targetToken)
+		if err != nil {
+			return
+		}
+		var swapItem *dbtypes.AtomicSwapFullData
+		swapItem, err = pgb.GetContractSwapDataByGroup(groupTx, targetToken.String)
+		if err != nil {
+			return
+		}
+		swaps = append(swaps, swapItem)
+		last = &dbtypes.PageCursor{Time: contractTime, Hash: groupTx}
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	nextCursor = dbtypes.NextPageCursor(last, len(swaps), int(n))
+	return
+}
+
 func (pgb *ChainDB) GetAtomicSwapSummary() (txCount, amount, oldestContract int64, err error) {
 	// get count all atomic swaps
 	err = pgb.db.QueryRow(internal.CountAtomicSwapsRow).Scan(&txCount)
@@ -3945,6 +4067,62 @@ func (pgb *ChainDB) TreasuryTxnsWithPeriod(n, offset int64, txType stake.TxType,
 	return txns, nil
 }
 
+// TreasuryTxnsByCursor fetches at most n mainchain treasury transactions that
+// follow the page cursor, most recent first, optionally filtered by type (-1
+// for all types) and by year and month. The encoded cursor of the next page is
+// also returned, or an empty string for the last page.
+func (pgb *ChainDB) TreasuryTxnsByCursor(n int64, cursor *dbtypes.PageCursor, txType stake.TxType,
+	year int64, month int64) ([]*dbtypes.TreasuryTx, string, error) {
+	typed, byYear, byMonth := txType != -1, year != 0, year != 0 && month != 0
+	args := []any{cursor.Height, cursor.TxIndex, n}
+	if typed {
+		args = append(args, txType)
+	}
+	if byYear {
+		args = append(args, year)
+	}
+	if byMonth {
+		args = append(args, month)
+	}
+	query := internal.MakeSelectTreasuryTxnsByCursor(typed, byYear, byMonth)
+	rows, err := pgb.db.QueryContext(pgb.ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var txns []*dbtypes.TreasuryTx
+	var last *dbtypes.PageCursor
+	var numRows int
+	for rows.Next() {
+		var tx dbtypes.TreasuryTx
+		var mainchain bool
+		var txIndex int64
+		err = rows.Scan(&tx.TxID, &tx.Type, &tx.Amount, &tx.BlockHash, &tx.BlockHeight, &tx.BlockTime, &mainchain, &txIndex)
+		if err != nil {
+			return nil, "", err
+		}
+		// The cursor advances past the row even if it is skipped below.
+		last = &dbtypes.PageCursor{Height: tx.BlockHeight, TxIndex: txIndex}
+		numRows++
+		// get vote info if tx is tspend
+		if tx.Type == int(stake.TxTypeTSpend) {
+			tspendMeta, err := pgb.getTSpendSimpleVoteInfo(tx.TxID)
+			if err != nil {
+				log.Warnf("Get Tspend vote info failed. TxID: %s, Error: %v", tx.TxID, err)
+				continue
+			}
+			tx.TSpendMeta = tspendMeta
+		}
+		txns = append(txns, &tx)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, "", err
+	}
+	return txns, dbtypes.NextPageCursor(last, numRows, int(n)), nil
+}
+
 // Get Simple tspend vote info (yes, no, total votes, approval rate)
 func (pgb *ChainDB) getTSpendSimpleVoteInfo(txHash string) (*dbtypes.TreasurySpendVotesSummaryData, error) {
 	var res dbtypes.TreasurySpendVotesSummaryData
@@ -4613,8 +4791,61 @@ func (pgb *ChainDB) AddressHistory(address string, N, offset int64,
 	return addressRows, balance, nil
 }
 
+// AddressHistoryByCursor queries the database for at most N rows of the
+// addresses table for the given address that follow the page cursor, for all
+// transactions, credits or debits. The encoded cursor of the next page is also
+// returned, or an empty string for the last page. Unlike AddressHistory, the
+// address rows cache is not used. Each page is a range scan of the addresses
+// index on (address, block_time, id) that starts at the cursor, so deep pages
+// cost the same as the first one.
+func (pgb *ChainDB) AddressHistoryByCursor(address string, N int64, cursor *dbtypes.PageCursor,
+	txnView dbtypes.AddrTxnViewType) ([]*dbtypes.AddressRow, *dbtypes.AddressBalance, string, error) {
+	_, err := stdaddr.DecodeAddress(address, pgb.chainParams)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var credit, debit bool
+	switch txnView {
+	case dbtypes.AddrTxnAll:
+	case dbtypes.AddrTxnCredit:
+		credit = true
+	case dbtypes.AddrTxnDebit:
+		debit = true
+	default:
+		return nil, nil, "", fmt.Errorf("cursor pagination is not supported for the %s view", txnView.String())
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	addressRows, last, err := RetrieveAddressTxnsByCursor(ctx, pgb.db, address, N, cursor, credit, debit)
+	if err != nil {
+		return nil, nil, "", pgb.replaceCancelError(err)
+	}
+
+	balance, _, err := pgb.AddressBalance(address)
+	if err != nil && !errors.Is(err, dbtypes.ErrNoResult) && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, "", err
+	}
+
+	return addressRows, balance, dbtypes.NextPageCursor(last, len(addressRows), int(N)), nil
+}
+
 // AddressData returns comprehensive, paginated information for an address.
 func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
+	txnType dbtypes.AddrTxnViewType, year int64, month int64) (addrData *dbtypes.AddressInfo, err error) {
+	return pgb.addressData(address, limitN, offsetAddrOuts, nil, txnType, year, month)
+}
+
+// AddressDataByCursor is like AddressData, but for the page of the address
+// transactions that follows the page cursor. The cursor of the next page is
+// set in the NextCursor field of the returned AddressInfo.
+func (pgb *ChainDB) AddressDataByCursor(address string, limitN int64, cursor *dbtypes.PageCursor,
+	txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, error) {
+	return pgb.addressData(address, limitN, 0, cursor, txnType, 0, 0)
+}
+
+func (pgb *ChainDB) addressData(address string, limitN, offsetAddrOuts int64, cursor *dbtypes.PageCursor,
 	txnType dbtypes.AddrTxnViewType, year int64, month int64) (addrData *dbtypes.AddressInfo, err error) {
 	_, addrType, addrErr := txhelpers.AddressValidation(address, pgb.chainParams)
 	if addrErr != nil && !errors.Is(err, txhelpers.AddressErrorNoError) {
@@ -4626,7 +4857,14 @@ func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
 		return nil, err
 	}
 
-	addrHist, balance, err := pgb.AddressHistory(address, limitN, offsetAddrOuts, txnType, year, month)
+	var addrHist []*dbtypes.AddressRow
+	var balance *dbtypes.AddressBalance
+	var nextCursor string
+	if cursor != nil {
+		addrHist, balance, nextCursor, err = pgb.AddressHistoryByCursor(address, limitN, cursor, txnType)
+	} else {
+		addrHist, balance, err = pgb.AddressHistory(address, limitN, offsetAddrOuts, txnType, year, month)
+	}
 	//if have period, get separator balance
 	if year != 0 {
 		balance, err = RetrieveAddressBalancePeriod(pgb.ctx, pgb.db, address, txnType, year, month)
@@ -4640,6 +4878,7 @@ func (pgb *ChainDB) AddressData(address string, limitN, offsetAddrOuts int64,
 		addrData.Offset = offsetAddrOuts
 		addrData.Limit = limitN
 		addrData.TxnType = txnType.String()
+		addrData.NextCursor = nextCursor
 		addrData.Address = address
 	}
 
@@ -5269,7 +5508,8 @@ func (pgb *ChainDB) AddressTotals(address string) (*apitypes.AddressTotals, erro
 	}, nil
 }
 
-func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, *dbtypes.AddressBalance, error) {
+func (pgb *ChainDB) addressInfo(addr string, count, skip int64, cursor *dbtypes.PageCursor,
+	txnType dbtypes.AddrTxnViewType) (*dbtypes.AddressInfo, *dbtypes.AddressBalance, error) {
 	address, err := stdaddr.DecodeAddress(addr, pgb.chainParams)
 	if err != nil {
 		log.Infof("Invalid address %s: %v", addr, err)
@@ -5277,7 +5517,14 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 	}
 
 	// Get rows from the addresses table for the address
-	addrHist, balance, err := pgb.AddressHistory(addr, count, skip, txnType, 0, 0)
+	var addrHist []*dbtypes.AddressRow
+	var balance *dbtypes.AddressBalance
+	var nextCursor string
+	if cursor != nil {
+		addrHist, balance, nextCursor, err = pgb.AddressHistoryByCursor(addr, count, cursor, txnType)
+	} else {
+		addrHist, balance, err = pgb.AddressHistory(addr, count, skip, txnType, 0, 0)
+	}
 	if err != nil {
 		log.Errorf("Unable to get address %s history: %v", address, err)
 		return nil, nil, err
@@ -5312,6 +5559,7 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 	if err != nil {
 		return nil, balance, fmt.Errorf("Unable to fill address %s transactions: %w", address, err)
 	}
+	addrData.NextCursor = nextCursor
 
 	return addrData, balance, nil
 }
@@ -5322,8 +5570,21 @@ func (pgb *ChainDB) addressInfo(addr string, count, skip int64, txnType dbtypes.
 // transactions.
 func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
 	txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error) {
+	return pgb.addressTransactionDetails(addr, count, skip, nil, txnType)
+}
+
+// AddressTransactionDetailsByCursor is like AddressTransactionDetails, but for
+// the count transactions that follow the page cursor. The cursor of the next
+// page is set in the NextCursor field of the returned Address.
+func (pgb *ChainDB) AddressTransactionDetailsByCursor(addr string, count int64,
+	cursor *dbtypes.PageCursor, txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error) {
+	return pgb.addressTransactionDetails(addr, count, 0, cursor, txnType)
+}
+
+func (pgb *ChainDB) addressTransactionDetails(addr string, count, skip int64,
+	cursor *dbtypes.PageCursor, txnType dbtypes.AddrTxnViewType) (*apitypes.Address, error) {
 	// Fetch address history for given transaction range and type
-	addrData, _, err := pgb.addressInfo(addr, count, skip, txnType)
+	addrData, _, err := pgb.addressInfo(addr, count, skip, cursor, txnType)
 	if err != nil {
 		return nil, err
 	}
@@ -5352,6 +5613,7 @@ func (pgb *ChainDB) AddressTransactionDetails(addr string, count, skip int64,
 	return &apitypes.Address{
 		Address:      addr,
 		Transactions: txsShort,
+		NextCursor:   addrData.NextCursor,
 	}, nil
 }
 
@@ -5559,10 +5821,18 @@ func (pgb *ChainDB) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBloc
 		return err
 	}
 	log.Infof("Store block data complete. Block height: %d", blockData.Header.Height)
+	if err = pgb.SyncMixStatsForBlock(int64(msgBlock.Header.Height)); err != nil {
+		log.Errorf("Sync mix_stats for block %d failed. %v", msgBlock.Header.Height, err)
+	}
 	// Signal updates to any subscribed heightClients.
 	pgb.SignalHeight(msgBlock.Header.Height)
 	log.Infof("Start syncing coin age bands/mean coin age data in the background. Height: %d.", msgBlock.Header.Height)
 	go pgb.SyncCoinAgeDataAllSet(int64(msgBlock.Header.Height))
+	go func() {
+		if err := pgb.SyncUtxoSet(mutilchain.TYPEDCR); err != nil {
+			log.Errorf("UTXO set sync failed: %v", err)
+		}
+	}()
 	return nil
 }
 
@@ -5772,7 +6042,18 @@ func (pgb *ChainDB) LTCStore(blockData *blockdataltc.BlockData, msgBlock *ltcwir
 			return err
 		}
 		if pgb.SyncChainDBFlag {
-			go pgb.SyncOneLTCWholeBlock(pgb.LtcClient, msgBlock)
+			go func() {
+				if err := pgb.SyncOneLTCWholeBlock(pgb.LtcClient, msgBlock); err != nil {
+					log.Errorf("LTC: sync for whole block failed. Height: %d. Err: %v", blockData.Header.Height, err)
+					return
+				}
+				if err := pgb.SyncMultichainCoinAge(mutilchain.TYPELTC); err != nil {
+					log.Errorf("LTC: coin age sync failed: %v", err)
+				}
+				if err := pgb.SyncUtxoSet(mutilchain.TYPELTC); err != nil {
+					log.Errorf("LTC: UTXO set sync failed: %v", err)
+				}
+			}()
 		}
 		// if err != nil {
 		// 	log.Errorf("LTC: sync for whole block failed. Height: %d. Err: %v", blockData.Header.Height, err)
@@ -5819,22 +6100,33 @@ func (pgb *ChainDB) BTCStore(blockData *blockdatabtc.BlockData, msgBlock *btcwir
 			return err
 		}
 		if pgb.SyncChainDBFlag {
-			go pgb.SyncOneBTCWholeBlock(pgb.BtcClient, msgBlock)
+			go func() {
+				if err := pgb.SyncOneBTCWholeBlock(pgb.BtcClient, msgBlock); err != nil {
+					log.Errorf("BTC: sync for whole block failed. Height: %d. Err: %v", blockData.Header.Height, err)
+					return
+				}
+				if err := pgb.SyncMultichainCoinAge(mutilchain.TYPEBTC); err != nil {
+					log.Errorf("BTC: coin age sync failed: %v", err)
+				}
+				if err := pgb.SyncUtxoSet(mutilchain.TYPEBTC); err != nil {
+					log.Errorf("BTC: UTXO set sync failed: %v", err)
+				}
+			}()
 		}
 		// if err != nil {
 		// 	log.Errorf("BTC: sync for whole block failed. Height: %d. Err: %v", blockData.Header.Height, err)
 		// 	return err
 		// }
 	} else {
-		if !pgb.BTC20BlocksSyncing {
+		if !pgb.BTCRecentBlocksSyncing {
 			go func() {
-				err := pgb.SyncLast20BTCBlocks(blockData.Header.Height)
+				err := pgb.SyncRecentBTCBlocks(blockData.Header.Height)
 				if err != nil {
 					log.Error(err)
 				} else {
-					log.Infof("Sync last 20 BTC Blocks successfully")
+					log.Infof("Sync of the recent BTC blocks finished successfully")
 				}
-				pgb.BTC20BlocksSyncing = false
+				pgb.BTCRecentBlocksSyncing = false
 			}()
 		}
 	}
@@ -6121,6 +6413,19 @@ func (pgb *ChainDB) chartXmrMutilchainBlocks(charts *cache.MutilchainChartData)
 	return rows, cancel, nil
 }
 
+// chartMutilchainCoinAge fetches the BTC or LTC coin age chart data from
+// retrieveMutilchainCoinAge. This is the Fetcher half of a pair that make up a
+// cache.ChartMutilchainUpdater. The Appender half is appendMutilchainCoinAge.
+func (pgb *ChainDB) chartMutilchainCoinAge(charts *cache.MutilchainChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	rows, err := retrieveMutilchainCoinAge(ctx, pgb.db, charts)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("%s: chartCoinAge: %w", charts.ChainType, pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
 // coinSupply fetches the coin supply chart data from retrieveCoinSupply.
 // This is the Fetcher half of a pair that make up a cache.ChartUpdater. The
 // Appender half is appendCoinSupply.
@@ -8282,6 +8587,12 @@ func (pgb *ChainDB) GetMultichainTransactionVerbose(txid, chainType string) (any
 		return pgb.GetBTCAPITransaction(txid)
 	case mutilchain.TYPELTC:
 		return pgb.GetLTCAPITransaction(txid)
+	case mutilchain.TYPEXMR:
+		tx, err := pgb.xmrTransaction(txid)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(tx.AsJSON), nil
 	}
 	return nil, fmt.Errorf("GetMultichainTransactionVerbose chaintype invalid")
 }
@@ -23,3 +23,30 @@ func TestIsRetryError(t *testing.T) {
 		})
 	}
 }
+
+func TestMutilchainUpgrades(t *testing.T) {
+	if len(mutilchainUpgrades) != mutilchainSchemaVersion {
+		t.Fatalf("%d upgrades for schema version %d", len(mutilchainUpgrades), mutilchainSchemaVersion)
+	}
+
+	tests := []struct {
+		name    string
+		ver     DatabaseVersion
+		wantErr bool
+	}{
+		{"legacy", *legacyMutilchainVersion, false},
+		{"current", *targetMutilchainVersion, false},
+		{"newer schema", NewDatabaseVersion(mutilchainCompatVersion, mutilchainSchemaVersion+1, 0), true},
+		{"newer maint", NewDatabaseVersion(mutilchainCompatVersion, mutilchainSchemaVersion, mutilchainMaintVersion+1), true},
+		{"newer compat", NewDatabaseVersion(mutilchainCompatVersion+1, 0, 0), true},
+		{"older compat", NewDatabaseVersion(mutilchainCompatVersion-1, 0, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkMutilchainVersion("btc", &tt.ver)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkMutilchainVersion(%v) = %v, want error %v", tt.ver, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -29,6 +29,15 @@ import (
 // 	t.Log(bal, spent, txCount, err)
 // }
 
+// swapTxGetter is a BlockGetter of the contract transactions of swaps.
+type swapTxGetter struct {
+	BlockGetter
+}
+
+func (swapTxGetter) GetRawTransactionVerbose(_ context.Context, txHash *chainhash.Hash) (*chainjson.TxRawResult, error) {
+	return &chainjson.TxRawResult{Txid: txHash.String(), Time: 1621787000}, nil
+}
+
 func TestInsertSwap(t *testing.T) {
 	contractHash := chainhash.Hash{1, 2}
 	spendHash := chainhash.Hash{3, 4}
@@ -53,7 +62,7 @@ func TestInsertSwap(t *testing.T) {
 		Contract:         []byte{1, 2, 3, 4, 5, 6, 7, 8}, // not stored
 		IsRefund:         true,
 	}
-	err = InsertSwap(db.db, 1234, asd)
+	err = InsertSwap(db.db, context.Background(), swapTxGetter{}, 1234, asd, asd.IsRefund)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -61,7 +70,7 @@ func TestInsertSwap(t *testing.T) {
 	asd.SpendTx = &chainhash.Hash{5, 6}
 	asd.SpendVin = 2
 	asd.Secret = nil
-	err = InsertSwap(db.db, 1234, asd)
+	err = InsertSwap(db.db, context.Background(), swapTxGetter{}, 1234, asd, asd.IsRefund)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -860,6 +860,19 @@ func checkExistAndCreateLtcSwapsTable(db *sql.DB) error {
 	return err
 }
 
+// CheckCreateSwapsTables creates the swaps, btc_swaps and ltc_swaps tables if
+// they do not already exist. This allows standalone tools such as swapscan to
+// write swap data without constructing a full ChainDB.
+func CheckCreateSwapsTables(db *sql.DB) error {
+	if err := createTable(db, "swaps", internal.CreateAtomicSwapTable); err != nil {
+		return err
+	}
+	if err := checkExistAndCreateBtcSwapsTable(db); err != nil {
+		return err
+	}
+	return checkExistAndCreateLtcSwapsTable(db)
+}
+
 // Check exist and create monthly_price table
 func checkExistAndCreateMonthlyPriceTable(db *sql.DB) error {
 	err := createTable(db, "monthly_price", internal.CreateMonthlyPriceTable)
@@ -2332,6 +2345,56 @@ func retrieveAddressTxns(ctx context.Context, db *sql.DB, address string, N, off
 	}
 }
 
+// RetrieveAddressTxnsByCursor retrieves at most N valid mainchain rows of the
+// addresses table for the given address that follow the page cursor, most
+// recent first. Only credit or debit rows are retrieved if credit or debit are
+// true. The cursor of the last row is also returned, or nil if there are no
+// rows.
+func RetrieveAddressTxnsByCursor(ctx context.Context, db *sql.DB, address string, N int64,
+	cursor *dbtypes.PageCursor, credit, debit bool) ([]*dbtypes.AddressRow, *dbtypes.PageCursor, error) {
+	rows, err := db.QueryContext(ctx, internal.MakeSelectAddressRowsByCursor(credit, debit),
+		address, cursor.CursorTime(), cursor.ID, N)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeRows(rows)
+
+	var addressRows []*dbtypes.AddressRow
+	var last *dbtypes.PageCursor
+	for rows.Next() {
+		var id uint64
+		var addr dbtypes.AddressRow
+		var matchingTxHash sql.NullString
+		var txVinIndex, vinDbID sql.NullInt64
+		err = rows.Scan(&id, &addr.Address, &matchingTxHash, &addr.TxHash, &addr.TxType,
+			&addr.ValidMainChain, &txVinIndex, &addr.TxBlockTime, &vinDbID,
+			&addr.Value, &addr.IsFunding)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if addr.IsFunding {
+			addr.AtomsCredit = addr.Value
+		} else {
+			addr.AtomsDebit = addr.Value
+		}
+		if matchingTxHash.Valid {
+			addr.MatchingTxHash = matchingTxHash.String
+		}
+		if txVinIndex.Valid {
+			addr.TxVinVoutIndex = uint32(txVinIndex.Int64)
+		}
+		if vinDbID.Valid {
+			addr.VinVoutDbID = uint64(vinDbID.Int64)
+		}
+
+		addressRows = append(addressRows, &addr)
+		last = dbtypes.NewAddressRowCursor(addr.TxBlockTime.T, id)
+	}
+
+	return addressRows, last, rows.Err()
+}
+
 func retrieveMutilchainAddressTxns(ctx context.Context, db *sql.DB, address string, N, offset int64,
 	statement string) ([]*dbtypes.MutilchainAddressRow, error) {
 	var rows *sql.Rows
@@ -4211,6 +4274,80 @@ func retrieveXmrMutilchainChartBlocks(ctx context.Context, db *sql.DB, charts *c
 	return rows, nil
 }
 
+// retrieveMutilchainCoinAge fetches the coin age data of the BTC or LTC blocks
+// above the coin age tip of the charts. All of it is fetched again if the block
+// at that tip changed, after a reorg or a rebuild of the coin age tables.
+func retrieveMutilchainCoinAge(ctx context.Context, db *sql.DB, charts *cache.MutilchainChartData) (*sql.Rows, error) {
+	start := int64(charts.CoinAgeTip()) + 1
+	if start > 0 {
+		var hash string
+		err := db.QueryRowContext(ctx, mutilchainquery.MakeSelectCoinAgeHash(charts.ChainType),
+			start-1).Scan(&hash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if hash != charts.CoinAgeTipHash {
+			start = 0
+		}
+	}
+	return db.QueryContext(ctx, mutilchainquery.MakeSelectCoinAgeChartRows(charts.ChainType), start)
+}
+
+// appendMutilchainCoinAge appends the results from retrieveMutilchainCoinAge
+// to the CoinAge data of the provided MutilchainChartData, in coins and days.
+// The data from the start height of the results is replaced, even if there are
+// no blocks from it. This is the Appender half of a pair that make up a
+// cache.ChartMutilchainUpdater.
+func appendMutilchainCoinAge(charts *cache.MutilchainChartData, rows *sql.Rows) error {
+	defer closeRows(rows)
+	blocks := charts.CoinAge
+	for rows.Next() {
+		var start, height int64
+		var hash string
+		var blockTime uint64
+		var cdd, avgAge, totalCoinDays, meanAge float64
+		var bands [10]float64
+		err := rows.Scan(&start, &height, &hash, &blockTime, &cdd, &avgAge, &totalCoinDays, &meanAge,
+			&bands[0], &bands[1], &bands[2], &bands[3], &bands[4], &bands[5], &bands[6],
+			&bands[7], &bands[8], &bands[9])
+		if err != nil {
+			return err
+		}
+		if height < 0 {
+			// The marker row. Drop the data being fetched again, or removed
+			// by a rebuild of the tables.
+			if int(start) < len(blocks.Time) {
+				blocks.Snip(int(start))
+				charts.CoinAgeTipHash = ""
+			}
+			continue
+		}
+		if int(height) != len(blocks.Time) {
+			return fmt.Errorf("coin age of block %d follows block %d", height, len(blocks.Time)-1)
+		}
+		blocks.Height = append(blocks.Height, uint64(height))
+		blocks.Time = append(blocks.Time, blockTime)
+		blocks.CoinDaysDestroyed = append(blocks.CoinDaysDestroyed, cdd/atomsPerCoin)
+		blocks.AvgCoinAge = append(blocks.AvgCoinAge, avgAge)
+		blocks.TotalCoinDays = append(blocks.TotalCoinDays, totalCoinDays/atomsPerCoin)
+		blocks.MeanCoinAge = append(blocks.MeanCoinAge, meanAge)
+		blocks.CoinAgeBands = append(blocks.CoinAgeBands, &dbtypes.AgeBandData{
+			Less1Day:         bands[0] / atomsPerCoin,
+			DayToWeek:        bands[1] / atomsPerCoin,
+			WeekToMonth:      bands[2] / atomsPerCoin,
+			MonthToHalfYear:  bands[3] / atomsPerCoin,
+			HalfYearToYear:   bands[4] / atomsPerCoin,
+			YearTo2Year:      bands[5] / atomsPerCoin,
+			TwoYearTo3Year:   bands[6] / atomsPerCoin,
+			ThreeYearTo5Year: bands[7] / atomsPerCoin,
+			FiveYearTo7Year:  bands[8] / atomsPerCoin,
+			GreaterThan7Year: bands[9] / atomsPerCoin,
+		})
+		charts.CoinAgeTipHash = hash
+	}
+	return rows.Err()
+}
+
 // Append the results from retrieveChartBlocks to the provided ChartData.
 // This is the Appender half of a pair that make up a cache.ChartUpdater.
 func appendChartBlocks(charts *cache.ChartData, rows *sql.Rows) error {
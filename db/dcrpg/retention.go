@@ -0,0 +1,318 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+const (
+	// defaultRecentBlocks is the number of blocks before the tip synced at a
+	// new block when the inputs and outputs of all the blocks are kept.
+	defaultRecentBlocks = 25
+	// defaultPruneBatchRows is the maximum number of rows deleted per
+	// statement when none is configured.
+	defaultPruneBatchRows = 5000
+	// pruneHeightStep is the number of blocks of the height ranges pruned in
+	// turn.
+	pruneHeightStep = 100
+)
+
+// RetentionPolicy is the window of blocks whose rows are kept in the tables of
+// a BTC or LTC chain when the whole chain is not synced. The raw rows are the
+// inputs, outputs and transactions of the blocks, and the address rows those
+// of the outputs funded in the blocks. Each window is set by a number of blocks
+// before the tip and/or an age, and keeps the rows of a block if either keeps
+// it. A window with neither keeps all the rows. The transactions are kept as
+// long as the address rows need them.
+type RetentionPolicy struct {
+	Blocks        int64
+	Age           time.Duration
+	AddressBlocks int64
+	AddressAge    time.Duration
+}
+
+func (p RetentionPolicy) String() string {
+	window := func(blocks int64, age time.Duration) string {
+		var s []string
+		if blocks > 0 {
+			s = append(s, fmt.Sprintf("%d blocks", blocks))
+		}
+		if age > 0 {
+			s = append(s, age.String())
+		}
+		if len(s) == 0 {
+			return "all"
+		}
+		return strings.Join(s, " or ")
+	}
+	return fmt.Sprintf("raw rows: %s, address rows: %s", window(p.Blocks, p.Age),
+		window(p.AddressBlocks, p.AddressAge))
+}
+
+// chainPruner prunes the tables of a chain to its retention policy in the
+// background, when triggered with the height of a new tip.
+type chainPruner struct {
+	policy  RetentionPolicy
+	spacing time.Duration
+	trigger chan int64
+
+	mtx    sync.RWMutex
+	window dbtypes.RetentionWindow
+
+	// The heights below which the raw, transaction and address rows were
+	// pruned since startup, or -1.
+	rawFloor, txFloor, addrFloor int64
+}
+
+func newChainPruner(chainType string, policy RetentionPolicy, spacing time.Duration) *chainPruner {
+	return &chainPruner{
+		policy:    policy,
+		spacing:   spacing,
+		trigger:   make(chan int64, 1),
+		window:    dbtypes.RetentionWindow{Chain: chainType},
+		rawFloor:  -1,
+		txFloor:   -1,
+		addrFloor: -1,
+	}
+}
+
+// MultichainRetentionWindow returns the retention window of the BTC or LTC
+// tables. The window is not pruned when the whole chain is synced.
+func (pgb *ChainDB) MultichainRetentionWindow(chainType string) *dbtypes.RetentionWindow {
+	p := pgb.pruners[chainType]
+	if p == nil {
+		return &dbtypes.RetentionWindow{Chain: chainType}
+	}
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	window := p.window
+	return &window
+}
+
+// TriggerRetentionPrune requests the pruning of the tables of a chain to its
+// retention window at the given tip height. It does not wait for the pruning,
+// and the request is dropped if one is already pending.
+func (pgb *ChainDB) TriggerRetentionPrune(chainType string, tip int64) {
+	p := pgb.pruners[chainType]
+	if p == nil {
+		return
+	}
+	select {
+	case p.trigger <- tip:
+	default:
+	}
+}
+
+// RunRetentionPruner prunes the tables of a chain to its retention window each
+// time it is triggered, until the ChainDB's context is canceled. It returns
+// immediately if the tables of the chain are not pruned.
+func (pgb *ChainDB) RunRetentionPruner(chainType string) {
+	p := pgb.pruners[chainType]
+	if p == nil {
+		return
+	}
+	log.Infof("%s: Retention of the DB tables: %v", strings.ToUpper(chainType), p.policy)
+	for {
+		select {
+		case <-pgb.ctx.Done():
+			return
+		case tip := <-p.trigger:
+			start := time.Now()
+			n, err := pgb.pruneChain(chainType, p, tip)
+			if err != nil {
+				if pgb.ctx.Err() == nil {
+					log.Errorf("%s: Pruning failed: %v", strings.ToUpper(chainType), err)
+				}
+				continue
+			}
+			if n > 0 {
+				log.Debugf("%s: Pruned %d rows in %v", strings.ToUpper(chainType), n,
+					time.Since(start).Round(time.Millisecond))
+			}
+		}
+	}
+}
+
+// retentionCutoff returns the first height kept by a window of blocks and age
+// at the tip height, or -1 if all the blocks are kept. The age is resolved
+// with the block times of the chain, or with its target block spacing if no
+// block is older.
+func (pgb *ChainDB) retentionCutoff(chainType string, tip, blocks int64, age, spacing time.Duration) (int64, error) {
+	cutoff := int64(-1)
+	if blocks > 0 {
+		cutoff = tip - blocks
+		if cutoff < 0 {
+			cutoff = 0
+		}
+	}
+	if age > 0 {
+		estimate := tip - int64(age/spacing)
+		var h int64
+		err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectRetentionAgeCutoff(chainType),
+			time.Now().Add(-age).Unix(), estimate).Scan(&h)
+		if err != nil {
+			return 0, err
+		}
+		if h < 0 {
+			h = 0
+		}
+		if cutoff < 0 || h < cutoff {
+			cutoff = h
+		}
+	}
+	return cutoff, nil
+}
+
+// recentBlocksStart returns the first height of the blocks synced at a new
+// tip of a chain when the whole chain is not synced, i.e. the start of the
+// window of the raw rows.
+func (pgb *ChainDB) recentBlocksStart(chainType string, tip int64) int64 {
+	start := tip - defaultRecentBlocks
+	if p := pgb.pruners[chainType]; p != nil {
+		cutoff, err := pgb.retentionCutoff(chainType, tip, p.policy.Blocks, p.policy.Age, p.spacing)
+		if err != nil {
+			log.Errorf("%s: Retention window: %v", strings.ToUpper(chainType), err)
+		} else if cutoff >= 0 {
+			start = cutoff
+		}
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+// pruneChain deletes the rows of a chain beyond its retention window at the
+// tip height, returning the number of rows deleted.
+func (pgb *ChainDB) pruneChain(chainType string, p *chainPruner, tip int64) (int64, error) {
+	rawCutoff, err := pgb.retentionCutoff(chainType, tip, p.policy.Blocks, p.policy.Age, p.spacing)
+	if err != nil {
+		return 0, err
+	}
+	addrCutoff, err := pgb.retentionCutoff(chainType, tip, p.policy.AddressBlocks, p.policy.AddressAge, p.spacing)
+	if err != nil {
+		return 0, err
+	}
+	// The transactions are kept while either window keeps them.
+	txCutoff := rawCutoff
+	if addrCutoff < txCutoff {
+		txCutoff = addrCutoff
+	}
+
+	window := dbtypes.RetentionWindow{
+		Chain:  chainType,
+		Pruned: rawCutoff > 0 || addrCutoff > 0,
+	}
+	cutoffs := []struct {
+		height     int64
+		fromHeight *int64
+		fromTime   *int64
+	}{
+		{rawCutoff, &window.RawFromHeight, &window.RawFromTime},
+		{txCutoff, &window.TxFromHeight, &window.TxFromTime},
+		{addrCutoff, &window.AddressFromHeight, &window.AddressFromTime},
+	}
+	for _, c := range cutoffs {
+		if c.height <= 0 {
+			continue
+		}
+		*c.fromHeight = c.height
+		err = pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectRetentionBlockTime(chainType),
+			c.height).Scan(c.fromTime)
+		if err != nil {
+			return 0, err
+		}
+	}
+	p.mtx.Lock()
+	p.window = window
+	p.mtx.Unlock()
+
+	var total int64
+	for _, stmt := range mutilchainquery.MakePruneRawRows(chainType) {
+		floor := p.rawFloor
+		n, err := pgb.pruneRows(chainType, stmt, &floor, rawCutoff)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	if rawCutoff > p.rawFloor {
+		p.rawFloor = rawCutoff
+	}
+	n, err := pgb.pruneRows(chainType, mutilchainquery.MakePruneAddresses(chainType), &p.addrFloor, addrCutoff)
+	total += n
+	if err != nil {
+		return total, err
+	}
+	n, err = pgb.pruneRows(chainType, mutilchainquery.MakePruneTransactions(chainType), &p.txFloor, txCutoff)
+	return total + n, err
+}
+
+// pruneRows runs a prune statement for the blocks from the floor up to the
+// cutoff height, in ranges of blocks and batches of rows, moving the floor up
+// as the ranges are done.
+func (pgb *ChainDB) pruneRows(chainType, stmt string, floor *int64, cutoff int64) (int64, error) {
+	if cutoff <= 0 || *floor >= cutoff {
+		return 0, nil
+	}
+	if *floor < 0 {
+		err := pgb.db.QueryRowContext(pgb.ctx, mutilchainquery.MakeSelectRetentionMinHeight(chainType)).Scan(floor)
+		if err != nil {
+			return 0, err
+		}
+	}
+	batchRows := int64(pgb.pruneBatchRows)
+	if batchRows <= 0 {
+		batchRows = defaultPruneBatchRows
+	}
+	var total int64
+	for *floor < cutoff {
+		hi := *floor + pruneHeightStep
+		if hi > cutoff {
+			hi = cutoff
+		}
+		for {
+			res, err := pgb.db.ExecContext(pgb.ctx, stmt, *floor, hi, batchRows)
+			if err != nil {
+				return total, err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return total, err
+			}
+			total += n
+			if n < batchRows {
+				break
+			}
+		}
+		*floor = hi
+	}
+	return total, nil
+}
+
+// newChainPruners returns the pruners of the BTC and LTC tables, unless the
+// whole chains are synced.
+func newChainPruners(cfg *ChainDBCfg) map[string]*chainPruner {
+	pruners := make(map[string]*chainPruner)
+	if cfg.SyncChainDBFlag {
+		return pruners
+	}
+	if cfg.BTCParams != nil {
+		pruners[mutilchain.TYPEBTC] = newChainPruner(mutilchain.TYPEBTC, cfg.BTCRetention,
+			cfg.BTCParams.TargetTimePerBlock)
+	}
+	if cfg.LTCParams != nil {
+		pruners[mutilchain.TYPELTC] = newChainPruner(mutilchain.TYPELTC, cfg.LTCRetention,
+			cfg.LTCParams.TargetTimePerBlock)
+	}
+	return pruners
+}
@@ -0,0 +1,148 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/mutilchain"
+)
+
+// seedRetentionTestBlocks inserts the blocks of the heights [0, numBlocks),
+// one hour apart up to an hour ago, with two transactions of each height in
+// [0, numTxHeights). The test is skipped if the chain has other blocks, as
+// the retention window is resolved with all the blocks of the chain.
+func seedRetentionTestBlocks(t *testing.T, chainType string, numBlocks, numTxHeights int64) time.Time {
+	t.Helper()
+	createMultichainTestTables(t, chainType)
+	var count int64
+	err := db.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) FROM %sblocks;`, chainType)).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count > 0 {
+		t.Skipf("%s blocks table is not empty", chainType)
+	}
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(fmt.Sprintf(`DELETE FROM %sblocks WHERE hash LIKE 'retentiontest-%%';`, chainType))
+		_, _ = db.db.Exec(fmt.Sprintf(`DELETE FROM %stransactions WHERE tx_hash LIKE 'retentiontest-%%';`, chainType))
+	})
+
+	now := time.Now()
+	for h := int64(0); h < numBlocks; h++ {
+		blockTime := now.Add(-time.Duration(numBlocks-h) * time.Hour)
+		_, err = db.db.Exec(fmt.Sprintf(`INSERT INTO %sblocks (hash, height, time) VALUES ($1, $2, $3);`, chainType),
+			fmt.Sprintf("retentiontest-%d", h), h, blockTime.Unix())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for h := int64(0); h < numTxHeights; h++ {
+		for i := 0; i < 2; i++ {
+			_, err = db.db.Exec(fmt.Sprintf(`INSERT INTO %stransactions (tx_hash, block_height, block_index)
+				VALUES ($1, $2, $3);`, chainType), fmt.Sprintf("retentiontest-%d-%d", h, i), h, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return now
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	seedRetentionTestBlocks(t, chainType, 10, 0)
+
+	const spacing = 10 * time.Minute
+	tests := []struct {
+		name   string
+		tip    int64
+		blocks int64
+		age    time.Duration
+		want   int64
+	}{
+		{"no window", 1000, 0, 0, -1},
+		{"blocks", 1000, 100, 0, 900},
+		{"blocks before genesis", 1000, 2000, 0, 0},
+		// The blocks older than 5 hours are the heights below 5.
+		{"age", 1000, 0, 5*time.Hour + 30*time.Minute, 5},
+		{"age kept by blocks", 1000, 998, 5*time.Hour + 30*time.Minute, 2},
+		// No block is older than a day, so the age is resolved with the
+		// block spacing.
+		{"age estimate", 1000, 0, 24 * time.Hour, 1000 - 144},
+		{"age estimate before genesis", 100, 0, 24 * time.Hour, 0},
+		{"age estimate kept by blocks", 1000, 50, 24 * time.Hour, 1000 - 144},
+	}
+	for _, tt := range tests {
+		got, err := db.retentionCutoff(chainType, tt.tip, tt.blocks, tt.age, spacing)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: cutoff %d, expecting %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPruneRows(t *testing.T) {
+	const chainType = mutilchain.TYPEBTC
+	seedRetentionTestBlocks(t, chainType, 10, 250)
+
+	// The rows are deleted in batches smaller than the rows of a range.
+	prevBatchRows := db.pruneBatchRows
+	db.pruneBatchRows = 3
+	t.Cleanup(func() { db.pruneBatchRows = prevBatchRows })
+
+	stmt := mutilchainquery.MakePruneTransactions(chainType)
+	remaining := func() (n, minHeight int64) {
+		t.Helper()
+		err := db.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*), COALESCE(MIN(block_height), -1) FROM %stransactions
+			WHERE tx_hash LIKE 'retentiontest-%%';`, chainType)).Scan(&n, &minHeight)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	// An unset floor starts at the lowest block of the chain.
+	floor := int64(-1)
+	n, err := db.pruneRows(chainType, stmt, &floor, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 300 || floor != 150 {
+		t.Errorf("pruned %d rows up to %d, expecting 300 up to 150", n, floor)
+	}
+	if count, minHeight := remaining(); count != 200 || minHeight != 150 {
+		t.Errorf("%d rows left from height %d, expecting 200 from 150", count, minHeight)
+	}
+
+	// The floor moves up from the last cutoff.
+	n, err = db.pruneRows(chainType, stmt, &floor, 230)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 160 || floor != 230 {
+		t.Errorf("pruned %d rows up to %d, expecting 160 up to 230", n, floor)
+	}
+	if count, minHeight := remaining(); count != 40 || minHeight != 230 {
+		t.Errorf("%d rows left from height %d, expecting 40 from 230", count, minHeight)
+	}
+
+	// Nothing is pruned at or below the floor, or without a cutoff.
+	for _, cutoff := range []int64{230, 100, 0, -1} {
+		n, err = db.pruneRows(chainType, stmt, &floor, cutoff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 || floor != 230 {
+			t.Errorf("cutoff %d: pruned %d rows up to %d, expecting none up to 230", cutoff, n, floor)
+		}
+	}
+	if count, _ := remaining(); count != 40 {
+		t.Errorf("%d rows left, expecting 40", count)
+	}
+}
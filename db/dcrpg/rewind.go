@@ -67,6 +67,10 @@ func deleteSwapsForBlockHeight(dbTx SqlExecutor, height int64) (rowsDeleted int6
 	return sqlExec(dbTx, internal.DeleteSwaps, "failed to delete swaps", height)
 }
 
+func deleteMixStatsForBlockHeight(dbTx SqlExecutor, height int64) (rowsDeleted int64, err error) {
+	return sqlExec(dbTx, internal.DeleteMixStats, "failed to delete mix stats", height)
+}
+
 func deleteTransactionsForBlock(dbTx *sql.Tx, hash string) (txRowIds []int64, err error) {
 	var rows *sql.Rows
 	rows, err = dbTx.Query(internal.DeleteTransactionsSimple, hash)
@@ -271,6 +275,14 @@ func DeleteBlockData(ctx context.Context, db *sql.DB, hash string, height int64)
 	}
 	res.Timings.Swaps = int64(time.Since(start))
 
+	start = time.Now()
+	if res.MixStats, err = deleteMixStatsForBlockHeight(dbTx, height); err != nil {
+		err = fmt.Errorf(`deleteMixStatsForBlockHeight failed with "%v". Rollback: %v`,
+			err, dbTx.Rollback())
+		return
+	}
+	res.Timings.MixStats = int64(time.Since(start))
+
 	start = time.Now()
 	if res.Blocks, err = deleteBlock(dbTx, hash); err != nil {
 		err = fmt.Errorf(`deleteBlock failed with "%v". Rollback: %v`,
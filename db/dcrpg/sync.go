@@ -1371,6 +1371,7 @@ func (pgb *ChainDB) SyncChainDB(ctx context.Context, client rpcutils.BlockFetche
 				voutPerSec := float64(totalVouts-lastVouts) / tickTime.Seconds()
 				log.Infof("(%3d blk/s,%5d tx/s,%5d vin/sec,%5d vout/s)", int64(blocksPerSec),
 					int64(txPerSec), int64(vinsPerSec), int64(voutPerSec))
+				recordSyncRate(mutilchain.TYPEDCR, blocksPerSec, txPerSec, vinsPerSec, voutPerSec)
 				lastBlock, lastTxs = ib, totalTxs
 				lastVins, lastVouts = totalVins, totalVouts
 			default:
@@ -2150,3 +2151,41 @@ func SumLTCSubsidy(startHeight, endHeight int32, params *ltcchaincfg.Params, exc
 	}
 	return total
 }
+
+// SyncRate is the block import speed of a chain at its last speed report.
+type SyncRate struct {
+	Time         time.Time
+	BlocksPerSec float64
+	TxPerSec     float64
+	VinsPerSec   float64
+	VoutsPerSec  float64
+}
+
+var syncRates = struct {
+	sync.Mutex
+	m map[string]SyncRate
+}{m: make(map[string]SyncRate)}
+
+func recordSyncRate(chainType string, blocksPerSec, txPerSec, vinsPerSec, voutsPerSec float64) {
+	syncRates.Lock()
+	defer syncRates.Unlock()
+	syncRates.m[chainType] = SyncRate{
+		Time:         time.Now(),
+		BlocksPerSec: blocksPerSec,
+		TxPerSec:     txPerSec,
+		VinsPerSec:   vinsPerSec,
+		VoutsPerSec:  voutsPerSec,
+	}
+}
+
+// SyncRates returns the last SyncRate of each chain with a block import since
+// startup.
+func SyncRates() map[string]SyncRate {
+	syncRates.Lock()
+	defer syncRates.Unlock()
+	rates := make(map[string]SyncRate, len(syncRates.m))
+	for chainType, rate := range syncRates.m {
+		rates[chainType] = rate
+	}
+	return rates
+}
@@ -42,9 +42,13 @@ var createTableStatements = [][2]string{
 	{"ltc_swaps", internal.CreateLtcAtomicSwapTable},
 	{"monthly_price", internal.CreateMonthlyPriceTable},
 	{"daily_market", internal.CreateDailyMarketTable},
+	{"daily_price", internal.CreateDailyPriceTable},
 	{"blocks24h", internal.Create24hBlocksTable},
 	{"tspend_votes", internal.CreateTSpendVotesTable},
 	{"black_list", internal.CreateBlackListTable},
+	{"mix_stats", internal.CreateMixStatsTable},
+	{"multichain_sync_checkpoints", mutilchainquery.CreateSyncCheckpointTable},
+	{"multichain_meta", mutilchainquery.CreateMultichainMetaTable},
 }
 
 func GetCreateDBTables() [][2]string {
@@ -68,6 +72,9 @@ func GetCreateDBTables() [][2]string {
 			result = append(result, [2]string{"monero_key_images", mutilchainquery.CreateMoneroKeyImagesTable})
 			result = append(result, [2]string{"monero_ring_members", mutilchainquery.CreateMoneroRingMembers})
 			result = append(result, [2]string{"monero_rct_data", mutilchainquery.CreateMoneroRctData})
+		} else {
+			result = append(result, mutilchainquery.MakeCoinAgeTables(chainType)...)
+			result = append(result, internal.MakeUtxoSetTables(chainType)...)
 		}
 	}
 	return result
@@ -92,6 +99,9 @@ func GetMutilchainTables(chainType string) [][2]string {
 		result = append(result, [2]string{"monero_key_images", mutilchainquery.CreateMoneroKeyImagesTable})
 		result = append(result, [2]string{"monero_ring_members", mutilchainquery.CreateMoneroRingMembers})
 		result = append(result, [2]string{"monero_rct_data", mutilchainquery.CreateMoneroRctData})
+	} else {
+		result = append(result, mutilchainquery.MakeCoinAgeTables(chainType)...)
+		result = append(result, internal.MakeUtxoSetTables(chainType)...)
 	}
 	return result
 }
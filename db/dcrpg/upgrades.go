@@ -32,7 +32,7 @@ const (
 	// This includes changes such as creating tables, adding/deleting columns,
 	// adding/deleting indexes or any other operations that create, delete, or
 	// modify the definition of any database relation.
-	schemaVersion = 11
+	schemaVersion = 12
 
 	// maintVersion indicates when certain maintenance operations should be
 	// performed for the same compatVersion and schemaVersion. Such operations
@@ -410,7 +410,20 @@ func (u *Upgrader) compatVersion1Upgrades(current, target DatabaseVersion) (bool
 		fallthrough
 
 	case 11:
-		// Perform schema v11 maintenance.
+		err = u.upgradeSchema11to12()
+		if err != nil {
+			return false, fmt.Errorf("failed to upgrade 1.11.0 to 1.12.0: %v", err)
+		}
+		current.schema++
+		current.maint = 0
+		if storeVers(u.db, &current); err != nil {
+			return false, err
+		}
+
+		fallthrough
+
+	case 12:
+		// Perform schema v12 maintenance.
 
 		// No further upgrades.
 		return upgradeCheck()
@@ -442,6 +455,21 @@ func removeTableComments(db *sql.DB) {
 	}
 }
 
+func (u *Upgrader) upgradeSchema11to12() error {
+	log.Infof("Performing database upgrade 1.11.0 -> 1.12.0")
+	// Cursor pagination of the address transactions and of the atomic swap
+	// contract groups seeks on these indexes.
+	log.Infof("Indexing addresses table on address and block time...")
+	if err := IndexAddressTableOnAddressBlockTime(u.db); err != nil {
+		return err
+	}
+	log.Infof("Indexing swaps table on group contract time and group tx...")
+	if err := IndexSwapsTableOnGroupTime(u.db); err != nil {
+		return err
+	}
+	return IndexSwapsTableOnGroupTx(u.db)
+}
+
 func (u *Upgrader) upgradeSchema10to11() error {
 	log.Infof("Performing database upgrade 1.10.0 -> 1.11.0")
 	// The status table already had an index created automatically because of
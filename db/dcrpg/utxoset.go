@@ -0,0 +1,443 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	btctxscript "github.com/btcsuite/btcd/txscript"
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal/mutilchainquery"
+	"github.com/decred/dcrdata/v8/db/cache"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/txhelpers"
+	ltctxscript "github.com/ltcsuite/ltcd/txscript"
+)
+
+// utxoSetReorgDepth is the number of blocks below the UTXO set tip whose
+// changes to the set are kept. A deeper reorg rebuilds the UTXO set tables of
+// the chain.
+const utxoSetReorgDepth = 1000
+
+// utxoSetLogInterval is the number of blocks between the progress messages of
+// the UTXO set sync.
+const utxoSetLogInterval = 10000
+
+// defaultUtxoSnapshotBlocks is the number of blocks between the UTXO set
+// snapshots when none is configured.
+const defaultUtxoSnapshotBlocks = 144
+
+// utxoSetDeltaStmts are the statements storing the changes to the UTXO set of
+// a block, by chain. The script_type column of the BTC and LTC outputs holds
+// the name of the DCR script class with the number of the btcd or ltcd script
+// class, which is mapped back to the name of the latter.
+var utxoSetDeltaStmts = map[string]string{
+	mutilchain.TYPEDCR: internal.MakeInsertUtxoSetDeltas("", internal.SelectDCRUtxoSetBlockOutputs,
+		"s.script_type", txhelpers.DCRDustRule),
+	mutilchain.TYPEBTC: internal.MakeInsertUtxoSetDeltas(mutilchain.TYPEBTC,
+		mutilchainquery.MakeSelectUtxoSetBlockOutputs(mutilchain.TYPEBTC),
+		multichainScriptTypeExpr(btcScriptClassNames()), txhelpers.BTCDustRule),
+	mutilchain.TYPELTC: internal.MakeInsertUtxoSetDeltas(mutilchain.TYPELTC,
+		mutilchainquery.MakeSelectUtxoSetBlockOutputs(mutilchain.TYPELTC),
+		multichainScriptTypeExpr(ltcScriptClassNames()), txhelpers.LTCDustRule),
+}
+
+func btcScriptClassNames() (names []string) {
+	for sc := btctxscript.NonStandardTy; sc <= btctxscript.WitnessUnknownTy; sc++ {
+		names = append(names, sc.String())
+	}
+	return
+}
+
+func ltcScriptClassNames() (names []string) {
+	for sc := ltctxscript.NonStandardTy; sc <= ltctxscript.WitnessUnknownTy; sc++ {
+		names = append(names, sc.String())
+	}
+	return
+}
+
+// multichainScriptTypeExpr returns the SQL expression of the script class
+// name, of those given by class number, of the stored script_type of an output.
+func multichainScriptTypeExpr(classNames []string) string {
+	var b strings.Builder
+	b.WriteString("CASE s.script_type")
+	for i, name := range classNames {
+		fmt.Fprintf(&b, " WHEN '%s' THEN '%s'", dbtypes.ScriptClass(i), name)
+	}
+	b.WriteString(" ELSE s.script_type END")
+	return b.String()
+}
+
+// utxoSetPrefix is the prefix of the names of the UTXO set tables of a chain.
+func utxoSetPrefix(chainType string) string {
+	if chainType == mutilchain.TYPEDCR {
+		return ""
+	}
+	return chainType
+}
+
+func (pgb *ChainDB) utxoSetSyncMtx(chainType string) *sync.Mutex {
+	switch chainType {
+	case mutilchain.TYPEBTC:
+		return &pgb.btcUtxoSetSyncMtx
+	case mutilchain.TYPELTC:
+		return &pgb.ltcUtxoSetSyncMtx
+	}
+	return &pgb.dcrUtxoSetSyncMtx
+}
+
+func (pgb *ChainDB) utxoSnapshotInterval() int64 {
+	if pgb.utxoSnapshotBlocks > 0 {
+		return pgb.utxoSnapshotBlocks
+	}
+	return defaultUtxoSnapshotBlocks
+}
+
+// CheckAndCreateUtxoSetTables creates the DCR UTXO set tables if they do not
+// already exist. Those of BTC and LTC are created with the other tables of the
+// chain.
+func (pgb *ChainDB) CheckAndCreateUtxoSetTables() error {
+	for _, table := range internal.MakeUtxoSetTables("") {
+		if err := createTable(pgb.db, table[0], table[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncUtxoSet brings the UTXO set tables of the DCR, BTC or LTC chain up to
+// date, one block at a time from the UTXO set tip, and stores a snapshot of the
+// set at every snapshot interval. The blocks of the tables that are no longer
+// in the main chain are rewound first. The DCR blocks are applied once the
+// next block sets the validity of their regular transactions, and the BTC and
+// LTC blocks from the whole-chain tables. It returns at once if a sync of the
+// chain is already running, since that sync picks up the new blocks.
+func (pgb *ChainDB) SyncUtxoSet(chainType string) error {
+	deltaStmt, ok := utxoSetDeltaStmts[chainType]
+	if !ok {
+		return fmt.Errorf("no UTXO set for the chain type %q", chainType)
+	}
+	mtx := pgb.utxoSetSyncMtx(chainType)
+	if !mtx.TryLock() {
+		return nil
+	}
+	defer mtx.Unlock()
+
+	chain := strings.ToUpper(chainType)
+	height, hash, err := pgb.rewindUtxoSet(chainType)
+	if err != nil {
+		return err
+	}
+
+	start, t := height, time.Now()
+	for {
+		if err = pgb.ctx.Err(); err != nil {
+			return err
+		}
+		next, blockTime, valid, found, err := pgb.nextUtxoSetBlock(chainType, height+1, hash)
+		if err != nil {
+			return err
+		}
+		if !found {
+			break
+		}
+		if err = pgb.storeUtxoSetBlock(chainType, deltaStmt, height+1, next, blockTime, valid); err != nil {
+			return fmt.Errorf("UTXO set of block %d: %w", height+1, err)
+		}
+		height, hash = height+1, next
+		if (height-start)%utxoSetLogInterval == 0 {
+			log.Infof("%s: UTXO set synced to block %d", chain, height)
+		}
+	}
+	if height > start {
+		log.Debugf("%s: UTXO set synced from block %d to %d in %v", chain, start+1, height,
+			time.Since(t))
+	}
+	return nil
+}
+
+// utxoSetMainBlock returns the hash of the main chain block at the height, and
+// the validity of its regular transactions. The hash is empty if there is no
+// such block.
+func (pgb *ChainDB) utxoSetMainBlock(chainType string, height int64) (string, bool, error) {
+	if chainType != mutilchain.TYPEDCR {
+		hash, err := pgb.MutilchainBlockHashAtHeight(height, chainType)
+		return hash, true, err
+	}
+	var hash string
+	var valid bool
+	err := pgb.db.QueryRowContext(pgb.ctx, internal.SelectDCRMainchainBlockValidity, height).Scan(&hash, &valid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	return hash, valid, err
+}
+
+// rewindUtxoSet returns the height and hash of the tip of the UTXO set tables
+// of a chain, after rewinding the blocks that are not in the main chain, or
+// whose validity changed. The height is -1 if the tables are empty.
+func (pgb *ChainDB) rewindUtxoSet(chainType string) (int64, string, error) {
+	prefix := utxoSetPrefix(chainType)
+	var tip int64
+	var hash string
+	var valid bool
+	err := pgb.db.QueryRowContext(pgb.ctx, internal.MakeSelectUtxoSetTip(prefix)).Scan(&tip, &hash, &valid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	height := tip
+	for height >= 0 {
+		mainHash, mainValid, err := pgb.utxoSetMainBlock(chainType, height)
+		if err != nil {
+			return 0, "", err
+		}
+		if mainHash == hash && mainValid == valid {
+			break
+		}
+		height--
+		if height < 0 {
+			break
+		}
+		err = pgb.db.QueryRowContext(pgb.ctx, internal.MakeSelectUtxoSetHash(prefix), height).Scan(&hash, &valid)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Beyond the kept changes.
+			break
+		}
+		if err != nil {
+			return 0, "", err
+		}
+	}
+	if height == tip {
+		return height, hash, nil
+	}
+
+	chain := strings.ToUpper(chainType)
+	var minHeight int64
+	err = pgb.db.QueryRowContext(pgb.ctx, internal.MakeSelectUtxoSetBlocksMinHeight(prefix)).Scan(&minHeight)
+	if err != nil {
+		return 0, "", err
+	}
+	if height < 0 || height < minHeight {
+		log.Warnf("%s: UTXO set reorg from block %d deeper than the kept changes. "+
+			"Rebuilding the UTXO set tables.", chain, tip)
+		_, err = pgb.db.ExecContext(pgb.ctx, internal.MakeTruncateUtxoSetTables(prefix))
+		return -1, "", err
+	}
+
+	log.Infof("%s: Rewinding the UTXO set from block %d to %d", chain, tip, height)
+	dbTx, err := pgb.db.BeginTx(pgb.ctx, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	for _, stmt := range internal.MakeRewindUtxoSetStmts(prefix) {
+		if _, err = dbTx.Exec(stmt, height); err != nil {
+			_ = dbTx.Rollback()
+			return 0, "", err
+		}
+	}
+	if _, err = dbTx.Exec(internal.MakeDeleteEmptyUtxoSet(prefix)); err != nil {
+		_ = dbTx.Rollback()
+		return 0, "", err
+	}
+	return height, hash, dbTx.Commit()
+}
+
+// nextUtxoSetBlock returns the hash, time and validity of the block at the
+// height following the block with the hash prevHash, to be applied to the UTXO
+// set. found is false if there is no such block yet.
+func (pgb *ChainDB) nextUtxoSetBlock(chainType string, height int64, prevHash string) (hash string, blockTime int64, valid, found bool, err error) {
+	if chainType != mutilchain.TYPEDCR {
+		hash, blockTime, found, err = pgb.nextMultichainSyncedBlock(chainType, height, prevHash)
+		return hash, blockTime, true, found, err
+	}
+	var prev string
+	err = pgb.db.QueryRowContext(pgb.ctx, internal.SelectDCRUtxoSetBlock, height).
+		Scan(&hash, &prev, &blockTime, &valid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, false, false, nil
+	}
+	if err != nil {
+		return "", 0, false, false, err
+	}
+	if height > 0 && prev != prevHash {
+		// The tip is rewound by the next sync.
+		return "", 0, false, false, nil
+	}
+	return hash, blockTime, valid, true, nil
+}
+
+type utxoSetStmt struct {
+	query string
+	args  []interface{}
+}
+
+// storeUtxoSetBlock applies the changes of a block to the UTXO set of a chain,
+// and stores a snapshot of the set at every snapshot interval.
+func (pgb *ChainDB) storeUtxoSetBlock(chainType, deltaStmt string, height int64, hash string, blockTime int64, valid bool) error {
+	prefix := utxoSetPrefix(chainType)
+	dbTx, err := pgb.db.BeginTx(pgb.ctx, nil)
+	if err != nil {
+		return err
+	}
+	stmts := []utxoSetStmt{
+		{deltaStmt, []interface{}{hash, height}},
+		{internal.MakeApplyUtxoSetDeltas(prefix), []interface{}{height}},
+		{internal.MakeDeleteEmptyUtxoSet(prefix), nil},
+		{internal.MakeInsertUtxoSetBlock(prefix), []interface{}{height, hash, valid}},
+	}
+	if height%pgb.utxoSnapshotInterval() == 0 {
+		stmts = append(stmts,
+			utxoSetStmt{internal.MakeInsertUtxoSetSnapshot(prefix), []interface{}{height, hash, blockTime}},
+			utxoSetStmt{internal.MakeInsertUtxoSetHistory(prefix), []interface{}{height}})
+	}
+	for _, query := range internal.MakePruneUtxoSetStmts(prefix) {
+		stmts = append(stmts, utxoSetStmt{query, []interface{}{height - utxoSetReorgDepth}})
+	}
+	for _, stmt := range stmts {
+		if _, err = dbTx.Exec(stmt.query, stmt.args...); err != nil {
+			_ = dbTx.Rollback()
+			return err
+		}
+	}
+	return dbTx.Commit()
+}
+
+// UtxoSetSnapshot returns the latest UTXO set snapshot of the DCR, BTC or LTC
+// chain, with its distribution by value bucket and by script type. The script
+// types are in decreasing order of count. It returns nil if there is no
+// snapshot yet.
+func (pgb *ChainDB) UtxoSetSnapshot(chainType string) (*dbtypes.UtxoSetSnapshot, error) {
+	if _, ok := utxoSetDeltaStmts[chainType]; !ok {
+		return nil, fmt.Errorf("no UTXO set for the chain type %q", chainType)
+	}
+	prefix := utxoSetPrefix(chainType)
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+
+	snapshot := &dbtypes.UtxoSetSnapshot{Chain: chainType}
+	err := pgb.db.QueryRowContext(ctx, internal.MakeSelectLatestUtxoSetSnapshot(prefix)).Scan(
+		&snapshot.Height, &snapshot.Hash, &snapshot.Time, &snapshot.Count, &snapshot.Value,
+		&snapshot.Size, &snapshot.DustCount, &snapshot.DustValue)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+
+	rows, err := pgb.db.QueryContext(ctx, internal.MakeSelectUtxoSetHistoryAtHeight(prefix), snapshot.Height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+	for rows.Next() {
+		var bucket int
+		var scriptType string
+		var dust bool
+		var count, value, size int64
+		if err = rows.Scan(&bucket, &scriptType, &dust, &count, &value, &size); err != nil {
+			return nil, err
+		}
+		snapshot.AddRow(bucket, scriptType, dust, count, value, size)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(snapshot.ScriptTypes, func(i, j int) bool {
+		return snapshot.ScriptTypes[i].Count > snapshot.ScriptTypes[j].Count
+	})
+	return snapshot, nil
+}
+
+// retrieveUtxoSetSnapshots fetches the UTXO set snapshots above the last
+// snapshot of the charts. All of them are fetched again if the block of that
+// snapshot changed, after a reorg or a rebuild of the UTXO set tables.
+func retrieveUtxoSetSnapshots(ctx context.Context, db *sql.DB, prefix string, tip int64, tipHash string) (*sql.Rows, error) {
+	start := tip + 1
+	if start > 0 {
+		var hash string
+		err := db.QueryRowContext(ctx, internal.MakeSelectUtxoSetSnapshotHash(prefix), tip).Scan(&hash)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if hash != tipHash {
+			start = 0
+		}
+	}
+	return db.QueryContext(ctx, internal.MakeSelectUtxoSetSnapshotsFrom(prefix), start)
+}
+
+// appendUtxoSetSnapshots appends the results from retrieveUtxoSetSnapshots to
+// the UTXO set series, in coins.
+func appendUtxoSetSnapshots(series *cache.UtxoSetSeries, rows *sql.Rows) error {
+	defer closeRows(rows)
+	first := true
+	for rows.Next() {
+		var height, blockTime, count, value, size, dustCount, dustValue int64
+		var hash string
+		err := rows.Scan(&height, &hash, &blockTime, &count, &value, &size, &dustCount, &dustValue)
+		if err != nil {
+			return err
+		}
+		if first {
+			// Drop the snapshots being fetched again.
+			series.SnipFrom(uint64(height))
+			first = false
+		}
+		series.Append(uint64(height), uint64(blockTime), uint64(count), float64(value)/atomsPerCoin,
+			uint64(size), uint64(dustCount), float64(dustValue)/atomsPerCoin)
+		series.TipHash = hash
+	}
+	return rows.Err()
+}
+
+// chartUtxoSet fetches the DCR UTXO set snapshots. This is the Fetcher half of
+// a pair that make up a cache.ChartUpdater. The Appender half is
+// appendChartUtxoSet.
+func (pgb *ChainDB) chartUtxoSet(charts *cache.ChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	tip, tipHash := charts.UtxoSetTip()
+	rows, err := retrieveUtxoSetSnapshots(ctx, pgb.db, "", tip, tipHash)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("chartUtxoSet: %w", pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+func appendChartUtxoSet(charts *cache.ChartData, rows *sql.Rows) error {
+	defer charts.DropUtxoSetCharts()
+	return appendUtxoSetSnapshots(charts.UtxoSet, rows)
+}
+
+// chartMutilchainUtxoSet fetches the BTC or LTC UTXO set snapshots. This is the
+// Fetcher half of a pair that make up a cache.ChartMutilchainUpdater. The
+// Appender half is appendMutilchainUtxoSet.
+func (pgb *ChainDB) chartMutilchainUtxoSet(charts *cache.MutilchainChartData) (*sql.Rows, func(), error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+
+	tip, tipHash := charts.UtxoSetTip()
+	rows, err := retrieveUtxoSetSnapshots(ctx, pgb.db, charts.ChainType, tip, tipHash)
+	if err != nil {
+		return nil, cancel, fmt.Errorf("%s: chartUtxoSet: %w", charts.ChainType, pgb.replaceCancelError(err))
+	}
+	return rows, cancel, nil
+}
+
+func appendMutilchainUtxoSet(charts *cache.MutilchainChartData, rows *sql.Rows) error {
+	defer charts.DropUtxoSetCharts()
+	return appendUtxoSetSnapshots(charts.UtxoSet, rows)
+}
@@ -0,0 +1,182 @@
+//go:build pgonline
+
+package dcrpg
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/decred/dcrdata/v8/txhelpers"
+	"github.com/lib/pq"
+)
+
+// utxoSetTestOutput is an output of the test blocks of the UTXO set deltas,
+// with a script type unique to it so that it has its own deltas row.
+type utxoSetTestOutput struct {
+	label    string
+	value    int64
+	pkScript []byte
+}
+
+// utxoSetTestDelta is the deltas row of an output.
+type utxoSetTestDelta struct {
+	bucket int
+	dust   bool
+	count  int64
+	size   int64
+}
+
+// insertUtxoSetTestDeltas stores the UTXO set deltas of the outputs at the
+// height with the dust rule, in the BTC tables, and returns the rows by label.
+func insertUtxoSetTestDeltas(t *testing.T, height int64, rule txhelpers.DustRule, outputs []utxoSetTestOutput) map[string]utxoSetTestDelta {
+	t.Helper()
+	t.Cleanup(func() {
+		_, _ = db.db.Exec(`DELETE FROM btcutxo_set_deltas WHERE height = $1;`, height)
+	})
+	var values []int64
+	var pkScripts [][]byte
+	var labels []string
+	for _, out := range outputs {
+		values = append(values, out.value)
+		pkScripts = append(pkScripts, out.pkScript)
+		labels = append(labels, out.label)
+	}
+	source := `SELECT 1 AS sign, t.value, t.pkscript, t.script_type
+		FROM unnest($1::INT8[], $3::BYTEA[], $4::TEXT[]) AS t(value, pkscript, script_type)`
+	stmt := internal.MakeInsertUtxoSetDeltas(mutilchain.TYPEBTC, source, "s.script_type", rule)
+	_, err := db.db.Exec(stmt, pq.Array(values), height, pq.ByteaArray(pkScripts), pq.Array(labels))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.db.Query(`SELECT script_type, value_bucket, dust, count, size
+		FROM btcutxo_set_deltas WHERE height = $1;`, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	deltas := make(map[string]utxoSetTestDelta)
+	for rows.Next() {
+		var label string
+		var d utxoSetTestDelta
+		if err = rows.Scan(&label, &d.bucket, &d.dust, &d.count, &d.size); err != nil {
+			t.Fatal(err)
+		}
+		deltas[label] = d
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return deltas
+}
+
+// TestUtxoSetDeltasSizeDust checks the sizes and dust flags of the UTXO set
+// deltas against txhelpers.DustRule for the same scripts and amounts.
+func TestUtxoSetDeltasSizeDust(t *testing.T) {
+	createMultichainTestTables(t, mutilchain.TYPEBTC)
+
+	script := func(b ...byte) []byte { return b }
+	pad := func(prefix []byte, n int) []byte {
+		s := make([]byte, n)
+		copy(s, prefix)
+		return s
+	}
+	scripts := map[string][]byte{
+		"empty":             nil,
+		"one byte":          script(0x51),
+		"three bytes":       script(0x00, 0x01, 0x02),
+		"p2pkh":             pad(script(0x76, 0xa9, 0x14), 25),
+		"p2sh":              pad(script(0xa9, 0x14), 23),
+		"p2wpkh":            pad(script(0x00, 0x14), 22),
+		"p2wsh":             pad(script(0x00, 0x20), 34),
+		"p2tr":              pad(script(0x51, 0x20), 34),
+		"v16 program":       pad(script(0x60, 0x02), 4),
+		"v17 not a program": pad(script(0x61, 0x02), 4),
+		"bad push":          pad(script(0x00, 0x15), 22),
+		"too long program":  pad(script(0x00, 0x29), 43),
+		"nulldata":          pad(script(0x6a, 0x28), 42),
+		"large":             make([]byte, 300),
+		"huge":              make([]byte, 70000),
+	}
+	rules := []struct {
+		name string
+		rule txhelpers.DustRule
+	}{
+		{"dcr", txhelpers.DCRDustRule},
+		{"btc", txhelpers.BTCDustRule},
+		{"ltc", txhelpers.LTCDustRule},
+	}
+	for i, r := range rules {
+		var outputs []utxoSetTestOutput
+		for scriptName, pkScript := range scripts {
+			// The amounts around the dust threshold of the script.
+			limit := (r.rule.DustRelayFeePerKb*int64(r.rule.SpendSize(pkScript)) + 999) / 1000
+			seen := make(map[int64]bool)
+			for _, amount := range []int64{0, 1, limit - 1, limit, limit + 1, 1e8} {
+				if seen[amount] {
+					continue
+				}
+				seen[amount] = true
+				outputs = append(outputs, utxoSetTestOutput{fmt.Sprintf("%s %d", scriptName, amount), amount, pkScript})
+			}
+		}
+
+		deltas := insertUtxoSetTestDeltas(t, 950001+int64(i), r.rule, outputs)
+		if len(deltas) != len(outputs) {
+			t.Errorf("%s: %d deltas rows of %d outputs", r.name, len(deltas), len(outputs))
+		}
+		for _, out := range outputs {
+			d := deltas[out.label]
+			if d.count != 1 {
+				t.Errorf("%s %s: count %d, expected 1", r.name, out.label, d.count)
+				continue
+			}
+			if want := int64(r.rule.OutputSize(len(out.pkScript))); d.size != want {
+				t.Errorf("%s %s: size %d, expected %d", r.name, out.label, d.size, want)
+			}
+			if want := r.rule.IsDust(out.value, out.pkScript); d.dust != want {
+				t.Errorf("%s %s: dust %v, expected %v", r.name, out.label, d.dust, want)
+			}
+		}
+	}
+}
+
+// TestUtxoSetDeltasValueBucket checks the value buckets of the UTXO set deltas
+// against the buckets of dbtypes.UtxoValueBuckets.
+func TestUtxoSetDeltasValueBucket(t *testing.T) {
+	createMultichainTestTables(t, mutilchain.TYPEBTC)
+
+	pkScript := make([]byte, 25)
+	var outputs []utxoSetTestOutput
+	want := make(map[string]int)
+	addOutput := func(value int64, bucket int) {
+		label := fmt.Sprintf("bucket %d", value)
+		outputs = append(outputs, utxoSetTestOutput{label, value, pkScript})
+		want[label] = bucket
+	}
+	// The lower bound in atoms of each bucket.
+	lower := int64(1000)
+	for i := 1; i < len(dbtypes.UtxoValueBuckets); i++ {
+		addOutput(lower-1, i-1)
+		addOutput(lower, i)
+		lower *= 10
+	}
+	addOutput(0, 0)
+	addOutput(1e18, len(dbtypes.UtxoValueBuckets)-1)
+
+	deltas := insertUtxoSetTestDeltas(t, 950000, txhelpers.BTCDustRule, outputs)
+	for _, out := range outputs {
+		d, ok := deltas[out.label]
+		if !ok {
+			t.Errorf("value %d: no deltas row", out.value)
+			continue
+		}
+		if d.bucket != want[out.label] {
+			t.Errorf("value %d: bucket %d, expected %d (%s)", out.value, d.bucket, want[out.label],
+				dbtypes.UtxoValueBuckets[want[out.label]])
+		}
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package dcrpg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrdata/db/dcrpg/v8/internal"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	"github.com/lib/pq"
+)
+
+// CheckAndCreateWebhookTables creates the webhook subscription, outbox and
+// dead letter tables if they do not already exist. The API key tables must
+// exist first.
+func (pgb *ChainDB) CheckAndCreateWebhookTables() error {
+	for _, table := range []struct{ name, stmt string }{
+		{"webhook_subscriptions", internal.CreateWebhookSubscriptionsTable},
+		{"webhook_outbox", internal.CreateWebhookOutboxTable},
+		{"webhook_dead_letters", internal.CreateWebhookDeadLettersTable},
+	} {
+		if err := createTable(pgb.db, table.name, table.stmt); err != nil {
+			return err
+		}
+	}
+	_, err := pgb.db.Exec(internal.IndexWebhookOutboxOnNextAttempt)
+	return err
+}
+
+func scanWebhookSubscriptions(rows *sql.Rows) ([]*dbtypes.WebhookSubscription, error) {
+	defer closeRows(rows)
+	var subs []*dbtypes.WebhookSubscription
+	for rows.Next() {
+		var sub dbtypes.WebhookSubscription
+		err := rows.Scan(&sub.ID, &sub.KeyID, &sub.URL, &sub.Secret, &sub.Event, &sub.Chain,
+			&sub.Target, &sub.Confirmations, &sub.Active, &sub.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, &sub)
+	}
+	return subs, rows.Err()
+}
+
+// WebhookSubscriptions retrieves the active webhook subscriptions.
+func (pgb *ChainDB) WebhookSubscriptions() ([]*dbtypes.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectActiveWebhookSubscriptions)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	subs, err := scanWebhookSubscriptions(rows)
+	return subs, pgb.replaceCancelError(err)
+}
+
+// WebhookSubscriptionsByKey retrieves the webhook subscriptions of an API key,
+// including the deactivated ones.
+func (pgb *ChainDB) WebhookSubscriptionsByKey(keyID int64) ([]*dbtypes.WebhookSubscription, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectWebhookSubscriptionsByKey, keyID)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	subs, err := scanWebhookSubscriptions(rows)
+	return subs, pgb.replaceCancelError(err)
+}
+
+// InsertWebhookSubscription stores a new webhook subscription, and sets its ID,
+// Active and CreatedAt fields.
+func (pgb *ChainDB) InsertWebhookSubscription(sub *dbtypes.WebhookSubscription) error {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	err := pgb.db.QueryRowContext(ctx, internal.InsertWebhookSubscription, sub.KeyID, sub.URL,
+		sub.Secret, sub.Event, sub.Chain, sub.Target, sub.Confirmations).
+		Scan(&sub.ID, &sub.Active, &sub.CreatedAt)
+	return pgb.replaceCancelError(err)
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription of an API key with
+// its pending deliveries and dead letters. dbtypes.ErrNoResult is returned if
+// the key has no such subscription.
+func (pgb *ChainDB) DeleteWebhookSubscription(keyID, id int64) error {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	res, err := pgb.db.ExecContext(ctx, internal.DeleteWebhookSubscription, id, keyID)
+	if err != nil {
+		return pgb.replaceCancelError(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return dbtypes.ErrNoResult
+	}
+	return nil
+}
+
+// DeactivateWebhookSubscription deactivates a webhook subscription, which
+// keeps its pending deliveries.
+func (pgb *ChainDB) DeactivateWebhookSubscription(id int64) error {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	_, err := pgb.db.ExecContext(ctx, internal.DeactivateWebhookSubscription, id)
+	return pgb.replaceCancelError(err)
+}
+
+// EnqueueWebhookDeliveries adds deliveries to the webhook outbox in a single
+// transaction.
+func (pgb *ChainDB) EnqueueWebhookDeliveries(deliveries []*dbtypes.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	dbTx, err := pgb.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin database transaction: %w", err)
+	}
+	stmt, err := dbTx.PrepareContext(ctx, internal.InsertWebhookDelivery)
+	if err != nil {
+		_ = dbTx.Rollback()
+		return pgb.replaceCancelError(err)
+	}
+	defer stmt.Close()
+
+	for _, d := range deliveries {
+		if _, err = stmt.ExecContext(ctx, d.SubscriptionID, d.Event, string(d.Payload)); err != nil {
+			_ = dbTx.Rollback()
+			return pgb.replaceCancelError(err)
+		}
+	}
+	return dbTx.Commit()
+}
+
+// ClaimWebhookDeliveries claims up to limit due deliveries of the webhook
+// outbox for the lease duration.
+func (pgb *ChainDB) ClaimWebhookDeliveries(limit int, lease time.Duration) ([]*dbtypes.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.ClaimWebhookDeliveries, limit, lease.Seconds())
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var deliveries []*dbtypes.WebhookDelivery
+	for rows.Next() {
+		var d dbtypes.WebhookDelivery
+		var payload string
+		err = rows.Scan(&d.ID, &d.SubscriptionID, &d.Event, &payload, &d.Attempts,
+			&d.CreatedAt, &d.URL, &d.Secret)
+		if err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, pgb.replaceCancelError(rows.Err())
+}
+
+// CompleteWebhookDelivery removes a successful delivery from the outbox. The
+// delivery may be removed while the ChainDB is shutting down.
+func (pgb *ChainDB) CompleteWebhookDelivery(id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pgb.queryTimeout)
+	defer cancel()
+	_, err := pgb.db.ExecContext(ctx, internal.DeleteWebhookDelivery, id)
+	return pgb.replaceCancelError(err)
+}
+
+// RetryWebhookDelivery records a failed attempt of a delivery, and schedules
+// the next attempt.
+func (pgb *ChainDB) RetryWebhookDelivery(id int64, next time.Time, lastErr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pgb.queryTimeout)
+	defer cancel()
+	_, err := pgb.db.ExecContext(ctx, internal.RetryWebhookDelivery, id, next, lastErr)
+	return pgb.replaceCancelError(err)
+}
+
+// DeadLetterWebhookDelivery moves a delivery that failed its last attempt from
+// the outbox to the dead letters.
+func (pgb *ChainDB) DeadLetterWebhookDelivery(id int64, lastErr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pgb.queryTimeout)
+	defer cancel()
+	_, err := pgb.db.ExecContext(ctx, internal.DeadLetterWebhookDelivery, id, lastErr)
+	return pgb.replaceCancelError(err)
+}
+
+// WebhookDeadLetters retrieves the limit most recent dead letters of the
+// webhook subscriptions of an API key.
+func (pgb *ChainDB) WebhookDeadLetters(keyID int64, limit int) ([]*dbtypes.WebhookDeadLetter, error) {
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, internal.SelectWebhookDeadLettersByKey, keyID, limit)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var letters []*dbtypes.WebhookDeadLetter
+	for rows.Next() {
+		var l dbtypes.WebhookDeadLetter
+		var payload string
+		err = rows.Scan(&l.ID, &l.SubscriptionID, &l.Event, &payload, &l.Attempts,
+			&l.LastError, &l.CreatedAt, &l.FailedAt)
+		if err != nil {
+			return nil, err
+		}
+		l.Payload = []byte(payload)
+		letters = append(letters, &l)
+	}
+	return letters, pgb.replaceCancelError(rows.Err())
+}
+
+// WebhookAddressActivity retrieves the funding and spending of the outputs of
+// the addresses by the transactions of a DCR, BTC or LTC block.
+func (pgb *ChainDB) WebhookAddressActivity(chainType string, addrs, txHashes []string) ([]*dbtypes.WebhookAddressActivity, error) {
+	var query string
+	switch chainType {
+	case mutilchain.TYPEDCR:
+		query = internal.SelectAddressActivityInTxns
+	case mutilchain.TYPEBTC, mutilchain.TYPELTC:
+		query = internal.MakeSelectMultichainAddressActivityInTxns(chainType)
+	default:
+		return nil, fmt.Errorf("address activity is not supported for chain %q", chainType)
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, query, pq.Array(addrs), pq.Array(txHashes))
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var activity []*dbtypes.WebhookAddressActivity
+	for rows.Next() {
+		var a dbtypes.WebhookAddressActivity
+		if err = rows.Scan(&a.Address, &a.TxHash, &a.IsFunding, &a.Value); err != nil {
+			return nil, err
+		}
+		activity = append(activity, &a)
+	}
+	return activity, pgb.replaceCancelError(rows.Err())
+}
+
+// WebhookTxBlocks retrieves the height and hash of the blocks of DCR, BTC or
+// LTC transactions, by transaction hash. The transactions that are not in a
+// mainchain block are omitted.
+func (pgb *ChainDB) WebhookTxBlocks(chainType string, txHashes []string) (map[string]*dbtypes.WebhookTxBlock, error) {
+	var query string
+	switch chainType {
+	case mutilchain.TYPEDCR:
+		query = internal.SelectTxsMainchainBlocks
+	case mutilchain.TYPEBTC, mutilchain.TYPELTC:
+		query = internal.MakeSelectMultichainTxsBlocks(chainType)
+	default:
+		return nil, fmt.Errorf("transaction blocks are not supported for chain %q", chainType)
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, query, pq.Array(txHashes))
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	blocks := make(map[string]*dbtypes.WebhookTxBlock)
+	for rows.Next() {
+		var txHash string
+		var b dbtypes.WebhookTxBlock
+		if err = rows.Scan(&txHash, &b.Height, &b.Hash); err != nil {
+			return nil, err
+		}
+		blocks[txHash] = &b
+	}
+	return blocks, pgb.replaceCancelError(rows.Err())
+}
+
+// WebhookSwapRedemptions retrieves the atomic swap contracts of a DCR, BTC or
+// LTC chain that were redeemed in the block at the given height.
+func (pgb *ChainDB) WebhookSwapRedemptions(chainType string, height int64) ([]*dbtypes.WebhookSwapRedemption, error) {
+	var query string
+	switch chainType {
+	case mutilchain.TYPEDCR:
+		query = internal.SelectSwapRedemptionsAtHeight
+	case mutilchain.TYPEBTC, mutilchain.TYPELTC:
+		query = internal.MakeSelectMultichainSwapRedemptionsAtHeight(chainType)
+	default:
+		return nil, fmt.Errorf("swaps are not supported for chain %q", chainType)
+	}
+
+	ctx, cancel := context.WithTimeout(pgb.ctx, pgb.queryTimeout)
+	defer cancel()
+	rows, err := pgb.db.QueryContext(ctx, query, height)
+	if err != nil {
+		return nil, pgb.replaceCancelError(err)
+	}
+	defer closeRows(rows)
+
+	var swaps []*dbtypes.WebhookSwapRedemption
+	for rows.Next() {
+		var s dbtypes.WebhookSwapRedemption
+		var secretHash sql.NullString
+		if err = rows.Scan(&s.ContractTx, &s.SpendTx, &s.SpendVin, &s.Value, &secretHash); err != nil {
+			return nil, err
+		}
+		s.SecretHash = secretHash.String
+		swaps = append(swaps, &s)
+	}
+	return swaps, pgb.replaceCancelError(rows.Err())
+}
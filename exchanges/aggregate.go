@@ -0,0 +1,185 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// madScale scales the median absolute deviation to the standard deviation
+	// of normally distributed prices.
+	madScale = 1.4826
+	// minRelativeMAD is the lower bound of the median absolute deviation, as a
+	// fraction of the median price, so that quotes are not rejected for tiny
+	// deviations when the other exchanges agree closely.
+	minRelativeMAD = 0.01
+	// minOutlierQuotes is the minimum number of quotes for outlier rejection.
+	// With two quotes, there is no telling which one is off.
+	minOutlierQuotes = 3
+)
+
+// The reasons for the exclusion of a quote from an aggregate price.
+const (
+	ExclusionStale    = "stale"
+	ExclusionOutlier  = "outlier"
+	ExclusionNoPrice  = "no price"
+	ExclusionNoVolume = "no volume"
+)
+
+// AggregationConfig configures the aggregation of the prices of the exchanges
+// of a market into the price of the market. The aggregate price is the
+// volume-weighted average price of the quotes that are neither stale nor
+// outliers. A zero field disables its rule.
+type AggregationConfig struct {
+	// MaxQuoteAge is the maximum time since the last update of an exchange for
+	// its quote to be included.
+	MaxQuoteAge time.Duration
+	// MADThreshold is the maximum deviation of a quote from the median price,
+	// in scaled median absolute deviations, for the quote to be included.
+	MADThreshold float64
+	// MinExchanges is the minimum number of included quotes. Without this
+	// quorum, the price of the market is not updated.
+	MinExchanges int
+}
+
+// DefaultAggregationConfig is used when the ExchangeBotConfig has no
+// Aggregation.
+var DefaultAggregationConfig = AggregationConfig{
+	MADThreshold: 3,
+	MinExchanges: 1,
+}
+
+// QuoteDiagnostic is the contribution of the quote of an exchange to an
+// aggregate price.
+type QuoteDiagnostic struct {
+	Token  string  `json:"token"`
+	Price  float64 `json:"price"`
+	Weight float64 `json:"weight"`
+	// Age is the time since the last update of the exchange, in seconds.
+	Age      int64  `json:"age"`
+	Included bool   `json:"included"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// PriceAggregate is the aggregate price of a market, with the diagnostics of
+// the quotes of its exchanges. Median and MAD are those of the quotes that
+// were not excluded as stale or without price or volume.
+type PriceAggregate struct {
+	Price    float64            `json:"price"`
+	Median   float64            `json:"median"`
+	MAD      float64            `json:"mad"`
+	Included int                `json:"included"`
+	Quorum   bool               `json:"quorum"`
+	Quotes   []*QuoteDiagnostic `json:"quotes"`
+
+	change, volume, low, high float64
+}
+
+// quote is the state of an exchange of a market, with the time of the last
+// update of the exchange.
+type quote struct {
+	token      string
+	state      *ExchangeState
+	lastUpdate time.Time
+}
+
+// median is the median of the values, which are sorted in place.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// aggregate computes the aggregate price of the quotes. The price is zero if
+// the quorum is not met. If volumeAveraged is false, all the included quotes
+// are given equal weight.
+func (cfg *AggregationConfig) aggregate(quotes []*quote, volumeAveraged bool, now time.Time) *PriceAggregate {
+	agg := &PriceAggregate{
+		Quotes: make([]*QuoteDiagnostic, 0, len(quotes)),
+	}
+	// The candidates are the quotes that are neither stale nor without price
+	// or volume, and their diagnostics.
+	var candidates []*quote
+	var diags []*QuoteDiagnostic
+	var prices []float64
+	for _, q := range quotes {
+		d := &QuoteDiagnostic{
+			Token:  q.token,
+			Price:  q.state.Price,
+			Weight: 1,
+			Age:    int64(now.Sub(q.lastUpdate).Seconds()),
+		}
+		if volumeAveraged {
+			d.Weight = q.state.BaseVolume
+		}
+		agg.Quotes = append(agg.Quotes, d)
+		switch {
+		case cfg.MaxQuoteAge > 0 && now.Sub(q.lastUpdate) > cfg.MaxQuoteAge:
+			d.Reason = ExclusionStale
+		case d.Price <= 0:
+			d.Reason = ExclusionNoPrice
+		case d.Weight <= 0:
+			d.Reason = ExclusionNoVolume
+		default:
+			candidates = append(candidates, q)
+			diags = append(diags, d)
+			prices = append(prices, d.Price)
+		}
+	}
+	sort.Slice(agg.Quotes, func(i, j int) bool {
+		return agg.Quotes[i].Token < agg.Quotes[j].Token
+	})
+
+	agg.Median = median(prices)
+	deviations := make([]float64, 0, len(candidates))
+	for _, d := range diags {
+		deviations = append(deviations, math.Abs(d.Price-agg.Median))
+	}
+	agg.MAD = median(deviations)
+	maxDeviation := math.Inf(1)
+	if cfg.MADThreshold > 0 && len(candidates) >= minOutlierQuotes {
+		maxDeviation = cfg.MADThreshold * madScale * math.Max(agg.MAD, minRelativeMAD*agg.Median)
+	}
+
+	var priceSum, changeSum float64
+	agg.low = math.MaxFloat64
+	for i, d := range diags {
+		if math.Abs(d.Price-agg.Median) > maxDeviation {
+			d.Reason = ExclusionOutlier
+			continue
+		}
+		d.Included = true
+		agg.Included++
+		state := candidates[i].state
+		agg.volume += d.Weight
+		priceSum += d.Weight * d.Price
+		changeSum += d.Weight * state.Change
+		if state.Low > 0 && state.Low < agg.low {
+			agg.low = state.Low
+		}
+		if state.High > agg.high {
+			agg.high = state.High
+		}
+	}
+	if agg.low == math.MaxFloat64 {
+		agg.low = 0
+	}
+	agg.Quorum = agg.Included > 0 && agg.Included >= cfg.MinExchanges
+	if !agg.Quorum {
+		agg.volume, agg.low, agg.high = 0, 0, 0
+		return agg
+	}
+	agg.Price = priceSum / agg.volume
+	agg.change = changeSum / agg.volume
+	return agg
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// syntheticExchange is an exchange whose quotes are set by the tests.
+type syntheticExchange struct {
+	*CommonExchange
+}
+
+func (xc *syntheticExchange) Refresh() {}
+
+func newSyntheticExchange(token string, lastUpdate time.Time) *syntheticExchange {
+	return &syntheticExchange{&CommonExchange{token: token, lastUpdate: lastUpdate}}
+}
+
+func syntheticQuote(token string, price, volume float64, age time.Duration, now time.Time) *quote {
+	return &quote{
+		token:      token,
+		state:      &ExchangeState{BaseState: BaseState{Price: price, BaseVolume: volume}},
+		lastUpdate: now.Add(-age),
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	now := time.Now()
+	cfg := &AggregationConfig{
+		MaxQuoteAge:  10 * time.Minute,
+		MADThreshold: 3,
+		MinExchanges: 2,
+	}
+	tests := []struct {
+		name     string
+		cfg      *AggregationConfig
+		quotes   []*quote
+		weighted bool
+		price    float64
+		excluded map[string]string
+	}{{
+		name: "broken feed",
+		cfg:  cfg,
+		quotes: []*quote{
+			syntheticQuote("a", 20.0, 100, time.Minute, now),
+			syntheticQuote("b", 20.2, 300, time.Minute, now),
+			syntheticQuote("c", 19.9, 100, time.Minute, now),
+			syntheticQuote("d", 30.0, 1000, time.Minute, now),
+		},
+		weighted: true,
+		price:    (20.0*100 + 20.2*300 + 19.9*100) / 500,
+		excluded: map[string]string{"d": ExclusionOutlier},
+	}, {
+		name: "stale and empty quotes",
+		cfg:  cfg,
+		quotes: []*quote{
+			syntheticQuote("a", 20.0, 100, time.Minute, now),
+			syntheticQuote("b", 22.0, 100, time.Minute, now),
+			syntheticQuote("c", 25.0, 100, time.Hour, now),
+			syntheticQuote("d", 0, 100, time.Minute, now),
+			syntheticQuote("e", 21.0, 0, time.Minute, now),
+		},
+		weighted: true,
+		price:    21.0,
+		excluded: map[string]string{"c": ExclusionStale, "d": ExclusionNoPrice, "e": ExclusionNoVolume},
+	}, {
+		name: "no quorum",
+		cfg:  cfg,
+		quotes: []*quote{
+			syntheticQuote("a", 20.0, 100, time.Minute, now),
+			syntheticQuote("b", 20.0, 100, time.Hour, now),
+		},
+		weighted: true,
+		price:    0,
+		excluded: map[string]string{"b": ExclusionStale},
+	}, {
+		name: "two quotes are not outliers",
+		cfg:  cfg,
+		quotes: []*quote{
+			syntheticQuote("a", 20.0, 100, time.Minute, now),
+			syntheticQuote("b", 30.0, 100, time.Minute, now),
+		},
+		weighted: true,
+		price:    25.0,
+	}, {
+		name: "close agreement",
+		cfg:  cfg,
+		quotes: []*quote{
+			syntheticQuote("a", 20.000, 1, time.Minute, now),
+			syntheticQuote("b", 20.001, 1, time.Minute, now),
+			syntheticQuote("c", 20.002, 1, time.Minute, now),
+			syntheticQuote("d", 20.300, 1, time.Minute, now),
+		},
+		price: (20.000 + 20.001 + 20.002 + 20.300) / 4,
+	}, {
+		name: "disabled rules",
+		cfg:  &AggregationConfig{},
+		quotes: []*quote{
+			syntheticQuote("a", 20.0, 1, time.Minute, now),
+			syntheticQuote("b", 20.0, 1, time.Minute, now),
+			syntheticQuote("c", 20.0, 1, time.Minute, now),
+			syntheticQuote("d", 40.0, 1, time.Hour, now),
+		},
+		price: 25.0,
+	}}
+	for _, tt := range tests {
+		agg := tt.cfg.aggregate(tt.quotes, tt.weighted, now)
+		if math.Abs(agg.Price-tt.price) > 1e-9 {
+			t.Errorf("%s: price %f, expecting %f", tt.name, agg.Price, tt.price)
+		}
+		if agg.Quorum != (tt.price > 0) {
+			t.Errorf("%s: quorum %v", tt.name, agg.Quorum)
+		}
+		if len(agg.Quotes) != len(tt.quotes) {
+			t.Fatalf("%s: %d quote diagnostics, expecting %d", tt.name, len(agg.Quotes), len(tt.quotes))
+		}
+		for _, d := range agg.Quotes {
+			if d.Included == (tt.excluded[d.Token] != "") || d.Reason != tt.excluded[d.Token] {
+				t.Errorf("%s: quote %s included %v for %q, expecting reason %q", tt.name,
+					d.Token, d.Included, d.Reason, tt.excluded[d.Token])
+			}
+		}
+	}
+}
+
+func TestUpdateStateAggregates(t *testing.T) {
+	now := time.Now()
+	bot := &ExchangeBot{
+		Exchanges:     make(map[string]Exchange),
+		RequestExpiry: time.Hour,
+		config:        new(ExchangeBotConfig),
+		aggregation:   AggregationConfig{MADThreshold: 3, MinExchanges: 2},
+		currentState: ExchangeBotState{
+			DcrBtc:      make(map[string]*ExchangeState),
+			FiatIndices: make(map[string]*ExchangeState),
+			Aggregates:  make(map[string]*PriceAggregate),
+		},
+	}
+	setQuote := func(states map[string]*ExchangeState, token string, price, volume float64) {
+		bot.Exchanges[token] = newSyntheticExchange(token, now)
+		states[token] = &ExchangeState{BaseState: BaseState{Price: price, BaseVolume: volume}}
+	}
+	setQuote(bot.currentState.FiatIndices, Coinbase, 50000, 0)
+	setQuote(bot.currentState.FiatIndices, Coindesk, 50100, 0)
+	setQuote(bot.currentState.DcrBtc, Binance, 20.0, 100)
+	setQuote(bot.currentState.DcrBtc, Mexc, 20.1, 100)
+	setQuote(bot.currentState.DcrBtc, Poloniex, 19.9, 100)
+	setQuote(bot.currentState.DcrBtc, DragonEx, 23.0, 10000)
+
+	if err := bot.updateState(); err != nil {
+		t.Fatal(err)
+	}
+	if bot.failed || math.Abs(bot.currentState.Price-20) > 1e-9 {
+		t.Fatalf("price %f, failed %v, expecting 20", bot.currentState.Price, bot.failed)
+	}
+	agg := bot.stateCopy.Aggregates[TYPEDCR]
+	if agg == nil || agg.Included != 3 {
+		t.Fatalf("dcr aggregate %+v, expecting 3 included quotes", agg)
+	}
+	for _, d := range agg.Quotes {
+		if (d.Token == DragonEx) == d.Included {
+			t.Errorf("quote %s included %v", d.Token, d.Included)
+		}
+	}
+	if agg := bot.stateCopy.Aggregates["index"]; agg == nil || agg.Price != 50050 {
+		t.Errorf("index aggregate %+v, expecting a price of 50050", agg)
+	}
+
+	// Without a quorum of DCR exchanges, the price is kept and the bot fails.
+	delete(bot.currentState.DcrBtc, Binance)
+	delete(bot.currentState.DcrBtc, Mexc)
+	delete(bot.currentState.DcrBtc, Poloniex)
+	if err := bot.updateState(); err != nil {
+		t.Fatal(err)
+	}
+	if !bot.failed || math.Abs(bot.currentState.Price-20) > 1e-9 {
+		t.Errorf("price %f, failed %v, expecting a failure at 20", bot.currentState.Price, bot.failed)
+	}
+	if agg := bot.stateCopy.Aggregates[TYPEDCR]; agg.Quorum {
+		t.Errorf("quorum with %d quotes", agg.Included)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math"
 	"net"
 	"net/http"
 	"slices"
@@ -48,6 +47,9 @@ const (
 	DCRUSDSYMBOL              = "DCRUSD"
 )
 
+// Chains are the chains whose markets the ExchangeBot monitors.
+var Chains = []string{TYPEDCR, TYPEBTC, TYPELTC, TYPEXMR}
+
 // ExchangeBotConfig is the configuration options for ExchangeBot.
 // DataExpiry must be less than RequestExpiry.
 // Recommend RequestExpiry > 2*DataExpiry, which will permit the exchange API
@@ -63,6 +65,16 @@ type ExchangeBotConfig struct {
 	MasterBot      string
 	MasterCertFile string
 	BinanceAPIURL  string
+	// Store persists the candlesticks and order book snapshots of the markets.
+	// Optional.
+	Store MarketStore
+	// DepthSnapshotInterval is the minimum time between the stored order book
+	// snapshots of a market. Zero is DefaultDepthSnapshotInterval, and a
+	// negative interval disables the snapshots.
+	DepthSnapshotInterval time.Duration
+	// Aggregation configures the aggregation of the exchange prices into the
+	// price of each market. nil is DefaultAggregationConfig.
+	Aggregation *AggregationConfig
 }
 
 // ExchangeBot monitors exchanges and processes updates. When an update is
@@ -113,6 +125,12 @@ type ExchangeBot struct {
 	indexChan    chan *IndexUpdate
 	client       *http.Client
 	config       *ExchangeBotConfig
+	// store and storeQueue persist the candlesticks and order book snapshots
+	// when a MarketStore is configured.
+	store                 MarketStore
+	storeQueue            chan *ExchangeUpdate
+	depthSnapshotInterval time.Duration
+	aggregation           AggregationConfig
 	// The failed flag is set when there are either no up-to-date Bitcoin-fiat
 	// exchanges or no up-to-date Decred exchanges. IsFailed is a getter for failed.
 	failed bool
@@ -156,6 +174,9 @@ type ExchangeBotState struct {
 	// TODO: We only really need the BaseState for the fiat indices.
 	FiatIndices   map[string]*ExchangeState `json:"btc_indices"`
 	VolumnOrdered []*TokenedExchange
+	// Aggregates are the diagnostics of the last aggregation of the prices of
+	// each market, keyed by market: index, dcr, dcrbtc, btc, ltc or xmr.
+	Aggregates map[string]*PriceAggregate `json:"aggregates"`
 }
 
 type ExchangeBotStateContent struct {
@@ -193,6 +214,11 @@ func (state ExchangeBotState) copy() *ExchangeBotState {
 	state.BtcUsd = copyStates(state.BtcUsd)
 	state.XmrUsd = copyStates(state.XmrUsd)
 	state.FiatIndices = copyStates(state.FiatIndices)
+	aggregates := make(map[string]*PriceAggregate, len(state.Aggregates))
+	for market, agg := range state.Aggregates {
+		aggregates[market] = agg
+	}
+	state.Aggregates = aggregates
 	return &state
 }
 
@@ -475,6 +501,7 @@ func NewExchangeBot(config *ExchangeBotConfig) (*ExchangeBot, error) {
 			BtcUsd:      make(map[string]*ExchangeState),
 			XmrUsd:      make(map[string]*ExchangeState),
 			FiatIndices: make(map[string]*ExchangeState),
+			Aggregates:  make(map[string]*PriceAggregate),
 		},
 		currentStateBytes: []byte{},
 		DataExpiry:        dataExpiry,
@@ -488,6 +515,20 @@ func NewExchangeBot(config *ExchangeBotConfig) (*ExchangeBot, error) {
 		client:            new(http.Client),
 		config:            config,
 		failed:            false,
+		aggregation:       DefaultAggregationConfig,
+	}
+
+	if config.Aggregation != nil {
+		bot.aggregation = *config.Aggregation
+	}
+
+	if config.Store != nil {
+		bot.store = config.Store
+		bot.storeQueue = make(chan *ExchangeUpdate, 64)
+		bot.depthSnapshotInterval = config.DepthSnapshotInterval
+		if bot.depthSnapshotInterval == 0 {
+			bot.depthSnapshotInterval = DefaultDepthSnapshotInterval
+		}
 	}
 
 	if config.MasterBot != "" {
@@ -623,6 +664,9 @@ func (bot *ExchangeBot) Start(ctx context.Context, wg *sync.WaitGroup) {
 	tick := time.NewTimer(time.Second)
 	config := bot.config
 	reconnectionAttempt := 0
+	if bot.store != nil {
+		go bot.runStore(ctx)
+	}
 	if config.MasterBot != "" {
 		stream, err := bot.connectMasterBot(ctx, 0)
 		if err != nil {
@@ -664,26 +708,7 @@ func (bot *ExchangeBot) Start(ctx context.Context, wg *sync.WaitGroup) {
 						reconnectionAttempt = 0
 						continue
 					}
-					// Send the update through the Exchange so that appropriate attributes
-					// are set.
-					if IsDcrExchange(update.Token, update.Symbol) {
-						state := exchangeStateFromProto(update)
-						bot.Exchanges[update.Token].Update(state)
-					} else if IsBtcIndex(update.Token) {
-						bot.Exchanges[update.Token].UpdateIndices(update.GetIndices())
-					}
-					if IsLTCExchange(update.Token, update.Symbol) {
-						state := exchangeStateFromProto(update)
-						bot.LTCExchanges[update.Token].Update(state)
-					}
-					if IsBTCExchange(update.Token, update.Symbol) {
-						state := exchangeStateFromProto(update)
-						bot.BTCExchanges[update.Token].Update(state)
-					}
-					if IsXMRExchange(update.Token, update.Symbol) {
-						state := exchangeStateFromProto(update)
-						bot.XMRExchanges[update.Token].Update(state)
-					}
+					bot.updateFromMaster(update)
 				}
 			}()
 		}
@@ -745,6 +770,7 @@ out:
 				continue
 			}
 			bot.signalExchangeUpdate(update)
+			bot.queueStore(update)
 		case update := <-bot.indexChan:
 			btcPrice, found := update.Indices[bot.BtcIndex]
 			if found {
@@ -798,21 +824,64 @@ func (bot *ExchangeBot) connectMasterBot(ctx context.Context, delay time.Duratio
 	}
 	bot.masterConnection = conn
 	grpcClient := dcrrates.NewDCRRatesClient(conn)
-	stream, err := grpcClient.SubscribeExchanges(ctx, &dcrrates.ExchangeSubscription{
+	// The lists of DCR, LTC and BTC exchanges are still sent for servers that
+	// predate the exchanges of each chain.
+	subscription := &dcrrates.ExchangeSubscription{
 		BtcIndex:     bot.BtcIndex,
 		Exchanges:    bot.subscribedExchanges(),
 		LtcExchanges: bot.subscribedMutilchainExchanges(TYPELTC),
 		BtcExchanges: bot.subscribedMutilchainExchanges(TYPEBTC),
-		XmrExchanges: bot.subscribedMutilchainExchanges(TYPEXMR),
-	})
+	}
+	for _, chainType := range Chains {
+		subscription.ChainExchanges = append(subscription.ChainExchanges, &dcrrates.ChainExchanges{
+			Chain:     chainType,
+			Exchanges: bot.subscribedMutilchainExchanges(chainType),
+		})
+	}
+	stream, err := grpcClient.SubscribeExchanges(ctx, subscription)
 	if err != nil {
 		return nil, err
 	}
 	return stream, nil
 }
 
+// updateFromMaster sends an update from the DCRRates server through its
+// Exchange so that appropriate attributes are set. Updates from servers that
+// predate the chain of the updates have no chain, which is then guessed from
+// the token and symbol.
+func (bot *ExchangeBot) updateFromMaster(update *dcrrates.ExchangeRateUpdate) {
+	chainType := update.GetChain()
+	if chainType == "" {
+		switch {
+		case IsDcrExchange(update.Token, update.Symbol):
+			chainType = TYPEDCR
+		case IsBtcIndex(update.Token):
+			if xc, found := bot.Exchanges[update.Token]; found {
+				xc.UpdateIndices(update.GetIndices())
+			}
+			return
+		case IsLTCExchange(update.Token, update.Symbol):
+			chainType = TYPELTC
+		case IsBTCExchange(update.Token, update.Symbol):
+			chainType = TYPEBTC
+		case IsXMRExchange(update.Token, update.Symbol):
+			chainType = TYPEXMR
+		default:
+			return
+		}
+	}
+	xc, found := bot.getMutilchainExchanges(chainType)[update.Token]
+	if !found {
+		log.Debugf("DCRRates update for unmonitored %s exchange %s", chainType, update.Token)
+		return
+	}
+	xc.Update(exchangeStateFromProto(update))
+}
+
 func (bot *ExchangeBot) getMutilchainExchanges(chainType string) map[string]Exchange {
 	switch chainType {
+	case TYPEDCR:
+		return bot.Exchanges
 	case TYPEBTC:
 		return bot.BTCExchanges
 	case TYPELTC:
@@ -907,12 +976,12 @@ func (bot *ExchangeBot) ConvertedState(code string) (*ExchangeBotState, error) {
 		}
 	}
 
-	dcrPrice, dcrChange, volume, low, high := bot.processState(bot.currentState.DcrBtc, true)
-	dcrBtcPrice, dcrBtcChange, dcrBtcvolume := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
-	ltcPrice, ltcChange, ltcVolumn, ltcLow, ltcHigh := bot.processMutilchainState(bot.currentState.LtcUsd, bot.LTCExchanges, true)
-	btcExchangePrice, btcUsdChange, btcVolumn, btcLow, btcHigh := bot.processMutilchainState(bot.currentState.BtcUsd, bot.BTCExchanges, true)
-	xmrPrice, xmrChange, xmrVolumn, xmrLow, xmrHigh := bot.processMutilchainState(bot.currentState.XmrUsd, bot.XMRExchanges, true)
-	btcPrice, _, _, _, _ := bot.processState(fiatIndices, false)
+	dcrPrice, dcrChange, volume, low, high, dcrAgg := bot.processState(bot.currentState.DcrBtc, true)
+	dcrBtcPrice, dcrBtcChange, dcrBtcvolume, dcrBtcAgg := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
+	ltcPrice, ltcChange, ltcVolumn, ltcLow, ltcHigh, ltcAgg := bot.processMutilchainState(bot.currentState.LtcUsd, bot.LTCExchanges, true)
+	btcExchangePrice, btcUsdChange, btcVolumn, btcLow, btcHigh, btcAgg := bot.processMutilchainState(bot.currentState.BtcUsd, bot.BTCExchanges, true)
+	xmrPrice, xmrChange, xmrVolumn, xmrLow, xmrHigh, xmrAgg := bot.processMutilchainState(bot.currentState.XmrUsd, bot.XMRExchanges, true)
+	btcPrice, _, _, _, _, indexAgg := bot.processState(fiatIndices, false)
 	if dcrPrice == 0 || btcPrice == 0 {
 		bot.failed = true
 		return nil, fmt.Errorf("Unable to process price for currency %s", code)
@@ -948,6 +1017,14 @@ func (bot *ExchangeBot) ConvertedState(code string) (*ExchangeBotState, error) {
 		DCRUSD24hChange:   dcrChange,
 		DCRBTC24hChange:   dcrBtcChange,
 		LTCPriceChange:    ltcChange,
+		Aggregates: map[string]*PriceAggregate{
+			"index":  indexAgg,
+			TYPEDCR:  dcrAgg,
+			"dcrbtc": dcrBtcAgg,
+			TYPEBTC:  btcAgg,
+			TYPELTC:  ltcAgg,
+			TYPEXMR:  xmrAgg,
+		},
 	}
 
 	return state.copy(), nil
@@ -994,8 +1071,8 @@ func (bot *ExchangeBot) ConvertedRates(code string) (*ExchangeRates, error) {
 		}
 	}
 
-	dcrPrice, _, _, _, _ := bot.processState(bot.currentState.DcrBtc, true)
-	btcPrice, _, _, _, _ := bot.processState(fiatIndices, false)
+	dcrPrice, _, _, _, _, _ := bot.processState(bot.currentState.DcrBtc, true)
+	btcPrice, _, _, _, _, _ := bot.processState(fiatIndices, false)
 	if dcrPrice == 0 || btcPrice == 0 {
 		bot.failed = true
 		return nil, fmt.Errorf("Unable to process price for currency %s", code)
@@ -1082,123 +1159,66 @@ func (bot *ExchangeBot) cachedChartVersion(chartId string) int {
 	return cid
 }
 
-func (bot *ExchangeBot) processMutilchainState(states map[string]*ExchangeState, exchanges map[string]Exchange, volumeAveraged bool) (float64, float64, float64, float64, float64) {
-	var priceAccumulator, volSum, changeSum float64
+// quotes collects the quotes of the states accepted by filter, and deletes the
+// states of the exchanges that have not been updated within RequestExpiry.
+func (bot *ExchangeBot) quotes(states map[string]*ExchangeState, exchanges map[string]Exchange, filter func(token string) bool) []*quote {
+	var quotes []*quote
 	var deletions []string
 	oldestValid := time.Now().Add(-bot.RequestExpiry)
-	lowPrice := math.MaxFloat64
-	highPrice := float64(0)
 	for token, state := range states {
-		if exchanges[token].LastUpdate().Before(oldestValid) {
-			deletions = append(deletions, token)
+		if !filter(token) {
 			continue
 		}
-		volume := 1.0
-		if volumeAveraged {
-			volume = state.BaseVolume
-		}
-		volSum += volume
-		priceAccumulator += volume * state.Price
-		changeSum += volume * state.Change
-		// compare with low price
-		if state.Low > 0 && state.Low < lowPrice {
-			lowPrice = state.Low
-		}
-		// compare with high price
-		if state.High > 0 && state.High > highPrice {
-			highPrice = state.High
+		lastUpdate := exchanges[token].LastUpdate()
+		if lastUpdate.Before(oldestValid) {
+			deletions = append(deletions, token)
+			continue
 		}
+		quotes = append(quotes, &quote{
+			token:      token,
+			state:      state,
+			lastUpdate: lastUpdate,
+		})
 	}
 	for _, token := range deletions {
 		delete(states, token)
 	}
-	if volSum == 0 {
-		return 0, 0, 0, 0, 0
-	}
-	return priceAccumulator / volSum, changeSum / volSum, volSum, lowPrice, highPrice
+	return quotes
+}
+
+func (bot *ExchangeBot) processMutilchainState(states map[string]*ExchangeState, exchanges map[string]Exchange, volumeAveraged bool) (float64, float64, float64, float64, float64, *PriceAggregate) {
+	quotes := bot.quotes(states, exchanges, func(string) bool { return true })
+	agg := bot.aggregation.aggregate(quotes, volumeAveraged, time.Now())
+	return agg.Price, agg.change, agg.volume, agg.low, agg.high, agg
 }
 
 // processState is a helper function to process a slice of ExchangeState into
 // a price, and optionally a volume sum, and perform some cleanup along the way.
 // If volumeAveraged is false, all exchanges are given equal weight in the avg.
-func (bot *ExchangeBot) processState(states map[string]*ExchangeState, volumeAveraged bool) (float64, float64, float64, float64, float64) {
-	var priceAccumulator, volSum, changeSum float64
-	var deletions []string
-	oldestValid := time.Now().Add(-bot.RequestExpiry)
-	lowPrice := math.MaxFloat64
-	highPrice := float64(0)
-	for token, state := range states {
-		if IsDCRBTCExchange(token) {
-			continue
-		}
-		if bot.Exchanges[token].LastUpdate().Before(oldestValid) {
-			deletions = append(deletions, token)
-			continue
-		}
-		volume := 1.0
-		if volumeAveraged {
-			volume = state.BaseVolume
-		}
-		volSum += volume
-		priceAccumulator += volume * state.Price
-		changeSum += volume * state.Change
-		// compare with low price
-		if state.Low > 0 && state.Low < lowPrice {
-			lowPrice = state.Low
-		}
-		// compare with high price
-		if state.High > 0 && state.High > highPrice {
-			highPrice = state.High
-		}
-	}
-	for _, token := range deletions {
-		delete(states, token)
-	}
-	if volSum == 0 {
-		return 0, 0, 0, 0, 0
-	}
-	if lowPrice == math.MaxFloat64 {
-		lowPrice = 0
-	}
-	return priceAccumulator / volSum, changeSum / volSum, volSum, lowPrice, highPrice
+// Stale quotes and outliers are excluded as configured by the
+// AggregationConfig, and the returned PriceAggregate describes which exchanges
+// were included.
+func (bot *ExchangeBot) processState(states map[string]*ExchangeState, volumeAveraged bool) (float64, float64, float64, float64, float64, *PriceAggregate) {
+	quotes := bot.quotes(states, bot.Exchanges, func(token string) bool { return !IsDCRBTCExchange(token) })
+	agg := bot.aggregation.aggregate(quotes, volumeAveraged, time.Now())
+	return agg.Price, agg.change, agg.volume, agg.low, agg.high, agg
 }
 
-func (bot *ExchangeBot) processDCRBTCState(states map[string]*ExchangeState, volumeAveraged bool) (float64, float64, float64) {
-	var priceAccumulator, volSum, changeSum float64
-	var deletions []string
-	oldestValid := time.Now().Add(-bot.RequestExpiry)
-	for token, state := range states {
-		if !IsDCRBTCExchange(token) {
-			continue
-		}
-		if bot.Exchanges[token].LastUpdate().Before(oldestValid) {
-			deletions = append(deletions, token)
-			continue
-		}
-		volume := 1.0
-		if volumeAveraged {
-			volume = state.BaseVolume
-		}
-		volSum += volume
-		priceAccumulator += volume * state.Price
-		changeSum += volume * state.Change
-	}
-	for _, token := range deletions {
-		delete(states, token)
-	}
-	if volSum == 0 {
-		return 0, 0, 0
-	}
-	return priceAccumulator / volSum, changeSum / volSum, volSum
+func (bot *ExchangeBot) processDCRBTCState(states map[string]*ExchangeState, volumeAveraged bool) (float64, float64, float64, *PriceAggregate) {
+	quotes := bot.quotes(states, bot.Exchanges, IsDCRBTCExchange)
+	agg := bot.aggregation.aggregate(quotes, volumeAveraged, time.Now())
+	return agg.Price, agg.change, agg.volume, agg
 }
 
 // updateExchange processes an update from a Decred-BTC Exchange.
 func (bot *ExchangeBot) updateExchange(update *ExchangeUpdate) error {
 	bot.mtx.Lock()
 	defer bot.mtx.Unlock()
+	chainType := SymbolChain(update.State.Symbol)
 	if update.State.Candlesticks != nil {
 		for bin := range update.State.Candlesticks {
 			bot.incrementChart(genCacheID(update.Token, string(bin)))
+			bot.incrementChart(genMutilchainCacheID(chainType, update.Token, string(bin)))
 		}
 	}
 	if update.State.Depth != nil {
@@ -1206,21 +1226,7 @@ func (bot *ExchangeBot) updateExchange(update *ExchangeUpdate) error {
 		bot.incrementChart(genCacheID(aggregatedOrderbookKey, orderbookKey))
 		bot.incrementChart(genCacheID(aggregatedBTCOrderbookKey, orderbookKey))
 	}
-	var chainType string
-	switch update.State.Symbol {
-	case LTCSYMBOL:
-		bot.currentState.LtcUsd[update.Token] = update.State
-		chainType = TYPELTC
-	case BTCSYMBOL:
-		bot.currentState.BtcUsd[update.Token] = update.State
-		chainType = TYPEBTC
-	case XMRSYMBOL:
-		bot.currentState.XmrUsd[update.Token] = update.State
-		chainType = TYPEXMR
-	default:
-		bot.currentState.DcrBtc[update.Token] = update.State
-		chainType = TYPEDCR
-	}
+	bot.currentState.GetMutilchainExchangeState(chainType)[update.Token] = update.State
 	return bot.updateMutilchainState(chainType)
 }
 
@@ -1247,7 +1253,8 @@ func (bot *ExchangeBot) updateIndices(update *IndexUpdate) error {
 func (bot *ExchangeBot) updateMutilchainState(chainType string) error {
 	switch chainType {
 	case TYPELTC:
-		ltcPrice, ltcChange, ltcVolumn, ltcLow, ltcHigh := bot.processMutilchainState(bot.currentState.LtcUsd, bot.LTCExchanges, true)
+		ltcPrice, ltcChange, ltcVolumn, ltcLow, ltcHigh, ltcAgg := bot.processMutilchainState(bot.currentState.LtcUsd, bot.LTCExchanges, true)
+		bot.currentState.Aggregates[TYPELTC] = ltcAgg
 		if ltcPrice == 0 {
 			bot.failed = true
 		} else {
@@ -1259,7 +1266,8 @@ func (bot *ExchangeBot) updateMutilchainState(chainType string) error {
 			bot.currentState.LTCPriceChange = ltcChange
 		}
 	case TYPEBTC:
-		btcPrice, btcChange, btcVolumn, btcLow, btcHigh := bot.processMutilchainState(bot.currentState.BtcUsd, bot.BTCExchanges, true)
+		btcPrice, btcChange, btcVolumn, btcLow, btcHigh, btcAgg := bot.processMutilchainState(bot.currentState.BtcUsd, bot.BTCExchanges, true)
+		bot.currentState.Aggregates[TYPEBTC] = btcAgg
 		if btcPrice == 0 {
 			bot.failed = true
 		} else {
@@ -1271,7 +1279,8 @@ func (bot *ExchangeBot) updateMutilchainState(chainType string) error {
 			bot.currentState.BTCPriceChange = btcChange
 		}
 	case TYPEXMR:
-		xmrPrice, xmrChange, xmrVolumn, xmrLow, xmrHigh := bot.processMutilchainState(bot.currentState.XmrUsd, bot.XMRExchanges, true)
+		xmrPrice, xmrChange, xmrVolumn, xmrLow, xmrHigh, xmrAgg := bot.processMutilchainState(bot.currentState.XmrUsd, bot.XMRExchanges, true)
+		bot.currentState.Aggregates[TYPEXMR] = xmrAgg
 		if xmrPrice == 0 {
 			bot.failed = true
 		} else {
@@ -1283,9 +1292,10 @@ func (bot *ExchangeBot) updateMutilchainState(chainType string) error {
 			bot.currentState.XMRPriceChange = xmrChange
 		}
 	default:
-		dcrPrice, dcrChange, volume, lowPrice, highPrice := bot.processState(bot.currentState.DcrBtc, true)
-		dcrBtcPrice, dcrBtcChange, dcrBtcVolume := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
-		btcPrice, _, _, _, _ := bot.processState(bot.currentState.FiatIndices, false)
+		dcrPrice, dcrChange, volume, lowPrice, highPrice, dcrAgg := bot.processState(bot.currentState.DcrBtc, true)
+		dcrBtcPrice, dcrBtcChange, dcrBtcVolume, dcrBtcAgg := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
+		btcPrice, _, _, _, _, indexAgg := bot.processState(bot.currentState.FiatIndices, false)
+		bot.setDCRAggregates(dcrAgg, dcrBtcAgg, indexAgg)
 		if dcrPrice == 0 || btcPrice == 0 {
 			bot.failed = true
 		} else {
@@ -1318,11 +1328,20 @@ func (bot *ExchangeBot) updateMutilchainState(chainType string) error {
 	return nil
 }
 
+// setDCRAggregates sets the aggregation diagnostics of the Decred markets and
+// the fiat indices in the current state (under mutex lock).
+func (bot *ExchangeBot) setDCRAggregates(dcrAgg, dcrBtcAgg, indexAgg *PriceAggregate) {
+	bot.currentState.Aggregates[TYPEDCR] = dcrAgg
+	bot.currentState.Aggregates["dcrbtc"] = dcrBtcAgg
+	bot.currentState.Aggregates["index"] = indexAgg
+}
+
 // Called from both updateIndices and updateExchange (under mutex lock).
 func (bot *ExchangeBot) updateState() error {
-	dcrPrice, dcrChange, volume, lowPrice, highPrice := bot.processState(bot.currentState.DcrBtc, true)
-	dcrBtcPrice, dcrBtcChange, dcrBtcVolume := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
-	btcPrice, _, _, _, _ := bot.processState(bot.currentState.FiatIndices, false)
+	dcrPrice, dcrChange, volume, lowPrice, highPrice, dcrAgg := bot.processState(bot.currentState.DcrBtc, true)
+	dcrBtcPrice, dcrBtcChange, dcrBtcVolume, dcrBtcAgg := bot.processDCRBTCState(bot.currentState.DcrBtc, true)
+	btcPrice, _, _, _, _, indexAgg := bot.processState(bot.currentState.FiatIndices, false)
+	bot.setDCRAggregates(dcrAgg, dcrBtcAgg, indexAgg)
 	if dcrPrice == 0 || btcPrice == 0 {
 		bot.failed = true
 	} else {
@@ -1365,6 +1384,49 @@ func (bot *ExchangeBot) IsFailed() bool {
 	return bot.failed
 }
 
+// ExchangeHealth is the update status of an exchange of one of the markets of
+// the ExchangeBot.
+type ExchangeHealth struct {
+	Token  string
+	Market string // index, dcr, btc, ltc or xmr
+	// LastUpdate is the time of the last successful update.
+	LastUpdate time.Time
+	Failed     bool
+	// WsErrors is the number of websocket errors since the last websocket
+	// update, for exchanges with a websocket connection.
+	WsErrors int
+}
+
+// ExchangeHealth returns the update status of every exchange.
+func (bot *ExchangeBot) ExchangeHealth() []*ExchangeHealth {
+	markets := []struct {
+		name      string
+		exchanges map[string]Exchange
+	}{
+		{"index", bot.IndexExchanges},
+		{TYPEDCR, bot.DcrBtcExchanges},
+		{TYPEBTC, bot.BTCUSDExchanges},
+		{TYPELTC, bot.LTCUSDExchanges},
+		{TYPEXMR, bot.XMRUSDExchanges},
+	}
+	var health []*ExchangeHealth
+	for _, market := range markets {
+		for token, xc := range market.exchanges {
+			h := &ExchangeHealth{
+				Token:      token,
+				Market:     market.name,
+				LastUpdate: xc.LastUpdate(),
+				Failed:     xc.IsFailed(),
+			}
+			if wsx, ok := xc.(interface{ wsErrorCount() int }); ok {
+				h.WsErrors = wsx.wsErrorCount()
+			}
+			health = append(health, h)
+		}
+	}
+	return health
+}
+
 // nextTick checks the exchanges' last update and fail times, and calculates
 // when the next Cycle should run.
 func (bot *ExchangeBot) nextTick() *time.Timer {
@@ -1497,91 +1559,53 @@ func (bot *ExchangeBot) fetchFromCache(chartID string) (data []byte, bestVersion
 	return
 }
 
+// MutilchainQuickSticks returns the up-to-date candlestick data for the
+// specified exchange of a chain and bin width, pulling from the cache if
+// appropriate.
 func (bot *ExchangeBot) MutilchainQuickSticks(token string, rawBin string, chainType string) ([]byte, error) {
-	chartID := genMutilchainCacheID(chainType, token, rawBin)
-	bin := candlestickKey(rawBin)
-	data, bestVersion, isGood := bot.fetchFromCache(chartID)
-	if isGood {
-		return data, nil
-	}
-	// No hit on cache. Re-encode.
-	bot.mtx.Lock()
-	defer bot.mtx.Unlock()
-	currentState := bot.currentState.GetMutilchainExchangeState(chainType)
-	state, found := currentState[token]
-	if !found {
-		return nil, fmt.Errorf("Failed to find DCR exchange state for %s", token)
-	}
-	if state.Candlesticks == nil {
-		return nil, fmt.Errorf("Failed to find candlesticks for %s", token)
-	}
-
-	sticks, found := state.Candlesticks[bin]
-	if !found {
-		return nil, fmt.Errorf("Failed to find candlesticks for %s and bin %s", token, rawBin)
-	}
-	if len(sticks) == 0 {
-		return nil, fmt.Errorf("Empty candlesticks for %s and bin %s", token, rawBin)
-	}
-
-	expiration := sticks[len(sticks)-1].Start.Add(2 * bin.duration())
-
-	chart, err := bot.encodeJSON(&candlestickResponse{
-		BtcIndex:   bot.BtcIndex,
-		Price:      bot.currentState.GetMutilchainPrice(chainType),
-		Sticks:     sticks,
-		Expiration: expiration.Unix(),
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("JSON encode error for %s and bin %s", token, rawBin)
-	}
-
-	vChart := &versionedChart{
-		chartID: chartID,
-		dataID:  bestVersion,
-		chart:   chart,
-	}
-
-	bot.versionedCharts[chartID] = vChart
-	return vChart.chart, nil
+	return bot.quickSticks(genMutilchainCacheID(chainType, token, rawBin), chainType, token, rawBin)
 }
 
 // QuickSticks returns the up-to-date candlestick data for the specified
 // exchange and bin width, pulling from the cache if appropriate.
 func (bot *ExchangeBot) QuickSticks(token string, rawBin string) ([]byte, error) {
-	chartID := genCacheID(token, rawBin)
+	return bot.quickSticks(genCacheID(token, rawBin), TYPEDCR, token, rawBin)
+}
+
+func (bot *ExchangeBot) quickSticks(chartID, chainType, token, rawBin string) ([]byte, error) {
 	bin := candlestickKey(rawBin)
 	data, bestVersion, isGood := bot.fetchFromCache(chartID)
 	if isGood {
 		return data, nil
 	}
 
-	// No hit on cache. Re-encode.
+	// No hit on cache. Re-encode, with the stored candlesticks if there is a
+	// MarketStore.
+	var stored Candlesticks
+	if bot.store != nil {
+		var err error
+		stored, err = bot.store.Candlesticks(chainType, token, rawBin, storedSticksLimit)
+		if err != nil {
+			log.Errorf("Failed to retrieve the %s candlesticks of %s %s: %v", rawBin, chainType, token, err)
+		}
+	}
 
 	bot.mtx.Lock()
 	defer bot.mtx.Unlock()
-	state, found := bot.currentState.DcrBtc[token]
-	if !found {
-		return nil, fmt.Errorf("Failed to find DCR exchange state for %s", token)
-	}
-	if state.Candlesticks == nil {
-		return nil, fmt.Errorf("Failed to find candlesticks for %s", token)
-	}
-
-	sticks, found := state.Candlesticks[bin]
-	if !found {
-		return nil, fmt.Errorf("Failed to find candlesticks for %s and bin %s", token, rawBin)
+	var live Candlesticks
+	if state, found := bot.currentState.GetMutilchainExchangeState(chainType)[token]; found {
+		live = state.Candlesticks[bin]
 	}
+	sticks := mergeSticks(stored, live)
 	if len(sticks) == 0 {
-		return nil, fmt.Errorf("Empty candlesticks for %s and bin %s", token, rawBin)
+		return nil, fmt.Errorf("Failed to find candlesticks for %s and bin %s", token, rawBin)
 	}
 
 	expiration := sticks[len(sticks)-1].Start.Add(2 * bin.duration())
 
 	chart, err := bot.encodeJSON(&candlestickResponse{
 		BtcIndex:   bot.BtcIndex,
-		Price:      bot.currentState.Price,
+		Price:      bot.currentState.GetMutilchainPrice(chainType),
 		Sticks:     sticks,
 		Expiration: expiration.Unix(),
 	})
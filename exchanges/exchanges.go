@@ -506,6 +506,21 @@ func IsXMRExchange(token string, symbol string) bool {
 	return exchange != nil
 }
 
+// SymbolChain returns the chain of the market of an ExchangeState symbol. The
+// Decred markets have the DCRBTC or DCRUSD symbols, or none.
+func SymbolChain(symbol string) string {
+	switch symbol {
+	case LTCSYMBOL:
+		return TYPELTC
+	case BTCSYMBOL:
+		return TYPEBTC
+	case XMRSYMBOL:
+		return TYPEXMR
+	default:
+		return TYPEDCR
+	}
+}
+
 // Tokens is a new slice of available exchange tokens.
 func Tokens() []string {
 	tokens := make([]string, 0, len(BtcIndices)+len(DcrExchanges))
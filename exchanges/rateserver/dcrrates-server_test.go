@@ -4,8 +4,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/decred/dcrdata/exchanges/v3"
@@ -43,6 +45,71 @@ func TestSendStateList(t *testing.T) {
 	}
 }
 
+type streamStub struct {
+	sent []*dcrrates.ExchangeRateUpdate
+}
+
+func (s *streamStub) Send(update *dcrrates.ExchangeRateUpdate) error {
+	s.sent = append(s.sent, update)
+	return nil
+}
+
+func (s *streamStub) Context() context.Context {
+	return context.Background()
+}
+
+func TestSendExchangeUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hello *dcrrates.ExchangeSubscription
+		sent  []string
+	}{{
+		name: "legacy",
+		hello: &dcrrates.ExchangeSubscription{
+			Exchanges:    []string{"binance", "coindesk"},
+			LtcExchanges: []string{"binance"},
+		},
+		sent: []string{"dcr binance", " coindesk", "ltc binance"},
+	}, {
+		name: "chains",
+		hello: &dcrrates.ExchangeSubscription{
+			Exchanges: []string{"binance", "coindesk"},
+			ChainExchanges: []*dcrrates.ChainExchanges{
+				{Chain: exchanges.TYPEDCR, Exchanges: []string{"binance", "coindesk"}},
+				{Chain: exchanges.TYPEXMR, Exchanges: []string{"kraken"}},
+			},
+		},
+		sent: []string{"dcr binance", " coindesk", "xmr kraken"},
+	}}
+	updates := []*dcrrates.ExchangeRateUpdate{
+		makeExchangeRateUpdate(&exchanges.ExchangeUpdate{Token: "binance",
+			State: &exchanges.ExchangeState{BaseState: exchanges.BaseState{Symbol: exchanges.DCRBTCSYMBOL}}}),
+		{Token: "coindesk", Indices: map[string]float64{"USD": 1}},
+		makeExchangeRateUpdate(&exchanges.ExchangeUpdate{Token: "binance",
+			State: &exchanges.ExchangeState{BaseState: exchanges.BaseState{Symbol: exchanges.LTCSYMBOL}}}),
+		makeExchangeRateUpdate(&exchanges.ExchangeUpdate{Token: "kraken",
+			State: &exchanges.ExchangeState{BaseState: exchanges.BaseState{Symbol: exchanges.XMRSYMBOL}}}),
+		makeExchangeRateUpdate(&exchanges.ExchangeUpdate{Token: "bittrex",
+			State: &exchanges.ExchangeState{BaseState: exchanges.BaseState{Symbol: exchanges.BTCSYMBOL}}}),
+	}
+	for _, tt := range tests {
+		stream := new(streamStub)
+		client := NewRateClient(stream, subscriptionExchanges(tt.hello))
+		for _, update := range updates {
+			if err := client.SendExchangeUpdate(update); err != nil {
+				t.Fatalf("%s: SendExchangeUpdate error: %v", tt.name, err)
+			}
+		}
+		var sent []string
+		for _, update := range stream.sent {
+			sent = append(sent, update.Chain+" "+update.Token)
+		}
+		if !slices.Equal(sent, tt.sent) {
+			t.Errorf("%s: sent %v, expecting %v", tt.name, sent, tt.sent)
+		}
+	}
+}
+
 type certWriterStub struct {
 	lengths map[string]int
 }
@@ -78,14 +78,12 @@ func main() {
 	}
 	var xcList, prepend string
 	tokenList := make([]string, 0)
-	for k := range xcBot.Exchanges {
-		if !slices.Contains(tokenList, k) {
-			tokenList = append(tokenList, k)
-		}
-	}
-	for k := range xcBot.LTCExchanges {
-		if !slices.Contains(tokenList, k) {
-			tokenList = append(tokenList, k)
+	for _, xcs := range []map[string]exchanges.Exchange{xcBot.Exchanges,
+		xcBot.LTCExchanges, xcBot.BTCExchanges, xcBot.XMRExchanges} {
+		for k := range xcs {
+			if !slices.Contains(tokenList, k) {
+				tokenList = append(tokenList, k)
+			}
 		}
 	}
 
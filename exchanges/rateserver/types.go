@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/decred/dcrdata/exchanges/v3"
@@ -93,11 +94,14 @@ func (server *RateServer) ReallySubscribeExchanges(hello *dcrrates.ExchangeSubsc
 		log.Infof("Client has connected from %s", clientAddr)
 	}
 
-	// Send Decred exchanges.
+	// Send the exchanges of each chain.
 	state := server.xcBot.State()
-	if err != nil {
-		err = sendStateList(client, state.DcrBtc)
-		return err
+	for _, chainType := range exchanges.Chains {
+		err = sendStateList(client, state.GetMutilchainExchangeState(chainType))
+		if err != nil {
+			server.deleteClient(sid)
+			return err
+		}
 	}
 	// Send Bitcoin-fiat indices.
 	for token := range state.FiatIndices {
@@ -125,7 +129,7 @@ func (server *RateServer) ReallySubscribeExchanges(hello *dcrrates.ExchangeSubsc
 func (server *RateServer) addClient(stream GRPCStream, hello *dcrrates.ExchangeSubscription) (RateClient, StreamID) {
 	server.clientLock.Lock()
 	defer server.clientLock.Unlock()
-	client := NewRateClient(stream, hello.GetExchanges())
+	client := NewRateClient(stream, subscriptionExchanges(hello))
 	streamCounter++
 	server.clients[streamCounter] = client
 	return client, streamCounter
@@ -138,16 +142,32 @@ func (server *RateServer) deleteClient(sid StreamID) {
 	delete(server.clients, sid)
 }
 
-// A rateClient stores a client's gRPC stream and a list of exchange tokens
-// to which they are subscribed. rateClient satisfies the RateClient interface.
+// subscriptionExchanges returns the exchange tokens of each chain of a
+// subscription. Older clients only send the lists of DCR, LTC and BTC
+// exchanges.
+func subscriptionExchanges(hello *dcrrates.ExchangeSubscription) map[string][]string {
+	chainExchanges := map[string][]string{
+		exchanges.TYPEDCR: hello.GetExchanges(),
+		exchanges.TYPELTC: hello.GetLtcExchanges(),
+		exchanges.TYPEBTC: hello.GetBtcExchanges(),
+	}
+	for _, xcs := range hello.GetChainExchanges() {
+		chainExchanges[xcs.GetChain()] = xcs.GetExchanges()
+	}
+	return chainExchanges
+}
+
+// A rateClient stores a client's gRPC stream and the exchange tokens of each
+// chain to which they are subscribed. rateClient satisfies the RateClient
+// interface.
 type rateClient struct {
 	stream    GRPCStream
-	exchanges []string
+	exchanges map[string][]string
 }
 
 // NewRateClient is a constructor for rate client. It returns the RateClient
 // interface rather than rateClient itself.
-func NewRateClient(stream GRPCStream, exchanges []string) RateClient {
+func NewRateClient(stream GRPCStream, exchanges map[string][]string) RateClient {
 	return &rateClient{
 		stream:    stream,
 		exchanges: exchanges,
@@ -165,6 +185,7 @@ func makeExchangeRateUpdate(update *exchanges.ExchangeUpdate) *dcrrates.Exchange
 		Volume:     state.Volume,
 		Change:     state.Change,
 		Stamp:      state.Stamp,
+		Chain:      exchanges.SymbolChain(state.Symbol),
 	}
 	if state.Candlesticks != nil {
 		protoUpdate.Candlesticks = make([]*dcrrates.ExchangeRateUpdate_Candlesticks, 0, len(state.Candlesticks))
@@ -211,13 +232,16 @@ func makeExchangeRateUpdate(update *exchanges.ExchangeUpdate) *dcrrates.Exchange
 	return protoUpdate
 }
 
-// SendExchangeUpdate sends the update if the client is subscribed to the exchange.
+// SendExchangeUpdate sends the update if the client is subscribed to the
+// exchange of the chain of the update. The Bitcoin indices, which have no
+// chain, are subscribed with the Decred exchanges.
 func (client *rateClient) SendExchangeUpdate(update *dcrrates.ExchangeRateUpdate) (err error) {
-	for i := range client.exchanges {
-		if client.exchanges[i] == update.Token {
-			err = client.stream.Send(update)
-			return
-		}
+	chainType := update.GetChain()
+	if chainType == "" {
+		chainType = exchanges.TYPEDCR
+	}
+	if slices.Contains(client.exchanges[chainType], update.Token) {
+		err = client.stream.Send(update)
 	}
 	return
 }
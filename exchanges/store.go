@@ -0,0 +1,238 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultDepthSnapshotInterval is the default minimum time between the
+	// stored order book snapshots of a market.
+	DefaultDepthSnapshotInterval = 30 * time.Minute
+
+	// storedSticksLimit is the maximum number of stored candlesticks in a
+	// candlestick chart.
+	storedSticksLimit = 5000
+	// liquidityRange is the price range around the mid-gap, as a fraction of
+	// the mid-gap, of the bid and ask depths of the Liquidity.
+	liquidityRange = 0.02
+	// snapshotRange is the price range around the mid-gap of the orders of
+	// a DepthSnapshot.
+	snapshotRange = 0.1
+	// liquidityKey identifies the liquidity charts in the chart cache.
+	liquidityKey = "liquidity"
+)
+
+// MarketStore persists the candlesticks and order book snapshots of the
+// markets. With a MarketStore, the candlestick charts are served from the
+// store, so they are available right after a restart and reach further back
+// than the retention of the exchanges. The first refresh of the exchanges
+// after a restart fills the gap of the downtime.
+type MarketStore interface {
+	// StoreCandlesticks stores the candlesticks of a market and bin, replacing
+	// the stored ones with the same start time.
+	StoreCandlesticks(chainType, token, bin string, sticks Candlesticks) error
+	// Candlesticks retrieves the latest candlesticks of a market and bin, at
+	// most limit, in ascending order of start time.
+	Candlesticks(chainType, token, bin string, limit int) (Candlesticks, error)
+	// StoreDepthSnapshot stores an order book snapshot of a market.
+	StoreDepthSnapshot(chainType, token string, snapshot *DepthSnapshot) error
+	// Liquidity retrieves the liquidity of the stored order book snapshots of
+	// a market, in ascending order of time.
+	Liquidity(chainType, token string) ([]*Liquidity, error)
+}
+
+// Liquidity is the liquidity of an order book: the mid-gap price, the spread
+// between the best ask and the best bid, and the quantities of the bids and
+// the asks within 2% of the mid-gap.
+type Liquidity struct {
+	Time     int64   `json:"time"`
+	MidGap   float64 `json:"mid_gap"`
+	Spread   float64 `json:"spread"`
+	BidDepth float64 `json:"bid_depth"`
+	AskDepth float64 `json:"ask_depth"`
+}
+
+// DepthSnapshot is an order book snapshot with its Liquidity. The book is
+// limited to the orders within 10% of the mid-gap.
+type DepthSnapshot struct {
+	Liquidity
+	Bids []DepthPoint
+	Asks []DepthPoint
+}
+
+// newDepthSnapshot creates a DepthSnapshot of an order book, whose bids and
+// asks are sorted from the best price.
+func newDepthSnapshot(depth *DepthData) *DepthSnapshot {
+	midGap := depth.MidGap()
+	snapshot := &DepthSnapshot{
+		Liquidity: Liquidity{
+			Time:   depth.Time,
+			MidGap: midGap,
+		},
+	}
+	if len(depth.Bids) > 0 && len(depth.Asks) > 0 {
+		snapshot.Spread = depth.Asks[0].Price - depth.Bids[0].Price
+	}
+	for _, pt := range depth.Bids {
+		if pt.Price < midGap*(1-snapshotRange) {
+			break
+		}
+		if pt.Price >= midGap*(1-liquidityRange) {
+			snapshot.BidDepth += pt.Quantity
+		}
+		snapshot.Bids = append(snapshot.Bids, pt)
+	}
+	for _, pt := range depth.Asks {
+		if pt.Price > midGap*(1+snapshotRange) {
+			break
+		}
+		if pt.Price <= midGap*(1+liquidityRange) {
+			snapshot.AskDepth += pt.Quantity
+		}
+		snapshot.Asks = append(snapshot.Asks, pt)
+	}
+	return snapshot
+}
+
+// mergeSticks appends the in-memory candlesticks of an exchange, which may not
+// be stored yet, to the stored candlesticks that precede them.
+func mergeSticks(stored, live Candlesticks) Candlesticks {
+	if len(live) == 0 {
+		return stored
+	}
+	first := live[0].Start
+	i := sort.Search(len(stored), func(i int) bool {
+		return !stored[i].Start.Before(first)
+	})
+	merged := make(Candlesticks, 0, i+len(live))
+	merged = append(merged, stored[:i]...)
+	return append(merged, live...)
+}
+
+// queueStore queues the candlesticks and order book of an update for storage.
+func (bot *ExchangeBot) queueStore(update *ExchangeUpdate) {
+	if bot.store == nil || (!update.State.HasCandlesticks() && !update.State.HasDepth()) {
+		return
+	}
+	select {
+	case bot.storeQueue <- update:
+	default:
+		log.Warnf("Market store queue is full. Dropping the update from %s", update.Token)
+	}
+}
+
+// runStore stores the queued updates until the context is canceled.
+func (bot *ExchangeBot) runStore(ctx context.Context) {
+	// The start time of the last stored candlestick, and the time of the last
+	// stored order book snapshot, by chart ID.
+	lastSticks := make(map[string]time.Time)
+	lastSnapshots := make(map[string]time.Time)
+	for {
+		select {
+		case update := <-bot.storeQueue:
+			bot.storeUpdate(update, lastSticks, lastSnapshots)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (bot *ExchangeBot) storeUpdate(update *ExchangeUpdate, lastSticks, lastSnapshots map[string]time.Time) {
+	chainType := SymbolChain(update.State.Symbol)
+	for bin, sticks := range update.State.Candlesticks {
+		if len(sticks) == 0 {
+			continue
+		}
+		// The exchanges return the same candlesticks on every refresh, so only
+		// those from the last stored one, which may have been in progress, are
+		// stored.
+		chartID := genMutilchainCacheID(chainType, update.Token, string(bin))
+		last := lastSticks[chartID]
+		i := sort.Search(len(sticks), func(i int) bool {
+			return !sticks[i].Start.Before(last)
+		})
+		if i == len(sticks) {
+			continue
+		}
+		err := bot.store.StoreCandlesticks(chainType, update.Token, string(bin), sticks[i:])
+		if err != nil {
+			log.Errorf("Failed to store the %s candlesticks of %s %s: %v", bin, chainType, update.Token, err)
+			continue
+		}
+		lastSticks[chartID] = sticks[len(sticks)-1].Start
+		bot.mtx.Lock()
+		bot.incrementChart(chartID)
+		if chainType == TYPEDCR {
+			bot.incrementChart(genCacheID(update.Token, string(bin)))
+		}
+		bot.mtx.Unlock()
+	}
+
+	depth := update.State.Depth
+	if depth == nil || bot.depthSnapshotInterval <= 0 {
+		return
+	}
+	chartID := genMutilchainCacheID(chainType, update.Token, liquidityKey)
+	depthTime := time.Unix(depth.Time, 0)
+	if depthTime.Sub(lastSnapshots[chartID]) < bot.depthSnapshotInterval {
+		return
+	}
+	if err := bot.store.StoreDepthSnapshot(chainType, update.Token, newDepthSnapshot(depth)); err != nil {
+		log.Errorf("Failed to store the order book of %s %s: %v", chainType, update.Token, err)
+		return
+	}
+	lastSnapshots[chartID] = depthTime
+	bot.mtx.Lock()
+	bot.incrementChart(chartID)
+	bot.mtx.Unlock()
+}
+
+type liquidityResponse struct {
+	BtcIndex  string       `json:"index"`
+	Price     float64      `json:"price"`
+	Liquidity []*Liquidity `json:"liquidity"`
+}
+
+// QuickLiquidity returns the liquidity of the stored order book snapshots of
+// a market, pulling from the cache if appropriate.
+func (bot *ExchangeBot) QuickLiquidity(token string, chainType string) ([]byte, error) {
+	if bot.store == nil {
+		return nil, fmt.Errorf("No market store")
+	}
+	chartID := genMutilchainCacheID(chainType, token, liquidityKey)
+	data, bestVersion, isGood := bot.fetchFromCache(chartID)
+	if isGood {
+		return data, nil
+	}
+
+	liquidity, err := bot.store.Liquidity(chainType, token)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve the liquidity of %s %s: %v", chainType, token, err)
+	}
+	if len(liquidity) == 0 {
+		return nil, fmt.Errorf("No order book snapshots for %s %s", chainType, token)
+	}
+
+	bot.mtx.Lock()
+	defer bot.mtx.Unlock()
+	chart, err := bot.encodeJSON(&liquidityResponse{
+		BtcIndex:  bot.BtcIndex,
+		Price:     bot.currentState.GetMutilchainPrice(chainType),
+		Liquidity: liquidity,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("JSON encode error for the liquidity of %s %s", chainType, token)
+	}
+	bot.versionedCharts[chartID] = &versionedChart{
+		chartID: chartID,
+		dataID:  bestVersion,
+		chart:   chart,
+	}
+	return chart, nil
+}
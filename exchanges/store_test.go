@@ -0,0 +1,116 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package exchanges
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type testMarketStore struct {
+	sticks    map[string]Candlesticks
+	snapshots []*DepthSnapshot
+}
+
+func (s *testMarketStore) StoreCandlesticks(chainType, token, bin string, sticks Candlesticks) error {
+	key := chainType + token + bin
+	s.sticks[key] = mergeSticks(s.sticks[key], sticks)
+	return nil
+}
+
+func (s *testMarketStore) Candlesticks(chainType, token, bin string, limit int) (Candlesticks, error) {
+	return s.sticks[chainType+token+bin], nil
+}
+
+func (s *testMarketStore) StoreDepthSnapshot(chainType, token string, snapshot *DepthSnapshot) error {
+	s.snapshots = append(s.snapshots, snapshot)
+	return nil
+}
+
+func (s *testMarketStore) Liquidity(chainType, token string) ([]*Liquidity, error) {
+	liquidity := make([]*Liquidity, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		liquidity = append(liquidity, &snapshot.Liquidity)
+	}
+	return liquidity, nil
+}
+
+func testSticks(start time.Time, closes ...float64) Candlesticks {
+	sticks := make(Candlesticks, 0, len(closes))
+	for i, c := range closes {
+		sticks = append(sticks, Candlestick{Close: c, Start: start.Add(time.Duration(i) * time.Hour)})
+	}
+	return sticks
+}
+
+func TestNewDepthSnapshot(t *testing.T) {
+	snapshot := newDepthSnapshot(&DepthData{
+		Time: 1700000000,
+		Bids: []DepthPoint{{2, 99.5}, {3, 98.5}, {4, 95}, {5, 80}},
+		Asks: []DepthPoint{{1, 100.5}, {6, 103}, {7, 115}},
+	})
+	if snapshot.MidGap != 100 || snapshot.Spread != 1 {
+		t.Errorf("mid-gap %f and spread %f, expecting 100 and 1", snapshot.MidGap, snapshot.Spread)
+	}
+	if snapshot.BidDepth != 5 || snapshot.AskDepth != 1 {
+		t.Errorf("bid depth %f and ask depth %f, expecting 5 and 1", snapshot.BidDepth, snapshot.AskDepth)
+	}
+	if len(snapshot.Bids) != 3 || len(snapshot.Asks) != 2 {
+		t.Errorf("%d bids and %d asks in the snapshot, expecting 3 and 2", len(snapshot.Bids), len(snapshot.Asks))
+	}
+}
+
+func TestStoredSticks(t *testing.T) {
+	store := &testMarketStore{sticks: make(map[string]Candlesticks)}
+	bot := &ExchangeBot{
+		versionedCharts:       make(map[string]*versionedChart),
+		chartVersions:         make(map[string]int),
+		config:                new(ExchangeBotConfig),
+		store:                 store,
+		depthSnapshotInterval: time.Hour,
+		currentState: ExchangeBotState{
+			DcrBtc: make(map[string]*ExchangeState),
+			LtcUsd: make(map[string]*ExchangeState),
+		},
+	}
+	lastSticks := make(map[string]time.Time)
+	lastSnapshots := make(map[string]time.Time)
+	start := time.Unix(1700000000, 0)
+	update := func(sticks Candlesticks, depthTime int64) {
+		state := &ExchangeState{
+			BaseState:    BaseState{Symbol: LTCSYMBOL},
+			Candlesticks: map[candlestickKey]Candlesticks{hourKey: sticks},
+			Depth:        &DepthData{Time: depthTime},
+		}
+		bot.storeUpdate(&ExchangeUpdate{Token: "binance", State: state}, lastSticks, lastSnapshots)
+		bot.currentState.LtcUsd["binance"] = state
+	}
+
+	// The exchange only returns the last 3 candlesticks, which are all kept in
+	// the store.
+	update(testSticks(start, 1, 2, 3), start.Unix())
+	update(testSticks(start.Add(2*time.Hour), 4, 5, 6), start.Add(30*time.Minute).Unix())
+	if got := store.sticks["ltcbinance1h"]; len(got) != 5 || got[2].Close != 4 {
+		t.Fatalf("stored candlesticks %v, expecting 5 ending with 4, 5, 6", got)
+	}
+	if len(store.snapshots) != 1 {
+		t.Errorf("%d order book snapshots stored, expecting 1", len(store.snapshots))
+	}
+
+	// After a restart, the stored candlesticks are served before the exchange
+	// is refreshed.
+	delete(bot.currentState.LtcUsd, "binance")
+	chart, err := bot.MutilchainQuickSticks("binance", "1h", TYPELTC)
+	if err != nil {
+		t.Fatalf("MutilchainQuickSticks error: %v", err)
+	}
+	var resp candlestickResponse
+	if err = json.Unmarshal(chart, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Sticks) != 5 || resp.Sticks[4].Close != 6 {
+		t.Errorf("chart candlesticks %v, expecting 5 ending with 6", resp.Sticks)
+	}
+}
@@ -9,12 +9,14 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	exptypes "github.com/decred/dcrdata/v8/explorer/types"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
 	"github.com/decred/dcrdata/v8/txhelpers"
 )
 
@@ -50,6 +52,12 @@ type MempoolMonitor struct {
 	params     *chaincfg.Params
 	collector  *DataCollector
 	dataSavers []MempoolDataSaver
+	// lastUpdate is the UNIX time in nanoseconds of the last collection or
+	// new transaction.
+	lastUpdate atomic.Int64
+
+	// Outgoing message
+	signalOuts []chan<- pstypes.HubMessage
 }
 
 // NewMempoolMonitor creates a new MempoolMonitor. The MempoolMonitor receives
@@ -58,7 +66,8 @@ type MempoolMonitor struct {
 // MempoolMonitor will process incoming transactions, and forward new ones on
 // via the newTxOutChan following an appropriate signal on hubRelay.
 func NewMempoolMonitor(ctx context.Context, collector *DataCollector,
-	savers []MempoolDataSaver, params *chaincfg.Params, initialStore bool) (*MempoolMonitor, error) {
+	savers []MempoolDataSaver, params *chaincfg.Params,
+	signalOuts []chan<- pstypes.HubMessage, initialStore bool) (*MempoolMonitor, error) {
 
 	// Make the skeleton MempoolMonitor.
 	p := &MempoolMonitor{
@@ -66,6 +75,7 @@ func NewMempoolMonitor(ctx context.Context, collector *DataCollector,
 		params:     params,
 		collector:  collector,
 		dataSavers: savers,
+		signalOuts: signalOuts,
 	}
 
 	if initialStore {
@@ -96,6 +106,15 @@ func (p *MempoolMonitor) LastBlockTime() int64 {
 	return p.lastBlock.Time
 }
 
+// LastUpdate returns the time of the last collection of the mempool data or of
+// the last new transaction, or the zero time if there was none.
+func (p *MempoolMonitor) LastUpdate() time.Time {
+	if t := p.lastUpdate.Load(); t != 0 {
+		return time.Unix(0, t)
+	}
+	return time.Time{}
+}
+
 // TxHandler receives signals from OnTxAccepted via the newTxIn, indicating that
 // a new transaction has entered mempool. This function should be launched as a
 // goroutine, and stopped by closing the quit channel, the broadcasting
@@ -153,6 +172,15 @@ func (p *MempoolMonitor) TxHandler(rawTx *btcjson.TxRawResult) error {
 	}
 	p.addrMap.mtx.Unlock()
 
+	// Send address signals.
+	for addr := range txAddresses {
+		log.Tracef("Signaling address tx mempool event to hub relays...")
+		p.hubSend(pstypes.SigBTCAddressTx, &pstypes.AddressMessage{
+			Address: addr,
+			TxHash:  hash,
+		}, time.Second*10)
+	}
+
 	// Store the current mempool transaction, block info zeroed.
 	p.txnsStore[msgTx.TxHash()] = &txhelpers.BTCTxWithBlockData{
 		Tx:          msgTx,
@@ -187,9 +215,25 @@ func (p *MempoolMonitor) TxHandler(rawTx *btcjson.TxRawResult) error {
 	p.inventory.FormattedTotalSize = exptypes.BytesString(uint64(p.inventory.TotalSize))
 	p.inventory.Unlock()
 	p.mtx.RUnlock()
+
+	p.lastUpdate.Store(time.Now().UnixNano())
+
+	// Broadcast the new transaction.
+	log.Tracef("Signaling new tx to hub relays...")
+	p.hubSend(pstypes.SigBTCNewTx, &tx, time.Second*10)
 	return nil
 }
 
+func (p *MempoolMonitor) hubSend(sig pstypes.HubSignal, msg interface{}, timeout time.Duration) {
+	for _, sigout := range p.signalOuts {
+		select {
+		case sigout <- pstypes.HubMessage{Signal: sig, Msg: msg}:
+		case <-time.After(timeout):
+			log.Errorf("send to signalOuts (%v) failed: Timeout waiting for WebsocketHub.", sig)
+		}
+	}
+}
+
 // Refresh collects mempool data, resets counters ticket counters and the timer,
 // but does not dispatch the MempoolDataSavers.
 func (p *MempoolMonitor) Refresh() ([]exptypes.MempoolTx, *exptypes.MutilchainMempoolInfo, error) {
@@ -223,6 +267,7 @@ func (p *MempoolMonitor) Refresh() ([]exptypes.MempoolTx, *exptypes.MutilchainMe
 	p.addrMap.mtx.Lock()
 	p.addrMap.store = addrOuts
 	p.addrMap.mtx.Unlock()
+	p.lastUpdate.Store(time.Now().UnixNano())
 
 	return txs, inventory, err
 }
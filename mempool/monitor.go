@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/decred/dcrd/blockchain/stake/v5"
@@ -53,6 +54,9 @@ type MempoolMonitor struct {
 	params     *chaincfg.Params
 	collector  *DataCollector
 	dataSavers []MempoolDataSaver
+	// lastUpdate is the UNIX time in nanoseconds of the last collection or
+	// new transaction.
+	lastUpdate atomic.Int64
 
 	// Outgoing message
 	signalOuts []chan<- pstypes.HubMessage
@@ -104,6 +108,15 @@ func (p *MempoolMonitor) LastBlockTime() int64 {
 	return p.lastBlock.Time
 }
 
+// LastUpdate returns the time of the last collection of the mempool data or of
+// the last new transaction, or the zero time if there was none.
+func (p *MempoolMonitor) LastUpdate() time.Time {
+	if t := p.lastUpdate.Load(); t != 0 {
+		return time.Unix(0, t)
+	}
+	return time.Time{}
+}
+
 // BlockHandler satisfies notification.BlockHandler. Triggers a websocket update.
 func (p *MempoolMonitor) BlockHandler(height uint32, _ string) error {
 	// Signal a new block
@@ -356,6 +369,8 @@ func (p *MempoolMonitor) TxHandler(rawTx *chainjson.TxRawResult) error {
 	p.inventory.Unlock()
 	p.mtx.RUnlock()
 
+	p.lastUpdate.Store(time.Now().UnixNano())
+
 	// Broadcast the new transaction.
 	log.Tracef("Signaling new tx to hub relays...")
 	p.hubSend(pstypes.SigNewTx, &tx, time.Second*10)
@@ -413,6 +428,7 @@ func (p *MempoolMonitor) Refresh() (*StakeData, []exptypes.MempoolTx, *exptypes.
 	p.addrMap.mtx.Lock()
 	p.addrMap.store = addrOuts
 	p.addrMap.mtx.Unlock()
+	p.lastUpdate.Store(time.Now().UnixNano())
 
 	// Insert new ticket counter into stakeData structure.
 	stakeData.NewTickets = uint32(newTickets)
@@ -80,6 +80,7 @@ type TxNumPerBlockAvgData struct {
 }
 
 func HandlerMutilchainChartsData(charts *cache.MutilchainChartData) error {
+	recordFallback(charts.ChainType, nil)
 	//handler for block chain size chart
 	HandlerBlockchainSizeData(charts)
 	//hanlder for blocksize
@@ -0,0 +1,67 @@
+package externalapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+var cryptoCompareHistoDayURL = `https://min-api.cryptocompare.com/data/v2/histoday`
+
+// CryptoCompareMaxDays is the maximum number of days of a histoday request.
+const CryptoCompareMaxDays = 2000
+
+type CryptoCompareHistoResponse struct {
+	Response string                 `json:"Response"`
+	Message  string                 `json:"Message"`
+	Data     CryptoCompareHistoData `json:"Data"`
+}
+
+type CryptoCompareHistoData struct {
+	TimeFrom int64                   `json:"TimeFrom"`
+	TimeTo   int64                   `json:"TimeTo"`
+	Data     []CryptoCompareHistoDay `json:"Data"`
+}
+
+type CryptoCompareHistoDay struct {
+	Time  int64   `json:"time"`
+	Close float64 `json:"close"`
+}
+
+// GetDailyPriceHistory retrieves the daily closing prices of a chain's coin in
+// a fiat currency, for at most limit days up to the day of toTs, in ascending
+// order of date. The days before the coin was listed are skipped.
+func GetDailyPriceHistory(chainType, currency string, toTs int64, limit int) ([]dbtypes.DailyPrice, error) {
+	var res CryptoCompareHistoResponse
+	query := map[string]string{
+		"fsym":  strings.ToUpper(chainType),
+		"tsym":  strings.ToUpper(currency),
+		"limit": strconv.Itoa(limit),
+		"toTs":  strconv.FormatInt(toTs, 10),
+	}
+	req := &ReqConfig{
+		Method:  http.MethodGet,
+		HttpUrl: cryptoCompareHistoDayURL,
+		Payload: query,
+	}
+	if err := HttpRequest(req, &res); err != nil {
+		return nil, err
+	}
+	if res.Response != "Success" {
+		return nil, fmt.Errorf("%s %s price history: %s", chainType, currency, res.Message)
+	}
+	prices := make([]dbtypes.DailyPrice, 0, len(res.Data.Data))
+	for _, day := range res.Data.Data {
+		if day.Close <= 0 {
+			continue
+		}
+		prices = append(prices, dbtypes.DailyPrice{
+			Date:  day.Time,
+			Price: day.Close,
+		})
+	}
+	return prices, nil
+}
@@ -0,0 +1,44 @@
+package externalapi
+
+import (
+	"sync"
+	"time"
+)
+
+// FallbackUse describes the use of the external APIs in place of the chain DB
+// for the address and chart data of a chain.
+type FallbackUse struct {
+	Count    int64
+	Failures int64
+	Last     time.Time
+}
+
+var (
+	fallbackMtx  sync.Mutex
+	fallbackUses = make(map[string]*FallbackUse)
+)
+
+func recordFallback(chainType string, err error) {
+	fallbackMtx.Lock()
+	defer fallbackMtx.Unlock()
+	use := fallbackUses[chainType]
+	if use == nil {
+		use = new(FallbackUse)
+		fallbackUses[chainType] = use
+	}
+	use.Count++
+	if err != nil {
+		use.Failures++
+	}
+	use.Last = time.Now()
+}
+
+// Fallbacks returns the use of the external APIs for the chain since startup.
+func Fallbacks(chainType string) FallbackUse {
+	fallbackMtx.Lock()
+	defer fallbackMtx.Unlock()
+	if use := fallbackUses[chainType]; use != nil {
+		return *use
+	}
+	return FallbackUse{}
+}
@@ -44,10 +44,13 @@ func GetAPIMutilchainAddressDetails(okLinkAPIKey, address string, chainType stri
 		//Get from API
 		addrInfo, err := GetAddressDetailsByAPIEnv(okLinkAPIKey, address, chainType, api, limit, offset, chainHeight, txnType)
 		if err == nil {
+			recordFallback(chainType, nil)
 			return addrInfo, nil
 		}
 	}
-	return nil, fmt.Errorf("%s", "Get address info from all API failed")
+	err := fmt.Errorf("%s", "Get address info from all API failed")
+	recordFallback(chainType, err)
+	return nil, err
 }
 
 func GetAddressDetailsByAPIEnv(okLinkAPIKey, address, chainType, apiType string, limit, offset, chainHeight int64, txnType dbtypes.AddrTxnViewType) (*APIAddressInfo, error) {
@@ -10,8 +10,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/decred/dcrd/chaincfg/v3"
-	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/slog"
 	survey "gopkg.in/AlecAivazis/survey.v1"
 
@@ -35,13 +33,15 @@ func main() {
 	backend.SetLevel(slog.LevelDebug)
 	psclient.UseLogger(backend)
 
-	params := chaincfg.MainNetParams()
-
 	// Create the pubsub client, opening a connection to the URL.
 	ctx, cancel := context.WithCancel(context.Background())
 	opts := psclient.Opts{
 		ReadTimeout:  psclient.DefaultReadTimeout,
 		WriteTimeout: 3 * time.Second,
+		// Reconnect and replay the subscriptions if the connection is lost,
+		// and check that the server still pings us.
+		Reconnect:    true,
+		PingInterval: time.Minute,
 	}
 	cl, err := psclient.New(cfg.URL, ctx, &opts)
 	if err != nil {
@@ -54,7 +54,10 @@ func main() {
 
 	// Subscribe/unsubscribe to several events.
 	var currentSubs []string
-	allSubs := []string{"ping", "newtxs", "newblock", "newltcblock", "newbtcblock", "mempool", "address:Dcur2mcGjmENx4DhNqDctW5wJCVyT3Qeqkx", "address"}
+	allSubs := []string{"ping", "newtxs", "btcnewtxs", "ltcnewtxs", "newblock", "newltcblock", "newbtcblock",
+		"mempool", "summaryinfo", "summary24h", "address:Dcur2mcGjmENx4DhNqDctW5wJCVyT3Qeqkx", "address",
+		"btcaddress", "ltcaddress"}
+	addrSubs := []string{"address", "btcaddress", "ltcaddress"}
 	subscribe := func(newsubs []string) error {
 		for _, sub := range newsubs {
 			if subd, _ := strInSlice(currentSubs, sub); subd {
@@ -127,24 +130,24 @@ func main() {
 
 			switch a.action {
 			case "subscribe":
-				subPrompt.Default = AnotInB(allSubs, append(currentSubs, "address"))
+				subPrompt.Default = AnotInB(allSubs, append(currentSubs, addrSubs...))
 				_ = survey.AskOne(subPrompt, &a.data, nil)
 				data := make([]string, 0, len(a.data))
 				for i := range a.data {
-					if a.data[i] == "address" {
+					if found, _ := strInSlice(addrSubs, a.data[i]); found {
 						var addr string
-						err = survey.AskOne(&survey.Input{Message: "Type the address."}, &addr, nil)
+						err = survey.AskOne(&survey.Input{Message: "Type the " + a.data[i] + "."}, &addr, nil)
 						if err != nil {
 							log.Fatal(err)
 							continue
 						}
-						_, err = stdaddr.DecodeAddress(addr, params)
-						if err != nil {
-							log.Fatalf("Invalid address %s: %v", addr, err)
+						sub := a.data[i] + ":" + addr
+						if _, _, valid := pstypes.ValidateSubscription(sub); !valid {
+							log.Fatalf("Invalid address %s.", addr)
 							continue
 						}
 
-						data = append(data, a.data[i]+":"+addr)
+						data = append(data, sub)
 					} else {
 						data = append(data, a.data[i])
 					}
@@ -205,7 +208,7 @@ func main() {
 	for {
 		msg := <-cl.Receive()
 		if msg == nil {
-			fmt.Printf("ReceiveMsg failed: %v", err)
+			fmt.Println("Connection closed. Bye!")
 			return
 		}
 
@@ -221,8 +224,20 @@ func main() {
 		case int:
 			// e.g. "ping"
 			log.Printf("Message (%s): %d", msg.EventId, m)
+		case *psclient.Gap:
+			log.Printf("Reconnected after %v (%d attempts, cause: %v). Events from %v to %v were missed. Replaying %v.",
+				m.End.Sub(m.Start).Round(time.Second), m.Attempts, m.Err,
+				m.Start.Format(time.RFC3339), m.End.Format(time.RFC3339), m.Subscriptions)
 		case *exptypes.WebsocketBlock:
-			log.Printf("Message (%s): WebsocketBlock(hash=%s)", msg.EventId, m.Block.Hash)
+			if m.Block != nil {
+				log.Printf("Message (%s): WebsocketBlock(height=%d, hash=%s)", msg.EventId, m.Block.Height, m.Block.Hash)
+			}
+		case *exptypes.WebsocketSummary:
+			if m.Summary24h != nil {
+				log.Printf("Message (%s): WebsocketSummary(blocks24h=%d)", msg.EventId, m.Summary24h.Blocks)
+			} else {
+				log.Printf("Message (%s): WebsocketSummary", msg.EventId)
+			}
 		case *exptypes.MempoolShort:
 			t := time.Unix(m.Time, 0)
 			log.Printf("Message (%s): MempoolShort(numTx=%d, time=%v)",
@@ -233,8 +248,8 @@ func main() {
 			log.Printf("Message (%s): AddressMessage(address=%s, txHash=%s)",
 				msg.EventId, m.Address, m.TxHash)
 		case *pstypes.HangUp:
-			log.Printf("Hung up. Bye!")
-			return
+			// The client reconnects, and the channel is closed if it cannot.
+			log.Printf("Hung up. Reconnecting...")
 		default:
 			log.Printf("Message of type %v unhandled.", msg.EventId)
 		}
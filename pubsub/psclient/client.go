@@ -3,12 +3,17 @@ package psclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	exptypes "github.com/decred/dcrdata/v8/explorer/types"
+	"github.com/decred/dcrdata/v8/mutilchain"
 	pubsub "github.com/decred/dcrdata/v8/pubsub"
 	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
 	"github.com/decred/dcrdata/v8/semver"
@@ -95,27 +100,111 @@ func newPingMsg(reqID int64) []byte {
 const (
 	DefaultReadTimeout  = pubsub.PingInterval * 10 / 9
 	DefaultWriteTimeout = 5 * time.Second
+
+	// DefaultMinReconnectDelay and DefaultMaxReconnectDelay bound the
+	// exponential backoff of the reconnect attempts.
+	DefaultMinReconnectDelay = time.Second
+	DefaultMaxReconnectDelay = time.Minute
+
+	// ReconnectEventID is the EventId of the ClientMessage with a *Gap that is
+	// received after the Client reconnects.
+	ReconnectEventID = "reconnect"
 )
 
 // Opts defines the psclient Client options.
 type Opts struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+	// Reconnect makes a Client created with New reconnect with backoff when
+	// the connection is lost, and replay its active subscriptions. A Gap is
+	// received after each reconnect.
+	Reconnect bool
+	// MinReconnectDelay and MaxReconnectDelay override the defaults.
+	MinReconnectDelay time.Duration
+	MaxReconnectDelay time.Duration
+	// PingInterval enables the liveness check if non-zero. The Client pings
+	// the server at this interval, and closes the connection if the pings of
+	// the server stop arriving.
+	PingInterval time.Duration
+}
+
+// Gap describes an interruption of the connection of a reconnecting Client,
+// during which the events of its subscriptions were missed.
+type Gap struct {
+	// Start is when the connection was lost, and End when it was restored.
+	Start time.Time
+	End   time.Time
+	// Err is why the connection was lost.
+	Err error
+	// Attempts is the number of connection attempts.
+	Attempts int
+	// Subscriptions are the subscriptions that are replayed.
+	Subscriptions []string
 }
 
 // Client wraps a *websocket.Conn.
 type Client struct {
 	*websocket.Conn
-	ourConn       bool
-	readTimeout   time.Duration
-	writeTimeout  time.Duration
-	reqMtx        sync.Mutex
-	recvMsgChan   chan *ClientMessage
-	nextRequestID int64
-	requests      map[int64]chan *pstypes.ResponseMessage
-	sendMtx       sync.Mutex
-	ctx           context.Context
-	shutdown      context.CancelFunc
+	ourConn           bool
+	url               string
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	reconnect         bool
+	minReconnectDelay time.Duration
+	maxReconnectDelay time.Duration
+	pingInterval      time.Duration
+	lastPing          atomic.Int64 // unix nano of the last server ping
+	reqMtx            sync.Mutex
+	recvMsgChan       chan *ClientMessage
+	nextRequestID     int64
+	requests          map[int64]chan *pstypes.ResponseMessage
+	subsMtx           sync.Mutex
+	subs              map[string]struct{}
+	// sendMtx serializes the writes, and guards the replacement of Conn by
+	// the receiver on reconnect.
+	sendMtx  sync.Mutex
+	ctx      context.Context
+	shutdown context.CancelFunc
+}
+
+func newClient(ws *websocket.Conn, ctx context.Context, opts *Opts) *Client {
+	readTimeout, writeTimeout := DefaultReadTimeout, DefaultWriteTimeout
+	minDelay, maxDelay := DefaultMinReconnectDelay, DefaultMaxReconnectDelay
+	var reconnect bool
+	var pingInterval time.Duration
+	if opts != nil {
+		readTimeout = opts.ReadTimeout
+		writeTimeout = opts.WriteTimeout
+		reconnect = opts.Reconnect
+		pingInterval = opts.PingInterval
+		if opts.MinReconnectDelay > 0 {
+			minDelay = opts.MinReconnectDelay
+		}
+		if opts.MaxReconnectDelay > 0 {
+			maxDelay = opts.MaxReconnectDelay
+		}
+		if maxDelay < minDelay {
+			maxDelay = minDelay
+		}
+	}
+
+	ctx, shutdown := context.WithCancel(ctx)
+	cl := &Client{
+		Conn:              ws,
+		readTimeout:       readTimeout,
+		writeTimeout:      writeTimeout,
+		reconnect:         reconnect,
+		minReconnectDelay: minDelay,
+		maxReconnectDelay: maxDelay,
+		pingInterval:      pingInterval,
+		recvMsgChan:       make(chan *ClientMessage, 16),
+		requests:          make(map[int64]chan *pstypes.ResponseMessage),
+		subs:              make(map[string]struct{}),
+		ctx:               ctx,
+		shutdown:          shutdown,
+	}
+	cl.lastPing.Store(time.Now().UnixNano())
+	return cl
 }
 
 // New creates a new Client from a URL.
@@ -125,73 +214,59 @@ func New(url string, ctx context.Context, opts *Opts) (*Client, error) {
 		return nil, err
 	}
 
-	readTimeout, writeTimeout := DefaultReadTimeout, DefaultWriteTimeout
-	if opts != nil {
-		readTimeout = opts.ReadTimeout
-		writeTimeout = opts.WriteTimeout
+	cl := newClient(ws, ctx, opts)
+	cl.ourConn = true
+	cl.url = url
+
+	go cl.receiver()
+
+	if err = cl.checkServerVersion(); err != nil {
+		cl.Stop()
+		return nil, err
 	}
 
-	ctx, shutdown := context.WithCancel(ctx)
-	cl := &Client{
-		Conn:         ws,
-		ourConn:      true,
-		readTimeout:  readTimeout,
-		writeTimeout: writeTimeout,
-		recvMsgChan:  make(chan *ClientMessage, 16),
-		requests:     make(map[int64]chan *pstypes.ResponseMessage),
-		ctx:          ctx,
-		shutdown:     shutdown,
+	if cl.pingInterval > 0 {
+		go cl.pinger()
 	}
 
-	go cl.receiver()
+	return cl, nil
+}
 
-	// Query for the server's pubsub version.
-	serverVer, err := cl.ServerVersion()
+// checkServerVersion ensures the server's pubsub version (actual) is compatible
+// with the client's version (required). This allows the client to have a high
+// minor version for equal major versions.
+func (c *Client) checkServerVersion() error {
+	serverVer, err := c.ServerVersion()
 	if err != nil {
-		cl.Stop()
-		return nil, fmt.Errorf("failed to get server pubsub version: %v", err)
+		return fmt.Errorf("failed to get server pubsub version: %v", err)
 	}
 	log.Infof("Server pubsub version: %s\n", serverVer)
 
-	// Ensure the server's pubsub version (actual) is compatible with the
-	// client's version (required). This allows the client to have a high minor
-	// version for equal major versions.
 	clientSemVer := Version()
 	serverSemVer := semver.NewSemver(serverVer.Major, serverVer.Minor, serverVer.Patch)
 	if !semver.Compatible(clientSemVer, serverSemVer) {
-		cl.Stop()
-		return nil, fmt.Errorf("server pubsub version is %v, but client is version %v",
+		return fmt.Errorf("server pubsub version is %v, but client is version %v",
 			serverSemVer, clientSemVer)
 	}
-
-	return cl, nil
+	return nil
 }
 
-// NewFromConn creates a new Client from a *websocket.Conn.
+// NewFromConn creates a new Client from a *websocket.Conn. The Client cannot
+// reconnect since it does not own the connection, so Opts.Reconnect is
+// ignored.
 func NewFromConn(ws *websocket.Conn, ctx context.Context, opts *Opts) *Client {
 	if ws == nil {
 		return nil
 	}
 
-	readTimeout, writeTimeout := DefaultReadTimeout, DefaultWriteTimeout
-	if opts != nil {
-		readTimeout = opts.ReadTimeout
-		writeTimeout = opts.WriteTimeout
-	}
-
-	ctx, shutdown := context.WithCancel(ctx)
-	cl := &Client{
-		Conn:         ws,
-		readTimeout:  readTimeout,
-		writeTimeout: writeTimeout,
-		recvMsgChan:  make(chan *ClientMessage, 16),
-		requests:     make(map[int64]chan *pstypes.ResponseMessage),
-		ctx:          ctx,
-		shutdown:     shutdown,
-	}
+	cl := newClient(ws, ctx, opts)
 
 	go cl.receiver()
 
+	if cl.pingInterval > 0 {
+		go cl.pinger()
+	}
+
 	return cl
 }
 
@@ -205,7 +280,10 @@ func (c *Client) Stop() {
 
 	// Close the websocket connection.
 	if c.ourConn {
-		if err := c.Conn.Close(); err != nil {
+		c.sendMtx.Lock()
+		err := c.Conn.Close()
+		c.sendMtx.Unlock()
+		if err != nil {
 			log.Errorf("Failed to Close websocket connection: %v", err)
 		}
 	}
@@ -219,7 +297,9 @@ type ClientMessage struct {
 }
 
 // Receive gets a receive-only *ClientMessage channel, through which all
-// messages from the server to the client should be received.
+// messages from the server to the client should be received. With
+// Opts.Reconnect, the channel stays open across reconnects, and a *Gap with
+// the EventId ReconnectEventID is received after each reconnect.
 func (c *Client) Receive() <-chan *ClientMessage {
 	return c.recvMsgChan
 }
@@ -244,10 +324,17 @@ func (c *Client) receiver() {
 
 		resp, err := c.receiveMsg()
 		if err != nil {
+			if c.ctx.Err() != nil {
+				log.Trace("receiver: context canceled...")
+				return
+			}
 			// Even a timeout should close shutdown the client since that
 			// indicates pings from the server did not arrive in time.
 			log.Errorf("ReceiveMsg failed: %v", err)
-			return
+			if !c.reconnectWithBackoff(err) {
+				return
+			}
+			continue
 		}
 
 		msg, err := DecodeMsg(resp)
@@ -260,7 +347,7 @@ func (c *Client) receiver() {
 		case *pstypes.ResponseMessage:
 			log.Debugf("Response to %s request ID=%d received. Success = %v. Data: %v",
 				m.RequestEventId, m.RequestId, m.Success, m.Data)
-			respChan := c.responseChan(m.RequestId)
+			respChan := c.takeResponseChan(m.RequestId)
 			if respChan == nil {
 				log.Errorf("receiver failed to find request ID %d", m.RequestId)
 				continue
@@ -268,24 +355,36 @@ func (c *Client) receiver() {
 			go func() {
 				respChan <- m // unbuffered
 				close(respChan)
-				c.deleteRequestID(m.RequestId)
 			}()
 			continue
 		case *pstypes.HangUp:
-			log.Infof("The server is hanging up on us! Shutting down.")
+			log.Infof("The server is hanging up on us!")
 			c.recvMsgChan <- &ClientMessage{
 				EventId: resp.EventId,
 				Message: msg,
 			}
-			return
+			if !c.reconnectWithBackoff(errors.New("server hung up")) {
+				log.Infof("Shutting down.")
+				return
+			}
+			continue
 		case string:
 			// generic "message"
 			log.Debugf("Message (%s): %s", resp.EventId, m)
 		case int:
 			// e.g. "ping"
+			if resp.EventId == "ping" {
+				c.lastPing.Store(time.Now().UnixNano())
+			}
 			log.Debugf("Message (%s): %d", resp.EventId, m)
 		case *exptypes.WebsocketBlock:
-			log.Debugf("Message (%s): WebsocketBlock(hash=%s)", resp.EventId, m.Block.Hash)
+			if m.Block != nil {
+				log.Debugf("Message (%s): WebsocketBlock(hash=%s)", resp.EventId, m.Block.Hash)
+			}
+		case *exptypes.WebsocketSummary:
+			log.Debugf("Message (%s): WebsocketSummary", resp.EventId)
+		case *exptypes.WebsocketXmrMempool:
+			log.Debugf("Message (%s): WebsocketXmrMempool", resp.EventId)
 		case *exptypes.MempoolShort:
 			t := time.Unix(m.Time, 0)
 			log.Debugf("Message (%s): MempoolShort(numTx=%d, time=%v)",
@@ -307,6 +406,141 @@ func (c *Client) receiver() {
 	}
 }
 
+// reconnectWithBackoff replaces a lost connection with a new one, retrying with
+// exponential backoff until it succeeds or the Client is stopped, in which
+// case it returns false. It is false too if the Client does not reconnect.
+// After a reconnect, the Gap is sent on recvMsgChan and the active
+// subscriptions are replayed. It is to be called only by the receiver.
+func (c *Client) reconnectWithBackoff(cause error) bool {
+	if !c.reconnect || !c.ourConn {
+		return false
+	}
+
+	gap := &Gap{
+		Start: time.Now(),
+		Err:   cause,
+	}
+
+	// Requests of the lost connection are not answered.
+	c.failRequests()
+	c.closeConn()
+
+	delay := c.minReconnectDelay
+	for {
+		// Up to 25% jitter keeps the clients of a restarted server from
+		// reconnecting in lockstep.
+		wait := delay + time.Duration(rand.Int63n(int64(delay)/4+1))
+		log.Infof("Reconnecting to %s in %v...", c.url, wait.Round(time.Millisecond))
+		select {
+		case <-time.After(wait):
+		case <-c.ctx.Done():
+			return false
+		}
+
+		gap.Attempts++
+		ws, err := websocket.Dial(c.url, "", "/")
+		if err == nil {
+			c.lastPing.Store(time.Now().UnixNano())
+			c.sendMtx.Lock()
+			c.Conn = ws
+			c.sendMtx.Unlock()
+			break
+		}
+		log.Warnf("Failed to reconnect to %s: %v", c.url, err)
+
+		delay *= 2
+		if delay > c.maxReconnectDelay {
+			delay = c.maxReconnectDelay
+		}
+	}
+
+	// Stop may have closed the previous connection while dialing.
+	if c.ctx.Err() != nil {
+		c.closeConn()
+		return false
+	}
+
+	gap.End = time.Now()
+	gap.Subscriptions = c.Subscriptions()
+	log.Infof("Reconnected to %s after %v. Replaying %d subscriptions.",
+		c.url, gap.End.Sub(gap.Start).Round(time.Millisecond), len(gap.Subscriptions))
+
+	// The responses to the subscribe requests are received by this goroutine,
+	// so the subscriptions are replayed by another.
+	go c.resubscribe(gap.Subscriptions)
+
+	select {
+	case c.recvMsgChan <- &ClientMessage{EventId: ReconnectEventID, Message: gap}:
+	case <-c.ctx.Done():
+		return false
+	}
+	return true
+}
+
+// resubscribe replays the subscriptions after a reconnect.
+func (c *Client) resubscribe(subs []string) {
+	for _, sub := range subs {
+		resp, err := c.Subscribe(sub)
+		if err != nil {
+			log.Errorf("Failed to resubscribe to %s: %v", sub, err)
+			continue
+		}
+		if !resp.Success {
+			log.Errorf("Failed to resubscribe to %s: %v", sub, resp.Data)
+		}
+	}
+}
+
+// closeConn closes the current connection, which makes the receiver reconnect
+// or return.
+func (c *Client) closeConn() {
+	c.sendMtx.Lock()
+	defer c.sendMtx.Unlock()
+	if err := c.Conn.Close(); err != nil {
+		log.Debugf("Failed to Close websocket connection: %v", err)
+	}
+}
+
+// pinger pings the server every pingInterval, and closes the connection if the
+// pings of the server stopped arriving, so that the receiver reconnects. The
+// read timeout of the receiver also detects a silent server, but not one that
+// still sends events.
+func (c *Client) pinger() {
+	livenessTimeout := 2 * pubsub.PingInterval
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.ctx.Done():
+			return
+		}
+
+		if err := c.Ping(); err != nil {
+			log.Debugf("Liveness check: %v", err)
+		}
+		lastPing := time.Unix(0, c.lastPing.Load())
+		if time.Since(lastPing) > livenessTimeout {
+			log.Warnf("No ping from the server since %v. Closing the connection.",
+				lastPing.Format(time.RFC3339))
+			c.lastPing.Store(time.Now().UnixNano())
+			c.closeConn()
+		}
+	}
+}
+
+// Subscriptions returns the active subscriptions of the Client.
+func (c *Client) Subscriptions() []string {
+	c.subsMtx.Lock()
+	defer c.subsMtx.Unlock()
+	subs := make([]string, 0, len(c.subs))
+	for sub := range c.subs {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+	return subs
+}
+
 func (c *Client) send(msg []byte) error {
 	c.sendMtx.Lock()
 	defer c.sendMtx.Unlock()
@@ -315,10 +549,24 @@ func (c *Client) send(msg []byte) error {
 	return err
 }
 
-func (c *Client) responseChan(reqID int64) chan *pstypes.ResponseMessage {
+// takeResponseChan removes the response channel of a request from the pending
+// requests, and returns it.
+func (c *Client) takeResponseChan(reqID int64) chan *pstypes.ResponseMessage {
+	c.reqMtx.Lock()
+	defer c.reqMtx.Unlock()
+	respChan := c.requests[reqID]
+	delete(c.requests, reqID)
+	return respChan
+}
+
+// failRequests closes the response channels of the pending requests.
+func (c *Client) failRequests() {
 	c.reqMtx.Lock()
 	defer c.reqMtx.Unlock()
-	return c.requests[reqID]
+	for reqID, respChan := range c.requests {
+		close(respChan)
+		delete(c.requests, reqID)
+	}
 }
 
 func (c *Client) newResponseChan() (chan *pstypes.ResponseMessage, int64) {
@@ -369,6 +617,12 @@ func (c *Client) Subscribe(event string) (*pstypes.ResponseMessage, error) {
 		return nil, fmt.Errorf("Response channel closed.")
 	}
 
+	if resp.Success {
+		c.subsMtx.Lock()
+		c.subs[event] = struct{}{}
+		c.subsMtx.Unlock()
+	}
+
 	// Read the response.
 	return resp, nil
 }
@@ -392,7 +646,16 @@ func (c *Client) Unsubscribe(event string) (*pstypes.ResponseMessage, error) {
 	}
 
 	// Wait for a response with the requestID.
-	resp := <-respChan
+	resp, ok := <-respChan
+	if !ok {
+		return nil, fmt.Errorf("Response channel closed.")
+	}
+
+	if resp.Success {
+		c.subsMtx.Lock()
+		delete(c.subs, event)
+		c.subsMtx.Unlock()
+	}
 
 	// Read the response.
 	return resp, nil
@@ -410,7 +673,10 @@ func (c *Client) ServerVersion() (*pstypes.Ver, error) {
 	}
 
 	// Wait for a response with the requestID
-	resp := <-respChan
+	resp, ok := <-respChan
+	if !ok {
+		return nil, fmt.Errorf("Response channel closed.")
+	}
 	if !resp.Success {
 		return nil, fmt.Errorf("failed to obtain server version")
 	}
@@ -479,11 +745,11 @@ func DecodeMsg(msg *pstypes.WebSocketMessage) (interface{}, error) {
 		var numClients int
 		err := json.Unmarshal(msg.Message, &numClients)
 		return numClients, err
-	case "address":
+	case "address", "btcaddress", "ltcaddress":
 		var am pstypes.AddressMessage
 		err := json.Unmarshal(msg.Message, &am)
 		return &am, err
-	case "newtxs":
+	case "newtxs", "btcnewtxs", "ltcnewtxs":
 		var newtxs pstypes.TxList
 		err := json.Unmarshal(msg.Message, &newtxs)
 		return &newtxs, err
@@ -499,6 +765,18 @@ func DecodeMsg(msg *pstypes.WebSocketMessage) (interface{}, error) {
 		var newblock exptypes.WebsocketBlock
 		err := json.Unmarshal(msg.Message, &newblock)
 		return &newblock, err
+	case "newxmrblock":
+		var newblock exptypes.WebsocketBlock
+		err := json.Unmarshal(msg.Message, &newblock)
+		return &newblock, err
+	case "summaryinfo", "summary24h":
+		var summary exptypes.WebsocketSummary
+		err := json.Unmarshal(msg.Message, &summary)
+		return &summary, err
+	case "xmrMempoolStatus":
+		var mempool exptypes.WebsocketXmrMempool
+		err := json.Unmarshal(msg.Message, &mempool)
+		return &mempool, err
 	case "mempool":
 		var mpshort exptypes.MempoolShort
 		err := json.Unmarshal(msg.Message, &mpshort)
@@ -598,6 +876,67 @@ func DecodeMsgNewBlock(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketBlock,
 	return newBlock, nil
 }
 
+// decodeEventMsg attempts to decode the Message content of the given
+// WebSocketMessage as type T, after checking that its EventId is one of the
+// given event IDs.
+func decodeEventMsg[T any](msg *pstypes.WebSocketMessage, eventIDs ...string) (*T, error) {
+	if msg == nil {
+		return nil, fmt.Errorf("empty message")
+	}
+	var known bool
+	for _, eventID := range eventIDs {
+		if msg.EventId == eventID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("event %q is not one of %s", msg.EventId,
+			strings.Join(eventIDs, ", "))
+	}
+	v, err := DecodeMsg(msg)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := v.(*T)
+	if !ok {
+		return nil, fmt.Errorf("content of Message was not of type %T", t)
+	}
+	return t, nil
+}
+
+// DecodeMsgNewBTCBlock attempts to decode the Message content of the given
+// WebSocketMessage as a newbtcblock message (*exptypes.WebsocketBlock).
+func DecodeMsgNewBTCBlock(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketBlock, error) {
+	return decodeEventMsg[exptypes.WebsocketBlock](msg, "newbtcblock")
+}
+
+// DecodeMsgNewLTCBlock attempts to decode the Message content of the given
+// WebSocketMessage as a newltcblock message (*exptypes.WebsocketBlock).
+func DecodeMsgNewLTCBlock(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketBlock, error) {
+	return decodeEventMsg[exptypes.WebsocketBlock](msg, "newltcblock")
+}
+
+// DecodeMsgNewXMRBlock attempts to decode the Message content of the given
+// WebSocketMessage as a newxmrblock message (*exptypes.WebsocketBlock).
+func DecodeMsgNewXMRBlock(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketBlock, error) {
+	return decodeEventMsg[exptypes.WebsocketBlock](msg, "newxmrblock")
+}
+
+// DecodeMsgSummary attempts to decode the Message content of the given
+// WebSocketMessage as a summaryinfo or summary24h message
+// (*exptypes.WebsocketSummary).
+func DecodeMsgSummary(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketSummary, error) {
+	return decodeEventMsg[exptypes.WebsocketSummary](msg, "summaryinfo", "summary24h")
+}
+
+// DecodeMsgXmrMempool attempts to decode the Message content of the given
+// WebSocketMessage as an xmrMempoolStatus message
+// (*exptypes.WebsocketXmrMempool).
+func DecodeMsgXmrMempool(msg *pstypes.WebSocketMessage) (*exptypes.WebsocketXmrMempool, error) {
+	return decodeEventMsg[exptypes.WebsocketXmrMempool](msg, "xmrMempoolStatus")
+}
+
 // DecodeMsgNewAddressTx attempts to decode the Message content of the given
 // WebSocketMessage as an address message (*DecodeMsgNewAddressTx).
 func DecodeMsgNewAddressTx(msg *pstypes.WebSocketMessage) (*pstypes.AddressMessage, error) {
@@ -611,3 +950,37 @@ func DecodeMsgNewAddressTx(msg *pstypes.WebSocketMessage) (*pstypes.AddressMessa
 	}
 	return am, nil
 }
+
+// msgChain returns the chain of the event of the given WebSocketMessage from
+// its chain prefix, e.g. "btc" for "btcaddress". Events without a prefix are
+// Decred events.
+func msgChain(msg *pstypes.WebSocketMessage) string {
+	for _, chain := range []string{mutilchain.TYPEBTC, mutilchain.TYPELTC} {
+		if strings.HasPrefix(msg.EventId, chain) {
+			return chain
+		}
+	}
+	return mutilchain.TYPEDCR
+}
+
+// DecodeMsgChainAddressTx attempts to decode the Message content of the given
+// WebSocketMessage as an address message of any chain (address, btcaddress or
+// ltcaddress). The chain of the address is also returned.
+func DecodeMsgChainAddressTx(msg *pstypes.WebSocketMessage) (string, *pstypes.AddressMessage, error) {
+	am, err := DecodeMsgNewAddressTx(msg)
+	if err != nil {
+		return "", nil, err
+	}
+	return msgChain(msg), am, nil
+}
+
+// DecodeMsgChainTxList attempts to decode the Message content of the given
+// WebSocketMessage as a new transactions message of any chain (newtxs,
+// btcnewtxs or ltcnewtxs). The chain of the transactions is also returned.
+func DecodeMsgChainTxList(msg *pstypes.WebSocketMessage) (string, *pstypes.TxList, error) {
+	txlist, err := DecodeMsgTxList(msg)
+	if err != nil {
+		return "", nil, err
+	}
+	return msgChain(msg), txlist, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	exptypes "github.com/decred/dcrdata/v8/explorer/types"
 	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
 )
 
@@ -751,6 +752,91 @@ func TestDecodeMsgTxList(t *testing.T) {
 	}
 }
 
+func TestDecodeMsgChainTxList(t *testing.T) {
+	msg := &pstypes.WebSocketMessage{
+		EventId: "btcnewtxs",
+		Message: msgNewTxs5.Message,
+	}
+	chain, txlist, err := DecodeMsgChainTxList(msg)
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if chain != "btc" {
+		t.Errorf("expecting chain btc, got %s", chain)
+	}
+	if len(*txlist) != 5 {
+		t.Errorf("expecting 5 txns, got %d", len(*txlist))
+	}
+}
+
+func TestDecodeMsgChainAddressTx(t *testing.T) {
+	msg := &pstypes.WebSocketMessage{
+		EventId: "ltcaddress",
+		Message: json.RawMessage(`{"address":"LPcmddE5zgnUBdXUEjrcUEuNFUNzvYd6AN","transaction":"c2a4c1d7e4e4a5b3"}`),
+	}
+	chain, am, err := DecodeMsgChainAddressTx(msg)
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if chain != "ltc" {
+		t.Errorf("expecting chain ltc, got %s", chain)
+	}
+	if am.Address != "LPcmddE5zgnUBdXUEjrcUEuNFUNzvYd6AN" || am.TxHash != "c2a4c1d7e4e4a5b3" {
+		t.Errorf("unexpected address message %v", am)
+	}
+
+	msg.EventId = "address"
+	if chain, _, _ = DecodeMsgChainAddressTx(msg); chain != "dcr" {
+		t.Errorf("expecting chain dcr, got %s", chain)
+	}
+}
+
+func TestDecodeMsgChainBlocks(t *testing.T) {
+	blockMsg := json.RawMessage(`{"block":{"hash":"5e3d2c6bb1c38f6e1b0bbbd0bd6cc1bd3cc60b6a15dffaa4c3f1e2b2d0f4f0aa","height":3123456}}`)
+	decoders := map[string]func(*pstypes.WebSocketMessage) (*exptypes.WebsocketBlock, error){
+		"newbtcblock": DecodeMsgNewBTCBlock,
+		"newltcblock": DecodeMsgNewLTCBlock,
+		"newxmrblock": DecodeMsgNewXMRBlock,
+	}
+	for eventID, decode := range decoders {
+		newBlock, err := decode(&pstypes.WebSocketMessage{EventId: eventID, Message: blockMsg})
+		if err != nil {
+			t.Fatalf("failed to decode %s message: %v", eventID, err)
+		}
+		if newBlock.Block == nil || newBlock.Block.Height != 3123456 {
+			t.Errorf("wrong %s block %v", eventID, newBlock.Block)
+		}
+		// The decoders do not accept the blocks of other chains.
+		if _, err = decode(&pstypes.WebSocketMessage{EventId: "newblock", Message: blockMsg}); err == nil {
+			t.Errorf("%s decoder accepted a newblock message", eventID)
+		}
+	}
+}
+
+func TestDecodeMsgSummaryAndXmrMempool(t *testing.T) {
+	summary, err := DecodeMsgSummary(&pstypes.WebSocketMessage{
+		EventId: "summary24h",
+		Message: json.RawMessage(`{"summary_info":null,"summary_24h":{"blocks":288}}`),
+	})
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if summary.Summary24h == nil {
+		t.Errorf("missing summary_24h")
+	}
+
+	mempool, err := DecodeMsgXmrMempool(&pstypes.WebSocketMessage{
+		EventId: "xmrMempoolStatus",
+		Message: json.RawMessage(`{"xmr_mempool":{"tx_count":7,"status":"OK"}}`),
+	})
+	if err != nil {
+		t.Fatalf("failed to decode message: %v", err)
+	}
+	if mempool.XmrMempool == nil || mempool.XmrMempool.TxCount != 7 {
+		t.Errorf("wrong xmr mempool %v", mempool.XmrMempool)
+	}
+}
+
 func TestDecodeMsgMempool(t *testing.T) {
 	mpShort, err := DecodeMsgMempool(msgMempool5Latest)
 	if err != nil {
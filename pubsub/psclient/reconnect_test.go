@@ -0,0 +1,130 @@
+package psclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	pubsub "github.com/decred/dcrdata/v8/pubsub"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// fakeServer answers the version and subscribe requests of the clients, and
+// hangs up on the first connection after its first subscription.
+type fakeServer struct {
+	mtx   sync.Mutex
+	conns int
+	subs  []string // subscriptions of every connection
+}
+
+func (s *fakeServer) handle(ws *websocket.Conn) {
+	s.mtx.Lock()
+	s.conns++
+	first := s.conns == 1
+	s.mtx.Unlock()
+
+	for {
+		var msg pstypes.WebSocketMessage
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			return
+		}
+		var req pstypes.RequestMessage
+		if err := json.Unmarshal(msg.Message, &req); err != nil {
+			return
+		}
+		resp := pstypes.ResponseMessage{
+			RequestEventId: req.Message,
+			RequestId:      req.RequestId,
+			Success:        true,
+		}
+		switch msg.EventId {
+		case "version":
+			v := pubsub.Version()
+			b, _ := json.Marshal(pstypes.NewVer(v.Split()))
+			resp.Data = string(b)
+		case "subscribe":
+			s.mtx.Lock()
+			s.subs = append(s.subs, req.Message)
+			s.mtx.Unlock()
+		default:
+			continue
+		}
+		b, _ := json.Marshal(resp)
+		err := websocket.JSON.Send(ws, pstypes.WebSocketMessage{
+			EventId: msg.EventId + "Resp",
+			Message: b,
+		})
+		if err != nil {
+			return
+		}
+		if first && msg.EventId == "subscribe" {
+			ws.Close()
+			return
+		}
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	srv := &fakeServer{}
+	ts := httptest.NewServer(websocket.Handler(srv.handle))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cl, err := New("ws"+strings.TrimPrefix(ts.URL, "http"), ctx, &Opts{
+		ReadTimeout:       DefaultReadTimeout,
+		WriteTimeout:      DefaultWriteTimeout,
+		Reconnect:         true,
+		MinReconnectDelay: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer cl.Stop()
+
+	resp, err := cl.Subscribe("btcnewtxs")
+	if err != nil || !resp.Success {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	// The server hangs up, and the client reconnects and reports the gap.
+	var gap *Gap
+	for gap == nil {
+		select {
+		case msg, ok := <-cl.Receive():
+			if !ok {
+				t.Fatalf("Receive channel closed")
+			}
+			if msg.EventId == ReconnectEventID {
+				gap = msg.Message.(*Gap)
+			}
+		case <-ctx.Done():
+			t.Fatalf("no reconnect")
+		}
+	}
+	if gap.Attempts < 1 || gap.End.Before(gap.Start) ||
+		len(gap.Subscriptions) != 1 || gap.Subscriptions[0] != "btcnewtxs" {
+		t.Errorf("wrong gap %+v", gap)
+	}
+
+	// The subscription is replayed on the new connection.
+	for {
+		srv.mtx.Lock()
+		conns, subs := srv.conns, len(srv.subs)
+		srv.mtx.Unlock()
+		if conns == 2 && subs == 2 {
+			break
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatalf("subscription not replayed: %d connections, %d subscriptions", conns, subs)
+		}
+	}
+}
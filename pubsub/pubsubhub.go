@@ -23,6 +23,7 @@ import (
 	btcjson "github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
 	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
+	btctxscript "github.com/btcsuite/btcd/txscript"
 	btcwire "github.com/btcsuite/btcd/wire"
 	"github.com/decred/dcrdata/v8/blockdata"
 	"github.com/decred/dcrdata/v8/blockdata/blockdatabtc"
@@ -39,6 +40,7 @@ import (
 	ltcjson "github.com/ltcsuite/ltcd/btcjson"
 	ltcchaincfg "github.com/ltcsuite/ltcd/chaincfg"
 	"github.com/ltcsuite/ltcd/ltcutil"
+	ltctxscript "github.com/ltcsuite/ltcd/txscript"
 	ltcwire "github.com/ltcsuite/ltcd/wire"
 	"golang.org/x/net/websocket"
 )
@@ -121,6 +123,8 @@ type PubSubHub struct {
 	LtcCharts  *cache.MutilchainChartData
 	BtcCharts  *cache.MutilchainChartData
 	XmrCharts  *cache.MutilchainChartData
+	sseOnce    sync.Once
+	sse        *sseBroker
 }
 
 // NewPubSubHub constructs a PubSubHub given a data source. The WebSocketHub is
@@ -468,181 +472,194 @@ loop:
 		log.Tracef("signaling client %d with %s", clientData.id, sig)
 
 		// Respond to the websocket client.
+		msg, send := psh.encodeMessage(clientData, sig, buff)
+		if !send {
+			continue loop
+		}
 		pushMsg := pstypes.WebSocketMessage{
 			EventId: sig.Signal.String(),
-			// Message is set in switch statement below.
+			Message: msg,
 		}
 
-		// JSON encoder for the Message.
-		buff.Reset()
-		enc := json.NewEncoder(buff)
-
-		switch sig.Signal {
-		case sigAddressTx:
-			// sig was already validated, but do it again here in case the
-			// type changed without changing the type assertion here.
-			am, ok := sig.Msg.(*pstypes.AddressMessage)
-			if !ok {
-				log.Errorf("sigAddressTx did not store a *AddressMessage in Msg.")
-				continue loop
-			}
-			err := enc.Encode(am)
-			if err != nil {
-				log.Warnf("Encode(AddressMessage) failed: %v", err)
+		// Send the message.
+		err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err != nil && !pstypes.IsWSClosedErr(err) {
+			log.Warnf("SetWriteDeadline failed: %v", err)
+		}
+		if err = websocket.JSON.Send(ws, pushMsg); err != nil {
+			// Do not log the error if the connection is just closed.
+			if !pstypes.IsWSClosedErr(err) {
+				log.Debugf("Failed to encode WebSocketMessage (push) %v: %v", sig, err)
 			}
+			// If the send failed, the client is probably gone, quit the
+			// send loop, unregistering the client from the websocket hub.
+			log.Errorf("websocket.JSON.Send of %v type message failed: %v", sig, err)
+			return
+		}
+	} // for range { a.k.a. loop:
+}
+
+// encodeMessage encodes the message of a signal for a client, using buff. The
+// returned message may be nil to send an empty message, and send is false if
+// nothing should be sent to the client. The message is only valid until buff
+// is reused.
+func (psh *PubSubHub) encodeMessage(clientData *client, sig pstypes.HubMessage, buff *bytes.Buffer) (msg json.RawMessage, send bool) {
+	// JSON encoder for the Message.
+	buff.Reset()
+	enc := json.NewEncoder(buff)
+
+	switch sig.Signal {
+	case sigAddressTx, sigBTCAddressTx, sigLTCAddressTx:
+		// sig was already validated, but do it again here in case the
+		// type changed without changing the type assertion here.
+		am, ok := sig.Msg.(*pstypes.AddressMessage)
+		if !ok {
+			log.Errorf("%v did not store a *AddressMessage in Msg.", sig.Signal)
+			return nil, false
+		}
+		err := enc.Encode(am)
+		if err != nil {
+			log.Warnf("Encode(AddressMessage) failed: %v", err)
+		}
 
-			log.Debugf("Sending sigAddressTx to client %d: %s", clientData.id, am)
+		log.Debugf("Sending %v to client %d: %s", sig.Signal, clientData.id, am)
 
-			pushMsg.Message = buff.Bytes()
-		case sigNewBlock:
-			psh.State.mtx.RLock()
-			if psh.State.BlockInfo == nil {
-				psh.State.mtx.RUnlock()
-				break // from switch to send empty message
-			}
-			err := enc.Encode(exptypes.WebsocketBlock{
-				Block: psh.State.BlockInfo,
-				Extra: psh.State.GeneralInfo,
-			})
+		msg = buff.Bytes()
+	case sigNewBlock:
+		psh.State.mtx.RLock()
+		if psh.State.BlockInfo == nil {
 			psh.State.mtx.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(WebsocketBlock) failed: %v", err)
-			}
+			return nil, true
+		}
+		err := enc.Encode(exptypes.WebsocketBlock{
+			Block: psh.State.BlockInfo,
+			Extra: psh.State.GeneralInfo,
+		})
+		psh.State.mtx.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(WebsocketBlock) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
-		case sigSummaryInfo:
-			psh.State.mtx.RLock()
-			if psh.State.SummaryInfo == nil {
-				psh.State.mtx.RUnlock()
-				break // from switch to send empty message
-			}
-			err := enc.Encode(exptypes.WebsocketSummary{
-				SummaryInfo: psh.State.SummaryInfo,
-			})
+		msg = buff.Bytes()
+	case sigSummaryInfo:
+		psh.State.mtx.RLock()
+		if psh.State.SummaryInfo == nil {
 			psh.State.mtx.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(WebsocketSummary) failed: %v", err)
-			}
+			return nil, true
+		}
+		err := enc.Encode(exptypes.WebsocketSummary{
+			SummaryInfo: psh.State.SummaryInfo,
+		})
+		psh.State.mtx.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(WebsocketSummary) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
-		case sigSummary24h:
-			psh.State.mtx.RLock()
-			if psh.State.Block24hInfo == nil {
-				psh.State.mtx.RUnlock()
-				break // from switch to send empty message
-			}
-			err := enc.Encode(exptypes.WebsocketSummary{
-				Summary24h: psh.State.Block24hInfo,
-			})
+		msg = buff.Bytes()
+	case sigSummary24h:
+		psh.State.mtx.RLock()
+		if psh.State.Block24hInfo == nil {
 			psh.State.mtx.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(WebsocketSummary) failed: %v", err)
-			}
+			return nil, true
+		}
+		err := enc.Encode(exptypes.WebsocketSummary{
+			Summary24h: psh.State.Block24hInfo,
+		})
+		psh.State.mtx.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(WebsocketSummary) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
-		case sigNewLTCBlock:
-			psh.State.mtx.RLock()
-			if psh.State.LTCBlockInfo == nil {
-				psh.State.mtx.RUnlock()
-				break // from switch to send empty message
-			}
-			err := enc.Encode(exptypes.WebsocketBlock{
-				Block: psh.State.LTCBlockInfo,
-				Extra: psh.State.LTCGeneralInfo,
-			})
+		msg = buff.Bytes()
+	case sigNewLTCBlock:
+		psh.State.mtx.RLock()
+		if psh.State.LTCBlockInfo == nil {
 			psh.State.mtx.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(WebsocketLTCBlock) failed: %v", err)
-			}
+			return nil, true
+		}
+		err := enc.Encode(exptypes.WebsocketBlock{
+			Block: psh.State.LTCBlockInfo,
+			Extra: psh.State.LTCGeneralInfo,
+		})
+		psh.State.mtx.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(WebsocketLTCBlock) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
-		case sigNewBTCBlock:
-			psh.State.mtx.RLock()
-			if psh.State.BTCBlockInfo == nil {
-				psh.State.mtx.RUnlock()
-				break // from switch to send empty message
-			}
-			err := enc.Encode(exptypes.WebsocketBlock{
-				Block: psh.State.BTCBlockInfo,
-				Extra: psh.State.BTCGeneralInfo,
-			})
+		msg = buff.Bytes()
+	case sigNewBTCBlock:
+		psh.State.mtx.RLock()
+		if psh.State.BTCBlockInfo == nil {
 			psh.State.mtx.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(WebsocketBTCBlock) failed: %v", err)
-			}
+			return nil, true
+		}
+		err := enc.Encode(exptypes.WebsocketBlock{
+			Block: psh.State.BTCBlockInfo,
+			Extra: psh.State.BTCGeneralInfo,
+		})
+		psh.State.mtx.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(WebsocketBTCBlock) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
-		case sigMempoolUpdate:
-			// You probably want the sigNewTxs event. sigMempoolUpdate sends
-			// a summary of mempool contents, and the NumLatestMempoolTxns
-			// latest transactions.
-			inv := psh.MempoolInventory()
-			if inv == nil {
-				break // from switch to send empty message
-			}
-			inv.RLock()
-			err := enc.Encode(inv.MempoolShort)
-			inv.RUnlock()
-			if err != nil {
-				log.Warnf("Encode(MempoolShort) failed: %v", err)
-			}
+		msg = buff.Bytes()
+	case sigMempoolUpdate:
+		// You probably want the sigNewTxs event. sigMempoolUpdate sends
+		// a summary of mempool contents, and the NumLatestMempoolTxns
+		// latest transactions.
+		inv := psh.MempoolInventory()
+		if inv == nil {
+			return nil, true
+		}
+		inv.RLock()
+		err := enc.Encode(inv.MempoolShort)
+		inv.RUnlock()
+		if err != nil {
+			log.Warnf("Encode(MempoolShort) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
+		msg = buff.Bytes()
 
-		case sigPingAndUserCount:
-			// ping and send user count
-			pushMsg.Message = json.RawMessage(strconv.Itoa(psh.WsHub.NumClients())) // No quotes as this is a JSON integer
+	case sigPingAndUserCount:
+		// ping and send user count
+		msg = json.RawMessage(strconv.Itoa(psh.WsHub.NumClients())) // No quotes as this is a JSON integer
 
-		case sigNewTxs:
-			// Marshal this client's tx buffer if it is not empty.
-			clientData.newTxs.Lock()
-			if len(clientData.newTxs.t) == 0 {
-				clientData.newTxs.Unlock()
-				continue loop // break sigselect
-			}
-			err := enc.Encode(clientData.newTxs.t)
+	case sigNewTxs, sigBTCNewTxs, sigLTCNewTxs:
+		// Marshal this client's tx buffer if it is not empty.
+		newTxs := clientData.newTxs[sig.Signal]
+		newTxs.Lock()
+		if len(newTxs.t) == 0 {
+			newTxs.Unlock()
+			return nil, false
+		}
+		err := enc.Encode(newTxs.t)
 
-			// Reinit the tx buffer.
-			clientData.newTxs.t = make(pstypes.TxList, 0, NewTxBufferSize)
-			clientData.newTxs.Unlock()
-			if err != nil {
-				log.Warnf("Encode([]*exptypes.MempoolTx) failed: %v", err)
-			}
+		// Reinit the tx buffer.
+		newTxs.t = make(pstypes.TxList, 0, NewTxBufferSize)
+		newTxs.Unlock()
+		if err != nil {
+			log.Warnf("Encode([]*exptypes.MempoolTx) failed: %v", err)
+		}
 
-			pushMsg.Message = buff.Bytes()
+		msg = buff.Bytes()
 
-		case sigByeNow:
-			pushMsg.Message = []byte(`"The dcrdata server is shutting down. Bye!"`)
-			log.Tracef("Sending %v", string(pushMsg.Message))
+	case sigByeNow:
+		msg = []byte(`"The dcrdata server is shutting down. Bye!"`)
+		log.Tracef("Sending %v", string(msg))
 
-		// case sigSyncStatus:
-		// 	err := enc.Encode(explorer.SyncStatus())
-		// 	if err != nil {
-		// 		log.Warnf("Encode(SyncStatus()) failed: %v", err)
-		// 	}
-		// 	pushMsg.Message = buff.String()
+	// case sigSyncStatus:
+	// 	err := enc.Encode(explorer.SyncStatus())
+	// 	if err != nil {
+	// 		log.Warnf("Encode(SyncStatus()) failed: %v", err)
+	// 	}
+	// 	pushMsg.Message = buff.String()
 
-		default:
-			log.Errorf("Not sending a %v to the client.", sig)
-			continue loop // break sigselect
-		} // switch sig
+	default:
+		log.Errorf("Not sending a %v to the client.", sig)
+		return nil, false
+	} // switch sig
 
-		// Send the message.
-		err := ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
-		if err != nil && !pstypes.IsWSClosedErr(err) {
-			log.Warnf("SetWriteDeadline failed: %v", err)
-		}
-		if err = websocket.JSON.Send(ws, pushMsg); err != nil {
-			// Do not log the error if the connection is just closed.
-			if !pstypes.IsWSClosedErr(err) {
-				log.Debugf("Failed to encode WebSocketMessage (push) %v: %v", sig, err)
-			}
-			// If the send failed, the client is probably gone, quit the
-			// send loop, unregistering the client from the websocket hub.
-			log.Errorf("websocket.JSON.Send of %v type message failed: %v", sig, err)
-			return
-		}
-	} // for range { a.k.a. loop:
+	return msg, true
 }
 
 // WebSocketHandler is the http.HandlerFunc for new websocket connections. The
@@ -940,6 +957,41 @@ func (psh *PubSubHub) BTCStore(blockData *blockdatabtc.BlockData, msgBlock *btcw
 	}()
 
 	log.Debugf("Got new BTC block %d for the pubsubhub.", newBlockData.Height)
+
+	// Since the coinbase transaction is generated by the miner, it will never
+	// hit mempool. It must be processed now, with the new block.
+	coinbaseTx := msgBlock.Transactions[0]
+	coinbaseHash := coinbaseTx.TxHash().String()
+	for _, out := range coinbaseTx.TxOut {
+		_, scriptAddrs, _, err := btctxscript.ExtractPkScriptAddrs(out.PkScript, psh.btcParams)
+		if err != nil {
+			continue
+		}
+		for _, scriptAddr := range scriptAddrs {
+			psh.hubSend(pstypes.HubMessage{
+				Signal: sigBTCAddressTx,
+				Msg: &pstypes.AddressMessage{
+					Address: scriptAddr.EncodeAddress(),
+					TxHash:  coinbaseHash,
+				},
+			})
+		}
+	}
+	psh.hubSend(pstypes.HubMessage{
+		Signal: sigBTCNewTx,
+		Msg: &exptypes.MempoolTx{
+			TxID:      coinbaseHash,
+			Version:   coinbaseTx.Version,
+			VinCount:  len(coinbaseTx.TxIn),
+			VoutCount: len(coinbaseTx.TxOut),
+			Vin:       exptypes.BTCMsgTxMempoolInputs(coinbaseTx),
+			Coinbase:  true,
+			Hash:      coinbaseHash,
+			Time:      msgBlock.Header.Timestamp.Unix(),
+			Size:      int32(coinbaseTx.SerializeSize()),
+			TotalOut:  txhelpers.BTCTotalOutFromMsgTx(coinbaseTx).ToBTC(),
+		},
+	})
 	return nil
 }
 
@@ -1027,9 +1079,56 @@ func (psh *PubSubHub) LTCStore(blockData *blockdataltc.BlockData, msgBlock *ltcw
 	}()
 
 	log.Debugf("Got new LTC block %d for the pubsubhub.", newBlockData.Height)
+
+	// Since the coinbase transaction is generated by the miner, it will never
+	// hit mempool. It must be processed now, with the new block.
+	coinbaseTx := msgBlock.Transactions[0]
+	coinbaseHash := coinbaseTx.TxHash().String()
+	for _, out := range coinbaseTx.TxOut {
+		_, scriptAddrs, _, err := ltctxscript.ExtractPkScriptAddrs(out.PkScript, psh.ltcParams)
+		if err != nil {
+			continue
+		}
+		for _, scriptAddr := range scriptAddrs {
+			psh.hubSend(pstypes.HubMessage{
+				Signal: sigLTCAddressTx,
+				Msg: &pstypes.AddressMessage{
+					Address: scriptAddr.EncodeAddress(),
+					TxHash:  coinbaseHash,
+				},
+			})
+		}
+	}
+	psh.hubSend(pstypes.HubMessage{
+		Signal: sigLTCNewTx,
+		Msg: &exptypes.MempoolTx{
+			TxID:      coinbaseHash,
+			Version:   coinbaseTx.Version,
+			VinCount:  len(coinbaseTx.TxIn),
+			VoutCount: len(coinbaseTx.TxOut),
+			Vin:       exptypes.LTCMsgTxMempoolInputs(coinbaseTx),
+			Coinbase:  true,
+			Hash:      coinbaseHash,
+			Time:      msgBlock.Header.Timestamp.Unix(),
+			Size:      int32(coinbaseTx.SerializeSize()),
+			TotalOut:  txhelpers.LTCTotalOutFromMsgTx(coinbaseTx).ToBTC(),
+		},
+	})
 	return nil
 }
 
+// hubSend signals the WebSocketHub, but does not block the caller, and does
+// not hang forever in a goroutine waiting to send.
+func (psh *PubSubHub) hubSend(msg pstypes.HubMessage) {
+	go func() {
+		select {
+		case psh.WsHub.HubRelay <- msg:
+		case <-time.After(time.Second * 10):
+			log.Errorf("%v send failed: Timeout waiting for WebsocketHub.", msg.Signal)
+		}
+	}()
+}
+
 func (psh *PubSubHub) GetMultichainBlockchainSize(chainType string) int64 {
 	mutilchainChartData := psh.GetMutilchainChartData(chainType)
 	if mutilchainChartData == nil {
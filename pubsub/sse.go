@@ -0,0 +1,311 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+const (
+	// SSEHeartbeatInterval is how often a comment is sent on idle Server-Sent
+	// Events streams, which keeps them open through proxies.
+	SSEHeartbeatInterval = 15 * time.Second
+
+	// sseRingSize is the number of recent events kept for streams resumed
+	// with a Last-Event-ID.
+	sseRingSize = 512
+	// sseRetry is the reconnection delay advised to the clients.
+	sseRetry = 3 * time.Second
+	// sseWriteTimeout is the write deadline of each write to a stream, which
+	// replaces the write timeout of the server.
+	sseWriteTimeout = 10 * time.Second
+)
+
+// sseSignals are the signals that may be streamed.
+var sseSignals = map[pstypes.HubSignal]struct{}{
+	sigNewBlock:      {},
+	sigNewBTCBlock:   {},
+	sigNewLTCBlock:   {},
+	sigMempoolUpdate: {},
+	sigNewTxs:        {},
+	sigBTCNewTxs:     {},
+	sigLTCNewTxs:     {},
+	sigAddressTx:     {},
+	sigBTCAddressTx:  {},
+	sigLTCAddressTx:  {},
+	sigSummaryInfo:   {},
+	sigSummary24h:    {},
+}
+
+// sseEvent is an encoded event of the SSE ring buffer.
+type sseEvent struct {
+	id      uint64
+	signal  pstypes.HubSignal
+	address string // address signals only
+	data    []byte
+}
+
+// sseBroker receives every event from the WebsocketHub as a single client,
+// encodes each event once, and keeps the recent events in a ring buffer that
+// is read by the Server-Sent Events streams. Event IDs are prefixed with the
+// start time of the broker so that the IDs of a previous run are not resumed.
+type sseBroker struct {
+	mtx    sync.RWMutex
+	epoch  string
+	ring   [sseRingSize]*sseEvent
+	next   uint64        // ID of the next event, from 1
+	notify chan struct{} // closed and replaced on each new event
+	closed bool
+}
+
+func newSSEBroker() *sseBroker {
+	return &sseBroker{
+		epoch:  strconv.FormatInt(time.Now().Unix(), 10),
+		next:   1,
+		notify: make(chan struct{}),
+	}
+}
+
+// add assigns the next ID to an event, and adds it to the ring buffer.
+func (b *sseBroker) add(ev *sseEvent) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	ev.id = b.next
+	b.next++
+	b.ring[ev.id%sseRingSize] = ev
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// close ends the streams once they have sent the buffered events.
+func (b *sseBroker) close() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.closed = true
+	close(b.notify)
+	b.notify = make(chan struct{})
+}
+
+// since returns the buffered events after the event with ID lastID, the
+// channel that is closed on the next event, and whether the broker is closed.
+// Events that were overwritten in the ring buffer are skipped.
+func (b *sseBroker) since(lastID uint64) ([]*sseEvent, <-chan struct{}, bool) {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+	first := lastID + 1
+	if b.next > sseRingSize && first < b.next-sseRingSize {
+		first = b.next - sseRingSize
+	}
+	var events []*sseEvent
+	for id := first; id < b.next; id++ {
+		events = append(events, b.ring[id%sseRingSize])
+	}
+	return events, b.notify, b.closed
+}
+
+// eventID formats the ID of an event.
+func (b *sseBroker) eventID(id uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// resumeID returns the ID of the last event sent on a stream, from its
+// Last-Event-ID header. New streams, and streams with the IDs of a previous
+// run, start after the latest event.
+func (b *sseBroker) resumeID(lastEventID string) uint64 {
+	b.mtx.RLock()
+	latest := b.next - 1
+	b.mtx.RUnlock()
+	epoch, idStr, found := strings.Cut(lastEventID, "-")
+	if !found || epoch != b.epoch {
+		return latest
+	}
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil || id > latest {
+		return latest
+	}
+	return id
+}
+
+// sseFilter is the set of events of a stream.
+type sseFilter struct {
+	signals map[pstypes.HubSignal]struct{}
+	addrs   map[pstypes.HubSignal]map[string]struct{}
+}
+
+// newSSEFilter parses the comma-separated events of a stream, which are given
+// as the subscriptions of the websocket clients (e.g. "newblock" and
+// "address:Dsxyz..."). The ping subscription is accepted, but the heartbeats
+// of the stream replace it.
+func newSSEFilter(events string) (*sseFilter, error) {
+	if events == "" {
+		return nil, errors.New("no events")
+	}
+	f := &sseFilter{
+		signals: make(map[pstypes.HubSignal]struct{}),
+		addrs:   make(map[pstypes.HubSignal]map[string]struct{}),
+	}
+	for _, event := range strings.Split(events, ",") {
+		event = strings.TrimSpace(event)
+		sig, msg, valid := pstypes.ValidateSubscription(event)
+		if !valid {
+			return nil, fmt.Errorf("invalid event %q", event)
+		}
+		if sig == sigPingAndUserCount {
+			continue
+		}
+		if _, ok := sseSignals[sig]; !ok {
+			return nil, fmt.Errorf("event %q cannot be streamed", event)
+		}
+		f.signals[sig] = struct{}{}
+		if sig.IsAddressSignal() {
+			addrs := f.addrs[sig]
+			if addrs == nil {
+				addrs = make(map[string]struct{})
+				f.addrs[sig] = addrs
+			}
+			addrs[msg.(*pstypes.AddressMessage).Address] = struct{}{}
+		}
+	}
+	return f, nil
+}
+
+func (f *sseFilter) match(ev *sseEvent) bool {
+	if _, ok := f.signals[ev.signal]; !ok {
+		return false
+	}
+	if ev.signal.IsAddressSignal() {
+		_, ok := f.addrs[ev.signal][ev.address]
+		return ok
+	}
+	return true
+}
+
+// sseBroker returns the SSE broker, which is started with the first stream.
+func (psh *PubSubHub) sseBroker() *sseBroker {
+	psh.sseOnce.Do(func() {
+		psh.sse = newSSEBroker()
+		ch := psh.WsHub.NewClientHubSpoke()
+		// Subscribe the broker to every address of every streamed signal.
+		cl := ch.cl
+		cl.mtx.Lock()
+		cl.allAddrs = true
+		for sig := range sseSignals {
+			cl.subs[sig] = struct{}{}
+		}
+		cl.mtx.Unlock()
+		go psh.runSSEBroker(ch)
+	})
+	return psh.sse
+}
+
+// runSSEBroker encodes the events of the WebsocketHub for the SSE broker until
+// the broker is unregistered.
+func (psh *PubSubHub) runSSEBroker(ch *clientHubSpoke) {
+	defer close(ch.cl.killed)
+	defer psh.sse.close()
+	buff := new(bytes.Buffer)
+	for sig := range *ch.c {
+		if _, ok := sseSignals[sig.Signal]; !ok || !sig.IsValid() {
+			continue
+		}
+		msg, send := psh.encodeMessage(ch.cl, sig, buff)
+		if !send {
+			continue
+		}
+		ev := &sseEvent{
+			signal: sig.Signal,
+			data:   bytes.TrimSpace(msg),
+		}
+		if len(ev.data) == 0 {
+			ev.data = []byte("null")
+		} else {
+			ev.data = append([]byte(nil), ev.data...)
+		}
+		if am, ok := sig.Msg.(*pstypes.AddressMessage); ok {
+			ev.address = am.Address
+		}
+		psh.sse.add(ev)
+	}
+}
+
+// SSEHandler is the http.HandlerFunc of the Server-Sent Events streams. The
+// comma-separated "events" URL query parameter lists the events of the stream
+// in the format of the websocket subscriptions, e.g.
+// "newblock,mempool,address:Dsxyz...". The data of each event is the message
+// of the websocket event. A reconnecting client that sends the Last-Event-ID
+// header first receives the events that it missed, if they are still in the
+// ring buffer of recent events.
+func (psh *PubSubHub) SSEHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := newSSEFilter(r.URL.Query().Get("events"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	b := psh.sseBroker()
+	lastID := b.resumeID(r.Header.Get("Last-Event-ID"))
+
+	// The stream outlives the write timeout of the server, so each write has
+	// its own deadline.
+	rc := http.NewResponseController(w)
+	write := func(format string, args ...any) error {
+		if err := rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil &&
+			!errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // no proxy buffering
+	if err = write("retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		log.Debugf("Unable to start SSE stream: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(SSEHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		events, notify, closed := b.since(lastID)
+		for _, ev := range events {
+			lastID = ev.id
+			if !filter.match(ev) {
+				continue
+			}
+			err = write("id: %s\nevent: %s\ndata: %s\n\n", b.eventID(ev.id), ev.signal, ev.data)
+			if err != nil {
+				log.Tracef("SSE stream closed: %v", err)
+				return
+			}
+		}
+		if closed {
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-heartbeat.C:
+			if err = write(": heartbeat\n\n"); err != nil {
+				log.Tracef("SSE stream closed: %v", err)
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
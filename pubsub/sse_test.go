@@ -0,0 +1,82 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_sseBroker_since(t *testing.T) {
+	b := newSSEBroker()
+	for i := 0; i < 3; i++ {
+		b.add(&sseEvent{signal: sigNewBlock})
+	}
+
+	// New streams start after the latest event.
+	lastID := b.resumeID("")
+	if lastID != 3 {
+		t.Fatalf("resumeID() = %d, want 3", lastID)
+	}
+	if events, _, _ := b.since(lastID); len(events) != 0 {
+		t.Errorf("since(%d) returned %d events, want 0", lastID, len(events))
+	}
+
+	// Resumed streams receive the missed events.
+	lastID = b.resumeID(b.eventID(1))
+	events, _, closed := b.since(lastID)
+	if len(events) != 2 || events[0].id != 2 || events[1].id != 3 || closed {
+		t.Errorf("since(%d) returned %d events, closed = %v", lastID, len(events), closed)
+	}
+
+	// IDs of a previous run are not resumed.
+	if id := b.resumeID("1-1"); id != 3 {
+		t.Errorf("resumeID() of another epoch = %d, want 3", id)
+	}
+
+	// Overwritten events are skipped.
+	for i := 0; i < sseRingSize; i++ {
+		b.add(&sseEvent{signal: sigNewBlock})
+	}
+	events, _, _ = b.since(b.resumeID(b.eventID(1)))
+	if len(events) != sseRingSize || events[0].id != 4 {
+		t.Errorf("since() returned %d events from %d, want %d events from 4",
+			len(events), events[0].id, sseRingSize)
+	}
+
+	_, notify, _ := b.since(0)
+	b.close()
+	<-notify
+	if _, _, closed = b.since(0); !closed {
+		t.Errorf("broker not closed")
+	}
+}
+
+func Test_sseFilter(t *testing.T) {
+	const addr = "DsfX4WrSecUwGoRd9B7Lz1JjYssYaVKnjGC"
+	f, err := newSSEFilter(fmt.Sprintf("newblock, ping, address:%s", addr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ev   *sseEvent
+		want bool
+	}{
+		{&sseEvent{signal: sigNewBlock}, true},
+		{&sseEvent{signal: sigNewBTCBlock}, false},
+		{&sseEvent{signal: sigAddressTx, address: addr}, true},
+		{&sseEvent{signal: sigAddressTx, address: "DsgRwmcnwLrNaY3gsrn2MXGMmaKAymnnFUR"}, false},
+	}
+	for _, tt := range tests {
+		if got := f.match(tt.ev); got != tt.want {
+			t.Errorf("match(%s %s) = %v, want %v", tt.ev.signal, tt.ev.address, got, tt.want)
+		}
+	}
+
+	for _, events := range []string{"", "nonsense", "newblock,address:nonsense", "decodetx"} {
+		if _, err = newSSEFilter(events); err == nil {
+			t.Errorf("newSSEFilter(%q) did not fail", events)
+		}
+	}
+}
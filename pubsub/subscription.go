@@ -0,0 +1,117 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// subscriptionBufferSize is the number of messages buffered for the receiver
+// of a HubSubscription. Messages are dropped when the buffer is full.
+const subscriptionBufferSize = 16
+
+// subscriptionSignals are the signals that internal clients may subscribe to.
+// The new transactions signals are excluded since their transactions are
+// buffered per websocket client.
+var subscriptionSignals = map[pstypes.HubSignal]struct{}{
+	sigNewBlock:      {},
+	sigNewBTCBlock:   {},
+	sigNewLTCBlock:   {},
+	sigMempoolUpdate: {},
+	sigAddressTx:     {},
+	sigBTCAddressTx:  {},
+	sigLTCAddressTx:  {},
+	sigSummaryInfo:   {},
+	sigSummary24h:    {},
+}
+
+// HubSubscription is the subscription of an internal client of the
+// WebsocketHub, such as a GraphQL subscription, to hub signals. The messages
+// of the subscribed signals are received on C until Close is called or the hub
+// is stopped, when C is closed.
+type HubSubscription struct {
+	C <-chan pstypes.HubMessage
+
+	wsh       *WebsocketHub
+	ch        *clientHubSpoke
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Subscribe registers an internal client of the hub that is subscribed to the
+// signals of the messages. The messages of the address signals (e.g.
+// SigAddressTx) have the watched address in an AddressMessage, as for the
+// subscriptions of the websocket clients.
+func (wsh *WebsocketHub) Subscribe(subs ...pstypes.HubMessage) (*HubSubscription, error) {
+	if len(subs) == 0 {
+		return nil, errors.New("no signals")
+	}
+	cl := newClient()
+	for _, sub := range subs {
+		if _, ok := subscriptionSignals[sub.Signal]; !ok {
+			return nil, fmt.Errorf("cannot subscribe to %v", sub.Signal)
+		}
+		if _, err := cl.subscribe(sub); err != nil {
+			return nil, err
+		}
+	}
+
+	c := make(hubSpoke, 16)
+	ch := &clientHubSpoke{cl: cl, c: &c}
+	select {
+	case wsh.Register <- ch:
+	case <-wsh.killed:
+		return nil, errors.New("the hub is stopped")
+	}
+
+	msgs := make(chan pstypes.HubMessage, subscriptionBufferSize)
+	s := &HubSubscription{
+		C:    msgs,
+		wsh:  wsh,
+		ch:   ch,
+		done: make(chan struct{}),
+	}
+	go s.relay(msgs)
+	return s, nil
+}
+
+// relay sends the subscribed messages of the hub to the receiver until the
+// hub closes the spoke of the client.
+func (s *HubSubscription) relay(msgs chan<- pstypes.HubMessage) {
+	defer close(s.ch.cl.killed)
+	defer close(msgs)
+	for msg := range *s.ch.c {
+		// Pings and hang-ups are sent to all the clients.
+		if !s.ch.cl.isSubscribed(msg) {
+			continue
+		}
+		select {
+		case <-s.done:
+		case msgs <- msg:
+		default:
+			log.Debugf("Dropped %v message of subscription %d.", msg.Signal, s.ch.cl.id)
+		}
+	}
+}
+
+// Close unregisters the client of the subscription from the hub.
+func (s *HubSubscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		select {
+		case s.wsh.Unregister <- s.ch.c:
+		case <-s.wsh.killed:
+		}
+	})
+}
+
+// Subscribe registers an internal client of the WebsocketHub that is
+// subscribed to the signals of the messages. See WebsocketHub.Subscribe.
+func (psh *PubSubHub) Subscribe(subs ...pstypes.HubMessage) (*HubSubscription, error) {
+	return psh.WsHub.Subscribe(subs...)
+}
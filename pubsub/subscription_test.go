@@ -0,0 +1,60 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+func TestWebsocketHub_Subscribe(t *testing.T) {
+	const addr = "DsfX4WrSecUwGoRd9B7Lz1JjYssYaVKnjGC"
+	wsh := NewWebsocketHub()
+	go wsh.Run()
+	defer wsh.Stop()
+
+	if _, err := wsh.Subscribe(pstypes.HubMessage{Signal: sigNewTxs}); err == nil {
+		t.Errorf("subscribed to %v", sigNewTxs)
+	}
+
+	sub, err := wsh.Subscribe(pstypes.HubMessage{Signal: sigNewBlock},
+		pstypes.HubMessage{Signal: sigAddressTx, Msg: &pstypes.AddressMessage{Address: addr}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := []pstypes.HubMessage{
+		{Signal: sigNewBTCBlock},
+		{Signal: sigAddressTx, Msg: &pstypes.AddressMessage{Address: "DsgRwmcnwLrNaY3gsrn2MXGMmaKAymnnFUR", TxHash: "a"}},
+		{Signal: sigAddressTx, Msg: &pstypes.AddressMessage{Address: addr, TxHash: "b"}},
+		{Signal: sigNewBlock},
+	}
+	for _, msg := range msgs {
+		wsh.HubRelay <- msg
+	}
+
+	receive := func() (pstypes.HubMessage, bool) {
+		select {
+		case msg, ok := <-sub.C:
+			return msg, ok
+		case <-time.After(5 * time.Second):
+			t.Fatal("no message")
+		}
+		return pstypes.HubMessage{}, false
+	}
+	if msg, _ := receive(); msg.Signal != sigAddressTx || msg.Msg.(*pstypes.AddressMessage).TxHash != "b" {
+		t.Errorf("received %v, want the address message of %s", msg, addr)
+	}
+	if msg, _ := receive(); msg.Signal != sigNewBlock {
+		t.Errorf("received %v, want %v", msg, sigNewBlock)
+	}
+
+	sub.Close()
+	sub.Close()
+	if _, ok := receive(); ok {
+		t.Errorf("subscription not closed")
+	}
+}
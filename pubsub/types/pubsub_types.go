@@ -7,8 +7,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/btcsuite/btcd/btcutil"
+	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
 	"github.com/decred/base58"
 	exptypes "github.com/decred/dcrdata/v8/explorer/types"
+	ltcchaincfg "github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
 )
 
 // Ver is a json tagged version type.
@@ -113,6 +117,12 @@ const (
 	SigSummary24h
 	SigNewXMRBlock
 	SigXmrMempoolStatus
+	SigBTCAddressTx
+	SigLTCAddressTx
+	SigBTCNewTx
+	SigLTCNewTx
+	SigBTCNewTxs
+	SigLTCNewTxs
 )
 
 var Subscriptions = map[string]HubSignal{
@@ -128,6 +138,10 @@ var Subscriptions = map[string]HubSignal{
 	"summary24h":       SigSummary24h,
 	"xmrMempoolStatus": SigXmrMempoolStatus,
 	"newxmrblock":      SigNewXMRBlock,
+	"btcaddress":       SigBTCAddressTx,
+	"ltcaddress":       SigLTCAddressTx,
+	"btcnewtxs":        SigBTCNewTxs,
+	"ltcnewtxs":        SigLTCNewTxs,
 }
 
 // Event type field for an event.
@@ -153,6 +167,67 @@ var eventIDs = map[HubSignal]string{
 	SigSummary24h:       "summary24h",
 	SigNewXMRBlock:      "newxmrblock",
 	SigXmrMempoolStatus: "xmrMempoolStatus",
+	SigBTCAddressTx:     "btcaddress",
+	SigLTCAddressTx:     "ltcaddress",
+	SigBTCNewTx:         "btcnewtx",
+	SigLTCNewTx:         "ltcnewtx",
+	SigBTCNewTxs:        "btcnewtxs",
+	SigLTCNewTxs:        "ltcnewtxs",
+}
+
+// NewTxsSignals maps the new transaction signals of each chain, which are
+// sent by the mempool monitors, to the new transactions signals of their
+// subscriptions, which batch the transactions.
+var NewTxsSignals = map[HubSignal]HubSignal{
+	SigNewTx:    SigNewTxs,
+	SigBTCNewTx: SigBTCNewTxs,
+	SigLTCNewTx: SigLTCNewTxs,
+}
+
+// IsAddressSignal indicates if the signal is the address transaction signal
+// of a chain, with an *AddressMessage.
+func (s HubSignal) IsAddressSignal() bool {
+	return s == SigAddressTx || s == SigBTCAddressTx || s == SigLTCAddressTx
+}
+
+// IsNewTxSignal indicates if the signal is the new transaction signal of a
+// chain, with an *exptypes.MempoolTx.
+func (s HubSignal) IsNewTxSignal() bool {
+	_, ok := NewTxsSignals[s]
+	return ok
+}
+
+// IsNewTxsSignal indicates if the signal is the new transactions signal of a
+// chain, with a []*exptypes.MempoolTx.
+func (s HubSignal) IsNewTxsSignal() bool {
+	return s == SigNewTxs || s == SigBTCNewTxs || s == SigLTCNewTxs
+}
+
+// The BTC and LTC address subscriptions are accepted for the addresses of any
+// network, since the network of the server is not known here.
+var (
+	btcNetParams = []*btcchaincfg.Params{&btcchaincfg.MainNetParams, &btcchaincfg.TestNet3Params,
+		&btcchaincfg.RegressionNetParams, &btcchaincfg.SimNetParams}
+	ltcNetParams = []*ltcchaincfg.Params{&ltcchaincfg.MainNetParams, &ltcchaincfg.TestNet4Params,
+		&ltcchaincfg.RegressionNetParams, &ltcchaincfg.SimNetParams}
+)
+
+func validBTCAddress(addr string) bool {
+	for _, params := range btcNetParams {
+		if _, err := btcutil.DecodeAddress(addr, params); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func validLTCAddress(addr string) bool {
+	for _, params := range ltcNetParams {
+		if _, err := ltcutil.DecodeAddress(addr, params); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 func ValidateSubscription(event string) (sub HubSignal, msg interface{}, valid bool) {
@@ -179,6 +254,20 @@ func ValidateSubscription(event string) (sub HubSignal, msg interface{}, valid b
 		msg = &AddressMessage{
 			Address: msgStr,
 		}
+	case SigBTCAddressTx:
+		if !validBTCAddress(msgStr) {
+			return SigUnknown, nil, false
+		}
+		msg = &AddressMessage{
+			Address: msgStr,
+		}
+	case SigLTCAddressTx:
+		if !validLTCAddress(msgStr) {
+			return SigUnknown, nil, false
+		}
+		msg = &AddressMessage{
+			Address: msgStr,
+		}
 	default:
 		// Other signals do not have a message.
 		if msgStr != "" {
@@ -214,12 +303,12 @@ func (m HubMessage) IsValid() bool {
 	}
 
 	ok := true
-	switch m.Signal {
-	case SigAddressTx:
+	switch {
+	case m.Signal.IsAddressSignal():
 		_, ok = m.Msg.(*AddressMessage)
-	case SigNewTx:
+	case m.Signal.IsNewTxSignal():
 		_, ok = m.Msg.(*exptypes.MempoolTx)
-	case SigNewTxs:
+	case m.Signal.IsNewTxsSignal():
 		_, ok = m.Msg.([]*exptypes.MempoolTx)
 	}
 
@@ -233,14 +322,14 @@ func (m HubMessage) String() string {
 
 	sigStr := m.Signal.String()
 
-	switch m.Signal {
-	case SigAddressTx:
+	switch {
+	case m.Signal.IsAddressSignal():
 		am := m.Msg.(*AddressMessage)
 		sigStr += ":" + am.String()
-	case SigNewTx:
+	case m.Signal.IsNewTxSignal():
 		tx := m.Msg.(*exptypes.MempoolTx)
 		sigStr += ":" + tx.Hash
-	case SigNewTxs:
+	case m.Signal.IsNewTxsSignal():
 		txs := m.Msg.([]*exptypes.MempoolTx)
 		sigStr += ":len=" + strconv.Itoa(len(txs))
 	}
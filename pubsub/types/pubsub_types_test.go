@@ -14,6 +14,8 @@ func TestHubSignal_String(t *testing.T) {
 	}{
 		{"ok", SigNewTx, "newtx"},
 		{"ok", SigNewTxs, "newtxs"},
+		{"ok", SigBTCAddressTx, "btcaddress"},
+		{"ok", SigLTCNewTxs, "ltcnewtxs"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -46,6 +48,19 @@ func TestHubMessage_String(t *testing.T) {
 			},
 			"address:DsgRwmcnwLrNaY3gsrn2MXGMmaKAymnnFUR:992cf0fa8fcb88f0cfa9a9808a02907c0a66a39ba588f1434c3bd779feb530e0",
 		},
+		{
+			"ok btcaddress",
+			HubMessage{
+				Signal: SigBTCAddressTx,
+				Msg:    &AddressMessage{Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+			},
+			"btcaddress:bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq:",
+		},
+		{
+			"ok ltcnewtxs",
+			HubMessage{Signal: SigLTCNewTxs, Msg: []*exptypes.MempoolTx{}},
+			"ltcnewtxs:len=0",
+		},
 		{
 			"ok newtx",
 			HubMessage{Signal: SigNewTx, Msg: &exptypes.MempoolTx{Hash: "4811246cb13f6e74c8c661242064664aba79e0baaae273c320b884cf461b28d7"}},
@@ -76,3 +91,31 @@ func TestHubMessage_String(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSubscription(t *testing.T) {
+	tests := []struct {
+		event string
+		sig   HubSignal
+		valid bool
+	}{
+		{"newblock", SigNewBlock, true},
+		{"btcnewtxs", SigBTCNewTxs, true},
+		{"address:DsgRwmcnwLrNaY3gsrn2MXGMmaKAymnnFUR", SigAddressTx, true},
+		{"btcaddress:bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", SigBTCAddressTx, true},
+		{"ltcaddress:LPcmddE5zgnUBdXUEjrcUEuNFUNzvYd6AN", SigLTCAddressTx, true},
+		{"btcaddress:DsgRwmcnwLrNaY3gsrn2MXGMmaKAymnnFUR", SigBTCAddressTx, false},
+		{"ltcaddress:bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", SigLTCAddressTx, false},
+		{"nonsense", SigUnknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			sig, _, valid := ValidateSubscription(tt.event)
+			if valid != tt.valid {
+				t.Fatalf("ValidateSubscription(%q) valid = %v, want %v", tt.event, valid, tt.valid)
+			}
+			if valid && sig != tt.sig {
+				t.Errorf("ValidateSubscription(%q) signal = %v, want %v", tt.event, sig, tt.sig)
+			}
+		})
+	}
+}
@@ -46,12 +46,29 @@ var (
 	sigNewTx            = pstypes.SigNewTx
 	sigNewTxs           = pstypes.SigNewTxs
 	sigAddressTx        = pstypes.SigAddressTx
+	sigBTCAddressTx     = pstypes.SigBTCAddressTx
+	sigLTCAddressTx     = pstypes.SigLTCAddressTx
+	sigBTCNewTx         = pstypes.SigBTCNewTx
+	sigLTCNewTx         = pstypes.SigLTCNewTx
+	sigBTCNewTxs        = pstypes.SigBTCNewTxs
+	sigLTCNewTxs        = pstypes.SigLTCNewTxs
 	sigSyncStatus       = pstypes.SigSyncStatus
 	sigByeNow           = pstypes.SigByeNow
 	sigSummaryInfo      = pstypes.SigSummaryInfo
 	sigSummary24h       = pstypes.SigSummary24h
 )
 
+// newTxsSignals are the new transactions signals of each chain, in the order
+// the transaction buffers are sent.
+var newTxsSignals = []pstypes.HubSignal{sigNewTxs, sigBTCNewTxs, sigLTCNewTxs}
+
+// addressSignalNames names the address signals in subscription errors.
+var addressSignalNames = map[pstypes.HubSignal]string{
+	sigAddressTx:    "SigAddressTx",
+	sigBTCAddressTx: "SigBTCAddressTx",
+	sigLTCAddressTx: "SigLTCAddressTx",
+}
+
 type txList struct {
 	sync.Mutex
 	t pstypes.TxList
@@ -88,6 +105,7 @@ type WebsocketHub struct {
 	killed             chan struct{}
 	requestLimit       int
 	ready              atomic.Value
+	subCountsReq       chan chan map[pstypes.HubSignal]int
 }
 
 func (wsh *WebsocketHub) TimeToSendTxBuffer() bool {
@@ -111,21 +129,32 @@ func (wsh *WebsocketHub) SetReady(ready bool) {
 }
 
 type client struct {
-	mtx    sync.RWMutex
-	id     uint64
-	subs   map[pstypes.HubSignal]struct{}
-	addrs  map[string]struct{}
-	killed chan struct{}
-	newTxs *txList
+	mtx  sync.RWMutex
+	id   uint64
+	subs map[pstypes.HubSignal]struct{}
+	// addrs are the watched addresses of each address signal (e.g.
+	// SigAddressTx and SigBTCAddressTx).
+	addrs map[pstypes.HubSignal]map[string]struct{}
+	// allAddrs subscribes the client to every address of its address
+	// signals, and is only set for internal clients such as the SSE broker.
+	allAddrs bool
+	killed   chan struct{}
+	// newTxs are the transaction buffers of each new transactions signal. The
+	// map is not modified after newClient.
+	newTxs map[pstypes.HubSignal]*txList
 }
 
 func newClient() *client {
+	newTxs := make(map[pstypes.HubSignal]*txList, len(newTxsSignals))
+	for _, sig := range newTxsSignals {
+		newTxs[sig] = newTxList(NewTxBufferSize)
+	}
 	return &client{
 		id:     newClientID(),
 		subs:   make(map[pstypes.HubSignal]struct{}, 16),
-		addrs:  make(map[string]struct{}, 16),
+		addrs:  make(map[pstypes.HubSignal]map[string]struct{}, 3),
 		killed: make(chan struct{}),
-		newTxs: newTxList(NewTxBufferSize),
+		newTxs: newTxs,
 	}
 }
 
@@ -139,13 +168,16 @@ func (c *client) isSubscribed(msg pstypes.HubMessage) bool {
 	}
 
 	switch msg.Signal {
-	case pstypes.SigAddressTx:
+	case sigAddressTx, sigBTCAddressTx, sigLTCAddressTx:
 		am, ok := msg.Msg.(*pstypes.AddressMessage)
 		if !ok {
-			log.Errorf("n AddressMessage (SigAddressTx): %T", msg.Msg)
+			log.Errorf("n AddressMessage (%s): %T", addressSignalNames[msg.Signal], msg.Msg)
 			return false
 		}
-		_, subd = c.addrs[am.Address]
+		if c.allAddrs {
+			return true
+		}
+		_, subd = c.addrs[msg.Signal][am.Address]
 	default:
 	}
 
@@ -157,12 +189,17 @@ func (c *client) subscribe(msg pstypes.HubMessage) (bool, error) {
 	defer c.mtx.Unlock()
 
 	switch msg.Signal {
-	case pstypes.SigAddressTx:
+	case sigAddressTx, sigBTCAddressTx, sigLTCAddressTx:
 		am, ok := msg.Msg.(*pstypes.AddressMessage)
 		if !ok {
-			return false, fmt.Errorf("msg.Msg not a string (SigAddressTx): %T", msg.Msg)
+			return false, fmt.Errorf("msg.Msg not a string (%s): %T", addressSignalNames[msg.Signal], msg.Msg)
 		}
-		c.addrs[am.Address] = struct{}{}
+		addrs := c.addrs[msg.Signal]
+		if addrs == nil {
+			addrs = make(map[string]struct{}, 16)
+			c.addrs[msg.Signal] = addrs
+		}
+		addrs[am.Address] = struct{}{}
 	case sigPingAndUserCount, sigByeNow, sigDecodeTx, sigSentTx, sigSubscribe, sigUnsubscribe:
 		// These are not subscription-based events, do not clutter the subs map.
 		return false, nil
@@ -178,16 +215,18 @@ func (c *client) unsubscribe(msg pstypes.HubMessage) error {
 	defer c.mtx.Unlock()
 
 	switch msg.Signal {
-	case pstypes.SigAddressTx:
+	case sigAddressTx, sigBTCAddressTx, sigLTCAddressTx:
 		am, ok := msg.Msg.(*pstypes.AddressMessage)
 		if !ok {
-			return fmt.Errorf("msg.Msg not an AddressMessage (SigAddressTx): %T", msg.Msg)
+			return fmt.Errorf("msg.Msg not an AddressMessage (%s): %T", addressSignalNames[msg.Signal], msg.Msg)
 		}
-		delete(c.addrs, am.Address)
-		// Unsubscribe from address signals ONLY if this client has no more
-		// watched addresses.
-		if len(c.addrs) == 0 {
-			delete(c.subs, pstypes.SigAddressTx)
+		addrs := c.addrs[msg.Signal]
+		delete(addrs, am.Address)
+		// Unsubscribe from the chain's address signals ONLY if this client
+		// has no more watched addresses on the chain.
+		if len(addrs) == 0 {
+			delete(c.addrs, msg.Signal)
+			delete(c.subs, msg.Signal)
 		}
 	default:
 		delete(c.subs, msg.Signal)
@@ -202,8 +241,8 @@ func (c *client) unsubscribeAll() {
 	for sub := range c.subs {
 		delete(c.subs, sub)
 	}
-	for addr := range c.addrs {
-		delete(c.addrs, addr)
+	for sig := range c.addrs {
+		delete(c.addrs, sig)
 	}
 }
 
@@ -218,6 +257,7 @@ func NewWebsocketHub() *WebsocketHub {
 		quitWSHandler:    make(chan struct{}),
 		killed:           make(chan struct{}),
 		requestLimit:     maxPayloadBytes, // 1 MB
+		subCountsReq:     make(chan chan map[pstypes.HubSignal]int),
 	}
 }
 
@@ -247,6 +287,38 @@ func (wsh *WebsocketHub) NewClientHubSpoke() *clientHubSpoke {
 	return ch
 }
 
+// SubscriptionCounts returns the number of clients subscribed to each signal,
+// or nil if the run loop is not running. The internal client of the SSE
+// streams is not counted.
+func (wsh *WebsocketHub) SubscriptionCounts() map[pstypes.HubSignal]int {
+	reply := make(chan map[pstypes.HubSignal]int, 1)
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	select {
+	case wsh.subCountsReq <- reply:
+	case <-wsh.killed:
+		return nil
+	case <-timer.C:
+		return nil
+	}
+	return <-reply
+}
+
+// subscriptionCounts should only be called from the run loop.
+func (wsh *WebsocketHub) subscriptionCounts() map[pstypes.HubSignal]int {
+	counts := make(map[pstypes.HubSignal]int)
+	for _, cl := range wsh.clients {
+		cl.mtx.RLock()
+		if !cl.allAddrs {
+			for sig := range cl.subs {
+				counts[sig]++
+			}
+		}
+		cl.mtx.RUnlock()
+	}
+	return counts
+}
+
 // NumClients returns the number of clients connected to the websocket hub.
 func (wsh *WebsocketHub) NumClients() int {
 	// Swallow any type assertion error since the default int of 0 is OK.
@@ -413,6 +485,21 @@ func (wsh *WebsocketHub) Run() {
 		}
 	}
 
+	// Send the signal to subscribed PubSubHub clients.
+	sendToSubscribed := func(hubMsg pstypes.HubMessage) {
+		for spoke, client := range wsh.clients {
+			// Verify the client subscription before bothering PubSubHub.
+			if !client.isSubscribed(hubMsg) {
+				log.Tracef("Client %d is NOT subscribed to %s.", client.id, hubMsg)
+				continue
+			}
+			log.Tracef("Client %d is subscribed to %s.", client.id, hubMsg)
+
+			// Signal or unregister the client.
+			sendMsg(spoke, client, hubMsg)
+		}
+	}
+
 	for {
 		//events:
 		select {
@@ -467,33 +554,42 @@ func (wsh *WebsocketHub) Run() {
 				continue // break events
 			case sigMempoolUpdate:
 				log.Infof("Signaling mempool inventory refresh to %d websocket clients.", clientsCount)
-			case sigAddressTx:
+			case sigAddressTx, sigBTCAddressTx, sigLTCAddressTx:
 				// AddressMessage already validated, but check again.
 				addrMsg, ok := hubMsg.Msg.(*pstypes.AddressMessage)
 				if !ok || addrMsg == nil {
-					log.Errorf("sigAddressTx did not store a *AddressMessage in Msg.")
+					log.Errorf("%v did not store a *AddressMessage in Msg.", hubMsg.Signal)
 					continue
 				}
-			case sigNewTx:
-				log.Tracef("Received sigNewTx")
+			case sigNewTx, sigBTCNewTx, sigLTCNewTx:
+				log.Tracef("Received %v", hubMsg.Signal)
 				newTx, ok := hubMsg.Msg.(*exptypes.MempoolTx)
 				if !ok || newTx == nil {
 					continue
 				}
-				log.Tracef("Received new tx %s. Queueing in each client's send buffer...", newTx.Hash)
-				// Only signal clients if there are tx buffers ready to send or
-				// the ticker has fired.
-				if !(wsh.maybeSendTxns(newTx) || wsh.TimeToSendTxBuffer()) {
-					break
+				txsSig := pstypes.NewTxsSignals[hubMsg.Signal]
+				log.Tracef("Received new tx %s. Queueing in each %s client's send buffer...", newTx.Hash, txsSig)
+				readyToSend := wsh.maybeSendTxns(txsSig, newTx)
+
+				// In PubSubHub, the outgoing client message will be a new
+				// transactions signal (e.g. SigNewTxs), with a slice of
+				// transactions. Since the single new transaction received from
+				// the mempool monitor is already added to each client's slice,
+				// just relay the signal to PubSubHub with a nil slice to be a
+				// valid message. PubSubHub accesses each client's own slice.
+				switch {
+				case wsh.TimeToSendTxBuffer():
+					// Send the buffers of every chain when the ticker has
+					// fired. Empty buffers are not sent to the clients.
+					for _, sig := range newTxsSignals {
+						sendToSubscribed(pstypes.HubMessage{Signal: sig, Msg: ([]*exptypes.MempoolTx)(nil)})
+					}
+					// The Tx buffers were just sent.
+					wsh.SetTimeToSendTxBuffer(false)
+				case readyToSend:
+					sendToSubscribed(pstypes.HubMessage{Signal: txsSig, Msg: ([]*exptypes.MempoolTx)(nil)})
 				}
-
-				// In PubSubHub, the outgoing client message will be a
-				// SigNewTxs, with a slice of transactions. Since the single new
-				// transaction received from the mempool monitor is already
-				// added to each client's slice, just relay a sigNewTxs to
-				// PubSubHub with a nil slice to be a valid message.
-				hubMsg.Signal = sigNewTxs
-				hubMsg.Msg = ([]*exptypes.MempoolTx)(nil) // PubSubHub accesses each client's own slice.
+				continue // break events
 			case sigSubscribe, sigUnsubscribe:
 				log.Warnf("sigSubscribe and sigUnsubscribe are not broadcastable events.")
 				continue // break events
@@ -510,28 +606,14 @@ func (wsh *WebsocketHub) Run() {
 			}
 
 			// Send the signal to subscribed PubSubHub clients.
-			for spoke, client := range wsh.clients {
-				// Verify the client subscription before bothering PubSubHub.
-				// This is why the signal must be changed from sigNewTx to
-				// sigNewTxs in the case of hubMsg.Signal==sigNewTx case above.
-				if !client.isSubscribed(hubMsg) {
-					log.Tracef("Client %d is NOT subscribed to %s.", client.id, hubMsg)
-					continue
-				}
-				log.Tracef("Client %d is subscribed to %s.", client.id, hubMsg)
-
-				// Signal or unregister the client.
-				sendMsg(spoke, client, hubMsg)
-			}
-
-			if hubMsg.Signal == sigNewTxs {
-				// The Tx buffers were just sent.
-				wsh.SetTimeToSendTxBuffer(false)
-			}
+			sendToSubscribed(hubMsg)
 
 		case ch := <-wsh.Register:
 			wsh.registerClient(ch)
 
+		case reply := <-wsh.subCountsReq:
+			reply <- wsh.subscriptionCounts()
+
 		case c := <-wsh.Unregister:
 			wsh.unregisterClient(c)
 
@@ -545,13 +627,13 @@ func (wsh *WebsocketHub) Run() {
 	} // for {
 }
 
-// maybeSendTxns adds a mempool transaction to the client broadcast buffer. If
-// the buffer is at capacity, a goroutine is launched to signal for the
-// transactions to be sent to the clients.
-func (wsh *WebsocketHub) maybeSendTxns(tx *exptypes.MempoolTx) (someReadyToSend bool) {
+// maybeSendTxns adds a mempool transaction to the client broadcast buffers of
+// a new transactions signal. If a buffer is at capacity, the send ticker is
+// reset since the event loop is about to send the buffers.
+func (wsh *WebsocketHub) maybeSendTxns(txsSig pstypes.HubSignal, tx *exptypes.MempoolTx) (someReadyToSend bool) {
 	// addTxToBuffer adds the transaction to each client's tx buffer, and
 	// indicates if at least one client has a buffer at or above the send limit.
-	someReadyToSend = wsh.addTxToBuffer(tx)
+	someReadyToSend = wsh.addTxToBuffer(txsSig, tx)
 	if someReadyToSend {
 		// Reset the "time to send" ticker since the event loop is about send.
 		wsh.bufferTickerChan <- tickerSigReset
@@ -559,11 +641,17 @@ func (wsh *WebsocketHub) maybeSendTxns(tx *exptypes.MempoolTx) (someReadyToSend
 	return
 }
 
-// addTxToBuffer adds a tx to each client's tx buffer. The return boolean value
-// indicates if at least one buffer is ready to be sent.
-func (wsh *WebsocketHub) addTxToBuffer(tx *exptypes.MempoolTx) (someReadyToSend bool) {
+// addTxToBuffer adds a tx to the buffer of each client subscribed to the new
+// transactions signal. The return boolean value indicates if at least one
+// buffer is ready to be sent.
+func (wsh *WebsocketHub) addTxToBuffer(txsSig pstypes.HubSignal, tx *exptypes.MempoolTx) (someReadyToSend bool) {
 	for _, client := range wsh.clients {
-		someReadyToSend = client.newTxs.addTxToBuffer(tx)
+		if !client.isSubscribed(pstypes.HubMessage{Signal: txsSig}) {
+			continue
+		}
+		if client.newTxs[txsSig].addTxToBuffer(tx) {
+			someReadyToSend = true
+		}
 	}
 	return
 }
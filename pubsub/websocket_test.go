@@ -29,6 +29,11 @@ func Test_client_subscribe(t *testing.T) {
 			Signal: sigAddressTx,
 			Msg:    pstypes.AddressMessage{Address: "DsfX4WrSecUwGoRd9B7Lz1JjYssYaVKnjGC"},
 		}, errors.New("msg.Msg not a string (SigAddressTx): types.AddressMessage"), false},
+		{"ok btcaddr", newClient(), pstypes.HubMessage{
+			Signal: sigBTCAddressTx,
+			Msg:    &pstypes.AddressMessage{Address: "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+		}, nil, true},
+		{"ok ltcnewtx", newClient(), pstypes.HubMessage{Signal: sigLTCNewTx}, nil, true},
 		{"bad addr", newClient(), pstypes.HubMessage{
 			Signal: sigAddressTx,
 			Msg:    nil,
@@ -45,6 +50,9 @@ func Test_client_subscribe(t *testing.T) {
 			if ok != tt.wantOK {
 				t.Errorf("Did not subscribe to %v.", tt.hubMsg)
 			}
+			if ok && !tt.cl.isSubscribed(tt.hubMsg) {
+				t.Errorf("Not subscribed to %v.", tt.hubMsg)
+			}
 		})
 	}
 }
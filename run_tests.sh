@@ -42,7 +42,7 @@ tar xvf "$TMPDIR/test-data-repo/stakedb/test_ticket_pool_v1.bdgr.tar.xz" -C ./st
 # Do the module paths in order so that go mod tidy updates will cascade to
 # dependent modules.
 MODPATHS="./go.mod ./exchanges/go.mod ./gov/go.mod ./db/dcrpg/go.mod ./cmd/dcrdata/go.mod \
-    ./pubsub/democlient/go.mod ./cmd/swapscan-btc/go.mod ./testutil/dbload/go.mod \
+    ./pubsub/democlient/go.mod ./cmd/swapscan/go.mod ./cmd/apikeys/go.mod ./testutil/dbload/go.mod \
     ./testutil/apiload/go.mod ./exchanges/rateserver/go.mod"
 #MODPATHS=$(find . -name go.mod -type f -print)
 
@@ -20,6 +20,16 @@ var (
 	btcZeroHash = chainhash.Hash{}
 )
 
+// BTCDustRule is the dust policy of Bitcoin Core, with the default dust relay fee of
+// 3000 atoms per kB and the size of an input redeeming a compressed P2PKH
+// output, or a P2WPKH output with the witness discounted.
+var BTCDustRule = DustRule{
+	DustRelayFeePerKb: 3000,
+	OutputOverhead:    8,
+	InputSize:         32 + 4 + 1 + 107 + 4,
+	WitnessInputSize:  32 + 4 + 1 + 107/4 + 4,
+}
+
 type BTCAddressOutpoints struct {
 	Address   string
 	Outpoints []*btcwire.OutPoint
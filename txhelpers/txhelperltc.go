@@ -20,6 +20,16 @@ var (
 	ltcZeroHash = chainhash.Hash{}
 )
 
+// LTCDustRule is the dust policy of Litecoin Core, with the default dust relay fee of
+// 30000 atoms per kB and the size of an input redeeming a compressed P2PKH
+// output, or a P2WPKH output with the witness discounted.
+var LTCDustRule = DustRule{
+	DustRelayFeePerKb: 30000,
+	OutputOverhead:    8,
+	InputSize:         32 + 4 + 1 + 107 + 4,
+	WitnessInputSize:  32 + 4 + 1 + 107/4 + 4,
+}
+
 type LTCAddressOutpoints struct {
 	Address   string
 	Outpoints []*ltcwire.OutPoint
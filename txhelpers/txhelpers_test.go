@@ -309,6 +309,38 @@ func TestFeeRate(t *testing.T) {
 	}
 }
 
+func TestDustRuleIsDust(t *testing.T) {
+	p2pkh := make([]byte, 25)
+	p2pkh[0], p2pkh[1], p2pkh[2] = 0x76, 0xa9, 0x14
+	p2wpkh := make([]byte, 22)
+	p2wpkh[1] = 0x14
+
+	tests := []struct {
+		name     string
+		rule     DustRule
+		pkScript []byte
+		limit    int64 // smallest amount that is not dust
+	}{
+		{"dcr p2pkh", DCRDustRule, p2pkh, 6060},
+		{"btc p2pkh", BTCDustRule, p2pkh, 546},
+		{"btc p2wpkh", BTCDustRule, p2wpkh, 294},
+		{"ltc p2pkh", LTCDustRule, p2pkh, 5460},
+		{"ltc p2wpkh", LTCDustRule, p2wpkh, 2940},
+	}
+	for _, tt := range tests {
+		if !tt.rule.IsDust(tt.limit-1, tt.pkScript) {
+			t.Errorf("%s: %d should be dust", tt.name, tt.limit-1)
+		}
+		if tt.rule.IsDust(tt.limit, tt.pkScript) {
+			t.Errorf("%s: %d should not be dust", tt.name, tt.limit)
+		}
+	}
+
+	if IsWitnessProgram(p2pkh) || !IsWitnessProgram(p2wpkh) {
+		t.Errorf("IsWitnessProgram failed")
+	}
+}
+
 func randomHash() chainhash.Hash {
 	var hash chainhash.Hash
 	if _, err := rand.Read(hash[:]); err != nil {
@@ -76,7 +76,7 @@ const (
 	//   - 1 byte compact int encoding value 107
 	//   - 107 bytes signature script
 	//   - 4 bytes sequence
-	// redeemP2PKHInputSize = 32 + 4 + 1 + 8 + 4 + 4 + 1 + redeemP2PKHSigScriptSize + 4
+	redeemP2PKHInputSize = 32 + 4 + 1 + 8 + 4 + 4 + 1 + redeemP2PKHSigScriptSize + 4
 
 	// p2pkhPkScriptSize is the size of a transaction output script that
 	// pays to a compressed pubkey hash. It is calculated as:
@@ -229,3 +229,64 @@ func EstimateSerializeSizeFromScriptSizes(inputScriptSizes, outputScriptSizes []
 		wire.VarIntSerializeSize(uint64(outputCount)) +
 		txInsSize + txOutsSize + changeSize
 }
+
+// DustRule is the relay policy of a chain on dust outputs, those whose value
+// is less than the fee to spend them at the dust relay fee rate. The cost of
+// spending an output is taken as the size of the output and of the input that
+// redeems it.
+type DustRule struct {
+	// DustRelayFeePerKb is the fee rate, in atoms per kB, of the dust
+	// threshold. It is the minimum relay fee times three on DCR.
+	DustRelayFeePerKb int64
+	// OutputOverhead is the size of an output besides its script and the
+	// compact int encoding of the script size.
+	OutputOverhead int
+	// InputSize is the size of an input spending the output.
+	InputSize int
+	// WitnessInputSize is the size of an input spending a witness program
+	// output, or zero on the chains without segregated witness.
+	WitnessInputSize int
+}
+
+// DCRDustRule is the dust policy of dcrd and dcrwallet, see
+// dcrwallet/wallet/txrules.IsDustAmount.
+var DCRDustRule = DustRule{
+	DustRelayFeePerKb: 3 * int64(DefaultRelayFeePerKb),
+	OutputOverhead:    8 + 2,
+	InputSize:         redeemP2PKHInputSize,
+}
+
+// OutputSize returns the serialize size of an output with a script of the
+// given size.
+func (r DustRule) OutputSize(scriptSize int) int {
+	return r.OutputOverhead + wire.VarIntSerializeSize(uint64(scriptSize)) + scriptSize
+}
+
+// SpendSize returns the size of an output with the script pkScript and of the
+// input that spends it.
+func (r DustRule) SpendSize(pkScript []byte) int {
+	inputSize := r.InputSize
+	if r.WitnessInputSize > 0 && IsWitnessProgram(pkScript) {
+		inputSize = r.WitnessInputSize
+	}
+	return r.OutputSize(len(pkScript)) + inputSize
+}
+
+// IsDust checks if an output of the given amount paying to pkScript is dust.
+func (r DustRule) IsDust(amount int64, pkScript []byte) bool {
+	return amount*1000 < r.DustRelayFeePerKb*int64(r.SpendSize(pkScript))
+}
+
+// IsWitnessProgram checks if pkScript is a segregated witness program: a
+// version opcode, OP_0 or OP_1 to OP_16, followed by a single push of 2 to 40
+// bytes.
+func IsWitnessProgram(pkScript []byte) bool {
+	n := len(pkScript)
+	if n < 4 || n > 42 {
+		return false
+	}
+	if pkScript[0] != 0x00 && (pkScript[0] < 0x51 || pkScript[0] > 0x60) {
+		return false
+	}
+	return int(pkScript[1]) == n-2
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// resolveTimeout is the time allowed to resolve the host of a new
+// subscription.
+const resolveTimeout = 5 * time.Second
+
+// errForbiddenAddress is the error of the callback hosts with an address that
+// is not a public unicast address, and not in the allowed networks.
+var errForbiddenAddress = errors.New("forbidden callback address")
+
+// addrFilter rejects the callback addresses in the loopback, private,
+// link-local, unspecified and multicast ranges, so that subscriptions cannot
+// reach the services of the dcrdata host and its network. The allowed
+// networks are permitted regardless.
+type addrFilter struct {
+	allowed []*net.IPNet
+}
+
+// permitted checks if an address may be called back.
+func (f *addrFilter) permitted(ip net.IP) bool {
+	for _, n := range f.allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() && !ip.IsUnspecified()
+}
+
+// checkHost resolves the host of a callback URL with lookup, and checks each
+// of its addresses.
+func (f *addrFilter) checkHost(ctx context.Context, host string,
+	lookup func(context.Context, string) ([]net.IPAddr, error)) error {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := lookup(ctx, host)
+		if err != nil {
+			return fmt.Errorf("unable to resolve %q: %w", host, err)
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+	for _, ip := range ips {
+		if !f.permitted(ip) {
+			return fmt.Errorf("%w %s of %q", errForbiddenAddress, ip, host)
+		}
+	}
+	return nil
+}
+
+// control is the Control of the dialer of the deliveries. The addresses are
+// checked again when they are dialed, since the host of a subscription may
+// resolve to another address by then.
+func (f *addrFilter) control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !f.permitted(ip) {
+		return fmt.Errorf("%w %s", errForbiddenAddress, host)
+	}
+	return nil
+}
+
+// newDeliveryClient creates the HTTP client of the deliveries, which does not
+// follow redirects or use a proxy, and only dials permitted addresses.
+func newDeliveryClient(f *addrFilter) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   deliveryTimeout,
+		KeepAlive: 30 * time.Second,
+		Control:   f.control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   deliveryTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+)
+
+// These are the headers of a delivery.
+const (
+	// EventHeader is the event of the delivery.
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader is the ID of the delivery, which is the same for each
+	// attempt so that receivers may ignore repeated deliveries.
+	DeliveryHeader = "X-Webhook-Delivery"
+	// TimestampHeader is the time of the attempt in UNIX seconds.
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader is "sha256=" followed by the hex encoded HMAC-SHA256
+	// of the timestamp, a period and the body, keyed with the secret of the
+	// subscription. See Sign.
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	// maxAttempts is the number of attempts of a delivery before it is moved
+	// to the dead letters.
+	maxAttempts = 10
+	// retryBaseDelay is the delay before the first retry of a delivery. The
+	// delay doubles with each attempt, up to retryMaxDelay.
+	retryBaseDelay = 15 * time.Second
+	retryMaxDelay  = 2 * time.Hour
+
+	deliveryTimeout  = 10 * time.Second
+	deliveryInterval = time.Second
+	// claimBatchSize is the number of deliveries claimed at once, which are
+	// attempted concurrently.
+	claimBatchSize = 32
+	// claimLease is how long a claimed delivery is reserved for the claiming
+	// instance. It is retried after the lease if the instance stops.
+	claimLease = 2 * deliveryTimeout
+	// maxErrorLength is the length of the response body kept in the error
+	// of a failed attempt.
+	maxErrorLength = 256
+)
+
+// Sign returns the value of the SignatureHeader of a delivery body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received delivery body, from the values of
+// its TimestampHeader and SignatureHeader. Receivers should also reject old
+// timestamps to prevent the replay of deliveries.
+func Verify(secret, timestamp, signature string, body []byte) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
+
+// retryDelay is the delay after the given number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
+// deliveryLoop delivers the due deliveries of the outbox until the context is
+// canceled.
+func (d *Dispatcher) deliveryLoop(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(deliveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Keep delivering while full batches are claimed.
+			n := d.deliverDue(ctx)
+			for n == claimBatchSize && ctx.Err() == nil {
+				n = d.deliverDue(ctx)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverDue claims and attempts the due deliveries, and returns the number of
+// deliveries claimed.
+func (d *Dispatcher) deliverDue(ctx context.Context) int {
+	deliveries, err := d.store.ClaimWebhookDeliveries(claimBatchSize, claimLease)
+	if err != nil {
+		log.Errorf("Unable to claim webhook deliveries: %v", err)
+		return 0
+	}
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *dbtypes.WebhookDelivery) {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+	return len(deliveries)
+}
+
+// deliver attempts a delivery, and removes it from the outbox, schedules its
+// next attempt, or moves it to the dead letters.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *dbtypes.WebhookDelivery) {
+	err := d.post(ctx, delivery)
+	if err == nil {
+		if err = d.store.CompleteWebhookDelivery(delivery.ID); err != nil {
+			log.Errorf("Unable to complete webhook delivery %d: %v", delivery.ID, err)
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		// Shutting down. The delivery is attempted again after its lease.
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	log.Debugf("Webhook delivery %d to %s failed (attempt %d): %v", delivery.ID,
+		delivery.URL, attempts, err)
+	if attempts >= maxAttempts {
+		log.Warnf("Webhook delivery %d to %s failed %d times, moving it to the dead letters.",
+			delivery.ID, delivery.URL, attempts)
+		err = d.store.DeadLetterWebhookDelivery(delivery.ID, err.Error())
+	} else {
+		err = d.store.RetryWebhookDelivery(delivery.ID, d.now().Add(retryDelay(attempts)), err.Error())
+	}
+	if err != nil {
+		log.Errorf("Unable to update webhook delivery %d: %v", delivery.ID, err)
+	}
+}
+
+// post sends the signed body of a delivery. Responses other than 2xx are
+// errors.
+func (d *Dispatcher) post(ctx context.Context, delivery *dbtypes.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "dcrdata-webhook")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
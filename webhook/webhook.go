@@ -0,0 +1,597 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+// Package webhook delivers chain events to the callback URLs registered by the
+// owners of API keys. Events are derived from the same hub signals as the
+// websocket events of the pubsub package: new blocks of each chain, and the
+// address transactions of the mempool. They are matched with the active
+// subscriptions and written to a persistent outbox, from which they are
+// delivered as HMAC signed JSON bodies. Failed deliveries are retried with
+// exponential backoff, and moved to a dead letter list after maxAttempts.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
+	btcwire "github.com/btcsuite/btcd/wire"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+	ltcchaincfg "github.com/ltcsuite/ltcd/chaincfg"
+	"github.com/ltcsuite/ltcd/ltcutil"
+	ltcwire "github.com/ltcsuite/ltcd/wire"
+
+	"github.com/decred/dcrdata/v8/blockdata"
+	"github.com/decred/dcrdata/v8/blockdata/blockdatabtc"
+	"github.com/decred/dcrdata/v8/blockdata/blockdataltc"
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// These are the events of a subscription.
+const (
+	// EventNewBlock is a new block of a chain.
+	EventNewBlock = "newblock"
+	// EventAddress is a transaction funding or spending an output of the
+	// target address, when it enters the mempool (DCR only) and when it is
+	// mined.
+	EventAddress = "address"
+	// EventTxConfirmed is the target transaction reaching the number of
+	// confirmations of the subscription. It is delivered once.
+	EventTxConfirmed = "txconfirmed"
+	// EventSwapRedeemed is the redemption of an atomic swap contract, or of
+	// the target contract transaction.
+	EventSwapRedeemed = "swapredeemed"
+)
+
+const (
+	// maxSubscriptionsPerKey is the number of subscriptions an API key may
+	// have.
+	maxSubscriptionsPerKey = 100
+	// maxConfirmations is the largest number of confirmations of a
+	// txconfirmed subscription.
+	maxConfirmations = 1000
+	// subscriptionRefreshInterval is how often the active subscriptions are
+	// reloaded, which picks up the changes made by other instances.
+	subscriptionRefreshInterval = time.Minute
+	// relayBufferSize is the number of hub signals queued for processing.
+	relayBufferSize = 256
+)
+
+// ErrInvalidSubscription is wrapped by the errors of Subscribe for invalid
+// subscriptions.
+var ErrInvalidSubscription = errors.New("invalid webhook subscription")
+
+// Store is the storage of the webhook subscriptions and deliveries, and the
+// chain data used to derive the events.
+type Store interface {
+	WebhookSubscriptions() ([]*dbtypes.WebhookSubscription, error)
+	WebhookSubscriptionsByKey(keyID int64) ([]*dbtypes.WebhookSubscription, error)
+	InsertWebhookSubscription(sub *dbtypes.WebhookSubscription) error
+	DeleteWebhookSubscription(keyID, id int64) error
+	DeactivateWebhookSubscription(id int64) error
+	EnqueueWebhookDeliveries(deliveries []*dbtypes.WebhookDelivery) error
+	ClaimWebhookDeliveries(limit int, lease time.Duration) ([]*dbtypes.WebhookDelivery, error)
+	CompleteWebhookDelivery(id int64) error
+	RetryWebhookDelivery(id int64, next time.Time, lastErr string) error
+	DeadLetterWebhookDelivery(id int64, lastErr string) error
+	WebhookDeadLetters(keyID int64, limit int) ([]*dbtypes.WebhookDeadLetter, error)
+	WebhookAddressActivity(chainType string, addrs, txHashes []string) ([]*dbtypes.WebhookAddressActivity, error)
+	WebhookTxBlocks(chainType string, txHashes []string) (map[string]*dbtypes.WebhookTxBlock, error)
+	WebhookSwapRedemptions(chainType string, height int64) ([]*dbtypes.WebhookSwapRedemption, error)
+}
+
+// Config is the configuration of a Dispatcher. The BTC and LTC parameters are
+// nil for disabled chains.
+type Config struct {
+	Store     Store
+	Params    *chaincfg.Params
+	BTCParams *btcchaincfg.Params
+	LTCParams *ltcchaincfg.Params
+	// Client is the HTTP client of the deliveries. A client with a
+	// deliveryTimeout that does not follow redirects, and only dials public
+	// addresses and the AllowedNets, is used if nil.
+	Client *http.Client
+	// AllowedNets are the networks of the callback addresses that are
+	// permitted although they are loopback, private, link-local, unspecified
+	// or multicast addresses, e.g. 127.0.0.1/32 for a local receiver.
+	AllowedNets []*net.IPNet
+}
+
+// Event is the JSON body of a delivery.
+type Event struct {
+	Event          string `json:"event"`
+	Chain          string `json:"chain"`
+	SubscriptionID int64  `json:"subscription_id"`
+	Time           int64  `json:"time"`
+	Data           any    `json:"data"`
+}
+
+// BlockData is the data of a newblock event, and the block of the other
+// events.
+type BlockData struct {
+	Height int64  `json:"height"`
+	Hash   string `json:"hash"`
+	Time   int64  `json:"time"`
+}
+
+// AddressData is the data of an address event. The direction and value are
+// only known for mined transactions.
+type AddressData struct {
+	Address   string     `json:"address"`
+	TxHash    string     `json:"tx_hash"`
+	Confirmed bool       `json:"confirmed"`
+	Direction string     `json:"direction,omitempty"`
+	Value     int64      `json:"value,omitempty"`
+	Block     *BlockData `json:"block,omitempty"`
+}
+
+// TxConfirmedData is the data of a txconfirmed event.
+type TxConfirmedData struct {
+	TxHash        string    `json:"tx_hash"`
+	Confirmations int64     `json:"confirmations"`
+	Block         BlockData `json:"block"`
+}
+
+// SwapRedeemedData is the data of a swapredeemed event.
+type SwapRedeemedData struct {
+	*dbtypes.WebhookSwapRedemption
+	Block BlockData `json:"block"`
+}
+
+// blockMessage is the message of the new block hub signals relayed to the
+// Dispatcher.
+type blockMessage struct {
+	chain    string
+	block    BlockData
+	txHashes []string
+}
+
+// Dispatcher matches the hub signals with the webhook subscriptions, and
+// delivers the events in the outbox. Create a Dispatcher with NewDispatcher.
+type Dispatcher struct {
+	store     Store
+	params    *chaincfg.Params
+	btcParams *btcchaincfg.Params
+	ltcParams *ltcchaincfg.Params
+	client    *http.Client
+	filter    *addrFilter
+	lookupIP  func(context.Context, string) ([]net.IPAddr, error)
+	relay     chan pstypes.HubMessage
+	now       func() time.Time
+
+	mtx  sync.RWMutex
+	subs []*dbtypes.WebhookSubscription
+}
+
+// NewDispatcher creates a Dispatcher and loads the active subscriptions.
+func NewDispatcher(cfg *Config) (*Dispatcher, error) {
+	filter := &addrFilter{allowed: cfg.AllowedNets}
+	client := cfg.Client
+	if client == nil {
+		client = newDeliveryClient(filter)
+	}
+	d := &Dispatcher{
+		store:     cfg.Store,
+		params:    cfg.Params,
+		btcParams: cfg.BTCParams,
+		ltcParams: cfg.LTCParams,
+		client:    client,
+		filter:    filter,
+		lookupIP:  net.DefaultResolver.LookupIPAddr,
+		relay:     make(chan pstypes.HubMessage, relayBufferSize),
+		now:       time.Now,
+	}
+	if err := d.loadSubscriptions(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Relay returns the channel on which the Dispatcher receives hub signals. The
+// signals other than SigAddressTx are ignored.
+func (d *Dispatcher) Relay() chan<- pstypes.HubMessage {
+	return d.relay
+}
+
+func (d *Dispatcher) loadSubscriptions() error {
+	subs, err := d.store.WebhookSubscriptions()
+	if err != nil {
+		return err
+	}
+	d.mtx.Lock()
+	d.subs = subs
+	d.mtx.Unlock()
+	return nil
+}
+
+// subscriptions returns the active subscriptions for an event of a chain.
+func (d *Dispatcher) subscriptions(event, chain string) []*dbtypes.WebhookSubscription {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	var subs []*dbtypes.WebhookSubscription
+	for _, sub := range d.subs {
+		if sub.Event == event && sub.Chain == chain {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+func (d *Dispatcher) removeSubscription(id int64) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for i, sub := range d.subs {
+		if sub.ID == id {
+			d.subs = append(d.subs[:i:i], d.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// validate checks a new subscription and normalizes its target. The host of
+// the URL must only resolve to permitted addresses.
+func (d *Dispatcher) validate(sub *dbtypes.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	if err = d.filter.checkHost(ctx, u.Hostname(), d.lookupIP); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	switch sub.Chain {
+	case mutilchain.TYPEDCR:
+	case mutilchain.TYPEBTC:
+		if d.btcParams == nil {
+			return fmt.Errorf("%w: chain %q is disabled", ErrInvalidSubscription, sub.Chain)
+		}
+	case mutilchain.TYPELTC:
+		if d.ltcParams == nil {
+			return fmt.Errorf("%w: chain %q is disabled", ErrInvalidSubscription, sub.Chain)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported chain %q", ErrInvalidSubscription, sub.Chain)
+	}
+
+	switch sub.Event {
+	case EventNewBlock:
+		sub.Target, sub.Confirmations = "", 0
+	case EventAddress:
+		if !d.validAddress(sub.Chain, sub.Target) {
+			return fmt.Errorf("%w: invalid %s address %q", ErrInvalidSubscription, sub.Chain, sub.Target)
+		}
+		sub.Confirmations = 0
+	case EventTxConfirmed:
+		if _, err = chainhash.NewHashFromStr(sub.Target); err != nil || len(sub.Target) != 2*chainhash.HashSize {
+			return fmt.Errorf("%w: invalid transaction hash %q", ErrInvalidSubscription, sub.Target)
+		}
+		if sub.Confirmations < 1 || sub.Confirmations > maxConfirmations {
+			return fmt.Errorf("%w: confirmations must be between 1 and %d",
+				ErrInvalidSubscription, maxConfirmations)
+		}
+	case EventSwapRedeemed:
+		if sub.Target != "" {
+			if _, err = chainhash.NewHashFromStr(sub.Target); err != nil || len(sub.Target) != 2*chainhash.HashSize {
+				return fmt.Errorf("%w: invalid contract transaction hash %q", ErrInvalidSubscription, sub.Target)
+			}
+		}
+		sub.Confirmations = 0
+	default:
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidSubscription, sub.Event)
+	}
+	return nil
+}
+
+func (d *Dispatcher) validAddress(chain, addr string) bool {
+	var err error
+	switch chain {
+	case mutilchain.TYPEDCR:
+		_, err = stdaddr.DecodeAddress(addr, d.params)
+	case mutilchain.TYPEBTC:
+		_, err = btcutil.DecodeAddress(addr, d.btcParams)
+	case mutilchain.TYPELTC:
+		_, err = ltcutil.DecodeAddress(addr, d.ltcParams)
+	default:
+		return false
+	}
+	return err == nil
+}
+
+// Subscribe validates and stores a new subscription of an API key, with a new
+// random secret for the signatures of its deliveries. Errors wrapping
+// ErrInvalidSubscription are returned for invalid subscriptions.
+func (d *Dispatcher) Subscribe(sub *dbtypes.WebhookSubscription) error {
+	if err := d.validate(sub); err != nil {
+		return err
+	}
+	subs, err := d.store.WebhookSubscriptionsByKey(sub.KeyID)
+	if err != nil {
+		return err
+	}
+	if len(subs) >= maxSubscriptionsPerKey {
+		return fmt.Errorf("%w: an API key may have at most %d subscriptions",
+			ErrInvalidSubscription, maxSubscriptionsPerKey)
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return err
+	}
+	sub.Secret = hex.EncodeToString(secret)
+	if err = d.store.InsertWebhookSubscription(sub); err != nil {
+		return err
+	}
+
+	stored := *sub
+	d.mtx.Lock()
+	d.subs = append(d.subs, &stored)
+	d.mtx.Unlock()
+	return nil
+}
+
+// Subscriptions returns the subscriptions of an API key, without their
+// secrets.
+func (d *Dispatcher) Subscriptions(keyID int64) ([]*dbtypes.WebhookSubscription, error) {
+	subs, err := d.store.WebhookSubscriptionsByKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		sub.Secret = ""
+	}
+	return subs, nil
+}
+
+// Unsubscribe deletes a subscription of an API key, and its pending
+// deliveries. dbtypes.ErrNoResult is returned if the key has no such
+// subscription.
+func (d *Dispatcher) Unsubscribe(keyID, id int64) error {
+	if err := d.store.DeleteWebhookSubscription(keyID, id); err != nil {
+		return err
+	}
+	d.removeSubscription(id)
+	return nil
+}
+
+// DeadLetters returns the limit most recent deliveries to the subscriptions of
+// an API key that failed too many times to be retried.
+func (d *Dispatcher) DeadLetters(keyID int64, limit int) ([]*dbtypes.WebhookDeadLetter, error) {
+	return d.store.WebhookDeadLetters(keyID, limit)
+}
+
+// Store signals a new DCR block to the Dispatcher. Store satisfies
+// blockdata.BlockDataSaver, and must follow the ChainDB in the savers so that
+// the block data is stored when the events are derived.
+func (d *Dispatcher) Store(blockData *blockdata.BlockData, msgBlock *wire.MsgBlock) error {
+	txHashes := make([]string, 0, len(msgBlock.Transactions)+len(msgBlock.STransactions))
+	for _, tx := range msgBlock.Transactions {
+		txHashes = append(txHashes, tx.TxHash().String())
+	}
+	for _, tx := range msgBlock.STransactions {
+		txHashes = append(txHashes, tx.TxHash().String())
+	}
+	d.signalBlock(pstypes.SigNewBlock, &blockMessage{
+		chain:    mutilchain.TYPEDCR,
+		block:    BlockData{int64(blockData.Header.Height), blockData.Header.Hash, blockData.Header.Time},
+		txHashes: txHashes,
+	})
+	return nil
+}
+
+// BTCStore signals a new BTC block to the Dispatcher. BTCStore satisfies
+// blockdatabtc.BlockDataSaver.
+func (d *Dispatcher) BTCStore(blockData *blockdatabtc.BlockData, msgBlock *btcwire.MsgBlock) error {
+	txHashes := make([]string, 0, len(msgBlock.Transactions))
+	for _, tx := range msgBlock.Transactions {
+		txHashes = append(txHashes, tx.TxHash().String())
+	}
+	d.signalBlock(pstypes.SigNewBTCBlock, &blockMessage{
+		chain:    mutilchain.TYPEBTC,
+		block:    BlockData{int64(blockData.Header.Height), blockData.Header.Hash, blockData.Header.Time},
+		txHashes: txHashes,
+	})
+	return nil
+}
+
+// LTCStore signals a new LTC block to the Dispatcher. LTCStore satisfies
+// blockdataltc.BlockDataSaver.
+func (d *Dispatcher) LTCStore(blockData *blockdataltc.BlockData, msgBlock *ltcwire.MsgBlock) error {
+	txHashes := make([]string, 0, len(msgBlock.Transactions))
+	for _, tx := range msgBlock.Transactions {
+		txHashes = append(txHashes, tx.TxHash().String())
+	}
+	d.signalBlock(pstypes.SigNewLTCBlock, &blockMessage{
+		chain:    mutilchain.TYPELTC,
+		block:    BlockData{int64(blockData.Header.Height), blockData.Header.Hash, blockData.Header.Time},
+		txHashes: txHashes,
+	})
+	return nil
+}
+
+func (d *Dispatcher) signalBlock(sig pstypes.HubSignal, msg *blockMessage) {
+	select {
+	case d.relay <- pstypes.HubMessage{Signal: sig, Msg: msg}:
+	case <-time.After(10 * time.Second):
+		log.Errorf("%v send failed: Timeout waiting for the webhook dispatcher.", sig)
+	}
+}
+
+// Run processes the hub signals and delivers the events of the outbox until
+// the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	wg.Add(1)
+	go d.deliveryLoop(ctx, wg)
+
+	refresh := time.NewTicker(subscriptionRefreshInterval)
+	defer refresh.Stop()
+	for {
+		select {
+		case msg := <-d.relay:
+			d.handle(msg)
+		case <-refresh.C:
+			if err := d.loadSubscriptions(); err != nil {
+				log.Errorf("Unable to load webhook subscriptions: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handle derives the events of a hub signal, and adds their deliveries to the
+// outbox.
+func (d *Dispatcher) handle(msg pstypes.HubMessage) {
+	var deliveries []*dbtypes.WebhookDelivery
+	switch msg.Signal {
+	case pstypes.SigAddressTx:
+		am, ok := msg.Msg.(*pstypes.AddressMessage)
+		if !ok {
+			return
+		}
+		for _, sub := range d.subscriptions(EventAddress, mutilchain.TYPEDCR) {
+			if sub.Target == am.Address {
+				deliveries = d.appendDelivery(deliveries, sub, &AddressData{
+					Address: am.Address,
+					TxHash:  am.TxHash,
+				})
+			}
+		}
+	case pstypes.SigNewBlock, pstypes.SigNewBTCBlock, pstypes.SigNewLTCBlock:
+		bm, ok := msg.Msg.(*blockMessage)
+		if !ok {
+			return
+		}
+		deliveries = d.blockDeliveries(bm)
+	default:
+		return
+	}
+
+	if err := d.store.EnqueueWebhookDeliveries(deliveries); err != nil {
+		log.Errorf("Unable to enqueue %d webhook deliveries of %v: %v", len(deliveries), msg.Signal, err)
+	}
+}
+
+// blockDeliveries derives the events of a new block.
+func (d *Dispatcher) blockDeliveries(bm *blockMessage) []*dbtypes.WebhookDelivery {
+	var deliveries []*dbtypes.WebhookDelivery
+	for _, sub := range d.subscriptions(EventNewBlock, bm.chain) {
+		block := bm.block
+		deliveries = d.appendDelivery(deliveries, sub, &block)
+	}
+
+	if addrSubs := d.subscriptions(EventAddress, bm.chain); len(addrSubs) > 0 {
+		addrs := make([]string, 0, len(addrSubs))
+		for _, sub := range addrSubs {
+			addrs = append(addrs, sub.Target)
+		}
+		activity, err := d.store.WebhookAddressActivity(bm.chain, addrs, bm.txHashes)
+		if err != nil {
+			log.Errorf("Unable to retrieve the %s address activity of block %d: %v",
+				bm.chain, bm.block.Height, err)
+		}
+		for _, a := range activity {
+			data := &AddressData{
+				Address:   a.Address,
+				TxHash:    a.TxHash,
+				Confirmed: true,
+				Direction: "spending",
+				Value:     a.Value,
+				Block:     &bm.block,
+			}
+			if a.IsFunding {
+				data.Direction = "funding"
+			}
+			for _, sub := range addrSubs {
+				if sub.Target == a.Address {
+					deliveries = d.appendDelivery(deliveries, sub, data)
+				}
+			}
+		}
+	}
+
+	if txSubs := d.subscriptions(EventTxConfirmed, bm.chain); len(txSubs) > 0 {
+		txHashes := make([]string, 0, len(txSubs))
+		for _, sub := range txSubs {
+			txHashes = append(txHashes, sub.Target)
+		}
+		blocks, err := d.store.WebhookTxBlocks(bm.chain, txHashes)
+		if err != nil {
+			log.Errorf("Unable to retrieve the blocks of %d %s transactions: %v",
+				len(txHashes), bm.chain, err)
+		}
+		for _, sub := range txSubs {
+			block, ok := blocks[sub.Target]
+			if !ok {
+				continue
+			}
+			confirmations := bm.block.Height - block.Height + 1
+			if confirmations < int64(sub.Confirmations) {
+				continue
+			}
+			if err = d.store.DeactivateWebhookSubscription(sub.ID); err != nil {
+				log.Errorf("Unable to deactivate webhook subscription %d: %v", sub.ID, err)
+				continue
+			}
+			d.removeSubscription(sub.ID)
+			deliveries = d.appendDelivery(deliveries, sub, &TxConfirmedData{
+				TxHash:        sub.Target,
+				Confirmations: confirmations,
+				Block:         BlockData{Height: block.Height, Hash: block.Hash},
+			})
+		}
+	}
+
+	if swapSubs := d.subscriptions(EventSwapRedeemed, bm.chain); len(swapSubs) > 0 {
+		swaps, err := d.store.WebhookSwapRedemptions(bm.chain, bm.block.Height)
+		if err != nil {
+			log.Errorf("Unable to retrieve the %s swap redemptions of block %d: %v",
+				bm.chain, bm.block.Height, err)
+		}
+		for _, swap := range swaps {
+			for _, sub := range swapSubs {
+				if sub.Target == "" || sub.Target == swap.ContractTx {
+					deliveries = d.appendDelivery(deliveries, sub, &SwapRedeemedData{swap, bm.block})
+				}
+			}
+		}
+	}
+	return deliveries
+}
+
+func (d *Dispatcher) appendDelivery(deliveries []*dbtypes.WebhookDelivery, sub *dbtypes.WebhookSubscription,
+	data any) []*dbtypes.WebhookDelivery {
+	payload, err := json.Marshal(&Event{
+		Event:          sub.Event,
+		Chain:          sub.Chain,
+		SubscriptionID: sub.ID,
+		Time:           d.now().Unix(),
+		Data:           data,
+	})
+	if err != nil {
+		log.Errorf("Unable to encode a %s event: %v", sub.Event, err)
+		return deliveries
+	}
+	return append(deliveries, &dbtypes.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		Event:          sub.Event,
+		Payload:        payload,
+	})
+}
@@ -0,0 +1,447 @@
+// Copyright (c) 2024, The Decred developers
+// See LICENSE for details.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+
+	"github.com/decred/dcrdata/v8/db/dbtypes"
+	"github.com/decred/dcrdata/v8/mutilchain"
+	pstypes "github.com/decred/dcrdata/v8/pubsub/types"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	mtx         sync.Mutex
+	subs        []*dbtypes.WebhookSubscription
+	outbox      []*dbtypes.WebhookDelivery
+	retries     map[int64]time.Time
+	deadLetters []*dbtypes.WebhookDeadLetter
+	nextID      int64
+	activity    []*dbtypes.WebhookAddressActivity
+	txBlocks    map[string]*dbtypes.WebhookTxBlock
+	txLookups   int
+}
+
+func newMemStore() *memStore {
+	return &memStore{retries: make(map[int64]time.Time)}
+}
+
+func (s *memStore) WebhookSubscriptions() ([]*dbtypes.WebhookSubscription, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var subs []*dbtypes.WebhookSubscription
+	for _, sub := range s.subs {
+		if sub.Active {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (s *memStore) WebhookSubscriptionsByKey(keyID int64) ([]*dbtypes.WebhookSubscription, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var subs []*dbtypes.WebhookSubscription
+	for _, sub := range s.subs {
+		if sub.KeyID == keyID {
+			c := *sub
+			subs = append(subs, &c)
+		}
+	}
+	return subs, nil
+}
+
+func (s *memStore) InsertWebhookSubscription(sub *dbtypes.WebhookSubscription) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.nextID++
+	sub.ID, sub.Active = s.nextID, true
+	c := *sub
+	s.subs = append(s.subs, &c)
+	return nil
+}
+
+func (s *memStore) DeleteWebhookSubscription(keyID, id int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for i, sub := range s.subs {
+		if sub.ID == id && sub.KeyID == keyID {
+			s.subs = append(s.subs[:i], s.subs[i+1:]...)
+			return nil
+		}
+	}
+	return dbtypes.ErrNoResult
+}
+
+func (s *memStore) DeactivateWebhookSubscription(id int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, sub := range s.subs {
+		if sub.ID == id {
+			sub.Active = false
+		}
+	}
+	return nil
+}
+
+func (s *memStore) EnqueueWebhookDeliveries(deliveries []*dbtypes.WebhookDelivery) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, d := range deliveries {
+		s.nextID++
+		d.ID = s.nextID
+		s.outbox = append(s.outbox, d)
+	}
+	return nil
+}
+
+func (s *memStore) ClaimWebhookDeliveries(limit int, _ time.Duration) ([]*dbtypes.WebhookDelivery, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var claimed []*dbtypes.WebhookDelivery
+	for _, d := range s.outbox {
+		if len(claimed) == limit {
+			break
+		}
+		for _, sub := range s.subs {
+			if sub.ID == d.SubscriptionID {
+				d.URL, d.Secret = sub.URL, sub.Secret
+			}
+		}
+		c := *d
+		claimed = append(claimed, &c)
+	}
+	return claimed, nil
+}
+
+func (s *memStore) removeDelivery(id int64) *dbtypes.WebhookDelivery {
+	for i, d := range s.outbox {
+		if d.ID == id {
+			s.outbox = append(s.outbox[:i], s.outbox[i+1:]...)
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *memStore) CompleteWebhookDelivery(id int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.removeDelivery(id)
+	return nil
+}
+
+func (s *memStore) RetryWebhookDelivery(id int64, next time.Time, _ string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, d := range s.outbox {
+		if d.ID == id {
+			d.Attempts++
+			s.retries[id] = next
+		}
+	}
+	return nil
+}
+
+func (s *memStore) DeadLetterWebhookDelivery(id int64, lastErr string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if d := s.removeDelivery(id); d != nil {
+		s.deadLetters = append(s.deadLetters, &dbtypes.WebhookDeadLetter{
+			ID:             d.ID,
+			SubscriptionID: d.SubscriptionID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Attempts:       d.Attempts + 1,
+			LastError:      lastErr,
+		})
+	}
+	return nil
+}
+
+func (s *memStore) WebhookDeadLetters(int64, int) ([]*dbtypes.WebhookDeadLetter, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.deadLetters, nil
+}
+
+func (s *memStore) WebhookAddressActivity(string, []string, []string) ([]*dbtypes.WebhookAddressActivity, error) {
+	return s.activity, nil
+}
+
+func (s *memStore) WebhookTxBlocks(_ string, txHashes []string) (map[string]*dbtypes.WebhookTxBlock, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.txLookups++
+	blocks := make(map[string]*dbtypes.WebhookTxBlock)
+	for _, txHash := range txHashes {
+		if b, ok := s.txBlocks[txHash]; ok {
+			blocks[txHash] = b
+		}
+	}
+	return blocks, nil
+}
+
+func (s *memStore) WebhookSwapRedemptions(string, int64) ([]*dbtypes.WebhookSwapRedemption, error) {
+	return nil, nil
+}
+
+const testAddress = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+
+// testHosts are the addresses of the hosts of the test URLs.
+var testHosts = map[string]string{
+	"example.com":      "93.184.215.14",
+	"internal.example": "10.1.2.3",
+	"rebind.example":   "169.254.169.254",
+}
+
+func lookupTestHost(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := testHosts[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+// newTestDispatcher creates a Dispatcher with the testHosts, which permits the
+// callback addresses of the allowed networks.
+func newTestDispatcher(t *testing.T, store *memStore, allowed ...string) *Dispatcher {
+	t.Helper()
+	cfg := &Config{Store: store, Params: chaincfg.MainNetParams()}
+	for _, cidr := range allowed {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.AllowedNets = append(cfg.AllowedNets, n)
+	}
+	d, err := NewDispatcher(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.lookupIP = lookupTestHost
+	return d
+}
+
+func TestSubscribeValidation(t *testing.T) {
+	d := newTestDispatcher(t, newMemStore(), "10.9.0.0/16")
+	tests := []struct {
+		name string
+		sub  dbtypes.WebhookSubscription
+		ok   bool
+	}{
+		{"newblock", dbtypes.WebhookSubscription{URL: "https://example.com/hook", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, true},
+		{"address", dbtypes.WebhookSubscription{URL: "http://example.com", Event: EventAddress, Chain: mutilchain.TYPEDCR, Target: testAddress}, true},
+		{"bad address", dbtypes.WebhookSubscription{URL: "http://example.com", Event: EventAddress, Chain: mutilchain.TYPEDCR, Target: "Dsbad"}, false},
+		{"bad url", dbtypes.WebhookSubscription{URL: "ftp://example.com", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"disabled chain", dbtypes.WebhookSubscription{URL: "http://example.com", Event: EventNewBlock, Chain: mutilchain.TYPEBTC}, false},
+		{"no confirmations", dbtypes.WebhookSubscription{URL: "http://example.com", Event: EventTxConfirmed, Chain: mutilchain.TYPEDCR,
+			Target: "4a9d3e1fa44c2c4d5e8a6b4b0e8d87f3b0e4b8e1e8a3e7f3d2c1b0a998877665"}, false},
+		{"unknown event", dbtypes.WebhookSubscription{URL: "http://example.com", Event: "nope", Chain: mutilchain.TYPEDCR}, false},
+		{"unresolved host", dbtypes.WebhookSubscription{URL: "http://nope.example", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"private host", dbtypes.WebhookSubscription{URL: "http://internal.example/hook", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"link-local host", dbtypes.WebhookSubscription{URL: "http://rebind.example", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"loopback", dbtypes.WebhookSubscription{URL: "http://127.0.0.1:8080", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"loopback v6", dbtypes.WebhookSubscription{URL: "http://[::1]/hook", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"mapped loopback", dbtypes.WebhookSubscription{URL: "http://[::ffff:127.0.0.1]/hook", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"unspecified", dbtypes.WebhookSubscription{URL: "http://0.0.0.0", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"multicast", dbtypes.WebhookSubscription{URL: "http://224.0.0.1", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, false},
+		{"allowed net", dbtypes.WebhookSubscription{URL: "http://10.9.8.7/hook", Event: EventNewBlock, Chain: mutilchain.TYPEDCR}, true},
+	}
+	for _, tt := range tests {
+		err := d.Subscribe(&tt.sub)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("%s: expected ErrInvalidSubscription, got %v", tt.name, err)
+		}
+		if tt.ok && len(tt.sub.Secret) != 64 {
+			t.Errorf("%s: expected a secret, got %q", tt.name, tt.sub.Secret)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, retryBaseDelay},
+		{2, 2 * retryBaseDelay},
+		{4, 8 * retryBaseDelay},
+		{maxAttempts, retryMaxDelay},
+		{100, retryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliveries(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	var mtx sync.Mutex
+	var got []received
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mtx.Lock()
+		defer mtx.Unlock()
+		got = append(got, received{r.Header, body})
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	store := newMemStore()
+	d := newTestDispatcher(t, store, "127.0.0.1/32")
+	sub := &dbtypes.WebhookSubscription{KeyID: 1, URL: srv.URL, Event: EventAddress,
+		Chain: mutilchain.TYPEDCR, Target: testAddress}
+	if err := d.Subscribe(sub); err != nil {
+		t.Fatal(err)
+	}
+
+	d.handle(pstypes.HubMessage{Signal: pstypes.SigAddressTx, Msg: &pstypes.AddressMessage{
+		Address: testAddress,
+		TxHash:  "f0e1d2c3b4a5968778695a4b3c2d1e0ff0e1d2c3b4a5968778695a4b3c2d1e0f",
+	}})
+	// Another address is not delivered.
+	d.handle(pstypes.HubMessage{Signal: pstypes.SigAddressTx, Msg: &pstypes.AddressMessage{
+		Address: "DsSomeOtherAddress",
+	}})
+	if len(store.outbox) != 1 {
+		t.Fatalf("expected 1 delivery in the outbox, got %d", len(store.outbox))
+	}
+
+	// A failed attempt is retried later.
+	ctx := context.Background()
+	if n := d.deliverDue(ctx); n != 1 {
+		t.Fatalf("expected 1 claimed delivery, got %d", n)
+	}
+	if len(store.outbox) != 1 || store.outbox[0].Attempts != 1 || store.retries[store.outbox[0].ID].IsZero() {
+		t.Fatalf("expected a retry of the failed delivery")
+	}
+
+	// A successful attempt is removed from the outbox, and is signed.
+	mtx.Lock()
+	fail = false
+	mtx.Unlock()
+	d.deliverDue(ctx)
+	if len(store.outbox) != 0 {
+		t.Fatalf("expected an empty outbox, got %d deliveries", len(store.outbox))
+	}
+	last := got[len(got)-1]
+	if !Verify(sub.Secret, last.header.Get(TimestampHeader), last.header.Get(SignatureHeader), last.body) {
+		t.Errorf("invalid signature %q", last.header.Get(SignatureHeader))
+	}
+	if Verify("wrong", last.header.Get(TimestampHeader), last.header.Get(SignatureHeader), last.body) {
+		t.Errorf("signature verified with the wrong secret")
+	}
+	if last.header.Get(EventHeader) != EventAddress {
+		t.Errorf("expected event header %q, got %q", EventAddress, last.header.Get(EventHeader))
+	}
+	var ev struct {
+		Event
+		Data AddressData `json:"data"`
+	}
+	if err := json.Unmarshal(last.body, &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.SubscriptionID != sub.ID || ev.Data.Address != testAddress || ev.Data.Confirmed {
+		t.Errorf("unexpected event %+v", ev)
+	}
+
+	// A delivery failing maxAttempts times is dead lettered.
+	mtx.Lock()
+	fail = true
+	mtx.Unlock()
+	d.handle(pstypes.HubMessage{Signal: pstypes.SigAddressTx, Msg: &pstypes.AddressMessage{Address: testAddress}})
+	for i := 0; i < maxAttempts; i++ {
+		d.deliverDue(ctx)
+	}
+	if len(store.outbox) != 0 || len(store.deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter and an empty outbox, got %d and %d",
+			len(store.deadLetters), len(store.outbox))
+	}
+	if dl := store.deadLetters[0]; dl.Attempts != maxAttempts || dl.LastError == "" {
+		t.Errorf("unexpected dead letter %+v", dl)
+	}
+}
+
+func TestDeliveryDialCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	// A host resolving to a loopback address after the subscription is not
+	// dialed.
+	d := newTestDispatcher(t, newMemStore())
+	_, err := d.client.Get(srv.URL)
+	if !errors.Is(err, errForbiddenAddress) {
+		t.Errorf("expected errForbiddenAddress, got %v", err)
+	}
+
+	d = newTestDispatcher(t, newMemStore(), "127.0.0.1/32")
+	resp, err := d.client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error dialing an allowed address: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestTxConfirmed(t *testing.T) {
+	const (
+		mined   = "4a9d3e1fa44c2c4d5e8a6b4b0e8d87f3b0e4b8e1e8a3e7f3d2c1b0a998877665"
+		pending = "f0e1d2c3b4a5968778695a4b3c2d1e0ff0e1d2c3b4a5968778695a4b3c2d1e0f"
+	)
+	store := newMemStore()
+	store.txBlocks = map[string]*dbtypes.WebhookTxBlock{mined: {Height: 100, Hash: "blockhash"}}
+	d := newTestDispatcher(t, store)
+	for _, sub := range []*dbtypes.WebhookSubscription{
+		{KeyID: 1, Target: mined, Confirmations: 1},
+		{KeyID: 1, Target: mined, Confirmations: 3},
+		{KeyID: 1, Target: pending, Confirmations: 1},
+	} {
+		sub.URL, sub.Event, sub.Chain = "https://example.com/hook", EventTxConfirmed, mutilchain.TYPEDCR
+		if err := d.Subscribe(sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One lookup for all the subscriptions of a block.
+	for i, delivered := range []int{1, 0, 1} {
+		height := 100 + int64(i)
+		d.handle(pstypes.HubMessage{Signal: pstypes.SigNewBlock, Msg: &blockMessage{
+			chain: mutilchain.TYPEDCR,
+			block: BlockData{Height: height},
+		}})
+		if store.txLookups != i+1 {
+			t.Fatalf("expected %d transaction block lookups, got %d", i+1, store.txLookups)
+		}
+		if len(store.outbox) != delivered {
+			t.Fatalf("height %d: expected %d deliveries, got %d", height, delivered, len(store.outbox))
+		}
+		store.outbox = nil
+	}
+	if subs := d.subscriptions(EventTxConfirmed, mutilchain.TYPEDCR); len(subs) != 1 || subs[0].Target != pending {
+		t.Errorf("expected the pending subscription to remain, got %v", subs)
+	}
+}
@@ -37,6 +37,9 @@ type BlockHeader struct {
 	PrevHash             string      `json:"prev_hash"`
 	Reward               uint64      `json:"reward"`
 	Timestamp            uint64      `json:"timestamp"`
+	BlockSize            uint64      `json:"block_size"`
+	NumTxes              uint64      `json:"num_txes"`
+	MinerTxHash          string      `json:"miner_tx_hash"`
 
 	// Optional / variant fields (may appear depending on monerod version)
 	PowAlgo       string `json:"pow_algo,omitempty"`